@@ -0,0 +1,165 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// AdaptiveTimeoutOptions tunes an AdaptiveTimeout.
+type AdaptiveTimeoutOptions struct {
+	// Percentile of the learned latencies the timeout is based on, in
+	// (0, 1]. Defaults to 0.99.
+	Percentile float64
+	// Factor multiplies the percentile. Defaults to 3.
+	Factor float64
+	// Min and Max clamp the timeout. Min defaults to 100ms; Max to no
+	// bound beyond Config.Timeout.
+	Min, Max time.Duration
+	// MinSamples is how many runs of a query shape must be seen before
+	// its timeout applies; until then only Config.Timeout and the
+	// caller's context bound it. Defaults to 20.
+	MinSamples int
+	// Samples is how many recent latencies are kept per fingerprint.
+	// Defaults to 256.
+	Samples int
+	// MaxFingerprints caps the query shapes learned. Defaults to 1000.
+	MaxFingerprints int
+}
+
+// AdaptiveTimeout sets per-statement deadlines from the latencies of
+// earlier statements with the same QueryFingerprint: Percentile ×
+// Factor, clamped to [Min, Max]. A quick lookup then fails fast when
+// the server stalls, while a slow report keeps the time it needs, which
+// one global timeout cannot do. The caller's context deadline still
+// applies when it is earlier.
+//
+//	adaptive := nexus.NewAdaptiveTimeout(nexus.AdaptiveTimeoutOptions{Factor: 4})
+//	client, _ := nexus.NewClientE(nexus.Config{BaseURL: url, AdaptiveTimeout: adaptive})
+//
+// Successful statements and statements that ran out of time are
+// learned from; other failures are not, as they are often quick. A
+// statement stopped by its adaptive deadline returns an
+// *AdaptiveTimeoutError. AdaptiveTimeout is safe for concurrent use and
+// may be shared by clients.
+type AdaptiveTimeout struct {
+	opts AdaptiveTimeoutOptions
+
+	mu     sync.Mutex
+	shapes map[string]*adaptiveShape
+}
+
+type adaptiveShape struct {
+	recent latencyWindow
+	// timeout is recomputed once stale new samples have arrived.
+	timeout time.Duration
+	stale   int
+}
+
+// NewAdaptiveTimeout returns an AdaptiveTimeout that has learned
+// nothing yet.
+func NewAdaptiveTimeout(opts AdaptiveTimeoutOptions) *AdaptiveTimeout {
+	if opts.Percentile <= 0 || opts.Percentile > 1 {
+		opts.Percentile = 0.99
+	}
+	if opts.Factor <= 0 {
+		opts.Factor = 3
+	}
+	if opts.Min <= 0 {
+		opts.Min = 100 * time.Millisecond
+	}
+	if opts.MinSamples <= 0 {
+		opts.MinSamples = 20
+	}
+	if opts.Samples <= 0 {
+		opts.Samples = 256
+	}
+	opts.MinSamples = min(opts.MinSamples, opts.Samples)
+	if opts.MaxFingerprints <= 0 {
+		opts.MaxFingerprints = 1000
+	}
+	return &AdaptiveTimeout{opts: opts, shapes: map[string]*adaptiveShape{}}
+}
+
+// ObserveQuery learns from event. The client calls it for every
+// statement; it is exported so AdaptiveTimeout can also be fed from
+// other sources, or warmed up from recorded QueryEvents.
+func (a *AdaptiveTimeout) ObserveQuery(_ context.Context, event QueryEvent) {
+	if event.Err != nil && !isTimeout(event.Err) {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	shape, ok := a.shapes[event.Fingerprint]
+	if !ok {
+		if len(a.shapes) >= a.opts.MaxFingerprints {
+			return
+		}
+		shape = &adaptiveShape{}
+		a.shapes[event.Fingerprint] = shape
+	}
+	shape.recent.add(event.Duration, a.opts.Samples)
+	shape.stale++
+}
+
+// Timeout is the deadline statements with fingerprint currently get,
+// or zero while fewer than MinSamples runs have been seen.
+func (a *AdaptiveTimeout) Timeout(fingerprint string) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	shape := a.shapes[fingerprint]
+	if shape == nil || len(shape.recent.samples) < a.opts.MinSamples {
+		return 0
+	}
+	// Sorting on every call would be wasted work; the percentile moves
+	// slowly, so refresh it after every 1/16th of the window.
+	if shape.timeout == 0 || shape.stale >= max(1, a.opts.Samples/16) {
+		p := percentile(shape.recent.sorted(), a.opts.Percentile)
+		d := max(time.Duration(float64(p)*a.opts.Factor), a.opts.Min)
+		if a.opts.Max > 0 {
+			d = min(d, a.opts.Max)
+		}
+		shape.timeout, shape.stale = d, 0
+	}
+	return shape.timeout
+}
+
+// apply bounds ctx by the timeout for fingerprint, returning the
+// timeout used (zero if none).
+func (a *AdaptiveTimeout) apply(ctx context.Context, fingerprint string) (context.Context, context.CancelFunc, time.Duration) {
+	d := a.Timeout(fingerprint)
+	if d == 0 {
+		return ctx, func() {}, 0
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return ctx, func() {}, 0
+	}
+	ctx, cancel := context.WithTimeout(ctx, d)
+	return ctx, cancel, d
+}
+
+// AdaptiveTimeoutError is returned when a statement outlives the
+// deadline AdaptiveTimeout set for it. It unwraps to the transport
+// error, which matches context.DeadlineExceeded.
+type AdaptiveTimeoutError struct {
+	Fingerprint string
+	Timeout     time.Duration
+	Err         error
+}
+
+func (e *AdaptiveTimeoutError) Error() string {
+	return fmt.Sprintf("nexus: query exceeded its adaptive timeout of %v: %v", e.Timeout, e.Err)
+}
+
+func (e *AdaptiveTimeoutError) Unwrap() error { return e.Err }
+
+// isTimeout reports whether err is a deadline or timeout failure.
+func isTimeout(err error) bool {
+	var adaptive *AdaptiveTimeoutError
+	var netErr net.Error
+	return errors.As(err, &adaptive) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,83 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveTimeoutLearnsPerFingerprint(t *testing.T) {
+	a := NewAdaptiveTimeout(AdaptiveTimeoutOptions{Percentile: 0.9, Factor: 2, MinSamples: 5, Samples: 10, Max: time.Second})
+	ctx := context.Background()
+	observe := func(fp string, d time.Duration, err error) {
+		a.ObserveQuery(ctx, QueryEvent{Fingerprint: fp, Duration: d, Err: err})
+	}
+
+	for i := 1; i <= 4; i++ {
+		observe("fast", time.Duration(i)*100*time.Millisecond, nil)
+	}
+	assert.Zero(t, a.Timeout("fast"), "not enough samples yet")
+	observe("fast", 500*time.Millisecond, errors.New("syntax error")) // not learned
+	observe("fast", 250*time.Millisecond, nil)
+	// p90 of 100..400,250 is 400ms; ×2 = 800ms.
+	assert.Equal(t, 800*time.Millisecond, a.Timeout("fast"))
+
+	for i := 0; i < 10; i++ {
+		observe("slow", 2*time.Second, nil)
+	}
+	assert.Equal(t, time.Second, a.Timeout("slow"), "clamped to Max")
+	for i := 0; i < 10; i++ {
+		observe("tiny", time.Millisecond, nil)
+	}
+	assert.Equal(t, 100*time.Millisecond, a.Timeout("tiny"), "clamped to Min")
+	assert.Zero(t, a.Timeout("unknown"))
+}
+
+func TestAdaptiveTimeoutBoundsQueries(t *testing.T) {
+	stall := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-stall:
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+	close(stall)
+
+	a := NewAdaptiveTimeout(AdaptiveTimeoutOptions{MinSamples: 3, Min: 50 * time.Millisecond})
+	client, err := NewClientWithOptions(server.URL, WithAdaptiveTimeout(a))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.ExecuteCypher(ctx, "MATCH (n) WHERE n.id = $id RETURN n", nil)
+		require.NoError(t, err)
+	}
+	fp := QueryFingerprint("MATCH (n) WHERE n.id = $id RETURN n")
+	assert.Equal(t, 50*time.Millisecond, a.Timeout(fp))
+
+	stall = make(chan struct{})
+	defer close(stall)
+	start := time.Now()
+	_, err = client.ExecuteCypher(ctx, "MATCH (n) WHERE n.id = $id RETURN n", nil)
+	var timeoutErr *AdaptiveTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, fp, timeoutErr.Fingerprint)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// An earlier caller deadline is left alone.
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = client.ExecuteCypher(short, "MATCH (n) WHERE n.id = $id RETURN n", nil)
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &timeoutErr))
+}
@@ -0,0 +1,140 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PageRankOptions configures a PageRank run through the
+// `gds.centrality.pagerank` procedure family.
+//
+// Zero values fall back to the server defaults (dampingFactor 0.85,
+// maxIterations 100, tolerance 0.0001).
+type PageRankOptions struct {
+	// Label restricts the projected graph to nodes with this label.
+	Label string
+	// RelationshipType restricts the projected graph to this type.
+	RelationshipType string
+	DampingFactor    float64
+	MaxIterations    int
+	Tolerance        float64
+	// Weighted switches to `gds.centrality.pagerank.weighted`.
+	Weighted bool
+	// SourceNodes personalizes the run: the random surfer teleports
+	// back to these nodes instead of to a uniformly random node, so
+	// scores measure importance relative to them.
+	SourceNodes []string
+	// SourceWeights optionally biases teleportation between the source
+	// nodes. Keys must be a subset of SourceNodes; missing entries
+	// weigh 1.0.
+	SourceWeights map[string]float64
+	// Limit caps the number of returned scores (0 = all).
+	Limit int
+}
+
+// NodeScore is one row of a centrality algorithm result.
+type NodeScore struct {
+	NodeID string  `json:"node_id"`
+	Score  float64 `json:"score"`
+}
+
+// PageRank runs PageRank and returns node scores ordered by score
+// descending.
+func (c *Client) PageRank(ctx context.Context, opts PageRankOptions) ([]NodeScore, error) {
+	config, err := opts.config()
+	if err != nil {
+		return nil, err
+	}
+
+	procedure := "gds.centrality.pagerank"
+	if opts.Weighted {
+		procedure = "gds.centrality.pagerank.weighted"
+	}
+	query := fmt.Sprintf(
+		"CALL %s($label, $relType, $config) YIELD node, score "+
+			"RETURN id(node) AS node_id, score ORDER BY score DESC", procedure)
+	params := map[string]interface{}{
+		"label":   opts.Label,
+		"relType": opts.RelationshipType,
+		"config":  config,
+	}
+	if opts.Limit > 0 {
+		query += " LIMIT $limit"
+		params["limit"] = opts.Limit
+	}
+
+	result, err := c.ExecuteCypher(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNodeScores(result), nil
+}
+
+// PersonalizedPageRank runs PageRank personalized on sourceIDs, so
+// "importance relative to this user/entity" is a single call. Any
+// SourceNodes already set on opts are replaced.
+func (c *Client) PersonalizedPageRank(ctx context.Context, sourceIDs []string, opts PageRankOptions) ([]NodeScore, error) {
+	if len(sourceIDs) == 0 {
+		return nil, errors.New("nexus: personalized PageRank requires at least one source node")
+	}
+	opts.SourceNodes = sourceIDs
+	return c.PageRank(ctx, opts)
+}
+
+// config renders the procedure configuration map, omitting unset
+// knobs so the server defaults apply.
+func (o PageRankOptions) config() (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	if o.DampingFactor != 0 {
+		if o.DampingFactor <= 0 || o.DampingFactor >= 1 {
+			return nil, fmt.Errorf("nexus: PageRank damping factor must be in (0, 1), got %v", o.DampingFactor)
+		}
+		config["dampingFactor"] = o.DampingFactor
+	}
+	if o.MaxIterations > 0 {
+		config["maxIterations"] = o.MaxIterations
+	}
+	if o.Tolerance > 0 {
+		config["tolerance"] = o.Tolerance
+	}
+	if len(o.SourceWeights) > 0 && len(o.SourceNodes) == 0 {
+		return nil, errors.New("nexus: PageRank source weights require SourceNodes")
+	}
+	if len(o.SourceNodes) > 0 {
+		sources := make(map[string]bool, len(o.SourceNodes))
+		for _, id := range o.SourceNodes {
+			sources[id] = true
+		}
+		config["sourceNodes"] = o.SourceNodes
+		if len(o.SourceWeights) > 0 {
+			weights := make(map[string]interface{}, len(o.SourceWeights))
+			for id, w := range o.SourceWeights {
+				if !sources[id] {
+					return nil, fmt.Errorf("nexus: PageRank source weight for %q which is not a source node", id)
+				}
+				if w < 0 {
+					return nil, fmt.Errorf("nexus: PageRank source weight for %q must not be negative", id)
+				}
+				weights[id] = w
+			}
+			config["sourceWeights"] = weights
+		}
+	}
+	return config, nil
+}
+
+// decodeNodeScores reads (node_id, score) rows.
+func decodeNodeScores(result *QueryResult) []NodeScore {
+	scores := make([]NodeScore, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		scores = append(scores, NodeScore{
+			NodeID: idString(row[0]),
+			Score:  asFloat(row[1]),
+		})
+	}
+	return scores
+}
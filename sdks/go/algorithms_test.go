@@ -0,0 +1,71 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalizedPageRank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/cypher", r.URL.Path)
+
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req["query"], "CALL gds.centrality.pagerank($label, $relType, $config)")
+		assert.Contains(t, req["query"], "LIMIT $limit")
+
+		params := req["parameters"].(map[string]interface{})
+		assert.Equal(t, "Person", params["label"])
+		assert.Equal(t, "KNOWS", params["relType"])
+		config := params["config"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"1"}, config["sourceNodes"])
+		assert.Equal(t, 0.9, config["dampingFactor"])
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"columns": []string{"node_id", "score"},
+			"rows":    [][]interface{}{{1, 0.5}, {2, 0.25}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+
+	scores, err := client.PersonalizedPageRank(context.Background(), []string{"1"}, PageRankOptions{
+		Label:            "Person",
+		RelationshipType: "KNOWS",
+		DampingFactor:    0.9,
+		Limit:            10,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, scores, 2)
+	assert.Equal(t, NodeScore{NodeID: "1", Score: 0.5}, scores[0])
+	assert.Equal(t, "2", scores[1].NodeID)
+}
+
+func TestPageRankOptionsValidation(t *testing.T) {
+	client := NewClient(Config{BaseURL: "http://localhost:15474"})
+	ctx := context.Background()
+
+	_, err := client.PersonalizedPageRank(ctx, nil, PageRankOptions{})
+	assert.Error(t, err)
+
+	_, err = client.PageRank(ctx, PageRankOptions{SourceWeights: map[string]float64{"1": 1}})
+	assert.Error(t, err)
+
+	_, err = client.PageRank(ctx, PageRankOptions{
+		SourceNodes:   []string{"1"},
+		SourceWeights: map[string]float64{"2": 1},
+	})
+	assert.Error(t, err)
+
+	_, err = client.PageRank(ctx, PageRankOptions{DampingFactor: 1.5})
+	assert.Error(t, err)
+}
@@ -0,0 +1,321 @@
+// Package apoc wraps common APOC-style utility procedures in typed
+// calls, so the CALL signatures and YIELD columns do not have to be
+// remembered and spelled out in query strings:
+//
+//	res, err := apoc.PeriodicIterate(ctx, client,
+//	    "MATCH (p:Person) RETURN p",
+//	    "SET p.score = p.visits * 2",
+//	    apoc.IterateConfig{BatchSize: 5000})
+//	if err == nil {
+//	    err = res.Err()
+//	}
+//
+// Every function takes a Querier, so they run on a *nexus.Client, a
+// transaction or a retrying client alike. The procedures must be
+// installed on the server; calls to missing ones fail with the server's
+// "unknown procedure" error.
+package apoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+)
+
+// Querier is the subset of *nexus.Client the wrappers need.
+type Querier interface {
+	ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error)
+}
+
+// IterateConfig configures PeriodicIterate. Zero fields keep the
+// procedure's defaults.
+type IterateConfig struct {
+	// BatchSize is the number of iterate rows per action transaction.
+	BatchSize int
+	// Parallel runs batches concurrently; only safe when batches touch
+	// disjoint parts of the graph.
+	Parallel    bool
+	Concurrency int
+	// Retries re-runs a failed batch this many times.
+	Retries int
+	// Params are visible to both statements.
+	Params map[string]interface{}
+	// BatchMode is "BATCH" (default), "SINGLE" or "BATCH_SINGLE".
+	BatchMode string
+}
+
+func (c IterateConfig) toMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if c.BatchSize > 0 {
+		m["batchSize"] = c.BatchSize
+	}
+	if c.Parallel {
+		m["parallel"] = true
+	}
+	if c.Concurrency > 0 {
+		m["concurrency"] = c.Concurrency
+	}
+	if c.Retries > 0 {
+		m["retries"] = c.Retries
+	}
+	if c.Params != nil {
+		m["params"] = c.Params
+	}
+	if c.BatchMode != "" {
+		m["batchMode"] = c.BatchMode
+	}
+	return m
+}
+
+// BatchResult reports a batched update procedure run.
+type BatchResult struct {
+	Batches             int64
+	Total               int64
+	TimeTaken           time.Duration
+	CommittedOperations int64
+	FailedOperations    int64
+	FailedBatches       int64
+	Retries             int64
+	// ErrorMessages counts failures by message.
+	ErrorMessages map[string]int64
+	WasTerminated bool
+}
+
+// Err summarises the failed operations of a run, or returns nil when
+// every batch committed. The procedures report failures in their
+// result rather than failing the call, so check it.
+func (r *BatchResult) Err() error {
+	if r.FailedOperations == 0 && r.FailedBatches == 0 && !r.WasTerminated {
+		return nil
+	}
+	msgs := make([]string, 0, len(r.ErrorMessages))
+	for msg, n := range r.ErrorMessages {
+		msgs = append(msgs, fmt.Sprintf("%s (x%d)", msg, n))
+	}
+	sort.Strings(msgs)
+	if r.WasTerminated {
+		msgs = append(msgs, "terminated")
+	}
+	return fmt.Errorf("apoc: %d operation(s) in %d batch(es) failed: %s",
+		r.FailedOperations, r.FailedBatches, strings.Join(msgs, "; "))
+}
+
+// PeriodicIterate runs action once per row of iterate, in batches of
+// their own transactions (apoc.periodic.iterate). Columns returned by
+// iterate are visible to action by name.
+func PeriodicIterate(ctx context.Context, q Querier, iterate, action string, cfg IterateConfig) (*BatchResult, error) {
+	if strings.TrimSpace(iterate) == "" || strings.TrimSpace(action) == "" {
+		return nil, errors.New("apoc: periodic iterate needs both statements")
+	}
+	result, err := q.ExecuteCypher(ctx,
+		"CALL apoc.periodic.iterate($iterate, $action, $config) "+
+			"YIELD batches, total, timeTaken, committedOperations, failedOperations, failedBatches, retries, errorMessages, wasTerminated "+
+			"RETURN batches, total, timeTaken, committedOperations, failedOperations, failedBatches, retries, errorMessages, wasTerminated",
+		map[string]interface{}{"iterate": iterate, "action": action, "config": cfg.toMap()})
+	if err != nil {
+		return nil, err
+	}
+	row, err := singleRow(result, "apoc.periodic.iterate")
+	if err != nil {
+		return nil, err
+	}
+	return &BatchResult{
+		Batches:             toInt64(row["batches"]),
+		Total:               toInt64(row["total"]),
+		TimeTaken:           time.Duration(toInt64(row["timeTaken"])) * time.Second,
+		CommittedOperations: toInt64(row["committedOperations"]),
+		FailedOperations:    toInt64(row["failedOperations"]),
+		FailedBatches:       toInt64(row["failedBatches"]),
+		Retries:             toInt64(row["retries"]),
+		ErrorMessages:       errorCounts(row["errorMessages"]),
+		WasTerminated:       row["wasTerminated"] == true,
+	}, nil
+}
+
+// PeriodicCommit re-runs statement until it reports zero updates
+// (apoc.periodic.commit). statement must end in `LIMIT $limit` and
+// return a count, e.g.
+//
+//	MATCH (n:Stale) WITH n LIMIT $limit DETACH DELETE n RETURN count(*)
+func PeriodicCommit(ctx context.Context, q Querier, statement string, limit int) (*BatchResult, error) {
+	if !strings.Contains(statement, "$limit") {
+		return nil, errors.New("apoc: periodic commit statement must use $limit")
+	}
+	if limit <= 0 {
+		limit = 10000
+	}
+	result, err := q.ExecuteCypher(ctx,
+		"CALL apoc.periodic.commit($statement, $params) "+
+			"YIELD updates, executions, runtime, batches, failedBatches, batchErrors, failedCommits, wasTerminated "+
+			"RETURN updates, executions, runtime, batches, failedBatches, batchErrors, failedCommits, wasTerminated",
+		map[string]interface{}{"statement": statement, "params": map[string]interface{}{"limit": limit}})
+	if err != nil {
+		return nil, err
+	}
+	row, err := singleRow(result, "apoc.periodic.commit")
+	if err != nil {
+		return nil, err
+	}
+	return &BatchResult{
+		Batches:             toInt64(row["batches"]),
+		Total:               toInt64(row["executions"]),
+		TimeTaken:           time.Duration(toInt64(row["runtime"])) * time.Second,
+		CommittedOperations: toInt64(row["updates"]),
+		FailedOperations:    toInt64(row["failedCommits"]),
+		FailedBatches:       toInt64(row["failedBatches"]),
+		ErrorMessages:       errorCounts(row["batchErrors"]),
+		WasTerminated:       row["wasTerminated"] == true,
+	}, nil
+}
+
+// LoadJSON reads a JSON document from url (apoc.load.json), optionally
+// narrowed by a JSON path, and calls fn with each value. Returning an
+// error from fn stops the scan.
+func LoadJSON(ctx context.Context, q Querier, url, path string, fn func(value interface{}) error) error {
+	if url == "" {
+		return errors.New("apoc: load json needs a URL")
+	}
+	query := "CALL apoc.load.json($url) YIELD value RETURN value"
+	params := map[string]interface{}{"url": url}
+	if path != "" {
+		query = "CALL apoc.load.json($url, $path) YIELD value RETURN value"
+		params["path"] = path
+	}
+	result, err := q.ExecuteCypher(ctx, query, params)
+	if err != nil {
+		return err
+	}
+	for _, row := range result.Rows {
+		if len(row) == 0 {
+			continue
+		}
+		if err := fn(row[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeConfig configures MergeNodes.
+type MergeConfig struct {
+	// Properties is "discard" (keep the first node's values),
+	// "overwrite" or "combine" (collect into lists). Empty keeps the
+	// procedure default.
+	Properties string
+	// MergeRels also merges relationships that become parallel.
+	MergeRels bool
+}
+
+// MergeNodes merges the nodes with the given IDs into the first one
+// (apoc.refactor.mergeNodes) and returns the survivor's ID.
+func MergeNodes(ctx context.Context, q Querier, ids []int64, cfg MergeConfig) (int64, error) {
+	if len(ids) < 2 {
+		return 0, errors.New("apoc: merge needs at least two nodes")
+	}
+	config := map[string]interface{}{}
+	if cfg.Properties != "" {
+		config["properties"] = cfg.Properties
+	}
+	if cfg.MergeRels {
+		config["mergeRels"] = true
+	}
+	// UNWIND keeps the caller's order: the first ID survives.
+	result, err := q.ExecuteCypher(ctx,
+		"UNWIND range(0, size($ids) - 1) AS i MATCH (n) WHERE id(n) = $ids[i] "+
+			"WITH n ORDER BY i WITH collect(n) AS nodes "+
+			"CALL apoc.refactor.mergeNodes(nodes, $config) YIELD node RETURN id(node) AS id",
+		map[string]interface{}{"ids": ids, "config": config})
+	if err != nil {
+		return 0, err
+	}
+	row, err := singleRow(result, "apoc.refactor.mergeNodes")
+	if err != nil {
+		return 0, err
+	}
+	return toInt64(row["id"]), nil
+}
+
+// RenameLabel renames a label on every node carrying it
+// (apoc.refactor.rename.label) and returns the number of nodes changed.
+func RenameLabel(ctx context.Context, q Querier, from, to string) (int64, error) {
+	return rename(ctx, q, "label", from, to)
+}
+
+// RenameType renames a relationship type (apoc.refactor.rename.type).
+func RenameType(ctx context.Context, q Querier, from, to string) (int64, error) {
+	return rename(ctx, q, "type", from, to)
+}
+
+// RenameNodeProperty renames a property on every node carrying it
+// (apoc.refactor.rename.nodeProperty).
+func RenameNodeProperty(ctx context.Context, q Querier, from, to string) (int64, error) {
+	return rename(ctx, q, "nodeProperty", from, to)
+}
+
+// RenameRelationshipProperty renames a relationship property
+// (apoc.refactor.rename.typeProperty).
+func RenameRelationshipProperty(ctx context.Context, q Querier, from, to string) (int64, error) {
+	return rename(ctx, q, "typeProperty", from, to)
+}
+
+func rename(ctx context.Context, q Querier, what, from, to string) (int64, error) {
+	if from == "" || to == "" {
+		return 0, errors.New("apoc: rename needs both names")
+	}
+	proc := "apoc.refactor.rename." + what
+	result, err := q.ExecuteCypher(ctx,
+		"CALL "+proc+"($from, $to) YIELD total, committedOperations, failedOperations, errorMessages "+
+			"RETURN total, committedOperations, failedOperations, errorMessages",
+		map[string]interface{}{"from": from, "to": to})
+	if err != nil {
+		return 0, err
+	}
+	row, err := singleRow(result, proc)
+	if err != nil {
+		return 0, err
+	}
+	res := &BatchResult{
+		Total:               toInt64(row["total"]),
+		CommittedOperations: toInt64(row["committedOperations"]),
+		FailedOperations:    toInt64(row["failedOperations"]),
+		ErrorMessages:       errorCounts(row["errorMessages"]),
+	}
+	return res.CommittedOperations, res.Err()
+}
+
+func singleRow(result *nexus.QueryResult, proc string) (map[string]interface{}, error) {
+	rows := result.RowsAsMap()
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("apoc: %s returned no rows", proc)
+	}
+	return rows[0], nil
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+func errorCounts(v interface{}) map[string]int64 {
+	m, _ := v.(map[string]interface{})
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]int64, len(m))
+	for msg, n := range m {
+		out[msg] = toInt64(n)
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package apoc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuerier struct {
+	queries []string
+	params  []map[string]interface{}
+	result  *nexus.QueryResult
+}
+
+func (f *fakeQuerier) ExecuteCypher(_ context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error) {
+	f.queries = append(f.queries, query)
+	f.params = append(f.params, params)
+	return f.result, nil
+}
+
+func TestPeriodicIterate(t *testing.T) {
+	q := &fakeQuerier{result: &nexus.QueryResult{
+		Columns: []string{"batches", "total", "timeTaken", "committedOperations", "failedOperations", "failedBatches", "retries", "errorMessages", "wasTerminated"},
+		Rows:    [][]interface{}{{int64(3), int64(2500), int64(4), int64(2490), int64(10), int64(1), int64(0), map[string]interface{}{"constraint violated": int64(10)}, false}},
+	}}
+	res, err := PeriodicIterate(context.Background(), q, "MATCH (p:Person) RETURN p", "SET p.score = 1",
+		IterateConfig{BatchSize: 1000, Parallel: true, Params: map[string]interface{}{"x": 1}})
+	require.NoError(t, err)
+	assert.Contains(t, q.queries[0], "CALL apoc.periodic.iterate($iterate, $action, $config)")
+	assert.Equal(t, map[string]interface{}{"batchSize": 1000, "parallel": true, "params": map[string]interface{}{"x": 1}}, q.params[0]["config"])
+	assert.Equal(t, int64(2500), res.Total)
+	assert.Equal(t, 4*time.Second, res.TimeTaken)
+	assert.EqualError(t, res.Err(), "apoc: 10 operation(s) in 1 batch(es) failed: constraint violated (x10)")
+
+	_, err = PeriodicIterate(context.Background(), q, "", "SET p.x = 1", IterateConfig{})
+	assert.Error(t, err)
+}
+
+func TestPeriodicCommitRequiresLimit(t *testing.T) {
+	q := &fakeQuerier{result: &nexus.QueryResult{
+		Columns: []string{"updates", "executions", "runtime", "batches", "failedBatches", "batchErrors", "failedCommits", "wasTerminated"},
+		Rows:    [][]interface{}{{float64(120), float64(3), float64(1), float64(3), float64(0), map[string]interface{}{}, float64(0), false}},
+	}}
+	_, err := PeriodicCommit(context.Background(), q, "MATCH (n) DETACH DELETE n", 0)
+	assert.Error(t, err)
+
+	res, err := PeriodicCommit(context.Background(), q, "MATCH (n:Stale) WITH n LIMIT $limit DETACH DELETE n RETURN count(*)", 0)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"limit": 10000}, q.params[0]["params"])
+	assert.Equal(t, int64(120), res.CommittedOperations)
+	assert.NoError(t, res.Err())
+}
+
+func TestLoadJSONAndRefactor(t *testing.T) {
+	q := &fakeQuerier{result: &nexus.QueryResult{Columns: []string{"value"}, Rows: [][]interface{}{{"a"}, {"b"}}}}
+	var values []interface{}
+	require.NoError(t, LoadJSON(context.Background(), q, "https://example.com/x.json", "$.items", func(v interface{}) error {
+		values = append(values, v)
+		return nil
+	}))
+	assert.Equal(t, []interface{}{"a", "b"}, values)
+	assert.Equal(t, "$.items", q.params[0]["path"])
+
+	q.result = &nexus.QueryResult{Columns: []string{"id"}, Rows: [][]interface{}{{int64(7)}}}
+	id, err := MergeNodes(context.Background(), q, []int64{7, 8}, MergeConfig{Properties: "combine"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.Equal(t, map[string]interface{}{"properties": "combine"}, q.params[1]["config"])
+	_, err = MergeNodes(context.Background(), q, []int64{7}, MergeConfig{})
+	assert.Error(t, err)
+
+	q.result = &nexus.QueryResult{
+		Columns: []string{"total", "committedOperations", "failedOperations", "errorMessages"},
+		Rows:    [][]interface{}{{int64(5), int64(5), int64(0), nil}},
+	}
+	n, err := RenameLabel(context.Background(), q, "Persn", "Person")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Contains(t, q.queries[2], "CALL apoc.refactor.rename.label($from, $to)")
+	_, err = RenameNodeProperty(context.Background(), q, "nme", "name")
+	require.NoError(t, err)
+	assert.Contains(t, q.queries[3], "apoc.refactor.rename.nodeProperty")
+}
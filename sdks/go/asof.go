@@ -0,0 +1,69 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrAsOfUnsupported is returned (wrapped together with the server's
+// *Error) when the server rejects an as-of read because it keeps no
+// MVCC history or the timestamp is outside the retention window.
+var ErrAsOfUnsupported = errors.New("nexus: server does not support as-of reads")
+
+// ExecuteCypherAsOf executes a read query against the graph state as it
+// was at timestamp, so auditors can inspect past data.
+//
+// As-of reads are an HTTP-route feature (`as_of` on POST /cypher) and
+// bypass the RPC transport. Write clauses are rejected by the server.
+func (c *Client) ExecuteCypherAsOf(ctx context.Context, timestamp time.Time, query string, params map[string]interface{}) (*QueryResult, error) {
+	if timestamp.IsZero() {
+		return nil, errors.New("nexus: as-of timestamp must be set")
+	}
+	query, err := c.checkQuery(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx, finish := c.startQuery(ctx, query, params, false)
+	result, err := c.postCypher(ctx, cypherRequest{Query: query, Parameters: params, AsOf: formatAsOf(timestamp)})
+	return result, finish(result, wrapAsOfError(err))
+}
+
+// GetNodeAsOf retrieves a node as it was at timestamp.
+func (c *Client) GetNodeAsOf(ctx context.Context, id string, timestamp time.Time) (*Node, error) {
+	if timestamp.IsZero() {
+		return nil, errors.New("nexus: as-of timestamp must be set")
+	}
+	path := fmt.Sprintf("/nodes/%s?as_of=%s", url.PathEscape(id), url.QueryEscape(formatAsOf(timestamp)))
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, wrapAsOfError(err)
+	}
+	defer resp.Body.Close()
+
+	var node Node
+	if err := decodeResponse(resp, &node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+func formatAsOf(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// wrapAsOfError tags the "no history" responses with ErrAsOfUnsupported
+// while keeping the *Error reachable through errors.As.
+func wrapAsOfError(err error) error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusNotImplemented, http.StatusGone:
+			return fmt.Errorf("%w: %w", ErrAsOfUnsupported, err)
+		}
+	}
+	return err
+}
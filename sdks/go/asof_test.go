@@ -0,0 +1,60 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCypherAsOf(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "2026-01-02T02:04:05Z", req["as_of"])
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(QueryResult{Columns: []string{"n"}, Rows: [][]interface{}{{1}}})
+	}))
+	defer server.Close()
+
+	var events []QueryEvent
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		Metrics: MetricsHookFunc(func(ctx context.Context, e QueryEvent) { events = append(events, e) }),
+	})
+	result, err := client.ExecuteCypherAsOf(context.Background(), ts, "MATCH (n) RETURN n", nil)
+
+	require.NoError(t, err)
+	assert.Len(t, result.Rows, 1)
+	assert.Equal(t, []ColumnType{ColumnInteger}, result.ColumnTypes)
+	require.Len(t, events, 1)
+	assert.Equal(t, "MATCH (n) RETURN n", events[0].Query)
+	assert.Equal(t, 1, events[0].Rows)
+}
+
+func TestGetNodeAsOfUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/nodes/7", r.URL.Path)
+		assert.NotEmpty(t, r.URL.Query().Get("as_of"))
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	_, err := client.GetNodeAsOf(context.Background(), "7", time.Now())
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrAsOfUnsupported))
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotImplemented, apiErr.StatusCode)
+}
@@ -0,0 +1,148 @@
+package nexus
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// Operation classifies what a request does to the graph.
+type Operation string
+
+const (
+	OperationRead   Operation = "read"
+	OperationWrite  Operation = "write"
+	OperationSchema Operation = "schema"
+)
+
+// Access describes a request for an Authorizer: what it does and which
+// labels and relationship types it names.
+type Access struct {
+	Operation Operation
+	// Labels and RelationshipTypes are the names the statement mentions,
+	// in order of first appearance. A pattern without a label, such as
+	// MATCH (n), can touch nodes of any label; so can requests that
+	// address an entity by ID, which carry no names at all.
+	Labels            []string
+	RelationshipTypes []string
+	// Procedures lists the procedures the statement CALLs; whether they
+	// write is up to the authorizer to know.
+	Procedures []string
+	// Query is the Cypher statement, or empty for REST calls.
+	Query string
+}
+
+// AnalyzeQuery extracts the Access a Cypher statement needs. It works
+// on tokens, not a full parse, so it is conservative: any write clause
+// makes the statement a write, and CREATE/DROP of an INDEX or
+// CONSTRAINT makes it a schema change.
+func AnalyzeQuery(query string) Access {
+	a := Access{Operation: OperationRead, Query: query}
+	toks, _ := cypherlex.Tokenize(query)
+	seenLabel, seenType, seenProc := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for i, t := range toks {
+		switch {
+		case cypherlex.Clause(toks, i, "CREATE") || cypherlex.Clause(toks, i, "DROP"):
+			if a.Operation != OperationSchema {
+				a.Operation = OperationWrite
+			}
+			for _, next := range toks[i+1 : min(i+4, len(toks))] {
+				if next.Kind == cypherlex.Word && (strings.EqualFold(next.Text, "INDEX") || strings.EqualFold(next.Text, "CONSTRAINT")) {
+					a.Operation = OperationSchema
+				}
+			}
+		case cypherlex.Clause(toks, i, "MERGE") || cypherlex.Clause(toks, i, "SET") || cypherlex.Clause(toks, i, "DELETE") ||
+			cypherlex.Clause(toks, i, "REMOVE") || cypherlex.Clause(toks, i, "FOREACH"):
+			if a.Operation == OperationRead {
+				a.Operation = OperationWrite
+			}
+		case cypherlex.Clause(toks, i, "CALL") && i+1 < len(toks) && !toks[i+1].Punct("{"):
+			var name strings.Builder
+			for _, n := range toks[i+1:] {
+				if n.Kind != cypherlex.Word && n.Kind != cypherlex.Quoted && !n.Punct(".") {
+					break
+				}
+				name.WriteString(n.Name())
+			}
+			if p := name.String(); p != "" && !seenProc[p] {
+				seenProc[p] = true
+				a.Procedures = append(a.Procedures, p)
+			}
+		case t.Punct(":") || t.Punct("|") || t.Punct("&"):
+			if i+1 >= len(toks) || toks[i+1].Kind != cypherlex.Word && toks[i+1].Kind != cypherlex.Quoted {
+				continue
+			}
+			name := toks[i+1].Name()
+			switch {
+			case t.In == 'r':
+				if !seenType[name] {
+					seenType[name] = true
+					a.RelationshipTypes = append(a.RelationshipTypes, name)
+				}
+			case t.In == '(' || t.In == 0 && t.Punct(":") && i > 0 && (toks[i-1].Kind == cypherlex.Word || toks[i-1].Kind == cypherlex.Quoted):
+				if !seenLabel[name] {
+					seenLabel[name] = true
+					a.Labels = append(a.Labels, name)
+				}
+			}
+		}
+	}
+	return a
+}
+
+// Authorizer decides whether a request may be sent. Install one with
+// Config.Authorizer; it runs after Config.QueryPolicy for Cypher
+// statements, on the query as rewritten, and before node,
+// relationship and index REST calls.
+type Authorizer interface {
+	Authorize(ctx context.Context, access Access) error
+}
+
+// AuthorizerFunc adapts a function to Authorizer.
+type AuthorizerFunc func(ctx context.Context, access Access) error
+
+// Authorize calls f(ctx, access).
+func (f AuthorizerFunc) Authorize(ctx context.Context, access Access) error { return f(ctx, access) }
+
+// AccessDeniedError is returned when the Authorizer refuses a request.
+type AccessDeniedError struct {
+	Access Access
+	Err    error
+}
+
+func (e *AccessDeniedError) Error() string { return "nexus: access denied: " + e.Err.Error() }
+
+func (e *AccessDeniedError) Unwrap() error { return e.Err }
+
+// authorize runs Config.Authorizer, if any. Every entity REST call
+// passes through it, so it also stops REST writes in dry-run mode.
+func (c *Client) authorize(ctx context.Context, access Access) error {
+	if access.Query == "" && access.Operation != OperationRead && c.isDryRun(ctx) {
+		return ErrDryRun
+	}
+	if c.authorizer == nil {
+		return nil
+	}
+	if err := c.authorizer.Authorize(ctx, access); err != nil {
+		return &AccessDeniedError{Access: access, Err: err}
+	}
+	return nil
+}
+
+// appendUnique appends the names not already in list.
+func appendUnique(list []string, names ...string) []string {
+	for _, n := range names {
+		found := false
+		for _, l := range list {
+			if l == n {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, n)
+		}
+	}
+	return list
+}
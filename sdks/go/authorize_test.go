@@ -0,0 +1,76 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeQuery(t *testing.T) {
+	a := AnalyzeQuery("MATCH (a:Person:`Tenant A`)-[:KNOWS|LIKES]->(b {name: 'x:Fake'}) WHERE b:Admin RETURN a.name")
+	assert.Equal(t, OperationRead, a.Operation)
+	assert.Equal(t, []string{"Person", "Tenant A", "Admin"}, a.Labels)
+	assert.Equal(t, []string{"KNOWS", "LIKES"}, a.RelationshipTypes)
+
+	a = AnalyzeQuery("MATCH (n:Person) SET n:Archived, n.set = 1")
+	assert.Equal(t, OperationWrite, a.Operation)
+	assert.Equal(t, []string{"Person", "Archived"}, a.Labels)
+
+	a = AnalyzeQuery("CREATE INDEX person_name IF NOT EXISTS FOR (n:Person) ON (n.name)")
+	assert.Equal(t, OperationSchema, a.Operation)
+	assert.Equal(t, []string{"Person"}, a.Labels)
+
+	a = AnalyzeQuery("CALL db.labels() YIELD label CALL { RETURN 1 AS one } RETURN label // CREATE (n:Hidden)")
+	assert.Equal(t, OperationRead, a.Operation)
+	assert.Equal(t, []string{"db.labels"}, a.Procedures)
+	assert.Empty(t, a.Labels)
+}
+
+func TestAuthorizer(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	var seen []Access
+	tenantOnly := AuthorizerFunc(func(_ context.Context, a Access) error {
+		seen = append(seen, a)
+		for _, l := range a.Labels {
+			if l != "Acme" {
+				return errors.New("label " + l + " is outside the tenant")
+			}
+		}
+		if a.Operation == OperationSchema {
+			return errors.New("schema changes are not allowed")
+		}
+		return nil
+	})
+	client := NewClient(Config{BaseURL: server.URL, Authorizer: tenantOnly, QueryPolicy: Guardrails{DefaultLimit: 5}})
+	ctx := context.Background()
+
+	_, err := client.ExecuteCypher(ctx, "MATCH (n:Acme) RETURN n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (n:Acme) RETURN n LIMIT 5", seen[0].Query)
+
+	_, err = client.ExecuteCypher(ctx, "MATCH (n:Globex) RETURN n", nil)
+	var denied *AccessDeniedError
+	require.True(t, errors.As(err, &denied))
+	assert.Equal(t, []string{"Globex"}, denied.Access.Labels)
+	assert.EqualError(t, err, "nexus: access denied: label Globex is outside the tenant")
+
+	_, err = client.CreateNode(ctx, []string{"Acme", "Other"}, nil)
+	assert.ErrorAs(t, err, &denied)
+	err = client.CreateIndex(ctx, "idx", "Acme", []string{"name"})
+	assert.ErrorAs(t, err, &denied)
+	err = client.DeleteNode(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, Access{Operation: OperationWrite}, seen[len(seen)-1])
+	assert.Equal(t, 2, requests)
+}
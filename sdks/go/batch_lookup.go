@@ -0,0 +1,208 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hivellm/nexus-go/internal/ids"
+)
+
+// NodeLookup identifies a relationship endpoint either by node ID or by
+// a (label, key property, value) lookup resolved server-side.
+type NodeLookup struct {
+	// ID selects the node directly.
+	ID string
+	// Label, Key and Value select the single node with that label whose
+	// Key property equals Value.
+	Label string
+	Key   string
+	Value interface{}
+}
+
+// NodeByID returns a lookup for a known node ID.
+func NodeByID(id string) NodeLookup { return NodeLookup{ID: id} }
+
+// NodeByKey returns a lookup for the node with label whose key property
+// equals value.
+func NodeByKey(label, key string, value interface{}) NodeLookup {
+	return NodeLookup{Label: label, Key: key, Value: value}
+}
+
+func (l NodeLookup) String() string {
+	if l.ID != "" {
+		return "id " + l.ID
+	}
+	return fmt.Sprintf("(:%s {%s: %v})", l.Label, l.Key, l.Value)
+}
+
+// RelationshipBatchInput is one relationship for
+// BatchCreateRelationships.
+type RelationshipBatchInput struct {
+	Start      NodeLookup
+	End        NodeLookup
+	Type       string
+	Properties map[string]interface{}
+}
+
+// EndpointLookupError reports a relationship whose endpoint lookups did
+// not resolve to exactly one (start, end) pair of nodes.
+type EndpointLookupError struct {
+	// Item is the index of the relationship in the input.
+	Item       int
+	Start, End NodeLookup
+	// Pairs is the number of node pairs the lookups matched.
+	Pairs int
+}
+
+func (e *EndpointLookupError) Error() string {
+	return fmt.Sprintf("nexus: relationship %d: endpoints %s -> %s matched %d node pair(s), expected 1",
+		e.Item, e.Start, e.End, e.Pairs)
+}
+
+// BatchCreateRelationships creates multiple relationships. Endpoints are
+// node IDs (NodeByID) or lookup keys (NodeByKey), so imports need not
+// resolve every node ID client-side first:
+//
+//	rels, err := client.BatchCreateRelationships(ctx, []nexus.RelationshipBatchInput{
+//		{Start: nexus.NodeByID(aliceID), End: nexus.NodeByKey("Company", "domain", "example.com"), Type: "WORKS_AT"},
+//	})
+//
+// A batch of IDs only is sent in a single request. Otherwise the
+// relationships are grouped by type and endpoint shape into UNWIND
+// statements run in one transaction; if any lookup matches no node or
+// several, nothing is created and an *EndpointLookupError is returned.
+// Results are in input order.
+func (c *Client) BatchCreateRelationships(ctx context.Context, rels []RelationshipBatchInput) ([]Relationship, error) {
+	if len(rels) == 0 {
+		return nil, nil
+	}
+	byIDOnly := true
+	groups := map[string][]interface{}{}
+	specs := map[string]RelationshipBatchInput{}
+	for i, r := range rels {
+		if err := validLabelIdentifier(r.Type); err != nil {
+			return nil, fmt.Errorf("nexus: relationship %d: %w", i, err)
+		}
+		start, startShape, err := lookupParam(r.Start)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: relationship %d start: %w", i, err)
+		}
+		end, endShape, err := lookupParam(r.End)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: relationship %d end: %w", i, err)
+		}
+		byIDOnly = byIDOnly && r.Start.ID != "" && r.End.ID != ""
+
+		props := r.Properties
+		if props == nil {
+			props = map[string]interface{}{}
+		}
+		group := strings.Join([]string{r.Type, startShape, endShape}, "|")
+		specs[group] = r
+		groups[group] = append(groups[group], map[string]interface{}{
+			"i": i, "start": start, "end": end, "props": props,
+		})
+	}
+
+	if byIDOnly {
+		input := make([]batchRelationship, len(rels))
+		for i, r := range rels {
+			input[i] = batchRelationship{StartNode: r.Start.ID, EndNode: r.End.ID, Type: r.Type, Properties: r.Properties}
+		}
+		return c.batchCreateRelationshipsByID(ctx, input)
+	}
+
+	tx, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := batchByLookupTx(ctx, tx, rels, groups, specs)
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return nil, fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func batchByLookupTx(ctx context.Context, tx *Transaction, rels []RelationshipBatchInput, groups map[string][]interface{}, specs map[string]RelationshipBatchInput) ([]Relationship, error) {
+	out := make([]Relationship, len(rels))
+	pairs := make([]int, len(rels))
+	for _, group := range sortedKeys(groups) {
+		spec := specs[group]
+		query := "UNWIND $rows AS row " +
+			lookupMatch("a", "row.start", spec.Start) + " " +
+			lookupMatch("b", "row.end", spec.End) + " " +
+			fmt.Sprintf("CREATE (a)-[r:%s]->(b) SET r = row.props ", spec.Type) +
+			"RETURN row.i AS i, id(r) AS id, id(a) AS start, id(b) AS end, properties(r) AS props"
+		result, err := tx.ExecuteCypher(ctx, query, map[string]interface{}{"rows": groups[group]})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Rows {
+			if len(row) < 5 {
+				continue
+			}
+			i := asInt(row[0])
+			if i < 0 || i >= len(rels) {
+				return nil, fmt.Errorf("nexus: unexpected relationship index %v in batch result", row[0])
+			}
+			props, _ := row[4].(map[string]interface{})
+			pairs[i]++
+			out[i] = Relationship{
+				ID:         idString(row[1]),
+				Type:       rels[i].Type,
+				StartNode:  idString(row[2]),
+				EndNode:    idString(row[3]),
+				Properties: props,
+			}
+		}
+	}
+	for i, n := range pairs {
+		if n != 1 {
+			return nil, &EndpointLookupError{Item: i, Start: rels[i].Start, End: rels[i].End, Pairs: n}
+		}
+	}
+	return out, nil
+}
+
+// lookupParam validates l and returns its UNWIND row value and a shape
+// key grouping lookups that share a MATCH clause.
+func lookupParam(l NodeLookup) (interface{}, string, error) {
+	if l.ID != "" {
+		id, err := parseID(l.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		return id, "id", nil
+	}
+	if l.Label == "" || l.Key == "" {
+		return nil, "", errors.New("endpoint needs an ID or a label and key")
+	}
+	if err := validLabelIdentifier(l.Label); err != nil {
+		return nil, "", err
+	}
+	if err := validLabelIdentifier(l.Key); err != nil {
+		return nil, "", err
+	}
+	if l.Value == nil {
+		return nil, "", fmt.Errorf("lookup %s.%s has no value", l.Label, l.Key)
+	}
+	return l.Value, l.Label + "." + l.Key, nil
+}
+
+func lookupMatch(variable, param string, l NodeLookup) string {
+	if l.ID != "" {
+		return fmt.Sprintf("MATCH (%s) WHERE id(%s) = %s", variable, variable, param)
+	}
+	return fmt.Sprintf("MATCH (%s:%s {%s: %s})", variable, l.Label, l.Key, param)
+}
+
+// idString formats an ID cell as ids.Format does.
+func idString(v interface{}) string { return ids.Format(v) }
@@ -0,0 +1,139 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lookupServer resolves Person nodes by email and echoes created
+// relationships; "ghost@x" matches nothing.
+func lookupServer(t *testing.T, queries *[]string, rolledBack *bool) *httptest.Server {
+	ids := map[string]float64{"ann@x": 1, "bob@x": 2}
+	next := 100.0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/transaction/begin":
+			json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx"})
+			return
+		case "/transaction/commit":
+			w.Write([]byte(`{}`))
+			return
+		case "/transaction/rollback":
+			*rolledBack = true
+			w.Write([]byte(`{}`))
+			return
+		}
+		var req struct {
+			Query      string                 `json:"query"`
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*queries = append(*queries, req.Query)
+		var rows [][]interface{}
+		for _, raw := range req.Parameters["rows"].([]interface{}) {
+			row := raw.(map[string]interface{})
+			resolve := func(v interface{}) (float64, bool) {
+				if s, ok := v.(string); ok {
+					id, ok := ids[s]
+					return id, ok
+				}
+				return v.(float64), true
+			}
+			a, okA := resolve(row["start"])
+			b, okB := resolve(row["end"])
+			if okA && okB {
+				next++
+				rows = append(rows, []interface{}{row["i"], next, a, b, row["props"]})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"i", "id", "start", "end", "props"}, "rows": rows})
+	}))
+}
+
+func TestBatchCreateRelationships(t *testing.T) {
+	var queries []string
+	var rolledBack bool
+	server := lookupServer(t, &queries, &rolledBack)
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	rels, err := client.BatchCreateRelationships(context.Background(), []RelationshipBatchInput{
+		{Start: NodeByKey("Person", "email", "ann@x"), End: NodeByKey("Person", "email", "bob@x"), Type: "KNOWS"},
+		{Start: NodeByID("7"), End: NodeByKey("Person", "email", "ann@x"), Type: "FOLLOWS", Properties: map[string]interface{}{"since": 2020}},
+		{Start: NodeByKey("Person", "email", "bob@x"), End: NodeByKey("Person", "email", "ann@x"), Type: "KNOWS"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, rels, 3)
+	assert.Equal(t, Relationship{ID: "101", Type: "FOLLOWS", StartNode: "7", EndNode: "1", Properties: map[string]interface{}{"since": float64(2020)}}, rels[1])
+	assert.Equal(t, "2", rels[2].StartNode)
+	assert.Equal(t, "KNOWS", rels[0].Type)
+	require.Len(t, queries, 2)
+	assert.True(t, strings.HasPrefix(queries[0], "UNWIND $rows AS row MATCH (a) WHERE id(a) = row.start MATCH (b:Person {email: row.end}) CREATE (a)-[r:FOLLOWS]->(b)"))
+	assert.Contains(t, queries[1], "MATCH (a:Person {email: row.start}) MATCH (b:Person {email: row.end}) CREATE (a)-[r:KNOWS]->(b)")
+}
+
+func TestBatchCreateRelationshipsUnresolved(t *testing.T) {
+	var queries []string
+	var rolledBack bool
+	server := lookupServer(t, &queries, &rolledBack)
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	_, err := client.BatchCreateRelationships(context.Background(), []RelationshipBatchInput{
+		{Start: NodeByKey("Person", "email", "ann@x"), End: NodeByKey("Person", "email", "bob@x"), Type: "KNOWS"},
+		{Start: NodeByKey("Person", "email", "ann@x"), End: NodeByKey("Person", "email", "ghost@x"), Type: "KNOWS"},
+	})
+
+	var lookupErr *EndpointLookupError
+	require.ErrorAs(t, err, &lookupErr)
+	assert.Equal(t, 1, lookupErr.Item)
+	assert.Equal(t, 0, lookupErr.Pairs)
+	assert.Contains(t, err.Error(), "(:Person {email: ghost@x})")
+	assert.True(t, rolledBack)
+}
+
+func TestBatchCreateRelationshipsValidation(t *testing.T) {
+	client := NewClient(Config{BaseURL: "http://localhost:1"})
+	_, err := client.BatchCreateRelationships(context.Background(), []RelationshipBatchInput{
+		{Start: NodeByKey("Bad Label", "id", 1), End: NodeByID("2"), Type: "KNOWS"},
+	})
+	assert.ErrorContains(t, err, "relationship 0 start")
+
+	_, err = client.BatchCreateRelationships(context.Background(), []RelationshipBatchInput{
+		{Start: NodeLookup{}, End: NodeByID("2"), Type: "KNOWS"},
+	})
+	assert.ErrorContains(t, err, "needs an ID or a label and key")
+}
+
+func TestBatchCreateRelationshipsByID(t *testing.T) {
+	var body struct{ Relationships []batchRelationship }
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/batch/relationships", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		json.NewEncoder(w).Encode([]Relationship{{ID: "9", Type: "KNOWS", StartNode: "1", EndNode: "2"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	rels, err := client.BatchCreateRelationships(context.Background(), []RelationshipBatchInput{
+		{Start: NodeByID("1"), End: NodeByID("2"), Type: "KNOWS", Properties: map[string]interface{}{"since": "2020"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Relationship{{ID: "9", Type: "KNOWS", StartNode: "1", EndNode: "2"}}, rels)
+	assert.Equal(t, []batchRelationship{{StartNode: "1", EndNode: "2", Type: "KNOWS", Properties: map[string]interface{}{"since": "2020"}}}, body.Relationships)
+}
+
+func TestIDString(t *testing.T) {
+	assert.Equal(t, "1234567", idString(1234567.0))
+	assert.Equal(t, "9007199254740993", idString(int64(9007199254740993)))
+	assert.Equal(t, "1.5", idString(1.5))
+	assert.Equal(t, "ext-1", idString("ext-1"))
+}
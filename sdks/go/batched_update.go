@@ -0,0 +1,116 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BatchedUpdateOptions tunes BatchedUpdate.
+type BatchedUpdateOptions struct {
+	// Params are passed to every batch.
+	Params map[string]interface{}
+	// OnProgress is called after every committed batch.
+	OnProgress func(BatchProgress)
+	// MaxBatches stops the loop after this many batches (0 means no
+	// limit), a safety net for updates that never stop matching.
+	MaxBatches int
+}
+
+// BatchProgress reports how far a BatchedUpdate has got.
+type BatchProgress struct {
+	Batches   int
+	Updated   int64
+	LastBatch int64
+	Elapsed   time.Duration
+}
+
+// RowsPerSecond is the average update rate so far.
+func (p BatchProgress) RowsPerSecond() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Updated) / p.Elapsed.Seconds()
+}
+
+type batchProgressKey struct{}
+
+// WithBatchProgress returns a context whose batched operations
+// (BatchedUpdate, RenameLabel, RenameProperty, …) call fn after every
+// committed batch, for those that take no options:
+//
+//	ctx = nexus.WithBatchProgress(ctx, func(p nexus.BatchProgress) {
+//	    log.Printf("%d renamed (%.0f/s)", p.Updated, p.RowsPerSecond())
+//	})
+func WithBatchProgress(ctx context.Context, fn func(BatchProgress)) context.Context {
+	return context.WithValue(ctx, batchProgressKey{}, fn)
+}
+
+// ErrMaxBatches is returned by BatchedUpdate when it reaches MaxBatches
+// and the last batch was full, so rows may still match.
+var ErrMaxBatches = errors.New("nexus: batched update stopped at MaxBatches")
+
+// BatchedUpdate repairs large data sets batchSize rows at a time, each
+// batch in its own transaction, until matchQuery finds nothing left:
+//
+//	progress, err := client.BatchedUpdate(ctx,
+//	    "MATCH (p:Person) WHERE p.email_lower IS NULL",
+//	    "SET p.email_lower = toLower(p.email)",
+//	    5000, nexus.BatchedUpdateOptions{})
+//
+// matchQuery is the MATCH/WHERE part without RETURN, and updateQuery
+// may use its variables. The update must make rows stop matching, or
+// the loop never ends; MaxBatches bounds it. A failed batch stops the
+// loop with earlier batches committed and the returned progress
+// counting them.
+func (c *Client) BatchedUpdate(ctx context.Context, matchQuery, updateQuery string, batchSize int, opts BatchedUpdateOptions) (BatchProgress, error) {
+	var progress BatchProgress
+	if strings.TrimSpace(matchQuery) == "" || strings.TrimSpace(updateQuery) == "" {
+		return progress, errors.New("nexus: batched update needs a match and an update query")
+	}
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	query := strings.TrimRight(strings.TrimSpace(matchQuery), ";") +
+		" WITH * LIMIT $__batch " +
+		strings.TrimRight(strings.TrimSpace(updateQuery), ";") +
+		" RETURN count(*) AS __updated"
+	params := make(map[string]interface{}, len(opts.Params)+1)
+	for k, v := range opts.Params {
+		params[k] = v
+	}
+	params["__batch"] = batchSize
+
+	start := time.Now()
+	for {
+		if opts.MaxBatches > 0 && progress.Batches >= opts.MaxBatches {
+			return progress, ErrMaxBatches
+		}
+		result, err := c.ExecuteCypher(ctx, query, params)
+		if err != nil {
+			return progress, fmt.Errorf("nexus: batched update batch %d: %w", progress.Batches+1, err)
+		}
+		var n int64
+		if len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
+			n = int64(asInt(result.Rows[0][0]))
+		}
+		if n == 0 {
+			return progress, nil
+		}
+		progress.Batches++
+		progress.Updated += n
+		progress.LastBatch = n
+		progress.Elapsed = time.Since(start)
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		if fn, ok := ctx.Value(batchProgressKey{}).(func(BatchProgress)); ok && fn != nil {
+			fn(progress)
+		}
+		if n < int64(batchSize) {
+			return progress, nil
+		}
+	}
+}
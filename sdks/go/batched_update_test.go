@@ -0,0 +1,72 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func batchServer(t *testing.T, remaining *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string                 `json:"query"`
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "MATCH (p:Person) WHERE p.email_lower IS NULL WITH * LIMIT $__batch SET p.email_lower = toLower(p.email) RETURN count(*) AS __updated", req.Query)
+		assert.Equal(t, "x", req.Parameters["extra"])
+		n := int(req.Parameters["__batch"].(float64))
+		if n > *remaining {
+			n = *remaining
+		}
+		*remaining -= n
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"__updated"}, "rows": [][]interface{}{{n}}})
+	}))
+}
+
+func TestBatchedUpdate(t *testing.T) {
+	remaining := 25
+	server := batchServer(t, &remaining)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	var seen []BatchProgress
+	progress, err := client.BatchedUpdate(context.Background(),
+		"MATCH (p:Person) WHERE p.email_lower IS NULL",
+		"SET p.email_lower = toLower(p.email);",
+		10, BatchedUpdateOptions{
+			Params:     map[string]interface{}{"extra": "x"},
+			OnProgress: func(p BatchProgress) { seen = append(seen, p) },
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 3, progress.Batches)
+	assert.Equal(t, int64(25), progress.Updated)
+	assert.Equal(t, int64(5), progress.LastBatch)
+	require.Len(t, seen, 3)
+	assert.Equal(t, int64(20), seen[1].Updated)
+	assert.Equal(t, 0, remaining)
+}
+
+func TestBatchedUpdateMaxBatches(t *testing.T) {
+	remaining := 100
+	server := batchServer(t, &remaining)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	progress, err := client.BatchedUpdate(context.Background(),
+		"MATCH (p:Person) WHERE p.email_lower IS NULL",
+		"SET p.email_lower = toLower(p.email)",
+		10, BatchedUpdateOptions{Params: map[string]interface{}{"extra": "x"}, MaxBatches: 2})
+	assert.True(t, errors.Is(err, ErrMaxBatches))
+	assert.Equal(t, int64(20), progress.Updated)
+	assert.Equal(t, 80, remaining)
+
+	_, err = client.BatchedUpdate(context.Background(), "", "SET x = 1", 10, BatchedUpdateOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package nexus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// RequestBody is a request payload that can be sent more than once, so
+// that any request, including streamed uploads, can be retried,
+// hedged or replayed after a redirect. Open returns a fresh reader
+// positioned at the start and may be called concurrently.
+type RequestBody interface {
+	Open() (io.ReadCloser, error)
+	// ContentType is the media type sent with the body.
+	ContentType() string
+	// Len is the body size in bytes, or -1 when unknown.
+	Len() int64
+}
+
+// BytesBody is an in-memory body.
+func BytesBody(data []byte, contentType string) RequestBody {
+	return &bytesBody{data: data, contentType: contentType}
+}
+
+type bytesBody struct {
+	data        []byte
+	contentType string
+}
+
+func (b *bytesBody) Open() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(b.data)), nil
+}
+func (b *bytesBody) ContentType() string { return b.contentType }
+func (b *bytesBody) Len() int64          { return int64(len(b.data)) }
+
+// ReaderBody makes a one-shot reader replayable by buffering it in
+// memory the first time it is opened. Use ReopenBody instead for
+// payloads too large to hold, such as files.
+func ReaderBody(r io.Reader, contentType string) RequestBody {
+	return &readerBody{r: r, contentType: contentType}
+}
+
+type readerBody struct {
+	r           io.Reader
+	contentType string
+
+	once sync.Once
+	data []byte
+	err  error
+}
+
+func (b *readerBody) buffer() {
+	b.data, b.err = io.ReadAll(b.r)
+	if c, ok := b.r.(io.Closer); ok {
+		c.Close()
+	}
+	b.r = nil
+}
+
+func (b *readerBody) Open() (io.ReadCloser, error) {
+	b.once.Do(b.buffer)
+	if b.err != nil {
+		return nil, fmt.Errorf("failed to buffer request body: %w", b.err)
+	}
+	return io.NopCloser(bytes.NewReader(b.data)), nil
+}
+func (b *readerBody) ContentType() string { return b.contentType }
+func (b *readerBody) Len() int64 {
+	b.once.Do(b.buffer)
+	return int64(len(b.data))
+}
+
+// ReopenBody streams a body from open, calling it again for every
+// attempt, e.g. to re-open a file instead of buffering it:
+//
+//	body := nexus.ReopenBody(func() (io.ReadCloser, error) { return os.Open(path) }, "text/csv", size)
+//
+// size may be -1 when unknown.
+func ReopenBody(open func() (io.ReadCloser, error), contentType string, size int64) RequestBody {
+	return &reopenBody{open: open, contentType: contentType, size: size}
+}
+
+type reopenBody struct {
+	open        func() (io.ReadCloser, error)
+	contentType string
+	size        int64
+}
+
+func (b *reopenBody) Open() (io.ReadCloser, error) { return b.open() }
+func (b *reopenBody) ContentType() string          { return b.contentType }
+func (b *reopenBody) Len() int64                   { return b.size }
+
+// requestBody turns a doRequest body into a RequestBody: RequestBody
+// values pass through, readers are buffered on first use and anything
+// else is marshalled to JSON once, however often it is sent.
+func requestBody(body interface{}) (RequestBody, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case RequestBody:
+		return b, nil
+	case io.Reader:
+		return ReaderBody(b, "application/octet-stream"), nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	return BytesBody(data, "application/json"), nil
+}
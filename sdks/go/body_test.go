@@ -0,0 +1,83 @@
+package nexus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyEcho fails the first attempts with 503 and records every body.
+func flakyEcho(t *testing.T, failures int32, bodies *[]string, types *[]string) *httptest.Server {
+	var calls int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		*bodies = append(*bodies, string(data))
+		*types = append(*types, r.Header.Get("Content-Type"))
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+}
+
+func TestRetryReplaysReaderBody(t *testing.T) {
+	var bodies, types []string
+	server := flakyEcho(t, 2, &bodies, &types)
+	defer server.Close()
+	rc := NewClient(Config{BaseURL: server.URL}).WithRetry(&RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffMultiplier: 1, RetryableStatusCodes: []int{http.StatusServiceUnavailable}})
+
+	resp, err := rc.Client.sendWithRetry(context.Background(), http.MethodPost, "/import", strings.NewReader("a,b\n1,2\n"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"a,b\n1,2\n", "a,b\n1,2\n", "a,b\n1,2\n"}, bodies)
+	assert.Equal(t, "application/octet-stream", types[0])
+}
+
+func TestRetryReopensBody(t *testing.T) {
+	var bodies, types []string
+	server := flakyEcho(t, 1, &bodies, &types)
+	defer server.Close()
+	rc := NewClient(Config{BaseURL: server.URL}).WithRetry(&RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffMultiplier: 1, RetryableStatusCodes: []int{http.StatusServiceUnavailable}})
+
+	opens := 0
+	body := ReopenBody(func() (io.ReadCloser, error) {
+		opens++
+		return io.NopCloser(strings.NewReader("payload")), nil
+	}, "text/csv", 7)
+	resp, err := rc.Client.sendWithRetry(context.Background(), http.MethodPost, "/import", body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 2, opens)
+	assert.Equal(t, []string{"payload", "payload"}, bodies)
+	assert.Equal(t, []string{"text/csv", "text/csv"}, types)
+}
+
+func TestRequestBodyMarshalsValuesAsJSON(t *testing.T) {
+	rb, err := requestBody(map[string]interface{}{"query": "RETURN 1"})
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", rb.ContentType())
+	for i := 0; i < 2; i++ {
+		r, err := rb.Open()
+		require.NoError(t, err)
+		data, _ := io.ReadAll(r)
+		assert.JSONEq(t, `{"query":"RETURN 1"}`, string(data))
+	}
+	assert.Equal(t, int64(len(`{"query":"RETURN 1"}`)), rb.Len())
+
+	rb, err = requestBody(nil)
+	require.NoError(t, err)
+	assert.Nil(t, rb)
+
+	_, err = requestBody(func() {})
+	assert.Error(t, err)
+}
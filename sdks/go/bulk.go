@@ -0,0 +1,228 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BulkNode is a node queued on a BulkLoader.
+type BulkNode struct {
+	Labels     []string
+	Properties map[string]interface{}
+	// Key, when set, names the property identifying the node: the node is
+	// MERGEd on (first label, Key) and its other properties are updated,
+	// making re-runs idempotent. Empty keys always CREATE.
+	Key string
+}
+
+// BulkEndpoint identifies a relationship endpoint by a unique property.
+type BulkEndpoint struct {
+	Label string
+	Key   string
+	Value interface{}
+}
+
+// BulkRelationship is a relationship queued on a BulkLoader. Endpoints
+// are looked up by key, so they may be nodes queued on the same loader.
+type BulkRelationship struct {
+	Type       string
+	From       BulkEndpoint
+	To         BulkEndpoint
+	Properties map[string]interface{}
+}
+
+// BulkLoaderOptions configures a BulkLoader.
+type BulkLoaderOptions struct {
+	// BatchSize is the number of rows sent per UNWIND statement
+	// (default 1000).
+	BatchSize int
+	// CreateRelationships uses CREATE instead of MERGE for
+	// relationships: faster, but re-runs produce duplicates.
+	CreateRelationships bool
+}
+
+// BulkStats counts what a BulkLoader has written so far.
+type BulkStats struct {
+	Nodes         int
+	Relationships int
+	Batches       int
+}
+
+// BulkLoader buffers nodes and relationships and writes them in batched
+// UNWIND statements grouped by label/type. Queued nodes are always
+// flushed before relationships so lookups find their endpoints.
+//
+// A BulkLoader is not safe for concurrent use. Call Flush when done.
+type BulkLoader struct {
+	client *Client
+	opts   BulkLoaderOptions
+
+	nodes     map[string][]interface{}
+	nodeSpecs map[string]BulkNode
+	rels      map[string][]interface{}
+	relSpecs  map[string]BulkRelationship
+	stats     BulkStats
+}
+
+// NewBulkLoader returns a loader writing through c.
+func (c *Client) NewBulkLoader(opts BulkLoaderOptions) *BulkLoader {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	return &BulkLoader{
+		client:    c,
+		opts:      opts,
+		nodes:     map[string][]interface{}{},
+		nodeSpecs: map[string]BulkNode{},
+		rels:      map[string][]interface{}{},
+		relSpecs:  map[string]BulkRelationship{},
+	}
+}
+
+// AddNode queues a node, flushing when a batch fills up.
+func (b *BulkLoader) AddNode(ctx context.Context, n BulkNode) error {
+	if len(n.Labels) == 0 && n.Key != "" {
+		return errors.New("nexus: bulk node with a key needs a label")
+	}
+	for _, l := range n.Labels {
+		if err := validLabelIdentifier(l); err != nil {
+			return err
+		}
+	}
+	props := n.Properties
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	row := map[string]interface{}{"props": props}
+	if n.Key != "" {
+		if err := validLabelIdentifier(n.Key); err != nil {
+			return err
+		}
+		value, ok := n.Properties[n.Key]
+		if !ok || value == nil {
+			return fmt.Errorf("nexus: bulk node is missing its key property %q", n.Key)
+		}
+		row["key"] = value
+	}
+	group := strings.Join(n.Labels, ":") + "|" + n.Key
+	b.nodeSpecs[group] = BulkNode{Labels: n.Labels, Key: n.Key}
+	b.nodes[group] = append(b.nodes[group], row)
+	if len(b.nodes[group]) >= b.opts.BatchSize {
+		return b.flushNodeGroup(ctx, group)
+	}
+	return nil
+}
+
+// AddRelationship queues a relationship. Filling a relationship batch
+// flushes every queued node first.
+func (b *BulkLoader) AddRelationship(ctx context.Context, r BulkRelationship) error {
+	for _, name := range []string{r.Type, r.From.Label, r.From.Key, r.To.Label, r.To.Key} {
+		if err := validLabelIdentifier(name); err != nil {
+			return err
+		}
+	}
+	props := r.Properties
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	group := strings.Join([]string{r.Type, r.From.Label, r.From.Key, r.To.Label, r.To.Key}, "|")
+	b.relSpecs[group] = BulkRelationship{Type: r.Type, From: BulkEndpoint{Label: r.From.Label, Key: r.From.Key}, To: BulkEndpoint{Label: r.To.Label, Key: r.To.Key}}
+	b.rels[group] = append(b.rels[group], map[string]interface{}{
+		"from": r.From.Value, "to": r.To.Value, "props": props,
+	})
+	if len(b.rels[group]) >= b.opts.BatchSize {
+		if err := b.flushNodes(ctx); err != nil {
+			return err
+		}
+		return b.flushRelGroup(ctx, group)
+	}
+	return nil
+}
+
+// Flush writes everything still queued: nodes first, then relationships.
+func (b *BulkLoader) Flush(ctx context.Context) error {
+	if err := b.flushNodes(ctx); err != nil {
+		return err
+	}
+	for _, group := range sortedKeys(b.rels) {
+		if err := b.flushRelGroup(ctx, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats returns the counts written so far.
+func (b *BulkLoader) Stats() BulkStats { return b.stats }
+
+func (b *BulkLoader) flushNodes(ctx context.Context) error {
+	for _, group := range sortedKeys(b.nodes) {
+		if err := b.flushNodeGroup(ctx, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *BulkLoader) flushNodeGroup(ctx context.Context, group string) error {
+	rows := b.nodes[group]
+	if len(rows) == 0 {
+		return nil
+	}
+	spec := b.nodeSpecs[group]
+	labels := ""
+	for _, l := range spec.Labels {
+		labels += ":" + l
+	}
+	var query string
+	if spec.Key != "" {
+		query = fmt.Sprintf("UNWIND $rows AS row MERGE (n:%s {%s: row.key}) SET n += row.props", spec.Labels[0], spec.Key)
+		for _, l := range spec.Labels[1:] {
+			query += ", n:" + l
+		}
+	} else {
+		query = fmt.Sprintf("UNWIND $rows AS row CREATE (n%s) SET n = row.props", labels)
+	}
+	if _, err := b.client.ExecuteCypher(ctx, query, map[string]interface{}{"rows": rows}); err != nil {
+		return fmt.Errorf("nexus: bulk load %s nodes: %w", labels, err)
+	}
+	b.stats.Nodes += len(rows)
+	b.stats.Batches++
+	delete(b.nodes, group)
+	return nil
+}
+
+func (b *BulkLoader) flushRelGroup(ctx context.Context, group string) error {
+	rows := b.rels[group]
+	if len(rows) == 0 {
+		return nil
+	}
+	spec := b.relSpecs[group]
+	verb := "MERGE"
+	if b.opts.CreateRelationships {
+		verb = "CREATE"
+	}
+	query := fmt.Sprintf(
+		"UNWIND $rows AS row MATCH (a:%s {%s: row.from}) MATCH (b:%s {%s: row.to}) "+
+			"%s (a)-[r:%s]->(b) SET r += row.props",
+		spec.From.Label, spec.From.Key, spec.To.Label, spec.To.Key, verb, spec.Type)
+	if _, err := b.client.ExecuteCypher(ctx, query, map[string]interface{}{"rows": rows}); err != nil {
+		return fmt.Errorf("nexus: bulk load %s relationships: %w", spec.Type, err)
+	}
+	b.stats.Relationships += len(rows)
+	b.stats.Batches++
+	delete(b.rels, group)
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
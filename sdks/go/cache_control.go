@@ -0,0 +1,43 @@
+package nexus
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// CacheControl overrides the server's result-cache behaviour for the
+// requests made with a context (see WithCacheControl).
+type CacheControl struct {
+	// NoCache bypasses the result cache: the query always executes and
+	// its fresh result replaces the cached entry.
+	NoCache bool
+	// MaxStaleness accepts a cached result only if it is at most this
+	// old (rounded up to whole seconds). Zero keeps the server default.
+	MaxStaleness time.Duration
+}
+
+// WithCacheControl returns a context whose requests carry cc as a
+// `Cache-Control` header, for reads that temporarily need strict
+// freshness:
+//
+//	ctx := nexus.WithCacheControl(ctx, nexus.CacheControl{NoCache: true})
+//	result, err := client.ExecuteCypher(ctx, query, nil)
+func WithCacheControl(ctx context.Context, cc CacheControl) context.Context {
+	var directives []string
+	if cc.NoCache {
+		directives = append(directives, "no-cache")
+	}
+	if cc.MaxStaleness > 0 {
+		seconds := int64(math.Ceil(cc.MaxStaleness.Seconds()))
+		directives = append(directives, "max-age="+strconv.FormatInt(seconds, 10))
+	}
+	if len(directives) == 0 {
+		return ctx
+	}
+	return transport.WithHeader(ctx, "Cache-Control", strings.Join(directives, ", "))
+}
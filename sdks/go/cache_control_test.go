@@ -0,0 +1,34 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCacheControl(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Cache-Control"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	_, err := client.ExecuteCypher(WithCacheControl(ctx, CacheControl{NoCache: true}), "RETURN 1", nil)
+	require.NoError(t, err)
+	_, err = client.ExecuteCypher(WithCacheControl(ctx, CacheControl{MaxStaleness: 1500 * time.Millisecond}), "RETURN 1", nil)
+	require.NoError(t, err)
+	_, err = client.ExecuteCypher(ctx, "RETURN 1", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"no-cache", "max-age=2", ""}, got)
+}
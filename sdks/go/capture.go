@@ -0,0 +1,197 @@
+package nexus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CapturedQuery is one line of a traffic capture: a statement as the
+// client sent it, with what happened.
+type CapturedQuery struct {
+	Time          time.Time              `json:"time"`
+	Query         string                 `json:"query"`
+	Params        map[string]interface{} `json:"params,omitempty"`
+	DurationMs    float64                `json:"duration_ms"`
+	Rows          int                    `json:"rows"`
+	Error         string                 `json:"error,omitempty"`
+	InTransaction bool                   `json:"in_transaction,omitempty"`
+}
+
+// Redacted replaces parameter values in a capture.
+const Redacted = "[REDACTED]"
+
+// CaptureOptions tunes a TrafficRecorder.
+type CaptureOptions struct {
+	// SampleRate is the fraction of statements recorded, in (0, 1].
+	// Zero records every statement.
+	SampleRate float64
+	// Redact names parameters (case-insensitive, at any depth of nested
+	// maps) whose values are written as Redacted. Literals inside the
+	// query text are written as is, so sensitive values should be
+	// passed as parameters.
+	Redact []string
+}
+
+// TrafficRecorder is a MetricsHook that writes the statements a client
+// runs to w as JSON lines of CapturedQuery, for ReplayTraffic to
+// re-execute against another server, e.g. to load test an upgrade:
+//
+//	f, _ := os.Create("traffic.jsonl")
+//	rec := nexus.NewTrafficRecorder(f, nexus.CaptureOptions{SampleRate: 0.1, Redact: []string{"password", "email"}})
+//	client, _ := nexus.NewClientWithOptions(url, nexus.WithMetrics(rec))
+//
+// Writes are serialized, so a recorder may be shared by clients. The
+// first write error stops recording and is reported by Err.
+type TrafficRecorder struct {
+	opts   CaptureOptions
+	redact map[string]bool
+
+	mu   sync.Mutex
+	w    *bufio.Writer
+	rand *rand.Rand
+	err  error
+}
+
+// NewTrafficRecorder returns a recorder writing to w. Call Flush before
+// closing w.
+func NewTrafficRecorder(w io.Writer, opts CaptureOptions) *TrafficRecorder {
+	if opts.SampleRate <= 0 || opts.SampleRate > 1 {
+		opts.SampleRate = 1
+	}
+	redact := make(map[string]bool, len(opts.Redact))
+	for _, name := range opts.Redact {
+		redact[strings.ToLower(name)] = true
+	}
+	return &TrafficRecorder{
+		opts:   opts,
+		redact: redact,
+		w:      bufio.NewWriter(w),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// ObserveQuery records event, subject to sampling.
+func (r *TrafficRecorder) ObserveQuery(_ context.Context, event QueryEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil || r.opts.SampleRate < 1 && r.rand.Float64() >= r.opts.SampleRate {
+		return
+	}
+	rec := CapturedQuery{
+		Time:          event.Start.UTC(),
+		Query:         event.Query,
+		DurationMs:    float64(event.Duration) / float64(time.Millisecond),
+		Rows:          event.Rows,
+		InTransaction: event.InTransaction,
+	}
+	if event.Params != nil {
+		rec.Params, _ = r.redactValue(event.Params).(map[string]interface{})
+	}
+	if event.Err != nil {
+		rec.Error = event.Err.Error()
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		r.err = fmt.Errorf("nexus: capturing %q: %w", event.Query, err)
+		return
+	}
+	line = append(line, '\n')
+	if _, err := r.w.Write(line); err != nil {
+		r.err = err
+	}
+}
+
+// redactValue copies v with redacted map entries replaced.
+func (r *TrafficRecorder) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if r.redact[strings.ToLower(k)] {
+				out[k] = Redacted
+			} else {
+				out[k] = r.redactValue(item)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = r.redactValue(item)
+		}
+		return out
+	}
+	return v
+}
+
+// Flush writes buffered records to the underlying writer.
+func (r *TrafficRecorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.err = r.w.Flush()
+	return r.err
+}
+
+// Err returns the error that stopped recording, if any.
+func (r *TrafficRecorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// ReadCapture decodes a capture written by TrafficRecorder. Whole
+// numbers in parameters come back as int64, others as float64.
+func ReadCapture(r io.Reader) ([]CapturedQuery, error) {
+	var out []CapturedQuery
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.UseNumber()
+		var rec CapturedQuery
+		if err := dec.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("nexus: capture line %d: %w", line, err)
+		}
+		if rec.Params != nil {
+			rec.Params = fromJSONNumbers(rec.Params).(map[string]interface{})
+		}
+		out = append(out, rec)
+	}
+	return out, scanner.Err()
+}
+
+// fromJSONNumbers replaces json.Number values with int64 or float64.
+func fromJSONNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = fromJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = fromJSONNumbers(item)
+		}
+	}
+	return v
+}
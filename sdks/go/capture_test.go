@@ -0,0 +1,113 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrafficRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"columns":["n"],"rows":[[1]]}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	rec := NewTrafficRecorder(&buf, CaptureOptions{Redact: []string{"Password"}})
+	client, err := NewClientWithOptions(server.URL, WithMetrics(rec))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.ExecuteCypher(ctx, "MATCH (u:User {name: $name}) RETURN u", map[string]interface{}{
+		"name": "ann", "limit": int64(5), "ratio": 0.5,
+		"auth": map[string]interface{}{"password": "hunter2", "user": "ann"},
+	})
+	require.NoError(t, err)
+	_, err = (&Transaction{client: client, id: "tx"}).ExecuteCypher(ctx, "RETURN 1", nil)
+	require.NoError(t, err)
+	require.NoError(t, rec.Flush())
+
+	captured, err := ReadCapture(&buf)
+	require.NoError(t, err)
+	require.Len(t, captured, 2)
+	assert.Equal(t, "MATCH (u:User {name: $name}) RETURN u", captured[0].Query)
+	assert.Equal(t, map[string]interface{}{
+		"name": "ann", "limit": int64(5), "ratio": 0.5,
+		"auth": map[string]interface{}{"password": Redacted, "user": "ann"},
+	}, captured[0].Params)
+	assert.Equal(t, 1, captured[0].Rows)
+	assert.False(t, captured[0].Time.IsZero())
+	assert.True(t, captured[1].InTransaction)
+	assert.Nil(t, captured[1].Params)
+
+	sampled := NewTrafficRecorder(&buf, CaptureOptions{SampleRate: 0.000001})
+	buf.Reset()
+	for i := 0; i < 100; i++ {
+		sampled.ObserveQuery(ctx, QueryEvent{Query: "RETURN 1"})
+	}
+	require.NoError(t, sampled.Flush())
+	assert.Less(t, strings.Count(buf.String(), "\n"), 3)
+
+	_, err = ReadCapture(strings.NewReader("{\"query\":\"RETURN 1\"}\nnot json\n"))
+	assert.ErrorContains(t, err, "capture line 2")
+}
+
+func TestReplayTraffic(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		sent = append(sent, req.Query)
+		mu.Unlock()
+		if strings.Contains(req.Query, "missing") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"unknown function"}`))
+			return
+		}
+		w.Write([]byte(`{"columns":["n"],"rows":[[1],[2]]}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var capture bytes.Buffer
+	enc := json.NewEncoder(&capture)
+	for i, q := range []string{"MATCH (n) WHERE n.id = 1 RETURN n", "CREATE (n:X)", "MATCH (n) WHERE n.id = 2 RETURN n", "RETURN missing()"} {
+		enc.Encode(CapturedQuery{Time: base.Add(time.Duration(i) * 100 * time.Millisecond), Query: q})
+	}
+
+	var results int
+	start := time.Now()
+	report, err := ReplayTraffic(context.Background(), bytes.NewReader(capture.Bytes()), client, ReplayOptions{
+		Speed:       10,
+		Concurrency: 1,
+		ReadOnly:    true,
+		OnResult:    func(CapturedQuery, *QueryResult, error) { results++ },
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond, "300ms of capture at 10x")
+	assert.Equal(t, 3, report.Replayed)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 3, results)
+	assert.NotContains(t, sent, "CREATE (n:X)")
+	require.Len(t, report.Latencies, 2)
+	counts := map[string]int64{}
+	for _, s := range report.Latencies {
+		counts[s.Query] = s.Count
+	}
+	assert.Equal(t, map[string]int64{"MATCH (n) WHERE n.id = ? RETURN n": 2, "RETURN missing()": 1}, counts)
+}
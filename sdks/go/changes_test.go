@@ -0,0 +1,46 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/changes", r.URL.Path)
+		assert.Equal(t, "c1", r.URL.Query().Get("since"))
+		assert.Equal(t, "Person,Company", r.URL.Query().Get("labels"))
+		w.Write([]byte(`{"cursor":"c2","op":"create","entity":"node","id":"7","labels":["Person"],"after":{"name":"Ann"}}` + "\n"))
+		w.Write([]byte(`{"cursor":"c3","op":"delete","entity":"node","id":"8","labels":["Person"]}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	var events []ChangeEvent
+	cursor, err := client.Changes(context.Background(), ChangesOptions{Since: "c1", Labels: []string{"Person", "Company"}}, func(ev ChangeEvent) error {
+		events = append(events, ev)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "c3", cursor)
+	require.Len(t, events, 2)
+	assert.Equal(t, ChangeOpCreate, events[0].Op)
+	assert.Equal(t, "Ann", events[0].After["name"])
+	assert.Equal(t, "8", events[1].ID)
+}
+
+func TestChangesEmptyKeepsCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	cursor, err := client.Changes(context.Background(), ChangesOptions{Since: "c9"}, func(ChangeEvent) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "c9", cursor)
+}
@@ -0,0 +1,260 @@
+package nexus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCheckpointMismatch is returned when a resume token was taken from
+// a different query or parameter set.
+var ErrCheckpointMismatch = errors.New("nexus: checkpoint belongs to a different query")
+
+// QueryIteratorOptions configures IterateQuery.
+type QueryIteratorOptions struct {
+	// PageSize is the number of rows fetched per round trip (default
+	// 1000).
+	PageSize int
+	// Resume is a token from QueryIterator.Checkpoint; iteration
+	// continues with the row after the one it was taken at.
+	Resume string
+	// KeyColumn switches from SKIP paging to keyset paging: the query
+	// must ORDER BY this column and filter on the `$__after` parameter,
+	// which is null for the first page and the last key seen afterwards:
+	//
+	//	MATCH (n:Event) WHERE $__after IS NULL OR n.seq > $__after
+	//	RETURN n.seq AS seq, n.payload AS payload ORDER BY seq
+	//
+	// Keyset pages cost the same however far in the consumer is, where
+	// SKIP re-walks every skipped row.
+	KeyColumn string
+}
+
+// QueryIterator pages through a query's rows and can report a resumable
+// position after every row:
+//
+//	it, err := client.IterateQuery(ctx, query, nil, nexus.QueryIteratorOptions{Resume: saved})
+//	if err != nil { … }
+//	for it.Next() {
+//	    process(it.Row())
+//	    saved = it.Checkpoint()
+//	}
+//	if err := it.Err(); err != nil { … }
+//
+// The query should have a stable order (ORDER BY) or resumed runs may
+// skip or repeat rows. A QueryIterator is not safe for concurrent use.
+type QueryIterator struct {
+	ctx    context.Context
+	client *Client
+	query  string
+	params map[string]interface{}
+	opts   QueryIteratorOptions
+	fp     string
+
+	columns []string
+	keyCol  int
+	page    [][]interface{}
+	pos     int // index of the current row in page
+	offset  int // rows consumed before page
+	after   interface{}
+	done    bool
+	row     []interface{}
+	err     error
+
+	transformers []Transformer
+	pipeline     *transformPipeline
+}
+
+// checkpoint is the decoded form of a resume token.
+type checkpoint struct {
+	Version     int         `json:"v"`
+	Fingerprint string      `json:"fp"`
+	Offset      int         `json:"off"`
+	After       interface{} `json:"after,omitempty"`
+}
+
+// IterateQuery starts (or, with opts.Resume, resumes) a paged iteration
+// over query. No request is sent until the first Next.
+func (c *Client) IterateQuery(ctx context.Context, query string, params map[string]interface{}, opts QueryIteratorOptions) (*QueryIterator, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("nexus: query must not be empty")
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 1000
+	}
+	fp, err := queryFingerprint(query, params, opts.KeyColumn)
+	if err != nil {
+		return nil, err
+	}
+	it := &QueryIterator{
+		ctx:    ctx,
+		client: c,
+		query:  strings.TrimRight(strings.TrimSpace(query), ";"),
+		params: params,
+		opts:   opts,
+		fp:     fp,
+		keyCol: -1,
+	}
+	if opts.Resume != "" {
+		cp, err := decodeCheckpoint(opts.Resume)
+		if err != nil {
+			return nil, err
+		}
+		if cp.Fingerprint != fp {
+			return nil, ErrCheckpointMismatch
+		}
+		it.offset, it.after = cp.Offset, cp.After
+	}
+	return it, nil
+}
+
+// Next advances to the next row, fetching a page when needed. It
+// returns false at the end of the result or on error.
+func (it *QueryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.pos+1 >= len(it.page) {
+		if it.done {
+			it.offset += len(it.page)
+			it.page, it.pos, it.row = nil, 0, nil
+			return false
+		}
+		if !it.fetch() {
+			it.row = nil
+			return false
+		}
+	} else {
+		it.pos++
+	}
+	raw := it.page[it.pos]
+	if it.keyCol >= 0 && it.keyCol < len(raw) {
+		it.after = raw[it.keyCol]
+	}
+	it.row = raw
+	if it.pipeline != nil {
+		row, err := it.pipeline.row(raw)
+		if err != nil {
+			it.err = fmt.Errorf("%w (row %d)", err, it.offset+it.pos)
+			it.row = nil
+			return false
+		}
+		it.row = row
+	}
+	return true
+}
+
+// Transform applies transformers to every row the iterator yields and
+// returns the iterator. Call it before the first Next; the pipeline is
+// set up from the first page, and Columns reports the transformed
+// names. The key column is read from the untransformed row, so it may
+// be renamed or dropped.
+func (it *QueryIterator) Transform(transformers ...Transformer) *QueryIterator {
+	it.transformers = append(it.transformers, transformers...)
+	return it
+}
+
+func (it *QueryIterator) fetch() bool {
+	it.offset += len(it.page)
+	params := make(map[string]interface{}, len(it.params)+2)
+	for k, v := range it.params {
+		params[k] = v
+	}
+	params["__limit"] = it.opts.PageSize
+	query := it.query
+	if it.opts.KeyColumn != "" {
+		params["__after"] = it.after
+		query += " LIMIT $__limit"
+	} else {
+		params["__skip"] = it.offset
+		query += " SKIP $__skip LIMIT $__limit"
+	}
+
+	result, err := it.client.ExecuteCypher(it.ctx, query, params)
+	if err != nil {
+		it.err = fmt.Errorf("nexus: iterate page at row %d: %w", it.offset, err)
+		return false
+	}
+	if it.columns == nil {
+		it.columns = result.Columns
+		if it.opts.KeyColumn != "" {
+			for i, c := range result.Columns {
+				if c == it.opts.KeyColumn {
+					it.keyCol = i
+				}
+			}
+			if it.keyCol < 0 {
+				it.err = fmt.Errorf("nexus: key column %q is not returned by the query (columns %v)", it.opts.KeyColumn, result.Columns)
+				return false
+			}
+		}
+	}
+	if it.pipeline == nil && len(it.transformers) > 0 {
+		if it.pipeline, err = newTransformPipeline(it.columns, result.Rows, it.transformers); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.page, it.pos = result.Rows, 0
+	it.done = len(result.Rows) < it.opts.PageSize
+	return len(result.Rows) > 0
+}
+
+// Row returns the current row.
+func (it *QueryIterator) Row() []interface{} { return it.row }
+
+// Columns returns the column names, known after the first Next.
+func (it *QueryIterator) Columns() []string {
+	if it.pipeline != nil {
+		return it.pipeline.columns
+	}
+	return it.columns
+}
+
+// Checkpoint returns an opaque token for the position just after the
+// current row; pass it as QueryIteratorOptions.Resume to continue from
+// there. Before the first Next it resumes where this iterator started.
+func (it *QueryIterator) Checkpoint() string {
+	consumed := it.offset
+	if it.row != nil {
+		consumed += it.pos + 1
+	}
+	data, _ := json.Marshal(checkpoint{Version: 1, Fingerprint: it.fp, Offset: consumed, After: it.after})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Err returns the first error encountered while iterating.
+func (it *QueryIterator) Err() error { return it.err }
+
+func decodeCheckpoint(token string) (checkpoint, error) {
+	var cp checkpoint
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(data, &cp)
+	}
+	if err != nil || cp.Version != 1 {
+		return cp, errors.New("nexus: malformed checkpoint token")
+	}
+	return cp, nil
+}
+
+// queryFingerprint identifies the query a checkpoint was taken from.
+// encoding/json sorts map keys, so equal parameter maps hash equally.
+func queryFingerprint(query string, params map[string]interface{}, keyColumn string) (string, error) {
+	p, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("nexus: checkpoint parameters: %w", err)
+	}
+	h := sha256.New()
+	h.Write([]byte(strings.TrimRight(strings.TrimSpace(query), ";")))
+	h.Write([]byte{0})
+	h.Write(p)
+	h.Write([]byte{0})
+	h.Write([]byte(keyColumn))
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
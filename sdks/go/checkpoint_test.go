@@ -0,0 +1,106 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterateQueryResumesFromCheckpoint(t *testing.T) {
+	server := pagedServer(t, 7)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+	query := "MATCH (n) RETURN id(n) AS id, n ORDER BY id"
+	opts := QueryIteratorOptions{PageSize: 3}
+
+	it, err := client.IterateQuery(ctx, query, nil, opts)
+	require.NoError(t, err)
+	var seen []int
+	var saved string
+	for it.Next() && len(seen) < 4 {
+		seen = append(seen, asInt(it.Row()[0]))
+		saved = it.Checkpoint()
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []int{0, 1, 2, 3}, seen)
+
+	opts.Resume = saved
+	it, err = client.IterateQuery(ctx, query, nil, opts)
+	require.NoError(t, err)
+	for it.Next() {
+		seen = append(seen, asInt(it.Row()[0]))
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, seen)
+	assert.Equal(t, []string{"id", "props"}, it.Columns())
+
+	// A finished iterator's checkpoint resumes to an empty tail.
+	opts.Resume = it.Checkpoint()
+	it, err = client.IterateQuery(ctx, query, nil, opts)
+	require.NoError(t, err)
+	assert.False(t, it.Next())
+	require.NoError(t, it.Err())
+
+	_, err = client.IterateQuery(ctx, query, map[string]interface{}{"x": 1}, opts)
+	assert.ErrorIs(t, err, ErrCheckpointMismatch)
+	opts.Resume = "garbage"
+	_, err = client.IterateQuery(ctx, query, nil, opts)
+	assert.ErrorContains(t, err, "malformed")
+}
+
+func TestIterateQueryKeyset(t *testing.T) {
+	var afters []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string                 `json:"query"`
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, strings.HasSuffix(req.Query, "ORDER BY seq LIMIT $__limit"))
+		after := req.Parameters["__after"]
+		afters = append(afters, after)
+		start := 0
+		if after != nil {
+			start = int(after.(float64)) + 1
+		}
+		var rows [][]interface{}
+		for i := start; i < 5 && i < start+2; i++ {
+			rows = append(rows, []interface{}{"e", i})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"payload", "seq"}, "rows": rows})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	query := "MATCH (n:Event) WHERE $__after IS NULL OR n.seq > $__after RETURN n.payload AS payload, n.seq AS seq ORDER BY seq"
+	opts := QueryIteratorOptions{PageSize: 2, KeyColumn: "seq"}
+
+	it, err := client.IterateQuery(context.Background(), query, nil, opts)
+	require.NoError(t, err)
+	require.True(t, it.Next())
+	require.True(t, it.Next())
+	require.True(t, it.Next())
+	opts.Resume = it.Checkpoint()
+
+	it, err = client.IterateQuery(context.Background(), query, nil, opts)
+	require.NoError(t, err)
+	var seqs []int
+	for it.Next() {
+		seqs = append(seqs, asInt(it.Row()[1]))
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []int{3, 4}, seqs)
+	assert.Equal(t, []interface{}{nil, float64(1), float64(2), float64(4)}, afters)
+
+	opts.Resume, opts.KeyColumn = "", "missing"
+	it, err = client.IterateQuery(context.Background(), query, nil, opts)
+	require.NoError(t, err)
+	assert.False(t, it.Next())
+	assert.ErrorContains(t, it.Err(), "key column")
+}
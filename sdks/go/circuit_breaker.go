@@ -0,0 +1,232 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, without contacting the server, by requests
+// made while the client's circuit breaker is open.
+var ErrCircuitOpen = errors.New("nexus: circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+// Circuit states.
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a few probe requests through to find out
+	// whether the server has recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// CircuitBreakerConfig configures the client-side circuit breaker. It
+// sits under every request the client sends, retries included: after
+// FailureThreshold consecutive failures the circuit opens and requests
+// fail fast with ErrCircuitOpen for OpenDuration, then up to
+// HalfOpenProbes requests probe the server. The circuit closes once they
+// all succeed and reopens as soon as one fails.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens
+	// the circuit (default: 5)
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open before probing
+	// (default: 30s)
+	OpenDuration time.Duration
+	// HalfOpenProbes is the number of requests let through while half
+	// open (default: 1)
+	HalfOpenProbes int
+	// IsFailure reports whether an error counts against the server
+	// (default: IsServerFailure)
+	IsFailure func(error) bool
+	// OnStateChange, when set, is called after every state change (for
+	// alerting). It runs on the goroutine of the request that caused the
+	// change and must not block.
+	OnStateChange func(CircuitStateChange)
+}
+
+// CircuitStateChange describes a circuit breaker state change.
+type CircuitStateChange struct {
+	From CircuitState
+	To   CircuitState
+	// Err is the failure that opened the circuit; nil on other changes
+	Err error
+}
+
+// IsServerFailure reports whether err means the server is unhealthy: a
+// 5xx, 408 or 429 response, or a request that got no response at all.
+// Other 4xx responses are the caller's fault and a cancelled context is
+// the caller's choice; neither counts.
+func IsServerFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 ||
+			apiErr.StatusCode == http.StatusRequestTimeout ||
+			apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// circuitBreaker implements CircuitBreakerConfig. A nil *circuitBreaker
+// lets everything through.
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int       // consecutive failures while closed
+	openedAt time.Time // when the circuit last opened
+	probes   int       // probes in flight while half open
+	passed   int       // probes succeeded while half open
+}
+
+func newCircuitBreaker(cfg *CircuitBreakerConfig) *circuitBreaker {
+	if cfg == nil {
+		return nil
+	}
+	b := &circuitBreaker{cfg: *cfg, now: time.Now}
+	if b.cfg.FailureThreshold <= 0 {
+		b.cfg.FailureThreshold = 5
+	}
+	if b.cfg.OpenDuration <= 0 {
+		b.cfg.OpenDuration = 30 * time.Second
+	}
+	if b.cfg.HalfOpenProbes <= 0 {
+		b.cfg.HalfOpenProbes = 1
+	}
+	if b.cfg.IsFailure == nil {
+		b.cfg.IsFailure = IsServerFailure
+	}
+	return b
+}
+
+// current returns the state, moving an open circuit whose OpenDuration
+// has passed to half open.
+func (b *circuitBreaker) current() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	change := b.expire()
+	state := b.state
+	b.mu.Unlock()
+	b.notify(change)
+	return state
+}
+
+// allow reports whether a request may be sent, failing with
+// ErrCircuitOpen when not. Every allowed request must be followed by
+// exactly one call to done.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	change := b.expire()
+	var err error
+	switch b.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.probes+b.passed >= b.cfg.HalfOpenProbes {
+			err = ErrCircuitOpen
+		} else {
+			b.probes++
+		}
+	}
+	b.mu.Unlock()
+	b.notify(change)
+	return err
+}
+
+// done records the outcome of a request allow let through.
+func (b *circuitBreaker) done(err error) {
+	if b == nil {
+		return
+	}
+	failed := err != nil && b.cfg.IsFailure(err)
+	// A request the caller cancelled says nothing about the server.
+	cancelled := !failed && errors.Is(err, context.Canceled)
+	b.mu.Lock()
+	var change *CircuitStateChange
+	switch b.state {
+	case CircuitClosed:
+		switch {
+		case failed:
+			if b.failures++; b.failures >= b.cfg.FailureThreshold {
+				change = b.open(err)
+			}
+		case !cancelled:
+			b.failures = 0
+		}
+	case CircuitHalfOpen:
+		if b.probes > 0 {
+			b.probes--
+		}
+		switch {
+		case failed:
+			change = b.open(err)
+		case !cancelled:
+			if b.passed++; b.passed >= b.cfg.HalfOpenProbes {
+				change = b.set(CircuitClosed, nil)
+			}
+		}
+	}
+	b.mu.Unlock()
+	b.notify(change)
+}
+
+// expire moves an open circuit to half open once OpenDuration has
+// passed. Called with mu held.
+func (b *circuitBreaker) expire() *CircuitStateChange {
+	if b.state != CircuitOpen || b.now().Sub(b.openedAt) < b.cfg.OpenDuration {
+		return nil
+	}
+	return b.set(CircuitHalfOpen, nil)
+}
+
+// open opens the circuit. Called with mu held.
+func (b *circuitBreaker) open(err error) *CircuitStateChange {
+	b.openedAt = b.now()
+	return b.set(CircuitOpen, err)
+}
+
+// set changes state, resetting the counters. Called with mu held.
+func (b *circuitBreaker) set(to CircuitState, err error) *CircuitStateChange {
+	change := &CircuitStateChange{From: b.state, To: to, Err: err}
+	b.state = to
+	b.failures, b.probes, b.passed = 0, 0, 0
+	return change
+}
+
+func (b *circuitBreaker) notify(change *CircuitStateChange) {
+	if change != nil && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(*change)
+	}
+}
+
+// CircuitState returns the state of the client's circuit breaker;
+// CircuitClosed when Config.CircuitBreaker is not set.
+func (c *Client) CircuitState() CircuitState {
+	return c.breaker.current()
+}
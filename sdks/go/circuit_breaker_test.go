@@ -0,0 +1,114 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch {
+		case r.URL.Path == "/nodes/404":
+			w.WriteHeader(http.StatusNotFound)
+		case !healthy.Load():
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"columns":[],"rows":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	var changes []CircuitStateChange
+	client := NewClient(Config{BaseURL: server.URL, CircuitBreaker: &CircuitBreakerConfig{
+		FailureThreshold: 3,
+		OpenDuration:     time.Minute,
+		OnStateChange:    func(c CircuitStateChange) { changes = append(changes, c) },
+	}})
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Client errors do not count and reset the run of failures.
+	for i := 0; i < 2; i++ {
+		_, err := client.ExecuteCypher(ctx, "RETURN 1", nil)
+		require.Error(t, err)
+	}
+	_, err := client.GetNode(ctx, "404")
+	require.Error(t, err)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+
+	for i := 0; i < 3; i++ {
+		_, err := client.ExecuteCypher(ctx, "RETURN 1", nil)
+		require.Error(t, err)
+	}
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+	require.Len(t, changes, 1)
+	assert.Equal(t, CircuitOpen, changes[0].To)
+	var apiErr *Error
+	require.ErrorAs(t, changes[0].Err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+
+	// Open: every method fails fast, retries included.
+	sent := hits.Load()
+	_, err = client.ExecuteCypher(ctx, "RETURN 1", nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	_, err = client.WithRetry(fastRetry()).GetNode(ctx, "1")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, sent, hits.Load())
+
+	// Half open: a failed probe reopens the circuit...
+	now = now.Add(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, client.CircuitState())
+	_, err = client.ExecuteCypher(ctx, "RETURN 1", nil)
+	require.Error(t, err)
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+
+	// ...and a successful one closes it.
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+	_, err = client.ExecuteCypher(ctx, "RETURN 1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+
+	var states []CircuitState
+	for _, c := range changes {
+		states = append(states, c.To)
+	}
+	assert.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}, states)
+}
+
+func TestCircuitBreakerHalfOpenProbes(t *testing.T) {
+	b := newCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Second, HalfOpenProbes: 2})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	require.NoError(t, b.allow())
+	b.done(errors.New("connection refused"))
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	now = now.Add(time.Second)
+	require.NoError(t, b.allow())
+	require.NoError(t, b.allow())
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen, "only HalfOpenProbes requests probe")
+	b.done(context.Canceled)
+	require.NoError(t, b.allow(), "a cancelled probe frees its slot")
+	b.done(nil)
+	assert.Equal(t, CircuitHalfOpen, b.current())
+	b.done(nil)
+	assert.Equal(t, CircuitClosed, b.current())
+
+	assert.False(t, IsServerFailure(&Error{StatusCode: http.StatusBadRequest}))
+	assert.True(t, IsServerFailure(&Error{StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, IsServerFailure(context.DeadlineExceeded))
+}
@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hivellm/nexus-go/transport"
@@ -35,10 +37,51 @@ type Client struct {
 	username   string
 	password   string
 	token      string
+	// credentials replaces apiKey/username/password when set.
+	credentials CredentialProvider
 
 	transport transport.Transport
 	endpoint  transport.Endpoint
 	mode      transport.Mode
+
+	escalate   map[NotificationCategory]bool
+	policy     QueryPolicy
+	authorizer Authorizer
+	queryList  *QueryList
+	metrics    MetricsHook
+	adaptive   *AdaptiveTimeout
+	dryRun     bool
+
+	// retry, when set, makes every HTTP request retry; see RetryConfig.
+	retry *RetryConfig
+	// breaker, when set, guards every request; see CircuitBreakerConfig.
+	breaker *circuitBreaker
+	// stale, when set, backs reads that fail; see StaleReadConfig.
+	stale *staleCache
+	// quota, when set, budgets statements; see QuotaConfig.
+	quota *quota
+
+	// Clients derived with WithRetry share the state of the client they
+	// came from.
+	*clientState
+}
+
+// clientState is the mutable state of a Client.
+type clientState struct {
+	// signatures caches ProcedureSignature by name for Call.
+	signatures sync.Map
+	// schema is Config.Schema, or the one RegisterJSONSchema created;
+	// nil when node writes are not validated.
+	schema atomic.Pointer[Schema]
+	// noTxRun is set once the server rejected POST /transaction/run.
+	noTxRun atomic.Bool
+	// inFlight counts requests awaiting their response; see
+	// ReadinessHandler.
+	inFlight atomic.Int64
+
+	// Lifecycle: see Close.
+	closed atomic.Bool
+	openTx sync.Map // transaction ID → *Transaction
 }
 
 // Config holds configuration options for the Nexus client.
@@ -61,6 +104,68 @@ type Config struct {
 	RpcPort uint16
 	// Resp3Port overrides the default RESP3 port (15476).
 	Resp3Port uint16
+	// Schema, when set, validates node writes client-side before they
+	// are sent. See Schema.
+	Schema *Schema
+	// EscalateNotifications turns server notifications in these
+	// categories into a *NotificationError from ExecuteCypher.
+	EscalateNotifications []NotificationCategory
+	// Signer, when set, signs every HTTP request (AWS SigV4, GCP IAP,
+	// bearer tokens for OIDC gateways). HTTP transport only.
+	Signer RequestSigner
+	// Credentials, when set, supplies the API key or username/password
+	// from a secret store instead of APIKey/Username/Password. It is
+	// consulted per HTTP request and per RPC connect, through a
+	// CredentialCache.
+	Credentials CredentialProvider
+	// PinnedCertFingerprints, when set, only accepts servers whose
+	// certificate chain contains one of these SHA-256 fingerprints (see
+	// PinnedCertVerifier). Requires an https:// BaseURL.
+	PinnedCertFingerprints []string
+	// TLS, when set, configures https:// connections: extra trusted CAs,
+	// a client certificate for mTLS, or skipped verification. See
+	// TLSConfig.
+	TLS *TLSConfig
+	// QueryPolicy, when set, vets or rewrites every Cypher query before
+	// it is sent, e.g. Guardrails for user- or LLM-written queries.
+	QueryPolicy QueryPolicy
+	// Authorizer, when set, is asked to approve every Cypher statement
+	// and entity REST call before it is sent. See Access.
+	Authorizer Authorizer
+	// QueryList, when set, restricts the client to (allow-list) or
+	// away from (deny-list) registered queries. It is checked before
+	// QueryPolicy.
+	QueryList *QueryList
+	// HTTPClient, when set, is the base for HTTP requests: its
+	// Transport (proxies, custom dialers, test doubles), Jar and
+	// CheckRedirect are used, and its Timeout when Timeout is zero.
+	// Ignored by RPC.
+	HTTPClient *http.Client
+	// Headers are added to every HTTP request that does not set them
+	// itself. Ignored by RPC.
+	Headers http.Header
+	// Metrics, when set, observes every Cypher statement the client
+	// runs, e.g. a QueryAggregator. See MetricsHook.
+	Metrics MetricsHook
+	// AdaptiveTimeout, when set, bounds each Cypher statement by a
+	// deadline learned from earlier runs of the same query shape. See
+	// AdaptiveTimeout.
+	AdaptiveTimeout *AdaptiveTimeout
+	// DryRun rehearses writes instead of applying them; see WithDryRun,
+	// which also overrides it per call.
+	DryRun bool
+	// Retry, when set, retries every HTTP request (and RPC reads) that
+	// fails with a retryable error; see RetryConfig.
+	Retry *RetryConfig
+	// CircuitBreaker, when set, stops sending requests to a server that
+	// keeps failing; see CircuitBreakerConfig.
+	CircuitBreaker *CircuitBreakerConfig
+	// StaleReads, when set, serves GetNode and RunSavedQuery from their
+	// last results while the server cannot answer; see StaleReadConfig.
+	StaleReads *StaleReadConfig
+	// Quota, when set, limits the statements, rows and bytes the client
+	// uses per time window; see QuotaConfig.
+	Quota *QuotaConfig
 }
 
 // NewClient creates a new Nexus client with the given configuration.
@@ -80,16 +185,54 @@ func NewClient(config Config) *Client {
 // NewClientE is the error-returning constructor. Prefer this over
 // NewClient for new code.
 func NewClientE(config Config) (*Client, error) {
+	if config.Timeout == 0 && config.HTTPClient != nil {
+		config.Timeout = config.HTTPClient.Timeout
+	}
 	if config.Timeout == 0 {
 		config.Timeout = 30 * time.Second
 	}
 
+	var roundTripper http.RoundTripper
+	if config.HTTPClient != nil {
+		roundTripper = config.HTTPClient.Transport
+	}
+	if config.TLS != nil || len(config.PinnedCertFingerprints) > 0 {
+		if roundTripper != nil {
+			return nil, errors.New("nexus: invalid configuration: Config.TLS and Config.PinnedCertFingerprints cannot be combined with an HTTPClient transport")
+		}
+		secured, err := httpsTransport(config.TLS, config.PinnedCertFingerprints)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: invalid configuration: %w", err)
+		}
+		roundTripper = secured
+	}
+	if config.Signer != nil {
+		roundTripper = NewSigningRoundTripper(config.Signer, roundTripper)
+	}
+	if len(config.Headers) > 0 {
+		// Outside the signer, so signatures cover the headers.
+		roundTripper = &headerRoundTripper{headers: config.Headers.Clone(), next: roundTripper}
+	}
+	quota := newQuota(config.Quota)
+	if quota != nil {
+		roundTripper = &quotaRoundTripper{quota: quota, next: roundTripper}
+	}
+	var credsSource transport.CredentialsSource
+	if config.Credentials != nil {
+		if _, ok := config.Credentials.(*CredentialCache); !ok {
+			config.Credentials = NewCredentialCache(config.Credentials, CredentialCacheOptions{})
+		}
+		credsSource = credentialsSource(config.Credentials)
+	}
+
 	built, err := transport.Build(transport.BuildOptions{
-		BaseURL:   config.BaseURL,
-		Transport: config.Transport,
-		RpcPort:   config.RpcPort,
-		Resp3Port: config.Resp3Port,
-		Timeout:   config.Timeout,
+		BaseURL:           config.BaseURL,
+		Transport:         config.Transport,
+		RpcPort:           config.RpcPort,
+		Resp3Port:         config.Resp3Port,
+		Timeout:           config.Timeout,
+		RoundTripper:      roundTripper,
+		CredentialsSource: credsSource,
 	}, transport.Credentials{
 		APIKey:   config.APIKey,
 		Username: config.Username,
@@ -98,18 +241,68 @@ func NewClientE(config Config) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("nexus: invalid configuration: %w", err)
 	}
+	if config.Signer != nil && built.Transport.IsRpc() {
+		built.Transport.Close()
+		return nil, errors.New("nexus: invalid configuration: Config.Signer requires the HTTP transport")
+	}
+	if len(config.PinnedCertFingerprints) > 0 && built.Mode != transport.ModeHttps {
+		built.Transport.Close()
+		return nil, errors.New("nexus: invalid configuration: Config.PinnedCertFingerprints requires an https:// endpoint")
+	}
+	if config.TLS != nil && built.Mode != transport.ModeHttps {
+		built.Transport.Close()
+		return nil, errors.New("nexus: invalid configuration: Config.TLS requires an https:// endpoint")
+	}
+
+	policy := config.QueryPolicy
+	if config.QueryList != nil {
+		policy = config.QueryList
+		if config.QueryPolicy != nil {
+			policy = ChainPolicies(config.QueryList, config.QueryPolicy)
+		}
+	}
+
+	var escalate map[NotificationCategory]bool
+	if len(config.EscalateNotifications) > 0 {
+		escalate = make(map[NotificationCategory]bool, len(config.EscalateNotifications))
+		for _, cat := range config.EscalateNotifications {
+			escalate[cat] = true
+		}
+	}
 
+	httpClient := &http.Client{
+		Timeout:   config.Timeout,
+		Transport: roundTripper,
+	}
+	if config.HTTPClient != nil {
+		httpClient.Jar = config.HTTPClient.Jar
+		httpClient.CheckRedirect = config.HTTPClient.CheckRedirect
+	}
+
+	state := &clientState{}
+	state.schema.Store(config.Schema)
 	return &Client{
-		baseURL: built.Endpoint.AsHttpURL(),
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
-		apiKey:    config.APIKey,
-		username:  config.Username,
-		password:  config.Password,
-		transport: built.Transport,
-		endpoint:  built.Endpoint,
-		mode:      built.Mode,
+		baseURL:     built.Endpoint.AsHttpURL(),
+		httpClient:  httpClient,
+		apiKey:      config.APIKey,
+		username:    config.Username,
+		password:    config.Password,
+		credentials: config.Credentials,
+		transport:   built.Transport,
+		endpoint:    built.Endpoint,
+		mode:        built.Mode,
+		escalate:    escalate,
+		policy:      policy,
+		authorizer:  config.Authorizer,
+		queryList:   config.QueryList,
+		metrics:     config.Metrics,
+		adaptive:    config.AdaptiveTimeout,
+		dryRun:      config.DryRun,
+		retry:       config.Retry,
+		breaker:     newCircuitBreaker(config.CircuitBreaker),
+		stale:       newStaleCache(config.StaleReads),
+		quota:       quota,
+		clientState: state,
 	}, nil
 }
 
@@ -121,13 +314,38 @@ func (c *Client) TransportMode() transport.Mode { return c.mode }
 // label (e.g. "nexus://127.0.0.1:15475 (RPC)").
 func (c *Client) EndpointDescription() string { return c.transport.Describe() }
 
-// Close releases the underlying transport's persistent sockets.
+// ErrClientClosed is returned by requests made after Client.Close.
+var ErrClientClosed = errors.New("nexus: client is closed")
+
+// Close shuts the client down: it rolls back transactions begun through
+// the client that were never committed or rolled back, drains idle HTTP
+// connections and closes the transport's persistent sockets. Requests made afterwards
+// fail with ErrClientClosed. Close is idempotent; only the first call
+// does any work.
 func (c *Client) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+
+	var errs []error
+	c.openTx.Range(func(key, value interface{}) bool {
+		tx := value.(*Transaction)
+		ctx, cancel := context.WithTimeout(context.Background(), c.httpClient.Timeout)
+		if err := tx.rollback(ctx, c.send); err != nil {
+			errs = append(errs, fmt.Errorf("nexus: roll back orphaned transaction %s: %w", tx.id, err))
+		}
+		cancel()
+		c.openTx.Delete(key)
+		return true
+	})
+
 	c.httpClient.CloseIdleConnections()
 	if c.transport != nil {
-		return c.transport.Close()
+		if err := c.transport.Close(); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // QueryResult represents the result of a Cypher query.
@@ -135,9 +353,23 @@ type QueryResult struct {
 	Columns []string        `json:"columns"`
 	Rows    [][]interface{} `json:"rows"`
 	Stats   *QueryStats     `json:"stats,omitempty"`
+	// ColumnTypes describes each column (node, relationship, path, scalar
+	// type or list), index-aligned with Columns. The server's own types
+	// are used when it sends them; otherwise they are inferred from the
+	// rows (see InferColumnTypes).
+	ColumnTypes []ColumnType `json:"column_types,omitempty"`
+	// Notifications carries server warnings such as deprecated syntax,
+	// unusable index hints or cartesian products.
+	Notifications []Notification `json:"notifications,omitempty"`
+	// Staleness is set when the result was served from the client-side
+	// cache instead of the server; see Config.StaleReads.
+	Staleness *Staleness `json:"-"`
+
+	pooled *transport.CypherResponse // row storage Release recycles
 }
 
 // RowsAsMap converts the array-based rows to map-based rows using column names as keys.
+// When column names repeat the last one wins; see RowsAsMapWith to keep them all.
 func (qr *QueryResult) RowsAsMap() []map[string]interface{} {
 	result := make([]map[string]interface{}, len(qr.Rows))
 	for i, row := range qr.Rows {
@@ -160,6 +392,17 @@ type QueryStats struct {
 	RelationshipsDeleted int     `json:"relationships_deleted"`
 	PropertiesSet        int     `json:"properties_set"`
 	ExecutionTimeMs      float64 `json:"execution_time_ms"`
+	LabelsAdded          int     `json:"labels_added"`
+	LabelsRemoved        int     `json:"labels_removed"`
+	// IndexesUsed names the indexes the planner chose.
+	IndexesUsed []string `json:"indexes_used,omitempty"`
+	RowsScanned int64    `json:"rows_scanned"`
+	DbHits      int64    `json:"db_hits"`
+	// PeakMemoryBytes is the query's memory high-water mark.
+	PeakMemoryBytes int64 `json:"peak_memory_bytes"`
+	// CacheHit reports whether the result was served from the server's
+	// result cache.
+	CacheHit bool `json:"cache_hit"`
 }
 
 // Node represents a graph node.
@@ -167,6 +410,9 @@ type Node struct {
 	ID         string                 `json:"id"`
 	Labels     []string               `json:"labels"`
 	Properties map[string]interface{} `json:"properties"`
+	// Staleness is set when the node was served from the client-side
+	// cache instead of the server; see Config.StaleReads.
+	Staleness *Staleness `json:"-"`
 }
 
 // Relationship represents a graph relationship.
@@ -188,15 +434,46 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("nexus: HTTP %d: %s", e.StatusCode, e.Message)
 }
 
-// doRequest performs an HTTP request with authentication.
+// doRequest performs an HTTP request with authentication, failing with
+// ErrClientClosed once the client is closed.
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	if c.retry != nil {
+		return c.sendWithRetry(ctx, method, path, body)
+	}
+	return c.send(ctx, method, path, body)
+}
+
+// doStream is doRequest for long-lived responses (server-sent events,
+// tails): Config.Timeout does not apply, only ctx bounds the request.
+func (c *Client) doStream(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	hc := *c.httpClient
+	hc.Timeout = 0
+	return c.sendVia(ctx, &hc, method, path, body)
+}
+
+// send performs an HTTP request with authentication.
+func (c *Client) send(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.sendVia(ctx, c.httpClient, method, path, body)
+}
+
+func (c *Client) sendVia(ctx context.Context, hc *http.Client, method, path string, body interface{}) (*http.Response, error) {
+	rb, err := requestBody(body)
+	if err != nil {
+		return nil, err
+	}
 	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
+	if rb != nil {
+		opened, err := rb.Open()
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, err
 		}
-		reqBody = bytes.NewReader(jsonData)
+		reqBody = opened
 	}
 
 	// Split the optional query string off the path before JoinPath
@@ -223,27 +500,59 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if rb != nil {
+		// GetBody lets net/http replay the body on redirects and
+		// HTTP/2 connection retries.
+		req.GetBody = rb.Open
+		if n := rb.Len(); n >= 0 {
+			req.ContentLength = n
+		}
+		if ct := rb.ContentType(); ct != "" {
+			req.Header.Set("Content-Type", ct)
+		}
+	}
 
 	// Add authentication
-	if c.apiKey != "" {
+	if c.credentials != nil {
+		creds, err := c.credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve credentials: %w", err)
+		}
+		if creds.APIKey != "" {
+			req.Header.Set("X-API-Key", creds.APIKey)
+		} else {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		}
+	} else if c.apiKey != "" {
 		req.Header.Set("X-API-Key", c.apiKey)
 	} else if c.token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
+	transport.ApplyHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	c.inFlight.Add(1)
+	resp, err := hc.Do(req)
+	c.inFlight.Add(-1)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		err = fmt.Errorf("request failed: %w", err)
+		c.breaker.done(err)
+		return nil, err
 	}
 
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
+		apiErr := &Error{
 			StatusCode: resp.StatusCode,
 			Message:    string(bodyBytes),
 		}
+		c.breaker.done(apiErr)
+		return nil, apiErr
 	}
+	c.breaker.done(nil)
 
 	return resp, nil
 }
@@ -254,45 +563,123 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 // socket using length-prefixed MessagePack frames. When the transport
 // is HTTP it hits the `/cypher` REST route. Both paths return the same
 // QueryResult shape.
+//
+// Request-scoped options that travel as HTTP headers (WithCacheControl
+// and friends) cannot ride an RPC frame; such calls go over the HTTP
+// route instead.
 func (c *Client) ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
-	args := []transport.NexusValue{transport.NxStr(query)}
-	if params != nil {
-		args = append(args, transport.JsonToNexus(params))
+	if c.closed.Load() {
+		return nil, ErrClientClosed
 	}
-	resp, err := c.transport.Execute(ctx, transport.Request{Command: "CYPHER", Args: args})
+	if c.transport.IsRpc() && transport.HasHeaders(ctx) {
+		return c.ExecuteCypherHTTP(ctx, query, params)
+	}
+	if c.retry != nil && AnalyzeQuery(query).Operation != OperationRead {
+		// Writes are retried over HTTP, which only replays them when
+		// the failure shows they were not applied.
+		return c.ExecuteCypherHTTP(ctx, query, params)
+	}
+	query, err := c.checkQuery(ctx, query, params)
 	if err != nil {
-		return nil, translateTransportError(err)
+		return nil, err
 	}
-	json := transport.NexusToJson(resp.Value)
-	obj, ok := json.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("nexus: CYPHER: expected object response, got %T", json)
+	ctx, finish := c.startQuery(ctx, query, params, false)
+	result, err := c.dryRunOr(ctx, query, params, c.executeCypherRetrying)
+	return result, finish(result, err)
+}
+
+// executeCypherRetrying is executeCypher, retried as Config.Retry
+// allows. Only reads get here when retries are on.
+func (c *Client) executeCypherRetrying(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
+	if c.retry == nil {
+		return c.executeCypher(ctx, query, params)
 	}
-	result := &QueryResult{}
-	if cols, ok := obj["columns"].([]interface{}); ok {
-		result.Columns = make([]string, len(cols))
-		for i, c := range cols {
-			result.Columns[i] = fmt.Sprint(c)
-		}
+	var result *QueryResult
+	err := c.retry.do(ctx, c.retry.isRetryableError, func() error {
+		var err error
+		result, err = c.executeCypher(ctx, query, params)
+		return err
+	})
+	return result, err
+}
+
+// executeCypher runs an already vetted query on the active transport.
+func (c *Client) executeCypher(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
+	resp := getCypherResponse()
+	if err := c.executeCypherInto(ctx, query, params, resp); err != nil {
+		putCypherResponse(resp)
+		return nil, err
 	}
-	if rows, ok := obj["rows"].([]interface{}); ok {
-		result.Rows = make([][]interface{}, len(rows))
-		for i, r := range rows {
-			if rr, ok := r.([]interface{}); ok {
-				result.Rows[i] = rr
-			}
+	result := &QueryResult{Rows: resp.Rows, pooled: resp}
+	if resp.Columns != nil {
+		result.Columns = make([]string, len(resp.Columns))
+		for i, c := range resp.Columns {
+			result.Columns[i] = fmt.Sprint(c)
 		}
 	}
-	if statsRaw, ok := obj["stats"].(map[string]interface{}); ok {
-		result.Stats = decodeStats(statsRaw)
+	if resp.Stats != nil {
+		result.Stats = decodeStats(resp.Stats)
 	}
-	if etMs, ok := obj["execution_time_ms"]; ok {
+	if resp.ExecutionTimeMs != nil {
 		if result.Stats == nil {
 			result.Stats = &QueryStats{}
 		}
-		result.Stats.ExecutionTimeMs = asFloat(etMs)
+		result.Stats.ExecutionTimeMs = *resp.ExecutionTimeMs
 	}
-	return result, nil
+	if resp.Notifications != nil {
+		result.Notifications = decodeNotifications(resp.Notifications)
+	}
+	if resp.ColumnTypes != nil {
+		result.ColumnTypes = make([]ColumnType, len(resp.ColumnTypes))
+		for i, t := range resp.ColumnTypes {
+			result.ColumnTypes[i] = ColumnType(fmt.Sprint(t))
+		}
+	}
+	result.fillColumnTypes()
+	return result, c.checkNotifications(result)
+}
+
+// executeCypherInto runs CYPHER on the active transport and decodes the
+// response into dst, skipping the NexusValue round trip when the
+// transport can.
+func (c *Client) executeCypherInto(ctx context.Context, query string, params map[string]interface{}, dst *transport.CypherResponse) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	c.inFlight.Add(1)
+	err := c.executeCypherTransport(ctx, query, params, dst)
+	c.inFlight.Add(-1)
+	c.breaker.done(err)
+	return err
+}
+
+func (c *Client) executeCypherTransport(ctx context.Context, query string, params map[string]interface{}, dst *transport.CypherResponse) error {
+	if direct, ok := c.transport.(transport.CypherDecoder); ok {
+		return translateTransportError(direct.CypherInto(ctx, query, params, dst))
+	}
+	v, err := c.executeCypherJSON(ctx, query, params)
+	if err != nil {
+		return err
+	}
+	return fillCypherResponse(v, dst)
+}
+
+// executeCypherJSON returns the CYPHER response as plain Go values,
+// for transports without CypherInto.
+func (c *Client) executeCypherJSON(ctx context.Context, query string, params map[string]interface{}) (interface{}, error) {
+	if direct, ok := c.transport.(transport.CypherJSONer); ok {
+		v, err := direct.CypherJSON(ctx, query, params)
+		return v, translateTransportError(err)
+	}
+	args := []transport.NexusValue{transport.NxStr(query)}
+	if params != nil {
+		args = append(args, transport.JsonToNexus(params))
+	}
+	resp, err := c.transport.Execute(ctx, transport.Request{Command: "CYPHER", Args: args})
+	if err != nil {
+		return nil, translateTransportError(err)
+	}
+	return transport.NexusToJson(resp.Value), nil
 }
 
 func decodeStats(m map[string]interface{}) *QueryStats {
@@ -303,6 +690,18 @@ func decodeStats(m map[string]interface{}) *QueryStats {
 	s.RelationshipsDeleted = asInt(m["relationships_deleted"])
 	s.PropertiesSet = asInt(m["properties_set"])
 	s.ExecutionTimeMs = asFloat(m["execution_time_ms"])
+	s.LabelsAdded = asInt(m["labels_added"])
+	s.LabelsRemoved = asInt(m["labels_removed"])
+	if used, ok := m["indexes_used"].([]interface{}); ok {
+		s.IndexesUsed = make([]string, len(used))
+		for i, name := range used {
+			s.IndexesUsed[i] = fmt.Sprint(name)
+		}
+	}
+	s.RowsScanned = int64(asInt(m["rows_scanned"]))
+	s.DbHits = int64(asInt(m["db_hits"]))
+	s.PeakMemoryBytes = int64(asInt(m["peak_memory_bytes"]))
+	s.CacheHit, _ = m["cache_hit"].(bool)
 	return s
 }
 
@@ -352,11 +751,23 @@ func translateTransportError(err error) error {
 // that inspects the `execution_time_ms` field surfaced only by the
 // JSON endpoint). Prefer ExecuteCypher — it works on both transports.
 func (c *Client) ExecuteCypherHTTP(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
-	reqBody := map[string]interface{}{"query": query}
-	if params != nil {
-		reqBody["parameters"] = params
+	query, err := c.checkQuery(ctx, query, params)
+	if err != nil {
+		return nil, err
 	}
-	resp, err := c.doRequest(ctx, http.MethodPost, "/cypher", reqBody)
+	ctx, finish := c.startQuery(ctx, query, params, false)
+	result, err := c.dryRunOr(ctx, query, params, c.executeCypherHTTP)
+	return result, finish(result, err)
+}
+
+func (c *Client) executeCypherHTTP(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
+	return c.postCypher(ctx, cypherRequest{Query: query, Parameters: params})
+}
+
+// postCypher sends a vetted request to POST /cypher and decodes the
+// result, as every HTTP Cypher read does.
+func (c *Client) postCypher(ctx context.Context, req cypherRequest) (*QueryResult, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/cypher", req)
 	if err != nil {
 		return nil, err
 	}
@@ -365,7 +776,18 @@ func (c *Client) ExecuteCypherHTTP(ctx context.Context, query string, params map
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	return &result, nil
+	result.fillColumnTypes()
+	return &result, c.checkNotifications(&result)
+}
+
+// cypherRequest is the body of POST /cypher.
+type cypherRequest struct {
+	Query      string                 `json:"query"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	// AsOf and Snapshot select past or snapshotted graph state; see
+	// ExecuteCypherAsOf and ExecuteCypherOnSnapshot.
+	AsOf     string `json:"as_of,omitempty"`
+	Snapshot string `json:"snapshot,omitempty"`
 }
 
 // CreateNodeRequest holds the body for the POST /data/nodes endpoint.
@@ -407,6 +829,12 @@ type GetNodeByExternalIDResponse struct {
 
 // CreateNode creates a new node with the given labels and properties.
 func (c *Client) CreateNode(ctx context.Context, labels []string, properties map[string]interface{}) (*Node, error) {
+	if err := c.authorize(ctx, Access{Operation: OperationWrite, Labels: labels}); err != nil {
+		return nil, err
+	}
+	if err := c.validateNode(labels, properties); err != nil {
+		return nil, err
+	}
 	reqBody := map[string]interface{}{
 		"labels":     labels,
 		"properties": properties,
@@ -439,6 +867,12 @@ func (c *Client) CreateNodeWithExternalID(
 	externalID string,
 	conflictPolicy string,
 ) (*CreateNodeResponse, error) {
+	if err := c.authorize(ctx, Access{Operation: OperationWrite, Labels: labels}); err != nil {
+		return nil, err
+	}
+	if err := c.validateNode(labels, properties); err != nil {
+		return nil, err
+	}
 	reqBody := CreateNodeRequest{
 		Labels:         labels,
 		Properties:     properties,
@@ -484,6 +918,14 @@ func (c *Client) GetNodeByExternalID(ctx context.Context, externalID string) (*G
 
 // GetNode retrieves a node by its ID.
 func (c *Client) GetNode(ctx context.Context, id string) (*Node, error) {
+	if err := c.authorize(ctx, Access{Operation: OperationRead}); err != nil {
+		return nil, err
+	}
+	node, err := c.getNode(ctx, id)
+	return c.staleNode(ctx, id, node, err)
+}
+
+func (c *Client) getNode(ctx context.Context, id string) (*Node, error) {
 	path := fmt.Sprintf("/nodes/%s", url.PathEscape(id))
 	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
@@ -500,7 +942,22 @@ func (c *Client) GetNode(ctx context.Context, id string) (*Node, error) {
 }
 
 // UpdateNode updates a node's properties.
+//
+// With Config.Schema set the node is fetched first so the replacement
+// properties can be validated against its labels.
 func (c *Client) UpdateNode(ctx context.Context, id string, properties map[string]interface{}) (*Node, error) {
+	if err := c.authorize(ctx, Access{Operation: OperationWrite}); err != nil {
+		return nil, err
+	}
+	if c.schema.Load() != nil {
+		current, err := c.GetNode(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.validateNode(current.Labels, properties); err != nil {
+			return nil, err
+		}
+	}
 	reqBody := map[string]interface{}{
 		"properties": properties,
 	}
@@ -511,6 +968,7 @@ func (c *Client) UpdateNode(ctx context.Context, id string, properties map[strin
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.stale.forget(staleNodeKey(ctx, id))
 
 	var node Node
 	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
@@ -522,18 +980,25 @@ func (c *Client) UpdateNode(ctx context.Context, id string, properties map[strin
 
 // DeleteNode deletes a node by its ID.
 func (c *Client) DeleteNode(ctx context.Context, id string) error {
+	if err := c.authorize(ctx, Access{Operation: OperationWrite}); err != nil {
+		return err
+	}
 	path := fmt.Sprintf("/nodes/%s", url.PathEscape(id))
 	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	c.stale.forget(staleNodeKey(ctx, id))
 
 	return nil
 }
 
 // CreateRelationship creates a new relationship between two nodes.
 func (c *Client) CreateRelationship(ctx context.Context, startNode, endNode, relType string, properties map[string]interface{}) (*Relationship, error) {
+	if err := c.authorize(ctx, Access{Operation: OperationWrite, RelationshipTypes: []string{relType}}); err != nil {
+		return nil, err
+	}
 	reqBody := map[string]interface{}{
 		"start_node": startNode,
 		"end_node":   endNode,
@@ -557,6 +1022,9 @@ func (c *Client) CreateRelationship(ctx context.Context, startNode, endNode, rel
 
 // GetRelationship retrieves a relationship by its ID.
 func (c *Client) GetRelationship(ctx context.Context, id string) (*Relationship, error) {
+	if err := c.authorize(ctx, Access{Operation: OperationRead}); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("/relationships/%s", url.PathEscape(id))
 	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
@@ -572,8 +1040,46 @@ func (c *Client) GetRelationship(ctx context.Context, id string) (*Relationship,
 	return &rel, nil
 }
 
+// UpdateRelationship replaces a relationship's properties: properties
+// not in the map are removed. Use PatchRelationship to change only some.
+func (c *Client) UpdateRelationship(ctx context.Context, id string, properties map[string]interface{}) (*Relationship, error) {
+	return c.writeRelationship(ctx, http.MethodPut, id, properties)
+}
+
+// PatchRelationship merges properties into a relationship's existing
+// ones, leaving properties not in the map untouched.
+func (c *Client) PatchRelationship(ctx context.Context, id string, properties map[string]interface{}) (*Relationship, error) {
+	return c.writeRelationship(ctx, http.MethodPatch, id, properties)
+}
+
+func (c *Client) writeRelationship(ctx context.Context, method, id string, properties map[string]interface{}) (*Relationship, error) {
+	if err := c.authorize(ctx, Access{Operation: OperationWrite}); err != nil {
+		return nil, err
+	}
+	reqBody := map[string]interface{}{
+		"properties": properties,
+	}
+
+	path := fmt.Sprintf("/relationships/%s", url.PathEscape(id))
+	resp, err := c.doRequest(ctx, method, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rel Relationship
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &rel, nil
+}
+
 // DeleteRelationship deletes a relationship by its ID.
 func (c *Client) DeleteRelationship(ctx context.Context, id string) error {
+	if err := c.authorize(ctx, Access{Operation: OperationWrite}); err != nil {
+		return err
+	}
 	path := fmt.Sprintf("/relationships/%s", url.PathEscape(id))
 	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
 	if err != nil {
@@ -600,6 +1106,25 @@ func (c *Client) BatchCreateNodes(ctx context.Context, nodes []struct {
 	Labels     []string
 	Properties map[string]interface{}
 }) ([]Node, error) {
+	access := Access{Operation: OperationWrite}
+	for _, n := range nodes {
+		access.Labels = appendUnique(access.Labels, n.Labels...)
+	}
+	if err := c.authorize(ctx, access); err != nil {
+		return nil, err
+	}
+	if schema := c.schema.Load(); schema != nil {
+		var violations []Violation
+		for i, n := range nodes {
+			for _, v := range schema.Validate(n.Labels, n.Properties) {
+				v.Item = i
+				violations = append(violations, v)
+			}
+		}
+		if len(violations) > 0 {
+			return nil, &ValidationError{Violations: violations}
+		}
+	}
 	reqBody := map[string]interface{}{
 		"nodes": nodes,
 	}
@@ -618,13 +1143,25 @@ func (c *Client) BatchCreateNodes(ctx context.Context, nodes []struct {
 	return result, nil
 }
 
-// BatchCreateRelationships creates multiple relationships in a single request.
-func (c *Client) BatchCreateRelationships(ctx context.Context, relationships []struct {
+// batchRelationship is a relationship in a POST /batch/relationships
+// request.
+type batchRelationship struct {
 	StartNode  string
 	EndNode    string
 	Type       string
 	Properties map[string]interface{}
-}) ([]Relationship, error) {
+}
+
+// batchCreateRelationshipsByID creates multiple relationships between
+// known node IDs in a single request.
+func (c *Client) batchCreateRelationshipsByID(ctx context.Context, relationships []batchRelationship) ([]Relationship, error) {
+	access := Access{Operation: OperationWrite}
+	for _, r := range relationships {
+		access.RelationshipTypes = appendUnique(access.RelationshipTypes, r.Type)
+	}
+	if err := c.authorize(ctx, access); err != nil {
+		return nil, err
+	}
 	reqBody := map[string]interface{}{
 		"relationships": relationships,
 	}
@@ -714,6 +1251,9 @@ type Index struct {
 
 // CreateIndex creates a new index on node properties.
 func (c *Client) CreateIndex(ctx context.Context, name, label string, properties []string) error {
+	if err := c.authorize(ctx, Access{Operation: OperationSchema, Labels: []string{label}}); err != nil {
+		return err
+	}
 	reqBody := map[string]interface{}{
 		"name":       name,
 		"label":      label,
@@ -749,6 +1289,9 @@ func (c *Client) ListIndexes(ctx context.Context) ([]Index, error) {
 
 // DeleteIndex deletes an index by name.
 func (c *Client) DeleteIndex(ctx context.Context, name string) error {
+	if err := c.authorize(ctx, Access{Operation: OperationSchema}); err != nil {
+		return err
+	}
 	path := fmt.Sprintf("/schema/indexes/%s", url.PathEscape(name))
 	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
 	if err != nil {
@@ -763,6 +1306,12 @@ func (c *Client) DeleteIndex(ctx context.Context, name string) error {
 type Transaction struct {
 	client *Client
 	id     string
+
+	// Set on nested transactions opened with Begin (see savepoint.go).
+	parent    *Transaction
+	savepoint string
+	seq       atomic.Int64
+	done      bool
 }
 
 // BeginTransaction starts a new transaction.
@@ -780,14 +1329,30 @@ func (c *Client) BeginTransaction(ctx context.Context) (*Transaction, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &Transaction{
+	tx := &Transaction{
 		client: c,
 		id:     result.TransactionID,
-	}, nil
+	}
+	c.openTx.Store(tx.id, tx)
+	return tx, nil
 }
 
 // ExecuteCypher executes a Cypher query within the transaction.
 func (tx *Transaction) ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
+	if tx.done {
+		return nil, ErrTransactionDone
+	}
+	query, err := tx.client.checkQuery(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx, finish := tx.client.startQuery(ctx, query, params, true)
+	result, err := tx.dryRunOr(ctx, query, params)
+	return result, finish(result, err)
+}
+
+// execute sends query as is, bypassing the query policy.
+func (tx *Transaction) execute(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
 	reqBody := map[string]interface{}{
 		"query":          query,
 		"transaction_id": tx.id,
@@ -807,11 +1372,17 @@ func (tx *Transaction) ExecuteCypher(ctx context.Context, query string, params m
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &result, nil
+	result.fillColumnTypes()
+	return &result, tx.client.checkNotifications(&result)
 }
 
-// Commit commits the transaction.
+// Commit commits the transaction. On a nested transaction (see Begin)
+// it only releases the savepoint; the work becomes part of the
+// enclosing transaction.
 func (tx *Transaction) Commit(ctx context.Context) error {
+	if tx.parent != nil {
+		return tx.releaseSavepoint(ctx)
+	}
 	reqBody := map[string]interface{}{
 		"transaction_id": tx.id,
 	}
@@ -822,16 +1393,28 @@ func (tx *Transaction) Commit(ctx context.Context) error {
 	}
 	defer resp.Body.Close()
 
+	tx.client.openTx.Delete(tx.id)
 	return nil
 }
 
-// Rollback rolls back the transaction.
+// Rollback rolls back the transaction. On a nested transaction it
+// undoes only the work done since Begin.
 func (tx *Transaction) Rollback(ctx context.Context) error {
+	if tx.parent != nil {
+		return tx.rollbackToSavepoint(ctx)
+	}
+	return tx.rollback(ctx, tx.client.doRequest)
+}
+
+func (tx *Transaction) rollback(ctx context.Context, do func(context.Context, string, string, interface{}) (*http.Response, error)) error {
 	reqBody := map[string]interface{}{
 		"transaction_id": tx.id,
 	}
 
-	resp, err := tx.client.doRequest(ctx, http.MethodPost, "/transaction/rollback", reqBody)
+	// Once a rollback was attempted the transaction is no longer ours to
+	// clean up, whatever the outcome.
+	tx.client.openTx.Delete(tx.id)
+	resp, err := do(ctx, http.MethodPost, "/transaction/rollback", reqBody)
 	if err != nil {
 		return err
 	}
@@ -77,6 +77,38 @@ func TestExecuteCypher(t *testing.T) {
 	assert.Equal(t, 1.5, result.Stats.ExecutionTimeMs)
 }
 
+func TestExecuteCypherExtendedStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := QueryResult{
+			Columns: []string{"n"},
+			Stats: &QueryStats{
+				LabelsAdded:     2,
+				IndexesUsed:     []string{"person_email"},
+				RowsScanned:     120,
+				DbHits:          4096,
+				PeakMemoryBytes: 1 << 20,
+				CacheHit:        true,
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	result, err := client.ExecuteCypher(context.Background(), "MATCH (n:Person) SET n:Active RETURN n", nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.Stats)
+	assert.Equal(t, 2, result.Stats.LabelsAdded)
+	assert.Equal(t, []string{"person_email"}, result.Stats.IndexesUsed)
+	assert.Equal(t, int64(120), result.Stats.RowsScanned)
+	assert.Equal(t, int64(4096), result.Stats.DbHits)
+	assert.Equal(t, int64(1<<20), result.Stats.PeakMemoryBytes)
+	assert.True(t, result.Stats.CacheHit)
+}
+
 func TestExecuteCypherWithParams(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req map[string]interface{}
@@ -297,6 +329,49 @@ func TestGetRelationship(t *testing.T) {
 	assert.Equal(t, "KNOWS", rel.Type)
 }
 
+func TestUpdateRelationship(t *testing.T) {
+	for method, update := range map[string]func(*Client, context.Context, string, map[string]interface{}) (*Relationship, error){
+		"PUT":   (*Client).UpdateRelationship,
+		"PATCH": (*Client).PatchRelationship,
+	} {
+		t.Run(method, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/relationships/r1", r.URL.Path)
+				assert.Equal(t, method, r.Method)
+
+				var req map[string]interface{}
+				err := json.NewDecoder(r.Body).Decode(&req)
+				require.NoError(t, err)
+
+				props := req["properties"].(map[string]interface{})
+				assert.Equal(t, float64(2020), props["since"])
+
+				response := Relationship{
+					ID:         "r1",
+					Type:       "KNOWS",
+					StartNode:  "1",
+					EndNode:    "2",
+					Properties: props,
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(response)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{BaseURL: server.URL})
+			ctx := context.Background()
+
+			rel, err := update(client, ctx, "r1", map[string]interface{}{
+				"since": 2020,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, float64(2020), rel.Properties["since"])
+		})
+	}
+}
+
 func TestDeleteRelationship(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/relationships/r1", r.URL.Path)
@@ -631,3 +706,41 @@ func TestTransactionRollback(t *testing.T) {
 	err = tx.Rollback(ctx)
 	require.NoError(t, err)
 }
+
+func TestCloseRollsBackOrphanedTransactions(t *testing.T) {
+	var rolledBack []string
+	ids := []string{"tx1", "tx2"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/transaction/begin":
+			json.NewEncoder(w).Encode(map[string]string{"transaction_id": ids[0]})
+			ids = ids[1:]
+		case "/transaction/rollback":
+			rolledBack = append(rolledBack, req["transaction_id"].(string))
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+	committed, err := client.BeginTransaction(ctx)
+	require.NoError(t, err)
+	require.NoError(t, committed.Commit(ctx))
+	orphan, err := client.BeginTransaction(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+	assert.Equal(t, []string{"tx2"}, rolledBack)
+	assert.Equal(t, "tx2", orphan.id)
+
+	_, err = client.ExecuteCypher(ctx, "RETURN 1", nil)
+	assert.ErrorIs(t, err, ErrClientClosed)
+	_, err = client.BeginTransaction(ctx)
+	assert.ErrorIs(t, err, ErrClientClosed)
+	assert.NoError(t, client.Close())
+}
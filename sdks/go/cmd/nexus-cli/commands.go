@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/hivellm/nexus-go/ingest/spreadsheet"
+)
+
+// paramFlags collects repeated -param name=value flags. Values are
+// decoded as JSON when possible (numbers, booleans, lists) and kept as
+// strings otherwise.
+type paramFlags map[string]interface{}
+
+func (p paramFlags) String() string { return fmt.Sprint(map[string]interface{}(p)) }
+
+func (p paramFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("parameter %q must be name=value", s)
+	}
+	p[name] = parseValue(value)
+	return nil
+}
+
+func parseValue(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err == nil {
+		return v
+	}
+	return s
+}
+
+// statementArg returns the Cypher text from the -f file or the
+// remaining arguments.
+func statementArg(fs *flag.FlagSet, file string) (string, error) {
+	if file != "" {
+		b, err := readFile(file)
+		return string(b), err
+	}
+	if fs.NArg() == 0 {
+		return "", errors.New("no statement given")
+	}
+	return strings.Join(fs.Args(), " "), nil
+}
+
+func readFile(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name)
+}
+
+func runQuery(ctx context.Context, client *nexus.Client, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	format := fs.String("format", "table", "output format: table, json or csv")
+	file := fs.String("f", "", "read the statement from a file (- for stdin)")
+	params := paramFlags{}
+	fs.Var(params, "param", "query parameter name=value (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	query, err := statementArg(fs, *file)
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ExecuteCypher(ctx, query, params)
+	if err != nil {
+		return err
+	}
+	return writeResult(stdout, *format, result)
+}
+
+func runIndex(ctx context.Context, client *nexus.Client, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: index list | create -name N -label L -props a,b | drop NAME")
+	}
+	switch args[0] {
+	case "list":
+		indexes, err := client.ListIndexes(ctx)
+		if err != nil {
+			return err
+		}
+		result := &nexus.QueryResult{Columns: []string{"name", "label", "properties", "type"}}
+		for _, idx := range indexes {
+			result.Rows = append(result.Rows, []interface{}{
+				idx.Name, idx.Label, strings.Join(idx.Properties, ","), idx.Type,
+			})
+		}
+		return writeTable(stdout, result)
+	case "create":
+		fs := flag.NewFlagSet("index create", flag.ContinueOnError)
+		name := fs.String("name", "", "index name")
+		label := fs.String("label", "", "node label")
+		props := fs.String("props", "", "comma-separated property names")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *name == "" || *label == "" || *props == "" {
+			return errors.New("index create requires -name, -label and -props")
+		}
+		if err := client.CreateIndex(ctx, *name, *label, strings.Split(*props, ",")); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "created index %s\n", *name)
+		return nil
+	case "drop":
+		if len(args) != 2 {
+			return errors.New("usage: index drop NAME")
+		}
+		if err := client.DeleteIndex(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "dropped index %s\n", args[1])
+		return nil
+	}
+	return fmt.Errorf("unknown index subcommand %q", args[0])
+}
+
+func runImport(ctx context.Context, client *nexus.Client, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	labels := fs.String("label", "", "comma-separated labels for the imported nodes")
+	format := fs.String("format", "", "input format: json (array or NDJSON), csv, xlsx or ods; default from extension")
+	sheet := fs.String("sheet", "", "sheet to read from an xlsx/ods workbook; default the first")
+	batchSize := fs.Int("batch", 500, "nodes per batch request")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *batchSize <= 0 {
+		return fmt.Errorf("-batch must be positive, got %d", *batchSize)
+	}
+	if fs.NArg() != 1 || *labels == "" {
+		return errors.New("usage: import -label L [-format json|csv|xlsx|ods] [-sheet S] FILE")
+	}
+	name := fs.Arg(0)
+	if *format == "" {
+		*format = "json"
+		switch lower := strings.ToLower(name); {
+		case strings.HasSuffix(lower, ".csv"):
+			*format = "csv"
+		case strings.HasSuffix(lower, ".xlsx"), strings.HasSuffix(lower, ".xlsm"):
+			*format = "xlsx"
+		case strings.HasSuffix(lower, ".ods"):
+			*format = "ods"
+		}
+	}
+
+	data, err := readFile(name)
+	if err != nil {
+		return err
+	}
+	var records []map[string]interface{}
+	switch *format {
+	case "json":
+		records, err = decodeJSONRecords(data)
+	case "csv":
+		records, err = decodeCSVRecords(data)
+	case "xlsx", "ods":
+		records, err = decodeSheetRecords(data, *format, *sheet)
+	default:
+		return fmt.Errorf("unknown format %q (want json, csv, xlsx or ods)", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	nodeLabels := strings.Split(*labels, ",")
+	total := 0
+	for start := 0; start < len(records); start += *batchSize {
+		end := start + *batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		batch := make([]struct {
+			Labels     []string
+			Properties map[string]interface{}
+		}, 0, end-start)
+		for _, r := range records[start:end] {
+			batch = append(batch, struct {
+				Labels     []string
+				Properties map[string]interface{}
+			}{nodeLabels, r})
+		}
+		created, err := client.BatchCreateNodes(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("batch starting at record %d: %w", start, err)
+		}
+		total += len(created)
+	}
+	fmt.Fprintf(stdout, "imported %d node(s)\n", total)
+	return nil
+}
+
+// decodeSheetRecords reads one sheet of a workbook, using its first row
+// as property names.
+func decodeSheetRecords(data []byte, format, name string) ([]map[string]interface{}, error) {
+	read := spreadsheet.ReadXLSX
+	if format == "ods" {
+		read = spreadsheet.ReadODS
+	}
+	wb, err := read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	if len(wb.Sheets) == 0 {
+		return nil, errors.New("workbook has no sheets")
+	}
+	sheet := wb.Sheets[0]
+	if name != "" {
+		if sheet = wb.Sheet(name); sheet == nil {
+			return nil, fmt.Errorf("workbook has no sheet named %q", name)
+		}
+	}
+	return sheet.Records(1)
+}
+
+func decodeJSONRecords(data []byte) ([]map[string]interface{}, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var records []map[string]interface{}
+		if err := json.Unmarshal([]byte(trimmed), &records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(trimmed))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var r map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &r); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// decodeCSVRecords maps each row to the header columns; cells holding
+// JSON scalars (numbers, booleans) are typed, the rest stay strings and
+// empty cells are omitted.
+func decodeCSVRecords(data []byte) ([]map[string]interface{}, error) {
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	header := rows[0]
+	records := make([]map[string]interface{}, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		r := make(map[string]interface{}, len(header))
+		for i, cell := range row {
+			if i >= len(header) || cell == "" {
+				continue
+			}
+			r[header[i]] = parseValue(cell)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func runExport(ctx context.Context, client *nexus.Client, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "json", "output format: json (NDJSON) or csv")
+	out := fs.String("o", "-", "output file (- for stdout)")
+	params := paramFlags{}
+	fs.Var(params, "param", "query parameter name=value (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	query, err := statementArg(fs, "")
+	if err != nil {
+		return err
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unknown format %q (want json or csv)", *format)
+	}
+
+	result, err := client.ExecuteCypher(ctx, query, params)
+	if err != nil {
+		return err
+	}
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		err = writeResult(f, *format, result)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "exported %d row(s) to %s\n", len(result.Rows), *out)
+		return nil
+	}
+	return writeResult(stdout, *format, result)
+}
+
+func runTx(ctx context.Context, client *nexus.Client, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("tx", flag.ContinueOnError)
+	file := fs.String("f", "-", "Cypher script file (- for stdin)")
+	format := fs.String("format", "table", "output format for statement results")
+	rollback := fs.Bool("rollback", false, "roll back instead of committing (dry run)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	script, err := readFile(*file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := client.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range nexus.SplitStatements(string(script)) {
+		result, err := tx.ExecuteCypher(ctx, stmt.Text, nil)
+		if err == nil {
+			err = writeResult(stdout, *format, result)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				return fmt.Errorf("line %d: %w (rollback failed: %v)", stmt.Line, err, rbErr)
+			}
+			return fmt.Errorf("line %d: %w (rolled back)", stmt.Line, err)
+		}
+	}
+	if *rollback {
+		if err := tx.Rollback(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "rolled back")
+		return nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, "committed")
+	return nil
+}
+
+func runReplay(ctx context.Context, client *nexus.Client, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := fs.Float64("speed", 1, "pace relative to the capture (2 = twice as fast, 0 = no pacing)")
+	concurrency := fs.Int("concurrency", 16, "maximum statements in flight")
+	readOnly := fs.Bool("read-only", false, "skip write statements")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: replay [-speed X] [-concurrency N] [-read-only] CAPTURE.jsonl")
+	}
+	capture, err := readFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	report, err := nexus.ReplayTraffic(ctx, bytes.NewReader(capture), client, nexus.ReplayOptions{
+		Speed:       *speed,
+		Concurrency: *concurrency,
+		ReadOnly:    *readOnly,
+	})
+	if report != nil {
+		fmt.Fprintf(stdout, "replayed %d statement(s) in %s: %d failed, %d skipped\n",
+			report.Replayed, report.Elapsed.Round(time.Millisecond), report.Failed, report.Skipped)
+		for _, s := range report.Latencies {
+			fmt.Fprintf(stdout, "%8d  p50 %-10s p99 %-10s %s\n", s.Count, s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond), s.Query)
+		}
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+// Command nexus-cli is the official command-line client for Nexus, built
+// on the Go SDK.
+//
+//	nexus-cli [global flags] <command> [flags] [args]
+//
+// Commands:
+//
+//	query    run a Cypher statement and print the result
+//	index    list, create or drop indexes
+//	import   load nodes from a JSON/NDJSON/CSV/xlsx/ODS file
+//	export   write a query result to a JSON/CSV file
+//	tx       run a script of statements inside one transaction
+//	replay   re-execute a traffic capture (see nexus.TrafficRecorder)
+//
+// Global flags default to the NEXUS_URL, NEXUS_API_KEY, NEXUS_USER and
+// NEXUS_PASSWORD environment variables.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, client *nexus.Client, args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"query", "run a Cypher statement and print the result", runQuery},
+	{"index", "list, create or drop indexes", runIndex},
+	{"import", "load nodes from a JSON/NDJSON/CSV/xlsx/ODS file", runImport},
+	{"export", "write a query result to a JSON/CSV file", runExport},
+	{"tx", "run a script of statements inside one transaction", runTx},
+	{"replay", "re-execute a traffic capture (see nexus.TrafficRecorder)", runReplay},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "nexus-cli:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	global := flag.NewFlagSet("nexus-cli", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	url := global.String("url", os.Getenv("NEXUS_URL"), "server URL (nexus://, http://, https://)")
+	apiKey := global.String("api-key", os.Getenv("NEXUS_API_KEY"), "API key")
+	user := global.String("user", os.Getenv("NEXUS_USER"), "username for basic auth")
+	password := global.String("password", os.Getenv("NEXUS_PASSWORD"), "password for basic auth")
+	timeout := global.Duration("timeout", 30*time.Second, "per-request timeout")
+	global.Usage = func() {
+		fmt.Fprintln(stderr, "usage: nexus-cli [global flags] <command> [flags] [args]")
+		fmt.Fprintln(stderr, "\ncommands:")
+		for _, c := range commands {
+			fmt.Fprintf(stderr, "  %-8s %s\n", c.name, c.usage)
+		}
+		fmt.Fprintln(stderr, "\nglobal flags:")
+		global.PrintDefaults()
+	}
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		return errors.New("no command given")
+	}
+
+	name := global.Arg(0)
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		client, err := nexus.NewClientE(nexus.Config{
+			BaseURL:  *url,
+			APIKey:   *apiKey,
+			Username: *user,
+			Password: *password,
+			Timeout:  *timeout,
+		})
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		return c.run(ctx, client, global.Args()[1:], stdout)
+	}
+	global.Usage()
+	return fmt.Errorf("unknown command %q", name)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCommandFormats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, map[string]interface{}{"min": float64(30)}, req["parameters"])
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"columns": []string{"name", "tags"},
+			"rows":    [][]interface{}{{"Alice", []string{"a", "b"}}, {"Bob, Jr.", nil}},
+		})
+	}))
+	defer server.Close()
+
+	var out, errOut bytes.Buffer
+	err := run([]string{"-url", server.URL, "query", "-format", "csv", "-param", "min=30",
+		"MATCH (n) WHERE n.age > $min RETURN n.name AS name, n.tags AS tags"}, &out, &errOut)
+	require.NoError(t, err)
+	assert.Equal(t, "name,tags\nAlice,\"[\"\"a\"\",\"\"b\"\"]\"\n\"Bob, Jr.\",\n", out.String())
+
+	out.Reset()
+	err = run([]string{"-url", server.URL, "query", "-format", "json", "-param", "min=30", "RETURN 1"}, &out, &errOut)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"name\":\"Alice\",\"tags\":[\"a\",\"b\"]}\n{\"name\":\"Bob, Jr.\",\"tags\":null}\n", out.String())
+}
+
+func TestDecodeCSVRecords(t *testing.T) {
+	records, err := decodeCSVRecords([]byte("name,age,active\nAlice,30,true\nBob,,false\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"name": "Alice", "age": float64(30), "active": true},
+		{"name": "Bob", "active": false},
+	}, records)
+}
+
+func TestImportRejectsNonPositiveBatch(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "people.json")
+	require.NoError(t, os.WriteFile(file, []byte(`[{"name":"Alice"}]`), 0o644))
+
+	var out, errOut bytes.Buffer
+	err := run([]string{"-url", "http://127.0.0.1:1", "import", "-label", "Person", "-batch", "0", file}, &out, &errOut)
+	assert.EqualError(t, err, "-batch must be positive, got 0")
+}
+
+func TestTxReportsOnlyAfterCommit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/transaction/begin":
+			w.Write([]byte(`{"transaction_id":"tx"}`))
+		case "/transaction/commit":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"disk full"}`))
+		default:
+			w.Write([]byte(`{"columns":[],"rows":[]}`))
+		}
+	}))
+	defer server.Close()
+	script := filepath.Join(t.TempDir(), "script.cypher")
+	require.NoError(t, os.WriteFile(script, []byte("CREATE (n);\n"), 0o644))
+
+	var out, errOut bytes.Buffer
+	err := run([]string{"-url", server.URL, "tx", "-f", script}, &out, &errOut)
+	require.Error(t, err)
+	assert.NotContains(t, out.String(), "committed")
+}
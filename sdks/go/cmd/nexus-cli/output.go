@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	nexus "github.com/hivellm/nexus-go"
+)
+
+// writeResult renders result to w in format (table, json or csv).
+func writeResult(w io.Writer, format string, result *nexus.QueryResult) error {
+	switch format {
+	case "table", "":
+		return writeTable(w, result)
+	case "json":
+		return writeJSONLines(w, result)
+	case "csv":
+		return writeCSV(w, result)
+	}
+	return fmt.Errorf("unknown format %q (want table, json or csv)", format)
+}
+
+func writeTable(w io.Writer, result *nexus.QueryResult) error {
+	return result.RenderTable(w, 0)
+}
+
+// writeJSONLines writes one JSON object per row, keyed by column.
+func writeJSONLines(w io.Writer, result *nexus.QueryResult) error {
+	enc := json.NewEncoder(w)
+	for _, row := range result.RowsAsMap() {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, result *nexus.QueryResult) error {
+	return result.WriteCSV(w, nexus.CSVOptions{})
+}
@@ -0,0 +1,178 @@
+package nexus
+
+// ColumnType describes what a result column holds, so generic consumers
+// (exporters, table renderers, UIs) can format cells without sniffing
+// every value themselves.
+type ColumnType string
+
+const (
+	ColumnNode         ColumnType = "node"
+	ColumnRelationship ColumnType = "relationship"
+	ColumnPath         ColumnType = "path"
+	ColumnString       ColumnType = "string"
+	ColumnInteger      ColumnType = "integer"
+	ColumnFloat        ColumnType = "float"
+	ColumnBoolean      ColumnType = "boolean"
+	ColumnMap          ColumnType = "map"
+	ColumnList         ColumnType = "list"
+	// ColumnNull is reported for columns whose every cell is null (or
+	// for results with no rows).
+	ColumnNull ColumnType = "null"
+	// ColumnMixed is reported when cells of one column disagree, as with
+	// `RETURN coalesce(n.age, n.name)`.
+	ColumnMixed ColumnType = "mixed"
+)
+
+// ColumnType returns the type of the named column, or "" when the
+// result has no such column.
+func (qr *QueryResult) ColumnType(name string) ColumnType {
+	for i, c := range qr.Columns {
+		if c == name && i < len(qr.ColumnTypes) {
+			return qr.ColumnTypes[i]
+		}
+	}
+	return ""
+}
+
+// fillColumnTypes sets ColumnTypes when the server did not send them,
+// inferring each column from its non-null cells.
+func (qr *QueryResult) fillColumnTypes() {
+	if qr == nil || len(qr.ColumnTypes) == len(qr.Columns) {
+		return
+	}
+	qr.ColumnTypes = InferColumnTypes(qr.Columns, qr.Rows)
+}
+
+// InferColumnTypes classifies each column from the decoded cells of
+// rows. Null cells are ignored; a column whose non-null cells classify
+// differently is ColumnMixed, except that integers and floats together
+// widen to ColumnFloat.
+func InferColumnTypes(columns []string, rows [][]interface{}) []ColumnType {
+	types := make([]ColumnType, len(columns))
+	for i := range columns {
+		t := ColumnNull
+		for _, row := range rows {
+			if i >= len(row) {
+				continue
+			}
+			t = mergeColumnTypes(t, valueColumnType(row[i]))
+			if t == ColumnMixed {
+				break
+			}
+		}
+		types[i] = t
+	}
+	return types
+}
+
+func mergeColumnTypes(a, b ColumnType) ColumnType {
+	switch {
+	case a == b || b == ColumnNull:
+		return a
+	case a == ColumnNull:
+		return b
+	case (a == ColumnInteger && b == ColumnFloat) || (a == ColumnFloat && b == ColumnInteger):
+		return ColumnFloat
+	}
+	return ColumnMixed
+}
+
+// valueColumnType classifies one cell. Nodes carry `_nexus_id` (or
+// `id` plus `labels`); relationships additionally carry `_nexus_type`
+// (or `type`) and their endpoints; paths are either an object with
+// `nodes` and `relationships` or a list alternating node, relationship,
+// node.
+func valueColumnType(v interface{}) ColumnType {
+	switch x := v.(type) {
+	case nil:
+		return ColumnNull
+	case string:
+		return ColumnString
+	case bool:
+		return ColumnBoolean
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return ColumnInteger
+	case float32:
+		return ColumnFloat
+	case float64:
+		// JSON decodes every number as float64; whole numbers are
+		// integers as far as the server is concerned.
+		if x == float64(int64(x)) {
+			return ColumnInteger
+		}
+		return ColumnFloat
+	case map[string]interface{}:
+		return mapColumnType(x)
+	case []interface{}:
+		if isPathList(x) {
+			return ColumnPath
+		}
+		return ColumnList
+	}
+	return ColumnMixed
+}
+
+func mapColumnType(m map[string]interface{}) ColumnType {
+	if _, ok := m["nodes"].([]interface{}); ok {
+		if _, ok := m["relationships"].([]interface{}); ok {
+			return ColumnPath
+		}
+	}
+	if isRelationshipValue(m) {
+		return ColumnRelationship
+	}
+	if isNodeValue(m) {
+		return ColumnNode
+	}
+	return ColumnMap
+}
+
+func isNodeValue(m map[string]interface{}) bool {
+	if _, ok := m["_nexus_id"]; ok {
+		return true
+	}
+	_, hasID := m["id"]
+	_, hasLabels := m["labels"].([]interface{})
+	return hasID && hasLabels
+}
+
+func isRelationshipValue(m map[string]interface{}) bool {
+	if _, ok := m["_nexus_type"]; ok {
+		return true
+	}
+	if _, ok := m["type"].(string); !ok {
+		return false
+	}
+	if _, ok := m["_nexus_id"]; !ok {
+		if _, ok := m["id"]; !ok {
+			return false
+		}
+	}
+	for _, pair := range [][2]string{{"_source", "_target"}, {"start_node", "end_node"}, {"source", "target"}, {"start", "end"}} {
+		_, hasStart := m[pair[0]]
+		_, hasEnd := m[pair[1]]
+		if hasStart && hasEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func isPathList(items []interface{}) bool {
+	if len(items) < 3 || len(items)%2 == 0 {
+		return false
+	}
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if i%2 == 0 && (isRelationshipValue(m) || !isNodeValue(m)) {
+			return false
+		}
+		if i%2 == 1 && !isRelationshipValue(m) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnTypesInferredFromRows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"columns":["n","r","p","name","age","score","tags","meta","missing","odd"],"rows":[
+			[{"_nexus_id":1,"name":"Alice"},{"_nexus_id":7,"_nexus_type":"KNOWS","_source":1,"_target":2},
+			 [{"_nexus_id":1},{"_nexus_id":7,"_nexus_type":"KNOWS","_source":1,"_target":2},{"_nexus_id":2}],
+			 "Alice",30,1,["a"],{"k":"v"},null,1],
+			[null,null,null,"Bob",41,2.5,[],{},null,"x"]]}`))
+	}))
+	defer server.Close()
+
+	result, err := NewClient(Config{BaseURL: server.URL}).ExecuteCypher(context.Background(), "MATCH ...", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []ColumnType{
+		ColumnNode, ColumnRelationship, ColumnPath, ColumnString, ColumnInteger,
+		ColumnFloat, ColumnList, ColumnMap, ColumnNull, ColumnMixed,
+	}, result.ColumnTypes)
+	assert.Equal(t, ColumnPath, result.ColumnType("p"))
+	assert.Equal(t, ColumnType(""), result.ColumnType("nope"))
+}
+
+func TestColumnTypesPreferServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"columns":["x"],"rows":[[1]],"column_types":["float"]}`))
+	}))
+	defer server.Close()
+
+	result, err := NewClient(Config{BaseURL: server.URL}).ExecuteCypher(context.Background(), "RETURN 1.0 AS x", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []ColumnType{ColumnFloat}, result.ColumnTypes)
+}
+
+func TestInferColumnTypesMapShapes(t *testing.T) {
+	rows := [][]interface{}{{
+		map[string]interface{}{"id": "n1", "labels": []interface{}{"Person"}, "properties": map[string]interface{}{}},
+		map[string]interface{}{"id": "r1", "type": "KNOWS", "start_node": "n1", "end_node": "n2"},
+		map[string]interface{}{"nodes": []interface{}{}, "relationships": []interface{}{}},
+		map[string]interface{}{"type": "plain map"},
+	}}
+	assert.Equal(t, []ColumnType{ColumnNode, ColumnRelationship, ColumnPath, ColumnMap},
+		InferColumnTypes([]string{"a", "b", "c", "d"}, rows))
+}
@@ -0,0 +1,273 @@
+package nexus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ConstraintKind is the kind of schema constraint a write violated, as
+// named in the server's `ERR_CONSTRAINT_VIOLATED: kind=…` messages.
+type ConstraintKind string
+
+const (
+	ConstraintUniqueness                    ConstraintKind = "UNIQUENESS"
+	ConstraintNodePropertyExistence         ConstraintKind = "NODE_PROPERTY_EXISTENCE"
+	ConstraintNodeKey                       ConstraintKind = "NODE_KEY"
+	ConstraintRelationshipPropertyExistence ConstraintKind = "RELATIONSHIP_PROPERTY_EXISTENCE"
+	ConstraintPropertyType                  ConstraintKind = "PROPERTY_TYPE"
+)
+
+// ConstraintViolationError details a write rejected by a uniqueness,
+// existence, node key or property type constraint. Fields the server
+// did not report are empty.
+//
+// It is obtained from the *Error a write returns:
+//
+//	var cv *nexus.ConstraintViolationError
+//	if errors.As(err, &cv) {
+//	    return fmt.Errorf("%s %v is already taken", cv.Property(), cv.Value)
+//	}
+type ConstraintViolationError struct {
+	Kind ConstraintKind
+	// Constraint is the constraint name, when the server reports it.
+	Constraint string
+	// Entity is "node" or "relationship".
+	Entity string
+	// Label is the node label or relationship type the constraint is
+	// declared on.
+	Label string
+	// Properties are the constrained properties (several for node keys).
+	Properties []string
+	// Value is the conflicting value of a uniqueness violation.
+	Value interface{}
+	// EntityID is the offending node or relationship, when reported.
+	EntityID string
+	// Expected and Actual are the declared and written types of a
+	// property type violation.
+	Expected string
+	Actual   string
+	// Message is the server's original message.
+	Message string
+	// StatusCode is the HTTP status (409 or 400), 0 on the RPC transport.
+	StatusCode int
+}
+
+// Property returns the first constrained property.
+func (e *ConstraintViolationError) Property() string {
+	if len(e.Properties) == 0 {
+		return ""
+	}
+	return e.Properties[0]
+}
+
+func (e *ConstraintViolationError) Error() string {
+	var b strings.Builder
+	b.WriteString("nexus: ")
+	b.WriteString(strings.ToLower(strings.ReplaceAll(string(e.Kind), "_", " ")))
+	if e.Kind == "" {
+		b.WriteString("schema")
+	}
+	b.WriteString(" constraint")
+	if e.Constraint != "" {
+		fmt.Fprintf(&b, " %q", e.Constraint)
+	}
+	b.WriteString(" violated")
+	if e.Label != "" || len(e.Properties) > 0 {
+		target := ":" + e.Label
+		if e.Entity == "relationship" {
+			target = "[:" + e.Label + "]"
+		}
+		fmt.Fprintf(&b, " on %s(%s)", target, strings.Join(e.Properties, ", "))
+	}
+	if e.Value != nil {
+		fmt.Fprintf(&b, " by value %#v", e.Value)
+	}
+	if e.Expected != "" {
+		fmt.Fprintf(&b, ": expected %s, got %s", e.Expected, e.Actual)
+	}
+	if e.EntityID != "" {
+		fmt.Fprintf(&b, " (entity %s)", e.EntityID)
+	}
+	return b.String()
+}
+
+// As lets errors.As extract a *ConstraintViolationError from an API
+// error whose body reports a constraint violation.
+func (e *Error) As(target interface{}) bool {
+	cv, ok := target.(**ConstraintViolationError)
+	if !ok {
+		return false
+	}
+	parsed := parseConstraintViolation(e.Message)
+	if parsed == nil {
+		return false
+	}
+	parsed.StatusCode = e.StatusCode
+	*cv = parsed
+	return true
+}
+
+// AsConstraintViolation extracts constraint details from any error a
+// write returned: API errors as well as RPC transport errors, which only
+// carry the server's message.
+func AsConstraintViolation(err error) (*ConstraintViolationError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var cv *ConstraintViolationError
+	if errors.As(err, &cv) {
+		return cv, true
+	}
+	cv = parseConstraintViolation(err.Error())
+	return cv, cv != nil
+}
+
+var (
+	constraintKV          = regexp.MustCompile(`(\w+)=("[^"]*"|\[[^\]]*\]|\S+)`)
+	constraintLegacy      = regexp.MustCompile(`(EXISTS|UNIQUE) constraint violated: property '([^']*)'.*label '([^']*)'`)
+	constraintNeo4j       = regexp.MustCompile("(Node|Relationship)\\((\\d+)\\) already exists with (?:label|type) `([^`]+)` and property `([^`]+)` = (.+)$")
+	constraintUniqueIndex = regexp.MustCompile(`Unique constraint violation for key: (.+)$`)
+	constraintIDWrapper   = regexp.MustCompile(`^Some\((.*)\)$`)
+)
+
+// parseConstraintViolation reads the violation messages the server
+// produces: a JSON body with structured fields, the documented
+// `ERR_CONSTRAINT_VIOLATED: kind=… key=value…` form, the older
+// "EXISTS/UNIQUE constraint violated" sentences and Neo4j's wording.
+// It returns nil for any other message.
+func parseConstraintViolation(message string) *ConstraintViolationError {
+	cv := &ConstraintViolationError{Message: message}
+	var body struct {
+		Code       string      `json:"code"`
+		Error      string      `json:"error"`
+		Message    string      `json:"message"`
+		Constraint string      `json:"constraint"`
+		Kind       string      `json:"kind"`
+		Label      string      `json:"label"`
+		Property   string      `json:"property"`
+		Properties []string    `json:"properties"`
+		Value      interface{} `json:"value"`
+	}
+	if json.Unmarshal([]byte(message), &body) == nil {
+		if text := strings.TrimSpace(body.Error + " " + body.Message); text != "" {
+			message = text
+			cv.Message = text
+		}
+		cv.Constraint, cv.Kind, cv.Label, cv.Value = body.Constraint, ConstraintKind(strings.ToUpper(body.Kind)), body.Label, body.Value
+		cv.Properties = body.Properties
+		if body.Property != "" && len(cv.Properties) == 0 {
+			cv.Properties = []string{body.Property}
+		}
+	}
+
+	switch {
+	case strings.Contains(message, "ERR_CONSTRAINT_VIOLATED"):
+		_, details, _ := strings.Cut(message, "ERR_CONSTRAINT_VIOLATED:")
+		for _, m := range constraintKV.FindAllStringSubmatch(details, -1) {
+			key, value := m[1], m[2]
+			switch key {
+			case "kind":
+				cv.Kind = ConstraintKind(value)
+			case "name", "constraint":
+				cv.Constraint = unquoteConstraintString(value)
+			case "entity":
+				cv.Entity = strings.ToLower(value)
+			case "labelsOrTypes", "label", "type":
+				if labels := constraintList(value); len(labels) > 0 {
+					cv.Label = labels[0]
+				}
+			case "properties", "property":
+				cv.Properties = constraintList(value)
+			case "offending_id", "id":
+				if id := constraintIDWrapper.ReplaceAllString(value, "$1"); id != "None" {
+					cv.EntityID = id
+				}
+			case "value":
+				cv.Value = unquoteConstraintValue(value)
+			case "expected":
+				cv.Expected = value
+			case "got":
+				cv.Actual = value
+			}
+		}
+	case constraintLegacy.MatchString(message):
+		m := constraintLegacy.FindStringSubmatch(message)
+		cv.Kind = ConstraintUniqueness
+		if m[1] == "EXISTS" {
+			cv.Kind = ConstraintNodePropertyExistence
+		}
+		cv.Entity, cv.Properties, cv.Label = "node", []string{m[2]}, m[3]
+	case constraintNeo4j.MatchString(message):
+		m := constraintNeo4j.FindStringSubmatch(message)
+		cv.Kind, cv.Entity, cv.EntityID = ConstraintUniqueness, strings.ToLower(m[1]), m[2]
+		cv.Label, cv.Properties, cv.Value = m[3], []string{m[4]}, unquoteConstraintValue(m[5])
+	case constraintUniqueIndex.MatchString(message):
+		cv.Kind = ConstraintUniqueness
+		cv.Value = unquoteConstraintValue(constraintUniqueIndex.FindStringSubmatch(message)[1])
+	case cv.Kind == "":
+		return nil
+	}
+	if cv.Entity == "" {
+		cv.Entity = "node"
+		if cv.Kind == ConstraintRelationshipPropertyExistence {
+			cv.Entity = "relationship"
+		}
+	}
+	return cv
+}
+
+// constraintList reads `["a", "b"]` (Rust Debug output) or a bare name.
+func constraintList(value string) []string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "[") {
+		return []string{unquoteConstraintString(value)}
+	}
+	var out []string
+	for _, part := range strings.Split(strings.Trim(value, "[]"), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, unquoteConstraintString(part))
+		}
+	}
+	return out
+}
+
+// unquoteConstraintValue strips the quotes around a reported value and
+// turns bare numbers and booleans into Go values.
+func unquoteConstraintValue(value string) interface{} {
+	value = strings.TrimSpace(value)
+	if isQuoted(value) {
+		return unquoteConstraintString(value)
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return value
+}
+
+func unquoteConstraintString(value string) string {
+	value = strings.TrimSpace(value)
+	if !isQuoted(value) {
+		return value
+	}
+	if s, err := strconv.Unquote(`"` + value[1:len(value)-1] + `"`); err == nil {
+		return s
+	}
+	return value[1 : len(value)-1]
+}
+
+func isQuoted(value string) bool {
+	return len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0]
+}
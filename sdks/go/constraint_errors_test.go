@@ -0,0 +1,78 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintViolationFromAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error":"ERR_CONSTRAINT_VIOLATED: kind=NODE_KEY entity=NODE labelsOrTypes=[\"Person\"] properties=[\"first\", \"last\"] offending_id=Some(12)"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(Config{BaseURL: server.URL}).ExecuteCypher(context.Background(), "CREATE (:Person {first: 'a'})", nil)
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr), "the API error type is unchanged")
+
+	var cv *ConstraintViolationError
+	require.True(t, errors.As(err, &cv))
+	assert.Equal(t, ConstraintNodeKey, cv.Kind)
+	assert.Equal(t, "node", cv.Entity)
+	assert.Equal(t, "Person", cv.Label)
+	assert.Equal(t, []string{"first", "last"}, cv.Properties)
+	assert.Equal(t, "12", cv.EntityID)
+	assert.Equal(t, http.StatusConflict, cv.StatusCode)
+	assert.Equal(t, "nexus: node key constraint violated on :Person(first, last) (entity 12)", cv.Error())
+}
+
+func TestParseConstraintViolationForms(t *testing.T) {
+	cases := []struct {
+		message string
+		want    ConstraintViolationError
+	}{
+		{
+			"UNIQUE constraint violated: property 'email' value already exists on another node with label 'User'",
+			ConstraintViolationError{Kind: ConstraintUniqueness, Entity: "node", Label: "User", Properties: []string{"email"}},
+		},
+		{
+			"EXISTS constraint violated: property 'name' must exist on nodes with label 'City'",
+			ConstraintViolationError{Kind: ConstraintNodePropertyExistence, Entity: "node", Label: "City", Properties: []string{"name"}},
+		},
+		{
+			`ERR_CONSTRAINT_VIOLATED: kind=PROPERTY_TYPE property="age" expected=INTEGER got=STRING`,
+			ConstraintViolationError{Kind: ConstraintPropertyType, Entity: "node", Properties: []string{"age"}, Expected: "INTEGER", Actual: "STRING"},
+		},
+		{
+			"Node(7) already exists with label `User` and property `email` = 'a@example.com'",
+			ConstraintViolationError{Kind: ConstraintUniqueness, Entity: "node", EntityID: "7", Label: "User", Properties: []string{"email"}, Value: "a@example.com"},
+		},
+		{
+			`{"code":"CONSTRAINT","message":"duplicate","constraint":"user_email","kind":"uniqueness","label":"User","property":"email","value":"a@example.com"}`,
+			ConstraintViolationError{Kind: ConstraintUniqueness, Constraint: "user_email", Entity: "node", Label: "User", Properties: []string{"email"}, Value: "a@example.com"},
+		},
+	}
+	for _, tc := range cases {
+		cv := parseConstraintViolation(tc.message)
+		require.NotNil(t, cv, tc.message)
+		cv.Message = ""
+		assert.Equal(t, tc.want, *cv, tc.message)
+	}
+
+	assert.Nil(t, parseConstraintViolation("Invalid query syntax"))
+	assert.Nil(t, parseConstraintViolation(`{"error":"syntax error"}`))
+
+	cv, ok := AsConstraintViolation(errors.New("rpc: Node(7) already exists with label `User` and property `id` = 42"))
+	require.True(t, ok)
+	assert.Equal(t, int64(42), cv.Value)
+	assert.Equal(t, `nexus: uniqueness constraint violated on :User(id) by value 42 (entity 7)`, cv.Error())
+	_, ok = AsConstraintViolation(nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,339 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// Credentials authenticate the client: an API key, or a username and
+// password. APIKey wins when both are set.
+type Credentials struct {
+	APIKey   string
+	Username string
+	Password string
+	// ExpiresAt, when set, bounds how long a CredentialCache keeps the
+	// credentials (e.g. a Vault lease).
+	ExpiresAt time.Time
+}
+
+// CredentialProvider supplies credentials from outside the program, so
+// keys never have to be hardcoded in Config. Set Config.Credentials to
+// use one; the client wraps it in a CredentialCache unless it already is
+// one.
+type CredentialProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// CredentialProviderFunc adapts a function to CredentialProvider.
+type CredentialProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Retrieve calls f(ctx).
+func (f CredentialProviderFunc) Retrieve(ctx context.Context) (Credentials, error) { return f(ctx) }
+
+// EnvCredentials reads credentials from environment variables on every
+// Retrieve. Empty variable names default to NEXUS_API_KEY, NEXUS_USER and
+// NEXUS_PASSWORD.
+type EnvCredentials struct {
+	APIKeyVar   string
+	UsernameVar string
+	PasswordVar string
+}
+
+// Retrieve implements CredentialProvider.
+func (e EnvCredentials) Retrieve(context.Context) (Credentials, error) {
+	creds := Credentials{
+		APIKey:   os.Getenv(orDefault(e.APIKeyVar, "NEXUS_API_KEY")),
+		Username: os.Getenv(orDefault(e.UsernameVar, "NEXUS_USER")),
+		Password: os.Getenv(orDefault(e.PasswordVar, "NEXUS_PASSWORD")),
+	}
+	if err := creds.check(); err != nil {
+		return Credentials{}, fmt.Errorf("%w in the environment", err)
+	}
+	return creds, nil
+}
+
+// FileCredentials reads credentials from a file, such as a Kubernetes
+// secret mount that is updated in place on rotation. The file holds
+// either a JSON object with api_key / username / password fields or just
+// the API key.
+type FileCredentials struct {
+	Path string
+}
+
+// Retrieve implements CredentialProvider.
+func (f FileCredentials) Retrieve(context.Context) (Credentials, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("nexus: read credentials: %w", err)
+	}
+	creds, err := parseSecret(bytes.TrimSpace(data))
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%w in %s", err, f.Path)
+	}
+	return creds, nil
+}
+
+// VaultCredentials reads a secret from HashiCorp Vault over its HTTP
+// API. Both KV v1 and KV v2 mounts are supported; the secret's fields
+// are mapped to credentials by name.
+type VaultCredentials struct {
+	// Address is the Vault URL (default $VAULT_ADDR).
+	Address string
+	// Token authenticates to Vault (default $VAULT_TOKEN); TokenFunc, when
+	// set, is called instead, e.g. for Kubernetes or AppRole login.
+	Token     string
+	TokenFunc func(ctx context.Context) (string, error)
+	// Path is the secret path, e.g. "secret/data/nexus" for KV v2.
+	Path string
+	// Namespace sets X-Vault-Namespace (Vault Enterprise).
+	Namespace string
+	// APIKeyField / UsernameField / PasswordField name the secret fields
+	// (defaults api_key, username, password).
+	APIKeyField   string
+	UsernameField string
+	PasswordField string
+	HTTPClient    *http.Client
+}
+
+// Retrieve implements CredentialProvider.
+func (v *VaultCredentials) Retrieve(ctx context.Context) (Credentials, error) {
+	addr := orDefault(v.Address, os.Getenv("VAULT_ADDR"))
+	if addr == "" || v.Path == "" {
+		return Credentials{}, errors.New("nexus: Vault address and path must be set")
+	}
+	token := orDefault(v.Token, os.Getenv("VAULT_TOKEN"))
+	if v.TokenFunc != nil {
+		var err error
+		if token, err = v.TokenFunc(ctx); err != nil {
+			return Credentials{}, fmt.Errorf("nexus: Vault login: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(v.Path, "/"), nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+	var secret struct {
+		LeaseDuration int                    `json:"lease_duration"`
+		Data          map[string]interface{} `json:"data"`
+	}
+	if err := secretRequest(v.HTTPClient, req, &secret); err != nil {
+		return Credentials{}, fmt.Errorf("nexus: Vault read %s: %w", v.Path, err)
+	}
+	fields := secret.Data
+	if inner, ok := fields["data"].(map[string]interface{}); ok {
+		fields = inner // KV v2 nests the secret under data.data
+	}
+	creds := Credentials{
+		APIKey:   fieldString(fields, orDefault(v.APIKeyField, "api_key")),
+		Username: fieldString(fields, orDefault(v.UsernameField, "username")),
+		Password: fieldString(fields, orDefault(v.PasswordField, "password")),
+	}
+	if secret.LeaseDuration > 0 {
+		creds.ExpiresAt = time.Now().Add(time.Duration(secret.LeaseDuration) * time.Second)
+	}
+	if err := creds.check(); err != nil {
+		return Credentials{}, fmt.Errorf("%w in Vault secret %s", err, v.Path)
+	}
+	return creds, nil
+}
+
+// AWSSecretsManagerCredentials reads a secret from AWS Secrets Manager
+// with a SigV4-signed GetSecretValue call. The SecretString is either a
+// JSON object with api_key / username / password fields or just the API
+// key.
+type AWSSecretsManagerCredentials struct {
+	Region   string
+	SecretID string
+	// VersionStage defaults to AWSCURRENT.
+	VersionStage string
+	// Credentials supplies the AWS keys (default AWSCredentialsFromEnv).
+	Credentials func(ctx context.Context) (AWSCredentials, error)
+	// Endpoint overrides https://secretsmanager.<region>.amazonaws.com
+	// (VPC endpoints, LocalStack).
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// Retrieve implements CredentialProvider.
+func (a *AWSSecretsManagerCredentials) Retrieve(ctx context.Context) (Credentials, error) {
+	if a.Region == "" || a.SecretID == "" {
+		return Credentials{}, errors.New("nexus: Secrets Manager region and secret ID must be set")
+	}
+	endpoint := orDefault(a.Endpoint, "https://secretsmanager."+a.Region+".amazonaws.com")
+	body, err := json.Marshal(map[string]string{
+		"SecretId":     a.SecretID,
+		"VersionStage": orDefault(a.VersionStage, "AWSCURRENT"),
+	})
+	if err != nil {
+		return Credentials{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signer := &AWSSigV4Signer{Region: a.Region, Service: "secretsmanager", Credentials: a.Credentials}
+	if err := signer.SignRequest(req); err != nil {
+		return Credentials{}, err
+	}
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := secretRequest(a.HTTPClient, req, &out); err != nil {
+		return Credentials{}, fmt.Errorf("nexus: Secrets Manager read %s: %w", a.SecretID, err)
+	}
+	creds, err := parseSecret([]byte(strings.TrimSpace(out.SecretString)))
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%w in secret %s", err, a.SecretID)
+	}
+	return creds, nil
+}
+
+// CredentialCacheOptions configures a CredentialCache.
+type CredentialCacheOptions struct {
+	// TTL is how long fetched credentials are reused (default 5m); a
+	// sooner Credentials.ExpiresAt wins.
+	TTL time.Duration
+	// OnRotate is called after a refresh returns credentials that differ
+	// from the previous ones (not on the first fetch).
+	OnRotate func(old, new Credentials)
+}
+
+// CredentialCache caches a CredentialProvider so secret stores are not
+// hit on every request. It is safe for concurrent use.
+type CredentialCache struct {
+	provider CredentialProvider
+	opts     CredentialCacheOptions
+
+	mu      sync.Mutex
+	creds   Credentials
+	fetched bool
+	expiry  time.Time
+}
+
+// NewCredentialCache wraps provider.
+func NewCredentialCache(provider CredentialProvider, opts CredentialCacheOptions) *CredentialCache {
+	if opts.TTL <= 0 {
+		opts.TTL = 5 * time.Minute
+	}
+	return &CredentialCache{provider: provider, opts: opts}
+}
+
+// Retrieve returns the cached credentials, refreshing them when they
+// have expired.
+func (c *CredentialCache) Retrieve(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fetched && time.Now().Before(c.expiry) {
+		return c.creds, nil
+	}
+	creds, err := c.provider.Retrieve(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.expiry = time.Now().Add(c.opts.TTL)
+	if !creds.ExpiresAt.IsZero() && creds.ExpiresAt.Before(c.expiry) {
+		c.expiry = creds.ExpiresAt
+	}
+	old, rotated := c.creds, c.fetched && !creds.sameAs(c.creds)
+	c.creds, c.fetched = creds, true
+	if rotated && c.opts.OnRotate != nil {
+		c.opts.OnRotate(old, creds)
+	}
+	return creds, nil
+}
+
+// Invalidate forces the next Retrieve to refresh, e.g. after the server
+// rejected the cached key.
+func (c *CredentialCache) Invalidate() {
+	c.mu.Lock()
+	c.expiry = time.Time{}
+	c.mu.Unlock()
+}
+
+func (c Credentials) sameAs(o Credentials) bool {
+	return c.APIKey == o.APIKey && c.Username == o.Username && c.Password == o.Password
+}
+
+func (c Credentials) check() error {
+	if c.APIKey == "" && (c.Username == "" || c.Password == "") {
+		return errors.New("nexus: no API key or username/password found")
+	}
+	return nil
+}
+
+// credentialsSource adapts a provider to the transport hook.
+func credentialsSource(p CredentialProvider) transport.CredentialsSource {
+	return func(ctx context.Context) (transport.Credentials, error) {
+		creds, err := p.Retrieve(ctx)
+		if err != nil {
+			return transport.Credentials{}, err
+		}
+		return transport.Credentials{APIKey: creds.APIKey, Username: creds.Username, Password: creds.Password}, nil
+	}
+}
+
+// parseSecret reads a JSON credentials object or a bare API key.
+func parseSecret(data []byte) (Credentials, error) {
+	if len(data) > 0 && data[0] == '{' {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return Credentials{}, fmt.Errorf("nexus: decode credentials: %w", err)
+		}
+		creds := Credentials{
+			APIKey:   fieldString(fields, "api_key"),
+			Username: fieldString(fields, "username"),
+			Password: fieldString(fields, "password"),
+		}
+		return creds, creds.check()
+	}
+	creds := Credentials{APIKey: string(data)}
+	return creds, creds.check()
+}
+
+func secretRequest(client *http.Client, req *http.Request, out interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return decodeResponse(resp, out)
+}
+
+func fieldString(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
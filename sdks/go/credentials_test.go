@@ -0,0 +1,140 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("MY_NEXUS_KEY", "k1")
+	creds, err := EnvCredentials{APIKeyVar: "MY_NEXUS_KEY"}.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "k1", creds.APIKey)
+
+	t.Setenv("MY_NEXUS_KEY", "")
+	_, err = EnvCredentials{APIKeyVar: "MY_NEXUS_KEY", UsernameVar: "UNSET_USER"}.Retrieve(context.Background())
+	assert.ErrorContains(t, err, "no API key or username/password")
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("secret-key\n"), 0o600))
+	jsonFile := filepath.Join(dir, "creds.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"username":"ann","password":"pw"}`), 0o600))
+
+	creds, err := FileCredentials{Path: keyFile}.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "secret-key", creds.APIKey)
+
+	creds, err = FileCredentials{Path: jsonFile}.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "ann", Password: "pw"}, creds)
+}
+
+func TestVaultCredentialsKV2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/nexus", r.URL.Path)
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		assert.Equal(t, "team-a", r.Header.Get("X-Vault-Namespace"))
+		w.Write([]byte(`{"lease_duration":60,"data":{"data":{"key":"from-vault"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	v := &VaultCredentials{Address: server.URL, Token: "vault-token", Namespace: "team-a", Path: "secret/data/nexus", APIKeyField: "key"}
+	creds, err := v.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", creds.APIKey)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), creds.ExpiresAt, 5*time.Second)
+}
+
+func TestAWSSecretsManagerCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request")
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "prod/nexus", req["SecretId"])
+		assert.Equal(t, "AWSCURRENT", req["VersionStage"])
+		json.NewEncoder(w).Encode(map[string]string{"SecretString": `{"api_key":"from-sm"}`})
+	}))
+	defer server.Close()
+
+	a := &AWSSecretsManagerCredentials{
+		Region:   "eu-west-1",
+		SecretID: "prod/nexus",
+		Endpoint: server.URL,
+		Credentials: func(context.Context) (AWSCredentials, error) {
+			return AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		},
+	}
+	creds, err := a.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "from-sm", creds.APIKey)
+}
+
+func TestCredentialCacheRotation(t *testing.T) {
+	calls := 0
+	keys := []string{"k1", "k1", "k2"}
+	var rotated []string
+	cache := NewCredentialCache(CredentialProviderFunc(func(context.Context) (Credentials, error) {
+		key := keys[calls]
+		calls++
+		return Credentials{APIKey: key}, nil
+	}), CredentialCacheOptions{OnRotate: func(old, new Credentials) {
+		rotated = append(rotated, old.APIKey+"->"+new.APIKey)
+	}})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		creds, err := cache.Retrieve(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "k1", creds.APIKey)
+	}
+	assert.Equal(t, 1, calls)
+
+	cache.Invalidate()
+	cache.Retrieve(ctx)
+	cache.Invalidate()
+	creds, _ := cache.Retrieve(ctx)
+	assert.Equal(t, "k2", creds.APIKey)
+	assert.Equal(t, []string{"k1->k2"}, rotated)
+}
+
+func TestClientCredentialsProvider(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("X-API-Key"))
+		if strings.HasPrefix(r.URL.Path, "/webhooks") {
+			w.Write([]byte(`{"webhooks":[]}`))
+			return
+		}
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	key := "k1"
+	cache := NewCredentialCache(CredentialProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{APIKey: key}, nil
+	}), CredentialCacheOptions{})
+	client := NewClient(Config{BaseURL: server.URL, Credentials: cache})
+
+	ctx := context.Background()
+	_, err := client.ExecuteCypher(ctx, "RETURN 1", nil)
+	require.NoError(t, err)
+	key = "k2"
+	cache.Invalidate()
+	_, err = client.ListWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k1", "k2"}, keys)
+}
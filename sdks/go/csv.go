@@ -0,0 +1,127 @@
+package nexus
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// CSVOptions configures QueryResult.WriteCSV and QueryIterator.WriteCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter (default ','); use '\t' for TSV.
+	Comma rune
+	// NoHeader omits the header row of column names.
+	NoHeader bool
+	// Flatten spreads node, relationship and map cells over one column
+	// per key ("n.name", "n._id", "n._labels"), with the keys inferred
+	// from the rows (the first page, for an iterator). Without it such
+	// cells are written as JSON.
+	Flatten bool
+	// Null is written for null cells (default empty).
+	Null string
+	// UseCRLF ends lines with \r\n.
+	UseCRLF bool
+}
+
+// WriteCSV writes the result to w as CSV (or TSV, see CSVOptions.Comma)
+// with a header row. Fields are quoted as RFC 4180 requires.
+func (qr *QueryResult) WriteCSV(w io.Writer, opts CSVOptions) error {
+	result := qr
+	if opts.Flatten {
+		var err error
+		if result, err = qr.Transform(flattenColumns(flattenMaps)); err != nil {
+			return err
+		}
+	}
+	cw := newCSVWriter(w, opts)
+	if !opts.NoHeader {
+		if err := cw.Write(result.Columns); err != nil {
+			return err
+		}
+	}
+	for _, row := range result.Rows {
+		if err := cw.Write(csvRecord(row, opts.Null)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCSV streams the remaining rows to w like QueryResult.WriteCSV
+// and returns the number of rows written. Call it on a fresh iterator:
+// Flatten adds a transformer, which must be in place before the first
+// Next.
+func (it *QueryIterator) WriteCSV(w io.Writer, opts CSVOptions) (int, error) {
+	if opts.Flatten {
+		it.Transform(flattenColumns(flattenMaps))
+	}
+	cw := newCSVWriter(w, opts)
+	header := !opts.NoHeader
+	n := 0
+	for it.Next() {
+		if header {
+			if err := cw.Write(it.Columns()); err != nil {
+				return n, err
+			}
+			header = false
+		}
+		if err := cw.Write(csvRecord(it.Row(), opts.Null)); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := it.Err(); err != nil {
+		return n, err
+	}
+	if header && it.Columns() != nil {
+		if err := cw.Write(it.Columns()); err != nil {
+			return n, err
+		}
+	}
+	cw.Flush()
+	return n, cw.Error()
+}
+
+func newCSVWriter(w io.Writer, opts CSVOptions) *csv.Writer {
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	cw.UseCRLF = opts.UseCRLF
+	return cw
+}
+
+func csvRecord(row []interface{}, null string) []string {
+	cells := make([]string, len(row))
+	for i, v := range row {
+		if v == nil {
+			cells[i] = null
+			continue
+		}
+		cells[i] = csvCell(v)
+	}
+	return cells
+}
+
+// csvCell renders scalars as-is and composite values as JSON. Floats
+// are written without exponents so spreadsheets read them back.
+func csvCell(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprint(x)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,56 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryResultWriteCSV(t *testing.T) {
+	qr := &QueryResult{
+		Columns: []string{"n", "note", "meta"},
+		Rows: [][]interface{}{
+			{map[string]interface{}{"id": int64(1), "labels": []interface{}{"Person"}, "properties": map[string]interface{}{"name": "Ann"}}, "says \"hi\", twice", map[string]interface{}{"a": int64(1)}},
+			{nil, 1e21, nil},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, qr.WriteCSV(&buf, CSVOptions{}))
+	assert.Equal(t, "n,note,meta\n"+
+		`"{""id"":1,""labels"":[""Person""],""properties"":{""name"":""Ann""}}","says ""hi"", twice","{""a"":1}"`+"\n"+
+		",1000000000000000000000,\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, qr.WriteCSV(&buf, CSVOptions{Comma: '\t', Flatten: true, Null: `\N`}))
+	assert.Equal(t, "n._id\tn._labels\tn.name\tnote\tmeta.a\n"+
+		"1\t\"[\"\"Person\"\"]\"\tAnn\t\"says \"\"hi\"\", twice\"\t1\n"+
+		"\\N\t\\N\t\\N\t1000000000000000000000\t\\N\n", buf.String())
+}
+
+func TestQueryIteratorWriteCSV(t *testing.T) {
+	server := pagedServer(t, 3)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	it, err := client.IterateQuery(context.Background(), "MATCH (n) RETURN id(n) AS id, properties(n) AS props ORDER BY id", nil, QueryIteratorOptions{PageSize: 2})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	n, err := it.WriteCSV(&buf, CSVOptions{Flatten: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "id,props.k\n0,0\n1,1\n2,2\n", buf.String())
+
+	server0 := pagedServer(t, 0)
+	defer server0.Close()
+	it, err = NewClient(Config{BaseURL: server0.URL}).IterateQuery(context.Background(), "MATCH (n) RETURN n ORDER BY id(n)", nil, QueryIteratorOptions{})
+	require.NoError(t, err)
+	buf.Reset()
+	n, err = it.WriteCSV(&buf, CSVOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, "id,props\n", buf.String())
+}
@@ -0,0 +1,152 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Direction is the way relationships are followed from a node.
+type Direction int
+
+// Directions. The zero value follows outgoing relationships.
+const (
+	DirectionOutgoing Direction = iota
+	DirectionIncoming
+	DirectionBoth
+)
+
+// pattern returns the relationship pattern from (a) to (b), binding
+// the relationship to variable and restricting it to types when they
+// are not empty.
+func (d Direction) pattern(variable string, types []string) string {
+	rel := "--"
+	if variable != "" || len(types) > 0 {
+		rel = "-[" + variable
+		if len(types) > 0 {
+			rel += ":" + strings.Join(types, "|")
+		}
+		rel += "]-"
+	}
+	switch d {
+	case DirectionIncoming:
+		return "(a)<" + rel + "(b)"
+	case DirectionBoth:
+		return "(a)" + rel + "(b)"
+	}
+	return "(a)" + rel + ">(b)"
+}
+
+// DeleteSpec selects the subgraph DeleteSubtree removes.
+type DeleteSpec struct {
+	// RelTypes are the relationship types followed from the root; empty
+	// follows every type.
+	RelTypes []string
+	// Direction is the way relationships are followed (default
+	// outgoing, i.e. the root's descendants).
+	Direction Direction
+	// MaxDepth bounds the number of hops from the root; 0 means no
+	// limit.
+	MaxDepth int
+	// BatchSize is the number of nodes expanded or deleted per
+	// statement (default 1000).
+	BatchSize int
+}
+
+// DeleteSubtreeResult reports what DeleteSubtree removed.
+type DeleteSubtreeResult struct {
+	// Nodes is the number of nodes deleted, the root included.
+	Nodes int64
+	// Depth is the deepest level reached below the root.
+	Depth int
+	// Batches is the number of delete statements run.
+	Batches int
+}
+
+// DeleteSubtree deletes rootID and every node reachable from it along
+// spec's relationships, deepest level first and at most BatchSize nodes
+// per statement, so a large hierarchy never needs one DETACH DELETE big
+// enough to time out:
+//
+//	res, err := client.DeleteSubtree(ctx, folderID, nexus.DeleteSpec{RelTypes: []string{"CONTAINS"}})
+//
+// Nodes reachable from the root are deleted even when something outside
+// the subtree also points at them; their other relationships go with
+// them. Batches are separate statements: a failure leaves the deeper
+// levels already deleted and the root in place, so the call can be
+// repeated.
+func (c *Client) DeleteSubtree(ctx context.Context, rootID string, spec DeleteSpec) (*DeleteSubtreeResult, error) {
+	root, err := parseID(rootID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range spec.RelTypes {
+		if err := validLabelIdentifier(t); err != nil {
+			return nil, err
+		}
+	}
+	if spec.BatchSize <= 0 {
+		spec.BatchSize = 1000
+	}
+	expand := "UNWIND $ids AS id MATCH " + spec.Direction.pattern("", spec.RelTypes) +
+		" WHERE id(a) = id RETURN DISTINCT id(b) AS id"
+
+	// Walk the subgraph level by level; seen keeps cycles and shared
+	// descendants from being visited twice.
+	result := &DeleteSubtreeResult{}
+	levels := [][]int64{{root}}
+	seen := map[int64]bool{root: true}
+	for spec.MaxDepth <= 0 || len(levels) <= spec.MaxDepth {
+		var next []int64
+		for _, batch := range chunkIDs(levels[len(levels)-1], spec.BatchSize) {
+			res, err := c.ExecuteCypher(ctx, expand, map[string]interface{}{"ids": batch})
+			if err != nil {
+				return result, fmt.Errorf("nexus: delete subtree: expand level %d: %w", len(levels), err)
+			}
+			for _, row := range res.Rows {
+				if len(row) == 0 {
+					continue
+				}
+				id := int64(asInt(row[0]))
+				if !seen[id] {
+					seen[id] = true
+					next = append(next, id)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		levels = append(levels, next)
+	}
+	result.Depth = len(levels) - 1
+
+	for depth := len(levels) - 1; depth >= 0; depth-- {
+		for _, batch := range chunkIDs(levels[depth], spec.BatchSize) {
+			res, err := c.ExecuteCypher(ctx,
+				"UNWIND $ids AS id MATCH (n) WHERE id(n) = id DETACH DELETE n RETURN count(*) AS deleted",
+				map[string]interface{}{"ids": batch})
+			if err != nil {
+				return result, fmt.Errorf("nexus: delete subtree: delete level %d: %w", depth, err)
+			}
+			result.Batches++
+			if len(res.Rows) > 0 && len(res.Rows[0]) > 0 {
+				result.Nodes += int64(asInt(res.Rows[0][0]))
+			}
+		}
+	}
+	return result, nil
+}
+
+// chunkIDs splits ids into slices of at most size.
+func chunkIDs(ids []int64, size int) [][]int64 {
+	var chunks [][]int64
+	for len(ids) > size {
+		chunks = append(chunks, ids[:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+	return chunks
+}
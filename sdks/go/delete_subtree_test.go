@@ -0,0 +1,59 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteSubtree(t *testing.T) {
+	// 1 → 2, 3; 2 → 4; 3 → 4; 4 → 1 (a cycle back to the root).
+	children := map[float64][]float64{1: {2, 3}, 2: {4}, 3: {4}, 4: {1}}
+	var expands []string
+	var deleted [][]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		ids := req.Parameters["ids"].([]interface{})
+		rows := [][]interface{}{}
+		if strings.Contains(req.Query, "DETACH DELETE") {
+			deleted = append(deleted, ids)
+			rows = append(rows, []interface{}{len(ids)})
+		} else {
+			expands = append(expands, req.Query)
+			for _, id := range ids {
+				for _, child := range children[id.(float64)] {
+					rows = append(rows, []interface{}{child})
+				}
+			}
+		}
+		writeJSON(w, map[string]interface{}{"columns": []string{"id"}, "rows": rows})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	res, err := client.DeleteSubtree(ctx, "1", DeleteSpec{RelTypes: []string{"CONTAINS", "LINKS"}, BatchSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, &DeleteSubtreeResult{Nodes: 4, Depth: 2, Batches: 4}, res)
+	assert.Equal(t, [][]interface{}{{float64(4)}, {float64(2)}, {float64(3)}, {float64(1)}}, deleted)
+	assert.Equal(t, "UNWIND $ids AS id MATCH (a)-[:CONTAINS|LINKS]->(b) WHERE id(a) = id RETURN DISTINCT id(b) AS id", expands[0])
+
+	deleted, expands = nil, nil
+	res, err = client.DeleteSubtree(ctx, "1", DeleteSpec{Direction: DirectionIncoming, MaxDepth: 1})
+	require.NoError(t, err)
+	assert.Equal(t, &DeleteSubtreeResult{Nodes: 3, Depth: 1, Batches: 2}, res)
+	assert.Equal(t, [][]interface{}{{float64(2), float64(3)}, {float64(1)}}, deleted)
+	assert.Equal(t, []string{"UNWIND $ids AS id MATCH (a)<--(b) WHERE id(a) = id RETURN DISTINCT id(b) AS id"}, expands)
+
+	_, err = client.DeleteSubtree(ctx, "1", DeleteSpec{RelTypes: []string{"BAD TYPE"}})
+	assert.Error(t, err)
+	_, err = client.DeleteSubtree(ctx, "x", DeleteSpec{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package nexus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Change kinds reported in a DiffEntry.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Entity kinds reported in a DiffEntry.
+const (
+	EntityNode         = "node"
+	EntityRelationship = "relationship"
+)
+
+// DiffOptions narrows a snapshot diff.
+type DiffOptions struct {
+	// Labels restricts node entries to these labels (empty = all).
+	Labels []string `json:"labels,omitempty"`
+	// RelationshipTypes restricts relationship entries (empty = all).
+	RelationshipTypes []string `json:"relationship_types,omitempty"`
+	// IgnoreProperties lists property keys whose changes are not
+	// reported (timestamps, counters, …).
+	IgnoreProperties []string `json:"ignore_properties,omitempty"`
+	// SkipRelationships limits the diff to nodes.
+	SkipRelationships bool `json:"skip_relationships,omitempty"`
+}
+
+// DiffEntry is one added, removed, or changed entity.
+type DiffEntry struct {
+	Entity string `json:"entity"`
+	Change string `json:"change"`
+	ID     string `json:"id"`
+	// Labels is set for node entries.
+	Labels []string `json:"labels,omitempty"`
+	// Type, StartNode and EndNode are set for relationship entries.
+	Type      string `json:"type,omitempty"`
+	StartNode string `json:"start_node,omitempty"`
+	EndNode   string `json:"end_node,omitempty"`
+	// Before is nil for added entities, After is nil for removed ones.
+	Before map[string]interface{} `json:"before,omitempty"`
+	After  map[string]interface{} `json:"after,omitempty"`
+	// ChangedKeys lists the differing property keys of changed entities.
+	ChangedKeys []string `json:"changed_keys,omitempty"`
+}
+
+// DiffStream iterates the entries of a snapshot diff as they arrive.
+// Always Close the stream, even after Next returned false.
+//
+//	stream, err := client.DiffSnapshots(ctx, "release-41", "release-42", nexus.DiffOptions{})
+//	if err != nil { … }
+//	defer stream.Close()
+//	for stream.Next() {
+//	    e := stream.Entry()
+//	    …
+//	}
+//	if err := stream.Err(); err != nil { … }
+type DiffStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	entry   DiffEntry
+	err     error
+}
+
+// DiffSnapshots streams the differences between snapshots a and b
+// (POST /snapshots/diff). Pass an empty name to compare against the
+// live graph. Config.Timeout does not apply to the stream; only ctx
+// bounds it.
+func (c *Client) DiffSnapshots(ctx context.Context, a, b string, opts DiffOptions) (*DiffStream, error) {
+	if a == "" && b == "" {
+		return nil, errors.New("nexus: at least one side of a diff must name a snapshot")
+	}
+	reqBody := struct {
+		A string `json:"a"`
+		B string `json:"b"`
+		DiffOptions
+	}{A: a, B: b, DiffOptions: opts}
+
+	resp, err := c.doStream(ctx, http.MethodPost, "/snapshots/diff", reqBody)
+	if err != nil {
+		return nil, err
+	}
+	return &DiffStream{body: resp.Body, scanner: newJSONLineScanner(resp.Body)}, nil
+}
+
+// Next advances to the next entry, returning false at the end of the
+// stream or on error.
+func (s *DiffStream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	for s.scanner.Scan() {
+		line := bytes.TrimSpace(s.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		s.entry = DiffEntry{}
+		if err := json.Unmarshal(line, &s.entry); err != nil {
+			s.err = fmt.Errorf("failed to decode diff entry: %w", err)
+			return false
+		}
+		return true
+	}
+	if err := s.scanner.Err(); err != nil {
+		s.err = fmt.Errorf("failed to read stream: %w", err)
+	}
+	return false
+}
+
+// Entry returns the current entry.
+func (s *DiffStream) Entry() DiffEntry { return s.entry }
+
+// Err returns the first error encountered while streaming.
+func (s *DiffStream) Err() error { return s.err }
+
+// Close releases the underlying response body.
+func (s *DiffStream) Close() error { return s.body.Close() }
@@ -0,0 +1,104 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDryRun is returned in dry-run mode for writes that cannot be
+// rehearsed and are skipped instead: entity REST writes and schema
+// changes.
+var ErrDryRun = errors.New("nexus: write skipped in dry-run mode")
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context whose requests run in dry-run mode (on)
+// or normally (off), overriding Config.DryRun:
+//
+//	result, err := client.ExecuteCypher(nexus.WithDryRun(ctx, true), migration, nil)
+//	fmt.Println(result.Stats.NodesDeleted, "nodes would be deleted")
+//
+// In dry-run mode, Cypher writes run in a transaction that is rolled
+// back (inside a Transaction, under a savepoint that is rolled back),
+// so the result and its Stats are those the write would have had but
+// no data changes. Schema changes and entity REST writes (CreateNode,
+// UpdateNode, …) return ErrDryRun without being sent. Reads run
+// normally.
+func WithDryRun(ctx context.Context, on bool) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, on)
+}
+
+// isDryRun reports whether ctx's requests run in dry-run mode.
+func (c *Client) isDryRun(ctx context.Context) bool {
+	if on, ok := ctx.Value(dryRunKey{}).(bool); ok {
+		return on
+	}
+	return c.dryRun
+}
+
+// rehearsable reports whether query must be rehearsed rather than run
+// in dry-run mode; schema changes are ErrDryRun.
+func rehearsable(query string) (bool, error) {
+	a := AnalyzeQuery(query)
+	switch {
+	case a.Operation == OperationSchema:
+		return false, fmt.Errorf("%w: %s", ErrDryRun, query)
+	case a.Operation == OperationWrite || len(a.Procedures) > 0:
+		// Procedures may write; rolling back is harmless if they do not.
+		return true, nil
+	}
+	return false, nil
+}
+
+// dryRunOr runs query with run, or, in dry-run mode, rehearses writes
+// in a transaction that is rolled back.
+func (c *Client) dryRunOr(ctx context.Context, query string, params map[string]interface{}, run func(context.Context, string, map[string]interface{}) (*QueryResult, error)) (*QueryResult, error) {
+	if !c.isDryRun(ctx) {
+		return run(ctx, query, params)
+	}
+	rehearse, err := rehearsable(query)
+	if err != nil {
+		return nil, err
+	}
+	if !rehearse {
+		return run(ctx, query, params)
+	}
+	tx, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rehearseIn(ctx, tx, query, params)
+}
+
+// dryRunOr is Client.dryRunOr inside a transaction: writes are
+// rehearsed under a savepoint.
+func (tx *Transaction) dryRunOr(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
+	if !tx.client.isDryRun(ctx) {
+		return tx.execute(ctx, query, params)
+	}
+	rehearse, err := rehearsable(query)
+	if err != nil {
+		return nil, err
+	}
+	if !rehearse {
+		return tx.execute(ctx, query, params)
+	}
+	nested, err := tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rehearseIn(ctx, nested, query, params)
+}
+
+// rehearseIn runs query in tx and rolls tx back.
+func rehearseIn(ctx context.Context, tx *Transaction, query string, params map[string]interface{}) (*QueryResult, error) {
+	result, err := tx.execute(ctx, query, params)
+	if rbErr := tx.Rollback(ctx); rbErr != nil {
+		if err == nil {
+			return nil, fmt.Errorf("nexus: dry run: rollback failed, the write may have been applied: %w", rbErr)
+		}
+		return nil, fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+	}
+	return result, err
+}
@@ -0,0 +1,69 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	var log []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		entry := r.URL.Path
+		if q, ok := body["query"].(string); ok {
+			entry += " " + q
+		}
+		log = append(log, entry)
+		switch r.URL.Path {
+		case "/transaction/begin":
+			json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx"})
+		default:
+			w.Write([]byte(`{"columns":[],"rows":[],"stats":{"nodes_deleted":3}}`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	client := NewClient(Config{BaseURL: server.URL, DryRun: true})
+	result, err := client.ExecuteCypher(ctx, "MATCH (n:Stale) DETACH DELETE n", nil)
+	require.NoError(t, err)
+	require.NotNil(t, result.Stats)
+	assert.Equal(t, 3, result.Stats.NodesDeleted)
+	_, err = client.ExecuteCypher(ctx, "MATCH (n) RETURN n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/transaction/begin",
+		"/transaction/execute MATCH (n:Stale) DETACH DELETE n",
+		"/transaction/rollback",
+		"/cypher MATCH (n) RETURN n",
+	}, log)
+
+	log = nil
+	_, err = client.ExecuteCypher(ctx, "CREATE INDEX ON :Person(name)", nil)
+	assert.ErrorIs(t, err, ErrDryRun)
+	_, err = client.CreateNode(ctx, []string{"Person"}, map[string]interface{}{"name": "Ann"})
+	assert.ErrorIs(t, err, ErrDryRun)
+	assert.Empty(t, log)
+
+	_, err = client.ExecuteCypher(WithDryRun(ctx, false), "CREATE (n)", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/cypher CREATE (n)"}, log)
+
+	log = nil
+	tx := &Transaction{client: NewClient(Config{BaseURL: server.URL}), id: "tx"}
+	_, err = tx.ExecuteCypher(WithDryRun(ctx, true), "CREATE (n)", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/transaction/execute SAVEPOINT nexus_sp_1",
+		"/transaction/execute CREATE (n)",
+		"/transaction/execute ROLLBACK TO SAVEPOINT nexus_sp_1",
+		"/transaction/execute RELEASE SAVEPOINT nexus_sp_1",
+	}, log)
+}
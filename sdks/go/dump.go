@@ -0,0 +1,542 @@
+package nexus
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DumpFormat and DumpVersion identify the archive written by Dump.
+//
+// A dump is a gzip-compressed tar archive holding, in this order:
+//
+//	schema.json                  DumpSchema: format, version, labels, relationship types, indexes
+//	nodes/000000.ndjson …        one DumpNode per line, DumpChunkSize per file
+//	relationships/000000.ndjson  one DumpRelationship per line, DumpChunkSize per file
+//	manifest.json                DumpManifest: totals and chunk names
+//
+// Every node chunk precedes every relationship chunk, so a loader can
+// stream the archive once. A dump without its trailing manifest.json is
+// truncated.
+const (
+	DumpFormat  = "nexus-dump"
+	DumpVersion = 1
+)
+
+// DumpChunkSize is the number of records per NDJSON chunk in a dump.
+const DumpChunkSize = 10000
+
+// DumpSchema is the first entry of a dump.
+type DumpSchema struct {
+	Format            string    `json:"format"`
+	Version           int       `json:"version"`
+	CreatedAt         time.Time `json:"created_at"`
+	Labels            []string  `json:"labels"`
+	RelationshipTypes []string  `json:"relationship_types"`
+	Indexes           []Index   `json:"indexes"`
+}
+
+// DumpNode is one line of a node chunk. ID is the node's ID on the
+// source server; it is only used to wire up relationships on load.
+type DumpNode struct {
+	ID         int64                  `json:"id"`
+	Labels     []string               `json:"labels"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// DumpRelationship is one line of a relationship chunk. Start and End
+// are source node IDs.
+type DumpRelationship struct {
+	ID         int64                  `json:"id"`
+	Type       string                 `json:"type"`
+	Start      int64                  `json:"start"`
+	End        int64                  `json:"end"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// DumpManifest is the last entry of a dump.
+type DumpManifest struct {
+	Nodes         int      `json:"nodes"`
+	Relationships int      `json:"relationships"`
+	Chunks        []string `json:"chunks"`
+}
+
+// LoadReport counts what Load wrote.
+type LoadReport struct {
+	// Nodes and Relationships count created records; the Updated
+	// counts are records an IDMapping already knew (see LoadOptions).
+	Nodes                int
+	NodesUpdated         int
+	Relationships        int
+	RelationshipsUpdated int
+	Indexes              int
+	// Dangling counts relationships skipped because an endpoint was not
+	// in the dump, which happens when the graph changed while it was
+	// being dumped.
+	Dangling int
+}
+
+// Dump writes the whole graph to w as a compressed archive (see
+// DumpFormat) that Load can replay into another server:
+//
+//	f, _ := os.Create("graph.nexus.tgz")
+//	manifest, err := client.Dump(ctx, f)
+//
+// Nodes and relationships are paged by ID, so memory stays bounded by
+// one chunk. The dump is not a point-in-time copy: quiesce writes while
+// it runs, or relationships created meanwhile may reference nodes it
+// missed (Load skips them and reports them as dangling).
+func (c *Client) Dump(ctx context.Context, w io.Writer) (*DumpManifest, error) {
+	schema := DumpSchema{Format: DumpFormat, Version: DumpVersion, CreatedAt: time.Now().UTC()}
+	labels, err := c.ListLabels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: dump labels: %w", err)
+	}
+	for _, l := range labels {
+		schema.Labels = append(schema.Labels, l.Name)
+	}
+	types, err := c.ListRelationshipTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: dump relationship types: %w", err)
+	}
+	for _, t := range types {
+		schema.RelationshipTypes = append(schema.RelationshipTypes, t.Name)
+	}
+	if schema.Indexes, err = c.ListIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("nexus: dump indexes: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifest := &DumpManifest{}
+	if err := writeDumpEntry(tw, "schema.json", schema); err != nil {
+		return nil, err
+	}
+
+	nodes, err := c.IterateQuery(ctx,
+		"MATCH (n) WHERE $__after IS NULL OR id(n) > $__after "+
+			"RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props ORDER BY id",
+		nil, QueryIteratorOptions{KeyColumn: "id"})
+	if err != nil {
+		return nil, err
+	}
+	manifest.Nodes, err = writeDumpChunks(tw, manifest, "nodes", nodes, func(row []interface{}) interface{} {
+		n := DumpNode{ID: int64(asInt(row[0])), Labels: []string{}}
+		for _, l := range asSlice(row[1]) {
+			if s, ok := l.(string); ok {
+				n.Labels = append(n.Labels, s)
+			}
+		}
+		n.Properties, _ = row[2].(map[string]interface{})
+		return n
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rels, err := c.IterateQuery(ctx,
+		"MATCH (a)-[r]->(b) WHERE $__after IS NULL OR id(r) > $__after "+
+			"RETURN id(r) AS id, type(r) AS type, id(a) AS start, id(b) AS end, properties(r) AS props ORDER BY id",
+		nil, QueryIteratorOptions{KeyColumn: "id"})
+	if err != nil {
+		return nil, err
+	}
+	manifest.Relationships, err = writeDumpChunks(tw, manifest, "relationships", rels, func(row []interface{}) interface{} {
+		r := DumpRelationship{ID: int64(asInt(row[0])), Start: int64(asInt(row[2])), End: int64(asInt(row[3]))}
+		r.Type, _ = row[1].(string)
+		r.Properties, _ = row[4].(map[string]interface{})
+		return r
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeDumpEntry(tw, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("nexus: dump: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("nexus: dump: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeDumpChunks drains it into DumpChunkSize-line NDJSON entries
+// named dir/NNNNNN.ndjson and returns the number of records.
+func writeDumpChunks(tw *tar.Writer, manifest *DumpManifest, dir string, it *QueryIterator, record func([]interface{}) interface{}) (int, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	count, lines, chunk := 0, 0, 0
+	flush := func() error {
+		if lines == 0 {
+			return nil
+		}
+		name := fmt.Sprintf("%s/%06d.ndjson", dir, chunk)
+		chunk++
+		if err := writeDumpFile(tw, name, buf.Bytes()); err != nil {
+			return err
+		}
+		manifest.Chunks = append(manifest.Chunks, name)
+		buf.Reset()
+		lines = 0
+		return nil
+	}
+	for it.Next() {
+		if err := enc.Encode(record(it.Row())); err != nil {
+			return count, fmt.Errorf("nexus: dump %s: %w", dir, err)
+		}
+		count++
+		if lines++; lines == DumpChunkSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return count, fmt.Errorf("nexus: dump %s: %w", dir, err)
+	}
+	return count, flush()
+}
+
+func writeDumpEntry(tw *tar.Writer, name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("nexus: dump %s: %w", name, err)
+	}
+	return writeDumpFile(tw, name, data)
+}
+
+func writeDumpFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("nexus: dump %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("nexus: dump %s: %w", name, err)
+	}
+	return nil
+}
+
+// LoadOptions configures LoadWithOptions.
+type LoadOptions struct {
+	// IDMapping is the source → target ID table. Source IDs it already
+	// knows are updated in place (properties merged, labels added)
+	// instead of created again, and relationships resolve endpoints
+	// loaded by earlier runs. A persisted mapping (OpenFileIDMapping)
+	// therefore makes repeated loads of the same source idempotent and
+	// lets partial dumps be merged into one graph. Nil keeps the
+	// mapping in memory for this load only.
+	IDMapping IDMapping
+}
+
+// Load replays a dump written by Dump into this server: nodes are
+// created in batches grouped by label set, relationships are wired up
+// through the new IDs, and the dumped indexes are created last unless
+// an index of the same name exists.
+//
+// Load is not atomic: a failure part way leaves what was written so
+// far. Every run creates new nodes; use LoadWithOptions with a
+// persisted IDMapping to load the same source more than once.
+func (c *Client) Load(ctx context.Context, r io.Reader) (*LoadReport, error) {
+	return c.LoadWithOptions(ctx, r, LoadOptions{})
+}
+
+// LoadWithOptions is Load with a caller-supplied ID mapping (see
+// LoadOptions). Pairs are recorded after each batch is written, so an
+// interrupted load can at worst duplicate the batch in flight.
+func (c *Client) LoadWithOptions(ctx context.Context, r io.Reader, opts LoadOptions) (*LoadReport, error) {
+	ids := opts.IDMapping
+	if ids == nil {
+		ids = NewMemoryIDMapping()
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: load: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	report := &LoadReport{}
+	var schema *DumpSchema
+	var manifest *DumpManifest
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, fmt.Errorf("nexus: load: %w", err)
+		}
+		if schema == nil && hdr.Name != "schema.json" {
+			return report, fmt.Errorf("nexus: load: not a %s archive (first entry is %q)", DumpFormat, hdr.Name)
+		}
+		switch {
+		case hdr.Name == "schema.json":
+			schema = &DumpSchema{}
+			if err := json.NewDecoder(tr).Decode(schema); err != nil {
+				return report, fmt.Errorf("nexus: load schema.json: %w", err)
+			}
+			if schema.Format != DumpFormat || schema.Version > DumpVersion {
+				return report, fmt.Errorf("nexus: load: unsupported dump %s v%d", schema.Format, schema.Version)
+			}
+		case strings.HasPrefix(hdr.Name, "nodes/"):
+			err = eachDumpLine(tr, func(batch []*DumpNode) error {
+				return c.loadNodes(ctx, batch, ids, report)
+			})
+		case strings.HasPrefix(hdr.Name, "relationships/"):
+			err = eachDumpLine(tr, func(batch []*DumpRelationship) error {
+				return c.loadRelationships(ctx, batch, ids, report)
+			})
+		case hdr.Name == "manifest.json":
+			manifest = &DumpManifest{}
+			err = json.NewDecoder(tr).Decode(manifest)
+		}
+		if err != nil {
+			return report, fmt.Errorf("nexus: load %s: %w", hdr.Name, err)
+		}
+	}
+	if manifest == nil {
+		return report, errors.New("nexus: load: dump is truncated (no manifest.json)")
+	}
+	nodes := report.Nodes + report.NodesUpdated
+	rels := report.Relationships + report.RelationshipsUpdated + report.Dangling
+	if nodes != manifest.Nodes || rels != manifest.Relationships {
+		return report, fmt.Errorf("nexus: load: dump is incomplete: loaded %d nodes and %d relationships, manifest lists %d and %d",
+			nodes, rels, manifest.Nodes, manifest.Relationships)
+	}
+
+	existing, err := c.ListIndexes(ctx)
+	if err != nil {
+		return report, fmt.Errorf("nexus: load indexes: %w", err)
+	}
+	have := map[string]bool{}
+	for _, idx := range existing {
+		have[idx.Name] = true
+	}
+	for _, idx := range schema.Indexes {
+		if have[idx.Name] {
+			continue
+		}
+		if err := c.CreateIndex(ctx, idx.Name, idx.Label, idx.Properties); err != nil {
+			return report, fmt.Errorf("nexus: load index %s: %w", idx.Name, err)
+		}
+		report.Indexes++
+	}
+	return report, nil
+}
+
+// loadBatchSize is the number of records per UNWIND statement on load.
+const loadBatchSize = 1000
+
+// eachDumpLine decodes an NDJSON chunk and hands it to fn in batches of
+// loadBatchSize.
+func eachDumpLine[T any](r io.Reader, fn func([]*T) error) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var batch []*T
+	for dec.More() {
+		rec := new(T)
+		if err := dec.Decode(rec); err != nil {
+			return err
+		}
+		batch = append(batch, rec)
+		if len(batch) == loadBatchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return fn(batch)
+}
+
+// loadNodes writes batch, one statement per label set: nodes ids
+// already maps are updated where they still exist, the rest are created
+// and their new IDs recorded.
+func (c *Client) loadNodes(ctx context.Context, batch []*DumpNode, ids IDMapping, report *LoadReport) error {
+	sources := make([]int64, len(batch))
+	for i, n := range batch {
+		sources[i] = n.ID
+	}
+	known, err := ids.Lookup(ctx, IDKindNode, sources)
+	if err != nil {
+		return err
+	}
+	updates, creates := map[string][]interface{}{}, map[string][]interface{}{}
+	for _, n := range batch {
+		for _, l := range n.Labels {
+			if err := validLabelIdentifier(l); err != nil {
+				return err
+			}
+		}
+		labels := append([]string(nil), n.Labels...)
+		sort.Strings(labels)
+		key := strings.Join(labels, ":")
+		row := map[string]interface{}{"id": n.ID, "props": propsFromJSON(n.Properties)}
+		if target, ok := known[n.ID]; ok {
+			row["target"] = target
+			updates[key] = append(updates[key], row)
+		} else {
+			creates[key] = append(creates[key], row)
+		}
+	}
+
+	for _, key := range sortedKeys(updates) {
+		set := "n += row.props"
+		if key != "" {
+			set += ", n:" + key
+		}
+		result, err := c.ExecuteCypher(ctx,
+			"UNWIND $rows AS row MATCH (n) WHERE id(n) = row.target SET "+set+" RETURN row.id AS old",
+			map[string]interface{}{"rows": updates[key]})
+		if err != nil {
+			return err
+		}
+		updated := returnedIDs(result)
+		report.NodesUpdated += len(updated)
+		// Mapped nodes deleted on the target since are created afresh.
+		for _, row := range updates[key] {
+			if !updated[row.(map[string]interface{})["id"].(int64)] {
+				creates[key] = append(creates[key], row)
+			}
+		}
+	}
+
+	recorded := map[int64]int64{}
+	for _, key := range sortedKeys(creates) {
+		pattern := "(n)"
+		if key != "" {
+			pattern = "(n:" + key + ")"
+		}
+		result, err := c.ExecuteCypher(ctx,
+			"UNWIND $rows AS row CREATE "+pattern+" SET n += row.props RETURN row.id AS old, id(n) AS id",
+			map[string]interface{}{"rows": creates[key]})
+		if err != nil {
+			return err
+		}
+		for _, row := range result.Rows {
+			if len(row) >= 2 {
+				recorded[int64(asInt(row[0]))] = int64(asInt(row[1]))
+			}
+		}
+		report.Nodes += len(creates[key])
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	return ids.Record(ctx, IDKindNode, recorded)
+}
+
+// loadRelationships writes batch, one statement per type, between the
+// target IDs of its endpoints. Relationships ids already maps are
+// updated; the rest are created and recorded.
+func (c *Client) loadRelationships(ctx context.Context, batch []*DumpRelationship, ids IDMapping, report *LoadReport) error {
+	sources := make([]int64, len(batch))
+	var endpoints []int64
+	for i, r := range batch {
+		if err := validLabelIdentifier(r.Type); err != nil {
+			return err
+		}
+		sources[i] = r.ID
+		endpoints = append(endpoints, r.Start, r.End)
+	}
+	nodes, err := ids.Lookup(ctx, IDKindNode, endpoints)
+	if err != nil {
+		return err
+	}
+	known, err := ids.Lookup(ctx, IDKindRelationship, sources)
+	if err != nil {
+		return err
+	}
+
+	var updates []interface{}
+	creates := map[string][]interface{}{}
+	for _, r := range batch {
+		start, ok1 := nodes[r.Start]
+		end, ok2 := nodes[r.End]
+		if !ok1 || !ok2 {
+			report.Dangling++
+			continue
+		}
+		row := map[string]interface{}{"id": r.ID, "start": start, "end": end, "props": propsFromJSON(r.Properties)}
+		if target, ok := known[r.ID]; ok {
+			row["target"] = target
+			row["type"] = r.Type
+			updates = append(updates, row)
+		} else {
+			creates[r.Type] = append(creates[r.Type], row)
+		}
+	}
+
+	if len(updates) > 0 {
+		result, err := c.ExecuteCypher(ctx,
+			"UNWIND $rows AS row MATCH ()-[r]->() WHERE id(r) = row.target SET r += row.props RETURN row.id AS old",
+			map[string]interface{}{"rows": updates})
+		if err != nil {
+			return err
+		}
+		updated := returnedIDs(result)
+		report.RelationshipsUpdated += len(updated)
+		for _, row := range updates {
+			m := row.(map[string]interface{})
+			if !updated[m["id"].(int64)] {
+				relType := m["type"].(string)
+				creates[relType] = append(creates[relType], row)
+			}
+		}
+	}
+
+	recorded := map[int64]int64{}
+	for _, relType := range sortedKeys(creates) {
+		result, err := c.ExecuteCypher(ctx,
+			"UNWIND $rows AS row MATCH (a) WHERE id(a) = row.start MATCH (b) WHERE id(b) = row.end "+
+				"CREATE (a)-[r:"+relType+"]->(b) SET r += row.props RETURN row.id AS old, id(r) AS id",
+			map[string]interface{}{"rows": creates[relType]})
+		if err != nil {
+			return err
+		}
+		for _, row := range result.Rows {
+			if len(row) >= 2 {
+				recorded[int64(asInt(row[0]))] = int64(asInt(row[1]))
+			}
+		}
+		report.Relationships += len(creates[relType])
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	return ids.Record(ctx, IDKindRelationship, recorded)
+}
+
+// returnedIDs collects the first column of result as a set of IDs.
+func returnedIDs(result *QueryResult) map[int64]bool {
+	ids := make(map[int64]bool, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) > 0 {
+			ids[int64(asInt(row[0]))] = true
+		}
+	}
+	return ids
+}
+
+// propsFromJSON turns the json.Numbers of a decoded property map back
+// into int64 or float64.
+func propsFromJSON(props map[string]interface{}) map[string]interface{} {
+	if props == nil {
+		return map[string]interface{}{}
+	}
+	return fromJSONNumbers(props).(map[string]interface{})
+}
@@ -0,0 +1,201 @@
+package nexus
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// dumpSource serves a small graph to Dump's paging queries.
+func dumpSource(t *testing.T) *httptest.Server {
+	nodes := [][]interface{}{
+		{1, []string{"Person"}, map[string]interface{}{"name": "Ann", "age": 30}},
+		{2, []string{"Person", "Admin"}, map[string]interface{}{"name": "Bob"}},
+		{3, []string{}, map[string]interface{}{"x": 1.5}},
+	}
+	rels := [][]interface{}{
+		{10, "KNOWS", 1, 2, map[string]interface{}{"since": 2020}},
+		{11, "KNOWS", 2, 9, map[string]interface{}{}},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schema/labels":
+			writeJSON(w, map[string]interface{}{"labels": []LabelInfo{{Name: "Person"}, {Name: "Admin", ID: 1}}})
+			return
+		case "/schema/rel_types":
+			writeJSON(w, map[string]interface{}{"types": []RelTypeInfo{{Name: "KNOWS"}}})
+			return
+		case "/schema/indexes":
+			writeJSON(w, map[string]interface{}{"indexes": []Index{{Name: "person_name", Label: "Person", Properties: []string{"name"}}}})
+			return
+		}
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		after, _ := req.Parameters["__after"].(float64)
+		limit := int(req.Parameters["__limit"].(float64))
+		source, columns := nodes, []string{"id", "labels", "props"}
+		if strings.Contains(req.Query, "-[r]->") {
+			source, columns = rels, []string{"id", "type", "start", "end", "props"}
+		}
+		rows := [][]interface{}{}
+		for _, row := range source {
+			if float64(row[0].(int)) > after && len(rows) < limit {
+				rows = append(rows, row)
+			}
+		}
+		writeJSON(w, map[string]interface{}{"columns": columns, "rows": rows})
+	}))
+}
+
+func TestDumpLoad(t *testing.T) {
+	ctx := context.Background()
+	source := dumpSource(t)
+	defer source.Close()
+
+	var archive bytes.Buffer
+	manifest, err := NewClient(Config{BaseURL: source.URL}).Dump(ctx, &archive)
+	require.NoError(t, err)
+	assert.Equal(t, &DumpManifest{Nodes: 3, Relationships: 2, Chunks: []string{"nodes/000000.ndjson", "relationships/000000.ndjson"}}, manifest)
+
+	target, created, indexes := loadTarget(t, nil)
+	defer target.Close()
+
+	report, err := NewClient(Config{BaseURL: target.URL}).Load(ctx, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, &LoadReport{Nodes: 3, Relationships: 1, Indexes: 1, Dangling: 1}, report)
+
+	require.Len(t, *created, 4)
+	queries := *created
+	assert.Equal(t, "UNWIND $rows AS row CREATE (n) SET n += row.props RETURN row.id AS old, id(n) AS id", queries[0].Query)
+	assert.Equal(t, "UNWIND $rows AS row CREATE (n:Admin:Person) SET n += row.props RETURN row.id AS old, id(n) AS id", queries[1].Query)
+	assert.Equal(t, "UNWIND $rows AS row CREATE (n:Person) SET n += row.props RETURN row.id AS old, id(n) AS id", queries[2].Query)
+	assert.Equal(t, map[string]interface{}{"name": "Ann", "age": float64(30)}, queries[2].Parameters["rows"].([]interface{})[0].(map[string]interface{})["props"])
+	assert.Equal(t, "UNWIND $rows AS row MATCH (a) WHERE id(a) = row.start MATCH (b) WHERE id(b) = row.end "+
+		"CREATE (a)-[r:KNOWS]->(b) SET r += row.props RETURN row.id AS old, id(r) AS id", queries[3].Query)
+	// Ann (1) was created third and Bob (2) second: 102 → 101.
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"id": float64(10), "start": float64(102), "end": float64(101), "props": map[string]interface{}{"since": float64(2020)},
+	}}, queries[3].Parameters["rows"])
+	assert.Equal(t, []map[string]interface{}{{"name": "person_name", "label": "Person", "properties": []interface{}{"name"}}}, *indexes)
+}
+
+// loadTarget fakes the server a dump is loaded into. CREATE statements
+// get IDs counting up from 100; update statements find every target ID
+// except those in deleted.
+func loadTarget(t *testing.T, deleted map[float64]bool) (*httptest.Server, *[]cypherRequest, *[]map[string]interface{}) {
+	var created []cypherRequest
+	var indexes []map[string]interface{}
+	nextID := 100
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/schema/indexes" {
+			if r.Method == http.MethodPost {
+				var idx map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&idx))
+				indexes = append(indexes, idx)
+			}
+			writeJSON(w, map[string]interface{}{"indexes": []Index{}})
+			return
+		}
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		created = append(created, req)
+		rows := [][]interface{}{}
+		for _, row := range req.Parameters["rows"].([]interface{}) {
+			m := row.(map[string]interface{})
+			switch {
+			case strings.Contains(req.Query, "CREATE"):
+				rows = append(rows, []interface{}{m["id"], nextID})
+				nextID++
+			case !deleted[m["target"].(float64)]:
+				rows = append(rows, []interface{}{m["id"]})
+			}
+		}
+		writeJSON(w, map[string]interface{}{"columns": []string{"old", "id"}, "rows": rows})
+	}))
+	return server, &created, &indexes
+}
+
+func TestLoadWithIDMapping(t *testing.T) {
+	ctx := context.Background()
+	source := dumpSource(t)
+	defer source.Close()
+	var archive bytes.Buffer
+	_, err := NewClient(Config{BaseURL: source.URL}).Dump(ctx, &archive)
+	require.NoError(t, err)
+
+	// Bob's copy (101) has been deleted on the target since the first load.
+	target, queries, _ := loadTarget(t, map[float64]bool{101: true})
+	defer target.Close()
+	client := NewClient(Config{BaseURL: target.URL})
+	path := filepath.Join(t.TempDir(), "ids.jsonl")
+
+	ids, err := OpenFileIDMapping(path)
+	require.NoError(t, err)
+	report, err := client.LoadWithOptions(ctx, bytes.NewReader(archive.Bytes()), LoadOptions{IDMapping: ids})
+	require.NoError(t, err)
+	assert.Equal(t, &LoadReport{Nodes: 3, Relationships: 1, Indexes: 1, Dangling: 1}, report)
+	require.NoError(t, ids.Close())
+
+	*queries = nil
+	ids, err = OpenFileIDMapping(path)
+	require.NoError(t, err)
+	defer ids.Close()
+	assert.Equal(t, 3, ids.Len(IDKindNode))
+	assert.Equal(t, 1, ids.Len(IDKindRelationship))
+
+	report, err = client.LoadWithOptions(ctx, bytes.NewReader(archive.Bytes()), LoadOptions{IDMapping: ids})
+	require.NoError(t, err)
+	assert.Equal(t, &LoadReport{Nodes: 1, NodesUpdated: 2, RelationshipsUpdated: 1, Indexes: 1, Dangling: 1}, report)
+	assert.Equal(t, "UNWIND $rows AS row MATCH (n) WHERE id(n) = row.target SET n += row.props, n:Admin:Person RETURN row.id AS old", (*queries)[1].Query)
+	assert.Equal(t, "UNWIND $rows AS row CREATE (n:Admin:Person) SET n += row.props RETURN row.id AS old, id(n) AS id", (*queries)[3].Query)
+	assert.Equal(t, "UNWIND $rows AS row MATCH ()-[r]->() WHERE id(r) = row.target SET r += row.props RETURN row.id AS old", (*queries)[4].Query)
+
+	// Bob was re-created as 104; the relationship now points at it.
+	targets, err := ids.Lookup(ctx, IDKindNode, []int64{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 102, 2: 104, 3: 100}, targets)
+	assert.Equal(t, float64(104), (*queries)[4].Parameters["rows"].([]interface{})[0].(map[string]interface{})["end"])
+}
+
+func TestLoadRejectsBadArchives(t *testing.T) {
+	archive := func(names ...string) io.Reader {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for _, name := range names {
+			data := []byte("{}")
+			if name == "schema.json" {
+				data, _ = json.Marshal(DumpSchema{Format: DumpFormat, Version: DumpVersion})
+			}
+			require.NoError(t, writeDumpFile(tw, name, data))
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+		return &buf
+	}
+	client := NewClient(Config{BaseURL: "http://127.0.0.1:1"})
+	ctx := context.Background()
+
+	_, err := client.Load(ctx, archive("other.json"))
+	assert.ErrorContains(t, err, "not a nexus-dump archive")
+	_, err = client.Load(ctx, archive("schema.json"))
+	assert.ErrorContains(t, err, "truncated")
+	_, err = client.Load(ctx, strings.NewReader("plain text"))
+	assert.Error(t, err)
+}
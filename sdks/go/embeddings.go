@@ -0,0 +1,174 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// EmbeddingAlgorithm selects the server-side node embedding algorithm.
+type EmbeddingAlgorithm string
+
+const (
+	EmbeddingNode2Vec EmbeddingAlgorithm = "node2vec"
+	EmbeddingFastRP   EmbeddingAlgorithm = "fastrp"
+)
+
+// EmbeddingJobOptions configures an embedding run. Zero-valued tuning
+// knobs are omitted so the server defaults apply.
+type EmbeddingJobOptions struct {
+	Algorithm EmbeddingAlgorithm `json:"algorithm"`
+	// Label restricts the embedded nodes; empty embeds every node.
+	Label             string   `json:"label,omitempty"`
+	RelationshipTypes []string `json:"relationship_types,omitempty"`
+	Dimensions        int      `json:"dimensions"`
+	Iterations        int      `json:"iterations,omitempty"`
+	// WalkLength / WalksPerNode only apply to node2vec.
+	WalkLength   int `json:"walk_length,omitempty"`
+	WalksPerNode int `json:"walks_per_node,omitempty"`
+	// WriteProperty, when set, makes the job write each vector to this
+	// node property instead of only keeping it for streaming.
+	WriteProperty string `json:"write_property,omitempty"`
+}
+
+// EmbeddingJob tracks a server-side embedding job. Fields reflect the
+// last Refresh; Job exposes it through the generic job interface.
+type EmbeddingJob struct {
+	ID       string              `json:"id"`
+	State    string              `json:"state"`
+	Progress float64             `json:"progress"`
+	Error    string              `json:"error,omitempty"`
+	Options  EmbeddingJobOptions `json:"options"`
+
+	client *Client
+	job    *Job
+}
+
+// NodeEmbedding is one streamed vector.
+type NodeEmbedding struct {
+	NodeID string    `json:"node_id"`
+	Vector []float32 `json:"vector"`
+}
+
+// StartEmbeddingJob submits a node2vec/FastRP job via
+// POST /algorithms/embeddings and returns immediately.
+func (c *Client) StartEmbeddingJob(ctx context.Context, opts EmbeddingJobOptions) (*EmbeddingJob, error) {
+	switch opts.Algorithm {
+	case EmbeddingNode2Vec, EmbeddingFastRP:
+	default:
+		return nil, fmt.Errorf("nexus: unknown embedding algorithm %q", opts.Algorithm)
+	}
+	if opts.Dimensions <= 0 {
+		return nil, fmt.Errorf("nexus: embedding dimensions must be positive, got %d", opts.Dimensions)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/algorithms/embeddings", opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	job := &EmbeddingJob{client: c}
+	if err := decodeResponse(resp, job); err != nil {
+		return nil, err
+	}
+	job.Options = opts
+	return job, nil
+}
+
+// GetEmbeddingJob fetches the current status of a job by ID.
+func (c *Client) GetEmbeddingJob(ctx context.Context, id string) (*EmbeddingJob, error) {
+	job := &EmbeddingJob{ID: id, client: c}
+	if err := job.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Job returns the generic handle on this job, for code that manages
+// every kind of server job alike (Status, Cancel, Wait).
+func (j *EmbeddingJob) Job() *Job {
+	if j.job == nil || j.job.ID != j.ID {
+		j.job = j.client.jobHandle(j.ID, JobEmbedding, "/algorithms/embeddings/"+url.PathEscape(j.ID))
+	}
+	return j.job
+}
+
+// Done reports whether the job reached a terminal state.
+func (j *EmbeddingJob) Done() bool {
+	return jobStateDone(j.State)
+}
+
+// Refresh reloads the job status from the server.
+func (j *EmbeddingJob) Refresh(ctx context.Context) error {
+	_, err := j.refresh(ctx)
+	return err
+}
+
+func (j *EmbeddingJob) refresh(ctx context.Context) (JobStatus, error) {
+	status, raw, err := j.Job().refresh(ctx)
+	if err != nil {
+		return status, err
+	}
+	opts := j.Options
+	if err := json.Unmarshal(raw, j); err != nil {
+		return status, fmt.Errorf("failed to decode embedding job: %w", err)
+	}
+	if j.Options.Algorithm == "" {
+		j.Options = opts
+	}
+	return status, nil
+}
+
+// Cancel asks the server to stop the job.
+func (j *EmbeddingJob) Cancel(ctx context.Context) error {
+	return j.Job().Cancel(ctx)
+}
+
+// Wait polls the job every pollInterval (default 1s) until it reaches a
+// terminal state or ctx is done. A failed or cancelled job is returned
+// as a *JobError.
+func (j *EmbeddingJob) Wait(ctx context.Context, pollInterval time.Duration) error {
+	_, err := pollJob(ctx, pollInterval, j.refresh)
+	return err
+}
+
+// StreamVectors streams the job's vectors from
+// GET /algorithms/embeddings/{id}/vectors (NDJSON), calling fn once per
+// node. Returning an error from fn stops the stream. Config.Timeout
+// does not apply to the stream; only ctx bounds it.
+func (j *EmbeddingJob) StreamVectors(ctx context.Context, fn func(NodeEmbedding) error) error {
+	path := fmt.Sprintf("/algorithms/embeddings/%s/vectors", url.PathEscape(j.ID))
+	resp, err := j.client.doStream(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return forEachJSONLine(resp.Body, func(line json.RawMessage) error {
+		var emb NodeEmbedding
+		if err := json.Unmarshal(line, &emb); err != nil {
+			return fmt.Errorf("failed to decode embedding: %w", err)
+		}
+		return fn(emb)
+	})
+}
+
+// CreateVectorIndex indexes the vectors a write-mode job stored in
+// Options.WriteProperty, making them searchable through KnnSearch.
+func (j *EmbeddingJob) CreateVectorIndex(ctx context.Context, name string, similarity VectorSimilarity) error {
+	if j.Options.WriteProperty == "" || j.Options.Label == "" {
+		return errors.New("nexus: embedding job must write to a labelled property to be indexed")
+	}
+	return j.client.CreateVectorIndex(ctx, VectorIndexOptions{
+		Name:       name,
+		Label:      j.Options.Label,
+		Property:   j.Options.WriteProperty,
+		Dimensions: j.Options.Dimensions,
+		Similarity: similarity,
+	})
+}
@@ -0,0 +1,104 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddingJobLifecycle(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/algorithms/embeddings":
+			assert.Equal(t, "POST", r.Method)
+			var req map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "fastrp", req["algorithm"])
+			assert.Equal(t, "embedding", req["write_property"])
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "state": "pending"})
+		case "/algorithms/embeddings/job-1":
+			polls++
+			state := "running"
+			if polls > 1 {
+				state = "succeeded"
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "job-1", "state": state, "progress": 0.5})
+		case "/algorithms/embeddings/job-1/vectors":
+			w.Write([]byte("{\"node_id\":\"1\",\"vector\":[0.1,0.2]}\n\n"))
+			w.(http.Flusher).Flush()
+			// Outlives Config.Timeout: streams must not be cut by it.
+			time.Sleep(150 * time.Millisecond)
+			w.Write([]byte("{\"node_id\":\"2\",\"vector\":[0.3,0.4]}\n"))
+		case "/schema/indexes":
+			var req map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "vector", req["type"])
+			assert.Equal(t, []interface{}{"embedding"}, req["properties"])
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Fatalf("Unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	job, err := client.StartEmbeddingJob(ctx, EmbeddingJobOptions{
+		Algorithm:     EmbeddingFastRP,
+		Label:         "Person",
+		Dimensions:    2,
+		WriteProperty: "embedding",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", job.ID)
+
+	require.NoError(t, job.Wait(ctx, time.Millisecond))
+	assert.True(t, job.Done())
+	assert.Equal(t, EmbeddingFastRP, job.Options.Algorithm)
+
+	var vectors []NodeEmbedding
+	require.NoError(t, job.StreamVectors(ctx, func(e NodeEmbedding) error {
+		vectors = append(vectors, e)
+		return nil
+	}))
+	require.Len(t, vectors, 2)
+	assert.Equal(t, "2", vectors[1].NodeID)
+	assert.Equal(t, []float32{0.3, 0.4}, vectors[1].Vector)
+
+	require.NoError(t, job.CreateVectorIndex(ctx, "person_embedding", ""))
+}
+
+func TestKnnSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/knn_traverse", r.URL.Path)
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, float64(3), req["k"])
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"nodes": []map[string]interface{}{
+				{"id": 42, "properties": map[string]interface{}{"name": "Alice"}, "score": 0.9},
+			},
+			"execution_time_ms": 1,
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+
+	matches, err := client.KnnSearch(context.Background(), KnnRequest{Label: "Person", Vector: []float32{0.1}, K: 3})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "42", matches[0].ID)
+	assert.Equal(t, 0.9, matches[0].Score)
+}
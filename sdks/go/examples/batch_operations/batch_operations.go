@@ -89,43 +89,38 @@ func main() {
 	fmt.Println("\n--- Batch Creating Relationships ---")
 	start = time.Now()
 
-	relationships, err := client.BatchCreateRelationships(ctx, []struct {
-		StartNode  string
-		EndNode    string
-		Type       string
-		Properties map[string]interface{}
-	}{
+	relationships, err := client.BatchCreateRelationships(ctx, []nexus.RelationshipBatchInput{
 		{
-			StartNode: nodes[0].ID, // Alice
-			EndNode:   nodes[1].ID, // Bob
-			Type:      "WORKS_WITH",
+			Start: nexus.NodeByID(nodes[0].ID), // Alice
+			End:   nexus.NodeByID(nodes[1].ID), // Bob
+			Type:  "WORKS_WITH",
 			Properties: map[string]interface{}{
 				"project": "GraphDB",
 				"since":   "2020",
 			},
 		},
 		{
-			StartNode: nodes[0].ID, // Alice
-			EndNode:   nodes[4].ID, // Eve
-			Type:      "WORKS_WITH",
+			Start: nexus.NodeByID(nodes[0].ID), // Alice
+			End:   nexus.NodeByID(nodes[4].ID), // Eve
+			Type:  "WORKS_WITH",
 			Properties: map[string]interface{}{
 				"project": "GraphDB",
 				"since":   "2021",
 			},
 		},
 		{
-			StartNode: nodes[1].ID, // Bob
-			EndNode:   nodes[4].ID, // Eve
-			Type:      "WORKS_WITH",
+			Start: nexus.NodeByID(nodes[1].ID), // Bob
+			End:   nexus.NodeByID(nodes[4].ID), // Eve
+			Type:  "WORKS_WITH",
 			Properties: map[string]interface{}{
 				"project": "GraphDB",
 				"since":   "2021",
 			},
 		},
 		{
-			StartNode: nodes[2].ID, // Charlie
-			EndNode:   nodes[3].ID, // Diana
-			Type:      "WORKS_WITH",
+			Start: nexus.NodeByID(nodes[2].ID), // Charlie
+			End:   nexus.NodeByID(nodes[3].ID), // Diana
+			Type:  "WORKS_WITH",
 			Properties: map[string]interface{}{
 				"project": "Marketing Campaign",
 				"since":   "2022",
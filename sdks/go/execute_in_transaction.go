@@ -0,0 +1,56 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+)
+
+// ExecuteInTransaction runs fn in a new transaction: it commits when fn
+// returns nil and rolls back when fn returns an error or panics (the
+// panic is then re-raised). It spares callers the Begin/Commit/Rollback
+// bookkeeping:
+//
+//	err := client.ExecuteInTransaction(ctx, func(tx *nexus.Transaction) error {
+//	    if _, err := tx.ExecuteCypher(ctx, debit, params); err != nil {
+//	        return err
+//	    }
+//	    _, err := tx.ExecuteCypher(ctx, credit, params)
+//	    return err
+//	})
+//
+// fn's error is returned as is, so callers can match it with errors.Is.
+// To retry the whole function on deadlocks and serialization failures,
+// use RetryableClient.ExecuteInTransaction.
+func (c *Client) ExecuteInTransaction(ctx context.Context, fn func(tx *Transaction) error) error {
+	return c.inTransaction(ctx, func(_ context.Context, tx *Transaction) error { return fn(tx) })
+}
+
+// ExecuteInTransaction is Client.ExecuteInTransaction that re-runs fn in
+// a fresh transaction, with backoff, when it fails with a transient
+// conflict (see ExecuteWrite). fn may therefore run more than once and
+// must not have side effects outside tx.
+func (rc *RetryableClient) ExecuteInTransaction(ctx context.Context, fn func(tx *Transaction) error) error {
+	return rc.ExecuteWrite(ctx, func(_ context.Context, tx *Transaction) error { return fn(tx) })
+}
+
+// inTransaction begins a transaction, runs fn and commits, rolling back
+// if fn fails or panics.
+func (c *Client) inTransaction(ctx context.Context, fn func(context.Context, *Transaction) error) error {
+	tx, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+	}()
+	if err := fn(ctx, tx); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,81 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txServer fakes the transaction routes, counting calls per path.
+func txServer(t *testing.T, execute func(n int) (int, string)) (*httptest.Server, map[string]int) {
+	t.Helper()
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch r.URL.Path {
+		case "/transaction/begin":
+			json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx"})
+		case "/transaction/execute":
+			status, body := execute(calls[r.URL.Path])
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, calls
+}
+
+func TestExecuteInTransaction(t *testing.T) {
+	server, calls := txServer(t, func(int) (int, string) { return http.StatusOK, `{"columns":[],"rows":[]}` })
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	err := client.ExecuteInTransaction(ctx, func(tx *Transaction) error {
+		_, err := tx.ExecuteCypher(ctx, "CREATE (n)", nil)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls["/transaction/commit"])
+	assert.Zero(t, calls["/transaction/rollback"])
+
+	errAbort := errors.New("insufficient funds")
+	err = client.ExecuteInTransaction(ctx, func(tx *Transaction) error { return errAbort })
+	assert.Same(t, errAbort, err)
+	assert.Equal(t, 1, calls["/transaction/rollback"])
+
+	assert.PanicsWithValue(t, "boom", func() {
+		client.ExecuteInTransaction(ctx, func(tx *Transaction) error { panic("boom") })
+	})
+	assert.Equal(t, 2, calls["/transaction/rollback"])
+	assert.Equal(t, 1, calls["/transaction/commit"])
+}
+
+func TestRetryableExecuteInTransaction(t *testing.T) {
+	server, calls := txServer(t, func(n int) (int, string) {
+		if n == 1 {
+			return http.StatusConflict, `{"code":"SERIALIZATION_FAILURE","error":"could not serialize access"}`
+		}
+		return http.StatusOK, `{"columns":[],"rows":[]}`
+	})
+	client := NewClient(Config{BaseURL: server.URL}).WithRetry(fastRetry())
+	ctx := context.Background()
+
+	runs := 0
+	err := client.ExecuteInTransaction(ctx, func(tx *Transaction) error {
+		runs++
+		_, err := tx.ExecuteCypher(ctx, "MATCH (a:Account) SET a.n = a.n + 1", nil)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs)
+	assert.Equal(t, 1, calls["/transaction/rollback"])
+	assert.Equal(t, 1, calls["/transaction/commit"])
+}
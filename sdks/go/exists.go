@@ -0,0 +1,51 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// NodeExists reports whether a node with the given ID exists, without
+// transferring it: it sends HEAD /nodes/{id}. Servers that do not
+// answer HEAD on the route are asked with a count query instead.
+func (c *Client) NodeExists(ctx context.Context, id string) (bool, error) {
+	return c.entityExists(ctx, "/nodes/", id, "MATCH (n) WHERE id(n) = $id RETURN count(n) AS found")
+}
+
+// RelationshipExists reports whether a relationship with the given ID
+// exists, like NodeExists.
+func (c *Client) RelationshipExists(ctx context.Context, id string) (bool, error) {
+	return c.entityExists(ctx, "/relationships/", id, "MATCH ()-[r]->() WHERE id(r) = $id RETURN count(r) AS found")
+}
+
+func (c *Client) entityExists(ctx context.Context, prefix, id, fallback string) (bool, error) {
+	resp, err := c.doRequest(ctx, http.MethodHead, prefix+url.PathEscape(id), nil)
+	if err == nil {
+		resp.Body.Close()
+		return true, nil
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false, err
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound:
+		return false, nil
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+	default:
+		return false, err
+	}
+
+	n, err := parseID(id)
+	if err != nil {
+		// IDs the server could never have issued cannot exist.
+		return false, nil
+	}
+	result, err := c.ExecuteCypher(ctx, fallback, map[string]interface{}{"id": n})
+	if err != nil {
+		return false, err
+	}
+	return len(result.Rows) > 0 && len(result.Rows[0]) > 0 && asInt(result.Rows[0][0]) > 0, nil
+}
@@ -0,0 +1,81 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		switch r.URL.Path {
+		case "/nodes/1", "/relationships/r1":
+			w.WriteHeader(http.StatusOK)
+		case "/nodes/boom":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	ok, err := client.NodeExists(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.NodeExists(ctx, "2")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = client.RelationshipExists(ctx, "r1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = client.NodeExists(ctx, "boom")
+	assert.Error(t, err)
+}
+
+func TestNodeExistsFallsBackToCypher(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req struct {
+			Query      string                 `json:"query"`
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		query = req.Query
+		found := 0
+		if req.Parameters["id"] == float64(7) {
+			found = 1
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"found"}, "rows": [][]interface{}{{found}}})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	ok, err := client.RelationshipExists(ctx, "7")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Contains(t, query, "id(r) = $id")
+
+	ok, err = client.NodeExists(ctx, "8")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = client.NodeExists(ctx, "not-a-number")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,80 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+)
+
+// ValueCount is one bucket of a PropertyHistogram.
+type ValueCount struct {
+	Value interface{}
+	Count int64
+}
+
+// DistinctPropertyValues returns up to limit distinct non-null values
+// of property across nodes with label, in ascending order, e.g. to fill
+// a filter dropdown. limit <= 0 means 100.
+func (c *Client) DistinctPropertyValues(ctx context.Context, label, property string, limit int) ([]interface{}, error) {
+	match, err := propertyMatch(label, property)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.ExecuteCypher(ctx,
+		match+" RETURN DISTINCT n."+cypherKey(property)+" AS value ORDER BY value LIMIT $limit",
+		map[string]interface{}{"limit": defaultLimit(limit)})
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) > 0 {
+			values = append(values, row[0])
+		}
+	}
+	return values, nil
+}
+
+// PropertyHistogram counts the nodes with label per value of property,
+// most frequent first, keeping the top limit values (limit <= 0 means
+// 100). Nodes without the property are not counted.
+func (c *Client) PropertyHistogram(ctx context.Context, label, property string, limit int) ([]ValueCount, error) {
+	match, err := propertyMatch(label, property)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.ExecuteCypher(ctx,
+		match+" RETURN n."+cypherKey(property)+" AS value, count(*) AS count ORDER BY count DESC, value LIMIT $limit",
+		map[string]interface{}{"limit": defaultLimit(limit)})
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]ValueCount, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		buckets = append(buckets, ValueCount{Value: row[0], Count: int64(asInt(row[1]))})
+	}
+	return buckets, nil
+}
+
+func propertyMatch(label, property string) (string, error) {
+	if property == "" {
+		return "", errors.New("nexus: property name must not be empty")
+	}
+	pattern := "(n)"
+	if label != "" {
+		if err := validLabelIdentifier(label); err != nil {
+			return "", err
+		}
+		pattern = "(n:" + label + ")"
+	}
+	return "MATCH " + pattern + " WHERE n." + cypherKey(property) + " IS NOT NULL", nil
+}
+
+func defaultLimit(limit int) int {
+	if limit <= 0 {
+		return 100
+	}
+	return limit
+}
@@ -0,0 +1,51 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistinctPropertyValuesAndHistogram(t *testing.T) {
+	var queries []string
+	var limits []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string                 `json:"query"`
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req.Query)
+		limits = append(limits, req.Parameters["limit"])
+		rows := [][]interface{}{{"Berlin"}, {"Oslo"}}
+		if len(queries) == 2 {
+			rows = [][]interface{}{{"Oslo", 7}, {"Berlin", 2}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"value", "count"}, "rows": rows})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	values, err := client.DistinctPropertyValues(ctx, "Person", "city", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"Berlin", "Oslo"}, values)
+
+	buckets, err := client.PropertyHistogram(ctx, "Person", "home city", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []ValueCount{{Value: "Oslo", Count: 7}, {Value: "Berlin", Count: 2}}, buckets)
+
+	assert.Equal(t, "MATCH (n:Person) WHERE n.city IS NOT NULL RETURN DISTINCT n.city AS value ORDER BY value LIMIT $limit", queries[0])
+	assert.Contains(t, queries[1], "n.`home city` IS NOT NULL")
+	assert.Equal(t, []interface{}{float64(100), float64(10)}, limits)
+
+	_, err = client.PropertyHistogram(ctx, "Bad Label", "city", 10)
+	assert.Error(t, err)
+	_, err = client.DistinctPropertyValues(ctx, "Person", "", 10)
+	assert.Error(t, err)
+}
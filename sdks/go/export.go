@@ -0,0 +1,286 @@
+package nexus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Blob is one object written to a BlobStore.
+type Blob struct {
+	Key             string
+	Body            io.Reader
+	Size            int64
+	ContentType     string
+	ContentEncoding string
+}
+
+// BlobStore is an object-storage destination for exports. Implementations
+// wrap S3, GCS, Azure Blob or anything else with a put-object call;
+// HTTPBlobStore covers stores reachable with a plain HTTP PUT.
+type BlobStore interface {
+	Put(ctx context.Context, blob Blob) error
+}
+
+// Export formats.
+const (
+	ExportNDJSON = "ndjson"
+	ExportCSV    = "csv"
+)
+
+// ExportOptions configures ExportToStore.
+type ExportOptions struct {
+	// Query selects the exported rows. It is paged by IterateQuery, so
+	// it should ORDER BY a stable key.
+	Query  string
+	Params map[string]interface{}
+	// KeyColumn pages by key instead of SKIP; see
+	// QueryIteratorOptions.KeyColumn for what the query must do.
+	KeyColumn string
+	// Format is ExportNDJSON (default, one object per row) or ExportCSV.
+	Format string
+	// Prefix is prepended to every object key, e.g. "exports/2024-06-01/".
+	Prefix string
+	// PartRows caps the rows per part object (default 100000).
+	PartRows int
+	// PageSize is the number of rows fetched per query (default 10000).
+	PageSize int
+	// Gzip compresses every part.
+	Gzip bool
+}
+
+// ExportPart describes one part object.
+type ExportPart struct {
+	Key    string `json:"key"`
+	Rows   int    `json:"rows"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// ExportManifest is written as "<prefix>manifest.json" once every part
+// has been stored; its presence marks the export complete.
+type ExportManifest struct {
+	Version     int          `json:"version"`
+	Query       string       `json:"query"`
+	Format      string       `json:"format"`
+	Compression string       `json:"compression,omitempty"`
+	Columns     []string     `json:"columns"`
+	Parts       []ExportPart `json:"parts"`
+	TotalRows   int          `json:"total_rows"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// ExportToStore runs opts.Query page by page through IterateQuery and
+// writes the rows to store as numbered part objects followed by a
+// manifest. Only one part is held in memory at a time; nothing is
+// staged on local disk. The manifest lists the query's columns even
+// when it returns no rows.
+func (c *Client) ExportToStore(ctx context.Context, store BlobStore, opts ExportOptions) (*ExportManifest, error) {
+	if strings.TrimSpace(opts.Query) == "" {
+		return nil, errors.New("nexus: export query must not be empty")
+	}
+	switch opts.Format {
+	case "":
+		opts.Format = ExportNDJSON
+	case ExportNDJSON, ExportCSV:
+	default:
+		return nil, fmt.Errorf("nexus: unknown export format %q", opts.Format)
+	}
+	if opts.PartRows <= 0 {
+		opts.PartRows = 100000
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 10000
+	}
+
+	manifest := &ExportManifest{Version: 1, Query: opts.Query, Format: opts.Format, CreatedAt: time.Now().UTC()}
+	if opts.Gzip {
+		manifest.Compression = "gzip"
+	}
+	part := newExportPart(opts)
+
+	flush := func() error {
+		if part.rows == 0 {
+			return nil
+		}
+		p, blob, err := part.finish(fmt.Sprintf("%spart-%05d.%s", opts.Prefix, len(manifest.Parts), part.ext()))
+		if err != nil {
+			return err
+		}
+		if err := store.Put(ctx, blob); err != nil {
+			return fmt.Errorf("nexus: export put %s: %w", p.Key, err)
+		}
+		manifest.Parts = append(manifest.Parts, p)
+		part = newExportPart(opts)
+		return nil
+	}
+
+	it, err := c.IterateQuery(ctx, opts.Query, opts.Params, QueryIteratorOptions{PageSize: opts.PageSize, KeyColumn: opts.KeyColumn})
+	if err != nil {
+		return nil, err
+	}
+	for it.Next() {
+		if err := part.write(it.Columns(), it.Row()); err != nil {
+			return nil, err
+		}
+		manifest.TotalRows++
+		if part.rows >= opts.PartRows {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("nexus: export: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	manifest.Columns = it.Columns()
+	if manifest.Columns == nil {
+		manifest.Columns = []string{}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	key := opts.Prefix + "manifest.json"
+	if err := store.Put(ctx, Blob{Key: key, Body: bytes.NewReader(data), Size: int64(len(data)), ContentType: "application/json"}); err != nil {
+		return nil, fmt.Errorf("nexus: export put %s: %w", key, err)
+	}
+	return manifest, nil
+}
+
+// exportPart accumulates one part in memory.
+type exportPart struct {
+	format string
+	buf    bytes.Buffer
+	gz     *gzip.Writer
+	w      io.Writer
+	csv    *csv.Writer
+	rows   int
+}
+
+func newExportPart(opts ExportOptions) *exportPart {
+	p := &exportPart{format: opts.Format}
+	p.w = &p.buf
+	if opts.Gzip {
+		p.gz = gzip.NewWriter(&p.buf)
+		p.w = p.gz
+	}
+	return p
+}
+
+func (p *exportPart) ext() string {
+	ext := "ndjson"
+	if p.format == ExportCSV {
+		ext = "csv"
+	}
+	if p.gz != nil {
+		ext += ".gz"
+	}
+	return ext
+}
+
+func (p *exportPart) write(columns []string, row []interface{}) error {
+	if p.format == ExportCSV {
+		if p.csv == nil {
+			p.csv = csv.NewWriter(p.w)
+			if err := p.csv.Write(columns); err != nil {
+				return err
+			}
+		}
+		p.rows++
+		return p.csv.Write(csvRecord(row, ""))
+	}
+	obj := make(map[string]interface{}, len(columns))
+	for i, col := range columns {
+		if i < len(row) {
+			obj[col] = row[i]
+		}
+	}
+	p.rows++
+	return json.NewEncoder(p.w).Encode(obj)
+}
+
+func (p *exportPart) finish(key string) (ExportPart, Blob, error) {
+	if p.csv != nil {
+		p.csv.Flush()
+		if err := p.csv.Error(); err != nil {
+			return ExportPart{}, Blob{}, err
+		}
+	}
+	blob := Blob{Key: key, ContentType: "application/x-ndjson"}
+	if p.format == ExportCSV {
+		blob.ContentType = "text/csv"
+	}
+	if p.gz != nil {
+		if err := p.gz.Close(); err != nil {
+			return ExportPart{}, Blob{}, err
+		}
+		blob.ContentEncoding = "gzip"
+	}
+	sum := sha256.Sum256(p.buf.Bytes())
+	blob.Body = bytes.NewReader(p.buf.Bytes())
+	blob.Size = int64(p.buf.Len())
+	return ExportPart{Key: key, Rows: p.rows, Bytes: blob.Size, SHA256: hex.EncodeToString(sum[:])}, blob, nil
+}
+
+// HTTPBlobStore writes blobs with an HTTP PUT to BaseURL + "/" + key. It
+// works with S3 and GCS (XML API) bucket endpoints when Client carries
+// request signing, and with pre-authorised upload gateways.
+type HTTPBlobStore struct {
+	// BaseURL is the bucket endpoint, e.g.
+	// "https://my-bucket.s3.eu-west-1.amazonaws.com" or
+	// "https://storage.googleapis.com/my-bucket".
+	BaseURL string
+	// Client sends the requests (default http.DefaultClient).
+	Client *http.Client
+	// Header is added to every request (e.g. storage-class headers).
+	Header http.Header
+}
+
+// Put uploads blob.
+func (s *HTTPBlobStore) Put(ctx context.Context, blob Blob) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, strings.TrimRight(s.BaseURL, "/")+"/"+blob.Key, blob.Body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = blob.Size
+	for k, vs := range s.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if blob.ContentType != "" {
+		req.Header.Set("Content-Type", blob.ContentType)
+	}
+	if blob.ContentEncoding != "" {
+		req.Header.Set("Content-Encoding", blob.ContentEncoding)
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package nexus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memStore struct {
+	mu    sync.Mutex
+	blobs map[string]Blob
+	data  map[string][]byte
+	order []string
+}
+
+func (s *memStore) Put(_ context.Context, b Blob) error {
+	data, err := io.ReadAll(b.Body)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.blobs == nil {
+		s.blobs, s.data = map[string]Blob{}, map[string][]byte{}
+	}
+	s.blobs[b.Key], s.data[b.Key] = b, data
+	s.order = append(s.order, b.Key)
+	return nil
+}
+
+func pagedServer(t *testing.T, total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string                 `json:"query"`
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, strings.HasSuffix(req.Query, " SKIP $__skip LIMIT $__limit"))
+		skip := int(req.Parameters["__skip"].(float64))
+		limit := int(req.Parameters["__limit"].(float64))
+		var rows [][]interface{}
+		for i := skip; i < total && i < skip+limit; i++ {
+			rows = append(rows, []interface{}{i, map[string]interface{}{"k": i}})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"id", "props"}, "rows": rows})
+	}))
+}
+
+func TestExportToStoreNDJSONGzip(t *testing.T) {
+	server := pagedServer(t, 7)
+	defer server.Close()
+
+	store := &memStore{}
+	client := NewClient(Config{BaseURL: server.URL})
+	manifest, err := client.ExportToStore(context.Background(), store, ExportOptions{
+		Query:    "MATCH (n) RETURN id(n) AS id, properties(n) AS props ORDER BY id",
+		Prefix:   "exp/",
+		PartRows: 3,
+		PageSize: 2,
+		Gzip:     true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, manifest.TotalRows)
+	assert.Equal(t, []string{"id", "props"}, manifest.Columns)
+	require.Len(t, manifest.Parts, 3)
+	assert.Equal(t, []int{3, 3, 1}, []int{manifest.Parts[0].Rows, manifest.Parts[1].Rows, manifest.Parts[2].Rows})
+	assert.Equal(t, []string{"exp/part-00000.ndjson.gz", "exp/part-00001.ndjson.gz", "exp/part-00002.ndjson.gz", "exp/manifest.json"}, store.order)
+
+	zr, err := gzip.NewReader(bytes.NewReader(store.data["exp/part-00002.ndjson.gz"]))
+	require.NoError(t, err)
+	line, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":6,"props":{"k":6}}`, string(line))
+	assert.Equal(t, "gzip", store.blobs["exp/part-00000.ndjson.gz"].ContentEncoding)
+
+	var stored ExportManifest
+	require.NoError(t, json.Unmarshal(store.data["exp/manifest.json"], &stored))
+	assert.Equal(t, "gzip", stored.Compression)
+	assert.Equal(t, manifest.Parts[1].SHA256, stored.Parts[1].SHA256)
+}
+
+func TestExportToStoreCSV(t *testing.T) {
+	server := pagedServer(t, 2)
+	defer server.Close()
+
+	store := &memStore{}
+	client := NewClient(Config{BaseURL: server.URL})
+	manifest, err := client.ExportToStore(context.Background(), store, ExportOptions{
+		Query:  "MATCH (n) RETURN id(n) AS id, properties(n) AS props ORDER BY id;",
+		Format: ExportCSV,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, manifest.Parts, 1)
+	assert.Equal(t, "id,props\n0,\"{\"\"k\"\":0}\"\n1,\"{\"\"k\"\":1}\"\n", string(store.data["part-00000.csv"]))
+}
+
+func TestExportToStoreEmptyResultKeepsColumns(t *testing.T) {
+	server := pagedServer(t, 0)
+	defer server.Close()
+
+	store := &memStore{}
+	client := NewClient(Config{BaseURL: server.URL})
+	manifest, err := client.ExportToStore(context.Background(), store, ExportOptions{
+		Query: "MATCH (n) RETURN id(n) AS id, properties(n) AS props ORDER BY id",
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Parts)
+	assert.Equal(t, []string{"manifest.json"}, store.order)
+	var stored ExportManifest
+	require.NoError(t, json.Unmarshal(store.data["manifest.json"], &stored))
+	assert.Equal(t, []string{"id", "props"}, stored.Columns)
+	assert.Zero(t, stored.TotalRows)
+}
+
+func TestHTTPBlobStorePut(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/bucket/denied" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("AccessDenied"))
+		}
+	}))
+	defer server.Close()
+
+	store := &HTTPBlobStore{BaseURL: server.URL + "/bucket/", Header: http.Header{"X-Amz-Storage-Class": {"STANDARD_IA"}}}
+	err := store.Put(context.Background(), Blob{Key: "exp/part-00000.csv", Body: strings.NewReader("a,b\n"), Size: 4, ContentType: "text/csv"})
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPut, got.Method)
+	assert.Equal(t, "/bucket/exp/part-00000.csv", got.URL.Path)
+	assert.Equal(t, "text/csv", got.Header.Get("Content-Type"))
+	assert.Equal(t, "STANDARD_IA", got.Header.Get("X-Amz-Storage-Class"))
+	assert.Equal(t, "a,b\n", string(body))
+
+	err = store.Put(context.Background(), Blob{Key: "denied", Body: strings.NewReader("x"), Size: 1})
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+}
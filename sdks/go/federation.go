@@ -0,0 +1,163 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FederationTarget is one database/tenant a federated query runs on.
+type FederationTarget struct {
+	// Name annotates every row coming from this target.
+	Name   string
+	Client *Client
+	// Scope, when set, derives the per-target request context, e.g.
+	// WithDatabase or WithTenant to fan out over one shared client.
+	Scope func(context.Context) context.Context
+}
+
+// FederateOptions configures Federate.
+type FederateOptions struct {
+	// Concurrency caps the number of targets queried at once (0 = all).
+	Concurrency int
+	// FailFast cancels the remaining targets on the first error.
+	FailFast bool
+	// SourceColumn names the prepended column holding the target name
+	// (default "_source").
+	SourceColumn string
+}
+
+// FederatedResult merges the rows of every successful target.
+type FederatedResult struct {
+	// Columns is SourceColumn followed by the query's columns.
+	Columns []string
+	Rows    [][]interface{}
+	// PerSource holds each successful target's own result.
+	PerSource map[string]*QueryResult
+	// Errors holds the failure of each unsuccessful target.
+	Errors map[string]error
+}
+
+// FederationError is returned when no target succeeded or FailFast
+// stopped the run.
+type FederationError struct {
+	Errors map[string]error
+}
+
+func (e *FederationError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = fmt.Sprintf("%s: %v", name, e.Errors[name])
+	}
+	return "nexus: federated query failed: " + strings.Join(msgs, "; ")
+}
+
+// Federate runs query concurrently against every target and merges the
+// rows, prefixing each with its target name — for fleet-wide
+// administrative queries. Rows keep target order, then server order.
+//
+// Targets whose columns differ from the first successful target are
+// recorded in Errors rather than merged. Partial failures are reported
+// through FederatedResult.Errors; a *FederationError is returned only
+// when every target failed or FailFast triggered.
+func Federate(ctx context.Context, targets []FederationTarget, query string, params map[string]interface{}, opts FederateOptions) (*FederatedResult, error) {
+	if len(targets) == 0 {
+		return nil, errors.New("nexus: Federate requires at least one target")
+	}
+	seen := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		if t.Name == "" || t.Client == nil {
+			return nil, errors.New("nexus: federation targets need a name and a client")
+		}
+		if seen[t.Name] {
+			return nil, fmt.Errorf("nexus: duplicate federation target %q", t.Name)
+		}
+		seen[t.Name] = true
+	}
+	if opts.SourceColumn == "" {
+		opts.SourceColumn = "_source"
+	}
+	limit := opts.Concurrency
+	if limit <= 0 || limit > len(targets) {
+		limit = len(targets)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*QueryResult, len(targets))
+	errs := make([]error, len(targets))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t FederationTarget) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			tctx := ctx
+			if t.Scope != nil {
+				tctx = t.Scope(ctx)
+			}
+			results[i], errs[i] = t.Client.ExecuteCypher(tctx, query, params)
+			if errs[i] != nil && opts.FailFast {
+				cancel()
+			}
+		}(i, t)
+	}
+	wg.Wait()
+
+	out := &FederatedResult{
+		PerSource: make(map[string]*QueryResult),
+		Errors:    make(map[string]error),
+	}
+	var columns []string
+	for i, t := range targets {
+		if errs[i] != nil {
+			out.Errors[t.Name] = errs[i]
+			continue
+		}
+		r := results[i]
+		if columns == nil {
+			columns = r.Columns
+			out.Columns = append([]string{opts.SourceColumn}, columns...)
+		} else if !equalStrings(columns, r.Columns) {
+			out.Errors[t.Name] = fmt.Errorf("columns %v do not match %v", r.Columns, columns)
+			continue
+		}
+		out.PerSource[t.Name] = r
+		for _, row := range r.Rows {
+			out.Rows = append(out.Rows, append([]interface{}{t.Name}, row...))
+		}
+	}
+
+	if len(out.PerSource) == 0 || (opts.FailFast && len(out.Errors) > 0) {
+		return out, &FederationError{Errors: out.Errors}
+	}
+	return out, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func federationServer(t *testing.T, body string, status int) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(Config{BaseURL: server.URL})
+}
+
+func TestFederateMergesWithSource(t *testing.T) {
+	east := federationServer(t, `{"columns":["n"],"rows":[[3]]}`, http.StatusOK)
+	west := federationServer(t, `{"columns":["n"],"rows":[[5],[6]]}`, http.StatusOK)
+	broken := federationServer(t, `down`, http.StatusServiceUnavailable)
+
+	result, err := Federate(context.Background(), []FederationTarget{
+		{Name: "east", Client: east},
+		{Name: "west", Client: west},
+		{Name: "broken", Client: broken},
+	}, "MATCH (n) RETURN count(n) AS n", nil, FederateOptions{Concurrency: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"_source", "n"}, result.Columns)
+	assert.Equal(t, [][]interface{}{{"east", int64(3)}, {"west", int64(5)}, {"west", int64(6)}}, result.Rows)
+	assert.Len(t, result.PerSource, 2)
+	assert.Contains(t, result.Errors, "broken")
+
+	_, err = Federate(context.Background(), []FederationTarget{{Name: "broken", Client: broken}},
+		"RETURN 1", nil, FederateOptions{})
+	var fedErr *FederationError
+	assert.True(t, errors.As(err, &fedErr))
+}
@@ -0,0 +1,107 @@
+package nexus
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// FindOptions selects and pages the nodes FindNodes returns.
+type FindOptions struct {
+	// Labels the nodes must all carry; empty matches any node.
+	Labels []string
+	// PropertyEquals the nodes' properties must equal.
+	PropertyEquals map[string]interface{}
+	// Skip and Limit page the matches; Limit defaults to 100.
+	Skip  int
+	Limit int
+	// OrderBy is the property to sort by, ascending unless Descending is
+	// set; empty sorts by node ID, so pages are stable.
+	OrderBy    string
+	Descending bool
+}
+
+// FindResult is a page of FindNodes matches.
+type FindResult struct {
+	Nodes []*Node
+	// Total is the number of matches across all pages.
+	Total int64
+}
+
+// FindNodes returns the nodes matching opts, a page at a time, with the
+// total number of matches:
+//
+//	page, err := client.FindNodes(ctx, nexus.FindOptions{
+//		Labels:         []string{"Person"},
+//		PropertyEquals: map[string]interface{}{"city": "Lisbon"},
+//		OrderBy:        "name",
+//		Limit:          20,
+//	})
+//
+// The server has no node listing route, so FindNodes runs a count and a
+// page query.
+func (c *Client) FindNodes(ctx context.Context, opts FindOptions) (*FindResult, error) {
+	match, params, err := findMatch(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.ExecuteCypher(ctx, match+" RETURN count(n) AS total", params)
+	if err != nil {
+		return nil, err
+	}
+	found := &FindResult{}
+	if len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
+		found.Total = int64(asInt(result.Rows[0][0]))
+	}
+	if found.Total == 0 {
+		return found, nil
+	}
+
+	order := "id(n)"
+	if opts.OrderBy != "" {
+		order = "n." + cypherKey(opts.OrderBy)
+	}
+	if opts.Descending {
+		order += " DESC"
+	}
+	if opts.OrderBy != "" {
+		order += ", id(n)"
+	}
+	params["skip"] = max(opts.Skip, 0)
+	params["limit"] = defaultLimit(opts.Limit)
+	result, err = c.ExecuteCypher(ctx,
+		match+" RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props "+
+			"ORDER BY "+order+" SKIP $skip LIMIT $limit",
+		params)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range result.Rows {
+		if len(row) >= 3 {
+			found.Nodes = append(found.Nodes, nodeFromRow(row))
+		}
+	}
+	return found, nil
+}
+
+// findMatch builds the MATCH … WHERE part of the FindNodes queries.
+func findMatch(opts FindOptions) (string, map[string]interface{}, error) {
+	for _, l := range opts.Labels {
+		if err := validLabelIdentifier(l); err != nil {
+			return "", nil, err
+		}
+	}
+	match := "MATCH (n" + labelSuffix(opts.Labels) + ")"
+	params := map[string]interface{}{}
+	var conds []string
+	for i, k := range sortedKeys(opts.PropertyEquals) {
+		name := "v" + strconv.Itoa(i)
+		conds = append(conds, "n."+cypherKey(k)+" = $"+name)
+		params[name] = opts.PropertyEquals[k]
+	}
+	if len(conds) > 0 {
+		match += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return match, params, nil
+}
@@ -0,0 +1,76 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindNodes(t *testing.T) {
+	var requests []cypherRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if strings.HasSuffix(req.Query, "count(n) AS total") {
+			total := 12
+			if req.Parameters["v0"] == "Nowhere" {
+				total = 0
+			}
+			writeJSON(w, map[string]interface{}{"columns": []string{"total"}, "rows": [][]interface{}{{total}}})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"columns": []string{"id", "labels", "props"},
+			"rows": [][]interface{}{
+				{4, []string{"Person"}, map[string]interface{}{"name": "Zoe"}},
+				{2, []string{"Person"}, map[string]interface{}{"name": "Ann"}},
+			},
+		})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	page, err := client.FindNodes(ctx, FindOptions{
+		Labels:         []string{"Person", "Customer"},
+		PropertyEquals: map[string]interface{}{"city": "Lisbon", "first-name": "Ann"},
+		OrderBy:        "name", Descending: true,
+		Skip: 10, Limit: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), page.Total)
+	require.Len(t, page.Nodes, 2)
+	assert.Equal(t, "4", page.Nodes[0].ID)
+	assert.Equal(t, "Ann", page.Nodes[1].Properties["name"])
+
+	require.Len(t, requests, 2)
+	match := "MATCH (n:Person:Customer) WHERE n.city = $v0 AND n.`first-name` = $v1"
+	assert.Equal(t, match+" RETURN count(n) AS total", requests[0].Query)
+	assert.Equal(t, match+" RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props "+
+		"ORDER BY n.name DESC, id(n) SKIP $skip LIMIT $limit", requests[1].Query)
+	assert.Equal(t, map[string]interface{}{
+		"v0": "Lisbon", "v1": "Ann", "skip": float64(10), "limit": float64(2),
+	}, requests[1].Parameters)
+
+	// No matches skip the page query; the default page is ordered by ID.
+	page, err = client.FindNodes(ctx, FindOptions{PropertyEquals: map[string]interface{}{"city": "Nowhere"}})
+	require.NoError(t, err)
+	assert.Equal(t, &FindResult{}, page)
+	assert.Len(t, requests, 3)
+
+	_, err = client.FindNodes(ctx, FindOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (n) RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props "+
+		"ORDER BY id(n) SKIP $skip LIMIT $limit", requests[4].Query)
+	assert.Equal(t, float64(100), requests[4].Parameters["limit"])
+
+	_, err = client.FindNodes(ctx, FindOptions{Labels: []string{"Bad Label"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package nexus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// cypherKeywords are upper-cased by NormalizeQuery so queries differing
+// only in keyword case share a fingerprint.
+var cypherKeywords = map[string]bool{
+	"MATCH": true, "OPTIONAL": true, "WHERE": true, "RETURN": true, "WITH": true,
+	"UNWIND": true, "CREATE": true, "MERGE": true, "SET": true, "DELETE": true,
+	"DETACH": true, "REMOVE": true, "ORDER": true, "BY": true, "SKIP": true,
+	"LIMIT": true, "AS": true, "AND": true, "OR": true, "XOR": true, "NOT": true,
+	"IN": true, "IS": true, "NULL": true, "DISTINCT": true, "ON": true,
+	"CALL": true, "YIELD": true, "UNION": true, "ALL": true, "CASE": true,
+	"WHEN": true, "THEN": true, "ELSE": true, "END": true, "ASC": true,
+	"DESC": true, "ASCENDING": true, "DESCENDING": true, "STARTS": true,
+	"ENDS": true, "CONTAINS": true, "EXISTS": true,
+}
+
+// NormalizeQuery reduces a query to its shape: string, number and
+// boolean literals become ?, lists of literals collapse to [?],
+// keywords are upper-cased, comments are dropped and runs of whitespace
+// become one space. Parameters and identifiers are kept, so
+//
+//	match (n:Person)
+//	WHERE n.age > 30 AND n.name IN ['a', 'b'] // adults
+//
+// normalizes to
+//
+//	MATCH (n:Person) WHERE n.age > ? AND n.name IN [?]
+func NormalizeQuery(query string) string {
+	toks, _ := cypherlex.Tokenize(query)
+	out := make([]normalToken, 0, len(toks))
+	for i, t := range toks {
+		text := t.Text
+		switch {
+		case t.Kind == cypherlex.String || t.Kind == cypherlex.Number ||
+			t.Kind == cypherlex.Word && (strings.EqualFold(text, "true") || strings.EqualFold(text, "false")):
+			text = "?"
+		case t.Kind == cypherlex.Word && cypherKeywords[strings.ToUpper(text)] && cypherlex.Clause(toks, i, text):
+			text = strings.ToUpper(text)
+		}
+		out = append(out, normalToken{text: text, space: i > 0 && t.Start > toks[i-1].End})
+		out = collapseLiteralList(out)
+	}
+
+	var b strings.Builder
+	for _, t := range out {
+		if t.space {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.text)
+	}
+	return b.String()
+}
+
+// normalToken is a token of a normalized query; space records whether
+// whitespace or a comment preceded it.
+type normalToken struct {
+	text  string
+	space bool
+}
+
+// collapseLiteralList rewrites a just-closed [?, ?, …] at the end of
+// toks to [?], so IN lists of any length normalize alike.
+func collapseLiteralList(toks []normalToken) []normalToken {
+	n := len(toks)
+	if n < 3 || toks[n-1].text != "]" {
+		return toks
+	}
+	for i := n - 2; i >= 0; i-- {
+		switch toks[i].text {
+		case "[":
+			if i >= n-3 {
+				return toks
+			}
+			return append(toks[:i+1], normalToken{text: "?"}, normalToken{text: "]"})
+		case "?", ",":
+		default:
+			return toks
+		}
+	}
+	return toks
+}
+
+// QueryFingerprint identifies the shape of a query: queries that
+// NormalizeQuery maps to the same text share a fingerprint. It is 16
+// hex digits.
+func QueryFingerprint(query string) string {
+	return fingerprintOf(NormalizeQuery(query))
+}
+
+func fingerprintOf(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
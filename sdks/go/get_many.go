@@ -0,0 +1,107 @@
+package nexus
+
+import "context"
+
+// GetNodes fetches the nodes with the given IDs in one query. The
+// result is aligned with ids: result[i] is the node for ids[i], or nil
+// when no such node exists. Repeated IDs yield the same node.
+func (c *Client) GetNodes(ctx context.Context, ids []string) ([]*Node, error) {
+	out := make([]*Node, len(ids))
+	wanted := numericIDs(ids)
+	if len(wanted) == 0 {
+		return out, nil
+	}
+	result, err := c.ExecuteCypher(ctx,
+		"MATCH (n) WHERE id(n) IN $ids RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props",
+		map[string]interface{}{"ids": wanted})
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]*Node, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		n := nodeFromRow(row)
+		found[n.ID] = n
+	}
+	for i, id := range ids {
+		out[i] = found[id]
+	}
+	return out, nil
+}
+
+// GetRelationships fetches the relationships with the given IDs in one
+// query, aligned with ids like GetNodes.
+func (c *Client) GetRelationships(ctx context.Context, ids []string) ([]*Relationship, error) {
+	out := make([]*Relationship, len(ids))
+	wanted := numericIDs(ids)
+	if len(wanted) == 0 {
+		return out, nil
+	}
+	result, err := c.ExecuteCypher(ctx,
+		"MATCH (a)-[r]->(b) WHERE id(r) IN $ids "+
+			"RETURN id(r) AS id, type(r) AS type, id(a) AS start, id(b) AS end, properties(r) AS props",
+		map[string]interface{}{"ids": wanted})
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]*Relationship, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		r := &Relationship{
+			ID:        idString(row[0]),
+			Type:      asString(row[1]),
+			StartNode: idString(row[2]),
+			EndNode:   idString(row[3]),
+		}
+		r.Properties, _ = row[4].(map[string]interface{})
+		found[r.ID] = r
+	}
+	for i, id := range ids {
+		out[i] = found[id]
+	}
+	return out, nil
+}
+
+// nodeFromRow reads an (id, labels, properties) row.
+func nodeFromRow(row []interface{}) *Node {
+	n := &Node{ID: idString(row[0])}
+	for _, l := range asSlice(row[1]) {
+		if s, ok := l.(string); ok {
+			n.Labels = append(n.Labels, s)
+		}
+	}
+	n.Properties, _ = row[2].(map[string]interface{})
+	return n
+}
+
+// MissingIDs returns the IDs whose slot in a GetNodes or
+// GetRelationships result is nil.
+func MissingIDs[T any](ids []string, entities []*T) []string {
+	var missing []string
+	for i, id := range ids {
+		if i >= len(entities) || entities[i] == nil {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// numericIDs parses the distinct IDs the server could have issued;
+// anything else can only be missing.
+func numericIDs(ids []string) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		n, err := parseID(id)
+		if err != nil || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNodes(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req struct {
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []interface{}{float64(3), float64(1), float64(9)}, req.Parameters["ids"])
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"columns": []string{"id", "labels", "props"},
+			"rows": [][]interface{}{
+				{1, []string{"Person"}, map[string]interface{}{"name": "Ann"}},
+				{3, []string{"Person", "Admin"}, map[string]interface{}{"name": "Bob"}},
+			},
+		})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	ids := []string{"3", "1", "9", "bogus", "3"}
+	nodes, err := client.GetNodes(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, nodes, 5)
+	assert.Equal(t, "Bob", nodes[0].Properties["name"])
+	assert.Equal(t, []string{"Person", "Admin"}, nodes[0].Labels)
+	assert.Equal(t, "1", nodes[1].ID)
+	assert.Nil(t, nodes[2])
+	assert.Nil(t, nodes[3])
+	assert.Same(t, nodes[0], nodes[4])
+	assert.Equal(t, []string{"9", "bogus"}, MissingIDs(ids, nodes))
+	assert.Equal(t, 1, requests)
+}
+
+func TestGetRelationships(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"columns": []string{"id", "type", "start", "end", "props"},
+			"rows":    [][]interface{}{{5, "KNOWS", 1, 2, map[string]interface{}{"since": 2020}}},
+		})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	rels, err := client.GetRelationships(context.Background(), []string{"6", "5"})
+	require.NoError(t, err)
+	assert.Nil(t, rels[0])
+	require.NotNil(t, rels[1])
+	assert.Equal(t, Relationship{ID: "5", Type: "KNOWS", StartNode: "1", EndNode: "2", Properties: map[string]interface{}{"since": int64(2020)}}, *rels[1])
+
+	rels, err = client.GetRelationships(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, rels)
+}
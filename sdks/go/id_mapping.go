@@ -0,0 +1,166 @@
+package nexus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// IDKind separates node and relationship IDs in an IDMapping; the
+// server numbers them independently.
+type IDKind string
+
+// ID kinds.
+const (
+	IDKindNode         IDKind = "node"
+	IDKindRelationship IDKind = "relationship"
+)
+
+// IDMapping records which target ID each source ID was loaded as, the
+// mapping table LoadWithOptions uses to remap IDs between servers.
+// Implementations must be safe for concurrent use.
+type IDMapping interface {
+	// Lookup returns the recorded target IDs of ids; unknown IDs are
+	// absent from the result.
+	Lookup(ctx context.Context, kind IDKind, ids []int64) (map[int64]int64, error)
+	// Record stores source → target pairs, replacing earlier ones.
+	Record(ctx context.Context, kind IDKind, pairs map[int64]int64) error
+}
+
+// MemoryIDMapping is an IDMapping held in memory; it lasts as long as
+// the value.
+type MemoryIDMapping struct {
+	mu  sync.RWMutex
+	ids map[IDKind]map[int64]int64
+}
+
+// NewMemoryIDMapping returns an empty in-memory mapping.
+func NewMemoryIDMapping() *MemoryIDMapping {
+	return &MemoryIDMapping{ids: map[IDKind]map[int64]int64{}}
+}
+
+// Lookup implements IDMapping.
+func (m *MemoryIDMapping) Lookup(_ context.Context, kind IDKind, ids []int64) (map[int64]int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	found := make(map[int64]int64, len(ids))
+	for _, id := range ids {
+		if target, ok := m.ids[kind][id]; ok {
+			found[id] = target
+		}
+	}
+	return found, nil
+}
+
+// Record implements IDMapping.
+func (m *MemoryIDMapping) Record(_ context.Context, kind IDKind, pairs map[int64]int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record(kind, pairs)
+	return nil
+}
+
+func (m *MemoryIDMapping) record(kind IDKind, pairs map[int64]int64) {
+	if m.ids[kind] == nil {
+		m.ids[kind] = make(map[int64]int64, len(pairs))
+	}
+	for source, target := range pairs {
+		m.ids[kind][source] = target
+	}
+}
+
+// Len returns the number of recorded IDs of kind.
+func (m *MemoryIDMapping) Len(kind IDKind) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.ids[kind])
+}
+
+// idMappingEntry is one line of a FileIDMapping.
+type idMappingEntry struct {
+	Kind   IDKind `json:"kind"`
+	Source int64  `json:"source"`
+	Target int64  `json:"target"`
+}
+
+// FileIDMapping is an IDMapping persisted as JSON lines
+// ({"kind":"node","source":12,"target":4031}), so the mapping outlives
+// one load. The file is read into memory when opened and only appended
+// to; later lines win. Keep one file per (source, target) server pair.
+type FileIDMapping struct {
+	*MemoryIDMapping
+
+	mu  sync.Mutex
+	f   *os.File
+	w   *bufio.Writer
+	err error
+}
+
+// OpenFileIDMapping opens (creating if needed) the mapping file at path.
+func OpenFileIDMapping(path string) (*FileIDMapping, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	m := &FileIDMapping{MemoryIDMapping: NewMemoryIDMapping(), f: f, w: bufio.NewWriter(f)}
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for line := 1; ; line++ {
+		var e idMappingEntry
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("nexus: id mapping %s entry %d: %w", path, line, err)
+		}
+		m.record(e.Kind, map[int64]int64{e.Source: e.Target})
+	}
+	return m, nil
+}
+
+// Record implements IDMapping, appending pairs to the file before they
+// become visible to Lookup.
+func (m *FileIDMapping) Record(ctx context.Context, kind IDKind, pairs map[int64]int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	enc := json.NewEncoder(m.w)
+	for _, source := range sortedInt64Keys(pairs) {
+		if m.err = enc.Encode(idMappingEntry{Kind: kind, Source: source, Target: pairs[source]}); m.err != nil {
+			return m.err
+		}
+	}
+	if m.err = m.w.Flush(); m.err != nil {
+		return m.err
+	}
+	return m.MemoryIDMapping.Record(ctx, kind, pairs)
+}
+
+// Close flushes and closes the file.
+func (m *FileIDMapping) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.w.Flush(); err != nil {
+		m.f.Close()
+		return err
+	}
+	return m.f.Close()
+}
+
+func sortedInt64Keys[V any](m map[int64]V) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,47 @@
+package nexus
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// IdempotencyKeyHeader carries a key identifying one logical write
+// across its attempts. The Nexus server does not read it, so it does
+// not make a replayed write safe; it lets logs, and proxies that honour
+// the header, tie the attempts together.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// WithIdempotencyKey returns a context whose requests carry key as
+// their Idempotency-Key. Retrying clients (see Config.Retry) generate a
+// key per request on their own; set one explicitly to correlate a
+// write across your own retries too, e.g. with a key derived from the
+// job or message being processed:
+//
+//	ctx := nexus.WithIdempotencyKey(ctx, "import-"+batchID)
+//	_, err := client.CreateNode(ctx, labels, props)
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return transport.WithHeader(ctx, IdempotencyKeyHeader, key)
+}
+
+// IdempotencyKeyFromContext returns the key set with
+// WithIdempotencyKey, or "".
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	return transport.HeadersFromContext(ctx).Get(IdempotencyKeyHeader)
+}
+
+// newIdempotencyKey returns a random UUID (version 4).
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("nexus: reading random bytes: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
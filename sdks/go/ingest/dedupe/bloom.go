@@ -0,0 +1,148 @@
+package dedupe
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"sync"
+)
+
+// BloomLedger is a Ledger held in memory as a Bloom filter: a few bits
+// per key whatever the key length, so tens of millions of keys fit in
+// tens of megabytes. It never reports a recorded key as new, but
+// reports a new key as seen at about the false-positive rate it was
+// sized for, and such an item is skipped. Use GraphLedger where every
+// item must land.
+//
+// Save it with WriteTo at the end of a run and load it with
+// ReadBloomLedger at the start of the next.
+type BloomLedger struct {
+	mu   sync.RWMutex
+	k    uint32
+	bits []uint64
+}
+
+// maxBloomWords bounds a ledger at 2 GiB of bits, enough for about a
+// billion keys at 0.1%. ReadBloomLedger refuses larger headers.
+const maxBloomWords = 1 << 28
+
+// NewBloomLedger returns a ledger sized for n keys at false-positive
+// rate p, e.g. 0.001, within 2 GiB.
+func NewBloomLedger(n int, p float64) *BloomLedger {
+	if n < 1 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.001
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(n)*math.Ln2))
+	words := min((uint64(m)+63)/64, maxBloomWords)
+	return &BloomLedger{k: uint32(k), bits: make([]uint64, words)}
+}
+
+// positions calls fn with the k bit positions of key.
+func (b *BloomLedger) positions(key string, fn func(uint64)) {
+	bloomPositions(key, uint64(len(b.bits))*64, b.k, fn)
+}
+
+// bloomPositions derives k positions below m from the two 64-bit
+// halves of key's 128-bit FNV-1a hash by double hashing, so filters
+// past 2^32 bits use their whole range.
+func bloomPositions(key string, m uint64, k uint32, fn func(uint64)) {
+	h := fnv.New128a()
+	h.Write([]byte(key))
+	sum := h.Sum(nil)
+	h1, h2 := binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:])|1
+	for i := uint64(0); i < uint64(k); i++ {
+		fn((h1 + i*h2) % m)
+	}
+}
+
+// Seen implements Ledger.
+func (b *BloomLedger) Seen(_ context.Context, keys []string) (map[string]bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	seen := map[string]bool{}
+	for _, key := range keys {
+		all := true
+		b.positions(key, func(p uint64) {
+			all = all && b.bits[p/64]&(1<<(p%64)) != 0
+		})
+		if all {
+			seen[key] = true
+		}
+	}
+	return seen, nil
+}
+
+// Record implements Ledger.
+func (b *BloomLedger) Record(_ context.Context, keys []string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, key := range keys {
+		b.positions(key, func(p uint64) { b.bits[p/64] |= 1 << (p % 64) })
+	}
+	return nil
+}
+
+// bloomMagic starts a saved BloomLedger.
+const bloomMagic = "NXBLOOM1"
+
+// bloomChunk is the number of words ReadBloomLedger reads at a time, so
+// a truncated file fails before its header's full size is allocated.
+const bloomChunk = 1 << 16
+
+// WriteTo saves the ledger to w.
+func (b *BloomLedger) WriteTo(w io.Writer) (int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	bw := bufio.NewWriter(w)
+	bw.WriteString(bloomMagic)
+	binary.Write(bw, binary.LittleEndian, b.k)
+	binary.Write(bw, binary.LittleEndian, uint64(len(b.bits)))
+	binary.Write(bw, binary.LittleEndian, b.bits)
+	n := int64(len(bloomMagic) + 4 + 8 + 8*len(b.bits))
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("dedupe: save bloom ledger: %w", err)
+	}
+	return n, nil
+}
+
+// ReadBloomLedger loads a ledger saved with WriteTo.
+func ReadBloomLedger(r io.Reader) (*BloomLedger, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(bloomMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, fmt.Errorf("dedupe: load bloom ledger: %w", err)
+	}
+	if string(magic) != bloomMagic {
+		return nil, errors.New("dedupe: load bloom ledger: not a saved ledger")
+	}
+	var k uint32
+	var words uint64
+	if err := binary.Read(br, binary.LittleEndian, &k); err != nil {
+		return nil, fmt.Errorf("dedupe: load bloom ledger: %w", err)
+	}
+	if err := binary.Read(br, binary.LittleEndian, &words); err != nil {
+		return nil, fmt.Errorf("dedupe: load bloom ledger: %w", err)
+	}
+	if k == 0 || words == 0 || words > maxBloomWords {
+		return nil, errors.New("dedupe: load bloom ledger: corrupt header")
+	}
+	b := &BloomLedger{k: k, bits: make([]uint64, 0, min(words, bloomChunk))}
+	chunk := make([]uint64, min(words, bloomChunk))
+	for left := words; left > 0; left -= uint64(len(chunk)) {
+		chunk = chunk[:min(left, bloomChunk)]
+		if err := binary.Read(br, binary.LittleEndian, chunk); err != nil {
+			return nil, fmt.Errorf("dedupe: load bloom ledger: %w", err)
+		}
+		b.bits = append(b.bits, chunk...)
+	}
+	return b, nil
+}
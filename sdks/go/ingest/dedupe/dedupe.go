@@ -0,0 +1,228 @@
+// Package dedupe makes ingestion pipelines safe to re-run: items carry
+// a natural key from their source (an order number, a file path plus
+// line, a message id), and a Ledger remembers the keys already
+// ingested so a second run skips them instead of creating duplicates.
+//
+// A Loader puts a Ledger in front of a BulkLoader. Nodes and
+// relationships are checked a batch at a time, only new ones are
+// written, and their keys are recorded once the write succeeded:
+//
+//	ledger := dedupe.NewGraphLedger(client, "orders-import")
+//	loader := dedupe.NewLoader(client.NewBulkLoader(nexus.BulkLoaderOptions{}), ledger, dedupe.Options{})
+//	for _, o := range orders {
+//	    err := loader.AddNode(ctx, "order/"+o.ID, nexus.BulkNode{Labels: []string{"Order"}, Properties: o.Props()})
+//	    ...
+//	}
+//	err := loader.Flush(ctx)
+//
+// GraphLedger keeps the keys in Nexus itself, exact and shared by every
+// process. BloomLedger keeps them in a compact in-memory filter that
+// can be saved between runs; it never misses a key it recorded but may,
+// at the configured rate, skip a new one.
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	nexus "github.com/hivellm/nexus-go"
+)
+
+// Ledger records which keys have been ingested.
+type Ledger interface {
+	// Seen returns the subset of keys already recorded.
+	Seen(ctx context.Context, keys []string) (map[string]bool, error)
+	// Record marks keys as ingested.
+	Record(ctx context.Context, keys []string) error
+}
+
+// keyLabel labels the ledger nodes of a GraphLedger, one per
+// (namespace, key).
+const keyLabel = "_IngestKey"
+
+// GraphLedger is a Ledger stored in Nexus as _IngestKey nodes. Keys are
+// scoped by namespace, so pipelines sharing a database do not see each
+// other's keys.
+type GraphLedger struct {
+	client    *nexus.Client
+	namespace string
+}
+
+// NewGraphLedger returns a ledger of the keys of namespace.
+func NewGraphLedger(client *nexus.Client, namespace string) *GraphLedger {
+	return &GraphLedger{client: client, namespace: namespace}
+}
+
+// Seen implements Ledger.
+func (g *GraphLedger) Seen(ctx context.Context, keys []string) (map[string]bool, error) {
+	seen := map[string]bool{}
+	if len(keys) == 0 {
+		return seen, nil
+	}
+	result, err := g.client.ExecuteCypher(ctx,
+		"UNWIND $keys AS k MATCH (l:"+keyLabel+" {namespace: $namespace, key: k}) RETURN l.key",
+		map[string]interface{}{"namespace": g.namespace, "keys": keys})
+	if err != nil {
+		return nil, fmt.Errorf("dedupe: look up keys: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) > 0 {
+			if k, ok := row[0].(string); ok {
+				seen[k] = true
+			}
+		}
+	}
+	return seen, nil
+}
+
+// Record implements Ledger.
+func (g *GraphLedger) Record(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := g.client.ExecuteCypher(ctx,
+		"UNWIND $keys AS k MERGE (l:"+keyLabel+" {namespace: $namespace, key: k}) "+
+			"ON CREATE SET l.ingested_at = timestamp()",
+		map[string]interface{}{"namespace": g.namespace, "keys": keys})
+	if err != nil {
+		return fmt.Errorf("dedupe: record keys: %w", err)
+	}
+	return nil
+}
+
+// Forget removes every key of the namespace, so the next run ingests
+// everything again.
+func (g *GraphLedger) Forget(ctx context.Context) error {
+	_, err := g.client.ExecuteCypher(ctx,
+		"MATCH (l:"+keyLabel+" {namespace: $namespace}) DELETE l",
+		map[string]interface{}{"namespace": g.namespace})
+	if err != nil {
+		return fmt.Errorf("dedupe: forget keys: %w", err)
+	}
+	return nil
+}
+
+// Options configures a Loader.
+type Options struct {
+	// BatchSize is the number of items checked against the ledger at a
+	// time (default 1000).
+	BatchSize int
+}
+
+// Stats counts what a Loader has done so far.
+type Stats struct {
+	// Written items were new and handed to the BulkLoader.
+	Written int
+	// Skipped items had a key already ingested, or repeated one earlier
+	// in the same run.
+	Skipped int
+}
+
+// Loader is a BulkLoader that skips items whose key a Ledger has seen.
+//
+// Keys are recorded after the BulkLoader flushed the batch holding
+// them. If the process dies in between, the next run writes that batch
+// again, which is harmless for keyed (MERGEd) nodes and relationships.
+//
+// A Loader is not safe for concurrent use. Call Flush when done.
+type Loader struct {
+	loader *nexus.BulkLoader
+	ledger Ledger
+	opts   Options
+
+	pending []item
+	stats   Stats
+}
+
+type item struct {
+	key  string
+	node *nexus.BulkNode
+	rel  *nexus.BulkRelationship
+}
+
+// NewLoader returns a Loader writing new items through loader.
+func NewLoader(loader *nexus.BulkLoader, ledger Ledger, opts Options) *Loader {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	return &Loader{loader: loader, ledger: ledger, opts: opts}
+}
+
+// AddNode queues n under key, flushing when a batch fills up.
+func (l *Loader) AddNode(ctx context.Context, key string, n nexus.BulkNode) error {
+	return l.add(ctx, item{key: key, node: &n})
+}
+
+// AddRelationship queues r under key, flushing when a batch fills up.
+func (l *Loader) AddRelationship(ctx context.Context, key string, r nexus.BulkRelationship) error {
+	return l.add(ctx, item{key: key, rel: &r})
+}
+
+func (l *Loader) add(ctx context.Context, it item) error {
+	if it.key == "" {
+		return errors.New("dedupe: empty key")
+	}
+	l.pending = append(l.pending, it)
+	if len(l.pending) >= l.opts.BatchSize {
+		return l.flush(ctx)
+	}
+	return nil
+}
+
+// Flush checks and writes everything still queued, flushes the
+// BulkLoader and records the keys written.
+func (l *Loader) Flush(ctx context.Context) error {
+	if err := l.flush(ctx); err != nil {
+		return err
+	}
+	return l.loader.Flush(ctx)
+}
+
+// Stats returns the counts so far.
+func (l *Loader) Stats() Stats { return l.stats }
+
+func (l *Loader) flush(ctx context.Context) error {
+	if len(l.pending) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(l.pending))
+	queued := map[string]bool{}
+	for _, it := range l.pending {
+		if !queued[it.key] {
+			queued[it.key] = true
+			keys = append(keys, it.key)
+		}
+	}
+	seen, err := l.ledger.Seen(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	var fresh []string
+	for _, it := range l.pending {
+		if seen[it.key] {
+			l.stats.Skipped++
+			continue
+		}
+		seen[it.key] = true
+		fresh = append(fresh, it.key)
+		if it.node != nil {
+			err = l.loader.AddNode(ctx, *it.node)
+		} else {
+			err = l.loader.AddRelationship(ctx, *it.rel)
+		}
+		if err != nil {
+			return err
+		}
+		l.stats.Written++
+	}
+	l.pending = l.pending[:0]
+	if len(fresh) == 0 {
+		return nil
+	}
+	if err := l.loader.Flush(ctx); err != nil {
+		return err
+	}
+	return l.ledger.Record(ctx, fresh)
+}
@@ -0,0 +1,164 @@
+package dedupe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ledgerServer fakes the ledger queries and counts the bulk rows
+// written.
+func ledgerServer(t *testing.T, written *int) *httptest.Server {
+	ledger := map[string]bool{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string `json:"query"`
+			Parameters struct {
+				Namespace string        `json:"namespace"`
+				Keys      []string      `json:"keys"`
+				Rows      []interface{} `json:"rows"`
+			} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		p := req.Parameters
+		rows := [][]interface{}{}
+		switch {
+		case strings.Contains(req.Query, "MATCH (l:_IngestKey") && strings.HasSuffix(req.Query, "RETURN l.key"):
+			for _, k := range p.Keys {
+				if ledger[p.Namespace+"/"+k] {
+					rows = append(rows, []interface{}{k})
+				}
+			}
+		case strings.Contains(req.Query, "MERGE (l:_IngestKey"):
+			for _, k := range p.Keys {
+				ledger[p.Namespace+"/"+k] = true
+			}
+		case strings.HasSuffix(req.Query, "DELETE l"):
+			for k := range ledger {
+				if strings.HasPrefix(k, p.Namespace+"/") {
+					delete(ledger, k)
+				}
+			}
+		default:
+			*written += len(p.Rows)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"key"}, "rows": rows})
+	}))
+}
+
+func TestLoaderSkipsIngestedKeys(t *testing.T) {
+	var written int
+	server := ledgerServer(t, &written)
+	defer server.Close()
+	client := nexus.NewClient(nexus.Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	run := func(ledger Ledger, n int) Stats {
+		loader := NewLoader(client.NewBulkLoader(nexus.BulkLoaderOptions{}), ledger, Options{BatchSize: 3})
+		for i := 0; i < n; i++ {
+			require.NoError(t, loader.AddNode(ctx, fmt.Sprintf("order/%d", i), nexus.BulkNode{
+				Labels: []string{"Order"}, Properties: map[string]interface{}{"id": i},
+			}))
+		}
+		require.NoError(t, loader.AddRelationship(ctx, "placed/1", nexus.BulkRelationship{
+			Type: "PLACED", From: nexus.BulkEndpoint{Label: "Customer", Key: "id", Value: 1},
+			To: nexus.BulkEndpoint{Label: "Order", Key: "id", Value: 1},
+		}))
+		require.NoError(t, loader.AddNode(ctx, "order/0", nexus.BulkNode{Labels: []string{"Order"}}))
+		require.NoError(t, loader.Flush(ctx))
+		return loader.Stats()
+	}
+
+	ledger := NewGraphLedger(client, "orders")
+	assert.Equal(t, Stats{Written: 5, Skipped: 1}, run(ledger, 4))
+	assert.Equal(t, 5, written)
+
+	// A re-run writes only what is new.
+	assert.Equal(t, Stats{Written: 2, Skipped: 6}, run(ledger, 6))
+	assert.Equal(t, 7, written)
+
+	// Namespaces are separate, and Forget starts over.
+	assert.Equal(t, Stats{Written: 3, Skipped: 1}, run(NewGraphLedger(client, "other"), 2))
+	require.NoError(t, ledger.Forget(ctx))
+	assert.Equal(t, Stats{Written: 7, Skipped: 1}, run(ledger, 6))
+
+	err := NewLoader(client.NewBulkLoader(nexus.BulkLoaderOptions{}), ledger, Options{}).
+		AddNode(ctx, "", nexus.BulkNode{Labels: []string{"Order"}})
+	assert.Error(t, err)
+}
+
+func TestBloomLedger(t *testing.T) {
+	ctx := context.Background()
+	ledger := NewBloomLedger(10000, 0.01)
+	var recorded []string
+	for i := 0; i < 10000; i++ {
+		recorded = append(recorded, fmt.Sprintf("key-%d", i))
+	}
+	require.NoError(t, ledger.Record(ctx, recorded))
+
+	var buf bytes.Buffer
+	n, err := ledger.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	loaded, err := ReadBloomLedger(&buf)
+	require.NoError(t, err)
+
+	seen, err := loaded.Seen(ctx, recorded)
+	require.NoError(t, err)
+	assert.Len(t, seen, len(recorded), "recorded keys are never reported new")
+
+	var fresh []string
+	for i := 0; i < 10000; i++ {
+		fresh = append(fresh, fmt.Sprintf("new-%d", i))
+	}
+	seen, err = loaded.Seen(ctx, fresh)
+	require.NoError(t, err)
+	assert.Less(t, len(seen), 300, "false positives stay near the configured rate")
+
+	_, err = ReadBloomLedger(strings.NewReader("not a ledger"))
+	assert.Error(t, err)
+}
+
+func TestReadBloomLedgerBoundsHeader(t *testing.T) {
+	header := func(words uint64) *bytes.Buffer {
+		var buf bytes.Buffer
+		buf.WriteString(bloomMagic)
+		binary.Write(&buf, binary.LittleEndian, uint32(3))
+		binary.Write(&buf, binary.LittleEndian, words)
+		return &buf
+	}
+	_, err := ReadBloomLedger(header(maxBloomWords + 1))
+	assert.ErrorContains(t, err, "corrupt header")
+
+	// A header claiming the maximum over a few bytes of data fails
+	// after the first chunk.
+	truncated := header(maxBloomWords)
+	truncated.Write(make([]byte, 64))
+	_, err = ReadBloomLedger(truncated)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestBloomPositionsUseWholeRange(t *testing.T) {
+	const m = 1 << 40
+	high := 0
+	for i := 0; i < 1000; i++ {
+		bloomPositions(fmt.Sprintf("key-%d", i), m, 7, func(p uint64) {
+			require.Less(t, p, uint64(m))
+			if p >= 1<<32 {
+				high++
+			}
+		})
+	}
+	assert.Greater(t, high, 6900, "positions spread past 2^32 bits")
+}
@@ -0,0 +1,438 @@
+// Package kafka connects Nexus to Kafka-style streams without depending
+// on a particular client library: adapt segmentio/kafka-go, franz-go or
+// confluent-kafka-go to the small Consumer and Producer interfaces.
+//
+// A Sink consumes messages and applies them as graph mutations. Each
+// message's mutations and its offset are written in one transaction, and
+// offsets at or below the recorded one are skipped, so redelivery after
+// a crash does not apply a message twice:
+//
+//	sink := kafka.NewSink(client, consumer, kafka.SinkOptions{Group: "graph-loader"})
+//	err := sink.Run(ctx)
+//
+// PublishChanges goes the other way, relaying the Nexus change feed to a
+// topic.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// Message is a record read from or written to a topic.
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+}
+
+// Consumer fetches messages in offset order per partition.
+type Consumer interface {
+	// Fetch blocks until the next message is available or ctx ends.
+	Fetch(ctx context.Context) (Message, error)
+	// Commit acknowledges msg (and everything before it in its partition).
+	Commit(ctx context.Context, msg Message) error
+}
+
+// Producer publishes messages.
+type Producer interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// Mutation operations understood by a Sink.
+const (
+	OpUpsertNode         = "upsert_node"
+	OpDeleteNode         = "delete_node"
+	OpUpsertRelationship = "upsert_relationship"
+	OpDeleteRelationship = "delete_relationship"
+)
+
+// NodeRef identifies a node by a unique property.
+type NodeRef struct {
+	Label string      `json:"label"`
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// Mutation is one graph change carried by a message. Node operations use
+// Node; relationship operations use Type, From and To. Upserts MERGE on
+// the identifying key and add Properties.
+type Mutation struct {
+	Op         string                 `json:"op"`
+	Node       *NodeRef               `json:"node,omitempty"`
+	Type       string                 `json:"type,omitempty"`
+	From       *NodeRef               `json:"from,omitempty"`
+	To         *NodeRef               `json:"to,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// DecodeJSON is the default SinkOptions.Decode: the message value is a
+// JSON Mutation or an array of them.
+func DecodeJSON(msg Message) ([]Mutation, error) {
+	var many []Mutation
+	if err := json.Unmarshal(msg.Value, &many); err == nil {
+		return many, nil
+	}
+	var one Mutation
+	if err := json.Unmarshal(msg.Value, &one); err != nil {
+		return nil, fmt.Errorf("kafka: decode mutation: %w", err)
+	}
+	return []Mutation{one}, nil
+}
+
+// SinkOptions configures a Sink.
+type SinkOptions struct {
+	// Group names the offset ledger, like a consumer group; sinks sharing
+	// a group share progress. Defaults to "nexus".
+	Group string
+	// Decode turns a message into mutations (default DecodeJSON).
+	Decode func(Message) ([]Mutation, error)
+	// OnError is called when a message cannot be decoded or applied.
+	// Returning nil skips the message (its offset is still recorded);
+	// returning an error stops Run. By default every error stops Run.
+	OnError func(Message, error) error
+}
+
+// SinkStats counts what a Sink has processed.
+type SinkStats struct {
+	Applied    int
+	Duplicates int
+	Skipped    int
+	Mutations  int
+}
+
+// Sink applies consumed messages to the graph.
+type Sink struct {
+	client   *nexus.Client
+	consumer Consumer
+	opts     SinkOptions
+
+	mu      sync.Mutex
+	offsets map[partitionKey]int64
+	stats   SinkStats
+}
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+// offsetLabel labels the ledger nodes holding the last applied offset
+// per (group, topic, partition).
+const offsetLabel = "_KafkaOffset"
+
+// NewSink returns a sink applying messages from consumer through client.
+func NewSink(client *nexus.Client, consumer Consumer, opts SinkOptions) *Sink {
+	if opts.Group == "" {
+		opts.Group = "nexus"
+	}
+	if opts.Decode == nil {
+		opts.Decode = DecodeJSON
+	}
+	return &Sink{client: client, consumer: consumer, opts: opts, offsets: map[partitionKey]int64{}}
+}
+
+// Run fetches and applies messages until ctx ends or an error stops it.
+func (s *Sink) Run(ctx context.Context) error {
+	for {
+		msg, err := s.consumer.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("kafka: fetch: %w", err)
+		}
+		if err := s.Apply(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// Stats returns the counts processed so far.
+func (s *Sink) Stats() SinkStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Apply writes one message's mutations and offset in a transaction, then
+// commits the message to the consumer. Messages at or below the recorded
+// offset of their partition are acknowledged without being applied.
+func (s *Sink) Apply(ctx context.Context, msg Message) error {
+	key := partitionKey{msg.Topic, msg.Partition}
+	last, err := s.lastOffset(ctx, key)
+	if err != nil {
+		return err
+	}
+	if msg.Offset <= last {
+		s.count(func(st *SinkStats) { st.Duplicates++ })
+		return s.commit(ctx, msg)
+	}
+
+	mutations, err := s.opts.Decode(msg)
+	if err == nil {
+		err = s.applyTx(ctx, msg, mutations)
+	}
+	if err != nil {
+		if s.opts.OnError == nil {
+			return err
+		}
+		if err := s.opts.OnError(msg, err); err != nil {
+			return err
+		}
+		// Record the skipped offset so the poison message is not retried.
+		if err := s.applyTx(ctx, msg, nil); err != nil {
+			return err
+		}
+		s.count(func(st *SinkStats) { st.Skipped++ })
+		return s.commit(ctx, msg)
+	}
+	s.count(func(st *SinkStats) {
+		st.Applied++
+		st.Mutations += len(mutations)
+	})
+	return s.commit(ctx, msg)
+}
+
+func (s *Sink) applyTx(ctx context.Context, msg Message, mutations []Mutation) error {
+	tx, err := s.client.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.writeTx(ctx, tx, msg, mutations); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.offsets[partitionKey{msg.Topic, msg.Partition}] = msg.Offset
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Sink) writeTx(ctx context.Context, tx *nexus.Transaction, msg Message, mutations []Mutation) error {
+	for i, m := range mutations {
+		query, params, err := mutationQuery(m)
+		if err != nil {
+			return fmt.Errorf("kafka: %s[%d] offset %d mutation %d: %w", msg.Topic, msg.Partition, msg.Offset, i, err)
+		}
+		if _, err := tx.ExecuteCypher(ctx, query, params); err != nil {
+			return fmt.Errorf("kafka: %s[%d] offset %d mutation %d: %w", msg.Topic, msg.Partition, msg.Offset, i, err)
+		}
+	}
+	_, err := tx.ExecuteCypher(ctx,
+		"MERGE (o:"+offsetLabel+" {group: $group, topic: $topic, partition: $partition}) SET o.offset = $offset",
+		map[string]interface{}{
+			"group":     s.opts.Group,
+			"topic":     msg.Topic,
+			"partition": int64(msg.Partition),
+			"offset":    msg.Offset,
+		})
+	if err != nil {
+		return fmt.Errorf("kafka: record offset: %w", err)
+	}
+	return nil
+}
+
+// lastOffset returns the recorded offset of a partition, loading it from
+// the ledger on first use; -1 means nothing has been applied.
+func (s *Sink) lastOffset(ctx context.Context, key partitionKey) (int64, error) {
+	s.mu.Lock()
+	off, ok := s.offsets[key]
+	s.mu.Unlock()
+	if ok {
+		return off, nil
+	}
+
+	result, err := s.client.ExecuteCypher(ctx,
+		"MATCH (o:"+offsetLabel+" {group: $group, topic: $topic, partition: $partition}) RETURN o.offset",
+		map[string]interface{}{"group": s.opts.Group, "topic": key.topic, "partition": int64(key.partition)})
+	if err != nil {
+		return 0, fmt.Errorf("kafka: load offset: %w", err)
+	}
+	off = -1
+	if len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
+		if off, err = toInt64(result.Rows[0][0]); err != nil {
+			return 0, fmt.Errorf("kafka: load offset: %w", err)
+		}
+	}
+	s.mu.Lock()
+	s.offsets[key] = off
+	s.mu.Unlock()
+	return off, nil
+}
+
+func (s *Sink) commit(ctx context.Context, msg Message) error {
+	if err := s.consumer.Commit(ctx, msg); err != nil {
+		return fmt.Errorf("kafka: commit offset %d: %w", msg.Offset, err)
+	}
+	return nil
+}
+
+func (s *Sink) count(fn func(*SinkStats)) {
+	s.mu.Lock()
+	fn(&s.stats)
+	s.mu.Unlock()
+}
+
+// mutationQuery builds the Cypher statement for m.
+func mutationQuery(m Mutation) (string, map[string]interface{}, error) {
+	props := m.Properties
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	switch m.Op {
+	case OpUpsertNode, OpDeleteNode:
+		if err := checkRef("node", m.Node); err != nil {
+			return "", nil, err
+		}
+		match := fmt.Sprintf("(n:%s {%s: $key})", m.Node.Label, m.Node.Key)
+		params := map[string]interface{}{"key": m.Node.Value}
+		if m.Op == OpDeleteNode {
+			return "MATCH " + match + " DETACH DELETE n", params, nil
+		}
+		params["props"] = props
+		return "MERGE " + match + " SET n += $props", params, nil
+
+	case OpUpsertRelationship, OpDeleteRelationship:
+		if !cypherlex.IsPlainName(m.Type) {
+			return "", nil, fmt.Errorf("invalid relationship type %q", m.Type)
+		}
+		if err := checkRef("from", m.From); err != nil {
+			return "", nil, err
+		}
+		if err := checkRef("to", m.To); err != nil {
+			return "", nil, err
+		}
+		params := map[string]interface{}{"from": m.From.Value, "to": m.To.Value}
+		if m.Op == OpDeleteRelationship {
+			return fmt.Sprintf("MATCH (a:%s {%s: $from})-[r:%s]->(b:%s {%s: $to}) DELETE r",
+				m.From.Label, m.From.Key, m.Type, m.To.Label, m.To.Key), params, nil
+		}
+		params["props"] = props
+		return fmt.Sprintf("MATCH (a:%s {%s: $from}) MATCH (b:%s {%s: $to}) MERGE (a)-[r:%s]->(b) SET r += $props",
+			m.From.Label, m.From.Key, m.To.Label, m.To.Key, m.Type), params, nil
+	}
+	return "", nil, fmt.Errorf("unknown op %q", m.Op)
+}
+
+func checkRef(name string, ref *NodeRef) error {
+	if ref == nil {
+		return fmt.Errorf("missing %s", name)
+	}
+	if !cypherlex.IsPlainName(ref.Label) || !cypherlex.IsPlainName(ref.Key) {
+		return fmt.Errorf("invalid %s label/key %q/%q", name, ref.Label, ref.Key)
+	}
+	if ref.Value == nil {
+		return fmt.Errorf("%s has no key value", name)
+	}
+	return nil
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case float64:
+		return int64(n), nil
+	case json.Number:
+		return n.Int64()
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	}
+	return 0, fmt.Errorf("unexpected offset %T", v)
+}
+
+// SourceOptions configures PublishChanges.
+type SourceOptions struct {
+	Topic string
+	// Since resumes after this change-feed cursor.
+	Since string
+	// Labels / RelationshipTypes filter the relayed events.
+	Labels            []string
+	RelationshipTypes []string
+	// PollInterval is the wait after an empty poll (default 1s).
+	PollInterval time.Duration
+	// Key picks the message key; the default "<entity>:<id>" keeps every
+	// entity's events on one partition, in order.
+	Key func(nexus.ChangeEvent) []byte
+	// OnCursor is called after each published batch with the cursor to
+	// persist for resuming. An error stops PublishChanges.
+	OnCursor func(cursor string) error
+}
+
+// PublishChanges relays the change feed to opts.Topic as JSON
+// ChangeEvents until ctx ends, returning the last published cursor.
+// Delivery is at-least-once: after a crash, events after the last
+// persisted cursor are published again.
+func PublishChanges(ctx context.Context, client *nexus.Client, producer Producer, opts SourceOptions) (string, error) {
+	if opts.Topic == "" {
+		return opts.Since, errors.New("kafka: source topic must not be empty")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Key == nil {
+		opts.Key = func(ev nexus.ChangeEvent) []byte { return []byte(ev.Entity + ":" + ev.ID) }
+	}
+
+	cursor := opts.Since
+	for {
+		published := 0
+		next, err := client.Changes(ctx, nexus.ChangesOptions{
+			Since:             cursor,
+			Labels:            opts.Labels,
+			RelationshipTypes: opts.RelationshipTypes,
+		}, func(ev nexus.ChangeEvent) error {
+			value, err := json.Marshal(ev)
+			if err != nil {
+				return err
+			}
+			msg := Message{
+				Topic:   opts.Topic,
+				Key:     opts.Key(ev),
+				Value:   value,
+				Headers: map[string]string{"nexus-op": ev.Op, "nexus-cursor": ev.Cursor},
+			}
+			if err := producer.Publish(ctx, msg); err != nil {
+				return fmt.Errorf("kafka: publish %s: %w", ev.Cursor, err)
+			}
+			published++
+			return nil
+		})
+		cursor = next
+		if published > 0 && opts.OnCursor != nil {
+			if cbErr := opts.OnCursor(cursor); cbErr != nil && err == nil {
+				err = cbErr
+			}
+		}
+		if err != nil {
+			return cursor, err
+		}
+		if published > 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return cursor, ctx.Err()
+		case <-time.After(opts.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConsumer struct {
+	msgs      []Message
+	committed []int64
+}
+
+func (c *fakeConsumer) Fetch(ctx context.Context) (Message, error) {
+	if len(c.msgs) == 0 {
+		return Message{}, errors.New("drained")
+	}
+	m := c.msgs[0]
+	c.msgs = c.msgs[1:]
+	return m, nil
+}
+
+func (c *fakeConsumer) Commit(_ context.Context, m Message) error {
+	c.committed = append(c.committed, m.Offset)
+	return nil
+}
+
+// graphServer records transactional statements and serves a stored
+// ledger offset.
+type graphServer struct {
+	mu        sync.Mutex
+	ledger    interface{}
+	queries   []string
+	commits   int
+	rollbacks int
+	failOn    string
+}
+
+func (g *graphServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/transaction/begin":
+		json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx"})
+		return
+	case "/transaction/commit":
+		g.commits++
+		json.NewEncoder(w).Encode(map[string]bool{"success": true})
+		return
+	case "/transaction/rollback":
+		g.rollbacks++
+		json.NewEncoder(w).Encode(map[string]bool{"success": true})
+		return
+	}
+	var req struct {
+		Query string `json:"query"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	if g.failOn != "" && strings.Contains(req.Query, g.failOn) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"boom"}`))
+		return
+	}
+	var rows [][]interface{}
+	if strings.HasPrefix(req.Query, "MATCH (o:_KafkaOffset") {
+		if g.ledger != nil {
+			rows = [][]interface{}{{g.ledger}}
+		}
+	} else {
+		g.queries = append(g.queries, req.Query)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"o.offset"}, "rows": rows})
+}
+
+func msg(offset int64, value string) Message {
+	return Message{Topic: "people", Partition: 0, Offset: offset, Value: []byte(value)}
+}
+
+func TestSinkAppliesAndSkipsDuplicates(t *testing.T) {
+	g := &graphServer{ledger: 4}
+	server := httptest.NewServer(g)
+	defer server.Close()
+
+	consumer := &fakeConsumer{msgs: []Message{
+		msg(3, `{"op":"upsert_node","node":{"label":"Person","key":"id","value":1}}`),
+		msg(5, `[{"op":"upsert_node","node":{"label":"Person","key":"id","value":1},"properties":{"name":"Ann"}},
+		        {"op":"upsert_relationship","type":"KNOWS","from":{"label":"Person","key":"id","value":1},"to":{"label":"Person","key":"id","value":2}}]`),
+		msg(6, `{"op":"delete_node","node":{"label":"Person","key":"id","value":2}}`),
+	}}
+	sink := NewSink(nexus.NewClient(nexus.Config{BaseURL: server.URL}), consumer, SinkOptions{Group: "g"})
+
+	err := sink.Run(context.Background())
+	require.ErrorContains(t, err, "drained")
+
+	assert.Equal(t, []int64{3, 5, 6}, consumer.committed)
+	assert.Equal(t, SinkStats{Applied: 2, Duplicates: 1, Mutations: 3}, sink.Stats())
+	assert.Equal(t, 2, g.commits)
+	assert.Equal(t, []string{
+		"MERGE (n:Person {id: $key}) SET n += $props",
+		"MATCH (a:Person {id: $from}) MATCH (b:Person {id: $to}) MERGE (a)-[r:KNOWS]->(b) SET r += $props",
+		"MERGE (o:_KafkaOffset {group: $group, topic: $topic, partition: $partition}) SET o.offset = $offset",
+		"MATCH (n:Person {id: $key}) DETACH DELETE n",
+		"MERGE (o:_KafkaOffset {group: $group, topic: $topic, partition: $partition}) SET o.offset = $offset",
+	}, g.queries)
+}
+
+func TestSinkRollsBackFailedMessage(t *testing.T) {
+	g := &graphServer{failOn: "KNOWS"}
+	server := httptest.NewServer(g)
+	defer server.Close()
+
+	consumer := &fakeConsumer{}
+	sink := NewSink(nexus.NewClient(nexus.Config{BaseURL: server.URL}), consumer, SinkOptions{})
+	err := sink.Apply(context.Background(), msg(0,
+		`{"op":"upsert_relationship","type":"KNOWS","from":{"label":"Person","key":"id","value":1},"to":{"label":"Person","key":"id","value":2}}`))
+
+	require.Error(t, err)
+	assert.Equal(t, 1, g.rollbacks)
+	assert.Zero(t, g.commits)
+	assert.Empty(t, consumer.committed)
+}
+
+func TestSinkOnErrorSkipsPoisonMessage(t *testing.T) {
+	g := &graphServer{}
+	server := httptest.NewServer(g)
+	defer server.Close()
+
+	var seen error
+	consumer := &fakeConsumer{}
+	sink := NewSink(nexus.NewClient(nexus.Config{BaseURL: server.URL}), consumer, SinkOptions{
+		OnError: func(_ Message, err error) error { seen = err; return nil },
+	})
+	require.NoError(t, sink.Apply(context.Background(), msg(0, `not json`)))
+	require.NoError(t, sink.Apply(context.Background(), msg(1, `{"op":"upsert_node","node":{"label":"Bad Label","key":"id","value":1}}`)))
+
+	assert.ErrorContains(t, seen, "invalid node label/key")
+	assert.Equal(t, []int64{0, 1}, consumer.committed)
+	assert.Equal(t, 2, sink.Stats().Skipped)
+	// Only the ledger updates were written.
+	assert.Len(t, g.queries, 2)
+}
+
+type fakeProducer struct{ msgs []Message }
+
+func (p *fakeProducer) Publish(_ context.Context, m Message) error {
+	p.msgs = append(p.msgs, m)
+	return nil
+}
+
+func TestPublishChanges(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if r.URL.Query().Get("since") == "" {
+			w.Write([]byte(`{"cursor":"c1","op":"create","entity":"node","id":"7"}` + "\n"))
+			w.Write([]byte(`{"cursor":"c2","op":"update","entity":"node","id":"7"}` + "\n"))
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	producer := &fakeProducer{}
+	var cursors []string
+	cursor, err := PublishChanges(ctx, nexus.NewClient(nexus.Config{BaseURL: server.URL}), producer, SourceOptions{
+		Topic:        "graph-changes",
+		PollInterval: time.Millisecond,
+		OnCursor: func(c string) error {
+			cursors = append(cursors, c)
+			cancel()
+			return nil
+		},
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "c2", cursor)
+	assert.Equal(t, []string{"c2"}, cursors)
+	require.Len(t, producer.msgs, 2)
+	assert.Equal(t, "graph-changes", producer.msgs[0].Topic)
+	assert.Equal(t, []byte("node:7"), producer.msgs[0].Key)
+	assert.Equal(t, "update", producer.msgs[1].Headers["nexus-op"])
+}
@@ -0,0 +1,222 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	odsOffice = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsTable  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsText   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+// ReadODS reads an OpenDocument spreadsheet (.ods).
+func ReadODS(r io.ReaderAt, size int64) (*Workbook, error) {
+	wb, err := readODS(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("spreadsheet: %w", err)
+	}
+	return wb, nil
+}
+
+func readODS(r io.ReaderAt, size int64) (*Workbook, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	var content *zip.File
+	for _, f := range zr.File {
+		if f.Name == "content.xml" {
+			content = f
+		}
+	}
+	if content == nil {
+		return nil, errors.New("not an OpenDocument spreadsheet (no content.xml)")
+	}
+	rc, err := content.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	// Repeated empty cells and rows are only materialised once content
+	// follows them: sheets routinely end in a single row "repeated"
+	// a million times.
+	wb := &Workbook{}
+	var (
+		sheet      *Sheet
+		row        []interface{}
+		emptyCells int
+		emptyRows  int
+		rowRepeat  int
+	)
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("content.xml: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != odsTable {
+				continue
+			}
+			switch t.Name.Local {
+			case "table":
+				sheet = &Sheet{Name: attr(t, odsTable, "name")}
+				wb.Sheets = append(wb.Sheets, sheet)
+				emptyRows = 0
+			case "table-row":
+				row, emptyCells = nil, 0
+				rowRepeat = repeat(attr(t, odsTable, "number-rows-repeated"))
+			case "table-cell", "covered-table-cell":
+				value, err := odsCell(dec, t)
+				if err != nil {
+					return nil, fmt.Errorf("content.xml: %w", err)
+				}
+				n := repeat(attr(t, odsTable, "number-columns-repeated"))
+				if value == nil {
+					emptyCells += n
+					continue
+				}
+				for ; emptyCells > 0; emptyCells-- {
+					row = append(row, nil)
+				}
+				for i := 0; i < n; i++ {
+					row = append(row, value)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space != odsTable || t.Name.Local != "table-row" || sheet == nil {
+				continue
+			}
+			if len(row) == 0 {
+				emptyRows += rowRepeat
+				continue
+			}
+			for ; emptyRows > 0; emptyRows-- {
+				sheet.Rows = append(sheet.Rows, nil)
+			}
+			for i := 0; i < rowRepeat; i++ {
+				sheet.Rows = append(sheet.Rows, append([]interface{}(nil), row...))
+			}
+		}
+	}
+	return wb, nil
+}
+
+// odsCell consumes a cell element and returns its typed value, or nil
+// for an empty cell. The office:value-type attribute decides the type;
+// the displayed text is used for strings and anything not otherwise
+// understood (e.g. durations).
+func odsCell(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	text, err := odsCellText(dec)
+	if err != nil {
+		return nil, err
+	}
+	switch attr(start, odsOffice, "value-type") {
+	case "":
+		return nil, nil
+	case "float", "percentage", "currency":
+		if n, err := number(attr(start, odsOffice, "value")); err == nil {
+			return n, nil
+		}
+	case "date":
+		if t, ok := odsDate(attr(start, odsOffice, "date-value")); ok {
+			return t, nil
+		}
+	case "boolean":
+		return attr(start, odsOffice, "boolean-value") == "true", nil
+	case "string":
+		for _, a := range start.Attr {
+			if a.Name.Space == odsOffice && a.Name.Local == "string-value" {
+				return a.Value, nil
+			}
+		}
+	}
+	return text, nil
+}
+
+// odsCellText reads a cell's content up to its end tag, joining
+// paragraphs with newlines and skipping comments.
+func odsCellText(dec *xml.Decoder) (string, error) {
+	var b strings.Builder
+	paragraphs := 0
+	for depth := 1; depth > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "annotation" {
+				if err := dec.Skip(); err != nil {
+					return "", err
+				}
+				continue
+			}
+			depth++
+			if t.Name.Space != odsText {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				if paragraphs > 0 {
+					b.WriteByte('\n')
+				}
+				paragraphs++
+			case "s":
+				b.WriteString(strings.Repeat(" ", repeat(attr(t, odsText, "c"))))
+			case "tab":
+				b.WriteByte('\t')
+			case "line-break":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			// Whitespace directly inside the cell is indentation.
+			if depth > 1 {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+func odsDate(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func attr(e xml.StartElement, space, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// repeat parses a repetition count, defaulting to 1.
+func repeat(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,254 @@
+// Package spreadsheet imports Excel (.xlsx) and OpenDocument (.ods)
+// workbooks into Nexus. Each mapped sheet becomes nodes of one label,
+// one node per row, with properties named by the sheet's header row:
+//
+//	wb, err := spreadsheet.Open("accounts.xlsx")
+//	...
+//	report, err := spreadsheet.Import(ctx, wb, client.NewBulkLoader(nexus.BulkLoaderOptions{}), spreadsheet.Mapping{
+//	    Sheets: []spreadsheet.SheetMapping{
+//	        {Sheet: "Customers", Label: "Customer", Key: "id"},
+//	        {Sheet: "Orders", Label: "Order", Key: "id", Rename: map[string]string{"Order Total": "total"}},
+//	    },
+//	})
+//
+// Workbooks are read with the standard library only. Cells arrive as
+// strings, int64 or float64 numbers, bools, or time.Time for
+// date-formatted cells; formulas contribute their cached result and
+// error cells (#N/A, #DIV/0!, …) are empty.
+package spreadsheet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+)
+
+// Workbook is a fully read spreadsheet file.
+type Workbook struct {
+	Sheets []*Sheet
+}
+
+// Sheet is one worksheet. Rows[i] holds row i+1 of the sheet; a nil
+// cell is empty and rows are not padded to a common width.
+type Sheet struct {
+	Name string
+	Rows [][]interface{}
+}
+
+// Open reads an .xlsx (or .xlsm) or .ods file, choosing the format from
+// the extension.
+func Open(path string) (*Workbook, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xlsm":
+		return ReadXLSX(f, info.Size())
+	case ".ods":
+		return ReadODS(f, info.Size())
+	}
+	return nil, fmt.Errorf("spreadsheet: unsupported file type %q (want .xlsx or .ods)", filepath.Ext(path))
+}
+
+// Sheet returns the sheet called name, matching case-insensitively if
+// there is no exact match, or nil.
+func (w *Workbook) Sheet(name string) *Sheet {
+	for _, s := range w.Sheets {
+		if s.Name == name {
+			return s
+		}
+	}
+	for _, s := range w.Sheets {
+		if strings.EqualFold(s.Name, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Records returns the rows below headerRow (1-based; 0 means the first
+// row) as header → value maps. Columns with a blank header are ignored,
+// empty cells are left out and blank rows are skipped. Dates become
+// RFC 3339 strings, or YYYY-MM-DD when they carry no time of day.
+func (s *Sheet) Records(headerRow int) ([]map[string]interface{}, error) {
+	var records []map[string]interface{}
+	err := s.eachRecord(headerRow, func(_ int, rec map[string]interface{}) error {
+		records = append(records, rec)
+		return nil
+	})
+	return records, err
+}
+
+// eachRecord calls fn with the 1-based row number of every non-blank
+// record below headerRow.
+func (s *Sheet) eachRecord(headerRow int, fn func(int, map[string]interface{}) error) error {
+	if headerRow <= 0 {
+		headerRow = 1
+	}
+	if headerRow > len(s.Rows) {
+		return nil
+	}
+	headers := make([]string, len(s.Rows[headerRow-1]))
+	seen := map[string]bool{}
+	for i, cell := range s.Rows[headerRow-1] {
+		if cell == nil {
+			continue
+		}
+		h := strings.TrimSpace(fmt.Sprint(property(cell)))
+		if h == "" {
+			continue
+		}
+		if seen[h] {
+			return fmt.Errorf("duplicate header %q in row %d", h, headerRow)
+		}
+		seen[h] = true
+		headers[i] = h
+	}
+	for i, row := range s.Rows[headerRow:] {
+		rec := map[string]interface{}{}
+		for col, cell := range row {
+			if col >= len(headers) || headers[col] == "" || cell == nil {
+				continue
+			}
+			if str, ok := cell.(string); ok && strings.TrimSpace(str) == "" {
+				continue
+			}
+			rec[headers[col]] = property(cell)
+		}
+		if len(rec) == 0 {
+			continue
+		}
+		if err := fn(headerRow+i+1, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SheetMapping maps a sheet to nodes, one per row below the header.
+type SheetMapping struct {
+	// Sheet is the sheet name.
+	Sheet string
+	// Label is the node label; ExtraLabels are added alongside it.
+	Label       string
+	ExtraLabels []string
+	// Key is the header of the column identifying the row; nodes are
+	// MERGEd on it so re-importing an edited spreadsheet updates instead
+	// of duplicating. The column is stored under its (renamed) property
+	// name.
+	Key string
+	// Rename maps header → property name; unlisted columns keep their
+	// header as the property name.
+	Rename map[string]string
+	// Exclude lists headers not stored as properties.
+	Exclude []string
+	// HeaderRow is the 1-based row holding the headers (default 1);
+	// rows above it are ignored.
+	HeaderRow int
+}
+
+// Mapping is a full import definition. With no sheet mappings every
+// sheet is imported under a label named after it.
+type Mapping struct {
+	Sheets []SheetMapping
+}
+
+// Report counts imported rows per label.
+type Report struct {
+	Nodes map[string]int
+}
+
+// Import writes the mapped sheets of wb through loader, flushing it at
+// the end.
+func Import(ctx context.Context, wb *Workbook, loader *nexus.BulkLoader, m Mapping) (*Report, error) {
+	report := &Report{Nodes: map[string]int{}}
+
+	mappings := m.Sheets
+	if len(mappings) == 0 {
+		for _, s := range wb.Sheets {
+			mappings = append(mappings, SheetMapping{Sheet: s.Name, Label: s.Name})
+		}
+	}
+	for _, sm := range mappings {
+		if sm.Label == "" {
+			return report, errors.New("spreadsheet: sheet mapping needs a label")
+		}
+		sheet := wb.Sheet(sm.Sheet)
+		if sheet == nil {
+			return report, fmt.Errorf("spreadsheet: no sheet named %q", sm.Sheet)
+		}
+		excluded := map[string]bool{}
+		for _, h := range sm.Exclude {
+			excluded[h] = true
+		}
+		labels := append([]string{sm.Label}, sm.ExtraLabels...)
+		key := ""
+		if sm.Key != "" {
+			key = renamed(sm.Rename, sm.Key)
+		}
+
+		err := sheet.eachRecord(sm.HeaderRow, func(line int, rec map[string]interface{}) error {
+			props := make(map[string]interface{}, len(rec))
+			for h, v := range rec {
+				if !excluded[h] {
+					props[renamed(sm.Rename, h)] = v
+				}
+			}
+			report.Nodes[sm.Label]++
+			if err := loader.AddNode(ctx, nexus.BulkNode{Labels: labels, Properties: props, Key: key}); err != nil {
+				return fmt.Errorf("row %d: %w", line, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return report, fmt.Errorf("spreadsheet: import %s from sheet %q: %w", sm.Label, sheet.Name, err)
+		}
+	}
+	return report, loader.Flush(ctx)
+}
+
+// property maps a cell onto a graph property value.
+func property(v interface{}) interface{} {
+	t, ok := v.(time.Time)
+	if !ok {
+		return v
+	}
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// number parses a numeric cell, keeping whole numbers as int64 the way
+// ExecuteCypher reports them.
+func number(s string) (interface{}, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return nil, err
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f), nil
+	}
+	return f, nil
+}
+
+func renamed(rename map[string]string, header string) string {
+	if p, ok := rename[header]; ok {
+		return p
+	}
+	return header
+}
@@ -0,0 +1,266 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zipped builds an in-memory archive from name → content.
+func zipped(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+var xlsxFiles = map[string]string{
+	"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets>
+    <sheet name="Customers" sheetId="1" r:id="rId7"/>
+    <sheet name="Notes" sheetId="2" r:id="rId8"/>
+  </sheets>
+</workbook>`,
+	"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId7" Type="worksheet" Target="worksheets/customers.xml"/>
+  <Relationship Id="rId8" Type="worksheet" Target="/xl/worksheets/notes.xml"/>
+</Relationships>`,
+	"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <si><t>id</t></si>
+  <si><t>Full Name</t></si>
+  <si><t>joined</t></si>
+  <si><t>vip</t></si>
+  <si><r><t>Ann </t></r><r><t>Lee</t></r><rPh><t>x</t></rPh></si>
+  <si><t>score</t></si>
+</sst>`,
+	"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <numFmts count="2">
+    <numFmt numFmtId="164" formatCode="yyyy\-mm\-dd hh:mm"/>
+    <numFmt numFmtId="165" formatCode="[Red]#,##0.00&quot; units&quot;"/>
+  </numFmts>
+  <cellXfs count="4">
+    <xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="165"/>
+  </cellXfs>
+</styleSheet>`,
+	"xl/worksheets/customers.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>
+    <row r="1">
+      <c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c>
+      <c r="D1" t="s"><v>3</v></c><c r="E1" t="s"><v>5</v></c>
+    </row>
+    <row r="2">
+      <c r="A2"><v>1</v></c><c r="B2" t="s"><v>4</v></c><c r="C2" s="1"><v>45000</v></c>
+      <c r="D2" t="b"><v>1</v></c><c r="E2" s="3"><v>9.5</v></c>
+    </row>
+    <row r="3"><c r="A3" s="1"/></row>
+    <row r="4">
+      <c r="A4"><v>2</v></c><c r="B4" t="inlineStr"><is><t>Bob</t></is></c>
+      <c r="C4" s="2"><v>45000.5</v></c><c r="E4" t="e"><v>#DIV/0!</v></c>
+    </row>
+  </sheetData>
+</worksheet>`,
+	"xl/worksheets/notes.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>
+    <row><c t="str"><v>text</v></c></row>
+    <row><c t="str"><v>hello</v></c></row>
+  </sheetData>
+</worksheet>`,
+}
+
+func TestReadXLSX(t *testing.T) {
+	data := zipped(t, xlsxFiles)
+	wb, err := ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, wb.Sheets, 2)
+
+	customers := wb.Sheet("customers")
+	require.NotNil(t, customers)
+	assert.Equal(t, "Customers", customers.Name)
+	require.Len(t, customers.Rows, 4)
+	assert.Equal(t, []interface{}{
+		int64(1), "Ann Lee", time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC), true, 9.5,
+	}, customers.Rows[1])
+	assert.Nil(t, customers.Rows[2])
+
+	records, err := customers.Records(0)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"id": int64(1), "Full Name": "Ann Lee", "joined": "2023-03-15", "vip": true, "score": 9.5},
+		{"id": int64(2), "Full Name": "Bob", "joined": "2023-03-15T12:00:00Z"},
+	}, records)
+
+	notes, err := wb.Sheet("Notes").Records(1)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"text": "hello"}}, notes)
+}
+
+func TestReadODS(t *testing.T) {
+	data := zipped(t, map[string]string{
+		"mimetype": "application/vnd.oasis.opendocument.spreadsheet",
+		"content.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+    xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+    xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+  <office:body><office:spreadsheet>
+    <table:table table:name="People">
+      <table:table-column table:number-columns-repeated="4"/>
+      <table:table-row>
+        <table:table-cell office:value-type="string"><text:p>name</text:p></table:table-cell>
+        <table:table-cell office:value-type="string"><text:p>age</text:p></table:table-cell>
+        <table:table-cell table:number-columns-repeated="2"/>
+        <table:table-cell office:value-type="string"><text:p>born</text:p></table:table-cell>
+        <table:table-cell office:value-type="string"><text:p>active</text:p></table:table-cell>
+      </table:table-row>
+      <table:table-row>
+        <table:table-cell office:value-type="string">
+          <office:annotation><text:p>a comment</text:p></office:annotation>
+          <text:p>Ann<text:s text:c="2"/>Lee</text:p><text:p>second line</text:p>
+        </table:table-cell>
+        <table:table-cell office:value-type="float" office:value="42"><text:p>42</text:p></table:table-cell>
+        <table:table-cell table:number-columns-repeated="2"/>
+        <table:table-cell office:value-type="date" office:date-value="1981-07-02"><text:p>02/07/81</text:p></table:table-cell>
+        <table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell>
+        <table:table-cell table:number-columns-repeated="16378"/>
+      </table:table-row>
+      <table:table-row table:number-rows-repeated="2">
+        <table:table-cell table:number-columns-repeated="16384"/>
+      </table:table-row>
+      <table:table-row table:number-rows-repeated="2">
+        <table:table-cell office:value-type="string"><text:p>Bob</text:p></table:table-cell>
+        <table:table-cell office:value-type="percentage" office:value="0.25"><text:p>25%</text:p></table:table-cell>
+      </table:table-row>
+      <table:table-row table:number-rows-repeated="1048570">
+        <table:table-cell table:number-columns-repeated="16384"/>
+      </table:table-row>
+    </table:table>
+  </office:spreadsheet></office:body>
+</office:document-content>`,
+	})
+	wb, err := ReadODS(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, wb.Sheets, 1)
+
+	people := wb.Sheet("People")
+	require.NotNil(t, people)
+	require.Len(t, people.Rows, 6)
+	assert.Equal(t, []interface{}{
+		"Ann  Lee\nsecond line", int64(42), nil, nil, time.Date(1981, 7, 2, 0, 0, 0, 0, time.UTC), true,
+	}, people.Rows[1])
+
+	records, err := people.Records(1)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"name": "Ann  Lee\nsecond line", "age": int64(42), "born": "1981-07-02", "active": true},
+		{"name": "Bob", "age": 0.25},
+		{"name": "Bob", "age": 0.25},
+	}, records)
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "book.XLSX")
+	require.NoError(t, os.WriteFile(name, zipped(t, xlsxFiles), 0o600))
+	wb, err := Open(name)
+	require.NoError(t, err)
+	assert.Len(t, wb.Sheets, 2)
+
+	_, err = Open(filepath.Join(dir, "book.csv"))
+	assert.Error(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "book.numbers"), nil, 0o600))
+	_, err = Open(filepath.Join(dir, "book.numbers"))
+	assert.ErrorContains(t, err, "unsupported file type")
+}
+
+func TestRecordsDuplicateHeader(t *testing.T) {
+	sheet := &Sheet{Rows: [][]interface{}{{"a", "a"}, {int64(1), int64(2)}}}
+	_, err := sheet.Records(1)
+	assert.ErrorContains(t, err, `duplicate header "a"`)
+}
+
+func TestIsDateFormat(t *testing.T) {
+	for code, want := range map[string]bool{
+		"yyyy-mm-dd":            true,
+		"[$-409]h:mm AM/PM":     true,
+		"0.00":                  false,
+		`#,##0" days"`:          false,
+		"[Red]0.00":             false,
+		`0.0\m`:                 false,
+		"General":               false,
+		`_(* #,##0_);_(* (#)`:   false,
+		"dd/mm/yyyy\\ hh:mm:ss": true,
+	} {
+		assert.Equal(t, want, isDateFormat(code), code)
+	}
+}
+
+func TestImport(t *testing.T) {
+	data := zipped(t, xlsxFiles)
+	wb, err := ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	type call struct {
+		Query string
+		Rows  []map[string]interface{}
+	}
+	var calls []call
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string `json:"query"`
+			Parameters struct {
+				Rows []map[string]interface{} `json:"rows"`
+			} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		calls = append(calls, call{req.Query, req.Parameters.Rows})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	client := nexus.NewClient(nexus.Config{BaseURL: server.URL})
+	loader := client.NewBulkLoader(nexus.BulkLoaderOptions{})
+	report, err := Import(context.Background(), wb, loader, Mapping{
+		Sheets: []SheetMapping{{
+			Sheet: "Customers", Label: "Customer", ExtraLabels: []string{"Imported"}, Key: "id",
+			Rename: map[string]string{"Full Name": "name"}, Exclude: []string{"score"},
+		}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"Customer": 2}, report.Nodes)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "UNWIND $rows AS row MERGE (n:Customer {id: row.key}) SET n += row.props, n:Imported", calls[0].Query)
+	assert.Equal(t, map[string]interface{}{
+		"id": float64(1), "name": "Ann Lee", "joined": "2023-03-15", "vip": true,
+	}, calls[0].Rows[0]["props"])
+
+	_, err = Import(context.Background(), wb, loader, Mapping{Sheets: []SheetMapping{{Sheet: "Missing", Label: "X"}}})
+	assert.ErrorContains(t, err, `no sheet named "Missing"`)
+
+	_, err = Import(context.Background(), wb, loader, Mapping{Sheets: []SheetMapping{{Sheet: "Notes", Label: "Note", Key: "id"}}})
+	assert.ErrorContains(t, err, "row 2")
+}
@@ -0,0 +1,319 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadXLSX reads an Office Open XML workbook (.xlsx).
+func ReadXLSX(r io.ReaderAt, size int64) (*Workbook, error) {
+	wb, err := readXLSX(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("spreadsheet: %w", err)
+	}
+	return wb, nil
+}
+
+func readXLSX(r io.ReaderAt, size int64) (*Workbook, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var book struct {
+		Pr struct {
+			Date1904 bool `xml:"date1904,attr"`
+		} `xml:"workbookPr"`
+		Sheets []struct {
+			Name  string     `xml:"name,attr"`
+			Attrs []xml.Attr `xml:",any,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if files["xl/workbook.xml"] == nil {
+		return nil, errors.New("not an xlsx workbook (no xl/workbook.xml)")
+	}
+	if err := decodeXML(files["xl/workbook.xml"], &book); err != nil {
+		return nil, err
+	}
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if f := files["xl/_rels/workbook.xml.rels"]; f != nil {
+		if err := decodeXML(f, &rels); err != nil {
+			return nil, err
+		}
+	}
+	targets := map[string]string{}
+	for _, rel := range rels.Rels {
+		if strings.HasPrefix(rel.Target, "/") {
+			targets[rel.ID] = strings.TrimPrefix(rel.Target, "/")
+		} else {
+			targets[rel.ID] = path.Join("xl", rel.Target)
+		}
+	}
+
+	strs, err := sharedStrings(files["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+	dates, err := dateStyles(files["xl/styles.xml"])
+	if err != nil {
+		return nil, err
+	}
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if book.Pr.Date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	wb := &Workbook{}
+	for i, s := range book.Sheets {
+		name := ""
+		for _, a := range s.Attrs {
+			if a.Name.Local == "id" {
+				name = targets[a.Value]
+			}
+		}
+		if files[name] == nil {
+			// Workbooks written without relationships use the default
+			// part names.
+			name = fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)
+		}
+		f := files[name]
+		if f == nil {
+			return nil, fmt.Errorf("sheet %q has no worksheet part", s.Name)
+		}
+		sheet, err := readWorksheet(f, strs, dates, epoch)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %q: %w", s.Name, err)
+		}
+		sheet.Name = s.Name
+		wb.Sheets = append(wb.Sheets, sheet)
+	}
+	return wb, nil
+}
+
+type xlsxText struct {
+	T    *string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String joins rich-text runs; phonetic hints (rPh) are left out.
+func (t xlsxText) String() string {
+	if t.T != nil {
+		return *t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+func sharedStrings(f *zip.File) ([]string, error) {
+	if f == nil {
+		return nil, nil
+	}
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if err := decodeXML(f, &sst); err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(sst.Items))
+	for i, si := range sst.Items {
+		strs[i] = si.String()
+	}
+	return strs, nil
+}
+
+// dateStyles reports, per cell style index, whether the style's number
+// format displays a date or time. Excel stores dates as plain serial
+// numbers, so the format is the only way to tell them apart.
+func dateStyles(f *zip.File) ([]bool, error) {
+	if f == nil {
+		return nil, nil
+	}
+	var styles struct {
+		NumFmts []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		Xfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+	if err := decodeXML(f, &styles); err != nil {
+		return nil, err
+	}
+	custom := map[int]bool{}
+	for _, nf := range styles.NumFmts {
+		custom[nf.ID] = isDateFormat(nf.Code)
+	}
+	dates := make([]bool, len(styles.Xfs))
+	for i, xf := range styles.Xfs {
+		if isDate, ok := custom[xf.NumFmtID]; ok {
+			dates[i] = isDate
+			continue
+		}
+		id := xf.NumFmtID
+		dates[i] = id >= 14 && id <= 22 || id >= 27 && id <= 36 || id >= 45 && id <= 47 || id >= 50 && id <= 58
+	}
+	return dates, nil
+}
+
+// isDateFormat reports whether a custom number format shows date or
+// time parts once quoted text, escapes and [colour] sections are
+// dropped.
+func isDateFormat(code string) bool {
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; c {
+		case '"':
+			if j := strings.IndexByte(code[i+1:], '"'); j >= 0 {
+				i += j + 1
+			}
+		case '[':
+			if j := strings.IndexByte(code[i+1:], ']'); j >= 0 {
+				i += j + 1
+			}
+		case '\\', '_', '*':
+			i++
+		case 'd', 'D', 'm', 'M', 'y', 'Y', 'h', 'H', 's', 'S':
+			return true
+		}
+	}
+	return false
+}
+
+func readWorksheet(f *zip.File, strs []string, dates []bool, epoch time.Time) (*Sheet, error) {
+	var ws struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Style  int      `xml:"s,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeXML(f, &ws); err != nil {
+		return nil, err
+	}
+	sheet := &Sheet{}
+	rowNum := 0
+	for _, row := range ws.Rows {
+		rowNum++
+		if row.R > 0 {
+			rowNum = row.R
+		}
+		var cells []interface{}
+		col := -1
+		for _, c := range row.Cells {
+			col++
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			var v interface{}
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(strs) {
+					return nil, fmt.Errorf("cell %s: bad shared string index %q", c.Ref, c.Value)
+				}
+				v = strs[i]
+			case "inlineStr":
+				v = c.Inline.String()
+			case "str":
+				v = c.Value
+			case "b":
+				v = c.Value == "1"
+			case "e":
+				// Error cells (#N/A, #REF!, …) are treated as empty.
+			case "d":
+				t, err := time.Parse(time.RFC3339Nano, c.Value)
+				if err != nil {
+					t, err = time.Parse("2006-01-02T15:04:05", c.Value)
+				}
+				if err == nil {
+					v = t.UTC()
+				}
+			default:
+				if c.Value == "" {
+					break
+				}
+				if c.Style >= 0 && c.Style < len(dates) && dates[c.Style] {
+					serial, err := strconv.ParseFloat(c.Value, 64)
+					if err != nil {
+						return nil, fmt.Errorf("cell %s: %w", c.Ref, err)
+					}
+					ms := math.Round(serial * 24 * 60 * 60 * 1000)
+					v = epoch.Add(time.Duration(ms) * time.Millisecond)
+					break
+				}
+				n, err := number(c.Value)
+				if err != nil {
+					return nil, fmt.Errorf("cell %s: %w", c.Ref, err)
+				}
+				v = n
+			}
+			if v == nil {
+				continue
+			}
+			for len(cells) < col {
+				cells = append(cells, nil)
+			}
+			cells = append(cells, v)
+		}
+		if len(cells) == 0 {
+			continue
+		}
+		for len(sheet.Rows) < rowNum-1 {
+			sheet.Rows = append(sheet.Rows, nil)
+		}
+		sheet.Rows = append(sheet.Rows, cells)
+	}
+	return sheet, nil
+}
+
+// columnIndex turns the letters of a cell reference ("C7", "AB12") into
+// a 0-based column index.
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A') + 1
+	}
+	return col - 1
+}
+
+func decodeXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
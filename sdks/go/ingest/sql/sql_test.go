@@ -0,0 +1,129 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver serves canned tables keyed by SQL text.
+type fakeDriver struct{ tables map[string]fakeTable }
+
+type fakeTable struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	t, ok := c.d.tables[query]
+	if !ok {
+		return nil, errors.New("unknown query: " + query)
+	}
+	return &fakeStmt{t}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ t fakeTable }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) { return &fakeRows{t: s.t}, nil }
+
+type fakeRows struct {
+	t fakeTable
+	i int
+}
+
+func (r *fakeRows) Columns() []string { return r.t.cols }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.t.rows) {
+		return io.EOF
+	}
+	copy(dest, r.t.rows[r.i])
+	r.i++
+	return nil
+}
+
+// The driver is registered once per process: sql.Register panics on a
+// second registration, which a repeated run (-count=2) would make.
+func init() {
+	dbsql.Register("nexus-fake", &fakeDriver{tables: map[string]fakeTable{
+		"SELECT * FROM customers": {cols: []string{"id", "name"}, rows: [][]driver.Value{
+			{int64(1), []byte("Ann")}, {int64(2), []byte("Bob")},
+		}},
+		"SELECT * FROM orders": {cols: []string{"id", "customer_id", "total"}, rows: [][]driver.Value{
+			{int64(10), int64(1), 9.5}, {int64(11), nil, 3.0},
+		}},
+	}})
+}
+
+func TestImport(t *testing.T) {
+	db, err := dbsql.Open("nexus-fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	type call struct {
+		Query string
+		Rows  []map[string]interface{}
+	}
+	var calls []call
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string `json:"query"`
+			Parameters struct {
+				Rows []map[string]interface{} `json:"rows"`
+			} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		calls = append(calls, call{req.Query, req.Parameters.Rows})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	client := nexus.NewClient(nexus.Config{BaseURL: server.URL})
+	loader := client.NewBulkLoader(nexus.BulkLoaderOptions{})
+	report, err := Import(context.Background(), db, loader, Mapping{
+		Nodes: []NodeMapping{
+			{Table: "customers", Label: "Customer", Key: "id"},
+			{Table: "orders", Label: "Order", Key: "id", Exclude: []string{"customer_id"}},
+		},
+		Relationships: []RelationshipMapping{{
+			Table: "orders", Type: "PLACED",
+			From: EndpointMapping{Label: "Customer", Key: "id", Column: "customer_id"},
+			To:   EndpointMapping{Label: "Order", Key: "id", Column: "id"},
+		}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"Customer": 2, "Order": 2}, report.Nodes)
+	assert.Equal(t, map[string]int{"PLACED": 1}, report.Relationships)
+	assert.Equal(t, 1, report.NullEndpoints)
+
+	require.Len(t, calls, 3)
+	assert.Equal(t, "UNWIND $rows AS row MERGE (n:Customer {id: row.key}) SET n += row.props", calls[0].Query)
+	assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "Ann"}, calls[0].Rows[0]["props"])
+	assert.NotContains(t, calls[1].Rows[0]["props"], "customer_id")
+	assert.Equal(t, "UNWIND $rows AS row MATCH (a:Customer {id: row.from}) MATCH (b:Order {id: row.to}) "+
+		"MERGE (a)-[r:PLACED]->(b) SET r += row.props", calls[2].Query)
+	assert.Equal(t, nexus.BulkStats{Nodes: 4, Relationships: 1, Batches: 3}, loader.Stats())
+}
@@ -0,0 +1,203 @@
+// Package cypherlex splits Cypher queries into tokens. It is a lexer,
+// not a parser: the client's guardrails, row filters and fingerprints
+// and nl2cypher's validator all work on its token stream. It also holds
+// the rules for writing names into query text, shared by every package
+// that builds Cypher.
+package cypherlex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind is the lexical class of a Token.
+type Kind int
+
+const (
+	Word Kind = iota
+	Quoted
+	String
+	Number
+	Param
+	Punct
+)
+
+// Token is one lexical token of a query, with its byte span.
+type Token struct {
+	Kind       Kind
+	Text       string
+	Start, End int
+	// Depth is the bracket nesting of the token; In is the innermost
+	// open bracket: '(', '[', '{', 'r' for a relationship pattern's
+	// brackets, or 0 at top level.
+	Depth int
+	In    byte
+}
+
+// Tokenize splits a query into tokens, dropping whitespace and
+// comments. Problems report unterminated literals and comments, which
+// end the token stream, and unbalanced brackets, which do not: a stray
+// closing bracket closes the innermost open one, if any.
+func Tokenize(q string) ([]Token, []string) {
+	var (
+		toks     []Token
+		problems []string
+		stack    []byte
+	)
+	top := func() byte {
+		if len(stack) == 0 {
+			return 0
+		}
+		return stack[len(stack)-1]
+	}
+	add := func(kind Kind, start, end int) {
+		toks = append(toks, Token{Kind: kind, Text: q[start:end], Start: start, End: end, Depth: len(stack), In: top()})
+	}
+	for i := 0; i < len(q); {
+		c := q[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(q[i:], "//"):
+			for i < len(q) && q[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(q[i:], "/*"):
+			end := strings.Index(q[i+2:], "*/")
+			if end < 0 {
+				return toks, append(problems, "unterminated comment")
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(q) {
+				if q[j] == c {
+					// A doubled backtick is a backtick inside the name.
+					if c != '`' || j+1 >= len(q) || q[j+1] != '`' {
+						break
+					}
+					j++
+				} else if q[j] == '\\' && c != '`' {
+					j++
+				}
+				j++
+			}
+			if j >= len(q) {
+				return toks, append(problems, fmt.Sprintf("unterminated %c literal", c))
+			}
+			kind := String
+			if c == '`' {
+				kind = Quoted
+			}
+			add(kind, i, j+1)
+			i = j + 1
+		case c == '$' || IsIdentStart(c):
+			j := i + 1
+			for j < len(q) && IsIdentPart(q[j]) {
+				j++
+			}
+			kind := Word
+			if c == '$' {
+				kind = Param
+			}
+			add(kind, i, j)
+			i = j
+		case c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(q) && (IsIdentPart(q[j]) || q[j] == '.' && j+1 < len(q) && q[j+1] >= '0' && q[j+1] <= '9') {
+				j++
+			}
+			add(Number, i, j)
+			i = j
+		case c == '(' || c == '[' || c == '{':
+			add(Punct, i, i+1)
+			open := c
+			if c == '[' && len(toks) >= 2 && toks[len(toks)-2].Text == "-" {
+				open = 'r'
+			}
+			stack = append(stack, open)
+			i++
+		case c == ')' || c == ']' || c == '}':
+			want := map[byte]byte{')': '(', ']': '[', '}': '{'}[c]
+			got := top()
+			if got == 'r' {
+				got = '['
+			}
+			if got != want {
+				problems = append(problems, fmt.Sprintf("unbalanced %q at offset %d", c, i))
+			}
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			add(Punct, i, i+1)
+			i++
+		default:
+			add(Punct, i, i+1)
+			i++
+		}
+	}
+	if len(stack) > 0 {
+		problems = append(problems, fmt.Sprintf("%d unclosed bracket(s)", len(stack)))
+	}
+	return toks, problems
+}
+
+// IsIdentStart reports whether c can start an unquoted identifier.
+func IsIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// IsIdentPart reports whether c can continue an unquoted identifier.
+func IsIdentPart(c byte) bool { return IsIdentStart(c) || c >= '0' && c <= '9' }
+
+// IsPlainName reports whether name can be written without backticks as
+// a label, type or property name: ASCII letters, digits and
+// underscores, not starting with a digit. Labels and types interpolated
+// into query text are held to it.
+func IsPlainName(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// QuoteName writes a label, type or property name bare when it is
+// plain and in backticks otherwise.
+func QuoteName(name string) string {
+	if IsPlainName(name) {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Name is the identifier a word or backtick-quoted token spells.
+func (t Token) Name() string {
+	if t.Kind == Quoted {
+		return strings.ReplaceAll(t.Text[1:len(t.Text)-1], "``", "`")
+	}
+	return t.Text
+}
+
+// Punct reports whether t is the punctuation s.
+func (t Token) Punct(s string) bool { return t.Kind == Punct && t.Text == s }
+
+// Keyword reports whether t is the word kw, in any case.
+func (t Token) Keyword(kw string) bool { return t.Kind == Word && strings.EqualFold(t.Text, kw) }
+
+// Clause reports whether toks[i] is the clause keyword kw: a word, not
+// a property (n.set), label (:Set) or map key ({set: 1}).
+func Clause(toks []Token, i int, kw string) bool {
+	if !toks[i].Keyword(kw) {
+		return false
+	}
+	if i > 0 && (toks[i-1].Punct(".") || toks[i-1].Punct(":")) {
+		return false
+	}
+	return i+1 >= len(toks) || !toks[i+1].Punct(":")
+}
@@ -0,0 +1,58 @@
+package cypherlex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	toks, problems := Tokenize("MATCH (a)-[:`KNOWS`*2]->(b {n: 'x)'}) // RETURN\nRETURN b.set")
+	assert.Empty(t, problems)
+	var texts []string
+	for _, tok := range toks {
+		texts = append(texts, tok.Text)
+	}
+	assert.Equal(t, []string{"MATCH", "(", "a", ")", "-", "[", ":", "`KNOWS`", "*", "2", "]", "-", ">",
+		"(", "b", "{", "n", ":", "'x)'", "}", ")", "RETURN", "b", ".", "set"}, texts)
+	assert.Equal(t, byte('r'), toks[6].In)
+	assert.Equal(t, "KNOWS", toks[7].Name())
+	assert.Equal(t, 2, toks[16].Depth)
+	assert.True(t, Clause(toks, 21, "return"))
+	assert.False(t, Clause(toks, 24, "SET"), "a property is not a clause")
+	assert.False(t, Clause(toks, 16, "N"), "a map key is not a clause")
+
+	// A stray bracket is reported but does not end the stream.
+	toks, problems = Tokenize("MATCH (n)) DETACH DELETE n")
+	assert.Equal(t, []string{`unbalanced ')' at offset 9`}, problems)
+	assert.True(t, Clause(toks, 5, "DETACH"))
+
+	_, problems = Tokenize("RETURN 'open")
+	assert.Equal(t, []string{"unterminated ' literal"}, problems)
+	_, problems = Tokenize("MATCH (n RETURN n /* x")
+	assert.Equal(t, []string{"unterminated comment"}, problems)
+	_, problems = Tokenize("MATCH (n RETURN n")
+	assert.Equal(t, []string{"1 unclosed bracket(s)"}, problems)
+}
+
+func TestQuoteName(t *testing.T) {
+	for name, want := range map[string]string{
+		"Person":  "Person",
+		"_tmp2":   "_tmp2",
+		"org-id":  "`org-id`",
+		"2nd":     "`2nd`",
+		"a`b":     "`a``b`",
+		"café":    "`café`",
+		"":        "``",
+		"x y) //": "`x y) //`",
+	} {
+		assert.Equal(t, want, QuoteName(name), name)
+		assert.Equal(t, want == name, IsPlainName(name), name)
+
+		toks, problems := Tokenize("MATCH (n:" + QuoteName(name) + ") RETURN n")
+		assert.Empty(t, problems, name)
+		if assert.Len(t, toks, 8, name) {
+			assert.Equal(t, name, toks[4].Name(), name)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Package ids formats the entity IDs found in query results.
+package ids
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Format returns the ID in cell v as the decimal string the server
+// issued. JSON decoding yields IDs as float64, which fmt prints in
+// exponent form (1.234567e+06) past six digits; integral values are
+// printed as integers instead.
+func Format(v interface{}) string {
+	switch n := v.(type) {
+	case string:
+		return n
+	case int:
+		return strconv.Itoa(n)
+	case int64:
+		return strconv.FormatInt(n, 10)
+	case uint64:
+		return strconv.FormatUint(n, 10)
+	case json.Number:
+		return n.String()
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) < 1<<63 {
+			return strconv.FormatInt(int64(n), 10)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,22 @@
+package ids
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	for v, want := range map[interface{}]string{
+		float64(1234567):        "1234567",
+		int64(9007199254740993): "9007199254740993",
+		42:                      "42",
+		uint64(1 << 63):         "9223372036854775808",
+		"n1":                    "n1",
+		json.Number("17"):       "17",
+		1.5:                     "1.5",
+	} {
+		assert.Equal(t, want, Format(v), "%T %v", v, v)
+	}
+}
@@ -0,0 +1,62 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterateNodes(t *testing.T) {
+	ids := []int{2, 5, 7, 11, 13}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string                 `json:"query"`
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "MATCH (n:Person) WHERE $__after IS NULL OR id(n) > $__after")
+		after, _ := req.Parameters["__after"].(float64)
+		limit := int(req.Parameters["__limit"].(float64))
+		rows := [][]interface{}{}
+		for _, id := range ids {
+			if float64(id) > after && len(rows) < limit {
+				rows = append(rows, []interface{}{id, []string{"Person"}, map[string]interface{}{"n": id}})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"id", "labels", "props"}, "rows": rows})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	it, err := client.IterateNodes(ctx, "Person", 2)
+	require.NoError(t, err)
+	var seen []string
+	var checkpoint string
+	for it.Next() {
+		seen = append(seen, it.Node().ID)
+		assert.Equal(t, []string{"Person"}, it.Node().Labels)
+		if it.Node().ID == "7" {
+			checkpoint = it.Checkpoint()
+		}
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"2", "5", "7", "11", "13"}, seen)
+
+	it, err = client.IterateNodesFrom(ctx, "Person", 2, checkpoint)
+	require.NoError(t, err)
+	seen = nil
+	for it.Next() {
+		seen = append(seen, it.Node().ID)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"11", "13"}, seen)
+
+	_, err = client.IterateNodes(ctx, "Bad Label", 10)
+	assert.Error(t, err)
+}
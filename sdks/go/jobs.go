@@ -0,0 +1,327 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Job states reported by the server.
+const (
+	JobStatePending   = "pending"
+	JobStateRunning   = "running"
+	JobStateSucceeded = "succeeded"
+	JobStateFailed    = "failed"
+	JobStateCancelled = "cancelled"
+)
+
+// JobKind classifies asynchronous server work.
+type JobKind string
+
+const (
+	JobImport    JobKind = "import"
+	JobExport    JobKind = "export"
+	JobBackup    JobKind = "backup"
+	JobRestore   JobKind = "restore"
+	JobAlgorithm JobKind = "algorithm"
+	JobEmbedding JobKind = "embedding"
+)
+
+// JobProgress is how far a job has got.
+type JobProgress struct {
+	// Percent is 0–100; it is derived from the item counts when the
+	// server only reports those.
+	Percent    float64
+	ItemsDone  int64
+	ItemsTotal int64
+	// ItemsPerSecond is the server's rate when it reports one, otherwise
+	// the rate observed between the last two status polls.
+	ItemsPerSecond float64
+}
+
+// JobStatus is a snapshot of a job.
+type JobStatus struct {
+	ID         string
+	Kind       JobKind
+	State      string
+	Progress   JobProgress
+	Error      string
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	// Result holds the job's kind-specific output once it succeeded
+	// (for example the manifest of an export), undecoded.
+	Result json.RawMessage
+}
+
+// Done reports whether the job reached a terminal state.
+func (s JobStatus) Done() bool { return jobStateDone(s.State) }
+
+func jobStateDone(state string) bool {
+	return state == JobStateSucceeded || state == JobStateFailed || state == JobStateCancelled
+}
+
+// JobError is returned by Wait for a job that failed or was cancelled.
+type JobError struct {
+	ID    string
+	Kind  JobKind
+	State string
+	// Message is the server's failure reason; empty for cancellations.
+	Message string
+}
+
+func (e *JobError) Error() string {
+	kind := string(e.Kind)
+	if kind == "" {
+		kind = "server"
+	}
+	if e.State == JobStateCancelled {
+		return fmt.Sprintf("nexus: %s job %s was cancelled", kind, e.ID)
+	}
+	return fmt.Sprintf("nexus: %s job %s failed: %s", kind, e.ID, e.Message)
+}
+
+// Job is a handle on one asynchronous server job: an import, export,
+// backup or algorithm run. Every kind is polled, cancelled and awaited
+// the same way. A Job is safe for concurrent use.
+type Job struct {
+	ID   string
+	Kind JobKind
+
+	client *Client
+	path   string
+
+	mu        sync.Mutex
+	status    JobStatus
+	sampledAt time.Time
+}
+
+// JobSpec submits a job through StartJob.
+type JobSpec struct {
+	Kind JobKind `json:"kind"`
+	// Params are the kind-specific settings, such as the source URL of
+	// an import or the algorithm name and configuration of a run.
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// StartJob submits spec via POST /jobs and returns immediately.
+func (c *Client) StartJob(ctx context.Context, spec JobSpec) (*Job, error) {
+	if spec.Kind == "" {
+		return nil, errors.New("nexus: job kind must not be empty")
+	}
+	resp, err := c.doRequest(ctx, http.MethodPost, "/jobs", spec)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	if err := decodeResponse(resp, &raw); err != nil {
+		return nil, err
+	}
+	status, err := decodeJobStatus(raw)
+	if err != nil {
+		return nil, err
+	}
+	if status.Kind == "" {
+		status.Kind = spec.Kind
+	}
+	job := c.jobHandle(status.ID, status.Kind, "/jobs/"+url.PathEscape(status.ID))
+	job.record(status)
+	return job, nil
+}
+
+// GetJob fetches a job by ID.
+func (c *Client) GetJob(ctx context.Context, id string) (*Job, error) {
+	job := c.jobHandle(id, "", "/jobs/"+url.PathEscape(id))
+	status, err := job.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	job.Kind = status.Kind
+	return job, nil
+}
+
+// ListJobs lists jobs, optionally only those of one kind.
+func (c *Client) ListJobs(ctx context.Context, kind JobKind) ([]JobStatus, error) {
+	path := "/jobs"
+	if kind != "" {
+		path += "?kind=" + url.QueryEscape(string(kind))
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Jobs []json.RawMessage `json:"jobs"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	out := make([]JobStatus, 0, len(result.Jobs))
+	for _, raw := range result.Jobs {
+		status, err := decodeJobStatus(raw)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, status)
+	}
+	return out, nil
+}
+
+func (c *Client) jobHandle(id string, kind JobKind, path string) *Job {
+	return &Job{ID: id, Kind: kind, client: c, path: path}
+}
+
+// Status fetches the current status from the server.
+func (j *Job) Status(ctx context.Context) (JobStatus, error) {
+	status, _, err := j.refresh(ctx)
+	return status, err
+}
+
+// LastStatus returns the status seen by the last poll without a round
+// trip.
+func (j *Job) LastStatus() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// refresh polls the job, returning the raw body too for kind-specific
+// wrappers such as EmbeddingJob.
+func (j *Job) refresh(ctx context.Context) (JobStatus, json.RawMessage, error) {
+	resp, err := j.client.doRequest(ctx, http.MethodGet, j.path, nil)
+	if err != nil {
+		return JobStatus{}, nil, err
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	if err := decodeResponse(resp, &raw); err != nil {
+		return JobStatus{}, nil, err
+	}
+	status, err := decodeJobStatus(raw)
+	if err != nil {
+		return JobStatus{}, nil, err
+	}
+	if status.ID == "" {
+		status.ID = j.ID
+	}
+	if status.Kind == "" {
+		status.Kind = j.Kind
+	}
+	return j.record(status), raw, nil
+}
+
+// record stores status, filling in the observed item rate when the
+// server does not report one.
+func (j *Job) record(status JobStatus) JobStatus {
+	now := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if status.Progress.ItemsPerSecond == 0 && !j.sampledAt.IsZero() {
+		if elapsed := now.Sub(j.sampledAt).Seconds(); elapsed > 0 && status.Progress.ItemsDone > j.status.Progress.ItemsDone {
+			status.Progress.ItemsPerSecond = float64(status.Progress.ItemsDone-j.status.Progress.ItemsDone) / elapsed
+		}
+	}
+	j.status, j.sampledAt = status, now
+	return status
+}
+
+// Cancel asks the server to stop the job. Cancellation is asynchronous:
+// Wait reports it once the job has stopped.
+func (j *Job) Cancel(ctx context.Context) error {
+	resp, err := j.client.doRequest(ctx, http.MethodPost, j.path+"/cancel", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// Wait polls the job every pollInterval (default 1s) until it reaches a
+// terminal state or ctx is done. A failed or cancelled job is returned
+// as a *JobError alongside its final status.
+func (j *Job) Wait(ctx context.Context, pollInterval time.Duration) (JobStatus, error) {
+	return pollJob(ctx, pollInterval, j.Status)
+}
+
+// pollJob drives Wait for Job and the kind-specific job types.
+func pollJob(ctx context.Context, pollInterval time.Duration, poll func(context.Context) (JobStatus, error)) (JobStatus, error) {
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := poll(ctx)
+		if err != nil {
+			return status, err
+		}
+		switch status.State {
+		case JobStateSucceeded:
+			return status, nil
+		case JobStateFailed, JobStateCancelled:
+			return status, &JobError{ID: status.ID, Kind: status.Kind, State: status.State, Message: status.Error}
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// decodeJobStatus reads the wire form shared by /jobs and the older
+// kind-specific job routes. `progress` is a 0–1 fraction there.
+func decodeJobStatus(raw json.RawMessage) (JobStatus, error) {
+	var wire struct {
+		ID             string          `json:"id"`
+		Kind           JobKind         `json:"kind"`
+		State          string          `json:"state"`
+		Progress       *float64        `json:"progress"`
+		ItemsDone      int64           `json:"items_done"`
+		ItemsTotal     int64           `json:"items_total"`
+		ItemsPerSecond float64         `json:"items_per_second"`
+		Error          string          `json:"error"`
+		StartedAt      *time.Time      `json:"started_at"`
+		FinishedAt     *time.Time      `json:"finished_at"`
+		Result         json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &wire); err != nil {
+		return JobStatus{}, fmt.Errorf("failed to decode job status: %w", err)
+	}
+	status := JobStatus{
+		ID:    wire.ID,
+		Kind:  wire.Kind,
+		State: wire.State,
+		Progress: JobProgress{
+			ItemsDone:      wire.ItemsDone,
+			ItemsTotal:     wire.ItemsTotal,
+			ItemsPerSecond: wire.ItemsPerSecond,
+		},
+		Error:      wire.Error,
+		StartedAt:  wire.StartedAt,
+		FinishedAt: wire.FinishedAt,
+		Result:     wire.Result,
+	}
+	switch {
+	case wire.Progress != nil:
+		status.Progress.Percent = *wire.Progress * 100
+	case wire.ItemsTotal > 0:
+		status.Progress.Percent = float64(wire.ItemsDone) / float64(wire.ItemsTotal) * 100
+	}
+	if status.State == JobStateSucceeded {
+		status.Progress.Percent = 100
+	}
+	return status, nil
+}
@@ -0,0 +1,108 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobLifecycle(t *testing.T) {
+	polls := 0
+	cancelled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /jobs":
+			var spec JobSpec
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&spec))
+			assert.Equal(t, JobImport, spec.Kind)
+			assert.Equal(t, "s3://bucket/people.csv", spec.Params["source"])
+			w.Write([]byte(`{"id":"j1","state":"pending"}`))
+		case "GET /jobs/j1":
+			polls++
+			switch polls {
+			case 1:
+				w.Write([]byte(`{"id":"j1","kind":"import","state":"running","items_done":100,"items_total":400}`))
+			case 2:
+				time.Sleep(5 * time.Millisecond)
+				w.Write([]byte(`{"id":"j1","kind":"import","state":"running","items_done":300,"items_total":400}`))
+			default:
+				w.Write([]byte(`{"id":"j1","kind":"import","state":"succeeded","items_done":400,"items_total":400,"result":{"nodes":400}}`))
+			}
+		case "GET /jobs":
+			assert.Equal(t, "backup", r.URL.Query().Get("kind"))
+			w.Write([]byte(`{"jobs":[{"id":"b1","kind":"backup","state":"failed","error":"disk full","progress":0.25}]}`))
+		case "POST /jobs/b1/cancel":
+			cancelled = true
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	job, err := client.StartJob(ctx, JobSpec{Kind: JobImport, Params: map[string]interface{}{"source": "s3://bucket/people.csv"}})
+	require.NoError(t, err)
+	assert.Equal(t, JobImport, job.Kind)
+	assert.Equal(t, JobStatePending, job.LastStatus().State)
+
+	status, err := job.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, status.Progress.Percent)
+	status, err = job.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 75.0, status.Progress.Percent)
+	assert.Greater(t, status.Progress.ItemsPerSecond, 0.0)
+
+	status, err = job.Wait(ctx, time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, status.Done())
+	assert.Equal(t, 100.0, status.Progress.Percent)
+	assert.JSONEq(t, `{"nodes":400}`, string(status.Result))
+
+	jobs, err := client.ListJobs(ctx, JobBackup)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, 25.0, jobs[0].Progress.Percent)
+	assert.Equal(t, "disk full", jobs[0].Error)
+	require.NoError(t, client.jobHandle("b1", JobBackup, "/jobs/b1").Cancel(ctx))
+	assert.True(t, cancelled)
+}
+
+func TestJobWaitReportsFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/jobs/j2":
+			w.Write([]byte(`{"id":"j2","kind":"export","state":"failed","error":"out of space"}`))
+		case "/algorithms/embeddings/e1":
+			w.Write([]byte(`{"id":"e1","state":"cancelled","progress":0.4}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	job, err := client.GetJob(ctx, "j2")
+	require.NoError(t, err)
+	assert.Equal(t, JobExport, job.Kind)
+	_, err = job.Wait(ctx, time.Millisecond)
+	var jobErr *JobError
+	require.True(t, errors.As(err, &jobErr))
+	assert.Equal(t, "out of space", jobErr.Message)
+	assert.EqualError(t, err, "nexus: export job j2 failed: out of space")
+
+	embedding := &EmbeddingJob{ID: "e1", client: client}
+	err = embedding.Wait(ctx, time.Millisecond)
+	assert.EqualError(t, err, "nexus: embedding job e1 was cancelled")
+	assert.Equal(t, 0.4, embedding.Progress)
+	assert.Equal(t, 40.0, embedding.Job().LastStatus().Progress.Percent)
+}
@@ -0,0 +1,469 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// jsonSchema is the compiled subset of JSON Schema (draft 2020-12
+// keywords) supported for node payloads: type, enum, const, required,
+// properties, additionalProperties, items, minItems, maxItems,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength,
+// maxLength, pattern, allOf, anyOf, oneOf and not. Unknown keywords
+// are ignored, as the specification requires.
+type jsonSchema struct {
+	types            []string
+	enum             []interface{}
+	constVal         interface{}
+	hasConst         bool
+	required         []string
+	properties       map[string]*jsonSchema
+	additional       *jsonSchema
+	noAdditional     bool
+	items            *jsonSchema
+	minItems         *int
+	maxItems         *int
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	minLength        *int
+	maxLength        *int
+	pattern          *regexp.Regexp
+	allOf            []*jsonSchema
+	anyOf            []*jsonSchema
+	oneOf            []*jsonSchema
+	not              *jsonSchema
+}
+
+// DefineJSONSchema registers a JSON Schema document for label. Node
+// properties are validated against it (in addition to any LabelSchema
+// rules) on every write, and by Client.ValidateGraph.
+func (s *Schema) DefineJSONSchema(label string, document []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(document, &raw); err != nil {
+		return fmt.Errorf("nexus: JSON schema for %s: %w", label, err)
+	}
+	compiled, err := compileJSONSchema(raw, "#")
+	if err != nil {
+		return fmt.Errorf("nexus: JSON schema for %s: %w", label, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jsonSchemas == nil {
+		s.jsonSchemas = make(map[string]*jsonSchema)
+	}
+	s.jsonSchemas[label] = compiled
+	return nil
+}
+
+// RegisterJSONSchema registers a JSON Schema for label on the client's
+// schema, creating one if Config.Schema was nil. It is safe to call
+// while the client is serving requests, and clients derived with
+// WithRetry share the schema.
+func (c *Client) RegisterJSONSchema(label string, document []byte) error {
+	schema := c.schema.Load()
+	if schema == nil {
+		c.schema.CompareAndSwap(nil, NewSchema())
+		schema = c.schema.Load()
+	}
+	return schema.DefineJSONSchema(label, document)
+}
+
+func compileJSONSchema(raw interface{}, path string) (*jsonSchema, error) {
+	switch v := raw.(type) {
+	case bool:
+		// `true` accepts everything, `false` nothing.
+		if v {
+			return &jsonSchema{}, nil
+		}
+		return &jsonSchema{not: &jsonSchema{}}, nil
+	case map[string]interface{}:
+		return compileJSONSchemaObject(v, path)
+	}
+	return nil, fmt.Errorf("%s: schema must be an object or boolean", path)
+}
+
+func compileJSONSchemaObject(m map[string]interface{}, path string) (*jsonSchema, error) {
+	s := &jsonSchema{}
+	var err error
+
+	switch t := m["type"].(type) {
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, e := range t {
+			s.types = append(s.types, fmt.Sprint(e))
+		}
+	}
+	if enum, ok := m["enum"].([]interface{}); ok {
+		s.enum = enum
+	}
+	if c, ok := m["const"]; ok {
+		s.constVal, s.hasConst = c, true
+	}
+	if req, ok := m["required"].([]interface{}); ok {
+		for _, r := range req {
+			s.required = append(s.required, fmt.Sprint(r))
+		}
+	}
+	if props, ok := m["properties"].(map[string]interface{}); ok {
+		s.properties = make(map[string]*jsonSchema, len(props))
+		for name, sub := range props {
+			if s.properties[name], err = compileJSONSchema(sub, path+"/properties/"+name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	switch ap := m["additionalProperties"].(type) {
+	case bool:
+		s.noAdditional = !ap
+	case map[string]interface{}:
+		if s.additional, err = compileJSONSchema(ap, path+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+	if items, ok := m["items"]; ok {
+		if s.items, err = compileJSONSchema(items, path+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	s.minItems = intKeyword(m, "minItems")
+	s.maxItems = intKeyword(m, "maxItems")
+	s.minLength = intKeyword(m, "minLength")
+	s.maxLength = intKeyword(m, "maxLength")
+	s.minimum = floatKeyword(m, "minimum")
+	s.maximum = floatKeyword(m, "maximum")
+	s.exclusiveMinimum = floatKeyword(m, "exclusiveMinimum")
+	s.exclusiveMaximum = floatKeyword(m, "exclusiveMaximum")
+	if p, ok := m["pattern"].(string); ok {
+		if s.pattern, err = regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("%s/pattern: %w", path, err)
+		}
+	}
+	for _, kw := range []struct {
+		name string
+		dst  *[]*jsonSchema
+	}{{"allOf", &s.allOf}, {"anyOf", &s.anyOf}, {"oneOf", &s.oneOf}} {
+		list, ok := m[kw.name].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, sub := range list {
+			compiled, err := compileJSONSchema(sub, fmt.Sprintf("%s/%s/%d", path, kw.name, i))
+			if err != nil {
+				return nil, err
+			}
+			*kw.dst = append(*kw.dst, compiled)
+		}
+	}
+	if not, ok := m["not"]; ok {
+		if s.not, err = compileJSONSchema(not, path+"/not"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func intKeyword(m map[string]interface{}, key string) *int {
+	if f, ok := m[key].(float64); ok {
+		n := int(f)
+		return &n
+	}
+	return nil
+}
+
+func floatKeyword(m map[string]interface{}, key string) *float64 {
+	if f, ok := m[key].(float64); ok {
+		return &f
+	}
+	return nil
+}
+
+// validate checks a JSON-normalised value, appending one message
+// per failure keyed by its property path ("" for the root).
+func (s *jsonSchema) validate(v interface{}, path string, report func(path, msg string)) {
+	if len(s.types) > 0 && !jsonTypeMatches(s.types, v) {
+		report(path, fmt.Sprintf("expected type %s, got %s", strings.Join(s.types, " or "), jsonTypeOf(v)))
+		return
+	}
+	if len(s.enum) > 0 {
+		found := false
+		for _, e := range s.enum {
+			if jsonEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report(path, fmt.Sprintf("value %v is not one of %v", v, s.enum))
+		}
+	}
+	if s.hasConst && !jsonEqual(s.constVal, v) {
+		report(path, fmt.Sprintf("value must be %v", s.constVal))
+	}
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := x[name]; !ok {
+				report(joinJSONPath(path, name), "required property is missing")
+			}
+		}
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := s.properties[k]; ok {
+				sub.validate(x[k], joinJSONPath(path, k), report)
+			} else if s.noAdditional {
+				report(joinJSONPath(path, k), "additional property is not allowed")
+			} else if s.additional != nil {
+				s.additional.validate(x[k], joinJSONPath(path, k), report)
+			}
+		}
+	case []interface{}:
+		if s.minItems != nil && len(x) < *s.minItems {
+			report(path, fmt.Sprintf("has %d items, minimum is %d", len(x), *s.minItems))
+		}
+		if s.maxItems != nil && len(x) > *s.maxItems {
+			report(path, fmt.Sprintf("has %d items, maximum is %d", len(x), *s.maxItems))
+		}
+		if s.items != nil {
+			for i, e := range x {
+				s.items.validate(e, fmt.Sprintf("%s[%d]", path, i), report)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(x)
+		if s.minLength != nil && n < *s.minLength {
+			report(path, fmt.Sprintf("length %d is below minimum %d", n, *s.minLength))
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			report(path, fmt.Sprintf("length %d is above maximum %d", n, *s.maxLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(x) {
+			report(path, fmt.Sprintf("value %q does not match pattern %s", x, s.pattern))
+		}
+	case float64:
+		if s.minimum != nil && x < *s.minimum {
+			report(path, fmt.Sprintf("value %v is below minimum %v", x, *s.minimum))
+		}
+		if s.maximum != nil && x > *s.maximum {
+			report(path, fmt.Sprintf("value %v is above maximum %v", x, *s.maximum))
+		}
+		if s.exclusiveMinimum != nil && x <= *s.exclusiveMinimum {
+			report(path, fmt.Sprintf("value %v must be greater than %v", x, *s.exclusiveMinimum))
+		}
+		if s.exclusiveMaximum != nil && x >= *s.exclusiveMaximum {
+			report(path, fmt.Sprintf("value %v must be less than %v", x, *s.exclusiveMaximum))
+		}
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(v, path, report)
+	}
+	if len(s.anyOf) > 0 && countValid(s.anyOf, v) == 0 {
+		report(path, "value does not match any schema in anyOf")
+	}
+	if len(s.oneOf) > 0 {
+		if n := countValid(s.oneOf, v); n != 1 {
+			report(path, fmt.Sprintf("value matches %d schemas in oneOf, expected exactly 1", n))
+		}
+	}
+	if s.not != nil && s.not.valid(v) {
+		report(path, "value must not match the schema in not")
+	}
+}
+
+func (s *jsonSchema) valid(v interface{}) bool {
+	ok := true
+	s.validate(v, "", func(string, string) { ok = false })
+	return ok
+}
+
+func countValid(schemas []*jsonSchema, v interface{}) int {
+	n := 0
+	for _, s := range schemas {
+		if s.valid(v) {
+			n++
+		}
+	}
+	return n
+}
+
+func jsonTypeOf(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func jsonTypeMatches(types []string, v interface{}) bool {
+	actual := jsonTypeOf(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+func joinJSONPath(base, key string) string {
+	if base == "" {
+		return key
+	}
+	return base + "." + key
+}
+
+// normalizeJSON converts arbitrary Go values (ints, typed slices,
+// structs) to the encoding/json generic shape the validator expects.
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ValidateGraphOptions controls a ValidateGraph scan.
+type ValidateGraphOptions struct {
+	// Labels limits the scan; empty scans every label with a JSON schema.
+	Labels []string
+	// BatchSize is the number of nodes fetched per query (default 500).
+	BatchSize int
+	// MaxViolations stops the scan early once reached (0 = unlimited).
+	MaxViolations int
+}
+
+// GraphValidationReport summarises a ValidateGraph scan. Violations
+// carry the offending node ID in NodeID.
+type GraphValidationReport struct {
+	NodesScanned int
+	Violations   []NodeViolation
+	// Truncated is set when MaxViolations stopped the scan early.
+	Truncated bool
+}
+
+// NodeViolation is a schema violation found on stored data.
+type NodeViolation struct {
+	NodeID string
+	Violation
+}
+
+// ValidateGraph audits existing nodes against the registered JSON
+// schemas, paging through each label by node id in batches. LabelSchema
+// rules are not applied, and labels without a JSON schema are skipped.
+func (c *Client) ValidateGraph(ctx context.Context, opts ValidateGraphOptions) (*GraphValidationReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	report := &GraphValidationReport{}
+	schema := c.schema.Load()
+	if schema == nil {
+		return report, nil
+	}
+
+	schema.mu.RLock()
+	jsonSchemas := make(map[string]*jsonSchema, len(schema.jsonSchemas))
+	for label, js := range schema.jsonSchemas {
+		jsonSchemas[label] = js
+	}
+	schema.mu.RUnlock()
+	labels := opts.Labels
+	if len(labels) == 0 {
+		for label := range jsonSchemas {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+	}
+
+	for _, label := range labels {
+		js, ok := jsonSchemas[label]
+		if !ok {
+			continue
+		}
+		query := fmt.Sprintf(
+			"MATCH (n:%s) WHERE id(n) > $after RETURN id(n) AS id, properties(n) AS props "+
+				"ORDER BY id(n) LIMIT $limit", cypherlex.QuoteName(label))
+		after := int64(-1)
+		for {
+			result, err := c.ExecuteCypher(ctx, query, map[string]interface{}{
+				"after": after,
+				"limit": opts.BatchSize,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, row := range result.Rows {
+				if len(row) < 2 {
+					continue
+				}
+				// Node IDs page the scan, so one that is not an integer
+				// would restart it forever.
+				id, err := parseID(idString(row[0]))
+				if err != nil {
+					return nil, err
+				}
+				report.NodesScanned++
+				after = id
+				props, _ := row[1].(map[string]interface{})
+				for _, v := range validateJSONSchema(label, js, props) {
+					report.Violations = append(report.Violations, NodeViolation{NodeID: idString(row[0]), Violation: v})
+					if opts.MaxViolations > 0 && len(report.Violations) >= opts.MaxViolations {
+						report.Truncated = true
+						return report, nil
+					}
+				}
+			}
+			if len(result.Rows) < opts.BatchSize {
+				break
+			}
+		}
+	}
+	return report, nil
+}
+
+// validLabelIdentifier guards labels and types interpolated into
+// Cypher text, which cannot be parameterised.
+func validLabelIdentifier(name string) error {
+	if !cypherlex.IsPlainName(name) {
+		return fmt.Errorf("nexus: %q is not a valid label or relationship type", name)
+	}
+	return nil
+}
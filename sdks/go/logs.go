@@ -0,0 +1,249 @@
+package nexus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// LogSource names a server log.
+type LogSource string
+
+const (
+	LogServer   LogSource = "server"
+	LogQuery    LogSource = "query"
+	LogSecurity LogSource = "security"
+)
+
+// LogFilter selects the entries TailLogs streams.
+type LogFilter struct {
+	// Sources limits the stream to these logs; empty means all.
+	Sources []LogSource
+	// MinLevel drops entries below this level ("debug", "info", "warn",
+	// "error").
+	MinLevel string
+	// Since replays entries logged at or after this time before
+	// following new ones. Zero starts at the tail.
+	Since time.Time
+	// Contains keeps only entries whose message contains the substring.
+	Contains string
+	// LastEventID resumes a dropped stream after the entry with this ID
+	// (see LogStream.LastEventID).
+	LastEventID string
+}
+
+// LogEntry is one structured server log line.
+type LogEntry struct {
+	ID        string
+	Timestamp time.Time
+	Level     string
+	Source    LogSource
+	Message   string
+	// Fields carries the structured attributes of the entry, such as
+	// the query text and duration of query log entries or the user and
+	// action of security log entries.
+	Fields map[string]interface{}
+}
+
+// UnmarshalJSON decodes an entry; unparseable timestamps are left zero
+// and any unknown keys land in Fields.
+func (e *LogEntry) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = LogEntry{
+		ID:      fieldString(raw, "id"),
+		Level:   strings.ToLower(fieldString(raw, "level")),
+		Source:  LogSource(fieldString(raw, "source")),
+		Message: fieldString(raw, "message"),
+	}
+	if ts := fieldString(raw, "timestamp"); ts != "" {
+		e.Timestamp, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	if fields, ok := raw["fields"].(map[string]interface{}); ok {
+		e.Fields = fields
+	}
+	for k, v := range raw {
+		switch k {
+		case "id", "timestamp", "level", "source", "message", "fields":
+			continue
+		}
+		if e.Fields == nil {
+			e.Fields = map[string]interface{}{}
+		}
+		e.Fields[k] = v
+	}
+	return nil
+}
+
+// LogStream is a live stream of log entries:
+//
+//	stream, err := client.TailLogs(ctx, nexus.LogFilter{Sources: []nexus.LogSource{nexus.LogQuery}})
+//	if err != nil { … }
+//	defer stream.Close()
+//	for stream.Next() {
+//	    e := stream.Entry()
+//	    …
+//	}
+//	if err := stream.Err(); err != nil { … }
+//
+// The stream ends when ctx is cancelled or the server closes it; pass
+// LastEventID to a new TailLogs call to continue without gaps.
+type LogStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	// snapshot holds the entries of a plain JSON response, from servers
+	// that cannot stream.
+	snapshot []LogEntry
+	sse      bool
+	entry    LogEntry
+	lastID   string
+	err      error
+}
+
+// TailLogs streams server log entries as server-sent events (GET /logs
+// with `follow=true`). Servers without streaming support answer with a
+// JSON snapshot of recent entries, which the stream replays before
+// ending.
+//
+// Config.Timeout does not apply to the stream; cancel ctx to stop it.
+func (c *Client) TailLogs(ctx context.Context, filter LogFilter) (*LogStream, error) {
+	q := url.Values{"follow": {"true"}}
+	for _, s := range filter.Sources {
+		q.Add("source", string(s))
+	}
+	if filter.MinLevel != "" {
+		q.Set("level", strings.ToLower(filter.MinLevel))
+	}
+	if !filter.Since.IsZero() {
+		q.Set("since", filter.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if filter.Contains != "" {
+		q.Set("contains", filter.Contains)
+	}
+	ctx = transport.WithHeader(ctx, "Accept", "text/event-stream")
+	if filter.LastEventID != "" {
+		ctx = transport.WithHeader(ctx, "Last-Event-ID", filter.LastEventID)
+	}
+
+	resp, err := c.doStream(ctx, http.MethodGet, "/logs?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	stream := &LogStream{body: resp.Body, lastID: filter.LastEventID}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/event-stream" {
+		stream.sse = true
+		stream.scanner = newJSONLineScanner(resp.Body)
+		return stream, nil
+	}
+
+	defer resp.Body.Close()
+	var snapshot struct {
+		Logs []LogEntry `json:"logs"`
+	}
+	if err := decodeResponse(resp, &snapshot); err != nil {
+		return nil, err
+	}
+	// The snapshot endpoint lists the newest entry first.
+	for i := len(snapshot.Logs) - 1; i >= 0; i-- {
+		stream.snapshot = append(stream.snapshot, snapshot.Logs[i])
+	}
+	return stream, nil
+}
+
+// Next blocks until the next entry arrives, returning false when the
+// stream ends or fails.
+func (s *LogStream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	if !s.sse {
+		if len(s.snapshot) == 0 {
+			return false
+		}
+		s.entry, s.snapshot = s.snapshot[0], s.snapshot[1:]
+		return true
+	}
+
+	var event, id string
+	var data bytes.Buffer
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			// A blank line dispatches the event accumulated so far.
+			if data.Len() == 0 {
+				event, id = "", ""
+				continue
+			}
+			if id != "" {
+				s.lastID = id
+			}
+			if event != "" && event != "log" && event != "message" {
+				event, id = "", ""
+				data.Reset()
+				continue
+			}
+			s.entry = LogEntry{}
+			if err := json.Unmarshal(data.Bytes(), &s.entry); err != nil {
+				s.err = fmt.Errorf("failed to decode log entry: %w", err)
+				return false
+			}
+			if s.entry.ID == "" {
+				s.entry.ID = id
+			}
+			return true
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment / keep-alive
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "id":
+			id = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	if err := s.scanner.Err(); err != nil && !isStreamClosed(err) {
+		s.err = fmt.Errorf("failed to read stream: %w", err)
+	}
+	return false
+}
+
+// isStreamClosed reports whether err just means the request context
+// was cancelled or the stream closed, which ends a tail normally.
+func isStreamClosed(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, net.ErrClosed)
+}
+
+// Entry returns the current entry.
+func (s *LogStream) Entry() LogEntry { return s.entry }
+
+// LastEventID returns the ID of the last event received, to resume
+// with LogFilter.LastEventID.
+func (s *LogStream) LastEventID() string { return s.lastID }
+
+// Err returns the first error encountered while streaming.
+func (s *LogStream) Err() error { return s.err }
+
+// Close stops the stream and releases the connection.
+func (s *LogStream) Close() error { return s.body.Close() }
@@ -0,0 +1,77 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailLogsSSE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/logs", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("follow"))
+		assert.Equal(t, []string{"query", "security"}, r.URL.Query()["source"])
+		assert.Equal(t, "warn", r.URL.Query().Get("level"))
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		assert.Equal(t, "41", r.Header.Get("Last-Event-ID"))
+
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		flusher := w.(http.Flusher)
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "id: 42\nevent: log\ndata: {\"timestamp\":\"2026-01-02T03:04:05Z\",\"level\":\"WARN\",\"source\":\"query\",\n")
+		fmt.Fprint(w, "data: \"message\":\"slow query\",\"duration_ms\":1200}\n\n")
+		fmt.Fprint(w, "event: heartbeat\ndata: {}\n\n")
+		flusher.Flush()
+		// Outlives Config.Timeout: streams must not be cut by it.
+		time.Sleep(150 * time.Millisecond)
+		fmt.Fprint(w, "id: 43\ndata: {\"level\":\"error\",\"source\":\"security\",\"message\":\"denied\",\"fields\":{\"user\":\"bob\"}}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+	stream, err := client.TailLogs(context.Background(), LogFilter{
+		Sources: []LogSource{LogQuery, LogSecurity}, MinLevel: "WARN", LastEventID: "41",
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	require.True(t, stream.Next())
+	e := stream.Entry()
+	assert.Equal(t, "42", e.ID)
+	assert.Equal(t, "warn", e.Level)
+	assert.Equal(t, LogQuery, e.Source)
+	assert.Equal(t, "slow query", e.Message)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), e.Timestamp)
+	assert.Equal(t, float64(1200), e.Fields["duration_ms"])
+
+	require.True(t, stream.Next())
+	assert.Equal(t, "denied", stream.Entry().Message)
+	assert.Equal(t, "bob", stream.Entry().Fields["user"])
+	assert.Equal(t, "43", stream.LastEventID())
+
+	assert.False(t, stream.Next())
+	assert.NoError(t, stream.Err())
+}
+
+func TestTailLogsSnapshotFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"logs":[{"timestamp":"","level":"INFO","message":"second"},{"timestamp":"","level":"INFO","message":"first"}],"total":2}`))
+	}))
+	defer server.Close()
+
+	stream, err := NewClient(Config{BaseURL: server.URL}).TailLogs(context.Background(), LogFilter{})
+	require.NoError(t, err)
+	var messages []string
+	for stream.Next() {
+		messages = append(messages, stream.Entry().Message)
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, []string{"first", "second"}, messages)
+}
@@ -0,0 +1,330 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// PropertyConflict decides which value wins when the survivor and a
+// duplicate both carry a property.
+type PropertyConflict int
+
+const (
+	// KeepSurvivor keeps the survivor's value; duplicates only fill in
+	// properties the survivor lacks.
+	KeepSurvivor PropertyConflict = iota
+	// PreferDuplicate overwrites with the duplicates' values, later
+	// duplicates winning over earlier ones.
+	PreferDuplicate
+	// CollectValues stores the distinct values of every node as a list.
+	CollectValues
+)
+
+// MergePolicy configures MergeNodes.
+type MergePolicy struct {
+	OnConflict PropertyConflict
+	// Resolve, when set, overrides OnConflict for every property present
+	// on more than one node. values holds the survivor's value first
+	// (nil if absent) followed by each duplicate's value in order.
+	Resolve func(key string, values []interface{}) interface{}
+	// SkipLabels leaves the survivor's labels untouched instead of adding
+	// the duplicates' labels.
+	SkipLabels bool
+	// KeepSelfLoops keeps relationships that become survivor→survivor
+	// loops after rewiring (for example a SAME_AS edge between the
+	// duplicates). They are dropped by default.
+	KeepSelfLoops bool
+}
+
+// MergeResult summarizes a MergeNodes call.
+type MergeResult struct {
+	Survivor             *Node
+	RelationshipsMoved   int
+	RelationshipsDropped int
+	NodesDeleted         int
+}
+
+// MergeNodes resolves duplicate entities: every relationship of
+// duplicateIDs is recreated on keepID (type, direction and properties
+// preserved), properties and labels are merged into the survivor
+// according to policy, and the duplicates are deleted.
+//
+// The whole operation runs in a single transaction and is rolled back
+// on any error.
+func (c *Client) MergeNodes(ctx context.Context, keepID string, duplicateIDs []string, policy MergePolicy) (*MergeResult, error) {
+	if len(duplicateIDs) == 0 {
+		return nil, errors.New("nexus: MergeNodes requires at least one duplicate")
+	}
+	keep, err := parseID(keepID)
+	if err != nil {
+		return nil, err
+	}
+	dups := make([]int64, 0, len(duplicateIDs))
+	seen := map[int64]bool{keep: true}
+	for _, id := range duplicateIDs {
+		n, err := parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		if seen[n] {
+			return nil, fmt.Errorf("nexus: MergeNodes: node %s listed twice or as both survivor and duplicate", id)
+		}
+		seen[n] = true
+		dups = append(dups, n)
+	}
+
+	tx, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := mergeNodesTx(ctx, tx, keep, dups, policy)
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return nil, fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func mergeNodesTx(ctx context.Context, tx *Transaction, keep int64, dups []int64, policy MergePolicy) (*MergeResult, error) {
+	all := append([]int64{keep}, dups...)
+	nodes, err := tx.ExecuteCypher(ctx,
+		"MATCH (n) WHERE id(n) IN $ids RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props",
+		map[string]interface{}{"ids": all})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]struct {
+		labels []string
+		props  map[string]interface{}
+	}, len(nodes.Rows))
+	for _, row := range nodes.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		entry := byID[int64(asInt(row[0]))]
+		for _, l := range asSlice(row[1]) {
+			entry.labels = append(entry.labels, fmt.Sprint(l))
+		}
+		entry.props, _ = row[2].(map[string]interface{})
+		byID[int64(asInt(row[0]))] = entry
+	}
+	for _, id := range all {
+		if _, ok := byID[id]; !ok {
+			return nil, fmt.Errorf("nexus: MergeNodes: node %d not found", id)
+		}
+	}
+
+	// Merge properties and labels in memory.
+	propSets := make([]map[string]interface{}, len(all))
+	for i, id := range all {
+		propSets[i] = byID[id].props
+	}
+	merged := mergeProperties(propSets, policy)
+	var addLabels []string
+	if !policy.SkipLabels {
+		have := map[string]bool{}
+		for _, l := range byID[keep].labels {
+			have[l] = true
+		}
+		for _, id := range dups {
+			for _, l := range byID[id].labels {
+				if !have[l] {
+					have[l] = true
+					addLabels = append(addLabels, l)
+				}
+			}
+		}
+	}
+
+	// Collect every relationship touching a duplicate.
+	rels, err := tx.ExecuteCypher(ctx,
+		"MATCH (a)-[r]->(b) WHERE id(a) IN $dups OR id(b) IN $dups "+
+			"RETURN DISTINCT id(r) AS id, type(r) AS type, id(a) AS start, id(b) AS end, properties(r) AS props",
+		map[string]interface{}{"dups": dups})
+	if err != nil {
+		return nil, err
+	}
+	isDup := make(map[int64]bool, len(dups))
+	for _, id := range dups {
+		isDup[id] = true
+	}
+	remap := func(id int64) int64 {
+		if isDup[id] {
+			return keep
+		}
+		return id
+	}
+
+	result := &MergeResult{}
+	byType := map[string][]interface{}{}
+	for _, row := range rels.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		relType := fmt.Sprint(row[1])
+		start, end := remap(int64(asInt(row[2]))), remap(int64(asInt(row[3])))
+		if start == keep && end == keep && !policy.KeepSelfLoops {
+			result.RelationshipsDropped++
+			continue
+		}
+		props, _ := row[4].(map[string]interface{})
+		if props == nil {
+			props = map[string]interface{}{}
+		}
+		byType[relType] = append(byType[relType], map[string]interface{}{
+			"start": start, "end": end, "props": props,
+		})
+		result.RelationshipsMoved++
+	}
+
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if err := validLabelIdentifier(t); err != nil {
+			return nil, err
+		}
+		query := fmt.Sprintf(
+			"UNWIND $rels AS rel MATCH (a), (b) WHERE id(a) = rel.start AND id(b) = rel.end "+
+				"CREATE (a)-[r:%s]->(b) SET r = rel.props", t)
+		if _, err := tx.ExecuteCypher(ctx, query, map[string]interface{}{"rels": byType[t]}); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.ExecuteCypher(ctx,
+		"MATCH (n) WHERE id(n) IN $dups DETACH DELETE n",
+		map[string]interface{}{"dups": dups}); err != nil {
+		return nil, err
+	}
+	result.NodesDeleted = len(dups)
+
+	setLabels := ""
+	for _, l := range addLabels {
+		if err := validLabelIdentifier(l); err != nil {
+			return nil, err
+		}
+		setLabels += ":" + l
+	}
+	query := "MATCH (n) WHERE id(n) = $id SET n = $props"
+	if setLabels != "" {
+		query += ", n" + setLabels
+	}
+	query += " RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props"
+	updated, err := tx.ExecuteCypher(ctx, query, map[string]interface{}{"id": keep, "props": merged})
+	if err != nil {
+		return nil, err
+	}
+
+	survivor := &Node{ID: strconv.FormatInt(keep, 10), Labels: append(byID[keep].labels, addLabels...), Properties: merged}
+	if len(updated.Rows) > 0 && len(updated.Rows[0]) >= 3 {
+		row := updated.Rows[0]
+		survivor.Labels = survivor.Labels[:0]
+		for _, l := range asSlice(row[1]) {
+			survivor.Labels = append(survivor.Labels, fmt.Sprint(l))
+		}
+		if props, ok := row[2].(map[string]interface{}); ok {
+			survivor.Properties = props
+		}
+	}
+	result.Survivor = survivor
+	return result, nil
+}
+
+// mergeProperties folds sets[1:] into sets[0] according to policy.
+func mergeProperties(sets []map[string]interface{}, policy MergePolicy) map[string]interface{} {
+	keys := map[string]bool{}
+	for _, s := range sets {
+		for k := range s {
+			keys[k] = true
+		}
+	}
+
+	merged := make(map[string]interface{}, len(keys))
+	for k := range keys {
+		values := make([]interface{}, len(sets))
+		present := 0
+		for i, s := range sets {
+			if v, ok := s[k]; ok && v != nil {
+				values[i] = v
+				present++
+			}
+		}
+		if present > 1 && policy.Resolve != nil {
+			merged[k] = policy.Resolve(k, values)
+			continue
+		}
+
+		switch policy.OnConflict {
+		case PreferDuplicate:
+			for _, v := range values {
+				if v != nil {
+					merged[k] = v
+				}
+			}
+		case CollectValues:
+			if present == 1 {
+				merged[k] = firstNonNil(values)
+				continue
+			}
+			var distinct []interface{}
+			for _, v := range values {
+				if v != nil && !containsValue(distinct, v) {
+					distinct = append(distinct, v)
+				}
+			}
+			if len(distinct) == 1 {
+				merged[k] = distinct[0]
+			} else {
+				merged[k] = distinct
+			}
+		default:
+			merged[k] = firstNonNil(values)
+		}
+	}
+	return merged
+}
+
+func firstNonNil(values []interface{}) interface{} {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, e := range list {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+// parseID converts a string node or relationship ID to the integer
+// form Cypher's id() function compares against.
+func parseID(id string) (int64, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("nexus: invalid ID %q", id)
+	}
+	return n, nil
+}
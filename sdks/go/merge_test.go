@@ -0,0 +1,94 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeNodes(t *testing.T) {
+	var queries []string
+	var created map[string]interface{}
+	committed := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/transaction/begin":
+			json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx1"})
+			return
+		case "/transaction/commit":
+			committed = true
+			json.NewEncoder(w).Encode(map[string]bool{"success": true})
+			return
+		}
+
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		query := req["query"].(string)
+		queries = append(queries, query)
+
+		var rows [][]interface{}
+		switch {
+		case strings.HasPrefix(query, "MATCH (n) WHERE id(n) IN $ids"):
+			rows = [][]interface{}{
+				{1, []string{"Person"}, map[string]interface{}{"name": "Ann", "email": "a@x"}},
+				{2, []string{"Person", "Customer"}, map[string]interface{}{"name": "Anne", "phone": "123"}},
+			}
+		case strings.HasPrefix(query, "MATCH (a)-[r]->(b)"):
+			rows = [][]interface{}{
+				{10, "KNOWS", 2, 3, map[string]interface{}{"since": 2020}},
+				{11, "SAME_AS", 1, 2, map[string]interface{}{}},
+			}
+		case strings.HasPrefix(query, "UNWIND $rels"):
+			assert.Contains(t, query, "CREATE (a)-[r:KNOWS]->(b)")
+			created = req["parameters"].(map[string]interface{})["rels"].([]interface{})[0].(map[string]interface{})
+		case strings.Contains(query, "SET n = $props"):
+			assert.Contains(t, query, "n:Customer")
+			props := req["parameters"].(map[string]interface{})["props"]
+			rows = [][]interface{}{{1, []string{"Person", "Customer"}, props}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{}, "rows": rows})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	result, err := client.MergeNodes(context.Background(), "1", []string{"2"}, MergePolicy{})
+
+	require.NoError(t, err)
+	assert.True(t, committed)
+	assert.Equal(t, 1, result.RelationshipsMoved)
+	assert.Equal(t, 1, result.RelationshipsDropped)
+	assert.Equal(t, 1, result.NodesDeleted)
+	assert.Equal(t, float64(1), created["start"])
+	assert.Equal(t, float64(3), created["end"])
+	assert.Equal(t, []string{"Person", "Customer"}, result.Survivor.Labels)
+	assert.Equal(t, "Ann", result.Survivor.Properties["name"])
+	assert.Equal(t, "123", result.Survivor.Properties["phone"])
+	assert.Contains(t, queries, "MATCH (n) WHERE id(n) IN $dups DETACH DELETE n")
+}
+
+func TestMergeProperties(t *testing.T) {
+	sets := []map[string]interface{}{
+		{"name": "Ann", "tags": "a"},
+		{"name": "Anne", "age": 30},
+		{"email": "a@x"},
+	}
+
+	assert.Equal(t, "Anne", mergeProperties(sets, MergePolicy{OnConflict: PreferDuplicate})["name"])
+	collected := mergeProperties(sets, MergePolicy{OnConflict: CollectValues})
+	assert.Equal(t, []interface{}{"Ann", "Anne"}, collected["name"])
+	assert.Equal(t, 30, collected["age"])
+
+	resolved := mergeProperties(sets, MergePolicy{Resolve: func(key string, values []interface{}) interface{} {
+		return len(values)
+	}})
+	assert.Equal(t, 3, resolved["name"])
+	assert.Equal(t, "a", resolved["tags"])
+}
@@ -0,0 +1,262 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueryEvent describes one Cypher statement the client ran.
+type QueryEvent struct {
+	// Query is the statement as sent, after QueryPolicy rewrites, and
+	// Params its parameters. Hooks must not modify Params.
+	Query  string
+	Params map[string]interface{}
+	// Normalized is NormalizeQuery(Query); Fingerprint identifies it.
+	Normalized  string
+	Fingerprint string
+	Start       time.Time
+	Duration    time.Duration
+	// Rows is the number of rows returned; zero when Err is set.
+	Rows int
+	// Stats are the statement's counters and server timings, when the
+	// server reported them.
+	Stats *QueryStats
+	Err   error
+	// InTransaction is set for statements run through a Transaction.
+	InTransaction bool
+}
+
+// MetricsHook observes every Cypher statement the client runs, through
+// ExecuteCypher, ExecuteCypherHTTP and Transaction.ExecuteCypher.
+// Queries rejected by QueryPolicy, QueryList or the Authorizer are not
+// reported. ObserveQuery runs on the calling goroutine, so it should
+// be quick; QueryAggregator is the built-in hook.
+type MetricsHook interface {
+	ObserveQuery(ctx context.Context, event QueryEvent)
+}
+
+// MetricsHookFunc adapts a function to MetricsHook.
+type MetricsHookFunc func(ctx context.Context, event QueryEvent)
+
+// ObserveQuery calls f(ctx, event).
+func (f MetricsHookFunc) ObserveQuery(ctx context.Context, event QueryEvent) { f(ctx, event) }
+
+// startQuery prepares to run a vetted query: it applies the adaptive
+// timeout, if any, and returns the context to run under and a function
+// reporting the outcome to the metrics hooks.
+func (c *Client) startQuery(ctx context.Context, query string, params map[string]interface{}, inTx bool) (context.Context, func(*QueryResult, error) error) {
+	if c.metrics == nil && c.adaptive == nil {
+		return ctx, func(result *QueryResult, err error) error {
+			c.chargeRows(result)
+			return err
+		}
+	}
+	normalized := NormalizeQuery(query)
+	fingerprint := fingerprintOf(normalized)
+	cancel := context.CancelFunc(func() {})
+	var timeout time.Duration
+	if c.adaptive != nil {
+		ctx, cancel, timeout = c.adaptive.apply(ctx, fingerprint)
+	}
+	start := time.Now()
+	return ctx, func(result *QueryResult, err error) error {
+		event := QueryEvent{
+			Query:         query,
+			Params:        params,
+			Normalized:    normalized,
+			Fingerprint:   fingerprint,
+			Start:         start,
+			Duration:      time.Since(start),
+			Err:           err,
+			InTransaction: inTx,
+		}
+		if err == nil && result != nil {
+			event.Rows = len(result.Rows)
+			event.Stats = result.Stats
+		}
+		c.chargeRows(result)
+		if err != nil && timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = &AdaptiveTimeoutError{Fingerprint: fingerprint, Timeout: timeout, Err: err}
+			event.Err = err
+		}
+		cancel()
+		if c.adaptive != nil {
+			c.adaptive.ObserveQuery(ctx, event)
+		}
+		if c.metrics != nil {
+			c.metrics.ObserveQuery(ctx, event)
+		}
+		return err
+	}
+}
+
+// QueryAggregatorOptions tunes a QueryAggregator.
+type QueryAggregatorOptions struct {
+	// Samples is how many recent latencies are kept per fingerprint for
+	// the percentiles. Defaults to 1024.
+	Samples int
+	// MaxFingerprints caps the fingerprints tracked; statements with new
+	// fingerprints beyond it are counted in QueryAggregator.Dropped.
+	// Defaults to 1000.
+	MaxFingerprints int
+}
+
+// FingerprintStats summarizes the statements sharing a fingerprint.
+type FingerprintStats struct {
+	Fingerprint string
+	// Query is the normalized statement.
+	Query  string
+	Count  int64
+	Errors int64
+	Rows   int64
+	// Total and Max cover every call; the percentiles cover the most
+	// recent QueryAggregatorOptions.Samples calls.
+	Total         time.Duration
+	Max           time.Duration
+	P50, P90, P99 time.Duration
+}
+
+// Mean is the average latency.
+func (s FingerprintStats) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// QueryAggregator is a MetricsHook that keeps per-fingerprint call
+// counts and latency percentiles in memory:
+//
+//	agg := nexus.NewQueryAggregator(nexus.QueryAggregatorOptions{})
+//	client, _ := nexus.NewClientE(nexus.Config{BaseURL: url, Metrics: agg})
+//	...
+//	for _, s := range agg.Snapshot() {
+//	    fmt.Println(s.Count, s.P99, s.Query)
+//	}
+//
+// It is safe for concurrent use.
+type QueryAggregator struct {
+	opts QueryAggregatorOptions
+
+	mu      sync.Mutex
+	stats   map[string]*fingerprintAgg
+	dropped int64
+}
+
+type fingerprintAgg struct {
+	stats  FingerprintStats
+	recent latencyWindow
+}
+
+// NewQueryAggregator returns an empty aggregator.
+func NewQueryAggregator(opts QueryAggregatorOptions) *QueryAggregator {
+	if opts.Samples <= 0 {
+		opts.Samples = 1024
+	}
+	if opts.MaxFingerprints <= 0 {
+		opts.MaxFingerprints = 1000
+	}
+	return &QueryAggregator{opts: opts, stats: map[string]*fingerprintAgg{}}
+}
+
+// ObserveQuery records event.
+func (a *QueryAggregator) ObserveQuery(_ context.Context, event QueryEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	agg, ok := a.stats[event.Fingerprint]
+	if !ok {
+		if len(a.stats) >= a.opts.MaxFingerprints {
+			a.dropped++
+			return
+		}
+		agg = &fingerprintAgg{stats: FingerprintStats{Fingerprint: event.Fingerprint, Query: event.Normalized}}
+		a.stats[event.Fingerprint] = agg
+	}
+	s := &agg.stats
+	s.Count++
+	if event.Err != nil {
+		s.Errors++
+	}
+	s.Rows += int64(event.Rows)
+	s.Total += event.Duration
+	s.Max = max(s.Max, event.Duration)
+	agg.recent.add(event.Duration, a.opts.Samples)
+}
+
+// Snapshot returns the current statistics, busiest fingerprint (by
+// total latency) first.
+func (a *QueryAggregator) Snapshot() []FingerprintStats {
+	a.mu.Lock()
+	out := make([]FingerprintStats, 0, len(a.stats))
+	sorted := make([][]time.Duration, 0, len(a.stats))
+	for _, agg := range a.stats {
+		out = append(out, agg.stats)
+		sorted = append(sorted, agg.recent.sorted())
+	}
+	a.mu.Unlock()
+
+	for i, samples := range sorted {
+		out[i].P50 = percentile(samples, 0.50)
+		out[i].P90 = percentile(samples, 0.90)
+		out[i].P99 = percentile(samples, 0.99)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Fingerprint < out[j].Fingerprint
+	})
+	return out
+}
+
+// Dropped is the number of statements not recorded because
+// MaxFingerprints was reached.
+func (a *QueryAggregator) Dropped() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.dropped
+}
+
+// Reset discards everything recorded so far.
+func (a *QueryAggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stats = map[string]*fingerprintAgg{}
+	a.dropped = 0
+}
+
+// percentile is the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// latencyWindow keeps the most recent latencies in a ring.
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+// add records d, keeping at most size samples.
+func (w *latencyWindow) add(d time.Duration, size int) {
+	if len(w.samples) < size {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % len(w.samples)
+}
+
+// sorted returns a sorted copy of the samples.
+func (w *latencyWindow) sorted() []time.Duration {
+	out := append([]time.Duration(nil), w.samples...)
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
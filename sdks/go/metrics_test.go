@@ -0,0 +1,111 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	cases := map[string]string{
+		"match (n:Person)\n  WHERE n.age > 30 AND n.name IN ['a', 'b'] // adults": "MATCH (n:Person) WHERE n.age > ? AND n.name IN [?]",
+		"MATCH (n {name: \"x\", ok: true}) RETURN n.score * 1.5 LIMIT 10":         "MATCH (n {name: ?, ok: ?}) RETURN n.score * ? LIMIT ?",
+		"MATCH (n) WHERE n.id = $id /* by id */ RETURN n":                         "MATCH (n) WHERE n.id = $id RETURN n",
+		"RETURN [] AS empty, [x IN [1,2] | x] AS xs":                              "RETURN [] AS empty, [x IN [?] | x] AS xs",
+		"MATCH (n) RETURN n.match, n.limit":                                       "MATCH (n) RETURN n.match, n.limit",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, NormalizeQuery(in), in)
+	}
+
+	assert.Equal(t,
+		QueryFingerprint("MATCH (n) WHERE n.x IN [1, 2, 3] RETURN n"),
+		QueryFingerprint("match (n)   where n.x in [4] return n"))
+	assert.NotEqual(t,
+		QueryFingerprint("MATCH (n:A) RETURN n"),
+		QueryFingerprint("MATCH (n:B) RETURN n"))
+	assert.Len(t, QueryFingerprint("RETURN 1"), 16)
+}
+
+func TestQueryAggregator(t *testing.T) {
+	agg := NewQueryAggregator(QueryAggregatorOptions{Samples: 10, MaxFingerprints: 2})
+	ctx := context.Background()
+	for i := 1; i <= 20; i++ {
+		agg.ObserveQuery(ctx, QueryEvent{Fingerprint: "a", Normalized: "A", Duration: time.Duration(i) * time.Millisecond, Rows: 1})
+	}
+	agg.ObserveQuery(ctx, QueryEvent{Fingerprint: "b", Normalized: "B", Duration: time.Second, Err: errors.New("boom")})
+	agg.ObserveQuery(ctx, QueryEvent{Fingerprint: "c", Normalized: "C", Duration: time.Second})
+
+	stats := agg.Snapshot()
+	require.Len(t, stats, 2)
+	assert.Equal(t, FingerprintStats{
+		Fingerprint: "b", Query: "B", Count: 1, Errors: 1,
+		Total: time.Second, Max: time.Second, P50: time.Second, P90: time.Second, P99: time.Second,
+	}, stats[0])
+	a := stats[1]
+	assert.Equal(t, int64(20), a.Count)
+	assert.Equal(t, int64(20), a.Rows)
+	assert.Equal(t, 210*time.Millisecond, a.Total)
+	assert.Equal(t, 10500*time.Microsecond, a.Mean())
+	assert.Equal(t, 20*time.Millisecond, a.Max)
+	// Percentiles cover the last 10 samples, 11ms..20ms.
+	assert.Equal(t, 15*time.Millisecond, a.P50)
+	assert.Equal(t, 19*time.Millisecond, a.P90)
+	assert.Equal(t, 20*time.Millisecond, a.P99)
+	assert.Equal(t, int64(1), agg.Dropped())
+
+	agg.Reset()
+	assert.Empty(t, agg.Snapshot())
+	assert.Zero(t, agg.Dropped())
+}
+
+func TestMetricsHookObservesQueries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"columns":["n"],"rows":[[1],[2]]}`))
+	}))
+	defer server.Close()
+
+	var events []QueryEvent
+	agg := NewQueryAggregator(QueryAggregatorOptions{})
+	hook := MetricsHookFunc(func(ctx context.Context, e QueryEvent) {
+		events = append(events, e)
+		agg.ObserveQuery(ctx, e)
+	})
+	client, err := NewClientWithOptions(server.URL,
+		WithMetrics(hook),
+		WithQueryPolicy(QueryPolicyFunc(func(_ context.Context, q string, _ map[string]interface{}) (string, error) {
+			if q == "forbidden" {
+				return "", errors.New("no")
+			}
+			return q, nil
+		})))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.ExecuteCypher(ctx, "MATCH (n) WHERE n.x = 1 RETURN n", nil)
+	require.NoError(t, err)
+	_, err = client.ExecuteCypherHTTP(ctx, "MATCH (n) WHERE n.x = 2 RETURN n", nil)
+	require.NoError(t, err)
+	_, err = (&Transaction{client: client, id: "tx1"}).ExecuteCypher(ctx, "MATCH (n) WHERE n.x = 3 RETURN n", nil)
+	require.NoError(t, err)
+	_, err = client.ExecuteCypher(ctx, "forbidden", nil)
+	require.Error(t, err)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, "MATCH (n) WHERE n.x = 2 RETURN n", events[1].Query)
+	assert.Equal(t, "MATCH (n) WHERE n.x = ? RETURN n", events[1].Normalized)
+	assert.Equal(t, 2, events[1].Rows)
+	assert.False(t, events[1].InTransaction)
+	assert.True(t, events[2].InTransaction)
+
+	stats := agg.Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(3), stats[0].Count)
+	assert.Equal(t, int64(6), stats[0].Rows)
+}
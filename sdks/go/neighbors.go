@@ -0,0 +1,154 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetNodeRelationships returns the relationships of nodeID in direction,
+// restricted to relTypes when given, ordered by ID. With DirectionBoth a
+// self-loop is returned once.
+//
+//	rels, err := client.GetNodeRelationships(ctx, id, nexus.DirectionOutgoing, "KNOWS", "FOLLOWS")
+func (c *Client) GetNodeRelationships(ctx context.Context, nodeID string, direction Direction, relTypes ...string) ([]*Relationship, error) {
+	id, err := parseID(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range relTypes {
+		if err := validLabelIdentifier(t); err != nil {
+			return nil, err
+		}
+	}
+
+	// Each direction is matched on its own so the pattern tells which
+	// end is the start node.
+	directions := []Direction{direction}
+	if direction == DirectionBoth {
+		directions = []Direction{DirectionOutgoing, DirectionIncoming}
+	}
+	var rels []*Relationship
+	seen := map[string]bool{}
+	for _, d := range directions {
+		result, err := c.ExecuteCypher(ctx,
+			"MATCH "+d.pattern("r", relTypes)+" WHERE id(a) = $id "+
+				"RETURN id(r) AS id, type(r) AS type, id(a) AS a, id(b) AS b, properties(r) AS props ORDER BY id",
+			map[string]interface{}{"id": id})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Rows {
+			if len(row) < 5 {
+				continue
+			}
+			r := &Relationship{ID: idString(row[0]), Type: asString(row[1]), StartNode: idString(row[2]), EndNode: idString(row[3])}
+			if d == DirectionIncoming {
+				r.StartNode, r.EndNode = r.EndNode, r.StartNode
+			}
+			r.Properties, _ = row[4].(map[string]interface{})
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				rels = append(rels, r)
+			}
+		}
+	}
+	if len(directions) > 1 {
+		sort.Slice(rels, func(i, j int) bool {
+			a, _ := parseID(rels[i].ID)
+			b, _ := parseID(rels[j].ID)
+			return a < b
+		})
+	}
+	return rels, nil
+}
+
+// NeighborOptions restricts the walk GetNeighbors makes.
+type NeighborOptions struct {
+	// Direction is the way relationships are followed (default
+	// outgoing).
+	Direction Direction
+	// RelTypes are the relationship types followed; empty follows every
+	// type.
+	RelTypes []string
+	// Labels, when set, only walks through nodes carrying one of them.
+	Labels []string
+	// Limit stops the walk once this many neighbours are found; 0 means
+	// no limit.
+	Limit int
+	// BatchSize is the number of nodes expanded per statement (default
+	// 1000).
+	BatchSize int
+}
+
+// Neighbor is a node GetNeighbors reached and its distance in hops.
+type Neighbor struct {
+	Node  *Node
+	Depth int
+}
+
+// GetNeighbors returns the nodes within depth hops of nodeID, nearest
+// first, each once at its shortest distance. The start node is not
+// included, even when a cycle leads back to it.
+//
+//	friends, err := client.GetNeighbors(ctx, id, 2, nexus.NeighborOptions{
+//		RelTypes: []string{"KNOWS"}, Direction: nexus.DirectionBoth,
+//	})
+func (c *Client) GetNeighbors(ctx context.Context, nodeID string, depth int, opts NeighborOptions) ([]Neighbor, error) {
+	root, err := parseID(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range opts.RelTypes {
+		if err := validLabelIdentifier(t); err != nil {
+			return nil, err
+		}
+	}
+	for _, l := range opts.Labels {
+		if err := validLabelIdentifier(l); err != nil {
+			return nil, err
+		}
+	}
+	if depth <= 0 {
+		depth = 1
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	expand := "UNWIND $ids AS id MATCH " + opts.Direction.pattern("", opts.RelTypes) + " WHERE id(a) = id"
+	if len(opts.Labels) > 0 {
+		expand += " AND (b:" + strings.Join(opts.Labels, " OR b:") + ")"
+	}
+	expand += " RETURN DISTINCT id(b) AS id, labels(b) AS labels, properties(b) AS props ORDER BY id"
+
+	var neighbors []Neighbor
+	level := []int64{root}
+	seen := map[int64]bool{root: true}
+	for hop := 1; hop <= depth && len(level) > 0; hop++ {
+		var next []int64
+		for _, batch := range chunkIDs(level, opts.BatchSize) {
+			result, err := c.ExecuteCypher(ctx, expand, map[string]interface{}{"ids": batch})
+			if err != nil {
+				return neighbors, fmt.Errorf("nexus: get neighbors: expand hop %d: %w", hop, err)
+			}
+			for _, row := range result.Rows {
+				if len(row) < 3 {
+					continue
+				}
+				id := int64(asInt(row[0]))
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				next = append(next, id)
+				neighbors = append(neighbors, Neighbor{Node: nodeFromRow(row), Depth: hop})
+				if opts.Limit > 0 && len(neighbors) == opts.Limit {
+					return neighbors, nil
+				}
+			}
+		}
+		level = next
+	}
+	return neighbors, nil
+}
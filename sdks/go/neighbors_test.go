@@ -0,0 +1,95 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNodeRelationships(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req.Query)
+		assert.Equal(t, float64(1), req.Parameters["id"])
+		rows := [][]interface{}{{7, "KNOWS", 1, 2, map[string]interface{}{"since": 2020}}, {9, "KNOWS", 1, 1, nil}}
+		if strings.Contains(req.Query, "<-") {
+			rows = [][]interface{}{{3, "FOLLOWS", 1, 4, nil}, {9, "KNOWS", 1, 1, nil}}
+		}
+		writeJSON(w, map[string]interface{}{"columns": []string{"id", "type", "a", "b", "props"}, "rows": rows})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	rels, err := client.GetNodeRelationships(ctx, "1", DirectionOutgoing, "KNOWS", "FOLLOWS")
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (a)-[r:KNOWS|FOLLOWS]->(b) WHERE id(a) = $id "+
+		"RETURN id(r) AS id, type(r) AS type, id(a) AS a, id(b) AS b, properties(r) AS props ORDER BY id", queries[0])
+	require.Len(t, rels, 2)
+	assert.Equal(t, Relationship{ID: "7", Type: "KNOWS", StartNode: "1", EndNode: "2", Properties: map[string]interface{}{"since": int64(2020)}}, *rels[0])
+
+	rels, err = client.GetNodeRelationships(ctx, "1", DirectionBoth)
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (a)<-[r]-(b) WHERE id(a) = $id "+
+		"RETURN id(r) AS id, type(r) AS type, id(a) AS a, id(b) AS b, properties(r) AS props ORDER BY id", queries[2])
+	var ids []string
+	for _, r := range rels {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"3", "7", "9"}, ids, "the self-loop is listed once")
+	assert.Equal(t, "4", rels[0].StartNode)
+	assert.Equal(t, "1", rels[0].EndNode)
+
+	_, err = client.GetNodeRelationships(ctx, "1", DirectionOutgoing, "BAD TYPE")
+	assert.Error(t, err)
+	assert.Len(t, queries, 3)
+}
+
+func TestGetNeighbors(t *testing.T) {
+	// 1 -> 2, 3; 2 -> 1, 4; 3 -> 4; 4 -> 5
+	edges := map[int][]int{1: {2, 3}, 2: {1, 4}, 3: {4}, 4: {5}}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req.Query)
+		rows := [][]interface{}{}
+		for _, id := range req.Parameters["ids"].([]interface{}) {
+			for _, b := range edges[int(id.(float64))] {
+				rows = append(rows, []interface{}{b, []string{"Person"}, map[string]interface{}{"n": b}})
+			}
+		}
+		writeJSON(w, map[string]interface{}{"columns": []string{"id", "labels", "props"}, "rows": rows})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	neighbors, err := client.GetNeighbors(ctx, "1", 2, NeighborOptions{RelTypes: []string{"KNOWS"}, Labels: []string{"Person", "Bot"}})
+	require.NoError(t, err)
+	assert.Equal(t, "UNWIND $ids AS id MATCH (a)-[:KNOWS]->(b) WHERE id(a) = id AND (b:Person OR b:Bot) "+
+		"RETURN DISTINCT id(b) AS id, labels(b) AS labels, properties(b) AS props ORDER BY id", queries[0])
+	got := map[string]int{}
+	for _, n := range neighbors {
+		got[n.Node.ID] = n.Depth
+	}
+	assert.Equal(t, map[string]int{"2": 1, "3": 1, "4": 2}, got)
+	assert.Len(t, queries, 2)
+
+	neighbors, err = client.GetNeighbors(ctx, "1", 5, NeighborOptions{Limit: 3})
+	require.NoError(t, err)
+	assert.Len(t, neighbors, 3)
+
+	neighbors, err = client.GetNeighbors(ctx, "1", 0, NeighborOptions{Direction: DirectionBoth})
+	require.NoError(t, err)
+	assert.Len(t, neighbors, 2)
+	assert.Contains(t, queries[len(queries)-1], "MATCH (a)--(b)")
+}
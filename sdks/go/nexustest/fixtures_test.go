@@ -0,0 +1,71 @@
+package nexustest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	var queries []string
+	nextID := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		query := req["query"].(string)
+		queries = append(queries, query)
+		if strings.Contains(query, "CREATE (a)-[r:WORKS_AT]->(b)") {
+			params := req["parameters"].(map[string]interface{})
+			assert.Equal(t, "0", params["from"])
+			assert.Equal(t, "1", params["to"])
+		}
+
+		nextID++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"columns": []string{"id"},
+			"rows":    [][]interface{}{{nextID - 1}},
+		})
+	}))
+	defer server.Close()
+
+	fsys := fstest.MapFS{
+		"01_people.yaml": {Data: []byte(`
+nodes:
+  - ref: alice
+    labels: [Person]
+    properties: {name: Alice, age: 30}
+`)},
+		"02_jobs.json": {Data: []byte(`{
+  "nodes": [{"ref": "acme", "labels": ["Company"], "properties": {"name": "Acme"}}],
+  "relationships": [{"ref": "job", "from": "alice", "to": "acme", "type": "WORKS_AT"}]
+}`)},
+		"README.md": {Data: []byte("ignored")},
+	}
+
+	client := nexus.NewClient(nexus.Config{BaseURL: server.URL})
+	fx, err := LoadFixtures(context.Background(), client, fsys)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0", fx.NodeID("alice"))
+	assert.Equal(t, "1", fx.NodeID("acme"))
+	assert.Equal(t, "2", fx.Relationships["job"])
+	assert.Equal(t, "CREATE (n:Person) SET n = $props RETURN id(n)", queries[0])
+	assert.Panics(t, func() { fx.NodeID("bob") })
+}
+
+func TestLoadFixturesUnknownRef(t *testing.T) {
+	client := nexus.NewClient(nexus.Config{BaseURL: "http://127.0.0.1:1"})
+	fsys := fstest.MapFS{"f.yaml": {Data: []byte("relationships:\n  - {from: a, to: b, type: X}\n")}}
+
+	_, err := LoadFixtures(context.Background(), client, fsys)
+	assert.ErrorContains(t, err, `unknown node ref "a"`)
+}
@@ -0,0 +1,203 @@
+// Package nl2cypher answers natural-language questions with Cypher
+// written by a language model the caller plugs in. The package supplies
+// the model with the graph's schema, validates what it writes before
+// anything reaches the server and runs the query with guardrails:
+//
+//	t := nl2cypher.New(client, nl2cypher.GeneratorFunc(func(ctx context.Context, req nl2cypher.Request) (string, error) {
+//	    return llm.Complete(ctx, "Schema:\n"+req.Schema.String()+"\nWrite one Cypher query answering: "+req.Question)
+//	}), nl2cypher.Options{})
+//	answer, err := t.Ask(ctx, "Who are Ann's friends?")
+//
+// By default queries must be read-only and at most 100 rows come back:
+// a LIMIT is added to queries without one and larger literal limits are
+// lowered.
+package nl2cypher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	nexus "github.com/hivellm/nexus-go"
+)
+
+// Querier is the subset of *nexus.Client the translator needs.
+type Querier interface {
+	ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error)
+}
+
+// Request is what the Generator is asked to translate.
+type Request struct {
+	Question string
+	Schema   *Schema
+	// Previous and Feedback are set when retrying: the query the last
+	// attempt produced and why it was rejected.
+	Previous string
+	Feedback string
+}
+
+// Generator writes a Cypher query for a request, typically by
+// prompting a language model. Markdown code fences around the query
+// are stripped.
+type Generator interface {
+	GenerateCypher(ctx context.Context, req Request) (string, error)
+}
+
+// GeneratorFunc adapts a function to Generator.
+type GeneratorFunc func(ctx context.Context, req Request) (string, error)
+
+func (f GeneratorFunc) GenerateCypher(ctx context.Context, req Request) (string, error) {
+	return f(ctx, req)
+}
+
+// Options configures a Translator. The zero value is read-only with a
+// 100-row limit and one retry.
+type Options struct {
+	// AllowWrites permits write clauses (CREATE, MERGE, SET, DELETE, …)
+	// and arbitrary procedure calls.
+	AllowWrites bool
+	// AllowedProcedures are procedures a read-only query may CALL.
+	AllowedProcedures []string
+	// MaxRows is the LIMIT enforced on the final RETURN (default 100;
+	// negative disables the limit).
+	MaxRows int
+	// Attempts is the number of generations tried before giving up; each
+	// retry is told why the previous query was rejected (default 2).
+	Attempts int
+	// SampleSize is the number of nodes and relationships sampled to
+	// discover the schema (default 1000).
+	SampleSize int
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxRows == 0 {
+		o.MaxRows = 100
+	}
+	if o.Attempts <= 0 {
+		o.Attempts = 2
+	}
+	if o.SampleSize <= 0 {
+		o.SampleSize = 1000
+	}
+	return o
+}
+
+// Answer is the outcome of Ask.
+type Answer struct {
+	Question string
+	// Cypher is the query that ran, after guardrails were applied.
+	Cypher string
+	Result *nexus.QueryResult
+}
+
+// Translator turns questions into validated Cypher.
+type Translator struct {
+	q    Querier
+	gen  Generator
+	opts Options
+
+	mu     sync.Mutex
+	schema *Schema
+}
+
+// New returns a Translator running queries through q.
+func New(q Querier, gen Generator, opts Options) *Translator {
+	return &Translator{q: q, gen: gen, opts: opts.withDefaults()}
+}
+
+// SetSchema replaces the discovered schema, e.g. with a curated one
+// that hides internal labels from the model.
+func (t *Translator) SetSchema(s *Schema) {
+	t.mu.Lock()
+	t.schema = s
+	t.mu.Unlock()
+}
+
+// Schema returns the schema given to the generator, sampling the graph
+// on first use.
+func (t *Translator) Schema(ctx context.Context) (*Schema, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.schema == nil {
+		s, err := DiscoverSchema(ctx, t.q, t.opts.SampleSize)
+		if err != nil {
+			return nil, err
+		}
+		t.schema = s
+	}
+	return t.schema, nil
+}
+
+// Translate asks the generator for a query answering question and
+// returns it once it validates, with the row limit applied. params are
+// the parameters the query will run with; the generator may refer to
+// them as $name.
+func (t *Translator) Translate(ctx context.Context, question string, params map[string]interface{}) (string, error) {
+	schema, err := t.Schema(ctx)
+	if err != nil {
+		return "", err
+	}
+	req := Request{Question: question, Schema: schema}
+	for attempt := 0; ; attempt++ {
+		query, err := t.gen.GenerateCypher(ctx, req)
+		if err != nil {
+			return "", fmt.Errorf("nl2cypher: generate: %w", err)
+		}
+		query = StripFences(query)
+		err = Validate(query, schema, params, t.opts)
+		if err == nil {
+			if t.opts.MaxRows > 0 {
+				query = LimitRows(query, t.opts.MaxRows)
+			}
+			return query, nil
+		}
+		if attempt+1 >= t.opts.Attempts {
+			return "", err
+		}
+		req.Previous, req.Feedback = query, err.Error()
+	}
+}
+
+// Ask translates question and runs the resulting query.
+func (t *Translator) Ask(ctx context.Context, question string, params map[string]interface{}) (*Answer, error) {
+	query, err := t.Translate(ctx, question, params)
+	if err != nil {
+		return nil, err
+	}
+	result, err := t.q.ExecuteCypher(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return &Answer{Question: question, Cypher: query, Result: result}, nil
+}
+
+// StripFences removes the Markdown code fence a model may wrap its
+// answer in, along with surrounding space and a trailing semicolon.
+func StripFences(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") {
+		s = s[3:]
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:] // language tag
+		}
+		if i := strings.LastIndex(s, "```"); i >= 0 {
+			s = s[:i]
+		}
+	}
+	return strings.TrimSuffix(strings.TrimSpace(s), ";")
+}
+
+// ValidationError lists why a generated query was rejected.
+type ValidationError struct {
+	Query    string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "nl2cypher: invalid query: " + strings.Join(e.Problems, "; ")
+}
+
+// ErrEmptyQuery is reported when the generator returns nothing.
+var ErrEmptyQuery = errors.New("nl2cypher: generator returned an empty query")
@@ -0,0 +1,130 @@
+package nl2cypher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuerier struct {
+	queries []string
+	params  []map[string]interface{}
+}
+
+func (f *fakeQuerier) ExecuteCypher(_ context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error) {
+	f.queries = append(f.queries, query)
+	f.params = append(f.params, params)
+	switch {
+	case strings.Contains(query, "collect(keys(n))"):
+		return &nexus.QueryResult{Rows: [][]interface{}{
+			{"Person", []interface{}{[]interface{}{"name", "age"}, []interface{}{"name"}}},
+			{"City", []interface{}{[]interface{}{"name"}}},
+		}}, nil
+	case strings.Contains(query, "type(r)"):
+		return &nexus.QueryResult{Rows: [][]interface{}{
+			{[]interface{}{"Person"}, "KNOWS", []interface{}{"Person"}, []interface{}{"since"}},
+			{[]interface{}{"Person"}, "LIVES_IN", []interface{}{"City"}, []interface{}{}},
+		}}, nil
+	}
+	return &nexus.QueryResult{Columns: []string{"name"}, Rows: [][]interface{}{{"Bob"}}}, nil
+}
+
+func TestDiscoverSchema(t *testing.T) {
+	s, err := DiscoverSchema(context.Background(), &fakeQuerier{}, 50)
+	require.NoError(t, err)
+	assert.Equal(t, []NodeSchema{{Label: "City", Properties: []string{"name"}}, {Label: "Person", Properties: []string{"age", "name"}}}, s.Nodes)
+	assert.Equal(t, "Node labels:\n  (:City {name})\n  (:Person {age, name})\n"+
+		"Relationship types:\n  [:KNOWS {since}]\n  [:LIVES_IN {}]\n"+
+		"Patterns:\n  (:Person)-[:KNOWS]->(:Person)\n  (:Person)-[:LIVES_IN]->(:City)\n", s.String())
+}
+
+func TestValidate(t *testing.T) {
+	s, err := DiscoverSchema(context.Background(), &fakeQuerier{}, 50)
+	require.NoError(t, err)
+	ok := []string{
+		"MATCH (p:Person {name: $name})-[:KNOWS]->(f:Person) RETURN f.name",
+		"MATCH (p)-[r:KNOWS|LIVES_IN]->(x) WHERE p:Person AND r.since > 2000 RETURN count(*)",
+		"MATCH (p:Person) RETURN p {.name, city: 'x:y'} // CREATE in a comment",
+		"CALL db.labels() YIELD label RETURN label",
+		"MATCH (p:Person) RETURN toUpper(p.name), [x IN [1.5, 2] | x * 2]",
+		"MATCH (p:Person) CALL { WITH p RETURN p.age AS age } RETURN age",
+	}
+	opts := Options{AllowedProcedures: []string{"db.labels"}}
+	for _, q := range ok {
+		assert.NoError(t, Validate(q, s, map[string]interface{}{"name": "Ann"}, opts), q)
+	}
+
+	bad := map[string]string{
+		"MATCH (p:Person) DETACH DELETE p":              "DELETE is not allowed",
+		"MATCH (p:Human) RETURN p":                      "unknown label :Human (known: City, Person)",
+		"MATCH (p)-[:FRIEND]->(q) RETURN q":             "unknown relationship type :FRIEND",
+		"MATCH (p:Person) RETURN p.email":               "unknown property key email",
+		"MATCH (p:Person) RETURN p; MATCH (n) RETURN n": "only one statement is allowed",
+		"MATCH (p:Person RETURN p":                      "unclosed bracket",
+		"MATCH (p:Person) WHERE p.name = $who RETURN p": "parameter $who is not provided",
+		"CALL dbms.killQueries(['1'])":                  "procedure dbms.killQueries is not allowed",
+		"RETURN 'unterminated":                          "unterminated ' literal",
+	}
+	for q, want := range bad {
+		err := Validate(q, s, nil, opts)
+		var verr *ValidationError
+		require.True(t, errors.As(err, &verr), q)
+		assert.Contains(t, verr.Error(), want, q)
+	}
+	assert.ErrorIs(t, Validate("  ", s, nil, opts), ErrEmptyQuery)
+	assert.NoError(t, Validate("CREATE (n:Anything)", nil, nil, Options{AllowWrites: true}))
+}
+
+func TestLimitRows(t *testing.T) {
+	cases := map[string]string{
+		"MATCH (n) RETURN n":                                       "MATCH (n) RETURN n LIMIT 100",
+		"MATCH (n) RETURN n LIMIT 5":                               "MATCH (n) RETURN n LIMIT 5",
+		"MATCH (n) RETURN n ORDER BY n.x limit 5000":               "MATCH (n) RETURN n ORDER BY n.x limit 100",
+		"MATCH (n) RETURN n LIMIT $n":                              "MATCH (n) RETURN n LIMIT $n",
+		"MATCH (n) CALL { WITH n RETURN n AS m LIMIT 1 } RETURN m": "MATCH (n) CALL { WITH n RETURN n AS m LIMIT 1 } RETURN m LIMIT 100",
+		"RETURN 1 UNION RETURN 2":                                  "RETURN 1 UNION RETURN 2",
+		"MATCH (n) WHERE n.s = 'RETURN' SET n.x = 1":               "MATCH (n) WHERE n.s = 'RETURN' SET n.x = 1",
+		"MATCH (n) RETURN n // all of them":                        "MATCH (n) RETURN n LIMIT 100 // all of them",
+		"MATCH (n) RETURN n /* every node */\n":                    "MATCH (n) RETURN n LIMIT 100 /* every node */\n",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, LimitRows(in, 100), in)
+	}
+}
+
+func TestAskRetriesWithFeedback(t *testing.T) {
+	q := &fakeQuerier{}
+	var requests []Request
+	gen := GeneratorFunc(func(_ context.Context, req Request) (string, error) {
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			return "MATCH (p:Human) RETURN p.name", nil
+		}
+		return "```cypher\nMATCH (p:Person {name: $name})-[:KNOWS]->(f) RETURN f.name;\n```", nil
+	})
+	tr := New(q, gen, Options{})
+	answer, err := tr.Ask(context.Background(), "Who does Ann know?", map[string]interface{}{"name": "Ann"})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Contains(t, requests[0].Schema.String(), "(:Person)-[:KNOWS]->(:Person)")
+	assert.Equal(t, "MATCH (p:Human) RETURN p.name", requests[1].Previous)
+	assert.Contains(t, requests[1].Feedback, "unknown label :Human")
+	assert.Equal(t, "MATCH (p:Person {name: $name})-[:KNOWS]->(f) RETURN f.name LIMIT 100", answer.Cypher)
+	assert.Equal(t, [][]interface{}{{"Bob"}}, answer.Result.Rows)
+	assert.Equal(t, 1000, q.params[0]["sample"])
+
+	// A supplied schema skips discovery; exhausted attempts surface the last error.
+	tr2 := New(q, GeneratorFunc(func(context.Context, Request) (string, error) {
+		return "MATCH (n) DELETE n", nil
+	}), Options{Attempts: 1})
+	tr2.SetSchema(&Schema{})
+	_, err = tr2.Ask(context.Background(), "wipe it", nil)
+	assert.ErrorContains(t, err, "DELETE is not allowed")
+	assert.Len(t, q.queries, 3)
+}
@@ -0,0 +1,163 @@
+package nl2cypher
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NodeSchema describes a label and the property keys seen on it.
+type NodeSchema struct {
+	Label      string
+	Properties []string
+}
+
+// RelationshipSchema describes a relationship type, its property keys
+// and the label pairs it connects.
+type RelationshipSchema struct {
+	Type       string
+	Properties []string
+	// Patterns are the observed (from label, to label) pairs, formatted
+	// as "(:Person)-[:KNOWS]->(:Person)".
+	Patterns []string
+}
+
+// Schema is the context given to the generator.
+type Schema struct {
+	Nodes         []NodeSchema
+	Relationships []RelationshipSchema
+}
+
+// DiscoverSchema samples up to sampleSize nodes and relationships to
+// find labels, relationship types, their property keys and the label
+// pairs each relationship type connects.
+func DiscoverSchema(ctx context.Context, q Querier, sampleSize int) (*Schema, error) {
+	params := map[string]interface{}{"sample": sampleSize}
+	nodes, err := q.ExecuteCypher(ctx,
+		"MATCH (n) WITH n LIMIT $sample UNWIND labels(n) AS label RETURN label, collect(keys(n)) AS keys", params)
+	if err != nil {
+		return nil, fmt.Errorf("nl2cypher: discover labels: %w", err)
+	}
+	rels, err := q.ExecuteCypher(ctx,
+		"MATCH (a)-[r]->(b) WITH a, r, b LIMIT $sample "+
+			"RETURN labels(a) AS from, type(r) AS type, labels(b) AS to, keys(r) AS keys", params)
+	if err != nil {
+		return nil, fmt.Errorf("nl2cypher: discover relationship types: %w", err)
+	}
+
+	s := &Schema{}
+	for _, row := range nodes.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		label, _ := row[0].(string)
+		keys := set{}
+		for _, ks := range asList(row[1]) {
+			keys.addAll(asList(ks))
+		}
+		s.Nodes = append(s.Nodes, NodeSchema{Label: label, Properties: keys.sorted()})
+	}
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].Label < s.Nodes[j].Label })
+
+	type relInfo struct{ keys, patterns set }
+	byType := map[string]*relInfo{}
+	for _, row := range rels.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		typ, _ := row[1].(string)
+		info := byType[typ]
+		if info == nil {
+			info = &relInfo{keys: set{}, patterns: set{}}
+			byType[typ] = info
+		}
+		info.keys.addAll(asList(row[3]))
+		for _, from := range asList(row[0]) {
+			for _, to := range asList(row[2]) {
+				info.patterns[fmt.Sprintf("(:%v)-[:%s]->(:%v)", from, typ, to)] = true
+			}
+		}
+	}
+	for typ, info := range byType {
+		s.Relationships = append(s.Relationships, RelationshipSchema{
+			Type: typ, Properties: info.keys.sorted(), Patterns: info.patterns.sorted(),
+		})
+	}
+	sort.Slice(s.Relationships, func(i, j int) bool { return s.Relationships[i].Type < s.Relationships[j].Type })
+	return s, nil
+}
+
+// String renders the schema compactly for a prompt.
+func (s *Schema) String() string {
+	var b strings.Builder
+	b.WriteString("Node labels:\n")
+	for _, n := range s.Nodes {
+		fmt.Fprintf(&b, "  (:%s {%s})\n", n.Label, strings.Join(n.Properties, ", "))
+	}
+	b.WriteString("Relationship types:\n")
+	for _, r := range s.Relationships {
+		fmt.Fprintf(&b, "  [:%s {%s}]\n", r.Type, strings.Join(r.Properties, ", "))
+	}
+	b.WriteString("Patterns:\n")
+	for _, r := range s.Relationships {
+		for _, p := range r.Patterns {
+			b.WriteString("  " + p + "\n")
+		}
+	}
+	return b.String()
+}
+
+// names indexes the schema for validation.
+func (s *Schema) names() (labels, types, props map[string]bool) {
+	labels, types, props = map[string]bool{}, map[string]bool{}, map[string]bool{}
+	if s == nil {
+		return
+	}
+	for _, n := range s.Nodes {
+		labels[n.Label] = true
+		for _, p := range n.Properties {
+			props[p] = true
+		}
+	}
+	for _, r := range s.Relationships {
+		types[r.Type] = true
+		for _, p := range r.Properties {
+			props[p] = true
+		}
+	}
+	return
+}
+
+type set map[string]bool
+
+func (s set) addAll(values []interface{}) {
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			s[str] = true
+		}
+	}
+}
+
+func (s set) sorted() []string {
+	out := make([]string, 0, len(s))
+	for k := range s {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func asList(v interface{}) []interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		return x
+	case []string:
+		out := make([]interface{}, len(x))
+		for i, s := range x {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
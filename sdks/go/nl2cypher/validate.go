@@ -0,0 +1,165 @@
+package nl2cypher
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+var writeClauses = []string{"CREATE", "MERGE", "SET", "DELETE", "DETACH", "REMOVE", "DROP", "FOREACH", "LOAD"}
+
+// Validate checks a generated query before it runs: it must be a
+// single, well-formed statement, read-only unless opts.AllowWrites is
+// set, refer only to labels, relationship types and property keys in
+// schema (when schema is non-nil), and use only parameters in params.
+// Problems are reported together in a *ValidationError.
+func Validate(query string, schema *Schema, params map[string]interface{}, opts Options) error {
+	if strings.TrimSpace(query) == "" {
+		return ErrEmptyQuery
+	}
+	toks, problems := cypherlex.Tokenize(query)
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	allowed := map[string]bool{}
+	for _, p := range opts.AllowedProcedures {
+		allowed[strings.ToLower(p)] = true
+	}
+	labels, types, props := schema.names()
+
+	for i, t := range toks {
+		prev, next := tokenAt(toks, i-1), tokenAt(toks, i+1)
+		switch {
+		case t.Text == ";" && t.Kind == cypherlex.Punct:
+			add("only one statement is allowed")
+		case cypherlex.Clause(toks, i, t.Text):
+			if opts.AllowWrites {
+				continue
+			}
+			for _, kw := range writeClauses {
+				if t.Keyword(kw) {
+					add("%s is not allowed in a read-only query", strings.ToUpper(t.Text))
+				}
+			}
+			if t.Keyword("CALL") && next.Text != "{" {
+				proc := procedureName(toks[i+1:])
+				if !allowed[strings.ToLower(proc)] {
+					add("procedure %s is not allowed", proc)
+				}
+			}
+		case (t.Text == ":" || t.Text == "|" || t.Text == "&") && t.Kind == cypherlex.Punct && schema != nil:
+			if next.Kind != cypherlex.Word && next.Kind != cypherlex.Quoted {
+				continue
+			}
+			// An empty graph has no names to check against.
+			switch {
+			case t.In == 'r':
+				if len(types) > 0 && !types[next.Name()] {
+					add("unknown relationship type :%s%s", next.Name(), known(types))
+				}
+			case t.In == '(' || t.In == 0 && t.Text == ":" && (prev.Kind == cypherlex.Word || prev.Kind == cypherlex.Quoted):
+				if len(labels) > 0 && !labels[next.Name()] {
+					add("unknown label :%s%s", next.Name(), known(labels))
+				}
+			}
+		case t.Text == "." && t.Kind == cypherlex.Punct && schema != nil && len(props) > 0:
+			// Namespaced function and procedure names are not properties.
+			after := tokenAt(toks, i+2).Text
+			if (prev.Kind == cypherlex.Word || prev.Kind == cypherlex.Quoted) && (next.Kind == cypherlex.Word || next.Kind == cypherlex.Quoted) &&
+				after != "(" && after != "." && !props[next.Name()] {
+				add("unknown property key %s", next.Name())
+			}
+		}
+	}
+
+	for _, name := range nexus.QueryParameters(query) {
+		if _, ok := params[name]; !ok {
+			add("parameter $%s is not provided", name)
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Query: query, Problems: problems}
+	}
+	return nil
+}
+
+func tokenAt(toks []cypherlex.Token, i int) cypherlex.Token {
+	if i < 0 || i >= len(toks) {
+		return cypherlex.Token{Kind: -1}
+	}
+	return toks[i]
+}
+
+// procedureName reads a dotted procedure name.
+func procedureName(toks []cypherlex.Token) string {
+	var b strings.Builder
+	for _, t := range toks {
+		if t.Kind != cypherlex.Word && t.Kind != cypherlex.Quoted && t.Text != "." {
+			break
+		}
+		b.WriteString(t.Name())
+	}
+	return b.String()
+}
+
+// known formats a hint listing the names a model could have used.
+func known(names map[string]bool) string {
+	if len(names) == 0 {
+		return ""
+	}
+	list := make([]string, 0, len(names))
+	for n := range names {
+		list = append(list, n)
+	}
+	sort.Strings(list)
+	if len(list) > 20 {
+		list = append(list[:20], "…")
+	}
+	return " (known: " + strings.Join(list, ", ") + ")"
+}
+
+// LimitRows caps the rows a query returns at n: a LIMIT is appended to
+// a final RETURN without one, and a literal LIMIT above n is lowered.
+// Queries without a top-level RETURN, UNIONs and parameterised limits
+// are left unchanged.
+func LimitRows(query string, n int) string {
+	toks, problems := cypherlex.Tokenize(query)
+	if len(problems) > 0 {
+		return query
+	}
+	ret := -1
+	for i, t := range toks {
+		if t.Depth != 0 {
+			continue
+		}
+		if t.Keyword("UNION") {
+			return query
+		}
+		if t.Keyword("RETURN") {
+			ret = i
+		}
+	}
+	if ret < 0 {
+		return query
+	}
+	for i := ret + 1; i < len(toks); i++ {
+		t := toks[i]
+		if t.Depth != 0 || !t.Keyword("LIMIT") {
+			continue
+		}
+		next := tokenAt(toks, i+1)
+		if v, err := strconv.Atoi(next.Text); err == nil && next.Kind == cypherlex.Number && v > n {
+			return query[:next.Start] + strconv.Itoa(n) + query[next.End:]
+		}
+		return query
+	}
+	// The LIMIT goes right after the last token, so a trailing comment
+	// cannot swallow it.
+	end := toks[len(toks)-1].End
+	return query[:end] + " LIMIT " + strconv.Itoa(n) + query[end:]
+}
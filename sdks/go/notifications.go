@@ -0,0 +1,113 @@
+package nexus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NotificationCategory groups server notifications.
+type NotificationCategory string
+
+const (
+	// NotificationDeprecation flags deprecated syntax or procedures.
+	NotificationDeprecation NotificationCategory = "DEPRECATION"
+	// NotificationPerformance flags plans likely to be slow, such as
+	// cartesian products or unbounded variable-length patterns.
+	NotificationPerformance NotificationCategory = "PERFORMANCE"
+	// NotificationHint flags index or join hints the planner could not
+	// honour.
+	NotificationHint NotificationCategory = "HINT"
+	// NotificationUnrecognized flags unknown labels, types or properties.
+	NotificationUnrecognized NotificationCategory = "UNRECOGNIZED"
+	NotificationGeneric      NotificationCategory = "GENERIC"
+)
+
+// Notification is a warning or informational message the server
+// attached to a query result.
+type Notification struct {
+	Code        string               `json:"code"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Severity    string               `json:"severity"`
+	Category    NotificationCategory `json:"category"`
+	// Position points into the query text, when known.
+	Position *NotificationPosition `json:"position,omitempty"`
+}
+
+// NotificationPosition locates a notification in the query text.
+type NotificationPosition struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (n Notification) String() string {
+	s := fmt.Sprintf("%s [%s]: %s", n.Code, n.Category, n.Title)
+	if n.Position != nil {
+		s += fmt.Sprintf(" (line %d, column %d)", n.Position.Line, n.Position.Column)
+	}
+	return s
+}
+
+// NotificationError is returned when a result carries notifications in
+// a category listed in Config.EscalateNotifications. The query has
+// already executed; the result is returned alongside the error.
+type NotificationError struct {
+	Notifications []Notification
+}
+
+func (e *NotificationError) Error() string {
+	msgs := make([]string, len(e.Notifications))
+	for i, n := range e.Notifications {
+		msgs[i] = n.String()
+	}
+	return "nexus: escalated notification(s): " + strings.Join(msgs, "; ")
+}
+
+// checkNotifications applies Config.EscalateNotifications to result.
+func (c *Client) checkNotifications(result *QueryResult) error {
+	if len(c.escalate) == 0 || result == nil {
+		return nil
+	}
+	var escalated []Notification
+	for _, n := range result.Notifications {
+		if c.escalate[n.Category] {
+			escalated = append(escalated, n)
+		}
+	}
+	if len(escalated) > 0 {
+		return &NotificationError{Notifications: escalated}
+	}
+	return nil
+}
+
+func decodeNotifications(raw []interface{}) []Notification {
+	out := make([]Notification, 0, len(raw))
+	for _, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		n := Notification{
+			Code:        asString(m["code"]),
+			Title:       asString(m["title"]),
+			Description: asString(m["description"]),
+			Severity:    asString(m["severity"]),
+			Category:    NotificationCategory(asString(m["category"])),
+		}
+		if pos, ok := m["position"].(map[string]interface{}); ok {
+			n.Position = &NotificationPosition{
+				Offset: asInt(pos["offset"]),
+				Line:   asInt(pos["line"]),
+				Column: asInt(pos["column"]),
+			}
+		}
+		out = append(out, n)
+	}
+	return out
+}
+
+func asString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
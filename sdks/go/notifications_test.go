@@ -0,0 +1,45 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationsSurfaceAndEscalate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"columns":["a","b"],"rows":[],"notifications":[
+			{"code":"Nexus.Performance.CartesianProduct","title":"Cartesian product","severity":"WARNING",
+			 "category":"PERFORMANCE","position":{"offset":6,"line":1,"column":7}},
+			{"code":"Nexus.Deprecation.Syntax","title":"Deprecated syntax","severity":"WARNING","category":"DEPRECATION"}]}`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	query := "MATCH (a), (b) RETURN a, b"
+
+	result, err := NewClient(Config{BaseURL: server.URL}).ExecuteCypher(ctx, query, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Notifications, 2)
+	assert.Equal(t, NotificationPerformance, result.Notifications[0].Category)
+	assert.Equal(t, 7, result.Notifications[0].Position.Column)
+
+	strict := NewClient(Config{BaseURL: server.URL, EscalateNotifications: []NotificationCategory{NotificationPerformance}})
+	result, err = strict.ExecuteCypher(ctx, query, nil)
+	var notifErr *NotificationError
+	require.True(t, errors.As(err, &notifErr))
+	require.Len(t, notifErr.Notifications, 1)
+	assert.Equal(t, "Nexus.Performance.CartesianProduct", notifErr.Notifications[0].Code)
+	assert.NotNil(t, result)
+}
+
+func TestDecodeNotificationsWithoutCode(t *testing.T) {
+	n := decodeNotifications([]interface{}{map[string]interface{}{"title": "Hint"}, "not a notification"})
+	require.Len(t, n, 1)
+	assert.Equal(t, Notification{Title: "Hint"}, n[0])
+}
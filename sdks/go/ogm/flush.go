@@ -0,0 +1,501 @@
+package ogm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/hivellm/nexus-go/internal/ids"
+)
+
+// flushPlan is the set of entities one Flush writes: the tracked ones
+// plus those reached through cascades. It is applied to the session
+// only once the transaction has committed.
+type flushPlan struct {
+	states  map[interface{}]*tracked
+	order   []interface{}
+	added   []interface{} // entities tracked by cascade
+	deleted map[interface{}]bool
+}
+
+func (p *flushPlan) state(entity interface{}) (*tracked, error) {
+	if t, ok := p.states[entity]; ok {
+		return t, nil
+	}
+	et, _, err := entityOf(entity)
+	if err != nil {
+		return nil, err
+	}
+	t := &tracked{entity: entity, typ: et}
+	p.states[entity] = t
+	p.order = append(p.order, entity)
+	p.added = append(p.added, entity)
+	return t, nil
+}
+
+// label returns the node label entity is mapped to.
+func (p *flushPlan) label(entity interface{}) string {
+	if t, ok := p.states[entity]; ok {
+		return t.typ.label
+	}
+	if et, _, err := entityOf(entity); err == nil {
+		return et.label
+	}
+	return ""
+}
+
+// id returns the entity's node ID, or "" when it is new.
+// Related entities outside the plan are linked by their ID alone.
+func (p *flushPlan) id(entity interface{}) string {
+	if t, ok := p.states[entity]; ok {
+		return t.typ.getID(reflect.ValueOf(entity).Elem())
+	}
+	et, v, err := entityOf(entity)
+	if err != nil {
+		return ""
+	}
+	return et.getID(v)
+}
+
+// referenced reports whether a live entity of the plan links to target.
+func (p *flushPlan) referenced(target interface{}) bool {
+	for _, e := range p.order {
+		if p.deleted[e] {
+			continue
+		}
+		t := p.states[e]
+		v := reflect.ValueOf(e).Elem()
+		for _, rf := range t.typ.rels {
+			for _, r := range rf.related(v) {
+				if r == target {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// cascade applies the save and delete cascades until nothing changes.
+func (p *flushPlan) cascade() error {
+	var remove func(entity interface{}) error
+	queue := append([]interface{}(nil), p.order...)
+	remove = func(entity interface{}) error {
+		if p.deleted[entity] {
+			return nil
+		}
+		if _, err := p.state(entity); err != nil {
+			return err
+		}
+		p.deleted[entity] = true
+		queue = append(queue, entity)
+		return nil
+	}
+	for len(queue) > 0 {
+		entity := queue[0]
+		queue = queue[1:]
+		t := p.states[entity]
+		v := reflect.ValueOf(entity).Elem()
+		for i, rf := range t.typ.rels {
+			current := rf.related(v)
+			var before []interface{}
+			if t.links != nil {
+				before = t.links[i]
+			}
+			if p.deleted[entity] {
+				if rf.cascade&CascadeDelete != 0 {
+					for _, r := range append(current, before...) {
+						if err := remove(r); err != nil {
+							return err
+						}
+					}
+				}
+				continue
+			}
+			for _, r := range current {
+				if _, ok := p.states[r]; ok {
+					continue
+				}
+				if rf.cascade&CascadeSave == 0 {
+					if et, rv, err := entityOf(r); err != nil || et.getID(rv) == "" {
+						return fmt.Errorf("ogm: %s.%s holds an unsaved %T; Save it or add the save cascade", t.typ.typ.Name(), rf.name, r)
+					}
+					continue
+				}
+				if _, err := p.state(r); err != nil {
+					return err
+				}
+				queue = append(queue, r)
+			}
+			if rf.cascade&CascadeDeleteOrphans != 0 {
+				for _, r := range before {
+					if !containsEntity(current, r) && !p.referenced(r) {
+						if err := remove(r); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func containsEntity(list []interface{}, entity interface{}) bool {
+	for _, e := range list {
+		if e == entity {
+			return true
+		}
+	}
+	return false
+}
+
+// link is one relationship to create or delete.
+type link struct {
+	from, to interface{}
+	typ      string
+	create   bool
+}
+
+// links lists the relationship changes of the live entities, each
+// once even when both ends map it.
+func (p *flushPlan) links() []link {
+	var out []link
+	seen := map[link]bool{}
+	add := func(l link) {
+		if p.deleted[l.from] || p.deleted[l.to] || seen[l] {
+			return
+		}
+		seen[l] = true
+		out = append(out, l)
+	}
+	for _, e := range p.order {
+		if p.deleted[e] {
+			continue
+		}
+		t := p.states[e]
+		v := reflect.ValueOf(e).Elem()
+		for i, rf := range t.typ.rels {
+			current := rf.related(v)
+			var before []interface{}
+			if t.links != nil {
+				before = t.links[i]
+			}
+			pair := func(r interface{}, create bool) link {
+				if rf.incoming {
+					return link{from: r, to: e, typ: rf.name, create: create}
+				}
+				return link{from: e, to: r, typ: rf.name, create: create}
+			}
+			for _, r := range current {
+				if !containsEntity(before, r) {
+					add(pair(r, true))
+				}
+			}
+			for _, r := range before {
+				if !containsEntity(current, r) {
+					add(pair(r, false))
+				}
+			}
+		}
+	}
+	return out
+}
+
+// pendingWrite is the outcome of one entity's statement, applied after
+// commit.
+type pendingWrite struct {
+	t        *tracked
+	snapshot map[string]interface{}
+	column   string // result column of the new node's ID, for creations
+}
+
+// Flush writes every pending change in one transaction: new entities
+// and the relationships touching them, property updates (only the
+// changed properties), relationship changes between existing nodes,
+// then deletions. Relationship fields are cascaded as their tags say.
+// Entities with no changes cost nothing. On success created entities
+// receive their IDs and every entity's snapshot is refreshed; on error
+// nothing was written and the session is unchanged, so Flush can be
+// retried.
+//
+// Existing nodes are matched by ID, label and the session's row
+// filters, so a write never reaches a node of another label or outside
+// the session's tenant; such a write matches nothing.
+func (s *Session) Flush(ctx context.Context) error {
+	p := &flushPlan{states: map[interface{}]*tracked{}, deleted: map[interface{}]bool{}}
+	for _, e := range s.order {
+		t := s.tracked[e]
+		p.states[e] = t
+		p.order = append(p.order, e)
+		if t.deleted {
+			p.deleted[e] = true
+		}
+	}
+	if err := p.cascade(); err != nil {
+		return err
+	}
+
+	var (
+		statements []nexus.Statement
+		writes     []pendingWrite
+		deletes    = map[string][]int64{} // label → node IDs
+		deleted    []string               // labels of deletes, in order
+		create     = &createStatement{vars: map[interface{}]string{}, params: map[string]interface{}{}}
+	)
+	for _, e := range p.order {
+		t := p.states[e]
+		v := reflect.ValueOf(e).Elem()
+		id := t.typ.getID(v)
+		if p.deleted[e] {
+			if id == "" {
+				continue
+			}
+			n, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return fmt.Errorf("ogm: delete %T: invalid node ID %q", e, id)
+			}
+			if _, ok := deletes[t.typ.label]; !ok {
+				deleted = append(deleted, t.typ.label)
+			}
+			deletes[t.typ.label] = append(deletes[t.typ.label], n)
+			continue
+		}
+		set, removed, err := t.diff()
+		if err != nil {
+			return err
+		}
+		if id != "" && t.snapshot != nil && len(set) == 0 && len(removed) == 0 {
+			continue
+		}
+		if s.opts.Schema != nil {
+			if violations := s.opts.Schema.Validate([]string{t.typ.label}, t.typ.properties(v)); len(violations) > 0 {
+				return &nexus.ValidationError{Violations: violations}
+			}
+		}
+		snapshot, err := normalize(t.typ.properties(v))
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			writes = append(writes, pendingWrite{t: t, snapshot: snapshot, column: create.node(e, t.typ.label, snapshot)})
+			continue
+		}
+		n, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return fmt.Errorf("ogm: update %T: invalid node ID %q", e, id)
+		}
+		statements = append(statements, s.updateStatement(t.typ.label, n, set, removed))
+		writes = append(writes, pendingWrite{t: t, snapshot: snapshot})
+	}
+	for _, l := range p.links() {
+		from, to := p.id(l.from), p.id(l.to)
+		if from == "" || to == "" {
+			// Only new nodes can be missing an ID, and those are
+			// created by the create statement.
+			if l.create {
+				if err := create.link(p, l); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		st, err := s.linkStatement(p, from, to, l)
+		if err != nil {
+			return err
+		}
+		statements = append(statements, st)
+	}
+	for _, label := range deleted {
+		query, params := s.scope("(n:"+cypherName(label)+")", "id(n) IN $ids", map[string]interface{}{"ids": deletes[label]})
+		statements = append(statements, nexus.Statement{Query: query + " DETACH DELETE n", Parameters: params})
+	}
+	first := 0
+	if len(create.creates) > 0 {
+		statements = append([]nexus.Statement{create.statement(s)}, statements...)
+		first = 1
+	}
+	if len(statements) == 0 {
+		return nil
+	}
+
+	results, err := s.q.RunTransaction(ctx, statements)
+	if err != nil {
+		return fmt.Errorf("ogm: flush: %w", err)
+	}
+	var created []interface{}
+	if first == 1 {
+		r := results[0]
+		if r == nil || len(r.Rows) == 0 {
+			return fmt.Errorf("ogm: flush: no IDs returned for new entities")
+		}
+		created = r.Rows[0]
+	}
+	for _, e := range p.added {
+		s.track(e, p.states[e].typ)
+	}
+	for _, w := range writes {
+		if w.column != "" {
+			i := columnIndex(results[0].Columns, w.column)
+			if i < 0 || i >= len(created) {
+				return fmt.Errorf("ogm: flush: no ID returned for new %T", w.t.entity)
+			}
+			v := reflect.ValueOf(w.t.entity).Elem()
+			if err := w.t.typ.setID(v, ids.Format(created[i])); err != nil {
+				return err
+			}
+			s.identity[identityKey{w.t.typ.typ, w.t.typ.getID(v)}] = w.t.entity
+		}
+		s.tracked[w.t.entity].snapshot = w.snapshot
+	}
+	for _, e := range p.order {
+		if p.deleted[e] {
+			s.Detach(e)
+			continue
+		}
+		t := s.tracked[e]
+		if len(t.typ.rels) == 0 {
+			continue
+		}
+		v := reflect.ValueOf(e).Elem()
+		t.links = make([][]interface{}, len(t.typ.rels))
+		for i, rf := range t.typ.rels {
+			t.links[i] = rf.related(v)
+		}
+	}
+	return nil
+}
+
+// createStatement builds the one statement creating every new node and
+// the relationships touching them, so that nodes created together can
+// be linked before any of them has an ID:
+//
+//	MATCH (e0:Customer) WHERE id(e0) = $e0
+//	CREATE (n0:Order) SET n0 = $n0 CREATE (n1:Line) SET n1 = $n1
+//	CREATE (n0)-[:HAS_LINE]->(n1) CREATE (e0)-[:PLACED]->(n0)
+//	RETURN id(n0) AS n0, id(n1) AS n1
+type createStatement struct {
+	vars     map[interface{}]string // entity → variable
+	matches  []string               // variables of existing nodes
+	labels   []string               // their labels
+	creates  []string
+	links    []string
+	params   map[string]interface{}
+	returned []string
+}
+
+func (c *createStatement) node(entity interface{}, label string, props map[string]interface{}) string {
+	name := fmt.Sprintf("n%d", len(c.creates))
+	c.vars[entity] = name
+	c.params[name] = props
+	c.creates = append(c.creates, fmt.Sprintf("CREATE (%s:%s) SET %s = $%s", name, cypherName(label), name, name))
+	c.returned = append(c.returned, fmt.Sprintf("id(%s) AS %s", name, name))
+	return name
+}
+
+// endpoint returns the variable of a link end, matching existing nodes
+// by ID.
+func (c *createStatement) endpoint(p *flushPlan, entity interface{}) (string, error) {
+	if name, ok := c.vars[entity]; ok {
+		return name, nil
+	}
+	id := p.id(entity)
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("ogm: link %T: invalid node ID %q", entity, id)
+	}
+	name := fmt.Sprintf("e%d", len(c.matches))
+	c.vars[entity] = name
+	c.params[name] = n
+	c.matches = append(c.matches, name)
+	c.labels = append(c.labels, p.label(entity))
+	return name, nil
+}
+
+func (c *createStatement) link(p *flushPlan, l link) error {
+	from, err := c.endpoint(p, l.from)
+	if err != nil {
+		return err
+	}
+	to, err := c.endpoint(p, l.to)
+	if err != nil {
+		return err
+	}
+	c.links = append(c.links, fmt.Sprintf("CREATE (%s)-[:%s]->(%s)", from, l.typ, to))
+	return nil
+}
+
+func (c *createStatement) statement(s *Session) nexus.Statement {
+	var parts []string
+	params := c.params
+	if len(c.matches) > 0 {
+		patterns := make([]string, len(c.matches))
+		conds := make([]string, len(c.matches))
+		for i, m := range c.matches {
+			patterns[i] = fmt.Sprintf("(%s:%s)", m, cypherName(c.labels[i]))
+			conds[i] = fmt.Sprintf("id(%s) = $%s", m, m)
+		}
+		var match string
+		match, params = s.scope(strings.Join(patterns, ", "), strings.Join(conds, " AND "), c.params)
+		parts = append(parts, match)
+	}
+	parts = append(parts, c.creates...)
+	parts = append(parts, c.links...)
+	parts = append(parts, "RETURN "+strings.Join(c.returned, ", "))
+	return nexus.Statement{Query: strings.Join(parts, " "), Parameters: params}
+}
+
+// linkStatement creates or deletes one relationship between existing
+// nodes.
+func (s *Session) linkStatement(p *flushPlan, from, to string, l link) (nexus.Statement, error) {
+	a, err := strconv.ParseInt(from, 10, 64)
+	if err != nil {
+		return nexus.Statement{}, fmt.Errorf("ogm: link: invalid node ID %q", from)
+	}
+	b, err := strconv.ParseInt(to, 10, 64)
+	if err != nil {
+		return nexus.Statement{}, fmt.Errorf("ogm: link: invalid node ID %q", to)
+	}
+	params := map[string]interface{}{"from": a, "to": b}
+	from, to = "(a:"+cypherName(p.label(l.from))+")", "(b:"+cypherName(p.label(l.to))+")"
+	if l.create {
+		query, params := s.scope(from+", "+to, "id(a) = $from AND id(b) = $to", params)
+		return nexus.Statement{Query: query + " CREATE (a)-[:" + l.typ + "]->(b)", Parameters: params}, nil
+	}
+	query, params := s.scope(from+"-[r:"+l.typ+"]->"+to, "id(a) = $from AND id(b) = $to", params)
+	return nexus.Statement{Query: query + " DELETE r", Parameters: params}, nil
+}
+
+// updateStatement sets the changed properties and removes the dropped
+// ones of one node.
+func (s *Session) updateStatement(label string, id int64, set map[string]interface{}, removed []string) nexus.Statement {
+	var b strings.Builder
+	query, params := s.scope("(n:"+cypherName(label)+")", "id(n) = $id", map[string]interface{}{"id": id})
+	b.WriteString(query)
+	if len(set) > 0 {
+		b.WriteString(" SET n += $set")
+		params["set"] = set
+	}
+	if len(removed) > 0 {
+		b.WriteString(" REMOVE ")
+		for i, k := range removed {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("n." + cypherName(k))
+		}
+	}
+	return nexus.Statement{Query: b.String(), Parameters: params}
+}
+
+func columnIndex(columns []string, name string) int {
+	for i, c := range columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
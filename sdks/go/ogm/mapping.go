@@ -0,0 +1,316 @@
+package ogm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// Labeler lets an entity type choose its node label; by default the
+// label is the struct's type name.
+type Labeler interface {
+	NodeLabel() string
+}
+
+// entityType describes how a struct maps onto a node.
+type entityType struct {
+	typ    reflect.Type
+	label  string
+	id     []int // index of the ID field
+	fields []propField
+	rels   []relField
+}
+
+// propField is one struct field stored as a node property.
+type propField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// Cascade controls what saving and deleting an entity does to the
+// entities related through one relationship field. Without any flag a
+// relationship field only links: deleting the entity detaches it, and
+// removing a child from the field deletes just the relationship.
+type Cascade int
+
+const (
+	// CascadeSave saves related entities along with the entity, creating
+	// new ones and writing changes to ones the session does not track.
+	CascadeSave Cascade = 1 << iota
+	// CascadeDelete deletes related entities with the entity.
+	CascadeDelete
+	// CascadeDeleteOrphans deletes a related entity once it is removed
+	// from the field, unless another entity in the session still links
+	// to it.
+	CascadeDeleteOrphans
+)
+
+// relField is one struct field holding related entities.
+type relField struct {
+	name     string // relationship type
+	index    []int
+	incoming bool
+	many     bool
+	target   reflect.Type // struct type of the related entities
+	cascade  Cascade
+}
+
+var entityTypes sync.Map // reflect.Type → *entityType
+
+// entityOf checks that entity is a non-nil pointer to a struct and
+// returns its mapping and the struct value.
+func entityOf(entity interface{}) (*entityType, reflect.Value, error) {
+	v := reflect.ValueOf(entity)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, reflect.Value{}, fmt.Errorf("ogm: entity must be a non-nil pointer to a struct, got %T", entity)
+	}
+	et, err := typeOf(v.Type().Elem())
+	return et, v.Elem(), err
+}
+
+// typeOf returns the mapping of a struct type, built once. Exported
+// fields become properties named by their `nexus:"name"` tag or, when
+// untagged, by the field name; `nexus:"-"` skips a field and
+// `nexus:",omitempty"` leaves zero values unset. The field tagged
+// `nexus:",id"`, or else a field named ID, receives the node ID and
+// must be a string or an integer.
+//
+// Fields of type *T or []*T, with T a mapped struct, hold related
+// entities when tagged `nexus:"TYPE,rel"`: the relationship type
+// followed by "rel" and optional flags, "in" for incoming relationships
+// and "save", "delete" and "orphans" for the cascades:
+//
+//	Lines []*Line `nexus:"HAS_LINE,rel,save,delete,orphans"`
+func typeOf(t reflect.Type) (*entityType, error) {
+	if cached, ok := entityTypes.Load(t); ok {
+		return cached.(*entityType), nil
+	}
+	et := &entityType{typ: t, label: t.Name()}
+	if l, ok := reflect.New(t).Interface().(Labeler); ok {
+		et.label = l.NodeLabel()
+	}
+	var fallbackID []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("nexus")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if flags := strings.Split(opts, ","); flags[0] == "rel" {
+			rf, err := relationField(f, name, flags[1:])
+			if err != nil {
+				return nil, err
+			}
+			et.rels = append(et.rels, rf)
+			continue
+		}
+		if opts == "id" {
+			et.id = f.Index
+			continue
+		}
+		if tag == "" && f.Name == "ID" {
+			fallbackID = f.Index
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		et.fields = append(et.fields, propField{name: name, index: f.Index, omitEmpty: opts == "omitempty"})
+	}
+	if et.id == nil {
+		et.id = fallbackID
+	}
+	if et.id == nil {
+		return nil, fmt.Errorf("ogm: %s has no ID field (tag one `nexus:\",id\"`)", t)
+	}
+	switch t.FieldByIndex(et.id).Type.Kind() {
+	case reflect.String, reflect.Int, reflect.Int64:
+	default:
+		return nil, fmt.Errorf("ogm: ID field of %s must be a string, int or int64", t)
+	}
+	if et.label == "" {
+		return nil, fmt.Errorf("ogm: %s has no label", t)
+	}
+	cached, _ := entityTypes.LoadOrStore(t, et)
+	return cached.(*entityType), nil
+}
+
+func relationField(f reflect.StructField, name string, flags []string) (relField, error) {
+	rf := relField{name: name, index: f.Index}
+	t := f.Type
+	if t.Kind() == reflect.Slice {
+		rf.many = true
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return rf, fmt.Errorf("ogm: relationship field %s must be *T or []*T, not %s", f.Name, f.Type)
+	}
+	rf.target = t.Elem()
+	if !cypherlex.IsPlainName(name) {
+		return rf, fmt.Errorf("ogm: relationship field %s: %q is not a valid relationship type", f.Name, name)
+	}
+	for _, flag := range flags {
+		switch flag {
+		case "in":
+			rf.incoming = true
+		case "save":
+			rf.cascade |= CascadeSave
+		case "delete":
+			rf.cascade |= CascadeDelete
+		case "orphans":
+			rf.cascade |= CascadeDeleteOrphans
+		default:
+			return rf, fmt.Errorf("ogm: relationship field %s: unknown flag %q", f.Name, flag)
+		}
+	}
+	return rf, nil
+}
+
+// related returns the non-nil entity pointers held by the field.
+func (rf relField) related(v reflect.Value) []interface{} {
+	f := v.FieldByIndex(rf.index)
+	if !rf.many {
+		if f.IsNil() {
+			return nil
+		}
+		return []interface{}{f.Interface()}
+	}
+	out := make([]interface{}, 0, f.Len())
+	for i := 0; i < f.Len(); i++ {
+		if e := f.Index(i); !e.IsNil() {
+			out = append(out, e.Interface())
+		}
+	}
+	return out
+}
+
+// setRelated stores entity pointers in the field.
+func (rf relField) setRelated(v reflect.Value, entities []interface{}) {
+	f := v.FieldByIndex(rf.index)
+	if !rf.many {
+		f.Set(reflect.Zero(f.Type()))
+		if len(entities) > 0 {
+			f.Set(reflect.ValueOf(entities[0]))
+		}
+		return
+	}
+	out := reflect.MakeSlice(f.Type(), 0, len(entities))
+	for _, e := range entities {
+		out = reflect.Append(out, reflect.ValueOf(e))
+	}
+	f.Set(out)
+}
+
+// getID returns the entity's node ID, or "" when it has none yet.
+func (et *entityType) getID(v reflect.Value) string {
+	f := v.FieldByIndex(et.id)
+	if f.Kind() == reflect.String {
+		return f.String()
+	}
+	if f.Int() == 0 {
+		return ""
+	}
+	return strconv.FormatInt(f.Int(), 10)
+}
+
+func (et *entityType) setID(v reflect.Value, id string) error {
+	f := v.FieldByIndex(et.id)
+	if f.Kind() == reflect.String {
+		f.SetString(id)
+		return nil
+	}
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("ogm: node ID %q does not fit %s", id, f.Type())
+	}
+	f.SetInt(n)
+	return nil
+}
+
+// properties returns the property values of v, leaving out nils and
+// omitempty zeros, as the field values themselves.
+func (et *entityType) properties(v reflect.Value) map[string]interface{} {
+	props := make(map[string]interface{}, len(et.fields))
+	for _, pf := range et.fields {
+		f := v.FieldByIndex(pf.index)
+		if pf.omitEmpty && f.IsZero() {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			if f.IsNil() {
+				continue
+			}
+		}
+		props[pf.name] = f.Interface()
+	}
+	return props
+}
+
+// load assigns node properties to the fields of v. Properties without
+// a field are ignored and fields without a property are zeroed.
+func (et *entityType) load(v reflect.Value, props map[string]interface{}) error {
+	for _, pf := range et.fields {
+		if err := assign(v.FieldByIndex(pf.index), props[pf.name]); err != nil {
+			return fmt.Errorf("ogm: %s.%s: %w", et.typ.Name(), pf.name, err)
+		}
+	}
+	return nil
+}
+
+// assign stores a decoded property value in f: directly when the types
+// allow it, through JSON otherwise (slices, maps, structs, time.Time).
+func assign(f reflect.Value, raw interface{}) error {
+	if raw == nil {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+	rv := reflect.ValueOf(raw)
+	if rv.Type().AssignableTo(f.Type()) {
+		f.Set(rv)
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	target := reflect.New(f.Type())
+	if err := json.Unmarshal(data, target.Interface()); err != nil {
+		return fmt.Errorf("cannot store %T in %s", raw, f.Type())
+	}
+	f.Set(target.Elem())
+	return nil
+}
+
+// normalize converts property values to their JSON form, with numbers
+// as json.Number, so values read from the server and values held in
+// fields compare equal when they encode the same.
+func normalize(props map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(props)
+	if err != nil {
+		return nil, fmt.Errorf("ogm: encode properties: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	out := map[string]interface{}{}
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("ogm: encode properties: %w", err)
+	}
+	for k, v := range out {
+		if v == nil {
+			delete(out, k)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,71 @@
+// Package ogm maps Go structs onto Nexus nodes.
+//
+// A Session is a unit of work: entities loaded through it are tracked,
+// and Flush writes every change made since (new entities, changed or
+// removed properties, deletions) in one transaction, sending only the
+// properties that actually changed:
+//
+//	type Person struct {
+//	    ID   string `nexus:",id"`
+//	    Name string `nexus:"name"`
+//	    Age  int    `nexus:"age,omitempty"`
+//	}
+//
+//	s := ogm.NewSession(client, ogm.Options{})
+//	var ann Person
+//	if err := s.Load(ctx, &ann, id); err != nil { … }
+//	ann.Age++                                     // tracked, nothing sent yet
+//	_ = s.Save(&Person{Name: "Bob"})              // queued for creation
+//	err := s.Flush(ctx)                           // one transaction
+//
+// Exported fields are properties named by their tag, or by the field
+// name when untagged; `nexus:"-"` skips a field and omitempty leaves
+// zero values unset. The field tagged `nexus:",id"` (or else named ID)
+// holds the node ID.
+//
+// Fields of type *T or []*T tagged `nexus:"TYPE,rel"` hold the entities
+// related through TYPE, outgoing unless the "in" flag is given. They
+// are loaded with their owner, so loading an aggregate root loads the
+// aggregate, up to Options.MaxDepth hops. The "save", "delete" and
+// "orphans" flags cascade writes along the relationship (see Cascade);
+// without them deleting an owner only detaches its related entities:
+//
+//	type Order struct {
+//	    ID       string    `nexus:",id"`
+//	    Lines    []*Line   `nexus:"HAS_LINE,rel,save,delete,orphans"`
+//	    Customer *Customer `nexus:"PLACED,rel,in"`
+//	}
+package ogm
+
+import (
+	"context"
+	"errors"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// Reader is the subset of *nexus.Client that Query needs. Transactions
+// and retrying clients satisfy it too.
+type Reader interface {
+	ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error)
+}
+
+// Querier is the subset of *nexus.Client a Session needs: queries for
+// loading and RunTransaction for flushing.
+type Querier interface {
+	Reader
+	RunTransaction(ctx context.Context, statements []nexus.Statement) ([]*nexus.QueryResult, error)
+}
+
+// ErrNotFound is returned by Load when no node has the ID, or the node
+// has a different label or is hidden by a row filter.
+var ErrNotFound = errors.New("ogm: entity not found")
+
+// ErrAlreadyLoaded is returned when a node the session already holds
+// would be loaded or saved as a second object.
+var ErrAlreadyLoaded = errors.New("ogm: entity already loaded as another object")
+
+// cypherName quotes a label or property name when it is not a plain
+// identifier.
+func cypherName(name string) string { return cypherlex.QuoteName(name) }
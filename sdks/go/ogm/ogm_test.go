@@ -0,0 +1,495 @@
+package ogm
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Person struct {
+	ID    string   `nexus:",id"`
+	Name  string   `nexus:"name"`
+	Age   int      `nexus:"age,omitempty"`
+	Tags  []string `nexus:"tags"`
+	Notes string   `nexus:"-"`
+}
+
+type Account struct {
+	ID    int64
+	Owner string `nexus:"owner,omitempty"`
+}
+
+func (Account) NodeLabel() string { return "BankAccount" }
+
+// fakeQuerier answers loads from node and relationship tables and
+// records flushed statements.
+type fakeQuerier struct {
+	nodes   map[int64]map[string]interface{}
+	labels  map[int64]string      // when set, Find only matches the entity's label
+	rels    map[string][][2]int64 // type → (from, to)
+	queries []string
+	params  []map[string]interface{}
+	flushed [][]nexus.Statement
+	nextID  int64
+}
+
+func (f *fakeQuerier) ExecuteCypher(_ context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error) {
+	f.queries = append(f.queries, query)
+	f.params = append(f.params, params)
+	result := &nexus.QueryResult{Columns: []string{"id", "props"}}
+	if m := relPattern.FindStringSubmatch(query); m != nil {
+		result.Columns = []string{"owner", "id", "props"}
+		for _, id := range params["ogm_ids"].([]int64) {
+			for _, edge := range f.rels[m[2]] {
+				switch {
+				case m[1] == "" && edge[0] == id:
+					result.Rows = append(result.Rows, []interface{}{id, edge[1], f.nodes[edge[1]]})
+				case m[1] == "<" && edge[1] == id:
+					result.Rows = append(result.Rows, []interface{}{id, edge[0], f.nodes[edge[0]]})
+				}
+			}
+		}
+		return result, nil
+	}
+	if id, ok := params["ogm_id"].(int64); ok {
+		if props, ok := f.nodes[id]; ok {
+			result.Rows = append(result.Rows, []interface{}{id, props})
+		}
+		return result, nil
+	}
+	for id := int64(1); id <= int64(len(f.nodes)); id++ {
+		if f.labels != nil && !strings.Contains(query, "(n:"+f.labels[id]+")") {
+			continue
+		}
+		result.Rows = append(result.Rows, []interface{}{id, f.nodes[id]})
+	}
+	return result, nil
+}
+
+var (
+	relPattern  = regexp.MustCompile(`\(ogm_p\)(<?)-\[:(\w+)\]`)
+	returnedIDs = regexp.MustCompile(`id\(n\d+\) AS (n\d+)`)
+)
+
+func (f *fakeQuerier) RunTransaction(_ context.Context, statements []nexus.Statement) ([]*nexus.QueryResult, error) {
+	// Round-trip through JSON as the HTTP transport would.
+	data, err := json.Marshal(statements)
+	if err != nil {
+		return nil, err
+	}
+	var sent []nexus.Statement
+	if err := json.Unmarshal(data, &sent); err != nil {
+		return nil, err
+	}
+	f.flushed = append(f.flushed, sent)
+	results := make([]*nexus.QueryResult, len(statements))
+	for i, st := range statements {
+		results[i] = &nexus.QueryResult{}
+		if created := returnedIDs.FindAllStringSubmatch(st.Query, -1); created != nil {
+			row := []interface{}{}
+			for _, m := range created {
+				f.nextID++
+				results[i].Columns = append(results[i].Columns, m[1])
+				row = append(row, float64(100+f.nextID)) // as decoded from JSON
+			}
+			results[i].Rows = [][]interface{}{row}
+		}
+	}
+	return results, nil
+}
+
+func TestSessionFlushWritesOnlyChanges(t *testing.T) {
+	q := &fakeQuerier{nodes: map[int64]map[string]interface{}{
+		1: {"name": "Ann", "age": int64(41), "tags": []interface{}{"a"}},
+		2: {"name": "Bob", "age": int64(30)},
+	}}
+	ctx := context.Background()
+	s := NewSession(q, Options{RowFilters: []nexus.RowFilter{nexus.PropertyFilter("tenant", "t1")}})
+
+	var ann Person
+	require.NoError(t, s.Load(ctx, &ann, "1"))
+	assert.Equal(t, Person{ID: "1", Name: "Ann", Age: 41, Tags: []string{"a"}}, ann)
+	assert.Contains(t, q.queries[0], "MATCH (n:Person) WHERE (id(n) = $ogm_id) AND n.tenant = $rls_tenant")
+	assert.Equal(t, "t1", q.params[0]["rls_tenant"])
+	assert.ErrorIs(t, s.Load(ctx, &Person{}, "9"), ErrNotFound)
+
+	var people []*Person
+	require.NoError(t, s.Find(ctx, &people, "", nil))
+	require.Len(t, people, 2)
+	bob := people[1]
+
+	// Nothing changed yet: flushing sends nothing.
+	require.NoError(t, s.Flush(ctx))
+	assert.Empty(t, q.flushed)
+
+	ann.Notes = "not mapped"
+	bob.Age = 0 // omitempty: the property is removed
+	cid := &Person{Name: "Cid"}
+	require.NoError(t, s.Save(cid))
+	require.NoError(t, s.Delete(&ann))
+
+	set, removed, err := s.Changes(bob)
+	require.NoError(t, err)
+	assert.Empty(t, set)
+	assert.Equal(t, []string{"age"}, removed)
+
+	require.NoError(t, s.Flush(ctx))
+	require.Len(t, q.flushed, 1)
+	assert.Equal(t, []nexus.Statement{
+		{Query: "CREATE (n0:Person) SET n0 = $n0 RETURN id(n0) AS n0", Parameters: map[string]interface{}{"n0": map[string]interface{}{"name": "Cid"}}},
+		{
+			Query:      "MATCH (n:Person) WHERE (id(n) = $id) AND n.tenant = $rls_tenant REMOVE n.age",
+			Parameters: map[string]interface{}{"id": float64(2), "rls_tenant": "t1"},
+		},
+		{
+			Query:      "MATCH (n:Person) WHERE (id(n) IN $ids) AND n.tenant = $rls_tenant DETACH DELETE n",
+			Parameters: map[string]interface{}{"ids": []interface{}{float64(1)}, "rls_tenant": "t1"},
+		},
+	}, q.flushed[0])
+	assert.Equal(t, "101", cid.ID)
+
+	// Deleted entities are no longer tracked.
+	_, _, err = s.Changes(&ann)
+	assert.Error(t, err)
+
+	require.NoError(t, s.Flush(ctx))
+	assert.Len(t, q.flushed, 1, "a second flush has nothing to write")
+
+	cid.Tags = []string{"x", "y"}
+	require.NoError(t, s.Flush(ctx))
+	assert.Equal(t, []nexus.Statement{
+		{
+			Query:      "MATCH (n:Person) WHERE (id(n) = $id) AND n.tenant = $rls_tenant SET n += $set",
+			Parameters: map[string]interface{}{"id": float64(101), "rls_tenant": "t1", "set": map[string]interface{}{"tags": []interface{}{"x", "y"}}},
+		},
+	}, q.flushed[1])
+}
+
+func TestSessionLargeCreatedIDs(t *testing.T) {
+	q := &fakeQuerier{nextID: 1234466}
+	ctx := context.Background()
+	s := NewSession(q, Options{})
+
+	p := &Person{Name: "Ann"}
+	require.NoError(t, s.Save(p))
+	require.NoError(t, s.Flush(ctx))
+	assert.Equal(t, "1234567", p.ID)
+
+	p.Name = "Anne"
+	require.NoError(t, s.Flush(ctx))
+	require.Len(t, q.flushed, 2)
+	assert.Equal(t, float64(1234567), q.flushed[1][0].Parameters["id"])
+}
+
+func TestSessionLabelsIDsAndSchema(t *testing.T) {
+	q := &fakeQuerier{}
+	ctx := context.Background()
+	schema := nexus.NewSchema()
+	require.NoError(t, schema.Define("BankAccount", nexus.LabelSchema{
+		Properties: map[string]nexus.PropertyRule{"owner": {Type: nexus.TypeString, Required: true}},
+	}))
+	s := NewSession(q, Options{Schema: schema})
+
+	acct := &Account{}
+	require.NoError(t, s.Save(acct))
+	var verr *nexus.ValidationError
+	assert.ErrorAs(t, s.Flush(ctx), &verr)
+	assert.Empty(t, q.flushed)
+
+	acct.Owner = "Ann"
+	require.NoError(t, s.Flush(ctx))
+	assert.Equal(t, "CREATE (n0:BankAccount) SET n0 = $n0 RETURN id(n0) AS n0", q.flushed[0][0].Query)
+	assert.Equal(t, int64(101), acct.ID)
+
+	assert.ErrorContains(t, s.Save(Person{}), "pointer to a struct")
+	var wrong []Person
+	assert.ErrorContains(t, s.Find(ctx, &wrong, "", nil), "slice of struct pointers")
+	type NoID struct{ Name string }
+	assert.ErrorContains(t, s.Save(&NoID{}), "has no ID field")
+}
+
+func TestSessionIdentityMap(t *testing.T) {
+	q := &fakeQuerier{nodes: map[int64]map[string]interface{}{
+		1: {"name": "Ann"},
+		2: {"name": "Bob"},
+	}}
+	ctx := context.Background()
+	s := NewSession(q, Options{})
+
+	ann, err := Get[Person](ctx, s, "1")
+	require.NoError(t, err)
+	ann.Name = "Annie"
+	again, err := Get[Person](ctx, s, "1")
+	require.NoError(t, err)
+	assert.Same(t, ann, again)
+	assert.Len(t, q.queries, 1, "the second Get is served from the session")
+
+	var people []*Person
+	require.NoError(t, s.Find(ctx, &people, "", nil))
+	assert.Same(t, ann, people[0])
+	assert.Equal(t, "Annie", people[0].Name, "pending changes are kept")
+	require.NoError(t, s.Load(ctx, people[1], "2"))
+	assert.Len(t, q.queries, 2)
+
+	assert.ErrorIs(t, s.Load(ctx, &Person{}, "1"), ErrAlreadyLoaded)
+	assert.ErrorIs(t, s.Save(&Person{ID: "2"}), ErrAlreadyLoaded)
+
+	created := &Person{Name: "Cid"}
+	require.NoError(t, s.Save(created))
+	require.NoError(t, s.Flush(ctx))
+	got, err := Get[Person](ctx, s, created.ID)
+	require.NoError(t, err)
+	assert.Same(t, created, got)
+
+	s.Clear()
+	fresh, err := Get[Person](ctx, s, "1")
+	require.NoError(t, err)
+	assert.NotSame(t, ann, fresh)
+	assert.Equal(t, "Ann", fresh.Name)
+}
+
+type Order struct {
+	ID       string    `nexus:",id"`
+	Number   string    `nexus:"number"`
+	Lines    []*Line   `nexus:"HAS_LINE,rel,save,delete,orphans"`
+	Customer *Customer `nexus:"PLACED,rel,in"`
+}
+
+type Line struct {
+	ID  string `nexus:",id"`
+	SKU string `nexus:"sku"`
+}
+
+type Customer struct {
+	ID   string `nexus:",id"`
+	Name string `nexus:"name"`
+}
+
+func TestSessionCascades(t *testing.T) {
+	q := &fakeQuerier{
+		nodes: map[int64]map[string]interface{}{
+			1: {"number": "A1"},
+			2: {"sku": "x"},
+			3: {"sku": "y"},
+			4: {"name": "Ann"},
+		},
+		rels: map[string][][2]int64{
+			"HAS_LINE": {{1, 2}, {1, 3}},
+			"PLACED":   {{4, 1}},
+		},
+	}
+	ctx := context.Background()
+	s := NewSession(q, Options{})
+
+	order, err := Get[Order](ctx, s, "1")
+	require.NoError(t, err)
+	require.Len(t, order.Lines, 2)
+	assert.Equal(t, "y", order.Lines[1].SKU)
+	require.NotNil(t, order.Customer)
+	assert.Equal(t, "Ann", order.Customer.Name)
+
+	// Dropping a line deletes it (orphans); a new line is saved with the
+	// order and linked in the same statement that creates it.
+	order.Lines = []*Line{order.Lines[0], {SKU: "z"}}
+	require.NoError(t, s.Flush(ctx))
+	assert.Equal(t, []nexus.Statement{
+		{
+			Query:      "MATCH (e0:Order) WHERE id(e0) = $e0 CREATE (n0:Line) SET n0 = $n0 CREATE (e0)-[:HAS_LINE]->(n0) RETURN id(n0) AS n0",
+			Parameters: map[string]interface{}{"e0": float64(1), "n0": map[string]interface{}{"sku": "z"}},
+		},
+		{Query: "MATCH (n:Line) WHERE id(n) IN $ids DETACH DELETE n", Parameters: map[string]interface{}{"ids": []interface{}{float64(3)}}},
+	}, q.flushed[0])
+	assert.Equal(t, "101", order.Lines[1].ID)
+
+	// A relationship without cascades only links: switching customers
+	// rewires PLACED and leaves both customers alone.
+	bob := &Customer{ID: "5", Name: "Bob"}
+	order.Customer = bob
+	require.NoError(t, s.Flush(ctx))
+	assert.Equal(t, []nexus.Statement{
+		{Query: "MATCH (a:Customer), (b:Order) WHERE id(a) = $from AND id(b) = $to CREATE (a)-[:PLACED]->(b)", Parameters: map[string]interface{}{"from": float64(5), "to": float64(1)}},
+		{Query: "MATCH (a:Customer)-[r:PLACED]->(b:Order) WHERE id(a) = $from AND id(b) = $to DELETE r", Parameters: map[string]interface{}{"from": float64(4), "to": float64(1)}},
+	}, q.flushed[1])
+
+	order.Customer = &Customer{Name: "Unsaved"}
+	assert.ErrorContains(t, s.Flush(ctx), "holds an unsaved *ogm.Customer")
+	order.Customer = bob
+
+	// Deleting the root deletes its lines but only detaches the customer.
+	require.NoError(t, s.Delete(order))
+	require.NoError(t, s.Flush(ctx))
+	assert.Equal(t, []nexus.Statement{
+		{Query: "MATCH (n:Order) WHERE id(n) IN $ids DETACH DELETE n", Parameters: map[string]interface{}{"ids": []interface{}{float64(1)}}},
+		{Query: "MATCH (n:Line) WHERE id(n) IN $ids DETACH DELETE n", Parameters: map[string]interface{}{"ids": []interface{}{float64(2), float64(101)}}},
+	}, q.flushed[2])
+
+	// A new aggregate is created, linked and attached to an existing
+	// node in one statement.
+	fresh := &Order{Number: "B2", Lines: []*Line{{SKU: "q"}}, Customer: bob}
+	require.NoError(t, s.Save(fresh))
+	require.NoError(t, s.Flush(ctx))
+	assert.Equal(t, "MATCH (e0:Customer) WHERE id(e0) = $e0 "+
+		"CREATE (n0:Order) SET n0 = $n0 CREATE (n1:Line) SET n1 = $n1 "+
+		"CREATE (n0)-[:HAS_LINE]->(n1) CREATE (e0)-[:PLACED]->(n0) "+
+		"RETURN id(n0) AS n0, id(n1) AS n1", q.flushed[3][0].Query)
+	assert.Equal(t, "102", fresh.ID)
+	assert.Equal(t, "103", fresh.Lines[0].ID)
+}
+
+type Employee struct {
+	ID      string    `nexus:",id"`
+	Name    string    `nexus:"name"`
+	Manager *Employee `nexus:"MANAGES,rel,in"`
+}
+
+func TestSessionBatchesRelatedLoads(t *testing.T) {
+	q := &fakeQuerier{
+		nodes: map[int64]map[string]interface{}{
+			1: {"number": "A1"},
+			2: {"number": "A2"},
+			3: {"sku": "x"},
+			4: {"sku": "y"},
+			5: {"sku": "z"},
+		},
+		labels: map[int64]string{1: "Order", 2: "Order", 3: "Line", 4: "Line", 5: "Line"},
+		rels:   map[string][][2]int64{"HAS_LINE": {{1, 3}, {1, 4}, {2, 5}}},
+	}
+	ctx := context.Background()
+	s := NewSession(q, Options{RowFilters: []nexus.RowFilter{nexus.PropertyFilter("tenant", "acme", "Line")}})
+
+	var orders []*Order
+	require.NoError(t, s.Find(ctx, &orders, "", nil))
+	require.Len(t, orders, 2)
+	assert.Len(t, orders[0].Lines, 2)
+	require.Len(t, orders[1].Lines, 1)
+	assert.Equal(t, "z", orders[1].Lines[0].SKU)
+	// One query for the orders and one per relationship field, not per
+	// order.
+	require.Len(t, q.queries, 3)
+	assert.Equal(t, "UNWIND $ogm_ids AS ogm_id MATCH (ogm_p)-[:HAS_LINE]->(n:Line) WHERE (id(ogm_p) = ogm_id) AND n.tenant = $rls_tenant "+
+		"RETURN id(ogm_p) AS owner, id(n) AS id, properties(n) AS props", q.queries[1])
+	assert.Equal(t, []int64{1, 2}, q.params[1]["ogm_ids"])
+}
+
+func TestSessionCapsLoadDepth(t *testing.T) {
+	q := &fakeQuerier{
+		nodes: map[int64]map[string]interface{}{
+			1: {"name": "Ann"},
+			2: {"name": "Bob"},
+			3: {"name": "Cid"},
+			4: {"name": "Dee"},
+		},
+		rels: map[string][][2]int64{"MANAGES": {{2, 1}, {3, 2}, {4, 3}}},
+	}
+	ctx := context.Background()
+	s := NewSession(q, Options{MaxDepth: 2})
+
+	ann, err := Get[Employee](ctx, s, "1")
+	require.NoError(t, err)
+	require.NotNil(t, ann.Manager)
+	require.NotNil(t, ann.Manager.Manager)
+	assert.Equal(t, "Cid", ann.Manager.Manager.Name)
+	assert.Nil(t, ann.Manager.Manager.Manager, "Cid is past MaxDepth")
+	assert.Len(t, q.queries, 3)
+
+	// Nothing is unlinked for the unloaded field.
+	require.NoError(t, s.Flush(ctx))
+	assert.Empty(t, q.flushed)
+
+	s = NewSession(q, Options{MaxDepth: -1})
+	ann, err = Get[Employee](ctx, s, "1")
+	require.NoError(t, err)
+	assert.Nil(t, ann.Manager)
+}
+
+func TestSessionScopesWritesToRowFilters(t *testing.T) {
+	q := &fakeQuerier{nodes: map[int64]map[string]interface{}{1: {"number": "A1"}}}
+	ctx := context.Background()
+	s := NewSession(q, Options{RowFilters: []nexus.RowFilter{nexus.PropertyFilter("tenant", "t1", "Order")}})
+
+	order, err := Get[Order](ctx, s, "1")
+	require.NoError(t, err)
+
+	// Both the existing order the new line hangs off and a customer
+	// linked by ID alone are matched with their label; the filter only
+	// applies to the Order.
+	order.Lines = []*Line{{SKU: "z"}}
+	order.Customer = &Customer{ID: "7"}
+	require.NoError(t, s.Flush(ctx))
+	require.Len(t, q.flushed, 1)
+	assert.Equal(t, []nexus.Statement{
+		{
+			Query: "MATCH (e0:Order) WHERE (id(e0) = $e0) AND e0.tenant = $rls_tenant " +
+				"CREATE (n0:Line) SET n0 = $n0 CREATE (e0)-[:HAS_LINE]->(n0) RETURN id(n0) AS n0",
+			Parameters: map[string]interface{}{"e0": float64(1), "n0": map[string]interface{}{"sku": "z"}, "rls_tenant": "t1"},
+		},
+		{
+			Query:      "MATCH (a:Customer), (b:Order) WHERE (id(a) = $from AND id(b) = $to) AND b.tenant = $rls_tenant CREATE (a)-[:PLACED]->(b)",
+			Parameters: map[string]interface{}{"from": float64(7), "to": float64(1), "rls_tenant": "t1"},
+		},
+	}, q.flushed[0])
+
+	require.NoError(t, s.Delete(order))
+	require.NoError(t, s.Flush(ctx))
+	assert.Equal(t, "MATCH (n:Order) WHERE (id(n) IN $ids) AND n.tenant = $rls_tenant DETACH DELETE n", q.flushed[1][0].Query)
+}
+
+type readerFunc func(query string) *nexus.QueryResult
+
+func (f readerFunc) ExecuteCypher(_ context.Context, query string, _ map[string]interface{}) (*nexus.QueryResult, error) {
+	return f(query), nil
+}
+
+func TestQueryProjectsIntoDTOs(t *testing.T) {
+	type Address struct {
+		Street string `json:"street"`
+	}
+	type CityStats struct {
+		City    string `nexus:"city"`
+		People  int
+		Average float64 `nexus:"avg_age"`
+		Oldest  *Person `nexus:"oldest"`
+		Mayor   Person  `nexus:"mayor"`
+		Address Address `nexus:"address"`
+		Skipped string  `nexus:"-"`
+	}
+	q := readerFunc(func(string) *nexus.QueryResult {
+		return &nexus.QueryResult{
+			Columns: []string{"city", "PEOPLE", "avg_age", "oldest", "mayor", "address", "extra"},
+			Rows: [][]interface{}{{
+				"Oslo", int64(3), 41.5,
+				map[string]interface{}{"id": int64(7), "labels": []interface{}{"Person"}, "properties": map[string]interface{}{"name": "Ann", "age": int64(90)}},
+				map[string]interface{}{"_nexus_id": int64(8), "name": "Bob"},
+				map[string]interface{}{"street": "Main"},
+				"ignored",
+			}},
+		}
+	})
+
+	stats, err := Query[CityStats](context.Background(), q, "…", nil)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, CityStats{
+		City: "Oslo", People: 3, Average: 41.5,
+		Oldest:  &Person{ID: "7", Name: "Ann", Age: 90},
+		Mayor:   Person{ID: "8", Name: "Bob"},
+		Address: Address{Street: "Main"},
+	}, stats[0])
+
+	names, err := Query[string](context.Background(), readerFunc(func(string) *nexus.QueryResult {
+		return &nexus.QueryResult{Columns: []string{"name"}, Rows: [][]interface{}{{"Ann"}, {"Bob"}}}
+	}), "…", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ann", "Bob"}, names)
+
+	_, err = Query[CityStats](context.Background(), readerFunc(func(string) *nexus.QueryResult {
+		return &nexus.QueryResult{Columns: []string{"people"}, Rows: [][]interface{}{{"many"}}}
+	}), "…", nil)
+	assert.ErrorContains(t, err, `column "people"`)
+}
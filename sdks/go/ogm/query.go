@@ -0,0 +1,31 @@
+package ogm
+
+import "context"
+
+// Query runs a read query and maps each row onto a DTO, for read
+// models and reports that do not match any one label:
+//
+//	type CityStats struct {
+//	    City    string  `nexus:"city"`
+//	    People  int     `nexus:"people"`
+//	    Oldest  *Person `nexus:"oldest"` // a node column
+//	}
+//	stats, err := ogm.Query[CityStats](ctx, client,
+//	    "MATCH (p:Person) WITH p.city AS city, count(p) AS people, … RETURN city, people, oldest", nil)
+//
+// Rows are mapped by nexus.QueryResult.Scan: columns go to the field
+// tagged with their name, or else to the field whose name matches
+// ignoring case, and node columns fill mapped entities, ID included. A
+// DTO that is not a struct receives the first column, so Query[string]
+// lists names. Entities read this way are not tracked by any session.
+func Query[DTO any](ctx context.Context, q Reader, cypher string, params map[string]interface{}) ([]DTO, error) {
+	result, err := q.ExecuteCypher(ctx, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	var out []DTO
+	if err := result.Scan(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
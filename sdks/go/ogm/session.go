@@ -0,0 +1,435 @@
+package ogm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/hivellm/nexus-go/internal/ids"
+)
+
+// Options configures a Session.
+type Options struct {
+	// RowFilters constrain every query the session runs to load
+	// entities and every node a Flush matches, as with
+	// QueryBuilder.WithRowFilters, so a session can neither load nor
+	// write rows outside its tenant.
+	RowFilters []nexus.RowFilter
+	// Schema, when set, validates created and changed entities on
+	// Flush before anything is sent.
+	Schema *nexus.Schema
+	// MaxDepth caps the relationship hops followed when loading
+	// (default 3; negative loads no relationships). Entities at the cap
+	// are loaded with their relationship fields empty and untracked:
+	// Flush neither deletes their links nor cascades through them, and
+	// links set on them are created.
+	MaxDepth int
+}
+
+// defaultMaxDepth is Options.MaxDepth when unset.
+const defaultMaxDepth = 3
+
+// Session tracks the entities it loads or saves until Flush. It is also
+// an identity map: within a session one node is always the same Go
+// object, whichever query loaded it, and an entity already loaded is
+// not fetched again. It is not safe for concurrent use; use one session
+// per request or job.
+type Session struct {
+	q    Querier
+	opts Options
+
+	tracked  map[interface{}]*tracked    // entity pointer → state
+	order    []interface{}               // entity pointers, in tracking order
+	identity map[identityKey]interface{} // (type, node ID) → entity pointer
+}
+
+// identityKey identifies a node as a given struct type; one node may be
+// mapped by several types when it has several labels.
+type identityKey struct {
+	typ reflect.Type
+	id  string
+}
+
+// tracked is the state of one entity in the unit of work.
+type tracked struct {
+	entity interface{}
+	typ    *entityType
+	// snapshot holds the normalized properties last read from or
+	// written to the server; nil for entities not yet created.
+	snapshot map[string]interface{}
+	// links holds, per relationship field, the related entities last
+	// read from or written to the server; nil when the fields were not
+	// loaded (see Options.MaxDepth).
+	links   [][]interface{}
+	deleted bool
+}
+
+// NewSession starts a unit of work over q.
+func NewSession(q Querier, opts Options) *Session {
+	return &Session{q: q, opts: opts, tracked: map[interface{}]*tracked{}, identity: map[identityKey]interface{}{}}
+}
+
+// Get returns the entity of type T with the given ID: the object the
+// session already holds for it when there is one, without a query, or
+// else a newly loaded one.
+//
+//	ann, err := ogm.Get[Person](ctx, s, id)
+func Get[T any](ctx context.Context, s *Session, id string) (*T, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("ogm: Get needs a struct type, got %s", t)
+	}
+	if cached, ok := s.identity[identityKey{t, id}]; ok {
+		return cached.(*T), nil
+	}
+	entity := new(T)
+	if err := s.Load(ctx, entity, id); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Load reads the node with the given ID into entity, a pointer to a
+// mapped struct, and starts tracking it. The node must carry the
+// struct's label. Loading an entity the session already holds is a
+// no-op; loading its node into another object fails with
+// ErrAlreadyLoaded.
+func (s *Session) Load(ctx context.Context, entity interface{}, id string) error {
+	et, v, err := entityOf(entity)
+	if err != nil {
+		return err
+	}
+	if cached, ok := s.identity[identityKey{et.typ, id}]; ok {
+		if cached != entity {
+			return fmt.Errorf("%w: %s %s (use Get to share it)", ErrAlreadyLoaded, et.typ.Name(), id)
+		}
+		return nil
+	}
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrNotFound
+	}
+	rows, err := s.match(ctx, "(n:"+cypherName(et.label)+")", "id(n) = $ogm_id", map[string]interface{}{"ogm_id": n})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	if err := s.attach(entity, et, v, rows[0]); err != nil {
+		return err
+	}
+	return s.loadRelated(ctx, []interface{}{entity})
+}
+
+// Find loads every node with the entity's label matching where (a
+// condition on n, may be empty) into out, a pointer to a slice of
+// struct pointers, and tracks them. Nodes the session already holds
+// are returned as the existing objects, unchanged, with any pending
+// modifications:
+//
+//	var adults []*Person
+//	err := s.Find(ctx, &adults, "n.age >= $min", map[string]interface{}{"min": 18})
+func (s *Session) Find(ctx context.Context, out interface{}, where string, params map[string]interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice ||
+		slice.Elem().Type().Elem().Kind() != reflect.Ptr || slice.Elem().Type().Elem().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ogm: Find needs a pointer to a slice of struct pointers, got %T", out)
+	}
+	elem := slice.Elem().Type().Elem().Elem()
+	et, err := typeOf(elem)
+	if err != nil {
+		return err
+	}
+	rows, err := s.match(ctx, "(n:"+cypherName(et.label)+")", where, params)
+	if err != nil {
+		return err
+	}
+	entities, fresh, err := s.attachAll(et, rows)
+	if err != nil {
+		return err
+	}
+	if err := s.loadRelated(ctx, fresh); err != nil {
+		return err
+	}
+	result := reflect.MakeSlice(slice.Elem().Type(), 0, len(entities))
+	for _, e := range entities {
+		result = reflect.Append(result, reflect.ValueOf(e))
+	}
+	slice.Elem().Set(result)
+	return nil
+}
+
+// attachAll returns the entities for (id, properties) rows, reusing
+// the ones the session holds and attaching the others, which it also
+// returns as fresh.
+func (s *Session) attachAll(et *entityType, rows [][]interface{}) (out, fresh []interface{}, err error) {
+	out = make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if len(row) > 0 {
+			if cached, ok := s.identity[identityKey{et.typ, ids.Format(row[0])}]; ok {
+				out = append(out, cached)
+				continue
+			}
+		}
+		ptr := reflect.New(et.typ)
+		if err := s.attach(ptr.Interface(), et, ptr.Elem(), row); err != nil {
+			return nil, nil, err
+		}
+		out = append(out, ptr.Interface())
+		fresh = append(fresh, ptr.Interface())
+	}
+	return out, fresh, nil
+}
+
+// match runs MATCH pattern WHERE … with the session's row filters and
+// returns the (id, properties) rows of n.
+func (s *Session) match(ctx context.Context, pattern, where string, params map[string]interface{}) ([][]interface{}, error) {
+	query, params := s.scope(pattern, where, params)
+	result, err := s.q.ExecuteCypher(ctx, query+" RETURN id(n) AS id, properties(n) AS props", params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Rows, nil
+}
+
+// scope returns the clause MATCH pattern WHERE … constrained by the
+// session's row filters, with its parameters.
+func (s *Session) scope(pattern, where string, params map[string]interface{}) (string, map[string]interface{}) {
+	qb := nexus.NewQueryBuilder().
+		WithRowFilters(s.opts.RowFilters...).
+		Match(pattern)
+	if where != "" {
+		qb.Where(where)
+	}
+	return qb.WithParams(params).Build()
+}
+
+// attach fills entity from an (id, properties) row and tracks it. Its
+// relationship fields are left to loadRelated.
+func (s *Session) attach(entity interface{}, et *entityType, v reflect.Value, row []interface{}) error {
+	if len(row) < 2 {
+		return fmt.Errorf("ogm: unexpected row %v", row)
+	}
+	props, _ := row[1].(map[string]interface{})
+	if err := et.setID(v, ids.Format(row[0])); err != nil {
+		return err
+	}
+	if err := et.load(v, props); err != nil {
+		return err
+	}
+	snapshot, err := normalize(et.properties(v))
+	if err != nil {
+		return err
+	}
+	t := s.track(entity, et)
+	t.snapshot = snapshot
+	s.identity[identityKey{et.typ, et.getID(v)}] = entity
+	return nil
+}
+
+// loadRelated fills the relationship fields of freshly attached
+// entities, then of the entities that reached in turn, breadth first,
+// so loading an entity loads the aggregate it roots up to
+// Options.MaxDepth hops; the identity map stops cycles. Each hop costs
+// one query per entity type and relationship field, whatever the
+// number of entities.
+func (s *Session) loadRelated(ctx context.Context, level []interface{}) error {
+	depth := s.opts.MaxDepth
+	if depth == 0 {
+		depth = defaultMaxDepth
+	}
+	for hop := 1; hop <= depth && len(level) > 0; hop++ {
+		var (
+			types  []*entityType
+			owners = map[*entityType][]*tracked{}
+		)
+		for _, e := range level {
+			t := s.tracked[e]
+			if len(t.typ.rels) == 0 {
+				continue
+			}
+			if _, ok := owners[t.typ]; !ok {
+				types = append(types, t.typ)
+			}
+			owners[t.typ] = append(owners[t.typ], t)
+		}
+		var next []interface{}
+		for _, et := range types {
+			fresh, err := s.loadRelations(ctx, et, owners[et])
+			if err != nil {
+				return err
+			}
+			next = append(next, fresh...)
+		}
+		level = next
+	}
+	return nil
+}
+
+// loadRelations fills the relationship fields of owners, entities of
+// type et, and returns the related entities it attached.
+func (s *Session) loadRelations(ctx context.Context, et *entityType, owners []*tracked) ([]interface{}, error) {
+	nodeIDs := make([]int64, len(owners))
+	for i, t := range owners {
+		nodeIDs[i], _ = strconv.ParseInt(et.getID(reflect.ValueOf(t.entity).Elem()), 10, 64)
+		t.links = make([][]interface{}, len(et.rels))
+	}
+	var attached []interface{}
+	for i, rf := range et.rels {
+		target, err := typeOf(rf.target)
+		if err != nil {
+			return nil, err
+		}
+		pattern := "(ogm_p)-[:" + rf.name + "]->(n:" + cypherName(target.label) + ")"
+		if rf.incoming {
+			pattern = "(ogm_p)<-[:" + rf.name + "]-(n:" + cypherName(target.label) + ")"
+		}
+		query, params := nexus.NewQueryBuilder().
+			WithRowFilters(s.opts.RowFilters...).
+			Unwind("$ogm_ids", "ogm_id").
+			Match(pattern).
+			Where("id(ogm_p) = ogm_id").
+			WithParam("ogm_ids", nodeIDs).
+			Build()
+		result, err := s.q.ExecuteCypher(ctx, query+" RETURN id(ogm_p) AS owner, id(n) AS id, properties(n) AS props", params)
+		if err != nil {
+			return nil, err
+		}
+		rows := map[string][][]interface{}{} // owner ID → (id, properties) rows
+		for _, row := range result.Rows {
+			if len(row) > 0 {
+				owner := ids.Format(row[0])
+				rows[owner] = append(rows[owner], row[1:])
+			}
+		}
+		for _, t := range owners {
+			v := reflect.ValueOf(t.entity).Elem()
+			related, fresh, err := s.attachAll(target, rows[et.getID(v)])
+			if err != nil {
+				return nil, err
+			}
+			rf.setRelated(v, related)
+			t.links[i] = related
+			attached = append(attached, fresh...)
+		}
+	}
+	return attached, nil
+}
+
+func (s *Session) track(entity interface{}, et *entityType) *tracked {
+	t, ok := s.tracked[entity]
+	if !ok {
+		t = &tracked{entity: entity, typ: et}
+		s.tracked[entity] = t
+		s.order = append(s.order, entity)
+	}
+	return t
+}
+
+// Save starts tracking entity. One without an ID is created on the
+// next Flush; one with an ID that the session did not load is assumed
+// changed in full, so all its properties are written.
+func (s *Session) Save(entity interface{}) error {
+	et, v, err := entityOf(entity)
+	if err != nil {
+		return err
+	}
+	if id := et.getID(v); id != "" {
+		key := identityKey{et.typ, id}
+		if cached, ok := s.identity[key]; ok && cached != entity {
+			return fmt.Errorf("%w: %s %s", ErrAlreadyLoaded, et.typ.Name(), id)
+		}
+		s.identity[key] = entity
+	}
+	t := s.track(entity, et)
+	t.deleted = false
+	return nil
+}
+
+// Delete marks entity for deletion, with its relationships, on the
+// next Flush. Deleting an entity that was never created only stops
+// tracking it.
+func (s *Session) Delete(entity interface{}) error {
+	et, v, err := entityOf(entity)
+	if err != nil {
+		return err
+	}
+	if et.getID(v) == "" {
+		s.Detach(entity)
+		return nil
+	}
+	s.track(entity, et).deleted = true
+	return nil
+}
+
+// Detach stops tracking entity; changes made to it are no longer
+// flushed.
+func (s *Session) Detach(entity interface{}) {
+	if _, ok := s.tracked[entity]; !ok {
+		return
+	}
+	delete(s.tracked, entity)
+	for k, e := range s.identity {
+		if e == entity {
+			delete(s.identity, k)
+		}
+	}
+	for i, e := range s.order {
+		if e == entity {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Clear detaches every entity, emptying the identity map; later loads
+// fetch fresh objects.
+func (s *Session) Clear() {
+	s.tracked = map[interface{}]*tracked{}
+	s.identity = map[identityKey]interface{}{}
+	s.order = nil
+}
+
+// Changes lists the properties of entity that the next Flush would
+// write (set) and remove, sorted. A new entity has all of its
+// properties set.
+func (s *Session) Changes(entity interface{}) (set, removed []string, err error) {
+	t, ok := s.tracked[entity]
+	if !ok {
+		return nil, nil, fmt.Errorf("ogm: %T is not tracked by the session", entity)
+	}
+	changed, removed, err := t.diff()
+	if err != nil {
+		return nil, nil, err
+	}
+	for k := range changed {
+		set = append(set, k)
+	}
+	sort.Strings(set)
+	return set, removed, nil
+}
+
+// diff compares the entity with its snapshot.
+func (t *tracked) diff() (set map[string]interface{}, removed []string, err error) {
+	current, err := normalize(t.typ.properties(reflect.ValueOf(t.entity).Elem()))
+	if err != nil {
+		return nil, nil, err
+	}
+	set = map[string]interface{}{}
+	for k, v := range current {
+		if old, ok := t.snapshot[k]; !ok || !reflect.DeepEqual(old, v) {
+			set[k] = v
+		}
+	}
+	for k := range t.snapshot {
+		if _, ok := current[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(removed)
+	return set, removed, nil
+}
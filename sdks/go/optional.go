@@ -0,0 +1,187 @@
+package nexus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Optional holds a property or result cell that may be absent. Cypher
+// treats a missing property and a null one alike, so both decode to an
+// Optional with Valid false, distinct from a present zero value:
+//
+//	type Person struct {
+//		Name string                `json:"name"`
+//		Age  nexus.Optional[int64] `json:"age"`
+//	}
+//
+// Optional implements json.Marshaler and json.Unmarshaler (an invalid
+// Optional marshals as null) and ValueScanner for decoded result cells.
+type Optional[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Some returns a valid Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Valid: true}
+}
+
+// Get returns the value and whether it is present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Valid
+}
+
+// OrElse returns the value, or def when absent.
+func (o Optional[T]) OrElse(def T) T {
+	if !o.Valid {
+		return def
+	}
+	return o.Value
+}
+
+// String formats the value, or "null" when absent.
+func (o Optional[T]) String() string {
+	if !o.Valid {
+		return "null"
+	}
+	return fmt.Sprint(o.Value)
+}
+
+// MarshalJSON encodes the value, or null when absent.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON decodes a value; null leaves the Optional invalid.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+	o.Value, o.Valid = zero, false
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(data, &o.Value); err != nil {
+		return err
+	}
+	o.Valid = true
+	return nil
+}
+
+// ScanValue sets the Optional from a decoded result cell (see
+// ValueScanner); nil leaves it invalid.
+func (o *Optional[T]) ScanValue(v interface{}) error {
+	var zero T
+	o.Value, o.Valid = zero, false
+	if v == nil {
+		return nil
+	}
+	if err := convertValue(v, &o.Value); err != nil {
+		return err
+	}
+	o.Valid = true
+	return nil
+}
+
+// ValueScanner is implemented by types that decode themselves from a
+// result cell as returned in QueryResult.Rows (nil, bool, string,
+// float64 or int64 numbers, []interface{} or map[string]interface{}).
+type ValueScanner interface {
+	ScanValue(v interface{}) error
+}
+
+// Cell reads the named column of row i as an Optional[T]. A null cell
+// gives an invalid Optional; a missing column or row is an error.
+func Cell[T any](qr *QueryResult, i int, column string) (Optional[T], error) {
+	var out Optional[T]
+	if i < 0 || i >= len(qr.Rows) {
+		return out, fmt.Errorf("nexus: row %d out of range (%d rows)", i, len(qr.Rows))
+	}
+	for j, c := range qr.Columns {
+		if c != column {
+			continue
+		}
+		var cell interface{}
+		if j < len(qr.Rows[i]) {
+			cell = qr.Rows[i][j]
+		}
+		if err := out.ScanValue(cell); err != nil {
+			return out, fmt.Errorf("nexus: column %q: %w", column, err)
+		}
+		return out, nil
+	}
+	return out, fmt.Errorf("nexus: result has no column %q", column)
+}
+
+// convertValue stores the decoded cell v into dst, a non-nil pointer.
+// Numbers convert between numeric kinds as long as no precision is
+// lost; anything else that is not directly assignable goes through a
+// JSON round trip, so maps decode into structs and []interface{} into
+// typed slices.
+func convertValue(v interface{}, dst interface{}) error {
+	out := reflect.ValueOf(dst).Elem()
+	if s, ok := dst.(ValueScanner); ok {
+		return s.ScanValue(v)
+	}
+	in := reflect.ValueOf(v)
+	if in.Type().AssignableTo(out.Type()) {
+		out.Set(in)
+		return nil
+	}
+	if isNumericKind(in.Kind()) && isNumericKind(out.Kind()) {
+		return convertNumber(in, out)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("cannot decode %T into %s: %w", v, out.Type(), err)
+	}
+	return nil
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Uint64) || k == reflect.Float32 || k == reflect.Float64
+}
+
+func convertNumber(in, out reflect.Value) error {
+	var f float64
+	switch {
+	case in.CanInt():
+		f = float64(in.Int())
+	case in.CanUint():
+		f = float64(in.Uint())
+	default:
+		f = in.Float()
+	}
+	switch {
+	case out.CanInt():
+		if in.CanFloat() && f != math.Trunc(f) {
+			return fmt.Errorf("cannot store %v in %s without losing its fraction", f, out.Type())
+		}
+		n := int64(f)
+		if in.CanInt() {
+			n = in.Int()
+		}
+		if out.OverflowInt(n) {
+			return fmt.Errorf("%v overflows %s", n, out.Type())
+		}
+		out.SetInt(n)
+	case out.CanUint():
+		if f < 0 || f != math.Trunc(f) {
+			return fmt.Errorf("cannot store %v in %s", f, out.Type())
+		}
+		if out.OverflowUint(uint64(f)) {
+			return fmt.Errorf("%v overflows %s", f, out.Type())
+		}
+		out.SetUint(uint64(f))
+	default:
+		out.SetFloat(f)
+	}
+	return nil
+}
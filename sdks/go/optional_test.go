@@ -0,0 +1,67 @@
+package nexus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalJSON(t *testing.T) {
+	type person struct {
+		Name  Optional[string] `json:"name"`
+		Age   Optional[int64]  `json:"age"`
+		Score Optional[int64]  `json:"score"`
+	}
+	var p person
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Alice","age":null,"score":0}`), &p))
+	assert.Equal(t, Some("Alice"), p.Name)
+	assert.False(t, p.Age.Valid)
+	assert.Equal(t, Some(int64(0)), p.Score)
+	assert.Equal(t, int64(18), p.Age.OrElse(18))
+
+	out, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Alice","age":null,"score":0}`, string(out))
+}
+
+func TestCellScansOptional(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	qr := &QueryResult{
+		Columns: []string{"age", "ratio", "tags", "addr", "missing"},
+		Rows: [][]interface{}{
+			{float64(42), float64(0.5), []interface{}{"a", "b"}, map[string]interface{}{"city": "Lisbon"}, nil},
+			{float64(1.5), int64(2), nil, nil, nil},
+		},
+	}
+
+	age, err := Cell[int](qr, 0, "age")
+	require.NoError(t, err)
+	assert.Equal(t, Some(42), age)
+	ratio, err := Cell[float64](qr, 1, "ratio")
+	require.NoError(t, err)
+	assert.Equal(t, Some(2.0), ratio)
+	tags, err := Cell[[]string](qr, 0, "tags")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, tags.Value)
+	addr, err := Cell[address](qr, 0, "addr")
+	require.NoError(t, err)
+	assert.Equal(t, "Lisbon", addr.Value.City)
+
+	missing, err := Cell[string](qr, 0, "missing")
+	require.NoError(t, err)
+	assert.False(t, missing.Valid)
+	assert.Equal(t, "null", missing.String())
+
+	_, err = Cell[int](qr, 1, "age")
+	assert.ErrorContains(t, err, "fraction")
+	_, err = Cell[int8](qr, 0, "age")
+	assert.NoError(t, err)
+	_, err = Cell[int](qr, 0, "nope")
+	assert.ErrorContains(t, err, "no column")
+	_, err = Cell[int](qr, 5, "age")
+	assert.Error(t, err)
+}
@@ -0,0 +1,174 @@
+package nexus
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// Option configures a client built with NewClientWithOptions. An Option
+// edits the Config the client is built from, so any setting without a
+// helper below can be written inline:
+//
+//	nexus.NewClientWithOptions(url, func(c *nexus.Config) { c.RpcPort = 16000 })
+type Option func(*Config)
+
+// NewClientWithOptions creates a client for baseURL configured by opts.
+// It is NewClientE for callers that prefer options to filling a Config;
+// new settings arrive as new options without touching existing calls:
+//
+//	client, err := nexus.NewClientWithOptions("http://localhost:15474",
+//	    nexus.WithAPIKey(key),
+//	    nexus.WithTimeout(10*time.Second),
+//	    nexus.WithHeaders(http.Header{"X-Request-Source": {"billing"}}),
+//	)
+func NewClientWithOptions(baseURL string, opts ...Option) (*Client, error) {
+	config := Config{BaseURL: baseURL}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return NewClientE(config)
+}
+
+// WithTimeout sets Config.Timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Config) { c.Timeout = d }
+}
+
+// WithAPIKey sets Config.APIKey.
+func WithAPIKey(key string) Option {
+	return func(c *Config) { c.APIKey = key }
+}
+
+// WithBasicAuth sets Config.Username and Config.Password.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Config) { c.Username, c.Password = username, password }
+}
+
+// WithCredentials sets Config.Credentials.
+func WithCredentials(p CredentialProvider) Option {
+	return func(c *Config) { c.Credentials = p }
+}
+
+// WithHTTPClient sets Config.HTTPClient, e.g. to route requests through
+// a custom transport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Config) { c.HTTPClient = hc }
+}
+
+// WithHeaders adds headers sent with every HTTP request. It may be
+// given more than once; values accumulate.
+func WithHeaders(h http.Header) Option {
+	return func(c *Config) {
+		if c.Headers == nil {
+			c.Headers = http.Header{}
+		}
+		for k, values := range h {
+			for _, v := range values {
+				c.Headers.Add(k, v)
+			}
+		}
+	}
+}
+
+// WithTransport sets Config.Transport.
+func WithTransport(mode transport.Mode) Option {
+	return func(c *Config) { c.Transport = mode }
+}
+
+// WithSigner sets Config.Signer.
+func WithSigner(s RequestSigner) Option {
+	return func(c *Config) { c.Signer = s }
+}
+
+// WithPinnedCertFingerprints sets Config.PinnedCertFingerprints.
+func WithPinnedCertFingerprints(fingerprints ...string) Option {
+	return func(c *Config) { c.PinnedCertFingerprints = fingerprints }
+}
+
+// WithTLS sets Config.TLS.
+func WithTLS(t TLSConfig) Option {
+	return func(c *Config) { c.TLS = &t }
+}
+
+// WithSchema sets Config.Schema.
+func WithSchema(s *Schema) Option {
+	return func(c *Config) { c.Schema = s }
+}
+
+// WithQueryPolicy sets Config.QueryPolicy.
+func WithQueryPolicy(p QueryPolicy) Option {
+	return func(c *Config) { c.QueryPolicy = p }
+}
+
+// WithAuthorizer sets Config.Authorizer.
+func WithAuthorizer(a Authorizer) Option {
+	return func(c *Config) { c.Authorizer = a }
+}
+
+// WithQueryList sets Config.QueryList.
+func WithQueryList(l *QueryList) Option {
+	return func(c *Config) { c.QueryList = l }
+}
+
+// WithMetrics sets Config.Metrics.
+func WithMetrics(h MetricsHook) Option {
+	return func(c *Config) { c.Metrics = h }
+}
+
+// WithAdaptiveTimeout sets Config.AdaptiveTimeout.
+func WithAdaptiveTimeout(a *AdaptiveTimeout) Option {
+	return func(c *Config) { c.AdaptiveTimeout = a }
+}
+
+// WithRetry sets Config.Retry; nil means DefaultRetryConfig.
+func WithRetry(cfg *RetryConfig) Option {
+	return func(c *Config) {
+		if cfg == nil {
+			cfg = DefaultRetryConfig()
+		}
+		c.Retry = cfg
+	}
+}
+
+// WithCircuitBreaker sets Config.CircuitBreaker.
+func WithCircuitBreaker(cfg *CircuitBreakerConfig) Option {
+	return func(c *Config) { c.CircuitBreaker = cfg }
+}
+
+// WithStaleReads sets Config.StaleReads.
+func WithStaleReads(cfg *StaleReadConfig) Option {
+	return func(c *Config) { c.StaleReads = cfg }
+}
+
+// WithQuota sets Config.Quota.
+func WithQuota(cfg *QuotaConfig) Option {
+	return func(c *Config) { c.Quota = cfg }
+}
+
+// WithEscalateNotifications sets Config.EscalateNotifications.
+func WithEscalateNotifications(categories ...NotificationCategory) Option {
+	return func(c *Config) { c.EscalateNotifications = categories }
+}
+
+// headerRoundTripper adds fixed headers to requests lacking them.
+type headerRoundTripper struct {
+	headers http.Header
+	next    http.RoundTripper
+}
+
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, values := range h.headers {
+		k = http.CanonicalHeaderKey(k)
+		if _, set := req.Header[k]; !set {
+			req.Header[k] = append([]string(nil), values...)
+		}
+	}
+	next := h.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
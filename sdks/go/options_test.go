@@ -0,0 +1,57 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingTransport struct {
+	calls int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	defer server.Close()
+
+	rt := &countingTransport{}
+	client, err := NewClientWithOptions(server.URL,
+		WithAPIKey("secret"),
+		WithTimeout(5*time.Second),
+		WithHTTPClient(&http.Client{Transport: rt}),
+		WithHeaders(http.Header{"x-source": {"billing"}}),
+		WithHeaders(http.Header{"X-Source": {"etl"}, "X-Api-Key": {"ignored"}}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
+
+	require.NoError(t, client.Ping(context.Background()))
+	assert.Equal(t, 1, rt.calls)
+	assert.Equal(t, []string{"billing", "etl"}, got.Values("X-Source"))
+	assert.Equal(t, "secret", got.Get("X-API-Key"), "headers set by the client win")
+
+	client, err = NewClientWithOptions(server.URL, WithHTTPClient(&http.Client{Timeout: time.Second}))
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, client.httpClient.Timeout)
+	client.Close()
+
+	_, err = NewClientWithOptions("https://localhost:15474",
+		WithHTTPClient(&http.Client{Transport: rt}),
+		WithPinnedCertFingerprints("00"))
+	assert.ErrorContains(t, err, "cannot be combined")
+}
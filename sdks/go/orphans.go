@@ -0,0 +1,146 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OrphanSpec selects the nodes CleanupOrphans looks at.
+type OrphanSpec struct {
+	// Labels the nodes must all have; empty matches every node.
+	Labels []string
+	// RelTypes narrows what counts as a relationship: a node is an
+	// orphan when it has none of these types. Empty means none at all.
+	RelTypes []string
+	// Where is an extra predicate on n, e.g. "n.createdAt < $cutoff".
+	Where string
+	// Params are passed to every batch, for Where.
+	Params map[string]interface{}
+	// Delete deletes the orphans found; otherwise they are only
+	// reported.
+	Delete bool
+	// BatchSize is the number of nodes found or deleted per statement
+	// (default 1000).
+	BatchSize int
+	// OnProgress is called after every batch.
+	OnProgress func(OrphanProgress)
+}
+
+// OrphanProgress reports how far CleanupOrphans has got.
+type OrphanProgress struct {
+	Batches int
+	Found   int64
+	Deleted int64
+	// IDs are the orphans of the last batch.
+	IDs     []int64
+	Elapsed time.Duration
+}
+
+// OrphanReport is the outcome of CleanupOrphans.
+type OrphanReport struct {
+	Batches int
+	Found   int64
+	Deleted int64
+	// IDs are every orphan found (and, with Delete, deleted), in ID
+	// order.
+	IDs     []int64
+	Elapsed time.Duration
+}
+
+// CleanupOrphans finds nodes without relationships, BatchSize at a time
+// in ID order, and with spec.Delete deletes them:
+//
+//	report, err := client.CleanupOrphans(ctx, nexus.OrphanSpec{
+//	    Labels: []string{"Tag"},
+//	    Where:  "n.createdAt < $cutoff",
+//	    Params: map[string]interface{}{"cutoff": cutoff},
+//	    Delete: true,
+//	})
+//
+// Each batch is its own statement and re-checks that its nodes are
+// still orphans, so a node that gained a relationship meanwhile is left
+// alone. A failed batch stops the run with the earlier ones done and
+// counted in the returned report.
+func (c *Client) CleanupOrphans(ctx context.Context, spec OrphanSpec) (*OrphanReport, error) {
+	for _, l := range spec.Labels {
+		if err := validLabelIdentifier(l); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range spec.RelTypes {
+		if err := validLabelIdentifier(t); err != nil {
+			return nil, err
+		}
+	}
+	if spec.BatchSize <= 0 {
+		spec.BatchSize = 1000
+	}
+
+	var q strings.Builder
+	q.WriteString("MATCH (n")
+	for _, l := range spec.Labels {
+		q.WriteString(":" + l)
+	}
+	q.WriteString(") WHERE id(n) > $__after AND NOT (n)")
+	if len(spec.RelTypes) > 0 {
+		q.WriteString("-[:" + strings.Join(spec.RelTypes, "|") + "]-")
+	} else {
+		q.WriteString("--")
+	}
+	q.WriteString("()")
+	if where := strings.TrimSpace(spec.Where); where != "" {
+		q.WriteString(" AND (" + where + ")")
+	}
+	q.WriteString(" WITH n ORDER BY id(n) LIMIT $__batch")
+	if spec.Delete {
+		q.WriteString(" WITH n, id(n) AS id DETACH DELETE n RETURN id")
+	} else {
+		q.WriteString(" RETURN id(n) AS id")
+	}
+	query := q.String()
+
+	params := make(map[string]interface{}, len(spec.Params)+2)
+	for k, v := range spec.Params {
+		params[k] = v
+	}
+	params["__batch"] = spec.BatchSize
+
+	report := &OrphanReport{}
+	start := time.Now()
+	after := int64(-1)
+	for {
+		params["__after"] = after
+		result, err := c.ExecuteCypher(ctx, query, params)
+		if err != nil {
+			return report, fmt.Errorf("nexus: cleanup orphans batch %d: %w", report.Batches+1, err)
+		}
+		ids := make([]int64, 0, len(result.Rows))
+		for _, row := range result.Rows {
+			if len(row) > 0 {
+				ids = append(ids, int64(asInt(row[0])))
+			}
+		}
+		if len(ids) == 0 {
+			return report, nil
+		}
+		after = ids[len(ids)-1]
+		report.Batches++
+		report.Found += int64(len(ids))
+		if spec.Delete {
+			report.Deleted += int64(len(ids))
+		}
+		report.IDs = append(report.IDs, ids...)
+		report.Elapsed = time.Since(start)
+		if spec.OnProgress != nil {
+			spec.OnProgress(OrphanProgress{
+				Batches: report.Batches, Found: report.Found, Deleted: report.Deleted,
+				IDs: ids, Elapsed: report.Elapsed,
+			})
+		}
+		if len(ids) < spec.BatchSize {
+			return report, nil
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupOrphans(t *testing.T) {
+	orphans := []float64{3, 7, 8, 12, 20}
+	var queries []cypherRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req)
+		after := req.Parameters["__after"].(float64)
+		limit := int(req.Parameters["__batch"].(float64))
+		rows := [][]interface{}{}
+		for _, id := range orphans {
+			if id > after && len(rows) < limit {
+				rows = append(rows, []interface{}{id})
+			}
+		}
+		writeJSON(w, map[string]interface{}{"columns": []string{"id"}, "rows": rows})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	var progress []OrphanProgress
+	report, err := client.CleanupOrphans(ctx, OrphanSpec{
+		Labels:     []string{"Tag"},
+		Where:      "n.createdAt < $cutoff",
+		Params:     map[string]interface{}{"cutoff": 100},
+		BatchSize:  2,
+		OnProgress: func(p OrphanProgress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Batches)
+	assert.Equal(t, int64(5), report.Found)
+	assert.Zero(t, report.Deleted)
+	assert.Equal(t, []int64{3, 7, 8, 12, 20}, report.IDs)
+	require.Len(t, progress, 3)
+	assert.Equal(t, []int64{8, 12}, progress[1].IDs)
+	assert.Equal(t, int64(4), progress[1].Found)
+	assert.Equal(t, "MATCH (n:Tag) WHERE id(n) > $__after AND NOT (n)--() AND (n.createdAt < $cutoff) "+
+		"WITH n ORDER BY id(n) LIMIT $__batch RETURN id(n) AS id", queries[0].Query)
+	assert.Equal(t, float64(100), queries[0].Parameters["cutoff"])
+	assert.Equal(t, float64(7), queries[1].Parameters["__after"])
+
+	queries = nil
+	report, err = client.CleanupOrphans(ctx, OrphanSpec{RelTypes: []string{"TAGGED"}, Delete: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), report.Deleted)
+	require.Len(t, queries, 1)
+	assert.Equal(t, "MATCH (n) WHERE id(n) > $__after AND NOT (n)-[:TAGGED]-() "+
+		"WITH n ORDER BY id(n) LIMIT $__batch WITH n, id(n) AS id DETACH DELETE n RETURN id", queries[0].Query)
+
+	_, err = client.CleanupOrphans(ctx, OrphanSpec{Labels: []string{"Bad Label"}})
+	assert.Error(t, err)
+}
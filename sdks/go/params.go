@@ -0,0 +1,222 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ParamBindingError is returned by ExecuteCypherStruct when the query
+// references parameters the bound value does not provide.
+type ParamBindingError struct {
+	Missing []string
+}
+
+func (e *ParamBindingError) Error() string {
+	return "nexus: query parameter(s) not bound: $" + strings.Join(e.Missing, ", $")
+}
+
+// CodeUnusedParameter is the code of the client-side notification
+// ExecuteCypherStruct attaches for bound parameters the query never
+// references.
+const CodeUnusedParameter = "Nexus.Client.UnusedParameter"
+
+// BindParams converts params to the map form ExecuteCypher takes. A
+// map with string keys is copied as is; a struct (or pointer to one) is
+// read field by field:
+//
+//	type byEmail struct {
+//	    Email string `param:"email"`
+//	    Limit int    `param:"limit,omitempty"`
+//	    Note  string `param:"-"`
+//	}
+//
+// Fields without a `param` tag use their `json` tag name, then the field
+// name. `omitempty` drops zero values, `-` skips the field, and embedded
+// structs are flattened. Unexported fields are ignored.
+func BindParams(params interface{}) (map[string]interface{}, error) {
+	if params == nil {
+		return nil, nil
+	}
+	if m, ok := params.(map[string]interface{}); ok {
+		return m, nil
+	}
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("nexus: cannot bind parameters from %s: keys must be strings", v.Type())
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = iter.Value().Interface()
+		}
+		return out, nil
+	case reflect.Struct:
+		out := map[string]interface{}{}
+		bindStruct(v, out)
+		return out, nil
+	}
+	return nil, fmt.Errorf("nexus: cannot bind parameters from %s", v.Type())
+}
+
+func bindStruct(v reflect.Value, out map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts := paramTag(f)
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if f.Anonymous && name == "" {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				bindStruct(fv, out)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		out[name] = fv.Interface()
+	}
+}
+
+// paramTag returns the name and options of the `param` tag, falling
+// back to the `json` tag.
+func paramTag(f reflect.StructField) (name, opts string) {
+	tag, ok := f.Tag.Lookup("param")
+	if !ok {
+		tag = f.Tag.Get("json")
+	}
+	name, opts, _ = strings.Cut(tag, ",")
+	return name, opts
+}
+
+// QueryParameters lists the parameters a query references ($name or
+// $`name`), in order of first use. References inside string literals
+// and comments are ignored.
+func QueryParameters(query string) []string {
+	var out []string
+	seen := map[string]bool{}
+	scanParameters([]rune(query), func(_, _ int, name string) {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	})
+	return out
+}
+
+// scanParameters calls fn with the rune span and name of every
+// parameter reference in query outside string literals and comments.
+func scanParameters(runes []rune, fn func(start, end int, name string)) {
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case r == '/' && next == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && next == '*':
+			for i += 2; i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/'); i++ {
+			}
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && r != '`' {
+					i++
+				}
+			}
+		case r == '$' && next == '`':
+			end := i + 2
+			for end < len(runes) && runes[end] != '`' {
+				end++
+			}
+			if end < len(runes) && end > i+2 {
+				fn(i, end+1, string(runes[i+2:end]))
+			}
+			i = end
+		case r == '$':
+			end := i + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			if end > i+1 {
+				fn(i, end, string(runes[i+1:end]))
+			}
+			i = end - 1
+		}
+	}
+}
+
+// ExecuteCypherStruct is ExecuteCypher with parameters bound from a
+// struct (see BindParams). Before sending, it checks that every
+// parameter the query references is bound and fails with a
+// *ParamBindingError otherwise. Bound parameters the query does not use
+// are reported as UNRECOGNIZED notifications with code
+// CodeUnusedParameter on the result, so Config.EscalateNotifications can
+// turn them into errors.
+func (c *Client) ExecuteCypherStruct(ctx context.Context, query string, params interface{}) (*QueryResult, error) {
+	bound, err := BindParams(params)
+	if err != nil {
+		return nil, err
+	}
+	referenced := QueryParameters(query)
+	var missing []string
+	used := make(map[string]bool, len(referenced))
+	for _, name := range referenced {
+		used[name] = true
+		if _, ok := bound[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &ParamBindingError{Missing: missing}
+	}
+	var unused []Notification
+	for _, name := range sortedKeys(bound) {
+		if !used[name] {
+			unused = append(unused, Notification{
+				Code:        CodeUnusedParameter,
+				Title:       "Unused query parameter",
+				Description: fmt.Sprintf("Parameter $%s is bound but not referenced by the query.", name),
+				Severity:    "WARNING",
+				Category:    NotificationUnrecognized,
+			})
+		}
+	}
+
+	result, err := c.ExecuteCypher(ctx, query, bound)
+	if result != nil {
+		result.Notifications = append(result.Notifications, unused...)
+	}
+	var notifErr *NotificationError
+	if err != nil && !errors.As(err, &notifErr) {
+		return result, err
+	}
+	return result, c.checkNotifications(result)
+}
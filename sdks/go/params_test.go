@@ -0,0 +1,89 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageParams struct {
+	Limit int `param:"limit,omitempty"`
+	Skip  int `param:"skip"`
+}
+
+type personParams struct {
+	Email  string `param:"email"`
+	Name   string `json:"name"`
+	Active bool
+	Note   string `param:"-"`
+	secret string
+	pageParams
+}
+
+func TestBindParams(t *testing.T) {
+	params, err := BindParams(&personParams{Email: "a@b.c", Name: "Ann", Active: true, Note: "x", secret: "s", pageParams: pageParams{Skip: 5}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"email": "a@b.c", "name": "Ann", "Active": true, "skip": 5}, params)
+
+	params, err = BindParams(map[string]int{"n": 1})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"n": 1}, params)
+
+	params, err = BindParams(nil)
+	require.NoError(t, err)
+	assert.Nil(t, params)
+
+	_, err = BindParams(42)
+	assert.Error(t, err)
+}
+
+func TestQueryParameters(t *testing.T) {
+	query := "MATCH (n:Person {email: $email}) // $commented\n" +
+		"WHERE n.name = '$literal' AND n.tag = $`odd name` /* $block */ AND n.age > $min_age\n" +
+		"RETURN n, $email"
+	assert.Equal(t, []string{"email", "odd name", "min_age"}, QueryParameters(query))
+}
+
+func TestExecuteCypherStruct(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		sent = req.Parameters
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"n"}, "rows": [][]interface{}{}})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	params := personParams{Email: "a@b.c", Name: "Ann"}
+	result, err := client.ExecuteCypherStruct(ctx, "MATCH (n {email: $email, name: $name}) RETURN n", params)
+	require.NoError(t, err)
+	assert.Equal(t, "a@b.c", sent["email"])
+	require.Len(t, result.Notifications, 2)
+	assert.Equal(t, CodeUnusedParameter, result.Notifications[0].Code)
+	assert.Contains(t, result.Notifications[0].Description, "$Active")
+	assert.Contains(t, result.Notifications[1].Description, "$skip")
+
+	sent = nil
+	_, err = client.ExecuteCypherStruct(ctx, "MATCH (n {email: $email}) RETURN n LIMIT $limit", params)
+	var bindErr *ParamBindingError
+	require.True(t, errors.As(err, &bindErr))
+	assert.Equal(t, []string{"limit"}, bindErr.Missing)
+	assert.Nil(t, sent, "nothing is sent when a parameter is missing")
+
+	strict := NewClient(Config{BaseURL: server.URL, EscalateNotifications: []NotificationCategory{NotificationUnrecognized}})
+	result, err = strict.ExecuteCypherStruct(ctx, "MATCH (n {email: $email}) RETURN n", params)
+	var notifErr *NotificationError
+	require.True(t, errors.As(err, &notifErr))
+	assert.Len(t, notifErr.Notifications, 3)
+	assert.NotNil(t, result)
+}
@@ -0,0 +1,47 @@
+package nexus
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCertificatePinMismatch is returned (wrapped in the request error)
+// when no certificate presented by the server matches a pinned
+// fingerprint.
+var ErrCertificatePinMismatch = errors.New("nexus: server certificate does not match any pinned fingerprint")
+
+// PinnedCertVerifier returns a tls.Config.VerifyConnection hook that
+// accepts the connection only if a certificate in the server's chain has
+// one of the given SHA-256 fingerprints. Fingerprints are hex digests of
+// the DER certificate, case-insensitive, with or without colons (the
+// format of `openssl x509 -fingerprint -sha256`). Pinning an
+// intermediate CA survives leaf renewals.
+//
+// The hook runs after normal chain verification, so CA trust is still
+// required; pinning only narrows it.
+func PinnedCertVerifier(fingerprints []string) (func(tls.ConnectionState) error, error) {
+	if len(fingerprints) == 0 {
+		return nil, errors.New("nexus: no certificate fingerprints to pin")
+	}
+	pins := make(map[string]bool, len(fingerprints))
+	for _, fp := range fingerprints {
+		normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(fp), ":", ""))
+		if raw, err := hex.DecodeString(normalized); err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("nexus: invalid SHA-256 certificate fingerprint %q", fp)
+		}
+		pins[normalized] = true
+	}
+	return func(state tls.ConnectionState) error {
+		for _, cert := range state.PeerCertificates {
+			sum := sha256.Sum256(cert.Raw)
+			if pins[hex.EncodeToString(sum[:])] {
+				return nil
+			}
+		}
+		return ErrCertificatePinMismatch
+	}, nil
+}
@@ -0,0 +1,53 @@
+package nexus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedCertVerifier(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	hexFP := strings.ToUpper(hex.EncodeToString(sum[:]))
+	var colons []string
+	for i := 0; i < len(hexFP); i += 2 {
+		colons = append(colons, hexFP[i:i+2])
+	}
+
+	get := func(fingerprints ...string) error {
+		verify, err := PinnedCertVerifier(fingerprints)
+		require.NoError(t, err)
+		tr := server.Client().Transport.(*http.Transport).Clone()
+		tr.TLSClientConfig.VerifyConnection = verify
+		resp, err := (&http.Client{Transport: tr}).Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.NoError(t, get(strings.Join(colons, ":")))
+	assert.NoError(t, get(strings.Repeat("ab", 32), strings.ToLower(hexFP)))
+	assert.ErrorIs(t, get(strings.Repeat("ab", 32)), ErrCertificatePinMismatch)
+}
+
+func TestPinnedCertFingerprintsConfig(t *testing.T) {
+	_, err := PinnedCertVerifier([]string{"not-hex"})
+	assert.ErrorContains(t, err, "invalid SHA-256 certificate fingerprint")
+
+	_, err = NewClientE(Config{BaseURL: "http://localhost:15474", PinnedCertFingerprints: []string{strings.Repeat("ab", 32)}})
+	assert.ErrorContains(t, err, "requires an https:// endpoint")
+
+	client, err := NewClientE(Config{BaseURL: "https://nexus.example.com", PinnedCertFingerprints: []string{strings.Repeat("ab", 32)}})
+	require.NoError(t, err)
+	assert.Equal(t, "https", string(client.TransportMode()))
+}
@@ -0,0 +1,355 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// PlanNode is one operator of an EXPLAIN/PROFILE plan. Profile-only
+// counters are nil for plain EXPLAIN plans.
+type PlanNode struct {
+	Operator      string      `json:"operator"`
+	Details       string      `json:"details,omitempty"`
+	Identifiers   []string    `json:"identifiers,omitempty"`
+	EstimatedRows *float64    `json:"estimated_rows,omitempty"`
+	Rows          *int64      `json:"rows,omitempty"`
+	DbHits        *int64      `json:"db_hits,omitempty"`
+	TimeMs        *float64    `json:"time_ms,omitempty"`
+	Children      []*PlanNode `json:"children,omitempty"`
+}
+
+// QueryPlan is a decoded EXPLAIN or PROFILE result.
+type QueryPlan struct {
+	Root     *PlanNode `json:"root"`
+	Profiled bool      `json:"profiled"`
+	// ExecutionTimeMs and RowsReturned are only set for PROFILE.
+	ExecutionTimeMs float64 `json:"execution_time_ms,omitempty"`
+	RowsReturned    int64   `json:"rows_returned,omitempty"`
+}
+
+// ParseQueryPlan decodes the single `plan` (EXPLAIN) or `profile`
+// (PROFILE) cell the server returns for a prefixed query.
+//
+// Both plan shapes are accepted: a nested tree under `root` (operators
+// with `children`) and the flat pipeline under `operators`, listed from
+// the first operator to the last; the flat form becomes a chain rooted
+// at the last operator.
+func ParseQueryPlan(result *QueryResult) (*QueryPlan, error) {
+	if result == nil || len(result.Rows) == 0 || len(result.Columns) == 0 {
+		return nil, errors.New("nexus: result holds no plan")
+	}
+	col := -1
+	profiled := false
+	for i, c := range result.Columns {
+		if c == "plan" || c == "profile" {
+			col, profiled = i, c == "profile"
+			break
+		}
+	}
+	if col < 0 || col >= len(result.Rows[0]) {
+		return nil, fmt.Errorf("nexus: result has no plan column (columns %v)", result.Columns)
+	}
+	raw, ok := result.Rows[0][col].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("nexus: plan cell is %T, expected an object", result.Rows[0][col])
+	}
+
+	plan := &QueryPlan{
+		Profiled:        profiled,
+		ExecutionTimeMs: planFloat(raw["execution_time_ms"]),
+		RowsReturned:    int64(planFloat(raw["rows_returned"])),
+	}
+	body, _ := raw["plan"].(map[string]interface{})
+	if body == nil {
+		body = raw
+	}
+	if root, ok := body["root"].(map[string]interface{}); ok {
+		plan.Root = decodePlanNode(root)
+	} else if ops, ok := body["operators"].([]interface{}); ok {
+		for _, op := range ops {
+			m, ok := op.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			node := decodePlanNode(m)
+			if plan.Root != nil {
+				node.Children = append(node.Children, plan.Root)
+			}
+			plan.Root = node
+		}
+	}
+	if plan.Root == nil {
+		return nil, errors.New("nexus: plan has no operators")
+	}
+	return plan, nil
+}
+
+// ExplainCypher returns the plan the server would use for query without
+// running it.
+func (c *Client) ExplainCypher(ctx context.Context, query string, params map[string]interface{}) (*QueryPlan, error) {
+	return c.planCypher(ctx, "EXPLAIN", query, params)
+}
+
+// ProfileCypher runs query and returns its plan with the actual rows,
+// db hits and time of every operator. The query's writes, if any, are
+// applied.
+func (c *Client) ProfileCypher(ctx context.Context, query string, params map[string]interface{}) (*QueryPlan, error) {
+	return c.planCypher(ctx, "PROFILE", query, params)
+}
+
+// planCypher prefixes query with mode, replacing an EXPLAIN or PROFILE
+// prefix it already carries.
+func (c *Client) planCypher(ctx context.Context, mode, query string, params map[string]interface{}) (*QueryPlan, error) {
+	query = strings.TrimSpace(query)
+	for _, prefix := range []string{"EXPLAIN", "PROFILE"} {
+		if len(query) > len(prefix) && strings.EqualFold(query[:len(prefix)], prefix) && isSpace(rune(query[len(prefix)])) {
+			query = strings.TrimSpace(query[len(prefix):])
+		}
+	}
+	result, err := c.ExecuteCypher(ctx, mode+" "+query, params)
+	if err != nil {
+		return nil, err
+	}
+	return ParseQueryPlan(result)
+}
+
+// Operators returns every operator of the plan, parents before their
+// children.
+func (p *QueryPlan) Operators() []*PlanNode {
+	var ops []*PlanNode
+	var walk func(*PlanNode)
+	walk = func(n *PlanNode) {
+		ops = append(ops, n)
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	if p.Root != nil {
+		walk(p.Root)
+	}
+	return ops
+}
+
+// IndexOperators returns the operators that read through an index
+// (NodeIndexSeek, IndexScan, …). An empty result means the query scans
+// labels or the whole graph, which CI checks can assert against:
+//
+//	plan, err := client.ExplainCypher(ctx, "MATCH (p:Person {email: $e}) RETURN p", nil)
+//	if !plan.UsesIndex("Person", "email") { t.Error("lookup by email is not indexed") }
+func (p *QueryPlan) IndexOperators() []*PlanNode {
+	var ops []*PlanNode
+	for _, op := range p.Operators() {
+		if strings.Contains(strings.ToLower(op.Operator), "index") {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// UsesIndex reports whether an index operator's details mention label
+// and, when not empty, property.
+func (p *QueryPlan) UsesIndex(label, property string) bool {
+	for _, op := range p.IndexOperators() {
+		if strings.Contains(op.Details, label) && strings.Contains(op.Details, property) {
+			return true
+		}
+	}
+	return false
+}
+
+// TotalDbHits sums the db hits of every operator; zero for EXPLAIN
+// plans.
+func (p *QueryPlan) TotalDbHits() int64 {
+	var total int64
+	for _, op := range p.Operators() {
+		if op.DbHits != nil {
+			total += *op.DbHits
+		}
+	}
+	return total
+}
+
+func decodePlanNode(m map[string]interface{}) *PlanNode {
+	n := &PlanNode{
+		Operator: firstString(m, "operator", "type"),
+		Details:  firstString(m, "details", "description"),
+	}
+	if n.Details == n.Operator {
+		n.Details = ""
+	}
+	for _, id := range asSlice(m["identifiers"]) {
+		n.Identifiers = append(n.Identifiers, fmt.Sprint(id))
+	}
+	if v, ok := planNumber(m["estimated_rows"]); ok {
+		n.EstimatedRows = &v
+	}
+	if v, ok := planNumber(m["rows"]); ok {
+		rows := int64(v)
+		n.Rows = &rows
+	}
+	if v, ok := planNumber(m["db_hits"]); ok {
+		hits := int64(v)
+		n.DbHits = &hits
+	}
+	if v, ok := planNumber(m["time_ms"]); ok {
+		n.TimeMs = &v
+	}
+	for _, child := range asSlice(m["children"]) {
+		if cm, ok := child.(map[string]interface{}); ok {
+			n.Children = append(n.Children, decodePlanNode(cm))
+		}
+	}
+	return n
+}
+
+func firstString(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// planNumber reads a numeric plan field; the server reports unknown
+// estimates as the string "N/A", which is treated as absent.
+func planNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64, float32, int, int64:
+		return asFloat(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func planFloat(v interface{}) float64 {
+	f, _ := planNumber(v)
+	return f
+}
+
+// Render writes the plan as an aligned ASCII tree, one operator per
+// line with its details and counters in columns. Profile columns are
+// only printed when the plan carries them.
+func (p *QueryPlan) Render(w io.Writer) error {
+	var (
+		hasEst, hasRows, hasHits, hasTime bool
+		walk                              func(*PlanNode)
+	)
+	walk = func(n *PlanNode) {
+		hasEst = hasEst || n.EstimatedRows != nil
+		hasRows = hasRows || n.Rows != nil
+		hasHits = hasHits || n.DbHits != nil
+		hasTime = hasTime || n.TimeMs != nil
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	if p.Root != nil {
+		walk(p.Root)
+	}
+
+	var table bytes.Buffer
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	header := []string{"Operator", "Details"}
+	if hasEst {
+		header = append(header, "Est. Rows")
+	}
+	if hasRows {
+		header = append(header, "Rows")
+	}
+	if hasHits {
+		header = append(header, "DB Hits")
+	}
+	if hasTime {
+		header = append(header, "Time (ms)")
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	var line func(n *PlanNode, prefix, branch string)
+	line = func(n *PlanNode, prefix, branch string) {
+		cells := []string{prefix + branch + n.Operator, n.Details}
+		if hasEst {
+			cells = append(cells, optFloat(n.EstimatedRows))
+		}
+		if hasRows {
+			cells = append(cells, optInt(n.Rows))
+		}
+		if hasHits {
+			cells = append(cells, optInt(n.DbHits))
+		}
+		if hasTime {
+			cells = append(cells, optFloat(n.TimeMs))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+
+		childPrefix := prefix
+		switch branch {
+		case "├─ ":
+			childPrefix += "│  "
+		case "└─ ":
+			childPrefix += "   "
+		}
+		for i, c := range n.Children {
+			if i == len(n.Children)-1 {
+				line(c, childPrefix, "└─ ")
+			} else {
+				line(c, childPrefix, "├─ ")
+			}
+		}
+	}
+	if p.Root != nil {
+		line(p.Root, "", "")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	// Empty trailing cells leave padding behind; trim it per line.
+	for _, l := range strings.SplitAfter(table.String(), "\n") {
+		if l == "" {
+			continue
+		}
+		if _, err := io.WriteString(w, strings.TrimRight(l, " \n")+"\n"); err != nil {
+			return err
+		}
+	}
+	if p.Profiled {
+		_, err := fmt.Fprintf(w, "Total: %d row(s) in %s ms\n", p.RowsReturned, strconv.FormatFloat(p.ExecutionTimeMs, 'f', -1, 64))
+		return err
+	}
+	return nil
+}
+
+// String renders the plan tree (see Render).
+func (p *QueryPlan) String() string {
+	var buf bytes.Buffer
+	p.Render(&buf)
+	return buf.String()
+}
+
+// JSON renders the plan as indented JSON, suitable for structured logs.
+func (p *QueryPlan) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+func optFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func optInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
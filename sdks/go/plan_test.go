@@ -0,0 +1,117 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAndRenderNestedPlan(t *testing.T) {
+	result := &QueryResult{
+		Columns: []string{"profile"},
+		Rows: [][]interface{}{{map[string]interface{}{
+			"execution_time_ms": 3.5,
+			"rows_returned":     2,
+			"plan": map[string]interface{}{"root": map[string]interface{}{
+				"operator": "ProduceResults", "details": "n", "rows": 2, "db_hits": 0, "estimated_rows": 2.5,
+				"children": []interface{}{map[string]interface{}{
+					"operator": "CartesianProduct", "rows": 2, "db_hits": 0,
+					"children": []interface{}{
+						map[string]interface{}{"operator": "NodeByLabelScan", "details": "a:Person", "rows": 1, "db_hits": 4},
+						map[string]interface{}{"operator": "AllNodesScan", "details": "b", "rows": 2, "db_hits": 9},
+					},
+				}},
+			}},
+		}}},
+	}
+
+	plan, err := ParseQueryPlan(result)
+	require.NoError(t, err)
+	assert.True(t, plan.Profiled)
+
+	expected := "" +
+		"Operator               Details   Est. Rows  Rows  DB Hits\n" +
+		"ProduceResults         n         2.5        2     0\n" +
+		"└─ CartesianProduct                         2     0\n" +
+		"   ├─ NodeByLabelScan  a:Person             1     4\n" +
+		"   └─ AllNodesScan     b                    2     9\n" +
+		"Total: 2 row(s) in 3.5 ms\n"
+	assert.Equal(t, expected, plan.String())
+
+	out, err := plan.JSON()
+	require.NoError(t, err)
+	var decoded QueryPlan
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, "AllNodesScan", decoded.Root.Children[0].Children[1].Operator)
+}
+
+func TestParseFlatServerPlan(t *testing.T) {
+	result := &QueryResult{
+		Columns: []string{"plan"},
+		Rows: [][]interface{}{{map[string]interface{}{
+			"plan": map[string]interface{}{"operators": []interface{}{
+				map[string]interface{}{"type": "NodeByLabel", "description": "NodeByLabel"},
+				map[string]interface{}{"type": "Project", "description": "Project"},
+			}},
+			"estimated_rows": "N/A",
+		}}},
+	}
+
+	plan, err := ParseQueryPlan(result)
+	require.NoError(t, err)
+	assert.False(t, plan.Profiled)
+	assert.Equal(t, "Project", plan.Root.Operator)
+	assert.Equal(t, "NodeByLabel", plan.Root.Children[0].Operator)
+	assert.Equal(t, "Operator        Details\nProject\n└─ NodeByLabel\n", plan.String())
+}
+
+func TestExplainAndProfileCypher(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req.Query)
+		column, plan := "plan", map[string]interface{}{"root": map[string]interface{}{
+			"operator": "ProduceResults", "details": "p", "estimated_rows": 1,
+			"children": []interface{}{map[string]interface{}{
+				"operator": "NodeIndexSeek", "details": "p:Person(email) = $e", "estimated_rows": 1,
+			}},
+		}}
+		if req.Query[:7] == "PROFILE" {
+			column, plan = "profile", map[string]interface{}{"execution_time_ms": 1.5, "rows_returned": 1, "plan": plan}
+			root := plan["plan"].(map[string]interface{})["root"].(map[string]interface{})
+			root["db_hits"] = 1
+			root["children"].([]interface{})[0].(map[string]interface{})["db_hits"] = 2
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{column}, "rows": [][]interface{}{{plan}}})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	plan, err := client.ExplainCypher(ctx, "MATCH (p:Person {email: $e}) RETURN p", map[string]interface{}{"e": "a@b"})
+	require.NoError(t, err)
+	assert.False(t, plan.Profiled)
+	assert.Len(t, plan.Operators(), 2)
+	require.Len(t, plan.IndexOperators(), 1)
+	assert.True(t, plan.UsesIndex("Person", "email"))
+	assert.False(t, plan.UsesIndex("Person", "name"))
+	assert.Zero(t, plan.TotalDbHits())
+
+	plan, err = client.ProfileCypher(ctx, "  explain MATCH (p:Person {email: $e}) RETURN p", map[string]interface{}{"e": "a@b"})
+	require.NoError(t, err)
+	assert.True(t, plan.Profiled)
+	assert.Equal(t, int64(3), plan.TotalDbHits())
+	assert.Equal(t, int64(1), plan.RowsReturned)
+
+	assert.Equal(t, []string{
+		"EXPLAIN MATCH (p:Person {email: $e}) RETURN p",
+		"PROFILE MATCH (p:Person {email: $e}) RETURN p",
+	}, queries)
+}
@@ -0,0 +1,206 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// QueryPolicy inspects every Cypher query before the client sends it
+// and may reject it with an error or return a rewritten query. Install
+// one with Config.QueryPolicy; it applies to ExecuteCypher and its
+// variants (HTTP, retrying, as-of, snapshot, transactions), including
+// the queries helper methods send, so put policies for untrusted input
+// on a dedicated client.
+type QueryPolicy interface {
+	ApplyPolicy(ctx context.Context, query string, params map[string]interface{}) (string, error)
+}
+
+// QueryPolicyFunc adapts a function to QueryPolicy.
+type QueryPolicyFunc func(ctx context.Context, query string, params map[string]interface{}) (string, error)
+
+// ApplyPolicy calls f(ctx, query, params).
+func (f QueryPolicyFunc) ApplyPolicy(ctx context.Context, query string, params map[string]interface{}) (string, error) {
+	return f(ctx, query, params)
+}
+
+// ChainPolicies applies policies in order, each seeing the previous
+// one's rewrite; the first error stops the chain.
+func ChainPolicies(policies ...QueryPolicy) QueryPolicy {
+	return QueryPolicyFunc(func(ctx context.Context, query string, params map[string]interface{}) (string, error) {
+		var err error
+		for _, p := range policies {
+			if query, err = p.ApplyPolicy(ctx, query, params); err != nil {
+				return "", err
+			}
+		}
+		return query, nil
+	})
+}
+
+// Rules reported by Guardrails.
+const (
+	RuleNoDetachDelete = "no-detach-delete"
+	RuleRequireLimit   = "require-limit"
+	RuleMaxHops        = "max-hops"
+)
+
+// QueryPolicyError is returned when a QueryPolicy rejects a query.
+type QueryPolicyError struct {
+	Rule   string
+	Query  string
+	Reason string
+}
+
+func (e *QueryPolicyError) Error() string {
+	return fmt.Sprintf("nexus: query rejected by policy %s: %s", e.Rule, e.Reason)
+}
+
+// Guardrails is the built-in QueryPolicy for queries written by end
+// users or language models. The zero value allows everything.
+type Guardrails struct {
+	// ForbidDetachDelete rejects DETACH DELETE.
+	ForbidDetachDelete bool
+	// RequireLimit rejects queries that MATCH and RETURN rows without a
+	// LIMIT on the final RETURN (of each UNION branch). DefaultLimit,
+	// when set, appends LIMIT DefaultLimit to them instead.
+	RequireLimit bool
+	DefaultLimit int
+	// MaxHops caps variable-length relationship patterns: an open upper
+	// bound (-[*]->, -[*2..]->) or one above MaxHops is lowered to
+	// MaxHops. Patterns whose lower bound exceeds it are rejected.
+	MaxHops int
+}
+
+// ApplyPolicy implements QueryPolicy.
+func (g Guardrails) ApplyPolicy(_ context.Context, query string, _ map[string]interface{}) (string, error) {
+	// Lexical problems are left to the server to report.
+	toks, _ := cypherlex.Tokenize(query)
+	reject := func(rule, format string, args ...interface{}) (string, error) {
+		return "", &QueryPolicyError{Rule: rule, Query: query, Reason: fmt.Sprintf(format, args...)}
+	}
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	if g.ForbidDetachDelete {
+		for i := range toks {
+			if cypherlex.Clause(toks, i, "DETACH") && i+1 < len(toks) && cypherlex.Clause(toks, i+1, "DELETE") {
+				return reject(RuleNoDetachDelete, "DETACH DELETE is not allowed")
+			}
+		}
+	}
+
+	if g.RequireLimit || g.DefaultLimit > 0 {
+		branch := 0
+		for i := 0; i <= len(toks); i++ {
+			if i < len(toks) && !(toks[i].Depth == 0 && cypherlex.Clause(toks, i, "UNION")) {
+				continue
+			}
+			if unlimitedReturn(toks[branch:i]) {
+				if g.DefaultLimit <= 0 {
+					return reject(RuleRequireLimit, "MATCH without LIMIT on the returned rows")
+				}
+				end := toks[i-1].End
+				edits = append(edits, edit{end, end, " LIMIT " + strconv.Itoa(g.DefaultLimit)})
+			}
+			branch = i + 1
+		}
+	}
+
+	if g.MaxHops > 0 {
+		for i, t := range toks {
+			if t.In != 'r' || !t.Punct("*") {
+				continue
+			}
+			lower, upper, end := variableLength(toks, i)
+			switch {
+			case lower > g.MaxHops:
+				return reject(RuleMaxHops, "variable-length pattern needs more than %d hops", g.MaxHops)
+			case upper < 0 || upper > g.MaxHops:
+				spec := "*.." + strconv.Itoa(g.MaxHops)
+				if lower >= 0 {
+					spec = "*" + strconv.Itoa(lower) + ".." + strconv.Itoa(g.MaxHops)
+				}
+				edits = append(edits, edit{t.Start, end, spec})
+			}
+		}
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		query = query[:e.start] + e.text + query[e.end:]
+	}
+	return query, nil
+}
+
+// unlimitedReturn reports whether a query branch MATCHes and returns
+// rows from its final top-level RETURN without a LIMIT.
+func unlimitedReturn(toks []cypherlex.Token) bool {
+	match, ret := false, -1
+	for i, t := range toks {
+		if cypherlex.Clause(toks, i, "MATCH") {
+			match = true
+		}
+		if t.Depth == 0 && cypherlex.Clause(toks, i, "RETURN") {
+			ret = i
+		}
+	}
+	if !match || ret < 0 {
+		return false
+	}
+	for i := ret + 1; i < len(toks); i++ {
+		if toks[i].Depth == 0 && cypherlex.Clause(toks, i, "LIMIT") {
+			return false
+		}
+	}
+	return true
+}
+
+// variableLength parses the range after the '*' at toks[i]: -1 marks
+// an omitted bound (an omitted lower bound means 1, a bare * has no
+// upper bound and *n is exactly n). end is the byte offset after it.
+func variableLength(toks []cypherlex.Token, i int) (lower, upper, end int) {
+	lower, upper, end = -1, -1, toks[i].End
+	j := i + 1
+	if j < len(toks) && toks[j].Kind == cypherlex.Number {
+		lower, _ = strconv.Atoi(toks[j].Text)
+		end = toks[j].End
+		j++
+	}
+	if j+1 < len(toks) && toks[j].Punct(".") && toks[j+1].Punct(".") {
+		end = toks[j+1].End
+		j += 2
+		if j < len(toks) && toks[j].Kind == cypherlex.Number {
+			upper, _ = strconv.Atoi(toks[j].Text)
+			end = toks[j].End
+		}
+		return lower, upper, end
+	}
+	return lower, lower, end
+}
+
+// checkQuery runs Config.QueryPolicy and then Config.Authorizer, if
+// set, over query, and charges it to Config.Quota.
+func (c *Client) checkQuery(ctx context.Context, query string, params map[string]interface{}) (string, error) {
+	if c.policy != nil {
+		var err error
+		if query, err = c.policy.ApplyPolicy(ctx, query, params); err != nil {
+			return "", err
+		}
+	}
+	if c.authorizer != nil {
+		if err := c.authorize(ctx, AnalyzeQuery(query)); err != nil {
+			return "", err
+		}
+	}
+	if err := c.quota.admit(ctx); err != nil {
+		return "", err
+	}
+	return query, nil
+}
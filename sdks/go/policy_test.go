@@ -0,0 +1,86 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardrails(t *testing.T) {
+	g := Guardrails{ForbidDetachDelete: true, DefaultLimit: 100, MaxHops: 5}
+	cases := map[string]string{
+		"MATCH (n) RETURN n":          "MATCH (n) RETURN n LIMIT 100",
+		"MATCH (n) RETURN n LIMIT 10": "MATCH (n) RETURN n LIMIT 10",
+		"MATCH (n) SET n.limit = 1":   "MATCH (n) SET n.limit = 1",
+		"RETURN 1":                    "RETURN 1",
+		"MATCH (a) RETURN a UNION MATCH (b) RETURN b LIMIT 3":                 "MATCH (a) RETURN a LIMIT 100 UNION MATCH (b) RETURN b LIMIT 3",
+		"MATCH (n) CALL { WITH n MATCH (n)-->(m) RETURN m LIMIT 1 } RETURN m": "MATCH (n) CALL { WITH n MATCH (n)-->(m) RETURN m LIMIT 1 } RETURN m LIMIT 100",
+		"MATCH p = (a)-[*]->(b) RETURN count(p) LIMIT 1":                      "MATCH p = (a)-[*..5]->(b) RETURN count(p) LIMIT 1",
+		"MATCH (a)-[:KNOWS*2..]->(b) RETURN b LIMIT 1":                        "MATCH (a)-[:KNOWS*2..5]->(b) RETURN b LIMIT 1",
+		"MATCH (a)-[:KNOWS*..20]->(b) RETURN b LIMIT 1":                       "MATCH (a)-[:KNOWS*..5]->(b) RETURN b LIMIT 1",
+		"MATCH (a)-[r *1..3]->(b) RETURN b LIMIT 1":                           "MATCH (a)-[r *1..3]->(b) RETURN b LIMIT 1",
+		"MATCH (n) WHERE n.s = 'DETACH DELETE' RETURN n.x * 2":                "MATCH (n) WHERE n.s = 'DETACH DELETE' RETURN n.x * 2 LIMIT 100",
+	}
+	for in, want := range cases {
+		got, err := g.ApplyPolicy(context.Background(), in, nil)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	rejected := map[string]string{
+		"MATCH (n) DETACH DELETE n":               RuleNoDetachDelete,
+		"MATCH (a)-[*6]->(b) RETURN b LIMIT 1":    RuleMaxHops,
+		"MATCH (a)-[*7..9]->(b) RETURN b LIMIT 1": RuleMaxHops,
+	}
+	for in, rule := range rejected {
+		_, err := g.ApplyPolicy(context.Background(), in, nil)
+		var perr *QueryPolicyError
+		require.True(t, errors.As(err, &perr), in)
+		assert.Equal(t, rule, perr.Rule)
+	}
+
+	_, err := Guardrails{RequireLimit: true}.ApplyPolicy(context.Background(), "MATCH (n) RETURN n", nil)
+	assert.EqualError(t, err, "nexus: query rejected by policy require-limit: MATCH without LIMIT on the returned rows")
+}
+
+func TestQueryPolicyAppliedByClient(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		sent = append(sent, req.Query)
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	var seenParams map[string]interface{}
+	audit := QueryPolicyFunc(func(_ context.Context, query string, params map[string]interface{}) (string, error) {
+		seenParams = params
+		if strings.Contains(query, "secret") {
+			return "", errors.New("no secrets")
+		}
+		return query, nil
+	})
+	client := NewClient(Config{BaseURL: server.URL, QueryPolicy: ChainPolicies(audit, Guardrails{DefaultLimit: 10})})
+	ctx := context.Background()
+
+	_, err := client.ExecuteCypher(ctx, "MATCH (n) RETURN n", map[string]interface{}{"x": 1})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"x": 1}, seenParams)
+	_, err = client.ExecuteCypherHTTP(ctx, "MATCH (n) RETURN n.secret", nil)
+	assert.EqualError(t, err, "no secrets")
+
+	tx := &Transaction{client: client, id: "tx1"}
+	_, err = tx.ExecuteCypher(ctx, "MATCH (m) RETURN m", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MATCH (n) RETURN n LIMIT 10", "MATCH (m) RETURN m LIMIT 10"}, sent)
+}
@@ -0,0 +1,110 @@
+package nexus
+
+import "strings"
+
+// Predicate is a WHERE condition whose values are bound as parameters
+// rather than formatted into the query text. Predicates compose with
+// And, Or and Not:
+//
+//	p := nexus.And(
+//		nexus.Eq("p.name", name),
+//		nexus.Or(nexus.Gt("p.age", 18), nexus.IsNull("p.age")),
+//	)
+//	qb.Match("(p:Person)").WherePredicate(p)
+//
+// Expressions (the left-hand sides) go into the query as written, so
+// they must come from code, never from user input.
+type Predicate interface {
+	// Build renders the condition, binding its values as parameters of
+	// qb.
+	Build(qb *QueryBuilder) string
+}
+
+type predicateFunc func(qb *QueryBuilder) string
+
+func (f predicateFunc) Build(qb *QueryBuilder) string { return f(qb) }
+
+// comparison renders "expr op $pN" with value bound to $pN.
+func comparison(expr, op string, value interface{}) Predicate {
+	return predicateFunc(func(qb *QueryBuilder) string {
+		return expr + " " + op + " " + qb.Param(value)
+	})
+}
+
+// Eq matches when expr equals value.
+func Eq(expr string, value interface{}) Predicate { return comparison(expr, "=", value) }
+
+// Ne matches when expr differs from value.
+func Ne(expr string, value interface{}) Predicate { return comparison(expr, "<>", value) }
+
+// Gt matches when expr is greater than value.
+func Gt(expr string, value interface{}) Predicate { return comparison(expr, ">", value) }
+
+// Gte matches when expr is greater than or equal to value.
+func Gte(expr string, value interface{}) Predicate { return comparison(expr, ">=", value) }
+
+// Lt matches when expr is less than value.
+func Lt(expr string, value interface{}) Predicate { return comparison(expr, "<", value) }
+
+// Lte matches when expr is less than or equal to value.
+func Lte(expr string, value interface{}) Predicate { return comparison(expr, "<=", value) }
+
+// In matches when expr is an element of values, which is bound as one
+// list parameter.
+func In(expr string, values interface{}) Predicate { return comparison(expr, "IN", values) }
+
+// Contains matches when the string expr contains substr.
+func Contains(expr, substr string) Predicate { return comparison(expr, "CONTAINS", substr) }
+
+// StartsWith matches when the string expr starts with prefix.
+func StartsWith(expr, prefix string) Predicate { return comparison(expr, "STARTS WITH", prefix) }
+
+// EndsWith matches when the string expr ends with suffix.
+func EndsWith(expr, suffix string) Predicate { return comparison(expr, "ENDS WITH", suffix) }
+
+// IsNull matches when expr is null, e.g. a missing property.
+func IsNull(expr string) Predicate {
+	return predicateFunc(func(*QueryBuilder) string { return expr + " IS NULL" })
+}
+
+// IsNotNull matches when expr is not null.
+func IsNotNull(expr string) Predicate {
+	return predicateFunc(func(*QueryBuilder) string { return expr + " IS NOT NULL" })
+}
+
+// Raw wraps a hand-written condition, for what the builders do not
+// cover. Bind its values with qb.Param or WithParam.
+func Raw(condition string) Predicate {
+	return predicateFunc(func(*QueryBuilder) string { return condition })
+}
+
+// And matches when all of preds match; with none it always matches.
+func And(preds ...Predicate) Predicate { return junction(" AND ", "true", preds) }
+
+// Or matches when any of preds matches; with none it never matches.
+func Or(preds ...Predicate) Predicate { return junction(" OR ", "false", preds) }
+
+// junction joins preds with op, parenthesised so the result composes
+// with any neighbouring operator.
+func junction(op, empty string, preds []Predicate) Predicate {
+	return predicateFunc(func(qb *QueryBuilder) string {
+		switch len(preds) {
+		case 0:
+			return empty
+		case 1:
+			return preds[0].Build(qb)
+		}
+		parts := make([]string, len(preds))
+		for i, p := range preds {
+			parts[i] = p.Build(qb)
+		}
+		return "(" + strings.Join(parts, op) + ")"
+	})
+}
+
+// Not matches when pred does not.
+func Not(pred Predicate) Predicate {
+	return predicateFunc(func(qb *QueryBuilder) string {
+		return "NOT (" + pred.Build(qb) + ")"
+	})
+}
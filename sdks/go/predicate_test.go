@@ -0,0 +1,36 @@
+package nexus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredicates(t *testing.T) {
+	qb := NewQueryBuilder()
+	query, params := qb.Match("(p:Person)").
+		WherePredicate(And(
+			Eq("p.name", "x' OR 1=1 //"),
+			Or(Gte("p.age", 18), IsNull("p.age")),
+			Not(In("p.status", []string{"banned", "deleted"})),
+			StartsWith("p.email", "admin"),
+		)).
+		WherePredicate(Or(Contains("p.bio", "go"), EndsWith("p.email", ".org"), Raw("p.vip"))).
+		Return("p").
+		Build()
+
+	assert.Equal(t, "MATCH (p:Person) WHERE (p.name = $p1 AND (p.age >= $p2 OR p.age IS NULL) AND "+
+		"NOT (p.status IN $p3) AND p.email STARTS WITH $p4) AND (p.bio CONTAINS $p5 OR p.email ENDS WITH $p6 OR p.vip) "+
+		"RETURN p", query)
+	assert.Equal(t, map[string]interface{}{
+		"p1": "x' OR 1=1 //", "p2": 18, "p3": []string{"banned", "deleted"},
+		"p4": "admin", "p5": "go", "p6": ".org",
+	}, params)
+
+	qb = NewQueryBuilder()
+	assert.Equal(t, "true", And().Build(qb))
+	assert.Equal(t, "false", Or().Build(qb))
+	assert.Equal(t, "n.x <> $p1", Or(Ne("n.x", 1)).Build(qb))
+	assert.Equal(t, "(n.x < $p2 AND n.x <= $p3 AND n.x > $p4 AND n.y IS NOT NULL)",
+		And(Lt("n.x", 1), Lte("n.x", 2), Gt("n.x", 0), IsNotNull("n.y")).Build(qb))
+}
@@ -0,0 +1,45 @@
+package nexus
+
+import (
+	"context"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// PriorityHeader carries the QoS class of a request.
+const PriorityHeader = "X-Nexus-Priority"
+
+// Priority is a request's QoS class. The server schedules higher
+// classes first when it is under load; with spare capacity every class
+// runs immediately.
+type Priority string
+
+const (
+	// PriorityLow marks background traffic (analytics, exports,
+	// reindexing) that may queue behind interactive queries.
+	PriorityLow Priority = "low"
+	// PriorityNormal is what requests without a priority get.
+	PriorityNormal Priority = "normal"
+	// PriorityHigh is for latency-critical interactive queries.
+	PriorityHigh Priority = "high"
+)
+
+// WithPriority returns a context whose requests carry p as a QoS hint:
+//
+//	ctx := nexus.WithPriority(ctx, nexus.PriorityLow)
+//	manifest, err := client.ExportToStore(ctx, store, opts)
+//
+// Like other request-scoped options, scoped Cypher requests go over HTTP
+// on the RPC transport.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	if p == "" {
+		return ctx
+	}
+	return transport.WithHeader(ctx, PriorityHeader, string(p))
+}
+
+// PriorityFromContext returns the priority set with WithPriority, or ""
+// when none is set.
+func PriorityFromContext(ctx context.Context) Priority {
+	return Priority(transport.HeadersFromContext(ctx).Get(PriorityHeader))
+}
@@ -0,0 +1,34 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPriority(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(PriorityHeader))
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := WithPriority(context.Background(), PriorityLow)
+	assert.Equal(t, PriorityLow, PriorityFromContext(ctx))
+	assert.Equal(t, Priority(""), PriorityFromContext(context.Background()))
+
+	_, err := client.ExecuteCypher(ctx, "RETURN 1", nil)
+	require.NoError(t, err)
+	_, err = client.ExecuteCypher(WithPriority(ctx, PriorityHigh), "RETURN 1", nil)
+	require.NoError(t, err)
+	_, err = client.ExecuteCypher(context.Background(), "RETURN 1", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"low", "high", ""}, got)
+}
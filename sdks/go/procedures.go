@@ -0,0 +1,217 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ProcedureKind distinguishes CALL-able procedures from scalar
+// functions.
+type ProcedureKind string
+
+const (
+	KindProcedure ProcedureKind = "procedure"
+	KindFunction  ProcedureKind = "function"
+)
+
+// ProcedureParam is one argument or output column of a signature.
+type ProcedureParam struct {
+	Name string `json:"name"`
+	// Type is the Cypher type name (STRING, INTEGER, FLOAT, LIST, MAP, ANY…).
+	Type string `json:"type"`
+	// Optional arguments may be omitted from the end of a call.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// ProcedureSignature describes a registered procedure or function.
+type ProcedureSignature struct {
+	Name        string           `json:"name"`
+	Kind        ProcedureKind    `json:"kind"`
+	Params      []ProcedureParam `json:"params"`
+	Yields      []ProcedureParam `json:"yields,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Language    string           `json:"language,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// ProcedureDefinition uploads a user-defined procedure or function.
+type ProcedureDefinition struct {
+	ProcedureSignature
+	// Source is the module body (WASM bytes or script text, per
+	// Language); it is base64-encoded on the wire.
+	Source []byte `json:"source"`
+}
+
+// UploadProcedure registers (or replaces) a user-defined procedure or
+// function via POST /procedures.
+func (c *Client) UploadProcedure(ctx context.Context, def ProcedureDefinition) (*ProcedureSignature, error) {
+	if !procedureName.MatchString(def.Name) {
+		return nil, fmt.Errorf("nexus: invalid procedure name %q", def.Name)
+	}
+	if def.Kind == "" {
+		def.Kind = KindProcedure
+	}
+	if len(def.Source) == 0 {
+		return nil, errors.New("nexus: procedure source must not be empty")
+	}
+	if err := checkParamOrder(def.Params); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/procedures", def)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var sig ProcedureSignature
+	if err := decodeResponse(resp, &sig); err != nil {
+		return nil, err
+	}
+	c.signatures.Delete(def.Name)
+	return &sig, nil
+}
+
+// ListProcedures retrieves the signatures of every registered procedure
+// and function, built-in ones included.
+func (c *Client) ListProcedures(ctx context.Context) ([]ProcedureSignature, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/procedures", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Procedures []ProcedureSignature `json:"procedures"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	return result.Procedures, nil
+}
+
+// GetProcedure retrieves one signature by name.
+func (c *Client) GetProcedure(ctx context.Context, name string) (*ProcedureSignature, error) {
+	path := fmt.Sprintf("/procedures/%s", url.PathEscape(name))
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var sig ProcedureSignature
+	if err := decodeResponse(resp, &sig); err != nil {
+		return nil, err
+	}
+	return &sig, nil
+}
+
+// RemoveProcedure unregisters a user-defined procedure or function.
+func (c *Client) RemoveProcedure(ctx context.Context, name string) error {
+	path := fmt.Sprintf("/procedures/%s", url.PathEscape(name))
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	c.signatures.Delete(name)
+	return nil
+}
+
+// ArgumentCountError is returned by Call when the number of arguments
+// does not fit the registered signature.
+type ArgumentCountError struct {
+	Procedure string
+	Got       int
+	Min, Max  int
+}
+
+func (e *ArgumentCountError) Error() string {
+	if e.Min == e.Max {
+		return fmt.Sprintf("nexus: %s takes %d argument(s), got %d", e.Procedure, e.Min, e.Got)
+	}
+	return fmt.Sprintf("nexus: %s takes %d to %d arguments, got %d", e.Procedure, e.Min, e.Max, e.Got)
+}
+
+// Call invokes a registered procedure or function with positional
+// arguments, checking the count against its signature first (fetched
+// once and cached per client). Procedures yield their declared columns;
+// functions return a single `result` column.
+func (c *Client) Call(ctx context.Context, name string, args ...interface{}) (*QueryResult, error) {
+	sig, err := c.signature(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	required := 0
+	for _, p := range sig.Params {
+		if !p.Optional {
+			required++
+		}
+	}
+	if len(args) < required || len(args) > len(sig.Params) {
+		return nil, &ArgumentCountError{Procedure: name, Got: len(args), Min: required, Max: len(sig.Params)}
+	}
+
+	params := make(map[string]interface{}, len(args))
+	placeholders := make([]string, len(args))
+	for i, arg := range args {
+		key := fmt.Sprintf("arg%d", i)
+		params[key] = arg
+		placeholders[i] = "$" + key
+	}
+	call := fmt.Sprintf("%s(%s)", name, strings.Join(placeholders, ", "))
+
+	var query string
+	if sig.Kind == KindFunction {
+		query = "RETURN " + call + " AS result"
+	} else {
+		query = "CALL " + call
+		if len(sig.Yields) > 0 {
+			cols := make([]string, len(sig.Yields))
+			for i, y := range sig.Yields {
+				cols[i] = y.Name
+			}
+			query += " YIELD " + strings.Join(cols, ", ")
+		}
+	}
+	return c.ExecuteCypher(ctx, query, params)
+}
+
+func (c *Client) signature(ctx context.Context, name string) (*ProcedureSignature, error) {
+	if !procedureName.MatchString(name) {
+		return nil, fmt.Errorf("nexus: invalid procedure name %q", name)
+	}
+	if cached, ok := c.signatures.Load(name); ok {
+		return cached.(*ProcedureSignature), nil
+	}
+	sig, err := c.GetProcedure(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	c.signatures.Store(name, sig)
+	return sig, nil
+}
+
+// checkParamOrder rejects required parameters after optional ones,
+// which positional calls could not address.
+func checkParamOrder(params []ProcedureParam) error {
+	optional := false
+	for _, p := range params {
+		if p.Optional {
+			optional = true
+		} else if optional {
+			return fmt.Errorf("nexus: required parameter %q follows an optional one", p.Name)
+		}
+	}
+	return nil
+}
+
+// procedureName matches dotted procedure names such as `my.pkg.fn`.
+var procedureName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
@@ -0,0 +1,55 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallValidatesSignature(t *testing.T) {
+	signatureFetches := 0
+	var lastQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/procedures/geo.distance":
+			signatureFetches++
+			w.Write([]byte(`{"name":"geo.distance","kind":"procedure",
+				"params":[{"name":"from","type":"STRING"},{"name":"to","type":"STRING"},{"name":"unit","type":"STRING","optional":true}],
+				"yields":[{"name":"km","type":"FLOAT"}]}`))
+		case "/cypher":
+			var req map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			lastQuery = req["query"].(string)
+			w.Write([]byte(`{"columns":["km"],"rows":[[12.5]]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	result, err := client.Call(ctx, "geo.distance", "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, "CALL geo.distance($arg0, $arg1) YIELD km", lastQuery)
+	assert.Equal(t, 12.5, result.Rows[0][0])
+
+	_, err = client.Call(ctx, "geo.distance", "a")
+	var countErr *ArgumentCountError
+	require.True(t, errors.As(err, &countErr))
+	assert.Equal(t, 2, countErr.Min)
+	assert.Equal(t, 3, countErr.Max)
+
+	_, err = client.Call(ctx, "geo.distance", "a", "b", "mi", "extra")
+	assert.Error(t, err)
+	assert.Equal(t, 1, signatureFetches)
+
+	_, err = client.Call(ctx, "bad name; DROP")
+	assert.Error(t, err)
+}
@@ -0,0 +1,393 @@
+// Package prompush pushes the query statistics of a Nexus client to
+// Prometheus, for batch jobs and ingestion pipelines that have no scrape
+// target. A Reporter is a nexus.MetricsHook that aggregates QueryStats
+// per label and operation; Run pushes them periodically to a Pushgateway
+// or a remote-write endpoint:
+//
+//	reporter, err := prompush.New(prompush.Options{URL: "http://pushgateway:9091", Job: "loader"})
+//	client, _ := nexus.NewClientE(nexus.Config{BaseURL: url, Metrics: reporter})
+//	go reporter.Run(ctx)
+//
+// Every metric is a counter covering the reporter's lifetime:
+//
+//	nexus_client_queries_total
+//	nexus_client_query_errors_total
+//	nexus_client_query_duration_seconds_total   (client-side latency)
+//	nexus_client_query_execution_seconds_total  (server-reported execution time)
+//	nexus_client_rows_returned_total
+//	nexus_client_nodes_created_total
+//	nexus_client_nodes_deleted_total
+//	nexus_client_relationships_created_total
+//	nexus_client_relationships_deleted_total
+//	nexus_client_properties_set_total
+//
+// each labelled with label (the first label or relationship type the
+// statement mentions, "" when none) and operation (read, write or
+// schema).
+package prompush
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+)
+
+// Protocol selects how a Reporter pushes.
+type Protocol int
+
+// Protocols.
+const (
+	// Pushgateway PUTs the text exposition format to
+	// URL/metrics/job/<Job>, replacing the job's previous push.
+	Pushgateway Protocol = iota
+	// RemoteWrite POSTs a snappy-compressed protobuf WriteRequest to URL
+	// (Prometheus remote-write 1.0), one sample per series and push.
+	RemoteWrite
+)
+
+// Options configures a Reporter.
+type Options struct {
+	// URL is the Pushgateway base URL or the remote-write endpoint.
+	URL      string
+	Protocol Protocol
+	// Job is the job label (default "nexus_client").
+	Job string
+	// Grouping adds labels to every series: Pushgateway grouping key
+	// labels (e.g. instance), or extra remote-write labels.
+	Grouping map[string]string
+	// Interval is the time between pushes of Run (default 15s).
+	Interval time.Duration
+	// HTTPClient sends the pushes (default: a client with a 10s timeout).
+	HTTPClient *http.Client
+	// Header is added to every push, e.g. Authorization.
+	Header http.Header
+	// OnError, when set, is called with the errors of Run's pushes,
+	// which are otherwise dropped.
+	OnError func(error)
+}
+
+// Reporter aggregates query statistics and pushes them. It is safe for
+// concurrent use.
+type Reporter struct {
+	opts Options
+	now  func() time.Time
+
+	mu     sync.Mutex
+	series map[seriesKey]*counters
+}
+
+type seriesKey struct {
+	label     string
+	operation nexus.Operation
+}
+
+type counters struct {
+	queries, errors, rows                      float64
+	duration, execution                        float64
+	nodesCreated, nodesDeleted                 float64
+	relationshipsCreated, relationshipsDeleted float64
+	propertiesSet                              float64
+}
+
+// metrics lists the counters in exposition order.
+var metrics = []struct {
+	name, help string
+	value      func(*counters) float64
+}{
+	{"nexus_client_queries_total", "Cypher statements run.", func(c *counters) float64 { return c.queries }},
+	{"nexus_client_query_errors_total", "Cypher statements that failed.", func(c *counters) float64 { return c.errors }},
+	{"nexus_client_query_duration_seconds_total", "Client-side latency of Cypher statements.", func(c *counters) float64 { return c.duration }},
+	{"nexus_client_query_execution_seconds_total", "Server-reported execution time of Cypher statements.", func(c *counters) float64 { return c.execution }},
+	{"nexus_client_rows_returned_total", "Rows returned by Cypher statements.", func(c *counters) float64 { return c.rows }},
+	{"nexus_client_nodes_created_total", "Nodes created.", func(c *counters) float64 { return c.nodesCreated }},
+	{"nexus_client_nodes_deleted_total", "Nodes deleted.", func(c *counters) float64 { return c.nodesDeleted }},
+	{"nexus_client_relationships_created_total", "Relationships created.", func(c *counters) float64 { return c.relationshipsCreated }},
+	{"nexus_client_relationships_deleted_total", "Relationships deleted.", func(c *counters) float64 { return c.relationshipsDeleted }},
+	{"nexus_client_properties_set_total", "Properties set.", func(c *counters) float64 { return c.propertiesSet }},
+}
+
+// New returns a Reporter pushing to opts.URL.
+func New(opts Options) (*Reporter, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("prompush: invalid URL %q", opts.URL)
+	}
+	if opts.Job == "" {
+		opts.Job = "nexus_client"
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 15 * time.Second
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	for name := range opts.Grouping {
+		if !validLabelName(name) || name == "job" || name == "label" || name == "operation" {
+			return nil, fmt.Errorf("prompush: invalid grouping label %q", name)
+		}
+	}
+	return &Reporter{opts: opts, now: time.Now, series: map[seriesKey]*counters{}}, nil
+}
+
+// ObserveQuery implements nexus.MetricsHook.
+func (r *Reporter) ObserveQuery(_ context.Context, event nexus.QueryEvent) {
+	access := nexus.AnalyzeQuery(event.Query)
+	key := seriesKey{operation: access.Operation}
+	switch {
+	case len(access.Labels) > 0:
+		key.label = access.Labels[0]
+	case len(access.RelationshipTypes) > 0:
+		key.label = access.RelationshipTypes[0]
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.series[key]
+	if c == nil {
+		c = &counters{}
+		r.series[key] = c
+	}
+	c.queries++
+	if event.Err != nil {
+		c.errors++
+	}
+	c.rows += float64(event.Rows)
+	c.duration += event.Duration.Seconds()
+	if s := event.Stats; s != nil {
+		c.execution += s.ExecutionTimeMs / 1000
+		c.nodesCreated += float64(s.NodesCreated)
+		c.nodesDeleted += float64(s.NodesDeleted)
+		c.relationshipsCreated += float64(s.RelationshipsCreated)
+		c.relationshipsDeleted += float64(s.RelationshipsDeleted)
+		c.propertiesSet += float64(s.PropertiesSet)
+	}
+}
+
+// Run pushes every Interval until ctx is done, then pushes a last time
+// so the final counts are not lost. It returns ctx's error.
+func (r *Reporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			timeout := r.opts.HTTPClient.Timeout
+			if timeout <= 0 {
+				timeout = 10 * time.Second
+			}
+			final, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+			r.report(r.Push(final))
+			cancel()
+			return ctx.Err()
+		case <-ticker.C:
+			r.report(r.Push(ctx))
+		}
+	}
+}
+
+func (r *Reporter) report(err error) {
+	if err != nil && r.opts.OnError != nil {
+		r.opts.OnError(err)
+	}
+}
+
+// Push sends the current counts once.
+func (r *Reporter) Push(ctx context.Context) error {
+	samples := r.snapshot()
+	var (
+		req *http.Request
+		err error
+	)
+	if r.opts.Protocol == RemoteWrite {
+		req, err = r.remoteWriteRequest(ctx, samples)
+	} else {
+		req, err = r.pushgatewayRequest(ctx, samples)
+	}
+	if err != nil {
+		return err
+	}
+	for name, values := range r.opts.Header {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	resp, err := r.opts.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("prompush: push: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var body bytes.Buffer
+		body.ReadFrom(resp.Body)
+		return fmt.Errorf("prompush: push: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(body.String()))
+	}
+	return nil
+}
+
+// sample is one series value at push time.
+type sample struct {
+	metric int // index into metrics
+	key    seriesKey
+	value  float64
+}
+
+// snapshot returns every series value, by metric then label and
+// operation.
+func (r *Reporter) snapshot() []sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]seriesKey, 0, len(r.series))
+	for k := range r.series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].label != keys[j].label {
+			return keys[i].label < keys[j].label
+		}
+		return keys[i].operation < keys[j].operation
+	})
+	out := make([]sample, 0, len(metrics)*len(keys))
+	for m, metric := range metrics {
+		for _, k := range keys {
+			out = append(out, sample{metric: m, key: k, value: metric.value(r.series[k])})
+		}
+	}
+	return out
+}
+
+func (r *Reporter) pushgatewayRequest(ctx context.Context, samples []sample) (*http.Request, error) {
+	path := "/metrics/job/" + url.PathEscape(r.opts.Job)
+	for _, name := range sortedNames(r.opts.Grouping) {
+		path += "/" + name + "/" + url.PathEscape(r.opts.Grouping[name])
+	}
+	var body bytes.Buffer
+	last := -1
+	for _, s := range samples {
+		if s.metric != last {
+			last = s.metric
+			fmt.Fprintf(&body, "# HELP %s %s\n# TYPE %s counter\n", metrics[s.metric].name, metrics[s.metric].help, metrics[s.metric].name)
+		}
+		fmt.Fprintf(&body, "%s{label=\"%s\",operation=\"%s\"} %s\n", metrics[s.metric].name,
+			escapeLabelValue(s.key.label), escapeLabelValue(string(s.key.operation)), formatFloat(s.value))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, strings.TrimRight(r.opts.URL, "/")+path, &body)
+	if err != nil {
+		return nil, fmt.Errorf("prompush: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	return req, nil
+}
+
+func (r *Reporter) remoteWriteRequest(ctx context.Context, samples []sample) (*http.Request, error) {
+	ts := r.now().UnixMilli()
+	var write []byte
+	for _, s := range samples {
+		labels := map[string]string{
+			"__name__":  metrics[s.metric].name,
+			"job":       r.opts.Job,
+			"label":     s.key.label,
+			"operation": string(s.key.operation),
+		}
+		for k, v := range r.opts.Grouping {
+			labels[k] = v
+		}
+		var series []byte
+		// Remote write wants labels sorted by name; empty values mean
+		// "no label".
+		for _, name := range sortedNames(labels) {
+			if labels[name] == "" {
+				continue
+			}
+			var label []byte
+			label = appendBytesField(label, 1, []byte(name))
+			label = appendBytesField(label, 2, []byte(labels[name]))
+			series = appendBytesField(series, 1, label)
+		}
+		var point []byte
+		point = appendTag(point, 1, 1)
+		point = binary.LittleEndian.AppendUint64(point, math.Float64bits(s.value))
+		point = appendTag(point, 2, 0)
+		point = binary.AppendUvarint(point, uint64(ts))
+		series = appendBytesField(series, 2, point)
+		write = appendBytesField(write, 1, series)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.opts.URL, bytes.NewReader(snappyEncode(write)))
+	if err != nil {
+		return nil, fmt.Errorf("prompush: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	return req, nil
+}
+
+// appendTag appends a protobuf field tag.
+func appendTag(b []byte, field, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(field<<3|wireType))
+}
+
+// appendBytesField appends a length-delimited protobuf field.
+func appendBytesField(b []byte, field int, data []byte) []byte {
+	b = appendTag(b, field, 2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// snappyEncode frames src as a snappy block of literals. That is valid
+// snappy every decoder accepts, without a compression dependency; the
+// payloads are small.
+func snappyEncode(src []byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(len(src)))
+	for len(src) > 0 {
+		n := min(len(src), 1<<16)
+		if n <= 60 {
+			out = append(out, byte(n-1)<<2)
+		} else {
+			// Tag 61: the length - 1 follows in two little-endian bytes.
+			out = append(out, 61<<2, byte(n-1), byte((n-1)>>8))
+		}
+		out = append(out, src[:n]...)
+		src = src[n:]
+	}
+	return out
+}
+
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validLabelName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "__") {
+		return false
+	}
+	for i, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
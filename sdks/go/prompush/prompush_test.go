@@ -0,0 +1,167 @@
+package prompush
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func observe(r *Reporter) {
+	ctx := context.Background()
+	r.ObserveQuery(ctx, nexus.QueryEvent{
+		Query:    "CREATE (n:Person {name: $name})",
+		Duration: 20 * time.Millisecond,
+		Stats:    &nexus.QueryStats{NodesCreated: 1, PropertiesSet: 1, ExecutionTimeMs: 5},
+	})
+	r.ObserveQuery(ctx, nexus.QueryEvent{
+		Query:    "CREATE (n:Person {name: $name})",
+		Duration: 30 * time.Millisecond,
+		Stats:    &nexus.QueryStats{NodesCreated: 1, PropertiesSet: 1, ExecutionTimeMs: 15},
+	})
+	r.ObserveQuery(ctx, nexus.QueryEvent{Query: "MATCH (n:Person) RETURN n", Rows: 2, Duration: 10 * time.Millisecond})
+	r.ObserveQuery(ctx, nexus.QueryEvent{Query: "RETURN 1", Err: errors.New("boom")})
+}
+
+func TestPushgateway(t *testing.T) {
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "Bearer t", r.Header.Get("Authorization"))
+		path = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	reporter, err := New(Options{
+		URL: server.URL, Job: "loader", Grouping: map[string]string{"instance": "worker 1"},
+		Header: http.Header{"Authorization": {"Bearer t"}},
+	})
+	require.NoError(t, err)
+	observe(reporter)
+	require.NoError(t, reporter.Push(context.Background()))
+
+	assert.Equal(t, "/metrics/job/loader/instance/worker 1", path)
+	assert.Contains(t, body, "# TYPE nexus_client_queries_total counter\n"+
+		"nexus_client_queries_total{label=\"\",operation=\"read\"} 1\n"+
+		"nexus_client_queries_total{label=\"Person\",operation=\"read\"} 1\n"+
+		"nexus_client_queries_total{label=\"Person\",operation=\"write\"} 2\n")
+	assert.Contains(t, body, "nexus_client_query_errors_total{label=\"\",operation=\"read\"} 1\n")
+	assert.Contains(t, body, "nexus_client_query_duration_seconds_total{label=\"Person\",operation=\"write\"} 0.05\n")
+	assert.Contains(t, body, "nexus_client_query_execution_seconds_total{label=\"Person\",operation=\"write\"} 0.02\n")
+	assert.Contains(t, body, "nexus_client_nodes_created_total{label=\"Person\",operation=\"write\"} 2\n")
+	assert.Contains(t, body, "nexus_client_rows_returned_total{label=\"Person\",operation=\"read\"} 2\n")
+
+	_, err = New(Options{URL: "pushgateway:9091"})
+	assert.Error(t, err)
+	_, err = New(Options{URL: server.URL, Grouping: map[string]string{"job": "x"}})
+	assert.Error(t, err)
+}
+
+func TestRemoteWrite(t *testing.T) {
+	var series []map[string]string
+	var values []float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		b, _ := io.ReadAll(r.Body)
+		for _, ts := range fields(t, snappyDecode(t, b))[1] {
+			labels := map[string]string{}
+			for _, l := range fields(t, ts)[1] {
+				f := fields(t, l)
+				labels[string(f[1][0])] = string(f[2][0])
+			}
+			series = append(series, labels)
+			point := fields(t, fields(t, ts)[2][0])
+			values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(point[1][0])))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	reporter, err := New(Options{URL: server.URL, Protocol: RemoteWrite, Grouping: map[string]string{"env": "prod"}})
+	require.NoError(t, err)
+	observe(reporter)
+	require.NoError(t, reporter.Push(context.Background()))
+
+	require.Len(t, series, len(metrics)*3)
+	assert.Equal(t, map[string]string{"__name__": "nexus_client_queries_total", "job": "nexus_client", "env": "prod", "operation": "read"}, series[0])
+	assert.Equal(t, map[string]string{
+		"__name__": "nexus_client_queries_total", "job": "nexus_client", "env": "prod", "label": "Person", "operation": "write",
+	}, series[2])
+	assert.Equal(t, []float64{1, 1, 2}, values[:3])
+}
+
+func TestRunPushesOnStop(t *testing.T) {
+	pushes := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		pushes <- string(b)
+	}))
+	defer server.Close()
+	reporter, err := New(Options{URL: server.URL, Interval: time.Hour})
+	require.NoError(t, err)
+	observe(reporter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, reporter.Run(ctx), context.Canceled)
+	assert.True(t, strings.Contains(<-pushes, "nexus_client_queries_total"))
+}
+
+// snappyDecode decodes the literal-only snappy blocks snappyEncode
+// writes.
+func snappyDecode(t *testing.T, b []byte) []byte {
+	n, k := binary.Uvarint(b)
+	b = b[k:]
+	var out []byte
+	for len(b) > 0 {
+		require.Zero(t, b[0]&3, "literal tag")
+		tag := int(b[0] >> 2)
+		b = b[1:]
+		size := tag + 1
+		switch tag {
+		case 60:
+			size, b = int(b[0])+1, b[1:]
+		case 61:
+			size, b = int(binary.LittleEndian.Uint16(b))+1, b[2:]
+		}
+		out, b = append(out, b[:size]...), b[size:]
+	}
+	require.Equal(t, int(n), len(out))
+	return out
+}
+
+// fields splits a protobuf message into its raw field values by number.
+func fields(t *testing.T, b []byte) map[int][][]byte {
+	out := map[int][][]byte{}
+	for len(b) > 0 {
+		key, k := binary.Uvarint(b)
+		b = b[k:]
+		field := int(key >> 3)
+		switch key & 7 {
+		case 0:
+			_, k := binary.Uvarint(b)
+			out[field], b = append(out[field], b[:k]), b[k:]
+		case 1:
+			out[field], b = append(out[field], b[:8]), b[8:]
+		case 2:
+			n, k := binary.Uvarint(b)
+			b = b[k:]
+			out[field], b = append(out[field], b[:n]), b[n:]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+	}
+	return out
+}
@@ -0,0 +1,298 @@
+// Package quality runs data quality audits against a Nexus graph.
+//
+// An audit is a list of Checks. Each check pages through the graph with
+// id-ordered Cypher queries and emits Violations as it finds them, so
+// audits over large graphs stream in bounded memory:
+//
+//	report, err := quality.Run(ctx, client, []quality.Check{
+//	    &quality.OrphanNodes{Label: "Customer"},
+//	    &quality.DuplicateKeys{Label: "Customer", Property: "email"},
+//	    &quality.NullRequired{Label: "Order", Properties: []string{"total"}},
+//	    &quality.DanglingReferences{Label: "Order", Property: "customer_id",
+//	        TargetLabel: "Customer", TargetProperty: "id"},
+//	})
+package quality
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+	"github.com/hivellm/nexus-go/internal/ids"
+)
+
+// DefaultPageSize is the number of rows fetched per query when a check
+// leaves PageSize at zero.
+const DefaultPageSize = 1000
+
+// Querier is the subset of *nexus.Client the checks need. Transactions
+// and retrying clients satisfy it too.
+type Querier interface {
+	ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error)
+}
+
+// Violation is a single data quality finding.
+type Violation struct {
+	Check    string
+	NodeID   string
+	Label    string
+	Property string
+	Message  string
+	// Details carries check-specific context (duplicate key value,
+	// the other node ids sharing it, …).
+	Details map[string]interface{}
+}
+
+// Check is one audit rule.
+type Check interface {
+	// Name identifies the check in reports.
+	Name() string
+	// Run scans the graph and calls emit once per violation. Returning
+	// an error from emit aborts the check.
+	Run(ctx context.Context, q Querier, emit func(Violation) error) error
+}
+
+// Report is the result of Run.
+type Report struct {
+	Violations []Violation
+	// Counts maps check name to its number of violations (checks with
+	// none are present with 0).
+	Counts map[string]int
+}
+
+// ErrStop may be returned from a Stream callback to end the audit early
+// without an error.
+var ErrStop = errors.New("quality: stop")
+
+// Run executes checks in order and collects every violation.
+func Run(ctx context.Context, q Querier, checks []Check) (*Report, error) {
+	report := &Report{Counts: make(map[string]int, len(checks))}
+	for _, c := range checks {
+		report.Counts[c.Name()] = 0
+	}
+	err := Stream(ctx, q, checks, func(v Violation) error {
+		report.Violations = append(report.Violations, v)
+		report.Counts[v.Check]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Stream executes checks in order, handing each violation to fn as soon
+// as it is found. Return ErrStop from fn to end the audit early.
+func Stream(ctx context.Context, q Querier, checks []Check, fn func(Violation) error) error {
+	for _, c := range checks {
+		name := c.Name()
+		err := c.Run(ctx, q, func(v Violation) error {
+			v.Check = name
+			return fn(v)
+		})
+		if errors.Is(err, ErrStop) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("quality: check %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// OrphanNodes reports nodes of Label without any relationship.
+type OrphanNodes struct {
+	Label    string
+	PageSize int
+}
+
+func (c *OrphanNodes) Name() string { return "orphan_nodes:" + c.Label }
+
+func (c *OrphanNodes) Run(ctx context.Context, q Querier, emit func(Violation) error) error {
+	match := fmt.Sprintf("MATCH (n:%s) WHERE NOT (n)--() AND id(n) > $after", quoteIdent(c.Label))
+	return pageByID(ctx, q, match, "", nil, c.PageSize, func(row []interface{}) error {
+		return emit(Violation{
+			NodeID:  ids.Format(row[0]),
+			Label:   c.Label,
+			Message: "node has no relationships",
+		})
+	})
+}
+
+// NullRequired reports nodes of Label missing any of Properties.
+type NullRequired struct {
+	Label      string
+	Properties []string
+	PageSize   int
+}
+
+func (c *NullRequired) Name() string { return "null_required:" + c.Label }
+
+func (c *NullRequired) Run(ctx context.Context, q Querier, emit func(Violation) error) error {
+	if len(c.Properties) == 0 {
+		return errors.New("no properties given")
+	}
+	nulls := make([]string, len(c.Properties))
+	for i, p := range c.Properties {
+		nulls[i] = fmt.Sprintf("n.%s IS NULL", quoteIdent(p))
+	}
+	match := fmt.Sprintf("MATCH (n:%s) WHERE (%s) AND id(n) > $after",
+		quoteIdent(c.Label), strings.Join(nulls, " OR "))
+	// Project each null test so one row reports every missing property.
+	extra := ", " + strings.Join(nulls, ", ")
+	return pageByID(ctx, q, match, extra, nil, c.PageSize, func(row []interface{}) error {
+		for i, p := range c.Properties {
+			if missing, _ := row[i+1].(bool); !missing {
+				continue
+			}
+			if err := emit(Violation{
+				NodeID:   ids.Format(row[0]),
+				Label:    c.Label,
+				Property: p,
+				Message:  "required property is null or missing",
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DanglingReferences reports nodes whose Property holds a key that no
+// TargetLabel node carries in TargetProperty — a reference "by
+// convention" (for example Order.customer_id → Customer.id) rather
+// than a relationship.
+type DanglingReferences struct {
+	Label          string
+	Property       string
+	TargetLabel    string
+	TargetProperty string
+	PageSize       int
+}
+
+func (c *DanglingReferences) Name() string {
+	return fmt.Sprintf("dangling_references:%s.%s", c.Label, c.Property)
+}
+
+func (c *DanglingReferences) Run(ctx context.Context, q Querier, emit func(Violation) error) error {
+	prop := quoteIdent(c.Property)
+	match := fmt.Sprintf(
+		"MATCH (n:%s) WHERE n.%s IS NOT NULL AND id(n) > $after "+
+			"OPTIONAL MATCH (t:%s) WHERE t.%s = n.%s "+
+			"WITH n, count(t) AS targets WHERE targets = 0",
+		quoteIdent(c.Label), prop, quoteIdent(c.TargetLabel), quoteIdent(c.TargetProperty), prop)
+	return pageByID(ctx, q, match, ", n."+prop, nil, c.PageSize, func(row []interface{}) error {
+		return emit(Violation{
+			NodeID:   ids.Format(row[0]),
+			Label:    c.Label,
+			Property: c.Property,
+			Message:  fmt.Sprintf("no %s node with %s = %v", c.TargetLabel, c.TargetProperty, row[1]),
+			Details:  map[string]interface{}{"value": row[1]},
+		})
+	})
+}
+
+// DuplicateKeys reports nodes of Label sharing a value of Property that
+// should be unique. One violation is emitted per duplicated value.
+type DuplicateKeys struct {
+	Label    string
+	Property string
+	PageSize int
+}
+
+func (c *DuplicateKeys) Name() string {
+	return fmt.Sprintf("duplicate_keys:%s.%s", c.Label, c.Property)
+}
+
+func (c *DuplicateKeys) Run(ctx context.Context, q Querier, emit func(Violation) error) error {
+	pageSize := c.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	prop := quoteIdent(c.Property)
+	query := fmt.Sprintf(
+		"MATCH (n:%s) WHERE n.%s IS NOT NULL "+
+			"WITH n.%s AS key, collect(id(n)) AS ids WHERE size(ids) > 1 "+
+			"RETURN key, ids ORDER BY key SKIP $skip LIMIT $limit",
+		quoteIdent(c.Label), prop, prop)
+
+	for skip := 0; ; skip += pageSize {
+		result, err := q.ExecuteCypher(ctx, query, map[string]interface{}{"skip": skip, "limit": pageSize})
+		if err != nil {
+			return err
+		}
+		for _, row := range result.Rows {
+			if len(row) < 2 {
+				continue
+			}
+			nodeIDs, _ := row[1].([]interface{})
+			first := ""
+			if len(nodeIDs) > 0 {
+				first = ids.Format(nodeIDs[0])
+			}
+			if err := emit(Violation{
+				NodeID:   first,
+				Label:    c.Label,
+				Property: c.Property,
+				Message:  fmt.Sprintf("value %v is shared by %d nodes", row[0], len(nodeIDs)),
+				Details:  map[string]interface{}{"value": row[0], "node_ids": nodeIDs},
+			}); err != nil {
+				return err
+			}
+		}
+		if len(result.Rows) < pageSize {
+			return nil
+		}
+	}
+}
+
+// pageByID runs `match RETURN id(n)<extra>` in id-ordered pages, calling
+// fn with each row. match must constrain `id(n) > $after`.
+func pageByID(ctx context.Context, q Querier, match, extra string, params map[string]interface{}, pageSize int, fn func([]interface{}) error) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	query := fmt.Sprintf("%s RETURN id(n) AS id%s ORDER BY id(n) LIMIT $limit", match, extra)
+	after := int64(-1)
+	for {
+		p := map[string]interface{}{"after": after, "limit": pageSize}
+		for k, v := range params {
+			p[k] = v
+		}
+		result, err := q.ExecuteCypher(ctx, query, p)
+		if err != nil {
+			return err
+		}
+		for _, row := range result.Rows {
+			if len(row) == 0 {
+				continue
+			}
+			after = toInt64(row[0])
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		if len(result.Rows) < pageSize {
+			return nil
+		}
+	}
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+// quoteIdent quotes a label, type or property name for interpolation
+// into Cypher text.
+func quoteIdent(name string) string { return cypherlex.QuoteName(name) }
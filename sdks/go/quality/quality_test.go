@@ -0,0 +1,65 @@
+package quality
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuerier struct {
+	queries []string
+	respond func(query string, params map[string]interface{}) *nexus.QueryResult
+}
+
+func (f *fakeQuerier) ExecuteCypher(_ context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error) {
+	f.queries = append(f.queries, query)
+	return f.respond(query, params), nil
+}
+
+func TestRunPaginatesAndCounts(t *testing.T) {
+	q := &fakeQuerier{respond: func(query string, params map[string]interface{}) *nexus.QueryResult {
+		switch {
+		case strings.Contains(query, "NOT (n)--()"):
+			if params["after"].(int64) < 0 {
+				return &nexus.QueryResult{Rows: [][]interface{}{{int64(1)}, {int64(2)}}}
+			}
+			return &nexus.QueryResult{Rows: [][]interface{}{{int64(5)}}}
+		case strings.Contains(query, "collect(id(n))"):
+			return &nexus.QueryResult{Rows: [][]interface{}{{"a@x", []interface{}{int64(3), int64(4)}}}}
+		}
+		return &nexus.QueryResult{}
+	}}
+
+	report, err := Run(context.Background(), q, []Check{
+		&OrphanNodes{Label: "Customer", PageSize: 2},
+		&DuplicateKeys{Label: "Customer", Property: "email"},
+		&NullRequired{Label: "Order", Properties: []string{"total"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Counts["orphan_nodes:Customer"])
+	assert.Equal(t, 1, report.Counts["duplicate_keys:Customer.email"])
+	assert.Equal(t, 0, report.Counts["null_required:Order"])
+	assert.Equal(t, "5", report.Violations[2].NodeID)
+	assert.Equal(t, "3", report.Violations[3].NodeID)
+	assert.Len(t, q.queries, 4)
+}
+
+func TestStreamStop(t *testing.T) {
+	q := &fakeQuerier{respond: func(string, map[string]interface{}) *nexus.QueryResult {
+		return &nexus.QueryResult{Rows: [][]interface{}{{int64(1)}, {int64(2)}}}
+	}}
+
+	seen := 0
+	err := Stream(context.Background(), q, []Check{&OrphanNodes{Label: "X"}}, func(Violation) error {
+		seen++
+		return ErrStop
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, seen)
+}
@@ -0,0 +1,188 @@
+package nexus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// VectorQuantization selects a compact encoding for embeddings. Both
+// encodings trade a little recall for memory and bandwidth: float16
+// halves the size of float32 vectors, int8 quarters it.
+type VectorQuantization string
+
+const (
+	// QuantizationNone keeps full float32 vectors.
+	QuantizationNone VectorQuantization = ""
+	// QuantizationFloat16 stores IEEE 754 half-precision floats, 2 bytes
+	// per dimension, accurate to about three significant digits.
+	QuantizationFloat16 VectorQuantization = "float16"
+	// QuantizationInt8 stores symmetric scalar-quantized bytes, 1 byte
+	// per dimension plus a 4-byte scale per vector.
+	QuantizationInt8 VectorQuantization = "int8"
+)
+
+// EncodeVector packs v into the little-endian wire form of q, suitable
+// for a bytes property or a bulk upload:
+//
+//	float16: 2 bytes per dimension
+//	int8:    float32 scale, then 1 signed byte per dimension
+//
+// QuantizationNone packs plain float32s (4 bytes per dimension).
+func EncodeVector(v []float32, q VectorQuantization) ([]byte, error) {
+	switch q {
+	case QuantizationNone:
+		out := make([]byte, 4*len(v))
+		for i, f := range v {
+			binary.LittleEndian.PutUint32(out[4*i:], math.Float32bits(f))
+		}
+		return out, nil
+	case QuantizationFloat16:
+		out := make([]byte, 2*len(v))
+		for i, f := range v {
+			binary.LittleEndian.PutUint16(out[2*i:], Float16FromFloat32(f))
+		}
+		return out, nil
+	case QuantizationInt8:
+		qv := QuantizeInt8(v)
+		out := make([]byte, 4+len(v))
+		binary.LittleEndian.PutUint32(out, math.Float32bits(qv.Scale))
+		for i, b := range qv.Values {
+			out[4+i] = byte(b)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("nexus: unknown vector quantization %q", q)
+}
+
+// DecodeVector unpacks a vector written by EncodeVector.
+func DecodeVector(data []byte, q VectorQuantization) ([]float32, error) {
+	switch q {
+	case QuantizationNone:
+		if len(data)%4 != 0 {
+			return nil, fmt.Errorf("nexus: float32 vector length %d is not a multiple of 4", len(data))
+		}
+		out := make([]float32, len(data)/4)
+		for i := range out {
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+		}
+		return out, nil
+	case QuantizationFloat16:
+		if len(data)%2 != 0 {
+			return nil, fmt.Errorf("nexus: float16 vector length %d is not a multiple of 2", len(data))
+		}
+		out := make([]float32, len(data)/2)
+		for i := range out {
+			out[i] = Float16ToFloat32(binary.LittleEndian.Uint16(data[2*i:]))
+		}
+		return out, nil
+	case QuantizationInt8:
+		if len(data) < 4 {
+			return nil, fmt.Errorf("nexus: int8 vector of %d bytes has no scale", len(data))
+		}
+		qv := Int8Vector{Scale: math.Float32frombits(binary.LittleEndian.Uint32(data)), Values: make([]int8, len(data)-4)}
+		for i, b := range data[4:] {
+			qv.Values[i] = int8(b)
+		}
+		return qv.Float32(), nil
+	}
+	return nil, fmt.Errorf("nexus: unknown vector quantization %q", q)
+}
+
+// Int8Vector is a symmetric scalar-quantized vector: element i is
+// approximately Values[i] * Scale.
+type Int8Vector struct {
+	Values []int8
+	Scale  float32
+}
+
+// QuantizeInt8 maps v onto [-127, 127] using its largest magnitude as
+// the scale.
+func QuantizeInt8(v []float32) Int8Vector {
+	var maxAbs float32
+	for _, f := range v {
+		if a := float32(math.Abs(float64(f))); a > maxAbs {
+			maxAbs = a
+		}
+	}
+	qv := Int8Vector{Values: make([]int8, len(v))}
+	if maxAbs == 0 {
+		return qv
+	}
+	qv.Scale = maxAbs / 127
+	for i, f := range v {
+		qv.Values[i] = int8(math.Round(float64(f / qv.Scale)))
+	}
+	return qv
+}
+
+// Float32 restores the approximate float32 vector.
+func (q Int8Vector) Float32() []float32 {
+	out := make([]float32, len(q.Values))
+	for i, b := range q.Values {
+		out[i] = float32(b) * q.Scale
+	}
+	return out
+}
+
+// Float16FromFloat32 converts f to IEEE 754 half precision, rounding to
+// nearest even. Values beyond ±65504 become ±Inf.
+func Float16FromFloat32(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23&0xff) - 127 + 15
+	mant := bits & 0x7fffff
+
+	switch {
+	case bits&0x7fffffff == 0:
+		return sign
+	case bits>>23&0xff == 0xff: // Inf or NaN
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	case exp >= 0x1f:
+		return sign | 0x7c00
+	case exp <= 0:
+		// Subnormal half, or too small: shift the implicit bit in.
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem := mant & (1<<shift - 1)
+		mid := uint32(1) << (shift - 1)
+		if rem > mid || (rem == mid && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+	half := uint16(exp)<<10 | uint16(mant>>13)
+	rem := mant & 0x1fff
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++ // may carry into the exponent, up to Inf
+	}
+	return sign | half
+}
+
+// Float16ToFloat32 widens an IEEE 754 half-precision value.
+func Float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := int32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch {
+	case exp == 0 && mant == 0:
+		return math.Float32frombits(sign)
+	case exp == 0: // subnormal: normalise the mantissa
+		exp = 1
+		for mant&0x400 == 0 {
+			mant <<= 1
+			exp--
+		}
+		mant &= 0x3ff
+	case exp == 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	}
+	return math.Float32frombits(sign | uint32(exp+127-15)<<23 | mant<<13)
+}
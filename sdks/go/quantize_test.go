@@ -0,0 +1,93 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloat16Conversion(t *testing.T) {
+	cases := map[float32]uint16{
+		0:             0x0000,
+		1:             0x3c00,
+		-2:            0xc000,
+		0.5:           0x3800,
+		65504:         0x7bff,
+		1e6:           0x7c00, // overflow to +Inf
+		5.960464e-08:  0x0001, // smallest subnormal
+		6.1035156e-5:  0x0400, // smallest normal
+		1.0009765625:  0x3c01, // one ulp above 1
+		1.00048828125: 0x3c00, // tie rounds to even
+	}
+	for f, want := range cases {
+		assert.Equal(t, want, Float16FromFloat32(f), "encode %v", f)
+	}
+	for _, h := range []uint16{0x0000, 0x0001, 0x03ff, 0x0400, 0x3c00, 0x3c01, 0xc000, 0x7bff, 0x7c00, 0xfc00} {
+		assert.Equal(t, h, Float16FromFloat32(Float16ToFloat32(h)), "round trip %#04x", h)
+	}
+	assert.True(t, math.IsNaN(float64(Float16ToFloat32(Float16FromFloat32(float32(math.NaN()))))))
+}
+
+func TestEncodeVectorRoundTrips(t *testing.T) {
+	v := []float32{0.12, -0.5, 0.99, 0, -1}
+	for _, q := range []VectorQuantization{QuantizationNone, QuantizationFloat16, QuantizationInt8} {
+		data, err := EncodeVector(v, q)
+		require.NoError(t, err)
+		got, err := DecodeVector(data, q)
+		require.NoError(t, err)
+		require.Len(t, got, len(v))
+		for i := range v {
+			assert.InDelta(t, v[i], got[i], 0.005, "%s[%d]", q, i)
+		}
+	}
+
+	data, _ := EncodeVector(v, QuantizationFloat16)
+	assert.Len(t, data, 10)
+	data, _ = EncodeVector(v, QuantizationInt8)
+	assert.Len(t, data, 9)
+
+	_, err := EncodeVector(v, "int4")
+	assert.Error(t, err)
+	_, err = DecodeVector([]byte{1, 2, 3}, QuantizationFloat16)
+	assert.Error(t, err)
+}
+
+func TestQuantizeInt8(t *testing.T) {
+	qv := QuantizeInt8([]float32{2, -1, 0})
+	assert.Equal(t, []int8{127, -64, 0}, qv.Values)
+	assert.InDelta(t, 2.0/127, qv.Scale, 1e-9)
+
+	zero := QuantizeInt8([]float32{0, 0})
+	assert.Equal(t, []float32{0, 0}, zero.Float32())
+}
+
+func TestCreateVectorIndexQuantization(t *testing.T) {
+	var options map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Options map[string]interface{} `json:"options"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		options = req.Options
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	err := client.CreateVectorIndex(context.Background(), VectorIndexOptions{
+		Name: "emb", Label: "Doc", Property: "embedding", Dimensions: 384, Quantization: QuantizationInt8,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "int8", options["quantization"])
+
+	err = client.CreateVectorIndex(context.Background(), VectorIndexOptions{
+		Name: "emb", Label: "Doc", Property: "embedding", Dimensions: 384, Quantization: "pq",
+	})
+	assert.Error(t, err)
+}
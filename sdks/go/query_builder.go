@@ -7,138 +7,283 @@ import (
 )
 
 // QueryBuilder provides a fluent API for constructing Cypher queries.
+//
+// Clauses are emitted in the order they are added, so queries can chain
+// MATCH … WITH … UNWIND … MATCH … RETURN. Where, And and Or constrain
+// the latest MATCH, OPTIONAL MATCH, WITH or CALL … YIELD; OrderBy, Skip and Limit
+// apply to the latest WITH or RETURN. Either kind added before its
+// clause waits for the next one.
+//
+// Values never go into the query text: Param and the pattern builders
+// bind them as generated parameters ($p1, $p2, …) that Build returns
+// alongside the query.
 type QueryBuilder struct {
-	matchClauses   []string
-	whereClauses   []string
-	createClauses  []string
-	setClauses     []string
-	deleteClauses  []string
-	returnClauses  []string
-	orderByClauses []string
-	skipValue      *int
-	limitValue     *int
-	parameters     map[string]interface{}
+	clauses    []*queryClause
+	pending    queryClause // Where/OrderBy/Skip/Limit waiting for their clause
+	parameters map[string]interface{}
+	nextParam  int
+	rowFilters []RowFilter
+}
+
+// queryClause is one clause of a QueryBuilder query.
+type queryClause struct {
+	keyword  string // MATCH, OPTIONAL MATCH, WITH, UNWIND, CALL, CREATE, …
+	body     string
+	yield    []string // CALL
+	onCreate []string // MERGE
+	onMatch  []string // MERGE
+	where    []string // MATCH, OPTIONAL MATCH, WITH and CALL … YIELD
+	orderBy  []string // WITH and RETURN
+	skip     *int
+	limit    *int
 }
 
 // NewQueryBuilder creates a new QueryBuilder instance.
 func NewQueryBuilder() *QueryBuilder {
 	return &QueryBuilder{
-		matchClauses:   make([]string, 0),
-		whereClauses:   make([]string, 0),
-		createClauses:  make([]string, 0),
-		setClauses:     make([]string, 0),
-		deleteClauses:  make([]string, 0),
-		returnClauses:  make([]string, 0),
-		orderByClauses: make([]string, 0),
-		parameters:     make(map[string]interface{}),
+		parameters: make(map[string]interface{}),
 	}
 }
 
+// add appends a clause, handing it the pending conditions or
+// modifiers it takes.
+func (qb *QueryBuilder) add(keyword, body string) *QueryBuilder {
+	c := &queryClause{keyword: keyword, body: body}
+	if c.filterable() {
+		c.where, qb.pending.where = qb.pending.where, nil
+	}
+	if c.projection() {
+		c.orderBy, c.skip, c.limit = qb.pending.orderBy, qb.pending.skip, qb.pending.limit
+		qb.pending.orderBy, qb.pending.skip, qb.pending.limit = nil, nil, nil
+	}
+	if c.keyword == "MERGE" {
+		c.onCreate, c.onMatch = qb.pending.onCreate, qb.pending.onMatch
+		qb.pending.onCreate, qb.pending.onMatch = nil, nil
+	}
+	qb.clauses = append(qb.clauses, c)
+	return qb
+}
+
+// last returns the latest clause matching keep, or the pending clause.
+func (qb *QueryBuilder) last(keep func(*queryClause) bool) *queryClause {
+	for i := len(qb.clauses) - 1; i >= 0; i-- {
+		if keep(qb.clauses[i]) {
+			return qb.clauses[i]
+		}
+	}
+	return &qb.pending
+}
+
+// filterable reports whether the clause takes a WHERE.
+func (c *queryClause) filterable() bool {
+	return c.keyword == "MATCH" || c.keyword == "OPTIONAL MATCH" || c.keyword == "WITH" ||
+		c.keyword == "CALL" && len(c.yield) > 0
+}
+
+// projection reports whether the clause takes ORDER BY, SKIP and LIMIT.
+func (c *queryClause) projection() bool {
+	return c.keyword == "WITH" || c.keyword == "RETURN"
+}
+
 // Match adds a MATCH clause to the query.
 func (qb *QueryBuilder) Match(pattern string) *QueryBuilder {
-	qb.matchClauses = append(qb.matchClauses, pattern)
-	return qb
+	return qb.add("MATCH", pattern)
 }
 
 // OptionalMatch adds an OPTIONAL MATCH clause to the query.
 func (qb *QueryBuilder) OptionalMatch(pattern string) *QueryBuilder {
-	qb.matchClauses = append(qb.matchClauses, "OPTIONAL MATCH "+pattern)
+	return qb.add("OPTIONAL MATCH", pattern)
+}
+
+// With adds a WITH clause projecting items to the rest of the query.
+func (qb *QueryBuilder) With(items ...string) *QueryBuilder {
+	return qb.add("WITH", strings.Join(items, ", "))
+}
+
+// WithDistinct adds a WITH DISTINCT clause.
+func (qb *QueryBuilder) WithDistinct(items ...string) *QueryBuilder {
+	return qb.add("WITH", "DISTINCT "+strings.Join(items, ", "))
+}
+
+// Unwind adds an UNWIND clause binding each element of list to alias;
+// list is an expression, e.g. a Param placeholder.
+func (qb *QueryBuilder) Unwind(list, alias string) *QueryBuilder {
+	return qb.add("UNWIND", list+" AS "+alias)
+}
+
+// Call adds a CALL clause invoking a procedure, e.g. Call("db.labels()").
+func (qb *QueryBuilder) Call(procedure string) *QueryBuilder {
+	return qb.add("CALL", procedure)
+}
+
+// CallWithArgs adds a CALL clause invoking procedure with args, bound
+// as generated parameters:
+//
+//	qb.CallWithArgs("gds.pageRank", "Person", 20).Yield("node", "score")
+func (qb *QueryBuilder) CallWithArgs(procedure string, args ...interface{}) *QueryBuilder {
+	placeholders := make([]string, len(args))
+	for i, arg := range args {
+		placeholders[i] = qb.Param(arg)
+	}
+	return qb.add("CALL", procedure+"("+strings.Join(placeholders, ", ")+")")
+}
+
+// Yield adds YIELD items to the latest CALL. Where then filters the
+// yielded rows.
+func (qb *QueryBuilder) Yield(items ...string) *QueryBuilder {
+	c := qb.last(func(c *queryClause) bool { return c.keyword == "CALL" })
+	if c == &qb.pending {
+		return qb
+	}
+	c.yield = append(c.yield, items...)
 	return qb
 }
 
-// Where adds a WHERE clause to the query.
+// Where adds a condition to the latest MATCH, OPTIONAL MATCH, WITH or
+// CALL … YIELD.
+// Conditions on the same clause are joined with AND.
 func (qb *QueryBuilder) Where(condition string) *QueryBuilder {
-	qb.whereClauses = append(qb.whereClauses, condition)
+	c := qb.last((*queryClause).filterable)
+	c.where = append(c.where, condition)
 	return qb
 }
 
+// WherePredicate adds a Predicate as a condition, like Where, binding
+// its values as parameters.
+func (qb *QueryBuilder) WherePredicate(p Predicate) *QueryBuilder {
+	return qb.Where(p.Build(qb))
+}
+
 // And adds an AND condition to the WHERE clause.
 func (qb *QueryBuilder) And(condition string) *QueryBuilder {
-	if len(qb.whereClauses) > 0 {
-		qb.whereClauses[len(qb.whereClauses)-1] += " AND " + condition
+	c := qb.last((*queryClause).filterable)
+	if len(c.where) > 0 {
+		c.where[len(c.where)-1] += " AND " + condition
 	} else {
-		qb.whereClauses = append(qb.whereClauses, condition)
+		c.where = append(c.where, condition)
 	}
 	return qb
 }
 
 // Or adds an OR condition to the WHERE clause.
 func (qb *QueryBuilder) Or(condition string) *QueryBuilder {
-	if len(qb.whereClauses) > 0 {
-		qb.whereClauses[len(qb.whereClauses)-1] += " OR " + condition
+	c := qb.last((*queryClause).filterable)
+	if len(c.where) > 0 {
+		c.where[len(c.where)-1] += " OR " + condition
 	} else {
-		qb.whereClauses = append(qb.whereClauses, condition)
+		c.where = append(c.where, condition)
 	}
 	return qb
 }
 
 // Create adds a CREATE clause to the query.
 func (qb *QueryBuilder) Create(pattern string) *QueryBuilder {
-	qb.createClauses = append(qb.createClauses, pattern)
-	return qb
+	return qb.add("CREATE", pattern)
 }
 
 // Merge adds a MERGE clause to the query.
 func (qb *QueryBuilder) Merge(pattern string) *QueryBuilder {
-	qb.createClauses = append(qb.createClauses, "MERGE "+pattern)
+	return qb.add("MERGE", pattern)
+}
+
+// OnCreateSet adds ON CREATE SET assignments to the latest MERGE,
+// applied only when it creates the pattern.
+func (qb *QueryBuilder) OnCreateSet(assignments ...string) *QueryBuilder {
+	c := qb.last(isMerge)
+	c.onCreate = append(c.onCreate, assignments...)
 	return qb
 }
 
-// Set adds a SET clause to the query.
-func (qb *QueryBuilder) Set(assignment string) *QueryBuilder {
-	qb.setClauses = append(qb.setClauses, assignment)
+// OnMatchSet adds ON MATCH SET assignments to the latest MERGE, applied
+// only when the pattern already exists.
+func (qb *QueryBuilder) OnMatchSet(assignments ...string) *QueryBuilder {
+	c := qb.last(isMerge)
+	c.onMatch = append(c.onMatch, assignments...)
 	return qb
 }
 
+// MergeNode adds an upsert of node: a MERGE on its variable, labels and
+// properties (the match keys), setting props, bound as parameters, both
+// when the node is created and when it is matched. OnCreateSet and
+// OnMatchSet add to either side:
+//
+//	user := nexus.NewNodePattern("u").WithLabel("User").WithProperty("email", email)
+//	qb.MergeNode(user, map[string]interface{}{"name": name}).
+//		OnCreateSet("u.created_at = timestamp()").
+//		Return("u")
+func (qb *QueryBuilder) MergeNode(node *NodePattern, props map[string]interface{}) *QueryBuilder {
+	qb.Merge(node.Build(qb))
+	if len(props) == 0 {
+		return qb
+	}
+	assignments := make([]string, 0, len(props))
+	for _, k := range sortedKeys(props) {
+		assignments = append(assignments, node.variable+"."+cypherKey(k)+" = "+qb.Param(props[k]))
+	}
+	return qb.OnCreateSet(assignments...).OnMatchSet(assignments...)
+}
+
+func isMerge(c *queryClause) bool { return c.keyword == "MERGE" }
+
+// Set adds a SET clause to the query; consecutive calls share one SET.
+func (qb *QueryBuilder) Set(assignment string) *QueryBuilder {
+	if n := len(qb.clauses); n > 0 && qb.clauses[n-1].keyword == "SET" {
+		qb.clauses[n-1].body += ", " + assignment
+		return qb
+	}
+	return qb.add("SET", assignment)
+}
+
 // Delete adds a DELETE clause to the query.
 func (qb *QueryBuilder) Delete(items string) *QueryBuilder {
-	qb.deleteClauses = append(qb.deleteClauses, items)
-	return qb
+	return qb.add("DELETE", items)
 }
 
 // DetachDelete adds a DETACH DELETE clause to the query.
 func (qb *QueryBuilder) DetachDelete(items string) *QueryBuilder {
-	qb.deleteClauses = append(qb.deleteClauses, "DETACH DELETE "+items)
-	return qb
+	return qb.add("DETACH DELETE", items)
 }
 
-// Return adds a RETURN clause to the query.
+// Return adds a RETURN clause to the query; consecutive calls share one
+// RETURN.
 func (qb *QueryBuilder) Return(items ...string) *QueryBuilder {
-	qb.returnClauses = append(qb.returnClauses, items...)
-	return qb
+	if n := len(qb.clauses); n > 0 && qb.clauses[n-1].keyword == "RETURN" {
+		qb.clauses[n-1].body += ", " + strings.Join(items, ", ")
+		return qb
+	}
+	return qb.add("RETURN", strings.Join(items, ", "))
 }
 
 // ReturnDistinct adds a RETURN DISTINCT clause to the query.
 func (qb *QueryBuilder) ReturnDistinct(items ...string) *QueryBuilder {
-	if len(qb.returnClauses) == 0 {
-		qb.returnClauses = append(qb.returnClauses, "DISTINCT "+strings.Join(items, ", "))
-	} else {
-		qb.returnClauses = append(qb.returnClauses, items...)
+	if n := len(qb.clauses); n > 0 && qb.clauses[n-1].keyword == "RETURN" {
+		return qb.Return(items...)
 	}
-	return qb
+	return qb.add("RETURN", "DISTINCT "+strings.Join(items, ", "))
 }
 
-// OrderBy adds an ORDER BY clause to the query.
+// OrderBy adds ORDER BY items to the latest WITH or RETURN.
 func (qb *QueryBuilder) OrderBy(items ...string) *QueryBuilder {
-	qb.orderByClauses = append(qb.orderByClauses, items...)
+	c := qb.last((*queryClause).projection)
+	c.orderBy = append(c.orderBy, items...)
 	return qb
 }
 
-// OrderByDesc adds an ORDER BY ... DESC clause to the query.
+// OrderByDesc adds an ORDER BY ... DESC item to the latest WITH or
+// RETURN.
 func (qb *QueryBuilder) OrderByDesc(item string) *QueryBuilder {
-	qb.orderByClauses = append(qb.orderByClauses, item+" DESC")
-	return qb
+	return qb.OrderBy(item + " DESC")
 }
 
-// Skip adds a SKIP clause to the query.
+// Skip sets the SKIP of the latest WITH or RETURN.
 func (qb *QueryBuilder) Skip(n int) *QueryBuilder {
-	qb.skipValue = &n
+	qb.last((*queryClause).projection).skip = &n
 	return qb
 }
 
-// Limit adds a LIMIT clause to the query.
+// Limit sets the LIMIT of the latest WITH or RETURN.
 func (qb *QueryBuilder) Limit(n int) *QueryBuilder {
-	qb.limitValue = &n
+	qb.last((*queryClause).projection).limit = &n
 	return qb
 }
 
@@ -156,73 +301,73 @@ func (qb *QueryBuilder) WithParams(params map[string]interface{}) *QueryBuilder
 	return qb
 }
 
-// Build constructs the final Cypher query string.
-func (qb *QueryBuilder) Build() string {
-	var parts []string
-
-	// MATCH clauses
-	for _, match := range qb.matchClauses {
-		if strings.HasPrefix(match, "OPTIONAL MATCH") {
-			parts = append(parts, match)
-		} else {
-			parts = append(parts, "MATCH "+match)
+// Param binds value to a generated parameter and returns its
+// placeholder, for use in conditions and assignments:
+//
+//	qb.Match("(n:Person)").Where("n.age >= " + qb.Param(18))
+func (qb *QueryBuilder) Param(value interface{}) string {
+	for {
+		qb.nextParam++
+		name := fmt.Sprintf("p%d", qb.nextParam)
+		if _, taken := qb.parameters[name]; !taken {
+			qb.parameters[name] = value
+			return "$" + name
 		}
 	}
+}
 
-	// WHERE clauses
-	if len(qb.whereClauses) > 0 {
-		parts = append(parts, "WHERE "+strings.Join(qb.whereClauses, " AND "))
-	}
-
-	// CREATE/MERGE clauses
-	for _, create := range qb.createClauses {
-		if strings.HasPrefix(create, "MERGE") {
-			parts = append(parts, create)
-		} else {
-			parts = append(parts, "CREATE "+create)
+// Build constructs the final Cypher query and its parameters.
+func (qb *QueryBuilder) Build() (string, map[string]interface{}) {
+	var parts []string
+	generated := 0
+	for _, c := range qb.clauses {
+		body := c.body
+		where := c.where
+		if c.keyword == "MATCH" || c.keyword == "OPTIONAL MATCH" {
+			// Row filters constrain the nodes each pattern binds; the
+			// conditions are parenthesised so an OR in them cannot
+			// bypass the filters.
+			var preds []string
+			body, preds = filterPattern(body, qb.rowFilters, &generated)
+			if len(preds) > 0 && len(where) > 0 {
+				where = append([]string{"(" + strings.Join(where, " AND ") + ")"}, preds...)
+			} else if len(preds) > 0 {
+				where = preds
+			}
 		}
-	}
-
-	// SET clauses
-	if len(qb.setClauses) > 0 {
-		parts = append(parts, "SET "+strings.Join(qb.setClauses, ", "))
-	}
-
-	// DELETE clauses
-	for _, del := range qb.deleteClauses {
-		if strings.HasPrefix(del, "DETACH DELETE") {
-			parts = append(parts, del)
-		} else {
-			parts = append(parts, "DELETE "+del)
+		if c.keyword == "MERGE" {
+			body = filterMerge(body, qb.rowFilters)
 		}
-	}
-
-	// RETURN clause
-	if len(qb.returnClauses) > 0 {
-		returnStr := strings.Join(qb.returnClauses, ", ")
-		if strings.HasPrefix(returnStr, "DISTINCT ") {
-			parts = append(parts, "RETURN "+returnStr)
-		} else {
-			parts = append(parts, "RETURN "+returnStr)
+		parts = append(parts, c.keyword+" "+body)
+		if len(c.yield) > 0 {
+			parts = append(parts, "YIELD "+strings.Join(c.yield, ", "))
+		}
+		if len(c.onCreate) > 0 {
+			parts = append(parts, "ON CREATE SET "+strings.Join(c.onCreate, ", "))
+		}
+		if len(c.onMatch) > 0 {
+			parts = append(parts, "ON MATCH SET "+strings.Join(c.onMatch, ", "))
 		}
-	}
-
-	// ORDER BY clause
-	if len(qb.orderByClauses) > 0 {
-		parts = append(parts, "ORDER BY "+strings.Join(qb.orderByClauses, ", "))
-	}
 
-	// SKIP clause
-	if qb.skipValue != nil {
-		parts = append(parts, fmt.Sprintf("SKIP %d", *qb.skipValue))
+		if len(c.orderBy) > 0 {
+			parts = append(parts, "ORDER BY "+strings.Join(c.orderBy, ", "))
+		}
+		if c.skip != nil {
+			parts = append(parts, fmt.Sprintf("SKIP %d", *c.skip))
+		}
+		if c.limit != nil {
+			parts = append(parts, fmt.Sprintf("LIMIT %d", *c.limit))
+		}
+		if len(where) > 0 {
+			parts = append(parts, "WHERE "+strings.Join(where, " AND "))
+		}
 	}
 
-	// LIMIT clause
-	if qb.limitValue != nil {
-		parts = append(parts, fmt.Sprintf("LIMIT %d", *qb.limitValue))
+	params := make(map[string]interface{}, len(qb.parameters))
+	for k, v := range qb.parameters {
+		params[k] = v
 	}
-
-	return strings.Join(parts, " ")
+	return strings.Join(parts, " "), params
 }
 
 // Parameters returns the parameters map for the query.
@@ -272,8 +417,12 @@ func (np *NodePattern) WithProperties(props map[string]interface{}) *NodePattern
 	return np
 }
 
-// Build constructs the node pattern string.
-func (np *NodePattern) Build() string {
+// Build constructs the node pattern string, binding its property values
+// as parameters of qb:
+//
+//	person := nexus.NewNodePattern("n").WithLabel("Person").WithProperty("name", name)
+//	qb.Match(person.Build(qb))
+func (np *NodePattern) Build(qb *QueryBuilder) string {
 	var result strings.Builder
 	result.WriteString("(")
 	result.WriteString(np.variable)
@@ -284,18 +433,8 @@ func (np *NodePattern) Build() string {
 	}
 
 	if len(np.properties) > 0 {
-		result.WriteString(" {")
-		first := true
-		for k, v := range np.properties {
-			if !first {
-				result.WriteString(", ")
-			}
-			result.WriteString(k)
-			result.WriteString(": ")
-			result.WriteString(formatValue(v))
-			first = false
-		}
-		result.WriteString("}")
+		result.WriteString(" ")
+		result.WriteString(propertyMap(qb, np.properties))
 	}
 
 	result.WriteString(")")
@@ -345,6 +484,20 @@ func (rp *RelationshipPattern) Undirected() *RelationshipPattern {
 	return rp
 }
 
+// WithProperty adds a property to the relationship pattern.
+func (rp *RelationshipPattern) WithProperty(key string, value interface{}) *RelationshipPattern {
+	rp.properties[key] = value
+	return rp
+}
+
+// WithProperties adds multiple properties to the relationship pattern.
+func (rp *RelationshipPattern) WithProperties(props map[string]interface{}) *RelationshipPattern {
+	for k, v := range props {
+		rp.properties[k] = v
+	}
+	return rp
+}
+
 // WithHops sets variable length path hops.
 func (rp *RelationshipPattern) WithHops(min, max int) *RelationshipPattern {
 	rp.minHops = &min
@@ -364,8 +517,9 @@ func (rp *RelationshipPattern) WithMaxHops(max int) *RelationshipPattern {
 	return rp
 }
 
-// Build constructs the relationship pattern string.
-func (rp *RelationshipPattern) Build() string {
+// Build constructs the relationship pattern string, binding its
+// property values as parameters of qb.
+func (rp *RelationshipPattern) Build(qb *QueryBuilder) string {
 	var result strings.Builder
 
 	// Start arrow
@@ -394,6 +548,11 @@ func (rp *RelationshipPattern) Build() string {
 		}
 	}
 
+	if len(rp.properties) > 0 {
+		result.WriteString(" ")
+		result.WriteString(propertyMap(qb, rp.properties))
+	}
+
 	result.WriteString("]-")
 
 	// End arrow
@@ -404,23 +563,21 @@ func (rp *RelationshipPattern) Build() string {
 	return result.String()
 }
 
-// formatValue formats a value for use in Cypher queries.
-func formatValue(v interface{}) string {
-	switch val := v.(type) {
-	case string:
-		return fmt.Sprintf("'%s'", strings.ReplaceAll(val, "'", "\\'"))
-	case int, int32, int64, float32, float64:
-		return fmt.Sprintf("%v", val)
-	case bool:
-		if val {
-			return "true"
+// propertyMap renders props as a map literal whose values are bound as
+// parameters of qb, keys in sorted order.
+func propertyMap(qb *QueryBuilder, props map[string]interface{}) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, k := range sortedKeys(props) {
+		if i > 0 {
+			b.WriteString(", ")
 		}
-		return "false"
-	case nil:
-		return "null"
-	default:
-		return fmt.Sprintf("'%v'", val)
+		b.WriteString(cypherKey(k))
+		b.WriteString(": ")
+		b.WriteString(qb.Param(props[k]))
 	}
+	b.WriteString("}")
+	return b.String()
 }
 
 // Path helps build path patterns combining nodes and relationships.
@@ -0,0 +1,86 @@
+package nexus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBuilderParameters(t *testing.T) {
+	qb := NewQueryBuilder().WithParam("p2", "taken")
+	a := NewNodePattern("a").WithLabel("Person").WithProperties(map[string]interface{}{
+		"name": "O'Brien') DETACH DELETE a //", "tags": []string{"x", "y"},
+	})
+	r := NewRelPattern("r").WithType("KNOWS").WithProperty("since", 2020).WithHops(1, 2)
+	b := NewNodePattern("b").WithProperty("e-mail", "b@example.com")
+
+	query, params := qb.Match(Path(a.Build(qb), r.Build(qb), b.Build(qb))).
+		Where("b.age >= " + qb.Param(18)).
+		Return("b").
+		Build()
+	assert.Equal(t, "MATCH (a:Person {name: $p1, tags: $p3})-[r:KNOWS*1..2 {since: $p4}]->(b {`e-mail`: $p5}) "+
+		"WHERE b.age >= $p6 RETURN b", query)
+	assert.Equal(t, map[string]interface{}{
+		"p1": "O'Brien') DETACH DELETE a //", "p2": "taken", "p3": []string{"x", "y"},
+		"p4": 2020, "p5": "b@example.com", "p6": 18,
+	}, params)
+
+	// The returned map is a copy.
+	params["p1"] = "changed"
+	assert.Equal(t, "O'Brien') DETACH DELETE a //", qb.Parameters()["p1"])
+}
+
+func TestQueryBuilderClauseOrder(t *testing.T) {
+	qb := NewQueryBuilder()
+	query, params := qb.
+		Match("(p:Person)-[:BOUGHT]->(o:Order)").Where("o.total > "+qb.Param(100)).
+		With("p", "count(o) AS orders").OrderByDesc("orders").Limit(10).Where("orders > 1").
+		Unwind(qb.Param([]string{"a", "b"}), "tag").
+		OptionalMatch("(p)-[:TAGGED]->(t:Tag {name: tag})").
+		Set("p.reviewed = true").Set("p.tag_count = orders").
+		Return("p.name", "orders").Return("t").Skip(5).
+		Build()
+	assert.Equal(t, "MATCH (p:Person)-[:BOUGHT]->(o:Order) WHERE o.total > $p1 "+
+		"WITH p, count(o) AS orders ORDER BY orders DESC LIMIT 10 WHERE orders > 1 "+
+		"UNWIND $p2 AS tag "+
+		"OPTIONAL MATCH (p)-[:TAGGED]->(t:Tag {name: tag}) "+
+		"SET p.reviewed = true, p.tag_count = orders "+
+		"RETURN p.name, orders, t SKIP 5", query)
+	assert.Equal(t, map[string]interface{}{"p1": 100, "p2": []string{"a", "b"}}, params)
+
+	// Conditions and modifiers given before their clause wait for it.
+	query, _ = NewQueryBuilder().Where("n.x = 1").Match("(n)").OrderBy("n.x").Limit(3).ReturnDistinct("n").Build()
+	assert.Equal(t, "MATCH (n) WHERE n.x = 1 RETURN DISTINCT n ORDER BY n.x LIMIT 3", query)
+}
+
+func TestQueryBuilderCall(t *testing.T) {
+	query, _ := NewQueryBuilder().Call("db.labels()").Yield("label").Where("label STARTS WITH 'P'").Return("label").Build()
+	assert.Equal(t, "CALL db.labels() YIELD label WHERE label STARTS WITH 'P' RETURN label", query)
+
+	qb := NewQueryBuilder()
+	query, params := qb.Match("(p:Person)").With("collect(p) AS people").
+		CallWithArgs("gds.pageRank", "Person", 20).Yield("node", "score").
+		Return("node.name", "score").OrderByDesc("score").
+		Build()
+	assert.Equal(t, "MATCH (p:Person) WITH collect(p) AS people "+
+		"CALL gds.pageRank($p1, $p2) YIELD node, score RETURN node.name, score ORDER BY score DESC", query)
+	assert.Equal(t, map[string]interface{}{"p1": "Person", "p2": 20}, params)
+}
+
+func TestQueryBuilderMerge(t *testing.T) {
+	qb := NewQueryBuilder()
+	user := NewNodePattern("u").WithLabel("User").WithProperty("email", "a@example.com")
+	query, params := qb.MergeNode(user, map[string]interface{}{"name": "Ada", "last-login": 7}).
+		OnCreateSet("u.created_at = timestamp()").
+		Merge("(t:Team {name: "+qb.Param("core")+"})").
+		OnMatchSet("t.seen = t.seen + 1").
+		Merge("(u)-[:MEMBER_OF]->(t)").
+		Return("u", "t").
+		Build()
+	assert.Equal(t, "MERGE (u:User {email: $p1}) "+
+		"ON CREATE SET u.`last-login` = $p2, u.name = $p3, u.created_at = timestamp() "+
+		"ON MATCH SET u.`last-login` = $p2, u.name = $p3 "+
+		"MERGE (t:Team {name: $p4}) ON MATCH SET t.seen = t.seen + 1 "+
+		"MERGE (u)-[:MEMBER_OF]->(t) RETURN u, t", query)
+	assert.Equal(t, map[string]interface{}{"p1": "a@example.com", "p2": 7, "p3": "Ada", "p4": "core"}, params)
+}
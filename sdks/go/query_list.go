@@ -0,0 +1,170 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// Rules reported by QueryList.
+const (
+	RuleAllowList = "allow-list"
+	RuleDenyList  = "deny-list"
+)
+
+// QueryList restricts which Cypher a client may send. In allow-list
+// mode only registered named queries run, with exactly the parameters
+// their templates use, which locks down services exposed to untrusted
+// input. In deny-list mode registered queries and deny patterns are
+// refused and everything else runs. Install one with Config.QueryList
+// and run registered queries by name with Client.ExecuteNamed.
+//
+// Queries are compared token by token, so whitespace and comments do
+// not matter. Every query the client sends is checked, including those
+// of helper methods such as GraphSummary: register what a locked-down
+// service uses. REST calls are not Cypher and are not restricted; see
+// Config.Authorizer for those.
+type QueryList struct {
+	allow bool
+
+	mu       sync.RWMutex
+	named    map[string]string
+	byText   map[string]string
+	patterns []*regexp.Regexp
+}
+
+// NewQueryAllowList returns an allow-list: only registered queries run.
+func NewQueryAllowList() *QueryList { return newQueryList(true) }
+
+// NewQueryDenyList returns a deny-list: registered queries and deny
+// patterns are refused.
+func NewQueryDenyList() *QueryList { return newQueryList(false) }
+
+func newQueryList(allow bool) *QueryList {
+	return &QueryList{allow: allow, named: map[string]string{}, byText: map[string]string{}}
+}
+
+// Register adds a named query template. Parameters are referenced as
+// $name and bound at execution time.
+func (l *QueryList) Register(name, query string) error {
+	if name == "" || strings.TrimSpace(query) == "" {
+		return fmt.Errorf("nexus: query list entry needs a name and a query")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, dup := l.named[name]; dup {
+		return fmt.Errorf("nexus: query %q is already registered", name)
+	}
+	l.named[name] = query
+	l.byText[compactQuery(query)] = name
+	return nil
+}
+
+// DenyPattern refuses every query matching the regular expression,
+// in either mode. The pattern is matched case-insensitively against the
+// compacted query (see compactQuery), e.g. `\bDETACH DELETE\b` or `\bCALL dbms\.`.
+func (l *QueryList) DenyPattern(pattern string) error {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return fmt.Errorf("nexus: invalid deny pattern: %w", err)
+	}
+	l.mu.Lock()
+	l.patterns = append(l.patterns, re)
+	l.mu.Unlock()
+	return nil
+}
+
+// Query returns the template registered under name.
+func (l *QueryList) Query(name string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	q, ok := l.named[name]
+	return q, ok
+}
+
+// Names lists the registered queries in order.
+func (l *QueryList) Names() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return sortedKeys(l.named)
+}
+
+// ApplyPolicy implements QueryPolicy.
+func (l *QueryList) ApplyPolicy(_ context.Context, query string, params map[string]interface{}) (string, error) {
+	compact := compactQuery(query)
+	l.mu.RLock()
+	name, registered := l.byText[compact]
+	patterns := l.patterns
+	l.mu.RUnlock()
+	reject := func(rule, format string, args ...interface{}) (string, error) {
+		return "", &QueryPolicyError{Rule: rule, Query: query, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	for _, re := range patterns {
+		if re.MatchString(compact) {
+			return reject(RuleDenyList, "query matches deny pattern %s", strings.TrimPrefix(re.String(), "(?i)"))
+		}
+	}
+	if !l.allow {
+		if registered {
+			return reject(RuleDenyList, "query %q is denied", name)
+		}
+		return query, nil
+	}
+	if !registered {
+		return reject(RuleAllowList, "query is not on the allow-list")
+	}
+	used := map[string]bool{}
+	for _, p := range QueryParameters(query) {
+		used[p] = true
+		if _, ok := params[p]; !ok {
+			return reject(RuleAllowList, "query %q is missing parameter $%s", name, p)
+		}
+	}
+	var extra []string
+	for p := range params {
+		if !used[p] {
+			extra = append(extra, p)
+		}
+	}
+	if len(extra) > 0 {
+		sort.Strings(extra)
+		return reject(RuleAllowList, "query %q does not take parameters %s", name, strings.Join(extra, ", "))
+	}
+	return query, nil
+}
+
+// compactQuery joins a query's tokens with single spaces, dropping
+// comments and layout. Unlike NormalizeQuery it keeps literals and
+// keyword case, so a registered query matches only itself and not every
+// query of the same shape.
+func compactQuery(query string) string {
+	toks, _ := cypherlex.Tokenize(query)
+	texts := make([]string, len(toks))
+	for i, t := range toks {
+		texts[i] = t.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// ErrUnknownNamedQuery is returned by ExecuteNamed for names that are
+// not registered.
+var ErrUnknownNamedQuery = errors.New("nexus: unknown named query")
+
+// ExecuteNamed runs the query registered under name in Config.QueryList.
+func (c *Client) ExecuteNamed(ctx context.Context, name string, params map[string]interface{}) (*QueryResult, error) {
+	if c.queryList == nil {
+		return nil, fmt.Errorf("%w %q: the client has no Config.QueryList", ErrUnknownNamedQuery, name)
+	}
+	query, ok := c.queryList.Query(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownNamedQuery, name)
+	}
+	return c.ExecuteCypher(ctx, query, params)
+}
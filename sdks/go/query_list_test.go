@@ -0,0 +1,70 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAllowList(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		sent = append(sent, req.Query)
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	list := NewQueryAllowList()
+	require.NoError(t, list.Register("user_by_email", "MATCH (u:User {email: $email}) RETURN u"))
+	require.NoError(t, list.Register("count_users", "MATCH (u:User) RETURN count(u)"))
+	assert.Error(t, list.Register("count_users", "RETURN 1"))
+	assert.Equal(t, []string{"count_users", "user_by_email"}, list.Names())
+
+	client := NewClient(Config{BaseURL: server.URL, QueryList: list, QueryPolicy: Guardrails{DefaultLimit: 10}})
+	ctx := context.Background()
+
+	_, err := client.ExecuteNamed(ctx, "user_by_email", map[string]interface{}{"email": "a@b.c"})
+	require.NoError(t, err)
+	// Formatting differences do not matter.
+	_, err = client.ExecuteCypher(ctx, "MATCH (u:User)\n  RETURN count(u) // how many", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MATCH (u:User {email: $email}) RETURN u LIMIT 10", "MATCH (u:User)\n  RETURN count(u) LIMIT 10 // how many"}, sent)
+
+	var perr *QueryPolicyError
+	_, err = client.ExecuteCypher(ctx, "MATCH (u:User) DETACH DELETE u", nil)
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, RuleAllowList, perr.Rule)
+	_, err = client.ExecuteNamed(ctx, "user_by_email", nil)
+	assert.EqualError(t, err, `nexus: query rejected by policy allow-list: query "user_by_email" is missing parameter $email`)
+	_, err = client.ExecuteNamed(ctx, "user_by_email", map[string]interface{}{"email": "x", "admin": true})
+	assert.EqualError(t, err, `nexus: query rejected by policy allow-list: query "user_by_email" does not take parameters admin`)
+	_, err = client.ExecuteNamed(ctx, "nope", nil)
+	assert.ErrorIs(t, err, ErrUnknownNamedQuery)
+	assert.Len(t, sent, 2)
+}
+
+func TestQueryDenyList(t *testing.T) {
+	list := NewQueryDenyList()
+	require.NoError(t, list.Register("wipe", "MATCH (n) DETACH DELETE n"))
+	require.NoError(t, list.DenyPattern(`\bCALL dbms \.`))
+	assert.Error(t, list.DenyPattern("("))
+	ctx := context.Background()
+
+	q, err := list.ApplyPolicy(ctx, "MATCH (n) RETURN n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (n) RETURN n", q)
+	_, err = list.ApplyPolicy(ctx, "MATCH (n)  DETACH DELETE n", nil)
+	assert.EqualError(t, err, `nexus: query rejected by policy deny-list: query "wipe" is denied`)
+	_, err = list.ApplyPolicy(ctx, "call dbms.killQuery('1')", nil)
+	assert.ErrorContains(t, err, "deny pattern")
+}
@@ -0,0 +1,204 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded matches every QuotaExceededError.
+var ErrQuotaExceeded = errors.New("nexus: quota exceeded")
+
+// QuotaConfig gives a client a cost budget per time window, so one
+// misbehaving service cannot monopolise a shared cluster. The quota is
+// soft: a statement is admitted while the budget is not yet spent, and
+// the rows and bytes it returns are charged when it completes, so the
+// statement that crosses a limit still finishes and the next one is
+// held back.
+type QuotaConfig struct {
+	// Window is the period budgets apply to (default 1 minute). Windows
+	// are fixed: usage resets Window after the first statement of a
+	// window.
+	Window time.Duration
+	// MaxQueries caps the Cypher statements run per window; 0 means no
+	// limit.
+	MaxQueries int64
+	// MaxRows caps the result rows returned per window; 0 means no
+	// limit.
+	MaxRows int64
+	// MaxBytes caps the HTTP response bytes read per window; 0 means no
+	// limit. Responses over the RPC transport are not counted.
+	MaxBytes int64
+	// Delay makes statements beyond the budget wait for the next window,
+	// or until their context ends, instead of failing with a
+	// QuotaExceededError.
+	Delay bool
+}
+
+// QuotaExceededError reports a statement refused because a budget of
+// Config.Quota is spent.
+type QuotaExceededError struct {
+	// Resource is "queries", "rows" or "bytes".
+	Resource    string
+	Used, Limit int64
+	// ResetAt is when the window ends and the budget is restored.
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("nexus: %s quota exceeded (%d of %d), resets at %s",
+		e.Resource, e.Used, e.Limit, e.ResetAt.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrQuotaExceeded.
+func (e *QuotaExceededError) Is(target error) bool { return target == ErrQuotaExceeded }
+
+// QuotaUsage is what a client has spent in the current window.
+type QuotaUsage struct {
+	Queries, Rows, Bytes int64
+	// WindowStart and ResetAt bound the window; both are zero before the
+	// first statement.
+	WindowStart, ResetAt time.Time
+}
+
+// QuotaUsage returns the usage of the current Config.Quota window, or
+// the zero QuotaUsage when no quota is set.
+func (c *Client) QuotaUsage() QuotaUsage {
+	if c.quota == nil {
+		return QuotaUsage{}
+	}
+	c.quota.mu.Lock()
+	defer c.quota.mu.Unlock()
+	c.quota.roll()
+	return c.quota.usage
+}
+
+// quota implements QuotaConfig. A nil *quota admits everything.
+type quota struct {
+	cfg QuotaConfig
+	now func() time.Time
+
+	mu    sync.Mutex
+	usage QuotaUsage
+}
+
+func newQuota(cfg *QuotaConfig) *quota {
+	if cfg == nil {
+		return nil
+	}
+	q := &quota{cfg: *cfg, now: time.Now}
+	if q.cfg.Window <= 0 {
+		q.cfg.Window = time.Minute
+	}
+	return q
+}
+
+// roll starts a new window once the current one is over. Callers hold
+// q.mu.
+func (q *quota) roll() {
+	now := q.now()
+	if q.usage.ResetAt.IsZero() || !now.Before(q.usage.ResetAt) {
+		q.usage = QuotaUsage{WindowStart: now, ResetAt: now.Add(q.cfg.Window)}
+	}
+}
+
+// spent returns the error for the first budget used up, or nil.
+// Callers hold q.mu.
+func (q *quota) spent() *QuotaExceededError {
+	for _, b := range []struct {
+		resource    string
+		used, limit int64
+	}{
+		{"queries", q.usage.Queries, q.cfg.MaxQueries},
+		{"rows", q.usage.Rows, q.cfg.MaxRows},
+		{"bytes", q.usage.Bytes, q.cfg.MaxBytes},
+	} {
+		if b.limit > 0 && b.used >= b.limit {
+			return &QuotaExceededError{Resource: b.resource, Used: b.used, Limit: b.limit, ResetAt: q.usage.ResetAt}
+		}
+	}
+	return nil
+}
+
+// admit charges one statement to the budget, or fails (or, with Delay,
+// waits) when it is spent.
+func (q *quota) admit(ctx context.Context) error {
+	if q == nil {
+		return nil
+	}
+	for {
+		q.mu.Lock()
+		q.roll()
+		exceeded := q.spent()
+		if exceeded == nil {
+			q.usage.Queries++
+			q.mu.Unlock()
+			return nil
+		}
+		q.mu.Unlock()
+		if !q.cfg.Delay {
+			return exceeded
+		}
+		timer := time.NewTimer(exceeded.ResetAt.Sub(q.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// charge adds rows and bytes to the budget.
+func (q *quota) charge(rows, bytes int64) {
+	if q == nil || rows == 0 && bytes == 0 {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.roll()
+	q.usage.Rows += rows
+	q.usage.Bytes += bytes
+}
+
+// chargeRows charges the rows of result, if any, to Config.Quota.
+func (c *Client) chargeRows(result *QueryResult) {
+	if result != nil {
+		c.quota.charge(int64(len(result.Rows)), 0)
+	}
+}
+
+// quotaRoundTripper charges the bytes of HTTP response bodies as they
+// are read.
+type quotaRoundTripper struct {
+	quota *quota
+	next  http.RoundTripper
+}
+
+func (q *quotaRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := q.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err == nil {
+		resp.Body = &quotaBody{ReadCloser: resp.Body, quota: q.quota}
+	}
+	return resp, err
+}
+
+// quotaBody charges the bytes read from an HTTP response body.
+type quotaBody struct {
+	io.ReadCloser
+	quota *quota
+}
+
+func (b *quotaBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.quota.charge(0, int64(n))
+	return n, err
+}
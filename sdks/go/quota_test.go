@@ -0,0 +1,71 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuota(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"columns":["n"],"rows":[[1],[2],[3]]}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, Quota: &QuotaConfig{MaxQueries: 10, MaxRows: 5, Window: time.Minute}})
+	now := time.Now()
+	client.quota.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// The statement crossing the row budget completes; the next is refused.
+	for i := 0; i < 2; i++ {
+		_, err := client.ExecuteCypher(ctx, "MATCH (n) RETURN n", nil)
+		require.NoError(t, err)
+	}
+	_, err := client.ExecuteCypher(ctx, "MATCH (n) RETURN n", nil)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	var qe *QuotaExceededError
+	require.True(t, errors.As(err, &qe))
+	assert.Equal(t, QuotaExceededError{Resource: "rows", Used: 6, Limit: 5, ResetAt: now.Add(time.Minute)}, *qe)
+	assert.Equal(t, int32(2), hits.Load())
+
+	usage := client.QuotaUsage()
+	assert.Equal(t, int64(2), usage.Queries)
+	assert.Equal(t, int64(6), usage.Rows)
+	assert.Equal(t, int64(len(`{"columns":["n"],"rows":[[1],[2],[3]]}`))*2, usage.Bytes)
+
+	// A new window restores the budget.
+	now = now.Add(time.Minute)
+	_, err = client.ExecuteCypher(ctx, "MATCH (n) RETURN n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), client.QuotaUsage().Queries)
+}
+
+func TestQuotaDelay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, Quota: &QuotaConfig{MaxQueries: 1, Window: 50 * time.Millisecond, Delay: true}})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := client.ExecuteCypher(ctx, "RETURN 1", nil)
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond, "the second statement waits for the next window")
+
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	_, err := client.ExecuteCypher(ctx, "RETURN 1", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
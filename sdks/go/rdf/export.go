@@ -0,0 +1,193 @@
+package rdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/hivellm/nexus-go/internal/ids"
+)
+
+// Querier is the subset of *nexus.Client Export needs.
+type Querier interface {
+	ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error)
+}
+
+// ExportOptions selects the subgraph Export writes.
+type ExportOptions struct {
+	// Where filters the exported nodes, bound to n, e.g.
+	// "n.iri STARTS WITH $ns"; empty exports every Mapping.Label node.
+	// Relationships are exported when both endpoints are selected.
+	// Parameters binds the parameters Where refers to.
+	Where      string
+	Parameters map[string]interface{}
+	// Namespace is prepended to names that map back to no IRI through
+	// Mapping.Names or Mapping.Prefixes, and to the ID of nodes without
+	// a key (default "urn:nexus:").
+	Namespace string
+}
+
+// Export writes the selected subgraph to w as N-Triples and returns
+// the number of triples written. Labels other than Mapping.Label
+// become rdf:type triples, properties become literals (one triple per
+// list element) and relationships become resource-to-resource triples;
+// relationship properties have no place in plain RDF and are dropped.
+func Export(ctx context.Context, q Querier, w io.Writer, m Mapping, opts ExportOptions) (int, error) {
+	m = m.withDefaults()
+	for _, id := range []string{m.Label, m.Key} {
+		if identifier(id) != id {
+			return 0, fmt.Errorf("rdf: invalid identifier %q", id)
+		}
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "urn:nexus:"
+	}
+	e := &exporter{m: m, opts: opts, iris: map[string]string{}, out: NewWriter(w)}
+	for iri, name := range m.Names {
+		e.iris[name] = iri
+	}
+	where := ""
+	if opts.Where != "" {
+		where = " WHERE " + opts.Where
+	}
+
+	nodes, err := q.ExecuteCypher(ctx, fmt.Sprintf(
+		"MATCH (n:%s)%s RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props", m.Label, where),
+		opts.Parameters)
+	if err != nil {
+		return 0, fmt.Errorf("rdf: export nodes: %w", err)
+	}
+	ids := make([]interface{}, 0, len(nodes.Rows))
+	for _, row := range nodes.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		ids = append(ids, row[0])
+		if err := e.node(row); err != nil {
+			return e.written, err
+		}
+	}
+
+	// A filtered export keeps only relationships between selected nodes.
+	relWhere, relParams := "", map[string]interface{}(nil)
+	if opts.Where != "" {
+		relWhere = " WHERE id(n) IN $ids AND id(o) IN $ids"
+		relParams = map[string]interface{}{"ids": ids}
+	}
+	rels, err := q.ExecuteCypher(ctx, fmt.Sprintf(
+		"MATCH (n:%s)-[r]->(o:%s)%s RETURN id(n) AS from, n.%s AS fromKey, type(r) AS type, id(o) AS to, o.%s AS toKey",
+		m.Label, m.Label, relWhere, m.Key, m.Key), relParams)
+	if err != nil {
+		return e.written, fmt.Errorf("rdf: export relationships: %w", err)
+	}
+	for _, row := range rels.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		t := Triple{
+			Subject:   e.resource(row[0], row[1]),
+			Predicate: NewIRI(e.iri(fmt.Sprint(row[2]))),
+			Object:    e.resource(row[3], row[4]),
+		}
+		if err := e.write(t); err != nil {
+			return e.written, err
+		}
+	}
+	return e.written, nil
+}
+
+type exporter struct {
+	m       Mapping
+	opts    ExportOptions
+	iris    map[string]string
+	out     *Writer
+	written int
+}
+
+func (e *exporter) write(t Triple) error {
+	if err := e.out.Write(t); err != nil {
+		return fmt.Errorf("rdf: export: %w", err)
+	}
+	e.written++
+	return nil
+}
+
+func (e *exporter) node(row []interface{}) error {
+	props, _ := row[2].(map[string]interface{})
+	subject := e.resource(row[0], props[e.m.Key])
+	labels, _ := row[1].([]interface{})
+	for _, l := range labels {
+		if l == e.m.Label {
+			continue
+		}
+		t := Triple{Subject: subject, Predicate: NewIRI(RDFType), Object: NewIRI(e.iri(fmt.Sprint(l)))}
+		if err := e.write(t); err != nil {
+			return err
+		}
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		if k != e.m.Key {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values, ok := props[k].([]interface{})
+		if !ok {
+			values = []interface{}{props[k]}
+		}
+		for _, v := range values {
+			if v == nil {
+				continue
+			}
+			if err := e.write(Triple{Subject: subject, Predicate: NewIRI(e.iri(k)), Object: literal(v)}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resource names a node by its key, minting an IRI from its ID when it
+// has none.
+func (e *exporter) resource(id, key interface{}) Term {
+	if s, ok := key.(string); ok && s != "" {
+		if strings.HasPrefix(s, "_:") {
+			return NewBlank(s[2:])
+		}
+		return NewIRI(s)
+	}
+	return NewIRI(e.opts.Namespace + "node/" + ids.Format(id))
+}
+
+// iri inverts Mapping.name as far as it can.
+func (e *exporter) iri(name string) string {
+	if iri, ok := e.iris[name]; ok {
+		return iri
+	}
+	for ns, prefix := range e.m.Prefixes {
+		if rest := strings.TrimPrefix(name, prefix+"_"); rest != name {
+			return ns + rest
+		}
+	}
+	return e.opts.Namespace + name
+}
+
+func literal(v interface{}) Term {
+	switch x := v.(type) {
+	case string:
+		return NewLiteral(x, XSDString, "")
+	case bool:
+		return NewLiteral(strconv.FormatBool(x), XSDBoolean, "")
+	case int, int32, int64:
+		return NewLiteral(fmt.Sprint(x), XSDInteger, "")
+	case float32, float64:
+		return NewLiteral(fmt.Sprint(x), XSDDouble, "")
+	}
+	return NewLiteral(fmt.Sprint(v), XSDString, "")
+}
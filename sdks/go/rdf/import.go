@@ -0,0 +1,255 @@
+package rdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	nexus "github.com/hivellm/nexus-go"
+)
+
+// Mapping controls how IRIs become labels, property names and
+// relationship types. It is shared by Import and Export so a graph
+// exported with the mapping it was imported with round-trips.
+type Mapping struct {
+	// Label is given to every resource node and is what nodes are
+	// MERGEd on (default "Resource").
+	Label string
+	// Key is the property holding the node's IRI (default "iri"). Blank
+	// nodes are stored as "_:label".
+	Key string
+	// Prefixes maps namespace IRIs to short prefixes: with
+	// {"http://xmlns.com/foaf/0.1/": "foaf"}, foaf:name becomes the
+	// property foaf_name. IRIs outside any namespace use their local
+	// name, the part after the last '#', '/' or ':'.
+	Prefixes map[string]string
+	// Names overrides the name of individual class and predicate IRIs.
+	Names map[string]string
+	// Languages keeps only language-tagged literals in these languages
+	// (e.g. "en"); empty keeps all of them. The tag itself is not
+	// stored.
+	Languages []string
+}
+
+func (m Mapping) withDefaults() Mapping {
+	if m.Label == "" {
+		m.Label = "Resource"
+	}
+	if m.Key == "" {
+		m.Key = "iri"
+	}
+	return m
+}
+
+// name maps a class or predicate IRI to a graph identifier.
+func (m Mapping) name(iri string) string {
+	if n, ok := m.Names[iri]; ok {
+		return n
+	}
+	best := ""
+	for ns := range m.Prefixes {
+		if strings.HasPrefix(iri, ns) && len(ns) > len(best) {
+			best = ns
+		}
+	}
+	if best != "" {
+		return identifier(m.Prefixes[best] + "_" + iri[len(best):])
+	}
+	return identifier(iri[strings.LastIndexAny(iri, "#/:")+1:])
+}
+
+// identifier replaces everything a label or property name cannot hold
+// with underscores.
+func identifier(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// Report counts what an import read and wrote.
+type Report struct {
+	Triples int
+	// Resources is the number of distinct IRIs and blank nodes.
+	Resources int
+	// Properties counts literal triples stored, per property name.
+	Properties map[string]int
+	// Relationships counts resource-to-resource triples, per type.
+	Relationships map[string]int
+	// Skipped counts literals dropped by Mapping.Languages.
+	Skipped int
+}
+
+// Import streams RDF from r into the graph through loader, flushing it
+// at the end. Triples about the same subject are expected to be
+// adjacent, as they are in most dumps; a property repeated within such
+// a run is stored as a list, while a later run for the same subject
+// overwrites it.
+//
+// Every resource seen is remembered so its node is queued only once;
+// memory grows with the number of distinct IRIs, not triples.
+func Import(ctx context.Context, r io.Reader, format Format, loader *nexus.BulkLoader, m Mapping) (*Report, error) {
+	m = m.withDefaults()
+	report := &Report{Properties: map[string]int{}, Relationships: map[string]int{}}
+	im := &importer{ctx: ctx, loader: loader, m: m, report: report, seen: map[string]bool{}}
+	if err := Parse(r, format, im.triple); err != nil {
+		return report, err
+	}
+	if err := im.flushSubject(); err != nil {
+		return report, err
+	}
+	return report, loader.Flush(ctx)
+}
+
+type importer struct {
+	ctx    context.Context
+	loader *nexus.BulkLoader
+	m      Mapping
+	report *Report
+	seen   map[string]bool
+
+	// The run of triples about the current subject.
+	subject string
+	labels  []string
+	props   map[string]interface{}
+}
+
+func (im *importer) triple(t Triple) error {
+	im.report.Triples++
+	subject := resourceKey(t.Subject)
+	if subject != im.subject {
+		if err := im.flushSubject(); err != nil {
+			return err
+		}
+		if err := im.ensure(subject); err != nil {
+			return err
+		}
+		im.subject = subject
+	}
+
+	switch {
+	case t.Predicate.Value == RDFType && t.Object.Kind == IRI:
+		im.labels = append(im.labels, im.m.name(t.Object.Value))
+	case t.Object.Kind == Literal:
+		if !im.keepLanguage(t.Object.Language) {
+			im.report.Skipped++
+			return nil
+		}
+		name := im.m.name(t.Predicate.Value)
+		if name == im.m.Key {
+			return fmt.Errorf("rdf: predicate %s maps onto the key property %q", t.Predicate.Value, name)
+		}
+		if im.props == nil {
+			im.props = map[string]interface{}{}
+		}
+		value := literalValue(t.Object)
+		switch prev := im.props[name].(type) {
+		case nil:
+			im.props[name] = value
+		case []interface{}:
+			im.props[name] = append(prev, value)
+		default:
+			im.props[name] = []interface{}{prev, value}
+		}
+		im.report.Properties[name]++
+	default:
+		object := resourceKey(t.Object)
+		if err := im.ensure(object); err != nil {
+			return err
+		}
+		typ := im.m.name(t.Predicate.Value)
+		im.report.Relationships[typ]++
+		return im.loader.AddRelationship(im.ctx, nexus.BulkRelationship{
+			Type: typ,
+			From: nexus.BulkEndpoint{Label: im.m.Label, Key: im.m.Key, Value: subject},
+			To:   nexus.BulkEndpoint{Label: im.m.Label, Key: im.m.Key, Value: object},
+		})
+	}
+	return nil
+}
+
+// ensure queues a bare node for a resource the first time it is seen,
+// so relationships flushed before its subject run still find it.
+func (im *importer) ensure(key string) error {
+	if im.seen[key] {
+		return nil
+	}
+	im.seen[key] = true
+	im.report.Resources++
+	return im.loader.AddNode(im.ctx, nexus.BulkNode{
+		Labels:     []string{im.m.Label},
+		Properties: map[string]interface{}{im.m.Key: key},
+		Key:        im.m.Key,
+	})
+}
+
+func (im *importer) flushSubject() error {
+	if im.subject == "" || len(im.labels) == 0 && len(im.props) == 0 {
+		im.labels, im.props = nil, nil
+		return nil
+	}
+	props := im.props
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	props[im.m.Key] = im.subject
+	labels := append([]string{im.m.Label}, im.labels...)
+	im.labels, im.props = nil, nil
+	return im.loader.AddNode(im.ctx, nexus.BulkNode{Labels: labels, Properties: props, Key: im.m.Key})
+}
+
+func (im *importer) keepLanguage(lang string) bool {
+	if lang == "" || len(im.m.Languages) == 0 {
+		return true
+	}
+	for _, l := range im.m.Languages {
+		if strings.EqualFold(l, lang) {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceKey(t Term) string {
+	if t.Kind == BlankNode {
+		return "_:" + t.Value
+	}
+	return t.Value
+}
+
+// literalValue converts XSD numbers and booleans to their Go types;
+// everything else, including dates, is kept as its lexical string.
+func literalValue(t Term) interface{} {
+	switch strings.TrimPrefix(t.Datatype, XSD) {
+	case "integer", "int", "long", "short", "byte",
+		"nonNegativeInteger", "positiveInteger", "nonPositiveInteger", "negativeInteger",
+		"unsignedInt", "unsignedShort", "unsignedByte":
+		if n, err := strconv.ParseInt(t.Value, 10, 64); err == nil {
+			return n
+		}
+	case "decimal", "double", "float":
+		if f, err := strconv.ParseFloat(t.Value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(t.Value); err == nil {
+			return b
+		}
+	}
+	return t.Value
+}
@@ -0,0 +1,162 @@
+// Package rdf moves RDF data in and out of Nexus. Import streams
+// N-Triples or Turtle into the graph through a BulkLoader, and Export
+// writes a subgraph back as N-Triples for semantic-web tooling:
+//
+//	f, _ := os.Open("people.ttl")
+//	report, err := rdf.Import(ctx, f, rdf.Turtle, client.NewBulkLoader(nexus.BulkLoaderOptions{}), rdf.Mapping{
+//	    Prefixes: map[string]string{"http://xmlns.com/foaf/0.1/": "foaf"},
+//	})
+//
+// Every IRI or blank node becomes a node labelled Resource (see
+// Mapping) whose iri property holds the IRI. rdf:type objects become
+// labels, literal objects become properties and IRI objects become
+// relationships.
+package rdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is an RDF serialisation.
+type Format int
+
+const (
+	NTriples Format = iota
+	Turtle
+)
+
+func (f Format) String() string {
+	switch f {
+	case NTriples:
+		return "N-Triples"
+	case Turtle:
+		return "Turtle"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// TermKind distinguishes the three kinds of RDF term.
+type TermKind int
+
+const (
+	IRI TermKind = iota
+	BlankNode
+	Literal
+)
+
+// Well-known IRIs.
+const (
+	RDFType       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	RDFLangString = "http://www.w3.org/1999/02/22-rdf-syntax-ns#langString"
+	XSD           = "http://www.w3.org/2001/XMLSchema#"
+	XSDString     = XSD + "string"
+	XSDInteger    = XSD + "integer"
+	XSDDecimal    = XSD + "decimal"
+	XSDDouble     = XSD + "double"
+	XSDBoolean    = XSD + "boolean"
+)
+
+// Term is an IRI, a blank node (Value is its label) or a literal.
+type Term struct {
+	Kind  TermKind
+	Value string
+	// Language and Datatype qualify literals. Plain literals have
+	// neither; the datatype is then xsd:string.
+	Language string
+	Datatype string
+}
+
+// NewIRI, NewBlank and NewLiteral build terms.
+func NewIRI(iri string) Term     { return Term{Kind: IRI, Value: iri} }
+func NewBlank(label string) Term { return Term{Kind: BlankNode, Value: label} }
+func NewLiteral(value, datatype, language string) Term {
+	return Term{Kind: Literal, Value: value, Datatype: datatype, Language: language}
+}
+
+// String formats the term in N-Triples syntax.
+func (t Term) String() string {
+	switch t.Kind {
+	case IRI:
+		return "<" + escapeIRI(t.Value) + ">"
+	case BlankNode:
+		return "_:" + t.Value
+	}
+	s := `"` + escapeString(t.Value) + `"`
+	switch {
+	case t.Language != "":
+		s += "@" + t.Language
+	case t.Datatype != "" && t.Datatype != XSDString:
+		s += "^^<" + escapeIRI(t.Datatype) + ">"
+	}
+	return s
+}
+
+// Triple is one RDF statement.
+type Triple struct {
+	Subject, Predicate, Object Term
+}
+
+// String formats the triple as an N-Triples line without the newline.
+func (t Triple) String() string {
+	return t.Subject.String() + " " + t.Predicate.String() + " " + t.Object.String() + " ."
+}
+
+// Parse streams the triples of r to fn. N-Triples is parsed as the
+// Turtle subset it is. Turtle collections `( … )` are not supported.
+// Returning an error from fn stops the parse.
+func Parse(r io.Reader, format Format, fn func(Triple) error) error {
+	if format != NTriples && format != Turtle {
+		return fmt.Errorf("rdf: unsupported format %v", format)
+	}
+	p := newParser(r, fn)
+	return p.parse()
+}
+
+// Writer writes N-Triples.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a Writer on w.
+func NewWriter(w io.Writer) *Writer { return &Writer{w: w} }
+
+// Write writes one triple.
+func (w *Writer) Write(t Triple) error {
+	_, err := io.WriteString(w.w, t.String()+"\n")
+	return err
+}
+
+func escapeString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func escapeIRI(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r <= 0x20 || strings.ContainsRune(`<>"{}|^`+"`"+`\`, r) {
+			fmt.Fprintf(&b, `\u%04X`, r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,193 @@
+package rdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	nexus "github.com/hivellm/nexus-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const foaf = "http://xmlns.com/foaf/0.1/"
+
+func parseAll(t *testing.T, src string, format Format) []Triple {
+	t.Helper()
+	var out []Triple
+	require.NoError(t, Parse(strings.NewReader(src), format, func(tr Triple) error {
+		out = append(out, tr)
+		return nil
+	}))
+	return out
+}
+
+func TestParseNTriples(t *testing.T) {
+	src := `# people
+<http://ex.org/ann> <http://xmlns.com/foaf/0.1/name> "Ann \"A\"\n" .
+<http://ex.org/ann> <http://xmlns.com/foaf/0.1/age> "42"^^<http://www.w3.org/2001/XMLSchema#integer> .
+<http://ex.org/ann> <http://xmlns.com/foaf/0.1/knows> _:b1 .
+_:b1 <http://xmlns.com/foaf/0.1/name> "Bob"@en-GB .
+`
+	triples := parseAll(t, src, NTriples)
+	require.Len(t, triples, 4)
+	assert.Equal(t, NewLiteral("Ann \"A\"\n", XSDString, ""), triples[0].Object)
+	assert.Equal(t, NewLiteral("42", XSDInteger, ""), triples[1].Object)
+	assert.Equal(t, NewBlank("b1"), triples[2].Object)
+	assert.Equal(t, NewLiteral("Bob", RDFLangString, "en-GB"), triples[3].Object)
+
+	// Formatting round-trips.
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, tr := range triples {
+		require.NoError(t, w.Write(tr))
+	}
+	assert.Equal(t, triples, parseAll(t, buf.String(), NTriples))
+}
+
+func TestParseTurtle(t *testing.T) {
+	src := `@prefix foaf: <http://xmlns.com/foaf/0.1/> .
+@base <http://ex.org/people/> .
+PREFIX : <http://ex.org/>
+
+<ann> a foaf:Person, :Admin ;
+    foaf:name "Ann", 'Annie'@en ;
+    foaf:age 42 ;
+    :score 4.5 ;
+    :active true ;
+    foaf:knows [ foaf:name """Bob
+the builder""" ] ;
+    .
+:bob foaf:mbox <mailto:bob@ex.org>.
+`
+	triples := parseAll(t, src, Turtle)
+	require.Len(t, triples, 10)
+	ann := NewIRI("http://ex.org/people/ann")
+	assert.Equal(t, Triple{ann, NewIRI(RDFType), NewIRI(foaf + "Person")}, triples[0])
+	assert.Equal(t, NewIRI("http://ex.org/Admin"), triples[1].Object)
+	assert.Equal(t, NewLiteral("Annie", RDFLangString, "en"), triples[3].Object)
+	assert.Equal(t, NewLiteral("42", XSDInteger, ""), triples[4].Object)
+	assert.Equal(t, NewLiteral("4.5", XSDDecimal, ""), triples[5].Object)
+	assert.Equal(t, NewLiteral("true", XSDBoolean, ""), triples[6].Object)
+	// The blank node's own triple is emitted before the one linking it.
+	assert.Equal(t, NewLiteral("Bob\nthe builder", XSDString, ""), triples[7].Object)
+	assert.Equal(t, Triple{ann, NewIRI(foaf + "knows"), triples[7].Subject}, triples[8])
+	assert.Equal(t, Triple{NewIRI("http://ex.org/bob"), NewIRI(foaf + "mbox"), NewIRI("mailto:bob@ex.org")}, triples[9])
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		`<a> <b> "unterminated .`,
+		`x:a <b> <c> .`,
+		`<a> <b> ( <c> ) .`,
+		`<a> <b> <c>`,
+	} {
+		err := Parse(strings.NewReader(src), Turtle, func(Triple) error { return nil })
+		assert.Error(t, err, src)
+	}
+}
+
+type call struct {
+	Query string
+	Rows  []map[string]interface{}
+}
+
+func TestImport(t *testing.T) {
+	var calls []call
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string `json:"query"`
+			Parameters struct {
+				Rows []map[string]interface{} `json:"rows"`
+			} `json:"parameters"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		calls = append(calls, call{req.Query, req.Parameters.Rows})
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	src := `@prefix foaf: <http://xmlns.com/foaf/0.1/> .
+<http://ex.org/ann> a foaf:Person ; foaf:name "Ann", "Anna" ; foaf:age 42 ;
+    foaf:nick "annie"@en, "annette"@fr ; foaf:knows <http://ex.org/bob> .
+`
+	client := nexus.NewClient(nexus.Config{BaseURL: server.URL})
+	loader := client.NewBulkLoader(nexus.BulkLoaderOptions{})
+	report, err := Import(context.Background(), strings.NewReader(src), Turtle, loader, Mapping{
+		Prefixes:  map[string]string{foaf: "foaf"},
+		Names:     map[string]string{foaf + "Person": "Person"},
+		Languages: []string{"en"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 7, report.Triples)
+	assert.Equal(t, 2, report.Resources)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, map[string]int{"foaf_name": 2, "foaf_age": 1, "foaf_nick": 1}, report.Properties)
+	assert.Equal(t, map[string]int{"foaf_knows": 1}, report.Relationships)
+
+	require.Len(t, calls, 3)
+	assert.Equal(t, "UNWIND $rows AS row MERGE (n:Resource {iri: row.key}) SET n += row.props, n:Person", calls[0].Query)
+	assert.Equal(t, map[string]interface{}{
+		"iri": "http://ex.org/ann", "foaf_name": []interface{}{"Ann", "Anna"}, "foaf_age": float64(42), "foaf_nick": "annie",
+	}, calls[0].Rows[0]["props"])
+	// Every resource is also queued bare, so relationships always find it.
+	assert.Equal(t, "UNWIND $rows AS row MERGE (n:Resource {iri: row.key}) SET n += row.props", calls[1].Query)
+	assert.Len(t, calls[1].Rows, 2)
+	assert.Equal(t, "UNWIND $rows AS row MATCH (a:Resource {iri: row.from}) MATCH (b:Resource {iri: row.to}) "+
+		"MERGE (a)-[r:foaf_knows]->(b) SET r += row.props", calls[2].Query)
+
+	_, err = Import(context.Background(), strings.NewReader(`<a> <http://x/iri> "clash" .`), NTriples, loader, Mapping{})
+	assert.Error(t, err)
+}
+
+type fakeQuerier struct {
+	queries []string
+	params  []map[string]interface{}
+	results []*nexus.QueryResult
+}
+
+func (f *fakeQuerier) ExecuteCypher(_ context.Context, query string, params map[string]interface{}) (*nexus.QueryResult, error) {
+	f.queries = append(f.queries, query)
+	f.params = append(f.params, params)
+	res := f.results[0]
+	f.results = f.results[1:]
+	return res, nil
+}
+
+func TestExport(t *testing.T) {
+	q := &fakeQuerier{results: []*nexus.QueryResult{
+		{Rows: [][]interface{}{
+			{int64(1), []interface{}{"Resource", "Person"}, map[string]interface{}{
+				"iri": "http://ex.org/ann", "foaf_name": []interface{}{"Ann", "Anna"}, "foaf_age": int64(42),
+			}},
+			{int64(2), []interface{}{"Resource"}, map[string]interface{}{"iri": "_:b1", "score": 1.5, "active": true}},
+			{int64(3), []interface{}{"Resource"}, map[string]interface{}{}},
+		}},
+		{Rows: [][]interface{}{{int64(1), "http://ex.org/ann", "foaf_knows", int64(3), nil}}},
+	}}
+	var buf bytes.Buffer
+	n, err := Export(context.Background(), q, &buf, Mapping{
+		Prefixes: map[string]string{foaf: "foaf"},
+		Names:    map[string]string{foaf + "Person": "Person"},
+	}, ExportOptions{Where: "n.iri STARTS WITH $ns", Parameters: map[string]interface{}{"ns": "http://ex.org/"}})
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+	assert.Equal(t, `<http://ex.org/ann> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://xmlns.com/foaf/0.1/Person> .
+<http://ex.org/ann> <http://xmlns.com/foaf/0.1/age> "42"^^<http://www.w3.org/2001/XMLSchema#integer> .
+<http://ex.org/ann> <http://xmlns.com/foaf/0.1/name> "Ann" .
+<http://ex.org/ann> <http://xmlns.com/foaf/0.1/name> "Anna" .
+_:b1 <urn:nexus:active> "true"^^<http://www.w3.org/2001/XMLSchema#boolean> .
+_:b1 <urn:nexus:score> "1.5"^^<http://www.w3.org/2001/XMLSchema#double> .
+<http://ex.org/ann> <http://xmlns.com/foaf/0.1/knows> <urn:nexus:node/3> .
+`, buf.String())
+	assert.Equal(t, "MATCH (n:Resource) WHERE n.iri STARTS WITH $ns RETURN id(n) AS id, labels(n) AS labels, properties(n) AS props", q.queries[0])
+	assert.Contains(t, q.queries[1], "WHERE id(n) IN $ids AND id(o) IN $ids")
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, q.params[1]["ids"])
+
+	_, err = Export(context.Background(), q, &buf, Mapping{Label: "Bad Label"}, ExportOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,547 @@
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIRI
+	tokPName
+	tokBlank
+	tokString
+	tokLangTag
+	tokNumber
+	tokKeyword
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// lexer tokenises Turtle from a stream, reading one rune at a time.
+type lexer struct {
+	r       *bufio.Reader
+	pending []rune
+	line    int
+}
+
+func (l *lexer) next() (rune, bool) {
+	var c rune
+	if n := len(l.pending); n > 0 {
+		c = l.pending[n-1]
+		l.pending = l.pending[:n-1]
+	} else {
+		var err error
+		if c, _, err = l.r.ReadRune(); err != nil {
+			return 0, false
+		}
+	}
+	if c == '\n' {
+		l.line++
+	}
+	return c, true
+}
+
+func (l *lexer) unread(c rune) {
+	if c == '\n' {
+		l.line--
+	}
+	l.pending = append(l.pending, c)
+}
+
+func (l *lexer) peek() (rune, bool) {
+	c, ok := l.next()
+	if ok {
+		l.unread(c)
+	}
+	return c, ok
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("rdf: line %d: %s", l.line, fmt.Sprintf(format, args...))
+}
+
+func (l *lexer) token() (token, error) {
+	// Skip whitespace and comments.
+	for {
+		c, ok := l.next()
+		if !ok {
+			return token{kind: tokEOF, line: l.line}, nil
+		}
+		if c == '#' {
+			for ok && c != '\n' {
+				c, ok = l.next()
+			}
+			continue
+		}
+		if !unicode.IsSpace(c) {
+			l.unread(c)
+			break
+		}
+	}
+	line := l.line
+	c, _ := l.next()
+	tok := func(kind tokenKind, text string) (token, error) {
+		return token{kind: kind, text: text, line: line}, nil
+	}
+	switch {
+	case c == '<':
+		s, err := l.iri()
+		return token{kind: tokIRI, text: s, line: line}, err
+	case c == '"' || c == '\'':
+		s, err := l.quoted(c)
+		return token{kind: tokString, text: s, line: line}, err
+	case c == '@':
+		name := l.name(func(r rune) bool { return isAlnum(r) || r == '-' })
+		if name == "" {
+			return token{}, l.errorf("expected language tag or directive after @")
+		}
+		if name == "prefix" || name == "base" {
+			return tok(tokKeyword, "@"+name)
+		}
+		return tok(tokLangTag, name)
+	case c == '^':
+		if n, _ := l.next(); n != '^' {
+			return token{}, l.errorf("expected ^^")
+		}
+		return tok(tokPunct, "^^")
+	case c == '_':
+		if n, _ := l.next(); n != ':' {
+			return token{}, l.errorf("expected _: blank node label")
+		}
+		return tok(tokBlank, l.localName())
+	case c == '+' || c == '-' || c == '.' && l.digitNext() || unicode.IsDigit(c):
+		l.unread(c)
+		return tok(tokNumber, l.number())
+	case strings.ContainsRune(".;,[]()", c):
+		return tok(tokPunct, string(c))
+	case c == ':' || unicode.IsLetter(c):
+		l.unread(c)
+		prefix := l.name(func(r rune) bool { return isAlnum(r) || r == '_' || r == '-' || r == '.' })
+		if n, ok := l.peek(); ok && n == ':' {
+			l.next()
+			return tok(tokPName, prefix+":"+l.localName())
+		}
+		return tok(tokKeyword, prefix)
+	}
+	return token{}, l.errorf("unexpected character %q", c)
+}
+
+func (l *lexer) digitNext() bool {
+	c, ok := l.peek()
+	return ok && unicode.IsDigit(c)
+}
+
+// name reads runes while accept holds; a trailing '.' ends a statement
+// rather than the name, so it is given back.
+func (l *lexer) name(accept func(rune) bool) string {
+	var b []rune
+	for {
+		c, ok := l.next()
+		if !ok {
+			break
+		}
+		if !accept(c) {
+			l.unread(c)
+			break
+		}
+		b = append(b, c)
+	}
+	for len(b) > 0 && b[len(b)-1] == '.' {
+		l.unread('.')
+		b = b[:len(b)-1]
+	}
+	return string(b)
+}
+
+func (l *lexer) localName() string {
+	return l.name(func(r rune) bool { return isAlnum(r) || strings.ContainsRune("_-.:%", r) })
+}
+
+func (l *lexer) number() string {
+	return l.name(func(r rune) bool { return unicode.IsDigit(r) || strings.ContainsRune("+-.eE", r) })
+}
+
+func (l *lexer) iri() (string, error) {
+	var b strings.Builder
+	for {
+		c, ok := l.next()
+		if !ok || c == '\n' {
+			return "", l.errorf("unterminated IRI")
+		}
+		switch c {
+		case '>':
+			return b.String(), nil
+		case '\\':
+			r, err := l.unicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(c)
+		}
+	}
+}
+
+func (l *lexer) unicodeEscape() (rune, error) {
+	c, _ := l.next()
+	n := 0
+	switch c {
+	case 'u':
+		n = 4
+	case 'U':
+		n = 8
+	default:
+		return 0, l.errorf("invalid escape \\%c", c)
+	}
+	hex := make([]rune, 0, n)
+	for i := 0; i < n; i++ {
+		h, _ := l.next()
+		hex = append(hex, h)
+	}
+	v, err := strconv.ParseUint(string(hex), 16, 32)
+	if err != nil {
+		return 0, l.errorf("invalid escape \\%c%s", c, string(hex))
+	}
+	return rune(v), nil
+}
+
+// quoted reads a string literal whose opening quote q has been
+// consumed, including the """long""" forms.
+func (l *lexer) quoted(q rune) (string, error) {
+	long := false
+	if c, _ := l.next(); c == q {
+		if c2, ok := l.next(); ok && c2 == q {
+			long = true
+		} else {
+			if ok {
+				l.unread(c2)
+			}
+			return "", nil
+		}
+	} else {
+		l.unread(c)
+	}
+	var b strings.Builder
+	for {
+		c, ok := l.next()
+		if !ok || (!long && c == '\n') {
+			return "", l.errorf("unterminated string")
+		}
+		switch {
+		case c == q && !long:
+			return b.String(), nil
+		case c == q:
+			c2, _ := l.next()
+			c3, _ := l.next()
+			if c2 == q && c3 == q {
+				return b.String(), nil
+			}
+			l.unread(c3)
+			l.unread(c2)
+			b.WriteRune(c)
+		case c == '\\':
+			e, _ := l.next()
+			switch e {
+			case 't':
+				b.WriteRune('\t')
+			case 'n':
+				b.WriteRune('\n')
+			case 'r':
+				b.WriteRune('\r')
+			case 'b':
+				b.WriteRune('\b')
+			case 'f':
+				b.WriteRune('\f')
+			case '"', '\'', '\\':
+				b.WriteRune(e)
+			default:
+				l.unread(e)
+				r, err := l.unicodeEscape()
+				if err != nil {
+					return "", err
+				}
+				b.WriteRune(r)
+			}
+		default:
+			b.WriteRune(c)
+		}
+	}
+}
+
+func isAlnum(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+// parser is a recursive-descent Turtle parser emitting triples as
+// soon as they are complete.
+type parser struct {
+	lex      *lexer
+	emit     func(Triple) error
+	prefixes map[string]string
+	base     *url.URL
+	tok      token
+	blanks   int
+}
+
+func newParser(r io.Reader, emit func(Triple) error) *parser {
+	return &parser{
+		lex:      &lexer{r: bufio.NewReader(r), line: 1},
+		emit:     emit,
+		prefixes: map[string]string{},
+	}
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.token()
+	p.tok = t
+	return err
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("rdf: line %d: %s", p.tok.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) punct(s string) bool { return p.tok.kind == tokPunct && p.tok.text == s }
+
+func (p *parser) expect(s string) error {
+	if !p.punct(s) {
+		return p.errorf("expected %q, got %q", s, p.tok.text)
+	}
+	return p.advance()
+}
+
+func (p *parser) parse() error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+	for p.tok.kind != tokEOF {
+		if err := p.statement(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) statement() error {
+	if p.tok.kind == tokKeyword {
+		switch strings.ToLower(p.tok.text) {
+		case "@prefix", "prefix":
+			sparql := p.tok.text == "PREFIX" || p.tok.text == "prefix"
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if p.tok.kind != tokPName || !strings.HasSuffix(p.tok.text, ":") {
+				return p.errorf("expected prefix name, got %q", p.tok.text)
+			}
+			name := strings.TrimSuffix(p.tok.text, ":")
+			if err := p.advance(); err != nil {
+				return err
+			}
+			iri, err := p.iriToken()
+			if err != nil {
+				return err
+			}
+			p.prefixes[name] = iri
+			return p.directiveEnd(sparql)
+		case "@base", "base":
+			sparql := p.tok.text != "@base"
+			if err := p.advance(); err != nil {
+				return err
+			}
+			iri, err := p.iriToken()
+			if err != nil {
+				return err
+			}
+			if p.base, err = url.Parse(iri); err != nil {
+				return p.errorf("invalid base IRI %q", iri)
+			}
+			return p.directiveEnd(sparql)
+		}
+	}
+
+	var subject Term
+	var err error
+	if p.punct("[") {
+		if subject, err = p.blankNodePropertyList(); err != nil {
+			return err
+		}
+		if p.punct(".") {
+			return p.advance()
+		}
+	} else if subject, err = p.resource(); err != nil {
+		return err
+	}
+	if err := p.predicateObjectList(subject); err != nil {
+		return err
+	}
+	return p.expect(".")
+}
+
+func (p *parser) directiveEnd(sparql bool) error {
+	if sparql {
+		return nil
+	}
+	return p.expect(".")
+}
+
+func (p *parser) iriToken() (string, error) {
+	if p.tok.kind != tokIRI {
+		return "", p.errorf("expected IRI, got %q", p.tok.text)
+	}
+	iri := p.resolve(p.tok.text)
+	return iri, p.advance()
+}
+
+func (p *parser) resolve(iri string) string {
+	if p.base == nil {
+		return iri
+	}
+	ref, err := url.Parse(iri)
+	if err != nil || ref.IsAbs() {
+		return iri
+	}
+	return p.base.ResolveReference(ref).String()
+}
+
+// resource parses an IRI, prefixed name or blank node label.
+func (p *parser) resource() (Term, error) {
+	var t Term
+	switch p.tok.kind {
+	case tokIRI:
+		t = NewIRI(p.resolve(p.tok.text))
+	case tokPName:
+		i := strings.IndexByte(p.tok.text, ':')
+		ns, ok := p.prefixes[p.tok.text[:i]]
+		if !ok {
+			return t, p.errorf("undefined prefix %q", p.tok.text[:i])
+		}
+		t = NewIRI(ns + p.tok.text[i+1:])
+	case tokBlank:
+		t = NewBlank(p.tok.text)
+	default:
+		if p.punct("(") {
+			return t, p.errorf("collections are not supported")
+		}
+		return t, p.errorf("expected IRI or blank node, got %q", p.tok.text)
+	}
+	return t, p.advance()
+}
+
+func (p *parser) predicateObjectList(subject Term) error {
+	for {
+		var pred Term
+		if p.tok.kind == tokKeyword && p.tok.text == "a" {
+			pred = NewIRI(RDFType)
+			if err := p.advance(); err != nil {
+				return err
+			}
+		} else {
+			var err error
+			if pred, err = p.resource(); err != nil {
+				return err
+			}
+		}
+		for {
+			obj, err := p.object()
+			if err != nil {
+				return err
+			}
+			if err := p.emit(Triple{Subject: subject, Predicate: pred, Object: obj}); err != nil {
+				return err
+			}
+			if !p.punct(",") {
+				break
+			}
+			if err := p.advance(); err != nil {
+				return err
+			}
+		}
+		if !p.punct(";") {
+			return nil
+		}
+		// Repeated and trailing semicolons are allowed.
+		for p.punct(";") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+		}
+		if p.punct(".") || p.punct("]") {
+			return nil
+		}
+	}
+}
+
+func (p *parser) blankNodePropertyList() (Term, error) {
+	p.blanks++
+	node := NewBlank(fmt.Sprintf("genid%d", p.blanks))
+	if err := p.advance(); err != nil {
+		return node, err
+	}
+	if !p.punct("]") {
+		if err := p.predicateObjectList(node); err != nil {
+			return node, err
+		}
+	}
+	return node, p.expect("]")
+}
+
+func (p *parser) object() (Term, error) {
+	switch p.tok.kind {
+	case tokString:
+		return p.literal()
+	case tokNumber:
+		text := p.tok.text
+		dt := XSDInteger
+		switch {
+		case strings.ContainsAny(text, "eE"):
+			dt = XSDDouble
+		case strings.Contains(text, "."):
+			dt = XSDDecimal
+		}
+		return NewLiteral(text, dt, ""), p.advance()
+	case tokKeyword:
+		if p.tok.text == "true" || p.tok.text == "false" {
+			t := NewLiteral(p.tok.text, XSDBoolean, "")
+			return t, p.advance()
+		}
+	case tokPunct:
+		if p.punct("[") {
+			return p.blankNodePropertyList()
+		}
+	}
+	return p.resource()
+}
+
+func (p *parser) literal() (Term, error) {
+	value := p.tok.text
+	if err := p.advance(); err != nil {
+		return Term{}, err
+	}
+	switch {
+	case p.tok.kind == tokLangTag:
+		t := NewLiteral(value, RDFLangString, p.tok.text)
+		return t, p.advance()
+	case p.punct("^^"):
+		if err := p.advance(); err != nil {
+			return Term{}, err
+		}
+		dt, err := p.resource()
+		if err != nil {
+			return Term{}, err
+		}
+		return NewLiteral(value, dt.Value, ""), nil
+	}
+	return NewLiteral(value, XSDString, ""), nil
+}
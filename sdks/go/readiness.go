@@ -0,0 +1,142 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReadinessOptions tunes ReadinessHandlerWithOptions.
+type ReadinessOptions struct {
+	// Timeout bounds the server health check (default 2s).
+	Timeout time.Duration
+	// CacheFor reuses a health check for this long, so frequent probes
+	// from several orchestrators do not add load (default 5s).
+	CacheFor time.Duration
+	// MaxInFlight reports the client saturated, and so not ready, once
+	// this many requests await a response. 0 reports the count without
+	// judging it.
+	MaxInFlight int
+}
+
+// Readiness is the body ReadinessHandler serves.
+type Readiness struct {
+	Ready bool `json:"ready"`
+	// Reasons says why the client is not ready.
+	Reasons []string `json:"reasons,omitempty"`
+	// Server is the outcome of the last health check.
+	Server ServerHealth `json:"server"`
+	// Circuit is the circuit breaker state (see Config.CircuitBreaker).
+	Circuit string `json:"circuit"`
+	// InFlight is the number of requests awaiting a response, and
+	// Saturation its share of MaxInFlight (0 when that is not set).
+	InFlight    int64   `json:"in_flight"`
+	MaxInFlight int     `json:"max_in_flight,omitempty"`
+	Saturation  float64 `json:"saturation,omitempty"`
+}
+
+// ServerHealth is the outcome of a server health check.
+type ServerHealth struct {
+	Reachable bool      `json:"reachable"`
+	LatencyMs float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// ReadinessHandler returns an http.Handler for an application's
+// readiness probe with the default ReadinessOptions:
+//
+//	mux.Handle("/readyz", nexus.ReadinessHandler(client))
+func ReadinessHandler(client *Client) http.Handler {
+	return ReadinessHandlerWithOptions(client, ReadinessOptions{})
+}
+
+// ReadinessHandlerWithOptions returns an http.Handler reporting the
+// client's view of Nexus as a Readiness document: 200 when ready, 503
+// when the client is closed, the server fails its health check, the
+// circuit breaker is open or MaxInFlight is reached.
+func ReadinessHandlerWithOptions(client *Client, opts ReadinessOptions) http.Handler {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+	if opts.CacheFor <= 0 {
+		opts.CacheFor = 5 * time.Second
+	}
+	return &readinessHandler{client: client, opts: opts}
+}
+
+type readinessHandler struct {
+	client *Client
+	opts   ReadinessOptions
+
+	mu   sync.Mutex
+	last ServerHealth
+}
+
+func (h *readinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := h.client
+	// After the health check, which may have moved the circuit.
+	health := h.health(r.Context())
+	state := c.CircuitState()
+	rd := Readiness{
+		Server:      health,
+		Circuit:     state.String(),
+		InFlight:    c.inFlight.Load(),
+		MaxInFlight: h.opts.MaxInFlight,
+	}
+
+	if c.closed.Load() {
+		rd.Reasons = append(rd.Reasons, "client closed")
+	}
+	if !rd.Server.Reachable {
+		rd.Reasons = append(rd.Reasons, "server unreachable")
+	}
+	if state == CircuitOpen {
+		rd.Reasons = append(rd.Reasons, "circuit open")
+	}
+	if h.opts.MaxInFlight > 0 {
+		rd.Saturation = float64(rd.InFlight) / float64(h.opts.MaxInFlight)
+		if rd.InFlight >= int64(h.opts.MaxInFlight) {
+			rd.Reasons = append(rd.Reasons, "saturated")
+		}
+	}
+	rd.Ready = len(rd.Reasons) == 0
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !rd.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(rd)
+}
+
+// health returns the last health check, running a new one when it is
+// older than CacheFor. Probes arriving meanwhile wait for it.
+func (h *readinessHandler) health(ctx context.Context) ServerHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.last.CheckedAt.IsZero() && time.Since(h.last.CheckedAt) < h.opts.CacheFor {
+		return h.last
+	}
+	ctx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+	start := time.Now()
+	err := h.client.Ping(ctx)
+	health := ServerHealth{
+		Reachable: err == nil,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: time.Now(),
+	}
+	if err != nil {
+		health.Error = err.Error()
+		if errors.Is(err, context.Canceled) {
+			// The probe went away; do not cache its verdict.
+			return health
+		}
+	}
+	h.last = health
+	return health
+}
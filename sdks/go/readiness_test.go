@@ -0,0 +1,62 @@
+package nexus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	var healthy atomic.Bool
+	var checks atomic.Int32
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Hour}})
+
+	probe := func(h http.Handler) (int, Readiness) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var rd Readiness
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rd))
+		return rec.Code, rd
+	}
+
+	h := ReadinessHandler(client)
+	code, rd := probe(h)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, rd.Ready)
+	assert.True(t, rd.Server.Reachable)
+	assert.Equal(t, "closed", rd.Circuit)
+
+	// The health check is cached.
+	probe(h)
+	assert.Equal(t, int32(1), checks.Load())
+
+	healthy.Store(false)
+	code, rd = probe(ReadinessHandlerWithOptions(client, ReadinessOptions{CacheFor: time.Nanosecond}))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, []string{"server unreachable", "circuit open"}, rd.Reasons)
+	assert.Equal(t, "open", rd.Circuit)
+
+	saturated := NewClient(Config{BaseURL: server.URL})
+	saturated.inFlight.Store(8)
+	healthy.Store(true)
+	code, rd = probe(ReadinessHandlerWithOptions(saturated, ReadinessOptions{MaxInFlight: 8}))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, []string{"saturated"}, rd.Reasons)
+	assert.Equal(t, 1.0, rd.Saturation)
+}
@@ -0,0 +1,104 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RenameLabel moves every node labelled oldLabel to newLabel, in
+// batches of 1000 nodes (see BatchedUpdate). Nodes that already have
+// newLabel just lose oldLabel. Schema objects on oldLabel (indexes,
+// constraints) are not moved; recreate them for newLabel. Report
+// progress with WithBatchProgress.
+func (c *Client) RenameLabel(ctx context.Context, oldLabel, newLabel string) (BatchProgress, error) {
+	if err := validLabelIdentifier(oldLabel); err != nil {
+		return BatchProgress{}, err
+	}
+	if err := validLabelIdentifier(newLabel); err != nil {
+		return BatchProgress{}, err
+	}
+	if oldLabel == newLabel {
+		return BatchProgress{}, fmt.Errorf("nexus: rename label: %s is already called that", oldLabel)
+	}
+	return c.BatchedUpdate(ctx,
+		"MATCH (n:"+oldLabel+")",
+		"SET n:"+newLabel+" REMOVE n:"+oldLabel,
+		0, BatchedUpdateOptions{})
+}
+
+// RenameProperty renames property oldName to newName on every node
+// labelled label (every node when label is empty), in batches of 1000
+// nodes (see BatchedUpdate). A newName value a node already has is
+// overwritten. Report progress with WithBatchProgress.
+func (c *Client) RenameProperty(ctx context.Context, label, oldName, newName string) (BatchProgress, error) {
+	match := "MATCH (n)"
+	if label != "" {
+		if err := validLabelIdentifier(label); err != nil {
+			return BatchProgress{}, err
+		}
+		match = "MATCH (n:" + label + ")"
+	}
+	if oldName == "" || newName == "" {
+		return BatchProgress{}, fmt.Errorf("nexus: rename property: property names must not be empty")
+	}
+	if oldName == newName {
+		return BatchProgress{}, fmt.Errorf("nexus: rename property: %s is already called that", oldName)
+	}
+	oldProp, newProp := "n."+cypherKey(oldName), "n."+cypherKey(newName)
+	return c.BatchedUpdate(ctx,
+		match+" WHERE "+oldProp+" IS NOT NULL",
+		"SET "+newProp+" = "+oldProp+" REMOVE "+oldProp,
+		0, BatchedUpdateOptions{})
+}
+
+// RelationshipFilter narrows the relationships RetypeRelationships
+// changes; the zero value matches them all.
+type RelationshipFilter struct {
+	// StartLabels and EndLabels are labels the start and end nodes must
+	// have.
+	StartLabels []string
+	EndLabels   []string
+	// Where is an extra predicate on the start node a, the relationship
+	// r and the end node b, e.g. "r.since < $year".
+	Where string
+	// Params are passed to every batch, for Where.
+	Params map[string]interface{}
+}
+
+// RetypeRelationships re-creates every oldType relationship matching
+// filter as a newType one with the same direction and properties, then
+// deletes the original, in batches of 1000 (see BatchedUpdate). The
+// new relationships get new IDs. Report progress with
+// WithBatchProgress.
+func (c *Client) RetypeRelationships(ctx context.Context, oldType, newType string, filter RelationshipFilter) (BatchProgress, error) {
+	if err := validLabelIdentifier(oldType); err != nil {
+		return BatchProgress{}, err
+	}
+	if err := validLabelIdentifier(newType); err != nil {
+		return BatchProgress{}, err
+	}
+	if oldType == newType {
+		return BatchProgress{}, fmt.Errorf("nexus: retype relationships: %s is already the type", oldType)
+	}
+	for _, l := range append(append([]string(nil), filter.StartLabels...), filter.EndLabels...) {
+		if err := validLabelIdentifier(l); err != nil {
+			return BatchProgress{}, err
+		}
+	}
+	match := "MATCH (a" + labelSuffix(filter.StartLabels) + ")-[r:" + oldType + "]->(b" + labelSuffix(filter.EndLabels) + ")"
+	if where := strings.TrimSpace(filter.Where); where != "" {
+		match += " WHERE " + where
+	}
+	return c.BatchedUpdate(ctx, match,
+		"CREATE (a)-[r2:"+newType+"]->(b) SET r2 = properties(r) DELETE r",
+		0, BatchedUpdateOptions{Params: filter.Params})
+}
+
+// labelSuffix returns labels as a node pattern suffix (":A:B").
+func labelSuffix(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return ":" + strings.Join(labels, ":")
+}
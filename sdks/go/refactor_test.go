@@ -0,0 +1,74 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refactorServer answers batched updates with remaining rows, at most
+// one batch at a time, recording the queries.
+func refactorServer(t *testing.T, remaining int) (*httptest.Server, *[]string) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req.Query)
+		n := min(int(req.Parameters["__batch"].(float64)), remaining)
+		remaining -= n
+		writeJSON(w, map[string]interface{}{"columns": []string{"__updated"}, "rows": [][]interface{}{{n}}})
+	}))
+	return server, &queries
+}
+
+func TestRenameLabelAndProperty(t *testing.T) {
+	server, queries := refactorServer(t, 2500)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	var seen []int64
+	ctx := WithBatchProgress(context.Background(), func(p BatchProgress) { seen = append(seen, p.Updated) })
+	progress, err := client.RenameLabel(ctx, "Person", "Human")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2500), progress.Updated)
+	assert.Equal(t, []int64{1000, 2000, 2500}, seen)
+	assert.Equal(t, "MATCH (n:Person) WITH * LIMIT $__batch SET n:Human REMOVE n:Person RETURN count(*) AS __updated", (*queries)[0])
+
+	*queries = nil
+	_, err = client.RenameProperty(context.Background(), "Person", "e-mail", "email")
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (n:Person) WHERE n.`e-mail` IS NOT NULL WITH * LIMIT $__batch "+
+		"SET n.email = n.`e-mail` REMOVE n.`e-mail` RETURN count(*) AS __updated", (*queries)[0])
+
+	_, err = client.RenameLabel(ctx, "Person", "Bad Label")
+	assert.Error(t, err)
+	_, err = client.RenameProperty(ctx, "", "email", "email")
+	assert.Error(t, err)
+}
+
+func TestRetypeRelationships(t *testing.T) {
+	server, queries := refactorServer(t, 10)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	progress, err := client.RetypeRelationships(ctx, "KNOWS", "FRIEND_OF", RelationshipFilter{
+		StartLabels: []string{"Person"},
+		Where:       "r.since < $year",
+		Params:      map[string]interface{}{"year": 2020},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), progress.Updated)
+	assert.Equal(t, "MATCH (a:Person)-[r:KNOWS]->(b) WHERE r.since < $year WITH * LIMIT $__batch "+
+		"CREATE (a)-[r2:FRIEND_OF]->(b) SET r2 = properties(r) DELETE r RETURN count(*) AS __updated", (*queries)[0])
+
+	_, err = client.RetypeRelationships(ctx, "KNOWS", "KNOWS", RelationshipFilter{})
+	assert.Error(t, err)
+	_, err = client.RetypeRelationships(ctx, "KNOWS", "LIKES", RelationshipFilter{EndLabels: []string{"a-b"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReifyOptions configures how a relationship is turned into a node.
+//
+// Reifying (a)-[r:T {props}]->(b) produces
+//
+//	(a)<-[:SourceType]-(m:Label {props})-[:TargetType]->(b)
+type ReifyOptions struct {
+	// Label of the intermediate node; defaults to the relationship type.
+	Label string
+	// SourceType / TargetType name the relationships from the
+	// intermediate node to the original endpoints (defaults "SOURCE" and
+	// "TARGET").
+	SourceType string
+	TargetType string
+}
+
+func (o ReifyOptions) withDefaults(relType string) ReifyOptions {
+	if o.Label == "" {
+		o.Label = relType
+	}
+	if o.SourceType == "" {
+		o.SourceType = "SOURCE"
+	}
+	if o.TargetType == "" {
+		o.TargetType = "TARGET"
+	}
+	return o
+}
+
+// ReifyRelationship replaces a relationship with an intermediate node
+// carrying its properties, so they can be indexed or linked to other
+// nodes. The original relationship is deleted in the same statement.
+func (c *Client) ReifyRelationship(ctx context.Context, relID string, opts ReifyOptions) (*Node, error) {
+	id, err := parseID(relID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Label == "" {
+		rel, err := c.GetRelationship(ctx, relID)
+		if err != nil {
+			return nil, err
+		}
+		opts.Label = rel.Type
+	}
+	opts = opts.withDefaults("")
+	for _, name := range []string{opts.Label, opts.SourceType, opts.TargetType} {
+		if err := validLabelIdentifier(name); err != nil {
+			return nil, err
+		}
+	}
+
+	query := fmt.Sprintf(
+		"MATCH (a)-[r]->(b) WHERE id(r) = $id "+
+			"CREATE (m:%s) SET m = properties(r) "+
+			"CREATE (a)<-[:%s]-(m)-[:%s]->(b) DELETE r "+
+			"RETURN id(m) AS id, labels(m) AS labels, properties(m) AS props",
+		opts.Label, opts.SourceType, opts.TargetType)
+	result, err := c.ExecuteCypher(ctx, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 3 {
+		return nil, fmt.Errorf("nexus: relationship %s not found", relID)
+	}
+	row := result.Rows[0]
+	node := &Node{ID: idString(row[0])}
+	for _, l := range asSlice(row[1]) {
+		node.Labels = append(node.Labels, fmt.Sprint(l))
+	}
+	node.Properties, _ = row[2].(map[string]interface{})
+	return node, nil
+}
+
+// UnreifyNode is the inverse of ReifyRelationship: it collapses an
+// intermediate node back into a relType relationship between its
+// SourceType and TargetType neighbours, copying the node's properties
+// onto it, and deletes the node.
+//
+// It refuses to run when the node has relationships other than the
+// source and target links, since those would be lost. The check and
+// the rewrite share a transaction.
+func (c *Client) UnreifyNode(ctx context.Context, nodeID, relType string, opts ReifyOptions) (*Relationship, error) {
+	id, err := parseID(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults(relType)
+	for _, name := range []string{relType, opts.SourceType, opts.TargetType} {
+		if err := validLabelIdentifier(name); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := unreifyTx(ctx, tx, id, relType, opts)
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return nil, fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+func unreifyTx(ctx context.Context, tx *Transaction, id int64, relType string, opts ReifyOptions) (*Relationship, error) {
+	counts, err := tx.ExecuteCypher(ctx,
+		"MATCH (m)-[x]-() WHERE id(m) = $id RETURN type(x) AS type, count(x) AS n",
+		map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range counts.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		t, n := fmt.Sprint(row[0]), asInt(row[1])
+		if (t != opts.SourceType && t != opts.TargetType) || n != 1 {
+			return nil, fmt.Errorf("nexus: node %d has %d %s relationship(s); expected exactly one %s and one %s",
+				id, n, t, opts.SourceType, opts.TargetType)
+		}
+	}
+
+	query := fmt.Sprintf(
+		"MATCH (a)<-[:%s]-(m)-[:%s]->(b) WHERE id(m) = $id "+
+			"CREATE (a)-[r:%s]->(b) SET r = properties(m) DETACH DELETE m "+
+			"RETURN id(r) AS id, id(a) AS start, id(b) AS end, properties(r) AS props",
+		opts.SourceType, opts.TargetType, relType)
+	result, err := tx.ExecuteCypher(ctx, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 4 {
+		return nil, fmt.Errorf("nexus: node %d is not a reified relationship", id)
+	}
+	row := result.Rows[0]
+	props, _ := row[3].(map[string]interface{})
+	return &Relationship{
+		ID:         idString(row[0]),
+		Type:       relType,
+		StartNode:  idString(row[1]),
+		EndNode:    idString(row[2]),
+		Properties: props,
+	}, nil
+}
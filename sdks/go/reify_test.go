@@ -0,0 +1,200 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReifyRelationship(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req["query"], "CREATE (m:Purchase) SET m = properties(r)")
+		assert.Contains(t, req["query"], "CREATE (a)<-[:BUYER]-(m)-[:TARGET]->(b) DELETE r")
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"columns": []string{"id", "labels", "props"},
+			"rows":    [][]interface{}{{7, []string{"Purchase"}, map[string]interface{}{"qty": 2}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	node, err := client.ReifyRelationship(context.Background(), "5", ReifyOptions{Label: "Purchase", SourceType: "BUYER"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "7", node.ID)
+	assert.Equal(t, []string{"Purchase"}, node.Labels)
+	assert.EqualValues(t, 2, node.Properties["qty"])
+
+	_, err = client.ReifyRelationship(context.Background(), "5", ReifyOptions{Label: "Bad Label"})
+	assert.Error(t, err)
+}
+
+// reifyGraph is an in-memory graph answering the statements
+// ReifyRelationship and UnreifyNode send.
+type reifyGraph struct {
+	mu         sync.Mutex
+	nodes      map[int64][]string // id → labels
+	props      map[int64]map[string]interface{}
+	rels       map[int64]reifyRel
+	next       int64
+	rolledBack bool
+}
+
+type reifyRel struct {
+	typ        string
+	start, end int64
+	props      map[string]interface{}
+}
+
+var (
+	reifyPattern   = regexp.MustCompile(`CREATE \(m:(\w+)\).*CREATE \(a\)<-\[:(\w+)\]-\(m\)-\[:(\w+)\]->\(b\)`)
+	unreifyPattern = regexp.MustCompile(`MATCH \(a\)<-\[:(\w+)\]-\(m\)-\[:(\w+)\]->\(b\).*CREATE \(a\)-\[r:(\w+)\]->\(b\)`)
+)
+
+func (g *reifyGraph) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch r.URL.Path {
+	case "/transaction/begin":
+		json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx"})
+		return
+	case "/transaction/commit":
+		w.Write([]byte(`{}`))
+		return
+	case "/transaction/rollback":
+		g.rolledBack = true
+		w.Write([]byte(`{}`))
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/relationships/") {
+		id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/relationships/"), 10, 64)
+		rel := g.rels[id]
+		json.NewEncoder(w).Encode(Relationship{ID: fmt.Sprint(id), Type: rel.typ, StartNode: fmt.Sprint(rel.start), EndNode: fmt.Sprint(rel.end), Properties: rel.props})
+		return
+	}
+	var req struct {
+		Query      string                 `json:"query"`
+		Parameters map[string]interface{} `json:"parameters"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	id := int64(req.Parameters["id"].(float64))
+	var columns []string
+	var rows [][]interface{}
+	if m := reifyPattern.FindStringSubmatch(req.Query); m != nil {
+		columns = []string{"id", "labels", "props"}
+		if rel, ok := g.rels[id]; ok {
+			delete(g.rels, id)
+			node := g.add([]string{m[1]}, rel.props)
+			g.link(m[2], node, rel.start, nil)
+			g.link(m[3], node, rel.end, nil)
+			rows = append(rows, []interface{}{node, g.nodes[node], g.props[node]})
+		}
+	} else if m := unreifyPattern.FindStringSubmatch(req.Query); m != nil {
+		columns = []string{"id", "start", "end", "props"}
+		var a, b int64
+		for rid, rel := range g.rels {
+			switch {
+			case rel.start == id && rel.typ == m[1]:
+				a = rel.end
+			case rel.start == id && rel.typ == m[2]:
+				b = rel.end
+			}
+			if rel.start == id || rel.end == id {
+				delete(g.rels, rid)
+			}
+		}
+		if a != 0 && b != 0 {
+			rid := g.link(m[3], a, b, g.props[id])
+			rows = append(rows, []interface{}{rid, a, b, g.props[id]})
+			delete(g.nodes, id)
+		}
+	} else if strings.HasPrefix(req.Query, "MATCH (m)-[x]-()") {
+		columns = []string{"type", "n"}
+		counts := map[string]int{}
+		for _, rel := range g.rels {
+			if rel.start == id || rel.end == id {
+				counts[rel.typ]++
+			}
+		}
+		types := make([]string, 0, len(counts))
+		for typ := range counts {
+			types = append(types, typ)
+		}
+		sort.Strings(types)
+		for _, typ := range types {
+			rows = append(rows, []interface{}{typ, counts[typ]})
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"columns": columns, "rows": rows})
+}
+
+func (g *reifyGraph) add(labels []string, props map[string]interface{}) int64 {
+	g.next++
+	g.nodes[g.next], g.props[g.next] = labels, props
+	return g.next
+}
+
+func (g *reifyGraph) link(typ string, start, end int64, props map[string]interface{}) int64 {
+	g.next++
+	g.rels[g.next] = reifyRel{typ: typ, start: start, end: end, props: props}
+	return g.next
+}
+
+func TestReifyRoundTrip(t *testing.T) {
+	g := &reifyGraph{nodes: map[int64][]string{}, props: map[int64]map[string]interface{}{}, rels: map[int64]reifyRel{}}
+	ann := g.add([]string{"Person"}, nil)
+	book := g.add([]string{"Book"}, nil)
+	bought := g.link("BOUGHT", ann, book, map[string]interface{}{"qty": 2.0})
+	server := httptest.NewServer(g)
+	defer server.Close()
+	ctx := context.Background()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	// The default label comes from the relationship's type.
+	node, err := client.ReifyRelationship(ctx, fmt.Sprint(bought), ReifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BOUGHT"}, node.Labels)
+	assert.Equal(t, map[string]interface{}{"qty": int64(2)}, node.Properties)
+	assert.Len(t, g.rels, 2, "SOURCE and TARGET links replace the relationship")
+
+	rel, err := client.UnreifyNode(ctx, node.ID, "BOUGHT", ReifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "BOUGHT", rel.Type)
+	assert.Equal(t, fmt.Sprint(ann), rel.StartNode)
+	assert.Equal(t, fmt.Sprint(book), rel.EndNode)
+	assert.EqualValues(t, 2, rel.Properties["qty"])
+	assert.Len(t, g.rels, 1)
+	assert.NotContains(t, g.nodes, mustParseID(t, node.ID))
+
+	// Custom link types must match on the way back; a node linked to
+	// anything else is refused and the transaction rolled back.
+	node, err = client.ReifyRelationship(ctx, rel.ID, ReifyOptions{Label: "Purchase", SourceType: "BUYER", TargetType: "ITEM"})
+	require.NoError(t, err)
+	review := g.add([]string{"Review"}, nil)
+	g.link("ABOUT", review, mustParseID(t, node.ID), nil)
+	_, err = client.UnreifyNode(ctx, node.ID, "BOUGHT", ReifyOptions{SourceType: "BUYER", TargetType: "ITEM"})
+	assert.ErrorContains(t, err, "1 ABOUT relationship(s)")
+	assert.True(t, g.rolledBack)
+	assert.Contains(t, g.nodes, mustParseID(t, node.ID))
+}
+
+func mustParseID(t *testing.T, id string) int64 {
+	n, err := parseID(id)
+	require.NoError(t, err)
+	return n
+}
@@ -0,0 +1,219 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// RenderedQueryHeader starts every RenderQuery output, so rendered text
+// pasted into logs is never mistaken for the statement that ran.
+const RenderedQueryHeader = "// nexus.RenderQuery: debugging preview, parameters inlined; not executable as sent"
+
+// RenderQuery returns query with its parameters replaced by Cypher
+// literals, for logs and for reproducing an issue in a REPL:
+//
+//	log.Println(nexus.RenderQuery(query, params))
+//
+// Strings are quoted and escaped, maps and lists are written as Cypher
+// literals, time.Time values become datetime() calls and structs take a
+// JSON round trip. References inside string literals and comments are
+// left alone, and parameters missing from params stay as `$name` with a
+// /* unbound */ marker. The output starts with RenderedQueryHeader.
+//
+// The result is meant for reading: server-side parameter handling
+// (plan caching, type coercion) differs from literals, so never execute
+// it in place of the parameterized query.
+func RenderQuery(query string, params map[string]interface{}) string {
+	runes := []rune(query)
+	var b strings.Builder
+	b.WriteString(RenderedQueryHeader)
+	b.WriteByte('\n')
+	last := 0
+	scanParameters(runes, func(start, end int, name string) {
+		b.WriteString(string(runes[last:start]))
+		last = end
+		v, ok := params[name]
+		if !ok {
+			b.WriteString(string(runes[start:end]))
+			b.WriteString(" /* unbound */")
+			return
+		}
+		writeCypherLiteral(&b, v)
+	})
+	b.WriteString(string(runes[last:]))
+	return b.String()
+}
+
+// writeCypherLiteral writes v as a Cypher literal.
+func writeCypherLiteral(b *strings.Builder, v interface{}) {
+	switch x := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(x))
+	case string:
+		b.WriteString(quoteCypherString(x))
+	case int:
+		b.WriteString(strconv.Itoa(x))
+	case int64:
+		b.WriteString(strconv.FormatInt(x, 10))
+	case float64:
+		writeCypherFloat(b, x)
+	case float32:
+		writeCypherFloat(b, float64(x))
+	case json.Number:
+		b.WriteString(x.String())
+	case []byte:
+		// Byte arrays have no literal form; show them as a hex string.
+		fmt.Fprintf(b, "'%x' /* bytes */", x)
+	case time.Time:
+		fmt.Fprintf(b, "datetime(%s)", quoteCypherString(x.Format(time.RFC3339Nano)))
+	case time.Duration:
+		fmt.Fprintf(b, "duration(%s)", quoteCypherString(cypherDuration(x)))
+	case []interface{}:
+		b.WriteByte('[')
+		for i, e := range x {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeCypherLiteral(b, e)
+		}
+		b.WriteByte(']')
+	case map[string]interface{}:
+		b.WriteByte('{')
+		for i, k := range sortedKeys(x) {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(cypherKey(k))
+			b.WriteString(": ")
+			writeCypherLiteral(b, x[k])
+		}
+		b.WriteByte('}')
+	default:
+		writeReflectedLiteral(b, v)
+	}
+}
+
+// writeReflectedLiteral handles the remaining numeric kinds, typed
+// slices and maps, and falls back to a JSON round trip for structs.
+func writeReflectedLiteral(b *strings.Builder, v interface{}) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(rv.Int(), 10))
+		return
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b.WriteString(strconv.FormatUint(rv.Uint(), 10))
+		return
+	case reflect.Float32, reflect.Float64:
+		writeCypherFloat(b, rv.Float())
+		return
+	case reflect.String:
+		b.WriteString(quoteCypherString(rv.String()))
+		return
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(rv.Bool()))
+		return
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			b.WriteString("null")
+			return
+		}
+		writeCypherLiteral(b, rv.Elem().Interface())
+		return
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			b.WriteString("null")
+			return
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		writeCypherLiteral(b, items)
+		return
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			m := make(map[string]interface{}, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				m[iter.Key().String()] = iter.Value().Interface()
+			}
+			writeCypherLiteral(b, m)
+			return
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(b, "null /* %T: %v */", v, err)
+		return
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		fmt.Fprintf(b, "null /* %T: %v */", v, err)
+		return
+	}
+	writeCypherLiteral(b, decoded)
+}
+
+func writeCypherFloat(b *strings.Builder, f float64) {
+	switch {
+	case math.IsNaN(f):
+		b.WriteString("0.0/0.0 /* NaN */")
+	case math.IsInf(f, 1):
+		b.WriteString("1.0/0.0 /* +Inf */")
+	case math.IsInf(f, -1):
+		b.WriteString("-1.0/0.0 /* -Inf */")
+	default:
+		s := strconv.FormatFloat(f, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEn") {
+			s += ".0" // keep whole floats typed as floats
+		}
+		b.WriteString(s)
+	}
+}
+
+// quoteCypherString writes s as a single-quoted Cypher string.
+func quoteCypherString(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'':
+			b.WriteString(`\'`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// cypherKey writes a map key bare when it is a plain identifier and in
+// backticks otherwise.
+func cypherKey(k string) string { return cypherlex.QuoteName(k) }
+
+// cypherDuration formats d as an ISO 8601 duration.
+func cypherDuration(d time.Duration) string {
+	return "PT" + strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+}
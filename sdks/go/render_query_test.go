@@ -0,0 +1,47 @@
+package nexus
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderQuery(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	out := RenderQuery(
+		"MATCH (p:Person {name: $name}) // $name stays\n"+
+			"WHERE p.tags = $tags AND p.meta = $meta AND p.at > $at AND p.note = '$name' "+
+			"AND p.score > $score AND p.home = $`home addr` AND p.x = $missing RETURN p LIMIT $limit",
+		map[string]interface{}{
+			"name":      "O'Brien \\ \"Ann\"\n",
+			"tags":      []string{"a", "b"},
+			"meta":      map[string]interface{}{"z": nil, "a b": true, "k": int32(3)},
+			"at":        at,
+			"score":     2.0,
+			"home addr": &address{City: "Oslo"},
+			"limit":     10,
+		})
+	lines := strings.SplitN(out, "\n", 2)
+	assert.Equal(t, RenderedQueryHeader, lines[0])
+	assert.Equal(t,
+		`MATCH (p:Person {name: 'O\'Brien \\ "Ann"\n'}) // $name stays`+"\n"+
+			"WHERE p.tags = ['a', 'b'] AND p.meta = {`a b`: true, k: 3, z: null} AND p.at > datetime('2024-05-01T12:00:00Z') AND p.note = '$name' "+
+			"AND p.score > 2.0 AND p.home = {city: 'Oslo'} AND p.x = $missing /* unbound */ RETURN p LIMIT 10",
+		lines[1])
+}
+
+func TestRenderQuerySpecialValues(t *testing.T) {
+	out := RenderQuery("RETURN $a, $b, $c, $d", map[string]interface{}{
+		"a": math.NaN(),
+		"b": []byte{0xca, 0xfe},
+		"c": "\x00",
+		"d": (*int)(nil),
+	})
+	assert.True(t, strings.HasSuffix(out, `RETURN 0.0/0.0 /* NaN */, 'cafe' /* bytes */, '\u0000', null`), out)
+}
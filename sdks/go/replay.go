@@ -0,0 +1,120 @@
+package nexus
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// ReplayOptions tunes ReplayTraffic.
+type ReplayOptions struct {
+	// Speed scales the recorded pacing: 1 sends statements at their
+	// original intervals, 2 twice as fast. Zero sends them as fast as
+	// Concurrency allows.
+	Speed float64
+	// Concurrency bounds the statements in flight. Defaults to 16.
+	Concurrency int
+	// ReadOnly skips statements AnalyzeQuery classifies as writes, so a
+	// capture can be replayed against a production copy safely.
+	ReadOnly bool
+	// OnResult, when set, is called after each statement with its
+	// outcome, e.g. to compare row counts with the capture. It may be
+	// called concurrently.
+	OnResult func(rec CapturedQuery, result *QueryResult, err error)
+}
+
+// ReplayReport summarizes a replay.
+type ReplayReport struct {
+	Replayed int
+	Failed   int
+	Skipped  int
+	Elapsed  time.Duration
+	// Latencies are the replayed statements' statistics per
+	// QueryFingerprint, busiest first.
+	Latencies []FingerprintStats
+}
+
+// ReplayTraffic re-executes a capture written by TrafficRecorder
+// against e, at the recorded pace scaled by Speed. Statements recorded
+// inside transactions are replayed on their own. Failed statements are
+// counted, not fatal; the replay stops early only when ctx is done.
+func ReplayTraffic(ctx context.Context, capture io.Reader, e CypherExecutor, opts ReplayOptions) (*ReplayReport, error) {
+	records, err := ReadCapture(capture)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 16
+	}
+
+	var (
+		report = &ReplayReport{}
+		agg    = NewQueryAggregator(QueryAggregatorOptions{})
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		slots  = make(chan struct{}, opts.Concurrency)
+		start  = time.Now()
+	)
+	for _, rec := range records {
+		if opts.ReadOnly && AnalyzeQuery(rec.Query).Operation != OperationRead {
+			report.Skipped++
+			continue
+		}
+		if opts.Speed > 0 && !records[0].Time.IsZero() {
+			due := start.Add(time.Duration(float64(rec.Time.Sub(records[0].Time)) / opts.Speed))
+			if err := sleepUntil(ctx, due); err != nil {
+				break
+			}
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(rec CapturedQuery) {
+			defer func() { <-slots; wg.Done() }()
+			began := time.Now()
+			result, err := e.ExecuteCypher(ctx, rec.Query, rec.Params)
+			event := QueryEvent{Query: rec.Query, Start: began, Duration: time.Since(began), Err: err}
+			event.Normalized = NormalizeQuery(rec.Query)
+			event.Fingerprint = fingerprintOf(event.Normalized)
+			if result != nil {
+				event.Rows = len(result.Rows)
+			}
+			agg.ObserveQuery(ctx, event)
+			mu.Lock()
+			report.Replayed++
+			if err != nil {
+				report.Failed++
+			}
+			mu.Unlock()
+			if opts.OnResult != nil {
+				opts.OnResult(rec, result, err)
+			}
+		}(rec)
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	report.Latencies = agg.Snapshot()
+	return report, ctx.Err()
+}
+
+// sleepUntil waits for t or for ctx to be done.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
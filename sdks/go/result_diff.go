@@ -0,0 +1,213 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ResultDiff is the difference between two query results, from a to b.
+type ResultDiff struct {
+	// Columns present in only one of the results.
+	AddedColumns   []string
+	RemovedColumns []string
+	// Added holds the rows of b with no counterpart in a, Removed the
+	// rows of a with none in b, each in its own result's column order.
+	Added   [][]interface{}
+	Removed [][]interface{}
+	// Changed pairs rows with the same key whose other values differ.
+	Changed []RowChange
+}
+
+// RowChange is one row present in both results with different values.
+type RowChange struct {
+	// Key holds the values of the key columns.
+	Key    []interface{}
+	Before []interface{}
+	After  []interface{}
+	// Columns names the shared columns whose values differ.
+	Columns []string
+}
+
+// Empty reports whether the results matched.
+func (d *ResultDiff) Empty() bool {
+	return len(d.AddedColumns) == 0 && len(d.RemovedColumns) == 0 &&
+		len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String summarises the diff, one line per difference, for test
+// failure messages.
+func (d *ResultDiff) String() string {
+	if d.Empty() {
+		return "no differences"
+	}
+	var b strings.Builder
+	for _, c := range d.AddedColumns {
+		fmt.Fprintf(&b, "+ column %s\n", c)
+	}
+	for _, c := range d.RemovedColumns {
+		fmt.Fprintf(&b, "- column %s\n", c)
+	}
+	for _, row := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", diffValue(row))
+	}
+	for _, row := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", diffValue(row))
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "~ %s: %s\n", diffValue(c.Key), strings.Join(c.Columns, ", "))
+	}
+	return b.String()
+}
+
+// DiffResults compares two results, e.g. a golden file against a live
+// query or staging against production. Rows are matched on keyColumns,
+// which must be present and unique in both; a row whose key is in both
+// results but whose other shared columns differ is Changed. With no
+// key columns, whole rows are matched, so rows are only ever Added or
+// Removed, with duplicates counted. Columns are matched by name, not
+// position, and numbers compare by value, so int64(1) equals 1.0.
+func DiffResults(a, b *QueryResult, keyColumns ...string) (*ResultDiff, error) {
+	d := &ResultDiff{}
+	var shared []string
+	for _, c := range a.Columns {
+		if columnIndex(b.Columns, c) < 0 {
+			d.RemovedColumns = append(d.RemovedColumns, c)
+		} else {
+			shared = append(shared, c)
+		}
+	}
+	for _, c := range b.Columns {
+		if columnIndex(a.Columns, c) < 0 {
+			d.AddedColumns = append(d.AddedColumns, c)
+		}
+	}
+
+	if len(keyColumns) == 0 {
+		keyColumns = shared
+		counts := map[string]int{}
+		for _, row := range a.Rows {
+			counts[diffKey(a.Columns, row, keyColumns)]++
+		}
+		for _, row := range b.Rows {
+			k := diffKey(b.Columns, row, keyColumns)
+			if counts[k] > 0 {
+				counts[k]--
+			} else {
+				d.Added = append(d.Added, row)
+			}
+		}
+		for _, row := range a.Rows {
+			k := diffKey(a.Columns, row, keyColumns)
+			if counts[k] > 0 {
+				counts[k]--
+				d.Removed = append(d.Removed, row)
+			}
+		}
+		return d, nil
+	}
+
+	for _, k := range keyColumns {
+		if columnIndex(a.Columns, k) < 0 || columnIndex(b.Columns, k) < 0 {
+			return nil, fmt.Errorf("nexus: diff: key column %q is not in both results (columns %v and %v)", k, a.Columns, b.Columns)
+		}
+	}
+	before, err := indexRows(a, keyColumns)
+	if err != nil {
+		return nil, err
+	}
+	after, err := indexRows(b, keyColumns)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range b.Rows {
+		old, ok := before[diffKey(b.Columns, row, keyColumns)]
+		if !ok {
+			d.Added = append(d.Added, row)
+			continue
+		}
+		var changed []string
+		for _, c := range shared {
+			if !diffEqual(cellByName(a.Columns, old, c), cellByName(b.Columns, row, c)) {
+				changed = append(changed, c)
+			}
+		}
+		if len(changed) > 0 {
+			key := make([]interface{}, len(keyColumns))
+			for i, k := range keyColumns {
+				key[i] = cellByName(b.Columns, row, k)
+			}
+			d.Changed = append(d.Changed, RowChange{Key: key, Before: old, After: row, Columns: changed})
+		}
+	}
+	for _, row := range a.Rows {
+		if _, ok := after[diffKey(a.Columns, row, keyColumns)]; !ok {
+			d.Removed = append(d.Removed, row)
+		}
+	}
+	return d, nil
+}
+
+func indexRows(qr *QueryResult, keyColumns []string) (map[string][]interface{}, error) {
+	index := make(map[string][]interface{}, len(qr.Rows))
+	for _, row := range qr.Rows {
+		k := diffKey(qr.Columns, row, keyColumns)
+		if _, dup := index[k]; dup {
+			return nil, fmt.Errorf("nexus: diff: key %s is not unique", k)
+		}
+		index[k] = row
+	}
+	return index, nil
+}
+
+func cellByName(columns []string, row []interface{}, name string) interface{} {
+	if i := columnIndex(columns, name); i >= 0 && i < len(row) {
+		return row[i]
+	}
+	return nil
+}
+
+// diffKey encodes the named cells of row, with numbers normalised.
+func diffKey(columns []string, row []interface{}, names []string) string {
+	key := make([]interface{}, len(names))
+	for i, n := range names {
+		key[i] = cellByName(columns, row, n)
+	}
+	return diffValue(key)
+}
+
+func diffValue(v interface{}) string {
+	data, err := json.Marshal(normalizeNumbers(v))
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func diffEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeNumbers(a), normalizeNumbers(b))
+}
+
+// normalizeNumbers turns every number into a float64, recursively, so
+// results decoded by different transports compare equal.
+func normalizeNumbers(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			out[k] = normalizeNumbers(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, e := range x {
+			out[i] = normalizeNumbers(e)
+		}
+		return out
+	}
+	if f, ok := numericValue(v); ok {
+		return f
+	}
+	return v
+}
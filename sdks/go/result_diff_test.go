@@ -0,0 +1,60 @@
+package nexus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffResults(t *testing.T) {
+	golden := &QueryResult{
+		Columns: []string{"id", "name", "age"},
+		Rows: [][]interface{}{
+			{float64(1), "Ann", float64(42)},
+			{float64(2), "Bob", float64(30)},
+			{float64(3), "Cid", float64(25)},
+		},
+	}
+	live := &QueryResult{
+		Columns: []string{"name", "id", "city"},
+		Rows: [][]interface{}{
+			{"Ann", int64(1), "Oslo"},
+			{"Bobby", int64(2), "Rome"},
+			{"Dee", int64(4), nil},
+		},
+	}
+
+	d, err := DiffResults(golden, live, "id")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"city"}, d.AddedColumns)
+	assert.Equal(t, []string{"age"}, d.RemovedColumns)
+	assert.Equal(t, [][]interface{}{{"Dee", int64(4), nil}}, d.Added)
+	assert.Equal(t, [][]interface{}{{float64(3), "Cid", float64(25)}}, d.Removed)
+	require.Len(t, d.Changed, 1)
+	assert.Equal(t, []interface{}{int64(2)}, d.Changed[0].Key)
+	assert.Equal(t, []string{"name"}, d.Changed[0].Columns)
+	assert.Equal(t, "+ column city\n- column age\n- [3,\"Cid\",25]\n+ [\"Dee\",4,null]\n~ [2]: name\n", d.String())
+
+	_, err = DiffResults(golden, live, "age")
+	assert.ErrorContains(t, err, `key column "age"`)
+	dup := &QueryResult{Columns: []string{"id"}, Rows: [][]interface{}{{1}, {1.0}}}
+	_, err = DiffResults(dup, dup, "id")
+	assert.ErrorContains(t, err, "not unique")
+
+	d, err = DiffResults(golden, golden, "id")
+	require.NoError(t, err)
+	assert.True(t, d.Empty())
+	assert.Equal(t, "no differences", d.String())
+}
+
+func TestDiffResultsWholeRows(t *testing.T) {
+	a := &QueryResult{Columns: []string{"x"}, Rows: [][]interface{}{{int64(1)}, {int64(1)}, {int64(2)}}}
+	b := &QueryResult{Columns: []string{"x"}, Rows: [][]interface{}{{1.0}, {3.0}}}
+
+	d, err := DiffResults(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{3.0}}, d.Added)
+	assert.Equal(t, [][]interface{}{{int64(1)}, {int64(2)}}, d.Removed)
+	assert.Empty(t, d.Changed)
+}
@@ -0,0 +1,73 @@
+package nexus
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// cypherResponses recycles the row storage of released results.
+var cypherResponses = sync.Pool{New: func() any { return new(transport.CypherResponse) }}
+
+func getCypherResponse() *transport.CypherResponse {
+	return cypherResponses.Get().(*transport.CypherResponse)
+}
+
+func putCypherResponse(resp *transport.CypherResponse) {
+	// Drop the cells so a pooled response does not keep them alive.
+	for _, row := range resp.Rows {
+		clear(row)
+	}
+	cypherResponses.Put(resp)
+}
+
+// Release hands the result's row storage back to the client for reuse
+// by later queries, which saves most of the allocations of decoding a
+// large result. The result must not be used afterwards, nor any row
+// taken from it, so only release results you own and have finished
+// with:
+//
+//	result, err := client.ExecuteCypher(ctx, query, params)
+//	…
+//	total := sum(result.Rows)
+//	result.Release()
+//
+// Releasing is optional: results never released are garbage collected
+// as usual. Release is a no-op on results the client did not decode
+// itself, such as ones built by hand or served from a cache.
+func (qr *QueryResult) Release() {
+	if qr == nil || qr.pooled == nil {
+		return
+	}
+	pooled := qr.pooled
+	qr.pooled, qr.Rows = nil, nil
+	putCypherResponse(pooled)
+}
+
+// fillCypherResponse copies a CYPHER response decoded as plain Go
+// values into dst.
+func fillCypherResponse(v interface{}, dst *transport.CypherResponse) error {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("nexus: CYPHER: expected object response, got %T", v)
+	}
+	*dst = transport.CypherResponse{Rows: dst.Rows[:0]}
+	dst.Columns, _ = obj["columns"].([]interface{})
+	if rows, ok := obj["rows"].([]interface{}); ok {
+		for _, r := range rows {
+			row, _ := r.([]interface{})
+			dst.Rows = append(dst.Rows, row)
+		}
+	} else {
+		dst.Rows = nil
+	}
+	dst.Stats, _ = obj["stats"].(map[string]interface{})
+	if etMs, ok := obj["execution_time_ms"]; ok {
+		ms := asFloat(etMs)
+		dst.ExecutionTimeMs = &ms
+	}
+	dst.Notifications, _ = obj["notifications"].([]interface{})
+	dst.ColumnTypes, _ = obj["column_types"].([]interface{})
+	return nil
+}
@@ -0,0 +1,40 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryResultRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"columns":["n"],"rows":[[1],[2]]}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	first, err := client.ExecuteCypher(ctx, "RETURN 1 AS n", nil)
+	require.NoError(t, err)
+	kept := first.Rows
+	assert.Equal(t, [][]interface{}{{int64(1)}, {int64(2)}}, kept)
+
+	second, err := client.ExecuteCypher(ctx, "RETURN 1 AS n", nil)
+	require.NoError(t, err)
+	second.Release()
+	assert.Nil(t, second.Rows)
+	second.Release()
+
+	third, err := client.ExecuteCypher(ctx, "RETURN 1 AS n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{int64(1)}, {int64(2)}}, third.Rows)
+	assert.Equal(t, [][]interface{}{{int64(1)}, {int64(2)}}, kept, "unreleased rows stay intact")
+
+	(&QueryResult{Rows: [][]interface{}{{1}}}).Release()
+	(*QueryResult)(nil).Release()
+}
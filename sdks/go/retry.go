@@ -3,9 +3,12 @@ package nexus
 
 import (
 	"context"
+	"errors"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -23,6 +26,20 @@ type RetryConfig struct {
 	Jitter bool
 	// RetryableStatusCodes defines which HTTP status codes should trigger a retry
 	RetryableStatusCodes []int
+	// OnRetry, when set, is called before each retry with the attempt
+	// that failed (for logging and metrics)
+	OnRetry func(RetryAttempt)
+}
+
+// RetryAttempt describes a failed attempt that is about to be retried.
+type RetryAttempt struct {
+	// Attempt is the 1-based number of the attempt that failed
+	Attempt int
+	Err     error
+	// Conflict is set when Err is a transient conflict (see IsTransientConflict)
+	Conflict bool
+	// Backoff is the wait before the next attempt
+	Backoff time.Duration
 }
 
 // DefaultRetryConfig returns a RetryConfig with sensible defaults.
@@ -50,6 +67,22 @@ func (c *RetryConfig) isRetryableError(err error) bool {
 		return false
 	}
 
+	// Deadlocks and write conflicts are retryable whatever their status
+	if IsTransientConflict(err) {
+		return true
+	}
+
+	// An open circuit fails fast; retrying would defeat it
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	// Escalated notifications describe the query, not the attempt
+	var notification *NotificationError
+	if errors.As(err, &notification) {
+		return false
+	}
+
 	// Check if it's a Nexus API error with a retryable status code
 	if apiErr, ok := err.(*Error); ok {
 		for _, code := range c.RetryableStatusCodes {
@@ -82,7 +115,10 @@ func (c *RetryConfig) calculateBackoff(attempt int) time.Duration {
 	return duration
 }
 
-// RetryableClient wraps a Client with retry functionality.
+// RetryableClient is a Client whose requests all retry: every method,
+// including those promoted from Client, goes through the retrying
+// request path (see Config.Retry). It adds ExecuteWrite and
+// ExecuteInTransaction, which re-run whole transactions on conflicts.
 type RetryableClient struct {
 	*Client
 	retryConfig *RetryConfig
@@ -93,6 +129,7 @@ func NewRetryableClient(config Config, retryConfig *RetryConfig) *RetryableClien
 	if retryConfig == nil {
 		retryConfig = DefaultRetryConfig()
 	}
+	config.Retry = retryConfig
 
 	return &RetryableClient{
 		Client:      NewClient(config),
@@ -100,169 +137,132 @@ func NewRetryableClient(config Config, retryConfig *RetryConfig) *RetryableClien
 	}
 }
 
-// WithRetry adds retry capability to an existing client.
+// WithRetry returns a view of the client whose requests retry. The view
+// shares the client's connections and lifecycle: closing either closes
+// both. The original client is unchanged.
 func (c *Client) WithRetry(retryConfig *RetryConfig) *RetryableClient {
 	if retryConfig == nil {
 		retryConfig = DefaultRetryConfig()
 	}
+	view := *c
+	view.retry = retryConfig
 
 	return &RetryableClient{
-		Client:      c,
+		Client:      &view,
 		retryConfig: retryConfig,
 	}
 }
 
-// doRequestWithRetry performs an HTTP request with automatic retry on failure.
-func (rc *RetryableClient) doRequestWithRetry(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var lastErr error
-
-	for attempt := 0; attempt <= rc.retryConfig.MaxRetries; attempt++ {
-		// Check context cancellation before each attempt
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		// Make the request
-		resp, err := rc.Client.doRequest(ctx, method, path, body)
-		if err == nil {
-			return resp, nil
-		}
-
-		lastErr = err
-
-		// Check if we should retry
-		if !rc.retryConfig.isRetryableError(err) {
-			return nil, err
-		}
-
-		// Don't sleep after the last attempt
-		if attempt < rc.retryConfig.MaxRetries {
-			backoff := rc.retryConfig.calculateBackoff(attempt)
-
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(backoff):
-				// Continue to next attempt
-			}
-		}
-	}
-
-	return nil, lastErr
-}
-
-// ExecuteCypher executes a Cypher query with automatic retry.
-func (rc *RetryableClient) ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
-	reqBody := map[string]interface{}{
-		"query": query,
-	}
-	if params != nil {
-		reqBody["parameters"] = params
-	}
-
-	resp, err := rc.doRequestWithRetry(ctx, http.MethodPost, "/cypher", reqBody)
+// sendWithRetry performs an HTTP request, retrying it as c.retry allows.
+// When retries run out the last error is returned wrapped in a *RetryError.
+//
+// The server does not deduplicate requests, so a POST or PATCH that is
+// not a read-only query is only retried when the failure shows it was
+// not applied (see notApplied): a write whose response was lost is not
+// sent twice. Such requests carry an Idempotency-Key, the same on every
+// attempt, so the attempts can be correlated in logs and by proxies.
+func (c *Client) sendWithRetry(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	safe := replayable(method, body)
+	// Resolve the body once: values are marshalled a single time and
+	// readers buffered, so every attempt sends the same bytes.
+	rb, err := requestBody(body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	var result QueryResult
-	if err := decodeResponse(resp, &result); err != nil {
-		return nil, err
+	if rb != nil {
+		body = rb
+	}
+	if (method == http.MethodPost || method == http.MethodPatch) && IdempotencyKeyFromContext(ctx) == "" {
+		ctx = WithIdempotencyKey(ctx, newIdempotencyKey())
+	}
+	// A conflict inside a transaction aborts it: retrying the statement
+	// cannot help, re-running the transaction (ExecuteWrite) can.
+	inTx := strings.HasPrefix(path, "/transaction/") && path != "/transaction/begin" && path != "/transaction/run"
+	retryable := func(err error) bool {
+		if inTx && IsTransientConflict(err) {
+			return false
+		}
+		if !safe && !notApplied(err) {
+			return false
+		}
+		return c.retry.isRetryableError(err)
 	}
 
-	return &result, nil
+	var resp *http.Response
+	err = c.retry.do(ctx, retryable, func() error {
+		var err error
+		resp, err = c.send(ctx, method, path, body)
+		return err
+	})
+	return resp, err
 }
 
-// Ping checks if the server is reachable with automatic retry.
-func (rc *RetryableClient) Ping(ctx context.Context) error {
-	resp, err := rc.doRequestWithRetry(ctx, http.MethodGet, "/health", nil)
-	if err != nil {
-		return err
+// replayable reports whether a request may be sent again after any
+// failure: requests with idempotent methods, and read-only Cypher.
+func replayable(method string, body interface{}) bool {
+	if method != http.MethodPost && method != http.MethodPatch {
+		return true
 	}
-	defer resp.Body.Close()
-
-	return nil
+	cr, ok := body.(cypherRequest)
+	return ok && AnalyzeQuery(cr.Query).Operation == OperationRead
 }
 
-// CreateNode creates a new node with automatic retry.
-func (rc *RetryableClient) CreateNode(ctx context.Context, labels []string, properties map[string]interface{}) (*Node, error) {
-	reqBody := map[string]interface{}{
-		"labels":     labels,
-		"properties": properties,
+// notApplied reports whether err shows the server did not apply the
+// request: the connection was never made, the server turned the
+// request away with 429 or 503, or it aborted it on a transient
+// conflict.
+func notApplied(err error) bool {
+	if IsTransientConflict(err) {
+		return true
 	}
-
-	resp, err := rc.doRequestWithRetry(ctx, http.MethodPost, "/nodes", reqBody)
-	if err != nil {
-		return nil, err
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable
 	}
-	defer resp.Body.Close()
-
-	var node Node
-	if err := decodeResponse(resp, &node); err != nil {
-		return nil, err
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
 	}
-
-	return &node, nil
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
 }
 
-// GetNode retrieves a node by its ID with automatic retry.
-func (rc *RetryableClient) GetNode(ctx context.Context, id string) (*Node, error) {
-	resp, err := rc.doRequestWithRetry(ctx, http.MethodGet, "/nodes/"+id, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var node Node
-	if err := decodeResponse(resp, &node); err != nil {
-		return nil, err
-	}
-
-	return &node, nil
-}
+// do runs attempt until it succeeds, fails with an error retryable
+// rejects, or MaxRetries retries have failed; then it returns the last
+// error wrapped in a *RetryError.
+func (c *RetryConfig) do(ctx context.Context, retryable func(error) bool, attempt func() error) error {
+	var lastErr error
+	start := time.Now()
 
-// ListLabels retrieves all node labels with automatic retry.
-//
-// Each entry carries the catalog id alongside the name (see
-// LabelInfo). Wire shape changed in nexus-server 1.15+ — see
-// hivellm/nexus#2.
-func (rc *RetryableClient) ListLabels(ctx context.Context) ([]LabelInfo, error) {
-	resp, err := rc.doRequestWithRetry(ctx, http.MethodGet, "/schema/labels", nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	for n := 0; n <= c.MaxRetries; n++ {
+		// Check context cancellation before each attempt
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-	var result struct {
-		Labels []LabelInfo `json:"labels"`
-	}
-	if err := decodeResponse(resp, &result); err != nil {
-		return nil, err
-	}
+		lastErr = attempt()
+		if lastErr == nil {
+			return nil
+		}
 
-	return result.Labels, nil
-}
+		// Check if we should retry
+		if !retryable(lastErr) {
+			return lastErr
+		}
 
-// ListRelationshipTypes retrieves all relationship types with automatic retry.
-//
-// Each entry carries the catalog id alongside the name (see
-// RelTypeInfo).
-func (rc *RetryableClient) ListRelationshipTypes(ctx context.Context) ([]RelTypeInfo, error) {
-	resp, err := rc.doRequestWithRetry(ctx, http.MethodGet, "/schema/rel_types", nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+		// Don't sleep after the last attempt
+		if n < c.MaxRetries {
+			backoff := c.calculateBackoff(n)
+			c.notify(n, lastErr, backoff)
 
-	var result struct {
-		Types []RelTypeInfo `json:"types"`
-	}
-	if err := decodeResponse(resp, &result); err != nil {
-		return nil, err
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+				// Continue to next attempt
+			}
+		}
 	}
 
-	return result.Types, nil
+	return &RetryError{Attempts: c.MaxRetries + 1, Elapsed: time.Since(start), Err: lastErr}
 }
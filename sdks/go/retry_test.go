@@ -0,0 +1,162 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer fails the first request to each path with 503 and
+// records the Idempotency-Key of every request.
+func flakyServer(t *testing.T) (*httptest.Server, func() map[string][]string) {
+	t.Helper()
+	var mu sync.Mutex
+	keys := map[string][]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		path := r.Method + " " + r.URL.Path
+		keys[path] = append(keys[path], r.Header.Get(IdempotencyKeyHeader))
+		first := len(keys[path]) == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/transaction/begin":
+			w.Write([]byte(`{"transaction_id":"tx"}`))
+		default:
+			w.Write([]byte(`{"node":{"id":1,"labels":["A"],"properties":{}},"columns":[],"rows":[]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() map[string][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return keys
+	}
+}
+
+func TestRetryCoversEveryMethod(t *testing.T) {
+	server, keys := flakyServer(t)
+	base := NewClient(Config{BaseURL: server.URL})
+	client := base.WithRetry(fastRetry())
+	ctx := context.Background()
+
+	_, err := client.UpdateNode(ctx, "1", map[string]interface{}{"x": 1})
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteNode(ctx, "1"))
+	tx, err := client.BeginTransaction(ctx)
+	require.NoError(t, err)
+	_, err = tx.ExecuteCypher(ctx, "CREATE (n)", nil)
+	require.NoError(t, err)
+	_, err = client.ExecuteCypher(ctx, "CREATE (n:A)", nil)
+	require.NoError(t, err)
+
+	got := keys()
+	for path, attempts := range got {
+		assert.Len(t, attempts, 2, path)
+	}
+	// Writes carry one key, reused by the retry; other methods none.
+	for _, path := range []string{"POST /transaction/begin", "POST /transaction/execute", "POST /cypher"} {
+		require.Len(t, got[path], 2, path)
+		assert.NotEmpty(t, got[path][0], path)
+		assert.Equal(t, got[path][0], got[path][1], path)
+	}
+	assert.NotEqual(t, got["POST /transaction/begin"][0], got["POST /cypher"][0])
+	assert.Equal(t, []string{"", ""}, got["DELETE /nodes/1"])
+
+	// The client WithRetry was called on does not retry.
+	err = base.DeleteNode(ctx, "2")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+}
+
+func TestRetryKeepsCallerIdempotencyKey(t *testing.T) {
+	server, keys := flakyServer(t)
+	client := NewClient(Config{BaseURL: server.URL, Retry: fastRetry()})
+
+	_, err := client.ExecuteCypherHTTP(WithIdempotencyKey(context.Background(), "batch-7"), "CREATE (n)", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch-7", "batch-7"}, keys()["POST /cypher"])
+	assert.Len(t, newIdempotencyKey(), 36)
+}
+
+func TestRetryReplaysWritesOnlyWhenNotApplied(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	status := int32(http.StatusBadGateway)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		calls[r.Method+" "+r.URL.Path+" "+req.Query]++
+		mu.Unlock()
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, Retry: fastRetry()})
+	ctx := context.Background()
+
+	// A 502 may come after the write was applied: writes are not
+	// replayed, reads and idempotent methods are.
+	_, err := client.ExecuteCypher(ctx, "CREATE (n)", nil)
+	require.Error(t, err)
+	_, err = client.CreateNode(ctx, []string{"A"}, nil)
+	require.Error(t, err)
+	_, err = client.ExecuteCypherHTTP(ctx, "MATCH (n) RETURN n", nil)
+	require.Error(t, err)
+	require.Error(t, client.DeleteNode(ctx, "1"))
+	mu.Lock()
+	assert.Equal(t, map[string]int{
+		"POST /cypher CREATE (n)":         1,
+		"POST /nodes ":                    1,
+		"POST /cypher MATCH (n) RETURN n": 4,
+		"DELETE /nodes/1 ":                4,
+	}, calls)
+	mu.Unlock()
+
+	// A 503 turns the request away before it runs.
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	_, err = client.ExecuteCypher(ctx, "CREATE (n)", nil)
+	require.Error(t, err)
+	mu.Lock()
+	assert.Equal(t, 5, calls["POST /cypher CREATE (n)"])
+	mu.Unlock()
+
+	// So does a refused connection.
+	server.Close()
+	attempts := 0
+	cfg := fastRetry()
+	cfg.OnRetry = func(RetryAttempt) { attempts++ }
+	_, err = NewClient(Config{BaseURL: server.URL, Retry: cfg}).ExecuteCypher(ctx, "CREATE (n)", nil)
+	require.Error(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryLeavesTransactionConflictsToExecuteWrite(t *testing.T) {
+	executes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/transaction/execute" {
+			executes++
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"code":"DEADLOCK_DETECTED"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL}).WithRetry(fastRetry())
+
+	_, err := (&Transaction{client: client.Client, id: "tx"}).ExecuteCypher(context.Background(), "CREATE (n)", nil)
+	assert.True(t, IsTransientConflict(err))
+	assert.Equal(t, 1, executes)
+}
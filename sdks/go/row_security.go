@@ -0,0 +1,203 @@
+package nexus
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hivellm/nexus-go/internal/cypherlex"
+)
+
+// RowFilter is a row-level security predicate that QueryBuilder adds
+// for every node a MATCH pattern binds, so a query built for one tenant
+// or owner cannot read another's rows even if a condition is forgotten.
+type RowFilter struct {
+	// Labels limits the filter to node patterns carrying one of these
+	// labels; empty applies it to every node pattern.
+	Labels []string
+	// Condition returns the predicate for the node bound to variable.
+	Condition func(variable string) string
+	// Params are added to the query's parameters.
+	Params map[string]interface{}
+
+	// property and param are set by PropertyFilter, whose filters MERGE
+	// patterns carry as properties.
+	property, param string
+}
+
+// PropertyFilter is a RowFilter requiring property to equal value, e.g.
+// PropertyFilter("tenant_id", tenant) adds `n.tenant_id = $rls_tenant_id`.
+// The value travels as a parameter.
+func PropertyFilter(property string, value interface{}, labels ...string) RowFilter {
+	param := "rls_" + strings.Map(func(r rune) rune {
+		if cypherlex.IsIdentPart(byte(r)) && r < 0x80 {
+			return r
+		}
+		return '_'
+	}, property)
+	return RowFilter{
+		Labels: labels,
+		Condition: func(v string) string {
+			return v + "." + cypherKey(property) + " = $" + param
+		},
+		Params:   map[string]interface{}{param: value},
+		property: property,
+		param:    param,
+	}
+}
+
+// WithRowFilters installs row-level security filters. At Build time
+// every node pattern in a MATCH or OPTIONAL MATCH clause that a filter
+// applies to is constrained by it: the predicate goes in the WHERE of
+// that clause, so OPTIONAL MATCH keeps its semantics, and anonymous
+// nodes such as (:Order) are given a variable to filter on.
+//
+// A MERGE cannot take a WHERE, so PropertyFilter filters go into the
+// property map of each labelled node it applies to instead:
+// MERGE (u:User {email: $p1}) becomes
+// MERGE (u:User {email: $p1, tenant_id: $rls_tenant_id}), which only
+// matches the tenant's node and creates it with the tenant set. Custom
+// Condition filters cannot be expressed there and do not constrain
+// MERGE. CREATE patterns are not filtered.
+func (qb *QueryBuilder) WithRowFilters(filters ...RowFilter) *QueryBuilder {
+	qb.rowFilters = append(qb.rowFilters, filters...)
+	for _, f := range filters {
+		qb.WithParams(f.Params)
+	}
+	return qb
+}
+
+// applies reports whether f constrains a node with labels.
+func (f RowFilter) applies(labels []string) bool {
+	if len(f.Labels) == 0 {
+		return true
+	}
+	for _, want := range f.Labels {
+		for _, l := range labels {
+			if l == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// patternNode is a node of a pattern: its byte offsets and what it
+// declares.
+type patternNode struct {
+	at       int // just inside the '('
+	close    int // at the ')'
+	mapClose int // at the '}' of its property map, or -1
+	mapEmpty bool
+	variable string
+	labels   []string
+}
+
+// patternNodes lists the node patterns of pattern.
+func patternNodes(pattern string) []patternNode {
+	var nodes []patternNode
+	toks, _ := cypherlex.Tokenize(pattern)
+	for i, t := range toks {
+		// A '(' after a word is a function call such as shortestPath(.
+		if !t.Punct("(") || i > 0 && toks[i-1].Kind == cypherlex.Word {
+			continue
+		}
+		n := patternNode{at: t.End, close: len(pattern), mapClose: -1}
+		j := i + 1
+		for ; j < len(toks) && toks[j].Depth > t.Depth; j++ {
+			inner := toks[j]
+			if inner.Depth != t.Depth+1 {
+				continue
+			}
+			if j == i+1 && (inner.Kind == cypherlex.Word || inner.Kind == cypherlex.Quoted) {
+				n.variable = inner.Text
+			}
+			if (inner.Punct(":") || inner.Punct("|") || inner.Punct("&")) && j+1 < len(toks) &&
+				(toks[j+1].Kind == cypherlex.Word || toks[j+1].Kind == cypherlex.Quoted) {
+				n.labels = append(n.labels, toks[j+1].Name())
+			}
+			if inner.Punct("}") {
+				n.mapClose = inner.Start
+				n.mapEmpty = toks[j-1].Punct("{")
+			}
+		}
+		if j < len(toks) {
+			n.close = toks[j].Start
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// applied returns the filters that constrain n.
+func (n patternNode) applied(filters []RowFilter) []RowFilter {
+	var out []RowFilter
+	for _, f := range filters {
+		if f.applies(n.labels) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// filterPattern binds a variable to every filtered anonymous node in a
+// MATCH pattern and returns the rewritten pattern with the predicates
+// the filters require. next numbers generated variables across clauses.
+func filterPattern(pattern string, filters []RowFilter, next *int) (string, []string) {
+	if len(filters) == 0 {
+		return pattern, nil
+	}
+	nodes := patternNodes(pattern)
+	var preds []string
+	for k := len(nodes) - 1; k >= 0; k-- {
+		n := nodes[k]
+		applied := n.applied(filters)
+		if len(applied) == 0 {
+			continue
+		}
+		if n.variable == "" {
+			n.variable = fmt.Sprintf("rls_n%d", *next)
+			*next++
+			pattern = pattern[:n.at] + n.variable + pattern[n.at:]
+		}
+		conds := make([]string, len(applied))
+		for i, f := range applied {
+			conds[i] = f.Condition(n.variable)
+		}
+		preds = append(conds, preds...)
+	}
+	return pattern, preds
+}
+
+// filterMerge adds the properties of the PropertyFilter filters to
+// every labelled node of a MERGE pattern, so the MERGE only matches,
+// and only creates, nodes the filters allow. Nodes without labels are
+// left alone: in a MERGE they refer to nodes bound, and filtered, by an
+// earlier MATCH.
+func filterMerge(pattern string, filters []RowFilter) string {
+	if len(filters) == 0 {
+		return pattern
+	}
+	nodes := patternNodes(pattern)
+	for k := len(nodes) - 1; k >= 0; k-- {
+		n := nodes[k]
+		if len(n.labels) == 0 {
+			continue
+		}
+		var entries []string
+		for _, f := range n.applied(filters) {
+			if f.property != "" {
+				entries = append(entries, cypherKey(f.property)+": $"+f.param)
+			}
+		}
+		switch {
+		case len(entries) == 0:
+		case n.mapClose < 0:
+			pattern = pattern[:n.close] + " {" + strings.Join(entries, ", ") + "}" + pattern[n.close:]
+		case n.mapEmpty:
+			pattern = pattern[:n.mapClose] + strings.Join(entries, ", ") + pattern[n.mapClose:]
+		default:
+			pattern = pattern[:n.mapClose] + ", " + strings.Join(entries, ", ") + pattern[n.mapClose:]
+		}
+	}
+	return pattern
+}
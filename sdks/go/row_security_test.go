@@ -0,0 +1,80 @@
+package nexus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowFilters(t *testing.T) {
+	tenant := PropertyFilter("tenant_id", "acme")
+	owner := RowFilter{
+		Labels:    []string{"Order"},
+		Condition: func(v string) string { return v + ".owner = $user" },
+		Params:    map[string]interface{}{"user": "u1"},
+	}
+
+	qb := NewQueryBuilder().
+		WithRowFilters(tenant, owner).
+		Match("(c:Customer)-[:PLACED]->(:Order)").
+		OptionalMatch("(c)-[:LIVES_IN]->(a:Address)").
+		Where("c.vip = true").Or("c.spend > 1000").
+		Return("c", "a")
+	query, params := qb.Build()
+	assert.Equal(t,
+		"MATCH (c:Customer)-[:PLACED]->(rls_n0:Order) "+
+			"WHERE c.tenant_id = $rls_tenant_id AND rls_n0.tenant_id = $rls_tenant_id AND rls_n0.owner = $user "+
+			"OPTIONAL MATCH (c)-[:LIVES_IN]->(a:Address) "+
+			"WHERE (c.vip = true OR c.spend > 1000) AND c.tenant_id = $rls_tenant_id AND a.tenant_id = $rls_tenant_id "+
+			"RETURN c, a",
+		query)
+	assert.Equal(t, map[string]interface{}{"rls_tenant_id": "acme", "user": "u1"}, params)
+
+	// Label-scoped filters leave other patterns alone; CREATE is untouched.
+	qb = NewQueryBuilder().WithRowFilters(owner).
+		Match("p = shortestPath((a:Person)-[*]-(b:Person))").
+		Create("(o:Order)").
+		Return("p")
+	query, _ = qb.Build()
+	assert.Equal(t, "MATCH p = shortestPath((a:Person)-[*]-(b:Person)) CREATE (o:Order) RETURN p", query)
+
+	qb = NewQueryBuilder().WithRowFilters(PropertyFilter("org-id", 7, "Doc")).Match("(d:Doc)").Return("d")
+	query, _ = qb.Build()
+	assert.Equal(t, "MATCH (d:Doc) WHERE d.`org-id` = $rls_org_id RETURN d", query)
+}
+
+func TestRowFiltersOnMerge(t *testing.T) {
+	tenant := PropertyFilter("tenant_id", "acme")
+	owner := RowFilter{
+		Labels:    []string{"User"},
+		Condition: func(v string) string { return v + ".owner = $user" },
+	}
+
+	user := NewNodePattern("u").WithLabel("User").WithProperty("email", "a@example.com")
+	qb := NewQueryBuilder().WithRowFilters(tenant, owner).
+		MergeNode(user, map[string]interface{}{"name": "Ann"}).
+		Return("u")
+	query, params := qb.Build()
+	assert.Equal(t,
+		"MERGE (u:User {email: $p1, tenant_id: $rls_tenant_id}) "+
+			"ON CREATE SET u.name = $p2 ON MATCH SET u.name = $p2 RETURN u",
+		query)
+	assert.Equal(t, "acme", params["rls_tenant_id"])
+
+	// Labelled nodes get a map, an empty map is filled, and bound
+	// variables are left to the MATCH that filtered them.
+	qb = NewQueryBuilder().WithRowFilters(tenant).
+		Match("(a:Account)").
+		Merge("(a)-[:OWNS]->(:Wallet)-[:IN]->(c:Currency {})").
+		Return("a")
+	query, _ = qb.Build()
+	assert.Equal(t,
+		"MATCH (a:Account) WHERE a.tenant_id = $rls_tenant_id "+
+			"MERGE (a)-[:OWNS]->(:Wallet {tenant_id: $rls_tenant_id})-[:IN]->(c:Currency {tenant_id: $rls_tenant_id}) "+
+			"RETURN a",
+		query)
+
+	// Condition filters cannot constrain a MERGE.
+	query, _ = NewQueryBuilder().WithRowFilters(owner).Merge("(u:User {email: $e})").Build()
+	assert.Equal(t, "MERGE (u:User {email: $e})", query)
+}
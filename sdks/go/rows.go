@@ -0,0 +1,106 @@
+package nexus
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RowMapOptions controls RowsAsMapWith.
+type RowMapOptions struct {
+	// SuffixDuplicates keeps every column when names repeat: the second
+	// "name" becomes "name_2", the third "name_3". Without it the last
+	// column wins, as in RowsAsMap.
+	SuffixDuplicates bool
+	// FillMissing sets columns absent from a short row to MissingValue
+	// instead of leaving the key out.
+	FillMissing  bool
+	MissingValue interface{}
+	// FlattenEntities replaces node and relationship cells with one key
+	// per property ("n.name", "n.age") plus "n._id", "r._type" for
+	// relationships and "n._labels" when the server sends labels. Null
+	// cells stay as a single nil key.
+	FlattenEntities bool
+}
+
+// RowsAsMapWith converts the rows to maps like RowsAsMap, with control
+// over duplicate column names, short rows and entity flattening.
+// Duplicate handling also covers flattened keys that clash with a
+// projected column of the same name.
+func (qr *QueryResult) RowsAsMapWith(opts RowMapOptions) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(qr.Rows))
+	for i, row := range qr.Rows {
+		rowMap := make(map[string]interface{}, len(qr.Columns))
+		put := func(key string, v interface{}) {
+			if opts.SuffixDuplicates {
+				key = uniqueKey(rowMap, key)
+			}
+			rowMap[key] = v
+		}
+		for j, col := range qr.Columns {
+			if j >= len(row) {
+				if opts.FillMissing {
+					put(col, opts.MissingValue)
+				}
+				continue
+			}
+			cell := row[j]
+			if m, ok := cell.(map[string]interface{}); ok && opts.FlattenEntities {
+				if fields, ok := flattenEntity(m); ok {
+					for _, k := range sortedKeys(fields) {
+						put(col+"."+k, fields[k])
+					}
+					continue
+				}
+			}
+			put(col, cell)
+		}
+		result[i] = rowMap
+	}
+	return result
+}
+
+func uniqueKey(m map[string]interface{}, key string) string {
+	if _, taken := m[key]; !taken {
+		return key
+	}
+	for n := 2; ; n++ {
+		candidate := key + "_" + strconv.Itoa(n)
+		if _, taken := m[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+// flattenEntity splits a node or relationship cell into its properties
+// and `_`-prefixed metadata. It accepts both the `{id, labels|type,
+// properties}` shape and the executor's `_nexus_id` shape, where
+// properties sit next to the metadata (and labels are not included).
+func flattenEntity(m map[string]interface{}) (map[string]interface{}, bool) {
+	isRel := isRelationshipValue(m)
+	if !isRel && !isNodeValue(m) {
+		return nil, false
+	}
+	fields := map[string]interface{}{}
+	if props, ok := m["properties"].(map[string]interface{}); ok {
+		for k, v := range props {
+			fields[k] = v
+		}
+		fields["_id"] = m["id"]
+		if isRel {
+			fields["_type"] = m["type"]
+		} else {
+			fields["_labels"] = m["labels"]
+		}
+		return fields, true
+	}
+	for k, v := range m {
+		if !strings.HasPrefix(k, "_") {
+			fields[k] = v
+		}
+	}
+	fields["_id"] = m["_nexus_id"]
+	if isRel {
+		fields["_type"] = m["_nexus_type"]
+	}
+	return fields, true
+}
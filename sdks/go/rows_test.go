@@ -0,0 +1,47 @@
+package nexus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsAsMapWithDuplicatesAndMissing(t *testing.T) {
+	qr := &QueryResult{
+		Columns: []string{"name", "name", "name_2", "age"},
+		Rows:    [][]interface{}{{"a", "b", "c", 1}, {"d", "e"}},
+	}
+	assert.Equal(t, map[string]interface{}{"name": "b", "name_2": "c", "age": 1}, qr.RowsAsMap()[0])
+
+	rows := qr.RowsAsMapWith(RowMapOptions{SuffixDuplicates: true, FillMissing: true, MissingValue: "?"})
+	assert.Equal(t, map[string]interface{}{"name": "a", "name_2": "b", "name_2_2": "c", "age": 1}, rows[0])
+	assert.Equal(t, map[string]interface{}{"name": "d", "name_2": "e", "name_2_2": "?", "age": "?"}, rows[1])
+
+	rows = qr.RowsAsMapWith(RowMapOptions{})
+	assert.Equal(t, map[string]interface{}{"name": "e"}, rows[1])
+}
+
+func TestRowsAsMapWithFlattenEntities(t *testing.T) {
+	qr := &QueryResult{
+		Columns: []string{"n", "r", "m", "n.name"},
+		Rows: [][]interface{}{
+			{
+				map[string]interface{}{"_nexus_id": float64(1), "name": "Alice", "age": float64(30)},
+				map[string]interface{}{"_nexus_id": float64(7), "_nexus_type": "KNOWS", "_source": float64(1), "_target": float64(2), "since": float64(2020)},
+				map[string]interface{}{"id": "2", "labels": []interface{}{"Person"}, "properties": map[string]interface{}{"name": "Bob"}},
+				"projected",
+			},
+			{nil, nil, map[string]interface{}{"plain": true}, nil},
+		},
+	}
+	rows := qr.RowsAsMapWith(RowMapOptions{FlattenEntities: true, SuffixDuplicates: true})
+	assert.Equal(t, map[string]interface{}{
+		"n._id": float64(1), "n.name": "Alice", "n.age": float64(30),
+		"r._id": float64(7), "r._type": "KNOWS", "r.since": float64(2020),
+		"m._id": "2", "m._labels": []interface{}{"Person"}, "m.name": "Bob",
+		"n.name_2": "projected",
+	}, rows[0])
+	assert.Equal(t, map[string]interface{}{
+		"n": nil, "r": nil, "m": map[string]interface{}{"plain": true}, "n.name": nil,
+	}, rows[1])
+}
@@ -0,0 +1,157 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Statement is one Cypher statement of a RunTransaction batch.
+type Statement struct {
+	Query      string                 `json:"query"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// RunTransaction executes statements atomically and returns one result
+// per statement.
+//
+// Servers exposing POST /transaction/run open, execute and commit the
+// batch in a single round trip. Older servers (404/405/501 on that
+// route) get the begin/execute/commit sequence instead; the client
+// remembers the outcome and skips the probe afterwards.
+//
+// Every statement goes through the same checks as ExecuteCypher
+// (QueryPolicy, QueryList, Authorizer and Quota) before any is sent,
+// and is reported to Config.Metrics and bounded by its adaptive timeout
+// like any other statement. In dry-run mode (see WithDryRun) the batch
+// runs in a transaction that is rolled back.
+func (c *Client) RunTransaction(ctx context.Context, statements []Statement) ([]*QueryResult, error) {
+	if len(statements) == 0 {
+		return nil, errors.New("nexus: RunTransaction requires at least one statement")
+	}
+	checked := make([]Statement, len(statements))
+	for i, stmt := range statements {
+		query, err := c.checkQuery(ctx, stmt.Query, stmt.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: statement %d: %w", i, err)
+		}
+		checked[i] = Statement{Query: query, Parameters: stmt.Parameters}
+	}
+	if c.isDryRun(ctx) {
+		return c.runTransactionSequential(ctx, checked, true)
+	}
+	if !c.noTxRun.Load() {
+		results, err := c.runTransactionSingle(ctx, checked)
+		if !isRouteUnsupported(err) {
+			return results, err
+		}
+		c.noTxRun.Store(true)
+	}
+	return c.runTransactionSequential(ctx, checked, false)
+}
+
+// runTransactionSingle sends the batch as one request. That request
+// runs under every statement's adaptive timeout, and each statement is
+// reported to the metrics hooks with the batch's outcome.
+func (c *Client) runTransactionSingle(ctx context.Context, statements []Statement) ([]*QueryResult, error) {
+	finishes := make([]func(*QueryResult, error) error, len(statements))
+	for i, stmt := range statements {
+		ctx, finishes[i] = c.startQuery(ctx, stmt.Query, stmt.Parameters, true)
+	}
+	results, batchErr := c.postTransactionRun(ctx, statements)
+	err := batchErr
+	for i, finish := range finishes {
+		var result *QueryResult
+		if results != nil {
+			result = results[i]
+		}
+		// Each context derives from the previous one, so the first
+		// statement to wrap the error is the one whose timeout fired.
+		if stmtErr := finish(result, batchErr); err == batchErr {
+			err = stmtErr
+		}
+	}
+	return results, err
+}
+
+func (c *Client) postTransactionRun(ctx context.Context, statements []Statement) ([]*QueryResult, error) {
+	reqBody := map[string]interface{}{"statements": statements}
+	resp, err := c.doRequest(ctx, http.MethodPost, "/transaction/run", reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Results []*QueryResult `json:"results"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Results) != len(statements) {
+		return nil, fmt.Errorf("nexus: transaction/run returned %d results for %d statements", len(result.Results), len(statements))
+	}
+	for _, r := range result.Results {
+		r.fillColumnTypes()
+		if err := c.checkNotifications(r); err != nil {
+			return result.Results, err
+		}
+	}
+	return result.Results, nil
+}
+
+// runTransactionSequential runs checked statements one by one in a
+// transaction, committing it or, to rehearse them, rolling it back.
+func (c *Client) runTransactionSequential(ctx context.Context, statements []Statement, rehearse bool) ([]*QueryResult, error) {
+	if rehearse {
+		for _, stmt := range statements {
+			if _, err := rehearsable(stmt.Query); err != nil {
+				return nil, err
+			}
+		}
+	}
+	tx, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*QueryResult, 0, len(statements))
+	for i, stmt := range statements {
+		stmtCtx, finish := c.startQuery(ctx, stmt.Query, stmt.Parameters, true)
+		result, err := tx.execute(stmtCtx, stmt.Query, stmt.Parameters)
+		if err = finish(result, err); err != nil {
+			err = fmt.Errorf("nexus: statement %d: %w", i, err)
+			// The statement may have failed because ctx ended; the
+			// transaction must still be closed.
+			if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil {
+				return nil, fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if rehearse {
+		if err := tx.Rollback(ctx); err != nil {
+			return nil, fmt.Errorf("nexus: dry run: rollback failed, the writes may have been applied: %w", err)
+		}
+		return results, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// isRouteUnsupported reports whether err means the server does not
+// know the requested route.
+func isRouteUnsupported(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
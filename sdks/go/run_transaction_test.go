@@ -0,0 +1,195 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTransactionSingleRoundTrip(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/transaction/run", r.URL.Path)
+		var req struct {
+			Statements []Statement `json:"statements"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Statements, 2)
+		assert.Equal(t, "Alice", req.Statements[0].Parameters["name"])
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{"columns":[],"rows":[]},{"columns":["c"],"rows":[[1]]}]}`))
+	}))
+	defer server.Close()
+
+	var events []QueryEvent
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		Metrics: MetricsHookFunc(func(ctx context.Context, e QueryEvent) { events = append(events, e) }),
+	})
+	results, err := client.RunTransaction(context.Background(), []Statement{
+		{Query: "CREATE (:Person {name: $name})", Parameters: map[string]interface{}{"name": "Alice"}},
+		{Query: "MATCH (p:Person) RETURN count(p) AS c"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	require.Len(t, results, 2)
+	assert.Equal(t, []string{"c"}, results[1].Columns)
+	require.Len(t, events, 2)
+	assert.Equal(t, "MATCH (p:Person) RETURN count(p) AS c", events[1].Query)
+	assert.Equal(t, 1, events[1].Rows)
+	assert.True(t, events[1].InTransaction)
+}
+
+func TestRunTransactionFallsBack(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/transaction/run":
+			w.WriteHeader(http.StatusNotFound)
+		case "/transaction/begin":
+			w.Write([]byte(`{"transaction_id":"t1"}`))
+		default:
+			w.Write([]byte(`{"columns":[],"rows":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	stmts := []Statement{{Query: "CREATE (:A)"}}
+	_, err := client.RunTransaction(context.Background(), stmts)
+	require.NoError(t, err)
+	_, err = client.RunTransaction(context.Background(), stmts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/transaction/run",
+		"/transaction/begin", "/transaction/execute", "/transaction/commit",
+		"/transaction/begin", "/transaction/execute", "/transaction/commit",
+	}, paths)
+}
+
+func TestRunTransactionChecksStatements(t *testing.T) {
+	var paths []string
+	var sent []Statement
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/transaction/run":
+			var req struct {
+				Statements []Statement `json:"statements"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			sent = req.Statements
+			w.Write([]byte(`{"results":[{"columns":[],"rows":[]},{"columns":[],"rows":[]}]}`))
+		case "/transaction/begin":
+			w.Write([]byte(`{"transaction_id":"t1"}`))
+		default:
+			w.Write([]byte(`{"columns":[],"rows":[]}`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	stmts := []Statement{{Query: "CREATE (:Audit)"}, {Query: "MATCH (n:Secret) DETACH DELETE n"}}
+
+	denyAll := NewClient(Config{BaseURL: server.URL, Authorizer: AuthorizerFunc(func(context.Context, Access) error {
+		return errors.New("denied")
+	})})
+	_, err := denyAll.RunTransaction(ctx, stmts)
+	assert.ErrorContains(t, err, "denied")
+
+	guarded := NewClient(Config{BaseURL: server.URL, QueryPolicy: Guardrails{ForbidDetachDelete: true}})
+	_, err = guarded.RunTransaction(ctx, stmts)
+	var policyErr *QueryPolicyError
+	assert.True(t, errors.As(err, &policyErr), "got %v", err)
+
+	limited := NewClient(Config{BaseURL: server.URL, Quota: &QuotaConfig{MaxQueries: 1}})
+	_, err = limited.RunTransaction(ctx, stmts)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Empty(t, paths, "nothing is sent when a statement is refused")
+
+	// Rewritten statements are the ones sent.
+	tagged := NewClient(Config{BaseURL: server.URL, QueryPolicy: QueryPolicyFunc(
+		func(_ context.Context, query string, _ map[string]interface{}) (string, error) {
+			return "/* app */ " + query, nil
+		})})
+	_, err = tagged.RunTransaction(ctx, stmts)
+	require.NoError(t, err)
+	require.Len(t, sent, 2)
+	assert.Equal(t, "/* app */ CREATE (:Audit)", sent[0].Query)
+
+	// Dry runs roll the batch back.
+	paths = nil
+	results, err := NewClient(Config{BaseURL: server.URL}).RunTransaction(WithDryRun(ctx, true), stmts)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, []string{
+		"/transaction/begin", "/transaction/execute", "/transaction/execute", "/transaction/rollback",
+	}, paths)
+}
+
+func TestRunTransactionRollsBackAfterAdaptiveTimeout(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/transaction/run":
+			w.WriteHeader(http.StatusNotFound)
+		case "/transaction/begin":
+			w.Write([]byte(`{"transaction_id":"t1"}`))
+		case "/transaction/execute":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad statement"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	// A learned timeout gives each statement its own context, which
+	// ends with the statement.
+	a := NewAdaptiveTimeout(AdaptiveTimeoutOptions{MinSamples: 1, Min: time.Second})
+	a.ObserveQuery(context.Background(), QueryEvent{Fingerprint: QueryFingerprint("CREATE (:A)"), Duration: time.Millisecond})
+	client := NewClient(Config{BaseURL: server.URL, AdaptiveTimeout: a})
+	_, err := client.RunTransaction(context.Background(), []Statement{{Query: "CREATE (:A)"}})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "rollback failed")
+	assert.Equal(t, []string{"/transaction/run", "/transaction/begin", "/transaction/execute", "/transaction/rollback"}, paths)
+}
+
+func TestRunTransactionSingleAppliesAdaptiveTimeout(t *testing.T) {
+	stall := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stall
+	}))
+	defer server.Close()
+	defer close(stall)
+
+	a := NewAdaptiveTimeout(AdaptiveTimeoutOptions{MinSamples: 1, Min: 50 * time.Millisecond})
+	fp := QueryFingerprint("MATCH (n) RETURN count(n) AS c")
+	a.ObserveQuery(context.Background(), QueryEvent{Fingerprint: fp, Duration: time.Millisecond})
+	client := NewClient(Config{BaseURL: server.URL, AdaptiveTimeout: a})
+
+	start := time.Now()
+	_, err := client.RunTransaction(context.Background(), []Statement{
+		{Query: "CREATE (:A)"},
+		{Query: "MATCH (n) RETURN count(n) AS c"},
+	})
+	var timeoutErr *AdaptiveTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, fp, timeoutErr.Fingerprint)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,282 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SavedQuery is a named, parameterised Cypher statement stored on the
+// server.
+type SavedQuery struct {
+	Name        string                 `json:"name"`
+	Query       string                 `json:"query"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// SaveQuery stores (or replaces) a saved query. params are the default
+// parameters; RunSavedQuery may override them.
+func (c *Client) SaveQuery(ctx context.Context, name, cypher string, params map[string]interface{}) (*SavedQuery, error) {
+	if name == "" {
+		return nil, errors.New("nexus: saved query name must not be empty")
+	}
+	if strings.TrimSpace(cypher) == "" {
+		return nil, errors.New("nexus: saved query must not be empty")
+	}
+	reqBody := map[string]interface{}{
+		"query": cypher,
+	}
+	if params != nil {
+		reqBody["parameters"] = params
+	}
+
+	path := fmt.Sprintf("/queries/%s", url.PathEscape(name))
+	resp, err := c.doRequest(ctx, http.MethodPut, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var saved SavedQuery
+	if err := decodeResponse(resp, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// ListSavedQueries retrieves all saved queries.
+func (c *Client) ListSavedQueries(ctx context.Context) ([]SavedQuery, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/queries", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Queries []SavedQuery `json:"queries"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	return result.Queries, nil
+}
+
+// DeleteSavedQuery removes a saved query and its schedules.
+func (c *Client) DeleteSavedQuery(ctx context.Context, name string) error {
+	path := fmt.Sprintf("/queries/%s", url.PathEscape(name))
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// RunSavedQuery executes a saved query. params are merged over the
+// saved defaults server-side.
+//
+// With Config.StaleReads set, a run the server cannot answer returns
+// the last result of the same name and params, marked with Staleness.
+func (c *Client) RunSavedQuery(ctx context.Context, name string, params map[string]interface{}) (*QueryResult, error) {
+	result, err := c.runSavedQuery(ctx, name, params)
+	return c.staleResult(staleSavedQueryKey(ctx, name, params), result, err)
+}
+
+func (c *Client) runSavedQuery(ctx context.Context, name string, params map[string]interface{}) (*QueryResult, error) {
+	reqBody := map[string]interface{}{}
+	if params != nil {
+		reqBody["parameters"] = params
+	}
+
+	path := fmt.Sprintf("/queries/%s/run", url.PathEscape(name))
+	resp, err := c.doRequest(ctx, http.MethodPost, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result QueryResult
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ScheduleDestination is where a scheduled run delivers its result.
+type ScheduleDestination struct {
+	// Type is "webhook" (POST the result to Target), "snapshot" (store
+	// the result under the Target name) or "none" (run for side effects).
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+}
+
+// ScheduleOptions creates a schedule for a saved query.
+type ScheduleOptions struct {
+	// Cron is a standard 5-field expression ("0 6 * * 1-5") or one of
+	// @hourly, @daily, @weekly, @monthly.
+	Cron string `json:"cron"`
+	// Timezone is an IANA name; empty means UTC.
+	Timezone    string                 `json:"timezone,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Destination ScheduleDestination    `json:"destination"`
+}
+
+// QuerySchedule is a recurring run of a saved query.
+type QuerySchedule struct {
+	ID          string                 `json:"id"`
+	QueryName   string                 `json:"query_name"`
+	Cron        string                 `json:"cron"`
+	Timezone    string                 `json:"timezone,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Destination ScheduleDestination    `json:"destination"`
+	Enabled     bool                   `json:"enabled"`
+	NextRunAt   *time.Time             `json:"next_run_at,omitempty"`
+	LastRunAt   *time.Time             `json:"last_run_at,omitempty"`
+	LastError   string                 `json:"last_error,omitempty"`
+}
+
+// CreateSchedule schedules a saved query. The cron expression is
+// checked client-side so typos fail before the round trip.
+func (c *Client) CreateSchedule(ctx context.Context, queryName string, opts ScheduleOptions) (*QuerySchedule, error) {
+	if err := validateCron(opts.Cron); err != nil {
+		return nil, err
+	}
+	if opts.Timezone != "" {
+		if _, err := time.LoadLocation(opts.Timezone); err != nil {
+			return nil, fmt.Errorf("nexus: schedule timezone: %w", err)
+		}
+	}
+	if opts.Destination.Type == "" {
+		opts.Destination.Type = "none"
+	}
+
+	path := fmt.Sprintf("/queries/%s/schedules", url.PathEscape(queryName))
+	resp, err := c.doRequest(ctx, http.MethodPost, path, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var schedule QuerySchedule
+	if err := decodeResponse(resp, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ListSchedules retrieves the schedules of queryName, or of every saved
+// query when queryName is empty.
+func (c *Client) ListSchedules(ctx context.Context, queryName string) ([]QuerySchedule, error) {
+	path := "/schedules"
+	if queryName != "" {
+		path += "?query=" + url.QueryEscape(queryName)
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Schedules []QuerySchedule `json:"schedules"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	return result.Schedules, nil
+}
+
+// SetScheduleEnabled pauses or resumes a schedule.
+func (c *Client) SetScheduleEnabled(ctx context.Context, id string, enabled bool) error {
+	path := fmt.Sprintf("/schedules/%s", url.PathEscape(id))
+	resp, err := c.doRequest(ctx, http.MethodPatch, path, map[string]interface{}{"enabled": enabled})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// DeleteSchedule removes a schedule by ID.
+func (c *Client) DeleteSchedule(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/schedules/%s", url.PathEscape(id))
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// cronFields bounds each field of a 5-field cron expression.
+var cronFields = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// validateCron checks the syntax and ranges of a cron expression:
+// numbers, `*`, ranges `a-b`, lists `a,b` and steps `/n`.
+func validateCron(expr string) error {
+	switch expr {
+	case "@hourly", "@daily", "@weekly", "@monthly", "@yearly":
+		return nil
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("nexus: cron expression %q must have %d fields, got %d", expr, len(cronFields), len(fields))
+	}
+	for i, field := range fields {
+		spec := cronFields[i]
+		for _, part := range strings.Split(field, ",") {
+			rng, step, hasStep := strings.Cut(part, "/")
+			if hasStep {
+				if n, err := parseCronNumber(step); err != nil || n == 0 {
+					return fmt.Errorf("nexus: cron %s: invalid step %q", spec.name, step)
+				}
+			}
+			if rng == "*" {
+				continue
+			}
+			lo, hi, isRange := strings.Cut(rng, "-")
+			if !isRange {
+				hi = lo
+			}
+			a, errA := parseCronNumber(lo)
+			b, errB := parseCronNumber(hi)
+			if errA != nil || errB != nil || a < spec.min || b > spec.max || a > b {
+				return fmt.Errorf("nexus: cron %s: invalid value %q (allowed %d-%d)", spec.name, part, spec.min, spec.max)
+			}
+		}
+	}
+	return nil
+}
+
+func parseCronNumber(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("empty")
+	}
+	n := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("not a number: %q", s)
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n, nil
+}
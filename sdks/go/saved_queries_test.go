@@ -0,0 +1,51 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCron(t *testing.T) {
+	for _, expr := range []string{"0 6 * * 1-5", "*/15 * * * *", "0 0 1,15 * *", "@daily"} {
+		assert.NoError(t, validateCron(expr), expr)
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "0 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		assert.Error(t, validateCron(expr), expr)
+	}
+}
+
+func TestCreateSchedule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/queries/daily%20report/schedules", r.URL.EscapedPath())
+
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "0 6 * * *", req["cron"])
+		assert.Equal(t, map[string]interface{}{"type": "webhook", "target": "https://hooks.example/r"}, req["destination"])
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"s1","query_name":"daily report","cron":"0 6 * * *","enabled":true,"next_run_at":"2026-10-17T06:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	schedule, err := client.CreateSchedule(context.Background(), "daily report", ScheduleOptions{
+		Cron:        "0 6 * * *",
+		Destination: ScheduleDestination{Type: "webhook", Target: "https://hooks.example/r"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "s1", schedule.ID)
+	assert.True(t, schedule.Enabled)
+	require.NotNil(t, schedule.NextRunAt)
+
+	_, err = client.CreateSchedule(context.Background(), "x", ScheduleOptions{Cron: "bad"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTransactionDone is returned when a nested transaction is used
+// after its Commit or Rollback.
+var ErrTransactionDone = errors.New("nexus: nested transaction already committed or rolled back")
+
+// TransactionStarter opens a transaction. *Client opens a top-level
+// one and *Transaction a nested one, so library code can take a
+// TransactionStarter and run its unit of work atomically whether or
+// not the caller already has a transaction open:
+//
+//	func transfer(ctx context.Context, ts nexus.TransactionStarter) error {
+//	    tx, err := ts.Begin(ctx)
+//	    if err != nil { return err }
+//	    if _, err := tx.ExecuteCypher(ctx, debit, params); err != nil {
+//	        tx.Rollback(ctx)
+//	        return err
+//	    }
+//	    return tx.Commit(ctx)
+//	}
+type TransactionStarter interface {
+	Begin(ctx context.Context) (*Transaction, error)
+}
+
+// Begin starts a top-level transaction, like BeginTransaction.
+func (c *Client) Begin(ctx context.Context) (*Transaction, error) {
+	return c.BeginTransaction(ctx)
+}
+
+// Begin starts a transaction nested in tx, backed by a server-side
+// SAVEPOINT. Its Rollback undoes only the work done since Begin and
+// leaves tx usable. Its Commit releases the savepoint, so the work
+// stays pending until tx itself commits. Nested transactions may nest
+// further; rolling back an outer one discards the inner ones.
+func (tx *Transaction) Begin(ctx context.Context) (*Transaction, error) {
+	if tx.done {
+		return nil, ErrTransactionDone
+	}
+	root := tx
+	for root.parent != nil {
+		root = root.parent
+	}
+	name := fmt.Sprintf("nexus_sp_%d", root.seq.Add(1))
+	if _, err := tx.execute(ctx, "SAVEPOINT "+name, nil); err != nil {
+		return nil, fmt.Errorf("nexus: begin nested transaction: %w", err)
+	}
+	return &Transaction{client: tx.client, id: tx.id, parent: tx, savepoint: name}, nil
+}
+
+// Savepoint returns the name of the savepoint backing a nested
+// transaction, or "" for a top-level one.
+func (tx *Transaction) Savepoint() string { return tx.savepoint }
+
+func (tx *Transaction) releaseSavepoint(ctx context.Context) error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+	tx.done = true
+	if _, err := tx.execute(ctx, "RELEASE SAVEPOINT "+tx.savepoint, nil); err != nil {
+		return fmt.Errorf("nexus: commit nested transaction: %w", err)
+	}
+	return nil
+}
+
+func (tx *Transaction) rollbackToSavepoint(ctx context.Context) error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+	tx.done = true
+	if _, err := tx.execute(ctx, "ROLLBACK TO SAVEPOINT "+tx.savepoint, nil); err != nil {
+		return fmt.Errorf("nexus: roll back nested transaction: %w", err)
+	}
+	// ROLLBACK TO keeps the savepoint; drop it so the stack matches
+	// the nesting.
+	if _, err := tx.execute(ctx, "RELEASE SAVEPOINT "+tx.savepoint, nil); err != nil {
+		return fmt.Errorf("nexus: roll back nested transaction: %w", err)
+	}
+	return nil
+}
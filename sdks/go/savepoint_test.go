@@ -0,0 +1,73 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNestedTransactions(t *testing.T) {
+	var log []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query         string `json:"query"`
+			TransactionID string `json:"transaction_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/transaction/begin":
+			log = append(log, "BEGIN")
+			json.NewEncoder(w).Encode(map[string]interface{}{"transaction_id": "tx1"})
+			return
+		case "/transaction/commit":
+			log = append(log, "COMMIT")
+		default:
+			assert.Equal(t, "tx1", req.TransactionID)
+			log = append(log, req.Query)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{}, "rows": [][]interface{}{}})
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	queries := NewQueryAllowList()
+	require.NoError(t, queries.Register("create", "CREATE (:A)"))
+	client := NewClient(Config{BaseURL: server.URL, QueryList: queries})
+
+	var ts TransactionStarter = client
+	tx, err := ts.Begin(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, tx.Savepoint())
+
+	inner, err := tx.Begin(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "nexus_sp_1", inner.Savepoint())
+	deeper, err := inner.Begin(ctx)
+	require.NoError(t, err)
+	_, err = deeper.ExecuteCypher(ctx, "CREATE (:A)", nil)
+	require.NoError(t, err)
+	require.NoError(t, deeper.Commit(ctx))
+	require.NoError(t, inner.Rollback(ctx))
+
+	assert.ErrorIs(t, inner.Commit(ctx), ErrTransactionDone)
+	_, err = inner.ExecuteCypher(ctx, "CREATE (:A)", nil)
+	assert.ErrorIs(t, err, ErrTransactionDone)
+	_, err = inner.Begin(ctx)
+	assert.ErrorIs(t, err, ErrTransactionDone)
+
+	require.NoError(t, tx.Commit(ctx))
+	assert.Equal(t, []string{
+		"BEGIN",
+		"SAVEPOINT nexus_sp_1",
+		"SAVEPOINT nexus_sp_2",
+		"CREATE (:A)",
+		"RELEASE SAVEPOINT nexus_sp_2",
+		"ROLLBACK TO SAVEPOINT nexus_sp_1",
+		"RELEASE SAVEPOINT nexus_sp_1",
+		"COMMIT",
+	}, log)
+}
@@ -0,0 +1,307 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hivellm/nexus-go/internal/ids"
+)
+
+// ErrNoRows is returned by QueryResult.Scan into a single value when the
+// result has no rows.
+var ErrNoRows = errors.New("nexus: no rows in result")
+
+// CypherExecutor runs Cypher; *Client and *Transaction implement it.
+type CypherExecutor interface {
+	ExecuteCypher(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error)
+}
+
+// Query runs query and scans every row into a T (see QueryResult.Scan):
+//
+//	type Person struct {
+//	    Name  string    `nexus:"name"`
+//	    Born  time.Time `nexus:"born"`
+//	    Email *string   `nexus:"email"` // nil when null
+//	}
+//	people, err := nexus.Query[Person](ctx, client,
+//	    "MATCH (p:Person) RETURN p.name AS name, p.born AS born, p.email AS email", nil)
+func Query[T any](ctx context.Context, e CypherExecutor, query string, params map[string]interface{}) ([]T, error) {
+	result, err := e.ExecuteCypher(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	var out []T
+	if err := result.Scan(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Scan copies the result into dest, which points to a slice (one
+// element per row) or to a single value (the first row; ErrNoRows when
+// there is none).
+//
+// When the element is a struct, each column goes to the field tagged
+// `nexus:"column"` (options after a comma are ignored), or else the
+// field whose name matches it ignoring case; `nexus:"-"` skips a field,
+// and columns without a field are ignored. Any other element type receives the first column, so a
+// []string can collect one column.
+//
+// Cells convert as follows: null leaves the zero value (a nil pointer);
+// numbers convert between numeric kinds when no precision is lost, and
+// to strings;
+// time.Time accepts RFC 3339 strings, dates ("2006-01-02") and epoch
+// milliseconds; a node or relationship fills Node, Relationship or a
+// struct whose fields name its properties, plus `_id`, `_labels` and
+// `_type` for the metadata, with the ID also filling the field tagged
+// `nexus:",id"` or else an untagged field named ID, as in ogm entities; maps fill structs and maps, lists fill
+// slices; ValueScanner implementations decode themselves.
+func (qr *QueryResult) Scan(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("nexus: Scan needs a non-nil pointer, got %T", dest)
+	}
+	v = v.Elem()
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		rows := reflect.MakeSlice(v.Type(), len(qr.Rows), len(qr.Rows))
+		for i := range qr.Rows {
+			if err := qr.scanRow(i, rows.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(rows)
+		return nil
+	}
+	if len(qr.Rows) == 0 {
+		return ErrNoRows
+	}
+	return qr.scanRow(0, v)
+}
+
+func (qr *QueryResult) scanRow(i int, dst reflect.Value) error {
+	row := qr.Rows[i]
+	target := dst.Type()
+	for target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	if target.Kind() != reflect.Struct || target == timeType || target == nodeType || target == relationshipType || scannerType(target) {
+		if len(row) == 0 {
+			return nil
+		}
+		if err := scanCell(row[0], dst); err != nil {
+			return fmt.Errorf("nexus: row %d: column %q: %w", i, qr.Columns[0], err)
+		}
+		return nil
+	}
+	cells := make(map[string]interface{}, len(qr.Columns))
+	for c, col := range qr.Columns {
+		if c < len(row) {
+			cells[col] = row[c]
+		}
+	}
+	if err := scanStruct(cells, dst); err != nil {
+		return fmt.Errorf("nexus: row %d: %w", i, err)
+	}
+	return nil
+}
+
+var (
+	timeType         = reflect.TypeOf(time.Time{})
+	nodeType         = reflect.TypeOf(Node{})
+	relationshipType = reflect.TypeOf(Relationship{})
+	valueScanner     = reflect.TypeOf((*ValueScanner)(nil)).Elem()
+)
+
+func scannerType(t reflect.Type) bool { return reflect.PointerTo(t).Implements(valueScanner) }
+
+// scanStruct fills the struct dst (or pointer to one) from named cells.
+func scanStruct(cells map[string]interface{}, dst reflect.Value) error {
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	t := dst.Type()
+	// Sorted, so a property named id wins over the node ID for a field
+	// both would fill.
+	for _, name := range sortedKeys(cells) {
+		cell := cells[name]
+		index := scanField(t, name)
+		if index == nil {
+			continue
+		}
+		if err := scanCell(cell, dst.FieldByIndex(index)); err != nil {
+			return fmt.Errorf("column %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// scanField finds the field for column name: by tag, else by
+// case-insensitive field name. The entity ID, _id, goes to the field
+// tagged ",id", else to an untagged field named ID.
+func scanField(t reflect.Type, name string) []int {
+	var fold, id []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		full := f.Tag.Get("nexus")
+		tag, opts, _ := strings.Cut(full, ",")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		if name == "_id" && opts == "id" || tag != "" && tag == name {
+			return f.Index
+		}
+		if full == "" && f.Name == "ID" {
+			id = f.Index
+		}
+		if tag == "" && opts != "id" && fold == nil && strings.EqualFold(f.Name, name) {
+			fold = f.Index
+		}
+	}
+	if fold == nil && name == "_id" {
+		return id
+	}
+	return fold
+}
+
+// scanCell stores one decoded cell in dst.
+func scanCell(cell interface{}, dst reflect.Value) error {
+	if cell == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if dst.CanAddr() {
+		if s, ok := dst.Addr().Interface().(ValueScanner); ok {
+			return s.ScanValue(cell)
+		}
+	}
+	if dst.Kind() == reflect.Ptr {
+		v := reflect.New(dst.Type().Elem())
+		if err := scanCell(cell, v.Elem()); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	}
+	if dst.Kind() == reflect.Interface {
+		return convertValue(cell, dst.Addr().Interface())
+	}
+
+	switch dst.Type() {
+	case timeType:
+		t, err := scanTime(cell)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	case nodeType, relationshipType:
+		m, ok := cell.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot decode %T into %s", cell, dst.Type())
+		}
+		fields, ok := flattenEntity(m)
+		if !ok {
+			return fmt.Errorf("value is not a node or relationship")
+		}
+		dst.Set(reflect.ValueOf(entityFromFields(m, fields, dst.Type() == nodeType)))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		// IDs arrive as numbers but are strings in Node and most structs.
+		switch cell.(type) {
+		case int64, float64:
+			dst.SetString(ids.Format(cell))
+			return nil
+		}
+	case reflect.Struct:
+		m, ok := cell.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot decode %T into %s", cell, dst.Type())
+		}
+		if fields, ok := flattenEntity(m); ok {
+			m = fields
+		}
+		return scanStruct(m, dst)
+	case reflect.Slice:
+		items, ok := cell.([]interface{})
+		if !ok || dst.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		out := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := scanCell(item, out.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Map:
+		m, ok := cell.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			break
+		}
+		out := reflect.MakeMapWithSize(dst.Type(), len(m))
+		for k, item := range m {
+			v := reflect.New(dst.Type().Elem()).Elem()
+			if err := scanCell(item, v); err != nil {
+				return fmt.Errorf("[%q]: %w", k, err)
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), v)
+		}
+		dst.Set(out)
+		return nil
+	}
+	return convertValue(cell, dst.Addr().Interface())
+}
+
+// scanTime decodes a temporal cell.
+func scanTime(cell interface{}) (time.Time, error) {
+	switch v := cell.(type) {
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as a time", v)
+	case int64, float64, int:
+		return time.UnixMilli(int64(asFloat(v))), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot decode %T into time.Time", cell)
+}
+
+// entityFromFields builds a Node or Relationship from a cell and its
+// flattenEntity fields.
+func entityFromFields(m, fields map[string]interface{}, node bool) interface{} {
+	props := map[string]interface{}{}
+	for k, v := range fields {
+		if !strings.HasPrefix(k, "_") {
+			props[k] = v
+		}
+	}
+	id := idString(fields["_id"])
+	if node {
+		var labels []string
+		for _, l := range asSlice(fields["_labels"]) {
+			labels = append(labels, fmt.Sprint(l))
+		}
+		return Node{ID: id, Labels: labels, Properties: props}
+	}
+	rel := Relationship{ID: id, Type: fmt.Sprint(fields["_type"]), Properties: props}
+	for _, pair := range [][2]string{{"_source", "_target"}, {"start_node", "end_node"}, {"source", "target"}, {"start", "end"}} {
+		if start, ok := m[pair[0]]; ok {
+			rel.StartNode, rel.EndNode = idString(start), idString(m[pair[1]])
+			break
+		}
+	}
+	return rel
+}
@@ -0,0 +1,135 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryResultScan(t *testing.T) {
+	type Address struct {
+		City string `nexus:"city"`
+	}
+	type Person struct {
+		ID      string `nexus:"_id"`
+		Name    string `nexus:"name"`
+		Age     int
+		Born    time.Time `nexus:"born"`
+		Email   *string   `nexus:"email"`
+		Tags    []string  `nexus:"tags"`
+		Address Address   `nexus:"address"`
+		Secret  string    `nexus:"-"`
+	}
+	type Row struct {
+		Person   Person  `nexus:"p"`
+		Friend   *Person `nexus:"friend"`
+		Node     Node    `nexus:"node"`
+		Since    time.Time
+		Weight   float32          `nexus:"w"`
+		Optional Optional[int64]  `nexus:"opt"`
+		Scores   map[string]int64 `nexus:"scores"`
+	}
+	ann := map[string]interface{}{"id": int64(1), "labels": []interface{}{"Person"}, "properties": map[string]interface{}{
+		"name": "Ann", "age": int64(30), "born": "1994-05-01T10:00:00Z", "email": "ann@example.com",
+		"tags": []interface{}{"a", "b"}, "address": map[string]interface{}{"city": "Oslo"}, "secret": "x",
+	}}
+	qr := &QueryResult{
+		Columns: []string{"p", "friend", "since", "w", "opt", "scores", "secret", "node"},
+		Rows: [][]interface{}{{
+			ann,
+			map[string]interface{}{"_nexus_id": 2.0, "name": "Bob", "age": 41.0, "email": nil},
+			"2020-01-02",
+			0.5,
+			nil,
+			map[string]interface{}{"x": int64(1), "y": 2.0},
+			"ignored",
+			ann,
+		}},
+	}
+
+	var rows []Row
+	require.NoError(t, qr.Scan(&rows))
+	require.Len(t, rows, 1)
+	r := rows[0]
+	email := "ann@example.com"
+	assert.Equal(t, Person{
+		ID: "1", Name: "Ann", Age: 30, Born: time.Date(1994, 5, 1, 10, 0, 0, 0, time.UTC),
+		Email: &email, Tags: []string{"a", "b"}, Address: Address{City: "Oslo"},
+	}, r.Person)
+	assert.Equal(t, &Person{ID: "2", Name: "Bob", Age: 41}, r.Friend)
+	assert.Equal(t, "1", r.Node.ID)
+	assert.Equal(t, []string{"Person"}, r.Node.Labels)
+	assert.Equal(t, "Ann", r.Node.Properties["name"])
+	assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), r.Since)
+	assert.Equal(t, float32(0.5), r.Weight)
+	assert.False(t, r.Optional.Valid)
+	assert.Equal(t, map[string]int64{"x": 1, "y": 2}, r.Scores)
+
+	var first Row
+	require.NoError(t, qr.Scan(&first))
+	assert.Equal(t, "Ann", first.Person.Name)
+
+	var names []string
+	require.NoError(t, (&QueryResult{Columns: []string{"n"}, Rows: [][]interface{}{{"a"}, {"b"}}}).Scan(&names))
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	var count int
+	assert.ErrorIs(t, (&QueryResult{Columns: []string{"n"}}).Scan(&count), ErrNoRows)
+	assert.ErrorContains(t, qr.Scan(rows), "non-nil pointer")
+
+	var ages []struct{ Age int }
+	err := (&QueryResult{Columns: []string{"Age"}, Rows: [][]interface{}{{1.5}}}).Scan(&ages)
+	assert.ErrorContains(t, err, `row 0: column "Age"`)
+	err = (&QueryResult{Columns: []string{"since"}, Rows: [][]interface{}{{"yesterday"}}}).Scan(&rows)
+	assert.ErrorContains(t, err, `cannot parse "yesterday" as a time`)
+
+	// Entity-style tags: options are ignored, and the node ID fills the
+	// ",id" field or else an untagged ID.
+	type Tagged struct {
+		Key  string `nexus:",id"`
+		Name string `nexus:"name,omitempty"`
+	}
+	type Untagged struct {
+		ID   int64
+		Name string
+	}
+	var entities []struct {
+		Tagged   Tagged    `nexus:"a"`
+		Untagged *Untagged `nexus:"b"`
+		WithID   Untagged  `nexus:"c"`
+	}
+	require.NoError(t, (&QueryResult{Columns: []string{"a", "b", "c"}, Rows: [][]interface{}{{
+		ann, ann, map[string]interface{}{"_nexus_id": int64(1), "id": int64(9), "name": "Ann"},
+	}}}).Scan(&entities))
+	assert.Equal(t, Tagged{Key: "1", Name: "Ann"}, entities[0].Tagged)
+	assert.Equal(t, &Untagged{ID: 1, Name: "Ann"}, entities[0].Untagged)
+	assert.Equal(t, int64(9), entities[0].WithID.ID, "a property named id wins over the node ID")
+}
+
+func TestQueryGeneric(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"columns":["name","born"],"rows":[["Ann",1700000000000],["Bob",null]]}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	type Person struct {
+		Name string     `nexus:"name"`
+		Born *time.Time `nexus:"born"`
+	}
+	people, err := Query[Person](context.Background(), client, "MATCH (p:Person) RETURN p.name AS name, p.born AS born", nil)
+	require.NoError(t, err)
+	require.Len(t, people, 2)
+	assert.Equal(t, "Ann", people[0].Name)
+	assert.Equal(t, time.UnixMilli(1700000000000), *people[0].Born)
+	assert.Nil(t, people[1].Born)
+
+	names, err := Query[string](context.Background(), &Transaction{client: client, id: "tx"}, "RETURN 1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ann", "Bob"}, names)
+}
@@ -0,0 +1,324 @@
+package nexus
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PropertyType is the expected Go/JSON kind of a property value.
+type PropertyType string
+
+const (
+	TypeAny     PropertyType = ""
+	TypeString  PropertyType = "string"
+	TypeInteger PropertyType = "integer"
+	TypeFloat   PropertyType = "float"
+	TypeNumber  PropertyType = "number"
+	TypeBoolean PropertyType = "boolean"
+	TypeList    PropertyType = "list"
+	TypeMap     PropertyType = "map"
+)
+
+// PropertyRule constrains one property of a label.
+type PropertyRule struct {
+	Type     PropertyType
+	Required bool
+	// Pattern is a regular expression string values must match.
+	Pattern string
+	// Min / Max bound numeric values (inclusive).
+	Min *float64
+	Max *float64
+	// MinLength / MaxLength bound string length in runes (0 = unbounded).
+	MinLength int
+	MaxLength int
+	// Enum lists the allowed values, compared with fmt.Sprint.
+	Enum []interface{}
+}
+
+// LabelSchema declares the properties of nodes carrying a label.
+type LabelSchema struct {
+	Properties map[string]PropertyRule
+	// Strict rejects properties not declared in Properties.
+	Strict bool
+}
+
+// Schema is an optional client-side property schema. Assign it to
+// Config.Schema and node writes (CreateNode, CreateNodeWithExternalID,
+// BatchCreateNodes, UpdateNode) are validated before any request is
+// sent; all violations are reported together in a *ValidationError.
+//
+// The zero Schema is empty and ready to use. A Schema is safe for
+// concurrent use; Define may be called while the client is serving
+// requests.
+type Schema struct {
+	mu          sync.RWMutex
+	labels      map[string]LabelSchema
+	patterns    map[schemaProperty]*regexp.Regexp
+	jsonSchemas map[string]*jsonSchema
+}
+
+// schemaProperty keys a compiled pattern. A struct rather than a
+// joined string keeps labels and names containing dots apart.
+type schemaProperty struct {
+	label, name string
+}
+
+// NewSchema returns an empty schema. Labels without a definition are
+// not validated.
+func NewSchema() *Schema {
+	return &Schema{
+		labels:   make(map[string]LabelSchema),
+		patterns: make(map[schemaProperty]*regexp.Regexp),
+	}
+}
+
+// Define registers (or replaces) the schema for label. Patterns are
+// compiled eagerly so a typo fails here instead of on the first write.
+func (s *Schema) Define(label string, ls LabelSchema) error {
+	compiled := make(map[schemaProperty]*regexp.Regexp)
+	for name, rule := range ls.Properties {
+		if rule.Pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("nexus: schema %s.%s: invalid pattern: %w", label, name, err)
+		}
+		compiled[schemaProperty{label, name}] = re
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.labels == nil {
+		s.labels = make(map[string]LabelSchema)
+		s.patterns = make(map[schemaProperty]*regexp.Regexp)
+	}
+	// Drop the patterns of the definition being replaced, so a rule
+	// that no longer has one does not keep enforcing it.
+	for name := range s.labels[label].Properties {
+		delete(s.patterns, schemaProperty{label, name})
+	}
+	s.labels[label] = ls
+	for k, re := range compiled {
+		s.patterns[k] = re
+	}
+	return nil
+}
+
+// Violation is a single schema failure.
+type Violation struct {
+	// Item is the position in a batch write (0 for single writes).
+	Item     int
+	Label    string
+	Property string
+	Message  string
+}
+
+func (v Violation) String() string {
+	if v.Property == "" {
+		return fmt.Sprintf("%s: %s", v.Label, v.Message)
+	}
+	return fmt.Sprintf("%s.%s: %s", v.Label, v.Property, v.Message)
+}
+
+// ValidationError aggregates every violation found in a write.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.String()
+	}
+	return fmt.Sprintf("nexus: %d schema violation(s): %s", len(e.Violations), strings.Join(msgs, "; "))
+}
+
+// Validate checks properties against the schema of every label in
+// labels and returns the violations (nil when valid).
+func (s *Schema) Validate(labels []string, properties map[string]interface{}) []Violation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Violation
+	for _, label := range labels {
+		if ls, ok := s.labels[label]; ok {
+			out = append(out, s.validateLabel(label, ls, properties)...)
+		}
+		if js, ok := s.jsonSchemas[label]; ok {
+			out = append(out, validateJSONSchema(label, js, properties)...)
+		}
+	}
+	return out
+}
+
+func validateJSONSchema(label string, js *jsonSchema, properties map[string]interface{}) []Violation {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	normalized, err := normalizeJSON(properties)
+	if err != nil {
+		return []Violation{{Label: label, Message: fmt.Sprintf("properties are not JSON-encodable: %v", err)}}
+	}
+	var out []Violation
+	js.validate(normalized, "", func(path, msg string) {
+		out = append(out, Violation{Label: label, Property: path, Message: msg})
+	})
+	return out
+}
+
+func (s *Schema) validateLabel(label string, ls LabelSchema, properties map[string]interface{}) []Violation {
+	var out []Violation
+	add := func(prop, format string, args ...interface{}) {
+		out = append(out, Violation{Label: label, Property: prop, Message: fmt.Sprintf(format, args...)})
+	}
+
+	names := make([]string, 0, len(ls.Properties))
+	for name := range ls.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rule := ls.Properties[name]
+		value, present := properties[name]
+		if !present || value == nil {
+			if rule.Required {
+				add(name, "required property is missing")
+			}
+			continue
+		}
+		if !matchesType(rule.Type, value) {
+			add(name, "expected %s, got %T", rule.Type, value)
+			continue
+		}
+		if n, ok := numericValue(value); ok {
+			if rule.Min != nil && n < *rule.Min {
+				add(name, "value %v is below minimum %v", value, *rule.Min)
+			}
+			if rule.Max != nil && n > *rule.Max {
+				add(name, "value %v is above maximum %v", value, *rule.Max)
+			}
+		}
+		if str, ok := value.(string); ok {
+			length := len([]rune(str))
+			if rule.MinLength > 0 && length < rule.MinLength {
+				add(name, "length %d is below minimum %d", length, rule.MinLength)
+			}
+			if rule.MaxLength > 0 && length > rule.MaxLength {
+				add(name, "length %d is above maximum %d", length, rule.MaxLength)
+			}
+			if re := s.patterns[schemaProperty{label, name}]; re != nil && !re.MatchString(str) {
+				add(name, "value %q does not match pattern %s", str, rule.Pattern)
+			}
+		}
+		if len(rule.Enum) > 0 && !inEnum(rule.Enum, value) {
+			add(name, "value %v is not one of %v", value, rule.Enum)
+		}
+	}
+
+	if ls.Strict {
+		var unknown []string
+		for name := range properties {
+			if _, ok := ls.Properties[name]; !ok {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		for _, name := range unknown {
+			add(name, "property is not declared in the schema")
+		}
+	}
+	return out
+}
+
+// validateNode runs the configured schema (if any) over a single write.
+func (c *Client) validateNode(labels []string, properties map[string]interface{}) error {
+	schema := c.schema.Load()
+	if schema == nil {
+		return nil
+	}
+	if v := schema.Validate(labels, properties); len(v) > 0 {
+		return &ValidationError{Violations: v}
+	}
+	return nil
+}
+
+func matchesType(t PropertyType, v interface{}) bool {
+	switch t {
+	case TypeAny:
+		return true
+	case TypeString:
+		_, ok := v.(string)
+		return ok
+	case TypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case TypeInteger:
+		switch n := v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		case float32:
+			return n == float32(int64(n))
+		}
+		return false
+	case TypeFloat, TypeNumber:
+		_, ok := numericValue(v)
+		return ok
+	case TypeList:
+		switch v.(type) {
+		case []interface{}, []string, []int, []int64, []float64, []float32, []bool:
+			return true
+		}
+		return false
+	case TypeMap:
+		_, ok := v.(map[string]interface{})
+		return ok
+	}
+	return false
+}
+
+func numericValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	s := fmt.Sprint(v)
+	for _, e := range enum {
+		if fmt.Sprint(e) == s {
+			return true
+		}
+	}
+	return false
+}
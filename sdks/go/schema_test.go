@@ -0,0 +1,209 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	minAge := 0.0
+	schema := NewSchema()
+	require.NoError(t, schema.Define("Person", LabelSchema{
+		Strict: true,
+		Properties: map[string]PropertyRule{
+			"name":  {Type: TypeString, Required: true, MinLength: 1},
+			"email": {Type: TypeString, Pattern: `^[^@]+@[^@]+$`},
+			"age":   {Type: TypeInteger, Min: &minAge},
+			"role":  {Enum: []interface{}{"admin", "user"}},
+		},
+	}))
+
+	assert.Empty(t, schema.Validate([]string{"Person"}, map[string]interface{}{
+		"name": "Ann", "email": "ann@example.com", "age": 30, "role": "user",
+	}))
+	assert.Empty(t, schema.Validate([]string{"Company"}, map[string]interface{}{"x": 1}))
+
+	violations := schema.Validate([]string{"Person"}, map[string]interface{}{
+		"email": "nope", "age": -1, "role": "root", "extra": true,
+	})
+	props := make([]string, len(violations))
+	for i, v := range violations {
+		props[i] = v.Property
+	}
+	assert.Equal(t, []string{"age", "email", "name", "role", "extra"}, props)
+
+	assert.Error(t, schema.Define("Bad", LabelSchema{Properties: map[string]PropertyRule{"x": {Pattern: "("}}}))
+}
+
+func TestSchemaRedefineDropsPatterns(t *testing.T) {
+	schema := NewSchema()
+	require.NoError(t, schema.Define("Person", LabelSchema{
+		Properties: map[string]PropertyRule{"email": {Type: TypeString, Pattern: `^[^@]+@[^@]+$`}},
+	}))
+	require.Len(t, schema.Validate([]string{"Person"}, map[string]interface{}{"email": "nope"}), 1)
+
+	require.NoError(t, schema.Define("Person", LabelSchema{
+		Properties: map[string]PropertyRule{"email": {Type: TypeString}},
+	}))
+	assert.Empty(t, schema.Validate([]string{"Person"}, map[string]interface{}{"email": "nope"}))
+}
+
+func TestZeroSchemaKeepsDottedPatternsApart(t *testing.T) {
+	schema := &Schema{}
+	require.NoError(t, schema.Define("a.b", LabelSchema{
+		Properties: map[string]PropertyRule{"c": {Type: TypeString, Pattern: `^x$`}},
+	}))
+	require.NoError(t, schema.Define("a", LabelSchema{
+		Properties: map[string]PropertyRule{"b.c": {Type: TypeString, Pattern: `^y$`}},
+	}))
+
+	assert.Empty(t, schema.Validate([]string{"a.b"}, map[string]interface{}{"c": "x"}))
+	assert.Empty(t, schema.Validate([]string{"a"}, map[string]interface{}{"b.c": "y"}))
+}
+
+func TestCreateNodeSchemaViolationSkipsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	schema := NewSchema()
+	require.NoError(t, schema.Define("Person", LabelSchema{
+		Properties: map[string]PropertyRule{"name": {Type: TypeString, Required: true}},
+	}))
+	client := NewClient(Config{BaseURL: server.URL, Schema: schema})
+
+	_, err := client.CreateNode(context.Background(), []string{"Person"}, map[string]interface{}{"name": 42})
+
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Len(t, verr.Violations, 1)
+	assert.Equal(t, "name", verr.Violations[0].Property)
+}
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema := NewSchema()
+	require.NoError(t, schema.DefineJSONSchema("Person", []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"age": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"address": {"type": "object", "properties": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}}
+		}
+	}`)))
+
+	assert.Empty(t, schema.Validate([]string{"Person"}, map[string]interface{}{
+		"name": "Ann", "age": 3, "tags": []string{"a"}, "address": map[string]interface{}{"zip": "12345"},
+	}))
+
+	violations := schema.Validate([]string{"Person"}, map[string]interface{}{
+		"age": 1.5, "tags": []interface{}{"a", 2}, "address": map[string]interface{}{"zip": "x"}, "extra": 1,
+	})
+	paths := make([]string, len(violations))
+	for i, v := range violations {
+		paths[i] = v.Property
+	}
+	assert.Equal(t, []string{"name", "address.zip", "age", "extra", "tags[1]"}, paths)
+
+	assert.Error(t, schema.DefineJSONSchema("Bad", []byte(`{"pattern": "("}`)))
+}
+
+func TestValidateGraph(t *testing.T) {
+	// String IDs, as some transports return them, still page the scan.
+	nodes := [][]interface{}{
+		{"1", map[string]interface{}{"name": "Ann"}},
+		{"2", map[string]interface{}{"name": 5}},
+		{"3", map[string]interface{}{"name": "Cid"}},
+	}
+	var afters []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		afters = append(afters, req.Parameters["after"])
+		var rows [][]interface{}
+		for _, n := range nodes {
+			id, _ := strconv.Atoi(n[0].(string))
+			if float64(id) > req.Parameters["after"].(float64) && len(rows) < int(req.Parameters["limit"].(float64)) {
+				rows = append(rows, n)
+			}
+		}
+		writeJSON(w, map[string]interface{}{"columns": []string{"id", "props"}, "rows": rows})
+	}))
+	defer server.Close()
+
+	// The LabelSchema rule would flag every node; ValidateGraph only
+	// applies JSON schemas.
+	schema := NewSchema()
+	require.NoError(t, schema.Define("Person", LabelSchema{
+		Properties: map[string]PropertyRule{"email": {Type: TypeString, Required: true}},
+	}))
+	require.NoError(t, schema.DefineJSONSchema("Person", []byte(`{"properties": {"name": {"type": "string"}}}`)))
+	client := NewClient(Config{BaseURL: server.URL, Schema: schema})
+
+	report, err := client.ValidateGraph(context.Background(), ValidateGraphOptions{BatchSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.NodesScanned)
+	require.Len(t, report.Violations, 1)
+	assert.Equal(t, "2", report.Violations[0].NodeID)
+	assert.Equal(t, "name", report.Violations[0].Property)
+	assert.Equal(t, []interface{}{float64(-1), float64(2)}, afters)
+
+	nodes = [][]interface{}{{"n1", map[string]interface{}{}}}
+	_, err = client.ValidateGraph(context.Background(), ValidateGraphOptions{BatchSize: 1})
+	assert.ErrorContains(t, err, `invalid ID "n1"`)
+}
+
+func TestValidateGraphQuotesLabels(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cypherRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req.Query)
+		writeJSON(w, map[string]interface{}{"columns": []string{"id", "props"}, "rows": [][]interface{}{}})
+	}))
+	defer server.Close()
+
+	schema := NewSchema()
+	require.NoError(t, schema.DefineJSONSchema("Legacy Person", []byte(`{"type": "object"}`)))
+	client := NewClient(Config{BaseURL: server.URL, Schema: schema})
+
+	_, err := client.ValidateGraph(context.Background(), ValidateGraphOptions{})
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "MATCH (n:`Legacy Person`)")
+}
+
+func TestRegisterJSONSchemaWhileServing(t *testing.T) {
+	client := NewClient(Config{BaseURL: "http://127.0.0.1:1"})
+	view := client.WithRetry(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			label := fmt.Sprintf("L%d", i)
+			assert.NoError(t, client.RegisterJSONSchema(label, []byte(`{"required": ["name"]}`)))
+			_ = view.validateNode([]string{label}, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	// Every registration landed on the one schema the view shares.
+	for i := 0; i < 8; i++ {
+		assert.Error(t, view.validateNode([]string{fmt.Sprintf("L%d", i)}, nil))
+	}
+}
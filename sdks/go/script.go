@@ -0,0 +1,192 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ScriptStatement is one statement of a Cypher script.
+type ScriptStatement struct {
+	Text string
+	// Line is the 1-based line on which the statement starts.
+	Line int
+}
+
+// SplitStatements splits a Cypher script on `;` terminators. Semicolons
+// inside string literals ('…', "…"), backtick-quoted identifiers and
+// comments (`// …`, `/* … */`) do not split; comments are stripped from
+// the returned statements and empty statements are dropped.
+func SplitStatements(script string) []ScriptStatement {
+	var (
+		out   []ScriptStatement
+		cur   strings.Builder
+		start = 0 // line of the first non-space rune of cur
+		line  = 1
+		runes = []rune(script)
+	)
+	flush := func() {
+		if text := strings.TrimSpace(cur.String()); text != "" {
+			out = append(out, ScriptStatement{Text: text, Line: start})
+		}
+		cur.Reset()
+		start = 0
+	}
+	emit := func(r rune) {
+		if start == 0 && !isSpace(r) {
+			start = line
+		}
+		cur.WriteRune(r)
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case r == '/' && next == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			if i < len(runes) {
+				line++
+				cur.WriteRune('\n')
+			}
+		case r == '/' && next == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i++ // skip the closing '/'
+			cur.WriteRune(' ')
+		case r == '\'' || r == '"' || r == '`':
+			emit(r)
+			for i++; i < len(runes); i++ {
+				c := runes[i]
+				cur.WriteRune(c)
+				if c == '\n' {
+					line++
+				}
+				if c == '\\' && r != '`' && i+1 < len(runes) {
+					i++
+					cur.WriteRune(runes[i])
+					continue
+				}
+				if c == r {
+					break
+				}
+			}
+		case r == ';':
+			flush()
+		default:
+			emit(r)
+			if r == '\n' {
+				line++
+			}
+		}
+	}
+	flush()
+	return out
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// ScriptOptions configures RunScript.
+type ScriptOptions struct {
+	// Transactional runs every statement in one transaction, committed
+	// only if all succeed. Otherwise each statement auto-commits.
+	Transactional bool
+	// ContinueOnError keeps executing after a failed statement
+	// (ignored when Transactional, where the first failure rolls back).
+	ContinueOnError bool
+	// Params are passed to every statement.
+	Params map[string]interface{}
+}
+
+// StatementResult is the outcome of one script statement.
+type StatementResult struct {
+	Statement ScriptStatement
+	Result    *QueryResult
+	Err       error
+}
+
+// ScriptResult reports every executed statement in order.
+type ScriptResult struct {
+	Statements []StatementResult
+	// Failed counts statements with a non-nil Err.
+	Failed int
+	// RolledBack is set when a transactional script was rolled back.
+	RolledBack bool
+}
+
+// ScriptError is returned by RunScript when a statement fails.
+type ScriptError struct {
+	Statement ScriptStatement
+	Err       error
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("nexus: script statement at line %d: %v", e.Statement.Line, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error { return e.Err }
+
+// RunScript reads a .cypher script from r, splits it with
+// SplitStatements and executes the statements in order. The returned
+// ScriptResult is populated even when an error is returned; the error is
+// a *ScriptError for the first failed statement.
+func (c *Client) RunScript(ctx context.Context, r io.Reader, opts ScriptOptions) (*ScriptResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: read script: %w", err)
+	}
+	statements := SplitStatements(string(data))
+	out := &ScriptResult{Statements: make([]StatementResult, 0, len(statements))}
+
+	exec := c.ExecuteCypher
+	var tx *Transaction
+	if opts.Transactional {
+		if tx, err = c.BeginTransaction(ctx); err != nil {
+			return out, err
+		}
+		exec = tx.ExecuteCypher
+	}
+
+	var firstErr error
+	for _, stmt := range statements {
+		result, err := exec(ctx, stmt.Text, opts.Params)
+		out.Statements = append(out.Statements, StatementResult{Statement: stmt, Result: result, Err: err})
+		if err == nil {
+			continue
+		}
+		out.Failed++
+		if firstErr == nil {
+			firstErr = &ScriptError{Statement: stmt, Err: err}
+		}
+		if tx != nil || !opts.ContinueOnError {
+			break
+		}
+	}
+
+	if tx != nil {
+		if firstErr != nil {
+			out.RolledBack = true
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				return out, fmt.Errorf("%w (rollback failed: %v)", firstErr, rbErr)
+			}
+			return out, firstErr
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return out, err
+		}
+	}
+	return out, firstErr
+}
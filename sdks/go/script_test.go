@@ -0,0 +1,73 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `// seed data
+CREATE (:A {v: 'x;y'}); /* block; comment */
+CREATE (:B {v: "it\"s;"});
+
+MATCH (n:` + "`odd;label`" + `) RETURN n // trailing
+;`
+
+	stmts := SplitStatements(script)
+
+	require.Len(t, stmts, 3)
+	assert.Equal(t, ScriptStatement{Text: "CREATE (:A {v: 'x;y'})", Line: 2}, stmts[0])
+	assert.Equal(t, ScriptStatement{Text: `CREATE (:B {v: "it\"s;"})`, Line: 3}, stmts[1])
+	assert.Equal(t, "MATCH (n:`odd;label`) RETURN n", stmts[2].Text)
+	assert.Equal(t, 5, stmts[2].Line)
+}
+
+func TestRunScriptTransactionalRollback(t *testing.T) {
+	var executed []string
+	rolledBack := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/transaction/begin":
+			json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx1"})
+		case "/transaction/rollback":
+			rolledBack = true
+			w.Write([]byte(`{}`))
+		case "/transaction/execute":
+			var req map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			query := req["query"].(string)
+			executed = append(executed, query)
+			if strings.Contains(query, "BROKEN") {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("syntax error"))
+				return
+			}
+			w.Write([]byte(`{"columns":[],"rows":[]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	result, err := client.RunScript(context.Background(),
+		strings.NewReader("CREATE (:A);\nBROKEN;\nCREATE (:C);"),
+		ScriptOptions{Transactional: true})
+
+	var scriptErr *ScriptError
+	require.True(t, errors.As(err, &scriptErr))
+	assert.Equal(t, 2, scriptErr.Statement.Line)
+	assert.True(t, rolledBack)
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, []string{"CREATE (:A)", "BROKEN"}, executed)
+}
@@ -0,0 +1,213 @@
+package nexus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SemanticCacheOptions configures a SemanticCache.
+type SemanticCacheOptions struct {
+	// Label holds the cache entries (default "SemanticCacheEntry").
+	Label string
+	// Threshold is the minimum similarity score a cached entry needs to
+	// answer a lookup (default 0.95 — near paraphrases under cosine
+	// similarity).
+	Threshold float64
+	// TTL is how long entries answer lookups (default 24h).
+	TTL time.Duration
+	// Candidates is the number of nearest entries considered per lookup
+	// (default 5).
+	Candidates int
+}
+
+// SemanticCacheHit is a cached payload similar enough to the lookup.
+type SemanticCacheHit struct {
+	ID      string
+	Score   float64
+	Payload json.RawMessage
+	// StoredAt and ExpiresAt bound the entry's lifetime.
+	StoredAt  time.Time
+	ExpiresAt time.Time
+}
+
+// SemanticCache answers requests whose embedding is close to an earlier
+// one from that earlier request's stored result, skipping the query or
+// LLM call. Entries are nodes carrying the request embedding, so the
+// label needs a vector index (see EnsureIndex). Typical RAG use:
+//
+//	cache, err := client.NewSemanticCache(nexus.SemanticCacheOptions{TTL: time.Hour})
+//	result, hit, err := cache.ExecuteCypher(ctx, questionEmbedding, retrievalQuery, params)
+//
+// A SemanticCache is safe for concurrent use.
+type SemanticCache struct {
+	client *Client
+	opts   SemanticCacheOptions
+	now    func() time.Time
+}
+
+// NewSemanticCache returns a cache over opts.Label, which must be a
+// valid label.
+func (c *Client) NewSemanticCache(opts SemanticCacheOptions) (*SemanticCache, error) {
+	if opts.Label == "" {
+		opts.Label = "SemanticCacheEntry"
+	}
+	if err := validLabelIdentifier(opts.Label); err != nil {
+		return nil, err
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = 0.95
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.Candidates <= 0 {
+		opts.Candidates = 5
+	}
+	return &SemanticCache{client: c, opts: opts, now: time.Now}, nil
+}
+
+// EnsureIndex creates the cosine vector index over the entries'
+// embeddings, ignoring the error of an index that already exists.
+func (s *SemanticCache) EnsureIndex(ctx context.Context, dimensions int) error {
+	err := s.client.CreateVectorIndex(ctx, VectorIndexOptions{
+		Name:       s.opts.Label + "_embedding",
+		Label:      s.opts.Label,
+		Property:   "embedding",
+		Dimensions: dimensions,
+		Similarity: SimilarityCosine,
+	})
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == 409 {
+		return nil
+	}
+	return err
+}
+
+// Lookup returns the most similar live entry scoring at least
+// Threshold, or nil on a miss.
+func (s *SemanticCache) Lookup(ctx context.Context, embedding []float32) (*SemanticCacheHit, error) {
+	return s.lookup(ctx, embedding, "")
+}
+
+// lookup is Lookup restricted to the entries stored under key, when
+// key is set.
+func (s *SemanticCache) lookup(ctx context.Context, embedding []float32, key string) (*SemanticCacheHit, error) {
+	// The timestamp is an integer literal and the key a hex digest, so
+	// the predicate is safe to inline.
+	where := fmt.Sprintf("n.expires_at > %d", s.now().UnixMilli())
+	if key != "" {
+		where += fmt.Sprintf(" AND n.query_key = '%s'", key)
+	}
+	matches, err := s.client.KnnSearch(ctx, KnnRequest{
+		Label:  s.opts.Label,
+		Vector: embedding,
+		K:      s.opts.Candidates,
+		Where:  where,
+		Limit:  s.opts.Candidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var best *SemanticCacheHit
+	for _, m := range matches {
+		if m.Score < s.opts.Threshold || (best != nil && m.Score <= best.Score) {
+			continue
+		}
+		if key != "" && m.Properties["query_key"] != key {
+			continue // servers that ignore the predicate
+		}
+		payload, _ := m.Properties["payload"].(string)
+		expires := time.UnixMilli(int64(asFloat(m.Properties["expires_at"])))
+		if !expires.After(s.now()) {
+			continue // servers that ignore the predicate
+		}
+		best = &SemanticCacheHit{
+			ID:        m.ID,
+			Score:     m.Score,
+			Payload:   json.RawMessage(payload),
+			StoredAt:  time.UnixMilli(int64(asFloat(m.Properties["stored_at"]))),
+			ExpiresAt: expires,
+		}
+	}
+	return best, nil
+}
+
+// Store caches payload, marshalled to JSON, under embedding.
+func (s *SemanticCache) Store(ctx context.Context, embedding []float32, payload interface{}) error {
+	return s.store(ctx, embedding, payload, "")
+}
+
+func (s *SemanticCache) store(ctx context.Context, embedding []float32, payload interface{}, key string) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("nexus: semantic cache payload: %w", err)
+	}
+	now := s.now()
+	props := map[string]interface{}{
+		"embedding":  embedding,
+		"payload":    string(data),
+		"stored_at":  now.UnixMilli(),
+		"expires_at": now.Add(s.opts.TTL).UnixMilli(),
+	}
+	if key != "" {
+		props["query_key"] = key
+	}
+	_, err = s.client.CreateNode(ctx, []string{s.opts.Label}, props)
+	return err
+}
+
+// ExecuteCypher returns the cached result of the same query, with the
+// same parameters, asked with a similar embedding, or runs the query
+// and caches its result. hit reports which happened. Queries match
+// token by token, literals included, so layout and comments do not
+// matter.
+func (s *SemanticCache) ExecuteCypher(ctx context.Context, embedding []float32, query string, params map[string]interface{}) (result *QueryResult, hit bool, err error) {
+	key, err := semanticCacheKey(query, params)
+	if err != nil {
+		return nil, false, err
+	}
+	cached, err := s.lookup(ctx, embedding, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if cached != nil {
+		result = &QueryResult{}
+		if err := json.Unmarshal(cached.Payload, result); err == nil {
+			return result, true, nil
+		}
+	}
+	result, err = s.client.ExecuteCypher(ctx, query, params)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, false, s.store(ctx, embedding, result, key)
+}
+
+// semanticCacheKey identifies a query and its parameters.
+func semanticCacheKey(query string, params map[string]interface{}) (string, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("nexus: semantic cache parameters: %w", err)
+	}
+	sum := sha256.Sum256([]byte(compactQuery(query) + "\n" + string(data)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Purge deletes expired entries and returns how many were removed.
+func (s *SemanticCache) Purge(ctx context.Context) (int64, error) {
+	result, err := s.client.ExecuteCypher(ctx,
+		"MATCH (n:"+s.opts.Label+") WHERE n.expires_at <= $now DELETE n RETURN count(*) AS purged",
+		map[string]interface{}{"now": s.now().UnixMilli()})
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return 0, nil
+	}
+	return int64(asInt(result.Rows[0][0])), nil
+}
@@ -0,0 +1,110 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCacheServer keeps created entries and scores every KNN hit with
+// the dot product of the request and stored vectors.
+type fakeCacheServer struct {
+	mu      sync.Mutex
+	entries []map[string]interface{}
+	queries int
+	wheres  []string
+}
+
+func (f *fakeCacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/nodes":
+		var req struct {
+			Properties map[string]interface{} `json:"properties"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		f.entries = append(f.entries, req.Properties)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "1"})
+	case "/knn_traverse":
+		var req KnnRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.wheres = append(f.wheres, req.Where)
+		var nodes []map[string]interface{}
+		for i, e := range f.entries {
+			var score float64
+			for j, x := range e["embedding"].([]interface{}) {
+				score += x.(float64) * float64(req.Vector[j])
+			}
+			nodes = append(nodes, map[string]interface{}{"id": i + 1, "properties": e, "score": score})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"nodes": nodes})
+	case "/cypher":
+		f.queries++
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"answer"}, "rows": [][]interface{}{{"42"}}})
+	}
+}
+
+func TestSemanticCache(t *testing.T) {
+	fake := &fakeCacheServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	now := time.UnixMilli(1_700_000_000_000)
+	cache, err := client.NewSemanticCache(SemanticCacheOptions{Threshold: 0.9, TTL: time.Minute})
+	require.NoError(t, err)
+	cache.now = func() time.Time { return now }
+
+	_, hit, err := cache.ExecuteCypher(ctx, []float32{1, 0}, "MATCH (d:Doc) RETURN d.answer AS answer", nil)
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, 1, fake.queries)
+	require.Len(t, fake.entries, 1)
+
+	// A near paraphrase is answered from the cache.
+	result, hit, err := cache.ExecuteCypher(ctx, []float32{0.95, 0.05}, "MATCH (d:Doc) RETURN d.answer AS answer", nil)
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.Equal(t, []string{"answer"}, result.Columns)
+	assert.Equal(t, "42", result.Rows[0][0])
+	assert.Equal(t, 1, fake.queries)
+	key, err := semanticCacheKey("MATCH (d:Doc)\n  RETURN d.answer AS answer", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "n.expires_at > 1700000000000 AND n.query_key = '"+key+"'", fake.wheres[1])
+
+	// Another query, or other parameters, miss even with the same
+	// embedding; the fake server ignores the key predicate.
+	_, hit, err = cache.ExecuteCypher(ctx, []float32{1, 0}, "MATCH (d:Doc) RETURN d.title AS answer", nil)
+	require.NoError(t, err)
+	assert.False(t, hit)
+	_, hit, err = cache.ExecuteCypher(ctx, []float32{1, 0}, "MATCH (d:Doc) WHERE d.lang = $lang RETURN d.answer AS answer", map[string]interface{}{"lang": "en"})
+	require.NoError(t, err)
+	assert.False(t, hit)
+	_, hit, err = cache.ExecuteCypher(ctx, []float32{1, 0}, "MATCH (d:Doc) WHERE d.lang = $lang RETURN d.answer AS answer", map[string]interface{}{"lang": "pt"})
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, 4, fake.queries)
+
+	// An unrelated question misses.
+	got, err := cache.Lookup(ctx, []float32{0, 1})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Expired entries miss even when the server ignores the predicate.
+	now = now.Add(2 * time.Minute)
+	got, err = cache.Lookup(ctx, []float32{1, 0})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = client.NewSemanticCache(SemanticCacheOptions{Label: "Bad Label"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,384 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestSigner authenticates an outgoing HTTP request, typically for a
+// gateway in front of Nexus (AWS API Gateway, GCP IAP, an OIDC proxy).
+// SignRequest may read req.Body but must leave it readable.
+type RequestSigner interface {
+	SignRequest(req *http.Request) error
+}
+
+// RequestSignerFunc adapts a function to RequestSigner.
+type RequestSignerFunc func(req *http.Request) error
+
+// SignRequest calls f(req).
+func (f RequestSignerFunc) SignRequest(req *http.Request) error { return f(req) }
+
+// NewSigningRoundTripper returns a RoundTripper that signs a clone of
+// every request with signer before passing it to base (default
+// http.DefaultTransport). Config.Signer installs one on the client.
+func NewSigningRoundTripper(signer RequestSigner, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &signingRoundTripper{signer: signer, base: base}
+}
+
+type signingRoundTripper struct {
+	signer RequestSigner
+	base   http.RoundTripper
+}
+
+func (rt *signingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed := req.Clone(req.Context())
+	if err := rt.signer.SignRequest(signed); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("nexus: sign request: %w", err)
+	}
+	return rt.base.RoundTrip(signed)
+}
+
+// readBody returns the request payload and leaves req.Body rewound.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(data)), nil }
+	return data, nil
+}
+
+// BearerTokenSigner sets `Authorization: Bearer <token>` from Token on
+// every request, for OIDC-protected gateways such as an ALB with OIDC
+// authentication. Token is called per request and should cache.
+type BearerTokenSigner struct {
+	Token func(ctx context.Context) (string, error)
+}
+
+// SignRequest implements RequestSigner.
+func (s *BearerTokenSigner) SignRequest(req *http.Request) error {
+	token, err := s.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// AWSCredentials are the keys used for SigV4 signing.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary (STS) credentials.
+	SessionToken string
+}
+
+// AWSCredentialsFromEnv reads AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+// and AWS_SESSION_TOKEN on every call, so rotated values are picked up.
+func AWSCredentialsFromEnv(context.Context) (AWSCredentials, error) {
+	creds := AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return AWSCredentials{}, errors.New("nexus: AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY not set")
+	}
+	return creds, nil
+}
+
+// AWSSigV4Signer signs requests with AWS Signature Version 4, as required
+// by API Gateway endpoints using IAM authorization.
+type AWSSigV4Signer struct {
+	// Region is the AWS region, e.g. "eu-west-1".
+	Region string
+	// Service is the signing name (default "execute-api").
+	Service string
+	// Credentials supplies the keys per request (default
+	// AWSCredentialsFromEnv).
+	Credentials func(ctx context.Context) (AWSCredentials, error)
+	// Now overrides the signing clock (tests).
+	Now func() time.Time
+}
+
+const sigV4Algorithm = "AWS4-HMAC-SHA256"
+
+// SignRequest implements RequestSigner.
+func (s *AWSSigV4Signer) SignRequest(req *http.Request) error {
+	if s.Region == "" {
+		return errors.New("nexus: SigV4 region must not be empty")
+	}
+	service := s.Service
+	if service == "" {
+		service = "execute-api"
+	}
+	credsFn := s.Credentials
+	if credsFn == nil {
+		credsFn = AWSCredentialsFromEnv
+	}
+	creds, err := credsFn(req.Context())
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	payloadHash := sha256Hex(body)
+
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	day := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	if service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" {
+			trimmed := make([]string, len(values))
+			for i, v := range values {
+				trimmed[i] = strings.Join(strings.Fields(v), " ")
+			}
+			headers[lower] = strings.Join(trimmed, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if service != "s3" {
+		// Every service but S3 signs the already-escaped path escaped again.
+		path = sigV4Escape(path, false)
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		sigV4Query(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.Region + "/" + service + "/aws4_request"
+	stringToSign := sigV4Algorithm + "\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), day)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, creds.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+func sigV4Query(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vs := append([]string(nil), values[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			parts = append(parts, sigV4Escape(k, true)+"="+sigV4Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// sigV4Escape percent-encodes everything but RFC 3986 unreserved
+// characters (and '/' unless encodeSlash).
+func sigV4Escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// gcpMetadataIdentityURL is the metadata-server endpoint minting ID
+// tokens for the instance's service account.
+const gcpMetadataIdentityURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/identity"
+
+// GCPIAPSigner authenticates to a backend behind Google Cloud
+// Identity-Aware Proxy with an OIDC ID token whose audience is the IAP
+// OAuth client ID. The token goes in Authorization, or in
+// Proxy-Authorization when Authorization is already taken (e.g. by
+// Nexus bearer auth), as IAP accepts both.
+type GCPIAPSigner struct {
+	// Audience is the IAP OAuth client ID.
+	Audience string
+	// TokenSource mints an ID token for audience. The default asks the
+	// GCE/GKE/Cloud Run metadata server; plug in
+	// google.golang.org/api/idtoken elsewhere.
+	TokenSource func(ctx context.Context, audience string) (string, error)
+	// HTTPClient is used by the default token source.
+	HTTPClient *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// SignRequest implements RequestSigner.
+func (s *GCPIAPSigner) SignRequest(req *http.Request) error {
+	if s.Audience == "" {
+		return errors.New("nexus: IAP audience must not be empty")
+	}
+	token, err := s.idToken(req.Context())
+	if err != nil {
+		return err
+	}
+	header := "Authorization"
+	if req.Header.Get(header) != "" {
+		header = "Proxy-Authorization"
+	}
+	req.Header.Set(header, "Bearer "+token)
+	return nil
+}
+
+// idToken returns the cached token until a minute before it expires.
+func (s *GCPIAPSigner) idToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Until(s.expiry) > time.Minute {
+		return s.token, nil
+	}
+	source := s.TokenSource
+	if source == nil {
+		source = s.metadataToken
+	}
+	token, err := source(ctx, s.Audience)
+	if err != nil {
+		return "", fmt.Errorf("nexus: fetch IAP ID token: %w", err)
+	}
+	s.token, s.expiry = token, jwtExpiry(token)
+	return token, nil
+}
+
+func (s *GCPIAPSigner) metadataToken(ctx context.Context, audience string) (string, error) {
+	u := gcpMetadataIdentityURL + "?format=full&audience=" + url.QueryEscape(audience)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// jwtExpiry reads the exp claim of a JWT without verifying it; tokens
+// without one are treated as already expiring, so they are not cached.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
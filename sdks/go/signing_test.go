@@ -0,0 +1,145 @@
+package nexus
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSigV4Signer() *AWSSigV4Signer {
+	return &AWSSigV4Signer{
+		Region:  "us-east-1",
+		Service: "service",
+		Credentials: func(context.Context) (AWSCredentials, error) {
+			return AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}, nil
+		},
+		Now: func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+}
+
+// The expected signatures come from the AWS SigV4 test suite.
+func TestAWSSigV4SignerTestSuite(t *testing.T) {
+	cases := []struct {
+		url, signature string
+	}{
+		{"https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	}
+	for _, tc := range cases {
+		req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+		require.NoError(t, err)
+		require.NoError(t, testSigV4Signer().SignRequest(req))
+
+		assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+		assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+			"SignedHeaders=host;x-amz-date, Signature="+tc.signature, req.Header.Get("Authorization"), tc.url)
+	}
+}
+
+func TestAWSSigV4SignerKeepsBodyAndSessionToken(t *testing.T) {
+	signer := testSigV4Signer()
+	signer.Credentials = func(context.Context) (AWSCredentials, error) {
+		return AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "tok"}, nil
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/cypher", io.NopCloser(strings.NewReader(`{"query":"RETURN 1"}`)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	require.NoError(t, signer.SignRequest(req))
+
+	assert.Equal(t, "tok", req.Header.Get("X-Amz-Security-Token"))
+	assert.Contains(t, req.Header.Get("Authorization"), "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,")
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"query":"RETURN 1"}`, string(body))
+}
+
+func fakeJWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "eyJhbGciOiJSUzI1NiJ9." + payload + ".sig"
+}
+
+func TestGCPIAPSignerCachesToken(t *testing.T) {
+	fetches := 0
+	token := fakeJWT(time.Now().Add(time.Hour))
+	signer := &GCPIAPSigner{
+		Audience: "123.apps.googleusercontent.com",
+		TokenSource: func(_ context.Context, audience string) (string, error) {
+			fetches++
+			assert.Equal(t, "123.apps.googleusercontent.com", audience)
+			return token, nil
+		},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://nexus.example.com/health", nil)
+	require.NoError(t, signer.SignRequest(req))
+	assert.Equal(t, "Bearer "+token, req.Header.Get("Authorization"))
+
+	req, _ = http.NewRequest(http.MethodGet, "https://nexus.example.com/health", nil)
+	req.Header.Set("Authorization", "Bearer nexus-token")
+	require.NoError(t, signer.SignRequest(req))
+	assert.Equal(t, "Bearer nexus-token", req.Header.Get("Authorization"))
+	assert.Equal(t, "Bearer "+token, req.Header.Get("Proxy-Authorization"))
+	assert.Equal(t, 1, fetches)
+}
+
+func TestGCPIAPSignerMetadataServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		assert.Equal(t, "aud", r.URL.Query().Get("audience"))
+		w.Write([]byte("id-token\n"))
+	}))
+	defer server.Close()
+
+	// Route the metadata host to the test server.
+	signer := &GCPIAPSigner{Audience: "aud", HTTPClient: &http.Client{Transport: rewriteHost(server.URL)}}
+	req, _ := http.NewRequest(http.MethodGet, "https://nexus.example.com/health", nil)
+	require.NoError(t, signer.SignRequest(req))
+	assert.Equal(t, "Bearer id-token", req.Header.Get("Authorization"))
+}
+
+type rewriteHost string
+
+func (h rewriteHost) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := strings.TrimPrefix(string(h), "http://")
+	req = req.Clone(req.Context())
+	req.URL.Host, req.Host = target, target
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClientSigner(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"columns":["x"],"rows":[[1]]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		Signer: &BearerTokenSigner{Token: func(context.Context) (string, error) {
+			return "gw-token", nil
+		}},
+	})
+	_, err := client.ExecuteCypher(context.Background(), "RETURN 1 AS x", nil)
+	require.NoError(t, err)
+	_, err = client.ListWebhooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer gw-token", "Bearer gw-token"}, auth)
+}
+
+func TestClientSignerRequiresHTTP(t *testing.T) {
+	_, err := NewClientE(Config{
+		BaseURL: "nexus://127.0.0.1:15475",
+		Signer:  RequestSignerFunc(func(*http.Request) error { return nil }),
+	})
+	assert.ErrorContains(t, err, "requires the HTTP transport")
+}
@@ -0,0 +1,85 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Snapshot is a named, read-only copy of the graph kept by the server
+// for reproducible analytics runs.
+type Snapshot struct {
+	Name              string    `json:"name"`
+	CreatedAt         time.Time `json:"created_at"`
+	NodeCount         int64     `json:"node_count"`
+	RelationshipCount int64     `json:"relationship_count"`
+}
+
+// CreateSnapshot captures the current graph state under name.
+func (c *Client) CreateSnapshot(ctx context.Context, name string) (*Snapshot, error) {
+	if name == "" {
+		return nil, errors.New("nexus: snapshot name must not be empty")
+	}
+	reqBody := map[string]interface{}{
+		"name": name,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/snapshots", reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var snapshot Snapshot
+	if err := decodeResponse(resp, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ListSnapshots retrieves all named snapshots.
+func (c *Client) ListSnapshots(ctx context.Context) ([]Snapshot, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/snapshots", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Snapshots []Snapshot `json:"snapshots"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	return result.Snapshots, nil
+}
+
+// DeleteSnapshot deletes a snapshot by name.
+func (c *Client) DeleteSnapshot(ctx context.Context, name string) error {
+	path := fmt.Sprintf("/snapshots/%s", url.PathEscape(name))
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ExecuteCypherOnSnapshot runs a read query against a named snapshot
+// instead of the live graph. Like as-of reads it uses the HTTP route.
+func (c *Client) ExecuteCypherOnSnapshot(ctx context.Context, snapshot, query string, params map[string]interface{}) (*QueryResult, error) {
+	if snapshot == "" {
+		return nil, errors.New("nexus: snapshot name must not be empty")
+	}
+	query, err := c.checkQuery(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx, finish := c.startQuery(ctx, query, params, false)
+	result, err := c.postCypher(ctx, cypherRequest{Query: query, Parameters: params, Snapshot: snapshot})
+	return result, finish(result, err)
+}
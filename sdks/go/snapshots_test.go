@@ -0,0 +1,105 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /snapshots":
+			var req map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "nightly", req["name"])
+			w.Write([]byte(`{"name":"nightly","created_at":"2026-01-02T03:04:05Z","node_count":10,"relationship_count":4}`))
+		case "GET /snapshots":
+			w.Write([]byte(`{"snapshots":[{"name":"nightly","node_count":10},{"name":"weekly","node_count":8}]}`))
+		case "POST /cypher":
+			var req map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "nightly", req["snapshot"])
+			assert.Equal(t, "MATCH (n) RETURN count(n) AS c", req["query"])
+			assert.Equal(t, map[string]interface{}{"x": 1.0}, req["parameters"])
+			w.Write([]byte(`{"columns":["c"],"rows":[[10]]}`))
+		case "DELETE /snapshots/a/b":
+			assert.Equal(t, "/snapshots/a%2Fb", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	var events []QueryEvent
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		Metrics: MetricsHookFunc(func(ctx context.Context, e QueryEvent) { events = append(events, e) }),
+	})
+
+	snapshot, err := client.CreateSnapshot(ctx, "nightly")
+	require.NoError(t, err)
+	assert.Equal(t, &Snapshot{Name: "nightly", CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), NodeCount: 10, RelationshipCount: 4}, snapshot)
+
+	snapshots, err := client.ListSnapshots(ctx)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "weekly", snapshots[1].Name)
+
+	result, err := client.ExecuteCypherOnSnapshot(ctx, "nightly", "MATCH (n) RETURN count(n) AS c", map[string]interface{}{"x": 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, result.Columns)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, []ColumnType{ColumnInteger}, result.ColumnTypes)
+	require.Len(t, events, 1)
+	assert.Equal(t, "MATCH (n) RETURN count(n) AS c", events[0].Query)
+
+	require.NoError(t, client.DeleteSnapshot(ctx, "a/b"))
+
+	_, err = client.CreateSnapshot(ctx, "")
+	assert.ErrorContains(t, err, "snapshot name must not be empty")
+	_, err = client.ExecuteCypherOnSnapshot(ctx, "", "RETURN 1", nil)
+	assert.ErrorContains(t, err, "snapshot name must not be empty")
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/snapshots/diff", r.URL.Path)
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "v1", req["a"])
+		assert.Equal(t, "v2", req["b"])
+		assert.Equal(t, []interface{}{"Person"}, req["labels"])
+
+		w.Write([]byte(`{"entity":"node","change":"added","id":"1","labels":["Person"],"after":{"name":"Ann"}}` + "\n"))
+		w.(http.Flusher).Flush()
+		// Outlives Config.Timeout: streams must not be cut by it.
+		time.Sleep(150 * time.Millisecond)
+		w.Write([]byte(`{"entity":"relationship","change":"changed","id":"r1","type":"KNOWS","changed_keys":["since"]}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+	stream, err := client.DiffSnapshots(context.Background(), "v1", "v2", DiffOptions{Labels: []string{"Person"}})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var entries []DiffEntry
+	for stream.Next() {
+		entries = append(entries, stream.Entry())
+	}
+	require.NoError(t, stream.Err())
+	require.Len(t, entries, 2)
+	assert.Equal(t, ChangeAdded, entries[0].Change)
+	assert.Equal(t, "Ann", entries[0].After["name"])
+	assert.Equal(t, EntityRelationship, entries[1].Entity)
+	assert.Equal(t, []string{"since"}, entries[1].ChangedKeys)
+}
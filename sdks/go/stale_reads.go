@@ -0,0 +1,231 @@
+package nexus
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// StaleReadConfig makes GetNode and RunSavedQuery fall back to the last
+// result they got for the same request when Nexus cannot answer, for
+// UIs that prefer stale data to none. Results served this way carry a
+// Staleness marker; fresh ones never do. Results are cached per
+// database and tenant (see WithDatabase and WithTenant).
+type StaleReadConfig struct {
+	// MaxEntries bounds the results kept, least recently used first out
+	// (default 10000).
+	MaxEntries int
+	// MaxAge is the oldest result served; 0 means no limit.
+	MaxAge time.Duration
+	// ServeOn reports whether a failed read may fall back to the cache
+	// (default: the circuit is open or IsServerFailure).
+	ServeOn func(error) bool
+}
+
+// Staleness marks a result served from the client-side cache because
+// the read failed.
+type Staleness struct {
+	// CachedAt is when the result was fetched from the server, and Age
+	// how old it was when served.
+	CachedAt time.Time
+	Age      time.Duration
+	// Err is the error of the read the result stands in for.
+	Err error
+}
+
+// staleCache implements StaleReadConfig. A nil *staleCache keeps and
+// serves nothing.
+type staleCache struct {
+	cfg StaleReadConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // of *staleEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type staleEntry struct {
+	key      string
+	value    interface{}
+	cachedAt time.Time
+}
+
+func newStaleCache(cfg *StaleReadConfig) *staleCache {
+	if cfg == nil {
+		return nil
+	}
+	s := &staleCache{cfg: *cfg, now: time.Now, order: list.New(), entries: map[string]*list.Element{}}
+	if s.cfg.MaxEntries <= 0 {
+		s.cfg.MaxEntries = 10000
+	}
+	if s.cfg.ServeOn == nil {
+		s.cfg.ServeOn = func(err error) bool {
+			return errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrClientClosed) && IsServerFailure(err)
+		}
+	}
+	return s
+}
+
+// remember stores value, the fresh result for key.
+func (s *staleCache) remember(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		e := el.Value.(*staleEntry)
+		e.value, e.cachedAt = value, s.now()
+		s.order.MoveToFront(el)
+		return
+	}
+	s.entries[key] = s.order.PushFront(&staleEntry{key: key, value: value, cachedAt: s.now()})
+	for s.order.Len() > s.cfg.MaxEntries {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*staleEntry).key)
+	}
+}
+
+// forget drops key, e.g. after the entity was deleted.
+func (s *staleCache) forget(key string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		s.order.Remove(el)
+		delete(s.entries, key)
+	}
+}
+
+// recall returns the cached result for key when a read that failed
+// with err may be served from the cache.
+func (s *staleCache) recall(key string, err error) (interface{}, *Staleness, bool) {
+	if s == nil || !s.cfg.ServeOn(err) {
+		return nil, nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	e := el.Value.(*staleEntry)
+	age := s.now().Sub(e.cachedAt)
+	if s.cfg.MaxAge > 0 && age > s.cfg.MaxAge {
+		return nil, nil, false
+	}
+	s.order.MoveToFront(el)
+	return e.value, &Staleness{CachedAt: e.cachedAt, Age: age, Err: err}, true
+}
+
+// staleScope prefixes cache keys with the database and tenant of ctx,
+// so a read is never answered with another tenant's cached result.
+func staleScope(ctx context.Context) string {
+	return DatabaseFromContext(ctx) + "\x00" + TenantFromContext(ctx) + "\x00"
+}
+
+func staleNodeKey(ctx context.Context, id string) string {
+	return staleScope(ctx) + "node\x00" + id
+}
+
+func staleSavedQueryKey(ctx context.Context, name string, params map[string]interface{}) string {
+	// Map keys marshal sorted, so equal params give equal keys.
+	p, _ := json.Marshal(params)
+	return staleScope(ctx) + "saved\x00" + name + "\x00" + string(p)
+}
+
+// staleNode returns node, or on err the cached copy of node id. The
+// cache keeps its own deep copy, so callers may change or release what
+// they are given.
+func (c *Client) staleNode(ctx context.Context, id string, node *Node, err error) (*Node, error) {
+	key := staleNodeKey(ctx, id)
+	if err == nil {
+		if c.stale != nil {
+			c.stale.remember(key, copyNode(node))
+		}
+		return node, nil
+	}
+	v, staleness, ok := c.stale.recall(key, err)
+	if !ok {
+		return nil, err
+	}
+	cached := copyNode(v.(*Node))
+	cached.Staleness = staleness
+	return cached, nil
+}
+
+// staleResult is staleNode for query results.
+func (c *Client) staleResult(key string, result *QueryResult, err error) (*QueryResult, error) {
+	if err == nil {
+		if c.stale != nil {
+			c.stale.remember(key, copyResult(result))
+		}
+		return result, nil
+	}
+	v, staleness, ok := c.stale.recall(key, err)
+	if !ok {
+		return nil, err
+	}
+	cached := copyResult(v.(*QueryResult))
+	cached.Staleness = staleness
+	return cached, nil
+}
+
+func copyNode(n *Node) *Node {
+	c := *n
+	c.Labels = append([]string(nil), n.Labels...)
+	c.Properties, _ = copyValue(n.Properties).(map[string]interface{})
+	return &c
+}
+
+// copyResult copies r without its pooled row storage, which Release
+// would hand back.
+func copyResult(r *QueryResult) *QueryResult {
+	c := *r
+	c.pooled = nil
+	c.Columns = append([]string(nil), r.Columns...)
+	if r.Rows != nil {
+		c.Rows = make([][]interface{}, len(r.Rows))
+		for i, row := range r.Rows {
+			c.Rows[i], _ = copyValue(row).([]interface{})
+		}
+	}
+	if r.Stats != nil {
+		stats := *r.Stats
+		c.Stats = &stats
+	}
+	c.ColumnTypes = append([]ColumnType(nil), r.ColumnTypes...)
+	c.Notifications = append([]Notification(nil), r.Notifications...)
+	return &c
+}
+
+// copyValue deep-copies the maps and slices of a decoded value.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = copyValue(e)
+		}
+		return s
+	}
+	return v
+}
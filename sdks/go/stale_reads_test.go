@@ -0,0 +1,146 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleReads(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/nodes/404":
+			w.WriteHeader(http.StatusNotFound)
+		case !healthy.Load():
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/queries/top/run":
+			w.Write([]byte(`{"columns":["n"],"rows":[[1]]}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Write([]byte(`{"id":"1","labels":["Person"],"properties":{"name":"Ada"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		BaseURL:        server.URL,
+		CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Hour},
+		StaleReads:     &StaleReadConfig{MaxAge: time.Minute},
+	})
+	now := time.Now()
+	client.stale.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	node, err := client.GetNode(ctx, "1")
+	require.NoError(t, err)
+	assert.Nil(t, node.Staleness)
+	result, err := client.RunSavedQuery(ctx, "top", map[string]interface{}{"k": 3})
+	require.NoError(t, err)
+	assert.Nil(t, result.Staleness)
+	// The cache keeps its own copy of what callers change or release.
+	node.Properties["name"] = "Bob"
+	result.Rows[0][0] = 99
+	result.Release()
+
+	// Client errors are answers, not outages.
+	_, err = client.GetNode(ctx, "404")
+	require.Error(t, err)
+
+	healthy.Store(false)
+	now = now.Add(10 * time.Second)
+	node, err = client.GetNode(ctx, "1")
+	require.NoError(t, err)
+	require.NotNil(t, node.Staleness)
+	assert.Equal(t, "Ada", node.Properties["name"])
+	assert.Equal(t, 10*time.Second, node.Staleness.Age)
+	assert.True(t, IsServerFailure(node.Staleness.Err))
+	node.Properties["name"] = "Bob"
+	node, err = client.GetNode(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", node.Properties["name"])
+
+	// The circuit is now open; the cache still answers.
+	result, err = client.RunSavedQuery(ctx, "top", map[string]interface{}{"k": 3})
+	require.NoError(t, err)
+	require.NotNil(t, result.Staleness)
+	assert.ErrorIs(t, result.Staleness.Err, ErrCircuitOpen)
+	assert.Equal(t, []interface{}{float64(1)}, result.Rows[0])
+
+	// Other params, or results past MaxAge, are not served.
+	_, err = client.RunSavedQuery(ctx, "top", map[string]interface{}{"k": 4})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	now = now.Add(time.Minute)
+	_, err = client.GetNode(ctx, "1")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestStaleReadsAreScopedToTenant(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/queries/top/run" {
+			w.Write([]byte(`{"columns":["tenant"],"rows":[["` + r.Header.Get(TenantHeader) + `"]]}`))
+			return
+		}
+		w.Write([]byte(`{"id":"1","labels":["Person"],"properties":{"tenant":"` + r.Header.Get(TenantHeader) + `"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, StaleReads: &StaleReadConfig{}})
+	a := WithTenant(context.Background(), "a")
+	b := WithTenant(context.Background(), "b")
+	_, err := client.GetNode(a, "1")
+	require.NoError(t, err)
+	_, err = client.RunSavedQuery(a, "top", nil)
+	require.NoError(t, err)
+
+	healthy.Store(false)
+	_, err = client.GetNode(b, "1")
+	assert.True(t, IsServerFailure(err), "tenant b has nothing cached")
+	_, err = client.RunSavedQuery(b, "top", nil)
+	assert.True(t, IsServerFailure(err))
+	_, err = client.GetNode(WithDatabase(a, "other"), "1")
+	assert.True(t, IsServerFailure(err), "nor has another database")
+
+	node, err := client.GetNode(a, "1")
+	require.NoError(t, err)
+	require.NotNil(t, node.Staleness)
+	assert.Equal(t, "a", node.Properties["tenant"])
+	result, err := client.RunSavedQuery(a, "top", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a", result.Rows[0][0])
+}
+
+func TestStaleCacheEvictsAndForgets(t *testing.T) {
+	s := newStaleCache(&StaleReadConfig{MaxEntries: 2})
+	s.remember("a", 1)
+	s.remember("b", 2)
+	_, _, ok := s.recall("a", ErrCircuitOpen)
+	require.True(t, ok)
+	s.remember("c", 3)
+
+	_, _, ok = s.recall("b", ErrCircuitOpen)
+	assert.False(t, ok, "least recently used entry is evicted")
+	v, _, ok := s.recall("a", ErrCircuitOpen)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	s.forget("a")
+	_, _, ok = s.recall("a", ErrCircuitOpen)
+	assert.False(t, ok)
+	_, _, ok = s.recall("c", ErrClientClosed)
+	assert.False(t, ok)
+}
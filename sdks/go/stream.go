@@ -0,0 +1,45 @@
+package nexus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxStreamLine bounds a single NDJSON record. Embedding vectors and
+// diff entries with large property maps routinely exceed bufio's 64 KiB
+// default token size.
+const maxStreamLine = 16 << 20
+
+// forEachJSONLine decodes a newline-delimited JSON stream, calling fn
+// with each non-empty record in order. Returning an error from fn stops
+// the scan and propagates it.
+func forEachJSONLine(r io.Reader, fn func(json.RawMessage) error) error {
+	scanner := newJSONLineScanner(r)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		// The scanner reuses its buffer — hand fn a private copy.
+		record := make(json.RawMessage, len(line))
+		copy(record, line)
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
+// newJSONLineScanner returns a line scanner sized for NDJSON records,
+// for iterators that pull one record per Next call.
+func newJSONLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+	return scanner
+}
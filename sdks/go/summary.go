@@ -0,0 +1,103 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// GraphSummary is a snapshot of the graph's shape, for health
+// dashboards and for spotting drift between two points in time. It
+// marshals to JSON for storing alongside earlier snapshots.
+type GraphSummary struct {
+	Nodes         int64 `json:"nodes"`
+	Relationships int64 `json:"relationships"`
+	// Labels and RelationshipTypes count nodes per label (a node with
+	// two labels counts towards both) and relationships per type.
+	Labels            map[string]int64 `json:"labels"`
+	RelationshipTypes map[string]int64 `json:"relationship_types"`
+	// NodePropertyKeys and RelationshipPropertyKeys count the entities
+	// carrying each property key.
+	NodePropertyKeys         map[string]int64 `json:"node_property_keys"`
+	RelationshipPropertyKeys map[string]int64 `json:"relationship_property_keys"`
+	// AverageDegree is the mean number of relationships per node,
+	// counting both endpoints.
+	AverageDegree float64   `json:"average_degree"`
+	CollectedAt   time.Time `json:"collected_at"`
+}
+
+// GraphSummary collects a GraphSummary. Totals come from GET /stats
+// when the server provides it; the per-label, per-type and property key
+// counts are aggregate queries that scan the graph, so on large graphs
+// call it from a dashboard refresh rather than a request path.
+func (c *Client) GraphSummary(ctx context.Context) (*GraphSummary, error) {
+	s := &GraphSummary{CollectedAt: time.Now().UTC()}
+	var err error
+	if s.Labels, err = c.countBy(ctx, "MATCH (n) UNWIND labels(n) AS k RETURN k, count(*) AS c"); err != nil {
+		return nil, err
+	}
+	if s.RelationshipTypes, err = c.countBy(ctx, "MATCH ()-[r]->() RETURN type(r) AS k, count(*) AS c"); err != nil {
+		return nil, err
+	}
+	if s.NodePropertyKeys, err = c.countBy(ctx, "MATCH (n) UNWIND keys(n) AS k RETURN k, count(*) AS c"); err != nil {
+		return nil, err
+	}
+	if s.RelationshipPropertyKeys, err = c.countBy(ctx, "MATCH ()-[r]->() UNWIND keys(r) AS k RETURN k, count(*) AS c"); err != nil {
+		return nil, err
+	}
+
+	if !c.catalogTotals(ctx, s) {
+		result, err := c.ExecuteCypher(ctx,
+			"MATCH (n) WITH count(n) AS nodes OPTIONAL MATCH ()-[r]->() RETURN nodes, count(r) AS rels", nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Rows) > 0 && len(result.Rows[0]) >= 2 {
+			s.Nodes, s.Relationships = int64(asInt(result.Rows[0][0])), int64(asInt(result.Rows[0][1]))
+		}
+	}
+	if s.Nodes > 0 {
+		s.AverageDegree = 2 * float64(s.Relationships) / float64(s.Nodes)
+	}
+	return s, nil
+}
+
+// catalogTotals fills the totals from GET /stats, reporting whether the
+// server answered.
+func (c *Client) catalogTotals(ctx context.Context, s *GraphSummary) bool {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/stats", nil)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	var stats struct {
+		Catalog *struct {
+			NodeCount int64 `json:"node_count"`
+			RelCount  int64 `json:"rel_count"`
+		} `json:"catalog"`
+	}
+	if decodeResponse(resp, &stats) != nil || stats.Catalog == nil {
+		return false
+	}
+	s.Nodes, s.Relationships = stats.Catalog.NodeCount, stats.Catalog.RelCount
+	return true
+}
+
+// countBy runs a (key, count) aggregation into a map.
+func (c *Client) countBy(ctx context.Context, query string) (map[string]int64, error) {
+	result, err := c.ExecuteCypher(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		if k, ok := row[0].(string); ok {
+			counts[k] += int64(asInt(row[1]))
+		}
+	}
+	return counts, nil
+}
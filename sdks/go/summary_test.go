@@ -0,0 +1,73 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func summaryServer(t *testing.T, withStats bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stats" {
+			if !withStats {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"catalog": map[string]interface{}{"node_count": 10, "rel_count": 15}})
+			return
+		}
+		var req struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var rows [][]interface{}
+		switch {
+		case strings.Contains(req.Query, "labels(n)"):
+			rows = [][]interface{}{{"Person", 8}, {"City", 2}}
+		case strings.Contains(req.Query, "type(r)"):
+			rows = [][]interface{}{{"LIVES_IN", 8}, {"KNOWS", 7}}
+		case strings.Contains(req.Query, "keys(n)"):
+			rows = [][]interface{}{{"name", 10}}
+		case strings.Contains(req.Query, "keys(r)"):
+			rows = [][]interface{}{{"since", 7}}
+		default:
+			rows = [][]interface{}{{4, 2}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{"k", "c"}, "rows": rows})
+	}))
+}
+
+func TestGraphSummary(t *testing.T) {
+	server := summaryServer(t, true)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	s, err := client.GraphSummary(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), s.Nodes)
+	assert.Equal(t, int64(15), s.Relationships)
+	assert.Equal(t, map[string]int64{"Person": 8, "City": 2}, s.Labels)
+	assert.Equal(t, map[string]int64{"LIVES_IN": 8, "KNOWS": 7}, s.RelationshipTypes)
+	assert.Equal(t, map[string]int64{"name": 10}, s.NodePropertyKeys)
+	assert.Equal(t, map[string]int64{"since": 7}, s.RelationshipPropertyKeys)
+	assert.InDelta(t, 3.0, s.AverageDegree, 1e-9)
+	assert.False(t, s.CollectedAt.IsZero())
+}
+
+func TestGraphSummaryWithoutStatsRoute(t *testing.T) {
+	server := summaryServer(t, false)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	s, err := client.GraphSummary(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), s.Nodes)
+	assert.Equal(t, int64(2), s.Relationships)
+	assert.InDelta(t, 1.0, s.AverageDegree, 1e-9)
+}
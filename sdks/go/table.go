@@ -0,0 +1,104 @@
+package nexus
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultTableWidth caps the columns of QueryResult.String.
+const defaultTableWidth = 40
+
+// RenderTable writes the result to w as an aligned text table: a header,
+// a rule, one line per row and a row count. Nulls print as "null",
+// composite values as JSON and numeric columns are right-aligned.
+// Cells longer than maxWidth characters are cut with "…"; maxWidth <= 0
+// leaves them whole.
+func (qr *QueryResult) RenderTable(w io.Writer, maxWidth int) error {
+	cells := make([][]string, len(qr.Rows))
+	widths := make([]int, len(qr.Columns))
+	for i, c := range qr.Columns {
+		widths[i] = utf8.RuneCountInString(truncateCell(c, maxWidth))
+	}
+	for r, row := range qr.Rows {
+		cells[r] = make([]string, len(qr.Columns))
+		for i := range qr.Columns {
+			var v interface{}
+			if i < len(row) {
+				v = row[i]
+			}
+			cells[r][i] = truncateCell(tableCell(v), maxWidth)
+			widths[i] = max(widths[i], utf8.RuneCountInString(cells[r][i]))
+		}
+	}
+	right := make([]bool, len(qr.Columns))
+	for i, t := range InferColumnTypes(qr.Columns, qr.Rows) {
+		right[i] = t == ColumnInteger || t == ColumnFloat
+	}
+
+	var b strings.Builder
+	line := func(values []string, pad func(i int) string) {
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			fill := strings.Repeat(pad(i), widths[i]-utf8.RuneCountInString(v))
+			switch {
+			case right[i]:
+				b.WriteString(fill + v)
+			case i == len(values)-1 && pad(i) == " ":
+				b.WriteString(v)
+			default:
+				b.WriteString(v + fill)
+			}
+		}
+		b.WriteString("\n")
+	}
+	header := make([]string, len(qr.Columns))
+	for i, c := range qr.Columns {
+		header[i] = truncateCell(c, maxWidth)
+	}
+	line(header, func(int) string { return " " })
+	line(make([]string, len(qr.Columns)), func(int) string { return "-" })
+	for _, row := range cells {
+		line(row, func(int) string { return " " })
+	}
+	fmt.Fprintf(&b, "(%d row(s))\n", len(qr.Rows))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// String renders the result as a table with RenderTable, cutting cells
+// at 40 characters, for debug logs and test failure messages.
+func (qr *QueryResult) String() string {
+	if qr == nil {
+		return "<nil>"
+	}
+	var b strings.Builder
+	qr.RenderTable(&b, defaultTableWidth)
+	return b.String()
+}
+
+// tableCell renders one cell on a single line.
+func tableCell(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	s := csvCell(v)
+	if strings.ContainsAny(s, "\t\r\n") {
+		s = strings.NewReplacer("\t", `\t`, "\r", `\r`, "\n", `\n`).Replace(s)
+	}
+	return s
+}
+
+func truncateCell(s string, maxWidth int) string {
+	if maxWidth <= 0 || utf8.RuneCountInString(s) <= maxWidth {
+		return s
+	}
+	if maxWidth == 1 {
+		return "…"
+	}
+	runes := []rune(s)
+	return string(runes[:maxWidth-1]) + "…"
+}
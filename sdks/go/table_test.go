@@ -0,0 +1,40 @@
+package nexus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryResultRenderTable(t *testing.T) {
+	qr := &QueryResult{
+		Columns: []string{"name", "age", "tags"},
+		Rows: [][]interface{}{
+			{"Ann", int64(42), []interface{}{"a", "b"}},
+			{"Bartholomew\nJr", int64(7), nil},
+		},
+	}
+
+	var b strings.Builder
+	require.NoError(t, qr.RenderTable(&b, 0))
+	assert.Equal(t, ""+
+		"name             age  tags\n"+
+		"---------------  ---  ---------\n"+
+		"Ann               42  [\"a\",\"b\"]\n"+
+		"Bartholomew\\nJr    7  null\n"+
+		"(2 row(s))\n", b.String())
+
+	b.Reset()
+	require.NoError(t, qr.RenderTable(&b, 6))
+	assert.Equal(t, ""+
+		"name    age  tags\n"+
+		"------  ---  ------\n"+
+		"Ann      42  [\"a\",…\n"+
+		"Barth…    7  null\n"+
+		"(2 row(s))\n", b.String())
+
+	assert.Contains(t, qr.String(), "Bartholomew\\nJr")
+	assert.Equal(t, "<nil>", (*QueryResult)(nil).String())
+}
@@ -0,0 +1,52 @@
+package nexus
+
+import (
+	"context"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// Request headers selecting the database and tenant of a request.
+const (
+	DatabaseHeader = "X-Nexus-Database"
+	TenantHeader   = "X-Nexus-Tenant"
+)
+
+// WithDatabase returns a context whose requests run against the named
+// database instead of the session default, so one shared client can
+// serve every database:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    ctx := nexus.WithDatabase(r.Context(), r.Header.Get("X-Workspace"))
+//	    result, err := client.ExecuteCypher(ctx, query, nil)
+//	    ...
+//	}
+//
+// Every client method honours it. On the RPC transport, scoped Cypher
+// requests go over HTTP, like other request-scoped options.
+func WithDatabase(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return transport.WithHeader(ctx, DatabaseHeader, name)
+}
+
+// WithTenant returns a context whose requests are attributed to tenant
+// id, for multi-tenant deployments that isolate data per tenant. It
+// composes with WithDatabase.
+func WithTenant(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return transport.WithHeader(ctx, TenantHeader, id)
+}
+
+// DatabaseFromContext returns the database set with WithDatabase, or "".
+func DatabaseFromContext(ctx context.Context) string {
+	return transport.HeadersFromContext(ctx).Get(DatabaseHeader)
+}
+
+// TenantFromContext returns the tenant set with WithTenant, or "".
+func TenantFromContext(ctx context.Context) string {
+	return transport.HeadersFromContext(ctx).Get(TenantHeader)
+}
@@ -0,0 +1,63 @@
+package nexus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDatabaseAndTenant(t *testing.T) {
+	var got []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Clone())
+		if r.URL.Path == "/webhooks" {
+			w.Write([]byte(`{"webhooks":[]}`))
+			return
+		}
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := WithTenant(WithDatabase(context.Background(), "sales"), "acme")
+	assert.Equal(t, "sales", DatabaseFromContext(ctx))
+	assert.Equal(t, "acme", TenantFromContext(ctx))
+
+	_, err := client.ExecuteCypher(ctx, "RETURN 1", nil)
+	require.NoError(t, err)
+	_, err = client.ListWebhooks(ctx)
+	require.NoError(t, err)
+	_, err = client.ExecuteCypher(context.Background(), "RETURN 1", nil)
+	require.NoError(t, err)
+
+	require.Len(t, got, 3)
+	for _, h := range got[:2] {
+		assert.Equal(t, "sales", h.Get(DatabaseHeader))
+		assert.Equal(t, "acme", h.Get(TenantHeader))
+	}
+	assert.Empty(t, got[2].Get(DatabaseHeader))
+}
+
+func TestWithDatabaseFederationScope(t *testing.T) {
+	var databases []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		databases = append(databases, r.Header.Get(DatabaseHeader))
+		w.Write([]byte(`{"columns":["n"],"rows":[[1]]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	scope := func(db string) func(context.Context) context.Context {
+		return func(ctx context.Context) context.Context { return WithDatabase(ctx, db) }
+	}
+	result, err := Federate(context.Background(), []FederationTarget{
+		{Name: "eu", Client: client, Scope: scope("eu")},
+	}, "RETURN 1 AS n", nil, FederateOptions{})
+	require.NoError(t, err)
+	assert.Len(t, result.Rows, 1)
+	assert.Equal(t, []string{"eu"}, databases)
+}
@@ -0,0 +1,109 @@
+package nexus
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSConfig configures TLS for https:// endpoints: trusting a private
+// or self-signed CA, presenting a client certificate (mTLS), or
+// skipping verification in development. PEM fields and file paths are
+// alternatives; when both are set the PEM wins.
+type TLSConfig struct {
+	// CAFile / CAPEM hold PEM certificates to trust in addition to the
+	// system roots.
+	CAFile string
+	CAPEM  []byte
+	// CertFile+KeyFile / CertPEM+KeyPEM are the client certificate and
+	// its private key, sent when the server asks for one.
+	CertFile string
+	KeyFile  string
+	CertPEM  []byte
+	KeyPEM   []byte
+	// ServerName overrides the name the server certificate is checked
+	// against, for servers reached by IP or through a tunnel.
+	ServerName string
+	// InsecureSkipVerify accepts any server certificate. It leaves the
+	// connection open to interception; use it only against local test
+	// servers. PinnedCertFingerprints still apply.
+	InsecureSkipVerify bool
+}
+
+// ClientConfig builds the crypto/tls configuration, reading CAFile,
+// CertFile and KeyFile.
+func (c TLSConfig) ClientConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	caPEM := c.CAPEM
+	if caPEM == nil && c.CAFile != "" {
+		var err error
+		if caPEM, err = os.ReadFile(c.CAFile); err != nil {
+			return nil, fmt.Errorf("nexus: reading CA bundle: %w", err)
+		}
+	}
+	if caPEM != nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("nexus: CA bundle contains no PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+
+	certPEM, keyPEM := c.CertPEM, c.KeyPEM
+	if certPEM == nil && c.CertFile != "" {
+		var err error
+		if certPEM, err = os.ReadFile(c.CertFile); err != nil {
+			return nil, fmt.Errorf("nexus: reading client certificate: %w", err)
+		}
+	}
+	if keyPEM == nil && c.KeyFile != "" {
+		var err error
+		if keyPEM, err = os.ReadFile(c.KeyFile); err != nil {
+			return nil, fmt.Errorf("nexus: reading client key: %w", err)
+		}
+	}
+	if (certPEM == nil) != (keyPEM == nil) {
+		return nil, errors.New("nexus: a client certificate needs both a certificate and a key")
+	}
+	if certPEM != nil {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// httpsTransport clones http.DefaultTransport with the TLS settings and
+// certificate pins applied; either may be empty.
+func httpsTransport(config *TLSConfig, pins []string) (*http.Transport, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config != nil {
+		var err error
+		if cfg, err = config.ClientConfig(); err != nil {
+			return nil, err
+		}
+	}
+	if len(pins) > 0 {
+		verify, err := PinnedCertVerifier(pins)
+		if err != nil {
+			return nil, err
+		}
+		cfg.VerifyConnection = verify
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = cfg
+	return t, nil
+}
@@ -0,0 +1,94 @@
+package nexus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selfSignedPEM(t *testing.T, cn string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestTLSConfig(t *testing.T) {
+	var clientCN string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			clientCN = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		w.Write([]byte(`{"columns":[],"rows":[]}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	query := func(cfg *TLSConfig) error {
+		client, err := NewClientE(Config{BaseURL: server.URL, TLS: cfg})
+		require.NoError(t, err)
+		_, err = client.ExecuteCypher(context.Background(), "RETURN 1", nil)
+		return err
+	}
+
+	assert.ErrorContains(t, query(&TLSConfig{}), "certificate")
+	assert.NoError(t, query(&TLSConfig{CAPEM: caPEM}))
+	assert.NoError(t, query(&TLSConfig{InsecureSkipVerify: true}))
+	assert.Empty(t, clientCN)
+
+	dir := t.TempDir()
+	certPEM, keyPEM := selfSignedPEM(t, "billing")
+	for name, data := range map[string][]byte{"ca.pem": caPEM, "client.pem": certPEM, "client.key": keyPEM} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+	assert.NoError(t, query(&TLSConfig{
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client.key"),
+	}))
+	assert.Equal(t, "billing", clientCN)
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	_, err := TLSConfig{CAPEM: []byte("nope")}.ClientConfig()
+	assert.ErrorContains(t, err, "no PEM certificates")
+	_, err = TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}.ClientConfig()
+	assert.ErrorContains(t, err, "reading CA bundle")
+	certPEM, _ := selfSignedPEM(t, "x")
+	_, err = TLSConfig{CertPEM: certPEM}.ClientConfig()
+	assert.ErrorContains(t, err, "both a certificate and a key")
+
+	_, err = NewClientWithOptions("http://localhost:15474", WithTLS(TLSConfig{InsecureSkipVerify: true}))
+	assert.ErrorContains(t, err, "Config.TLS requires an https:// endpoint")
+	_, err = NewClientWithOptions("https://localhost", WithTLS(TLSConfig{}), WithHTTPClient(&http.Client{Transport: http.DefaultTransport}))
+	assert.ErrorContains(t, err, "cannot be combined")
+}
@@ -0,0 +1,316 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// RowTransform rewrites one row.
+type RowTransform func(row []interface{}) ([]interface{}, error)
+
+// Transformer is one step of a result pipeline. Given the incoming
+// column names and a sample of rows (the whole result, or the first
+// page of an iterator) it returns the outgoing column names and the
+// function rewriting each row to match them. Transformers compose with
+// QueryResult.Transform and QueryIterator.Transform.
+type Transformer func(columns []string, sample [][]interface{}) ([]string, RowTransform, error)
+
+// RenameColumns renames columns; unlisted columns keep their names.
+func RenameColumns(names map[string]string) Transformer {
+	return func(columns []string, _ [][]interface{}) ([]string, RowTransform, error) {
+		out := make([]string, len(columns))
+		for i, c := range columns {
+			out[i] = c
+			if n, ok := names[c]; ok {
+				out[i] = n
+			}
+		}
+		return out, identityRow, nil
+	}
+}
+
+// DropColumns removes the named columns.
+func DropColumns(names ...string) Transformer {
+	return func(columns []string, _ [][]interface{}) ([]string, RowTransform, error) {
+		drop := map[string]bool{}
+		for _, n := range names {
+			drop[n] = true
+		}
+		var keep []int
+		for i, c := range columns {
+			if !drop[c] {
+				keep = append(keep, i)
+			}
+		}
+		return pick(columns, keep)
+	}
+}
+
+// SelectColumns keeps only the named columns, in the given order.
+func SelectColumns(names ...string) Transformer {
+	return func(columns []string, _ [][]interface{}) ([]string, RowTransform, error) {
+		keep := make([]int, len(names))
+		for i, n := range names {
+			keep[i] = columnIndex(columns, n)
+			if keep[i] < 0 {
+				return nil, nil, fmt.Errorf("nexus: transform: no column %q (columns %v)", n, columns)
+			}
+		}
+		return pick(columns, keep)
+	}
+}
+
+func pick(columns []string, keep []int) ([]string, RowTransform, error) {
+	out := make([]string, len(keep))
+	for i, k := range keep {
+		out[i] = columns[k]
+	}
+	return out, func(row []interface{}) ([]interface{}, error) {
+		next := make([]interface{}, len(keep))
+		for i, k := range keep {
+			if k < len(row) {
+				next[i] = row[k]
+			}
+		}
+		return next, nil
+	}, nil
+}
+
+// MapColumn rewrites every cell of column with fn.
+func MapColumn(column string, fn func(v interface{}) (interface{}, error)) Transformer {
+	return func(columns []string, _ [][]interface{}) ([]string, RowTransform, error) {
+		idx := columnIndex(columns, column)
+		if idx < 0 {
+			return nil, nil, fmt.Errorf("nexus: transform: no column %q (columns %v)", column, columns)
+		}
+		return columns, func(row []interface{}) ([]interface{}, error) {
+			if idx >= len(row) {
+				return row, nil
+			}
+			v, err := fn(row[idx])
+			if err != nil {
+				return nil, fmt.Errorf("nexus: transform column %q: %w", column, err)
+			}
+			next := append([]interface{}(nil), row...)
+			next[idx] = v
+			return next, nil
+		}, nil
+	}
+}
+
+// CoerceColumn converts the cells of column to a scalar type:
+// TypeString, TypeInteger, TypeFloat or TypeBoolean. Numeric strings
+// parse, whole floats become integers and lists and maps become JSON
+// strings; nulls stay null and anything else is an error.
+func CoerceColumn(column string, to PropertyType) Transformer {
+	return MapColumn(column, func(v interface{}) (interface{}, error) { return coerce(v, to) })
+}
+
+func coerce(v interface{}, to PropertyType) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch to {
+	case TypeString:
+		switch x := v.(type) {
+		case string:
+			return x, nil
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(x)
+			return string(data), err
+		}
+		return fmt.Sprint(v), nil
+	case TypeInteger:
+		switch x := v.(type) {
+		case int64:
+			return x, nil
+		case int:
+			return int64(x), nil
+		case float64:
+			if x == math.Trunc(x) && !math.IsInf(x, 0) {
+				return int64(x), nil
+			}
+		case string:
+			if n, err := strconv.ParseInt(x, 10, 64); err == nil {
+				return n, nil
+			}
+		case bool:
+			if x {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+	case TypeFloat, TypeNumber:
+		if f, ok := numericValue(v); ok {
+			return f, nil
+		}
+		if s, ok := v.(string); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f, nil
+			}
+		}
+	case TypeBoolean:
+		switch x := v.(type) {
+		case bool:
+			return x, nil
+		case string:
+			if b, err := strconv.ParseBool(x); err == nil {
+				return b, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("cannot coerce to %q", to)
+	}
+	return nil, fmt.Errorf("cannot coerce %T %v to %s", v, v, to)
+}
+
+// FlattenEntities replaces each node or relationship column with one
+// column per property ("n.name", "n.age") plus "n._id" and, as sent by
+// the server, "n._labels" or "r._type", the same keys RowMapOptions
+// uses. The property columns are those found in the sample, so an
+// iterator only sees the keys present in its first page. Columns
+// holding other values are left alone.
+func FlattenEntities() Transformer { return flattenColumns(flattenEntity) }
+
+// flattenMaps flattens nodes and relationships like FlattenEntities
+// and plain maps into one column per key.
+func flattenMaps(m map[string]interface{}) (map[string]interface{}, bool) {
+	if fields, ok := flattenEntity(m); ok {
+		return fields, true
+	}
+	return m, len(m) > 0
+}
+
+// flattenColumns spreads the map cells that fields accepts over one
+// column per key.
+func flattenColumns(fields func(map[string]interface{}) (map[string]interface{}, bool)) Transformer {
+	return func(columns []string, sample [][]interface{}) ([]string, RowTransform, error) {
+		keys := make([][]string, len(columns))
+		for i := range columns {
+			seen := map[string]bool{}
+			for _, row := range sample {
+				if i >= len(row) {
+					continue
+				}
+				m, ok := row[i].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if f, ok := fields(m); ok {
+					for k := range f {
+						seen[k] = true
+					}
+				}
+			}
+			keys[i] = sortedKeys(seen)
+		}
+		var out []string
+		for i, c := range columns {
+			if len(keys[i]) == 0 {
+				out = append(out, c)
+				continue
+			}
+			for _, k := range keys[i] {
+				out = append(out, c+"."+k)
+			}
+		}
+		return out, func(row []interface{}) ([]interface{}, error) {
+			next := make([]interface{}, 0, len(out))
+			for i := range columns {
+				var cell interface{}
+				if i < len(row) {
+					cell = row[i]
+				}
+				if len(keys[i]) == 0 {
+					next = append(next, cell)
+					continue
+				}
+				var f map[string]interface{}
+				if m, ok := cell.(map[string]interface{}); ok {
+					f, _ = fields(m)
+				}
+				for _, k := range keys[i] {
+					next = append(next, f[k])
+				}
+			}
+			return next, nil
+		}, nil
+	}
+}
+
+func identityRow(row []interface{}) ([]interface{}, error) { return row, nil }
+
+func columnIndex(columns []string, name string) int {
+	for i, c := range columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Transform runs the result through a pipeline of transformers and
+// returns the transformed copy; the receiver is not modified. Column
+// types are re-inferred from the new rows.
+func (qr *QueryResult) Transform(transformers ...Transformer) (*QueryResult, error) {
+	columns, rows := qr.Columns, qr.Rows
+	for _, t := range transformers {
+		next, fn, err := t(columns, rows)
+		if err != nil {
+			return nil, err
+		}
+		out := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			if out[i], err = fn(row); err != nil {
+				return nil, fmt.Errorf("%w (row %d)", err, i)
+			}
+		}
+		columns, rows = next, out
+	}
+	return &QueryResult{
+		Columns:       columns,
+		Rows:          rows,
+		Stats:         qr.Stats,
+		ColumnTypes:   InferColumnTypes(columns, rows),
+		Notifications: qr.Notifications,
+	}, nil
+}
+
+// transformPipeline applies transformers row by row, for iterators.
+type transformPipeline struct {
+	columns []string
+	steps   []RowTransform
+}
+
+// newTransformPipeline sets the pipeline up from the first page.
+func newTransformPipeline(columns []string, sample [][]interface{}, transformers []Transformer) (*transformPipeline, error) {
+	p := &transformPipeline{}
+	for _, t := range transformers {
+		next, fn, err := t(columns, sample)
+		if err != nil {
+			return nil, err
+		}
+		out := make([][]interface{}, len(sample))
+		for i, row := range sample {
+			if out[i], err = fn(row); err != nil {
+				return nil, err
+			}
+		}
+		columns, sample = next, out
+		p.steps = append(p.steps, fn)
+	}
+	p.columns = columns
+	return p, nil
+}
+
+func (p *transformPipeline) row(row []interface{}) ([]interface{}, error) {
+	var err error
+	for _, step := range p.steps {
+		if row, err = step(row); err != nil {
+			return nil, err
+		}
+	}
+	return row, nil
+}
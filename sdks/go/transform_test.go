@@ -0,0 +1,62 @@
+package nexus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryResultTransform(t *testing.T) {
+	qr := &QueryResult{
+		Columns: []string{"n", "age", "score", "debug"},
+		Rows: [][]interface{}{
+			{map[string]interface{}{"id": int64(1), "labels": []interface{}{"Person"}, "properties": map[string]interface{}{"name": "Ann"}}, "42", int64(3), "x"},
+			{map[string]interface{}{"id": int64(2), "labels": []interface{}{"Person"}, "properties": map[string]interface{}{"name": "Bob", "city": "Oslo"}}, nil, 2.5, "y"},
+		},
+	}
+
+	out, err := qr.Transform(
+		DropColumns("debug"),
+		CoerceColumn("age", TypeInteger),
+		CoerceColumn("score", TypeString),
+		FlattenEntities(),
+		RenameColumns(map[string]string{"n.name": "name"}),
+		MapColumn("name", func(v interface{}) (interface{}, error) { return strings.ToUpper(v.(string)), nil }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"n._id", "n._labels", "n.city", "name", "age", "score"}, out.Columns)
+	assert.Equal(t, []interface{}{int64(1), []interface{}{"Person"}, nil, "ANN", int64(42), "3"}, out.Rows[0])
+	assert.Equal(t, []interface{}{int64(2), []interface{}{"Person"}, "Oslo", "BOB", nil, "2.5"}, out.Rows[1])
+	assert.Equal(t, ColumnInteger, out.ColumnTypes[4])
+	assert.Len(t, qr.Columns, 4, "the receiver is left alone")
+
+	out, err = qr.Transform(SelectColumns("score", "age"), CoerceColumn("score", TypeFloat))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"score", "age"}, out.Columns)
+	assert.Equal(t, []interface{}{float64(3), "42"}, out.Rows[0])
+
+	_, err = qr.Transform(CoerceColumn("debug", TypeBoolean))
+	assert.ErrorContains(t, err, `column "debug"`)
+	_, err = qr.Transform(SelectColumns("missing"))
+	assert.ErrorContains(t, err, `no column "missing"`)
+}
+
+func TestQueryIteratorTransform(t *testing.T) {
+	server := pagedServer(t, 5)
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	it, err := client.IterateQuery(context.Background(), "MATCH (n) RETURN id(n) AS id, n.k AS props ORDER BY id", nil, QueryIteratorOptions{PageSize: 2})
+	require.NoError(t, err)
+	it.Transform(RenameColumns(map[string]string{"id": "key"}), DropColumns("props"), CoerceColumn("key", TypeString))
+	var keys []interface{}
+	for it.Next() {
+		keys = append(keys, it.Row()[0])
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []interface{}{"0", "1", "2", "3", "4"}, keys)
+	assert.Equal(t, []string{"key"}, it.Columns())
+}
@@ -0,0 +1,132 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// transientCodes are server error codes for conflicts that succeed when
+// the transaction is simply run again.
+var transientCodes = map[string]bool{
+	"DEADLOCK_DETECTED":     true,
+	"LOCK_TIMEOUT":          true,
+	"WRITE_CONFLICT":        true,
+	"TRANSACTION_CONFLICT":  true,
+	"SERIALIZATION_FAILURE": true,
+}
+
+// transientMarkers match the messages of the same conflicts for servers
+// and transports that report no code.
+var transientMarkers = []string{
+	"deadlock detected",
+	"lock timeout",
+	"write conflict",
+	"serialization failure",
+	"could not serialize",
+}
+
+// IsTransientConflict reports whether err is a deadlock, lock timeout
+// or write conflict: the transaction was aborted before committing and
+// can be retried as a whole. Other 4xx/5xx errors, including 409s for
+// duplicates, are not transient conflicts.
+func IsTransientConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		var body struct {
+			Code    string `json:"code"`
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal([]byte(apiErr.Message), &body) == nil {
+			code := strings.ToUpper(body.Code)
+			if transientCodes[code] || strings.HasPrefix(body.Code, "Neo.TransientError.") {
+				return true
+			}
+			message = body.Error + " " + body.Message
+		} else {
+			message = apiErr.Message
+		}
+	}
+	message = strings.ToLower(message)
+	for _, marker := range transientMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryError is returned when retries are exhausted. It wraps the last
+// attempt's error, so errors.As/Is still see the underlying cause.
+type RetryError struct {
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("nexus: gave up after %d attempt(s) in %s: %v", e.Attempts, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
+
+type attemptKey struct{}
+
+// AttemptFromContext returns the 1-based attempt number inside an
+// ExecuteWrite callback, or 0 outside one.
+func AttemptFromContext(ctx context.Context) int {
+	n, _ := ctx.Value(attemptKey{}).(int)
+	return n
+}
+
+// ExecuteWrite runs fn in a transaction and commits it, re-running the
+// whole transaction with exponential backoff when it fails with a
+// transient conflict (see IsTransientConflict). Any other error rolls
+// back and is returned immediately, because replaying a write after an
+// ambiguous failure could apply it twice. fn may run several times and
+// must not have side effects outside tx; AttemptFromContext(ctx) tells
+// it which attempt it is.
+//
+// When conflicts persist past MaxRetries the last one is returned as a
+// *RetryError.
+func (rc *RetryableClient) ExecuteWrite(ctx context.Context, fn func(ctx context.Context, tx *Transaction) error) error {
+	start := time.Now()
+	var lastErr error
+	for attempt := 0; attempt <= rc.retryConfig.MaxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		lastErr = rc.inTransaction(context.WithValue(ctx, attemptKey{}, attempt+1), fn)
+		if lastErr == nil {
+			return nil
+		}
+		if !IsTransientConflict(lastErr) {
+			return lastErr
+		}
+		if attempt < rc.retryConfig.MaxRetries {
+			backoff := rc.retryConfig.calculateBackoff(attempt)
+			rc.retryConfig.notify(attempt, lastErr, backoff)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+	}
+	return &RetryError{Attempts: rc.retryConfig.MaxRetries + 1, Elapsed: time.Since(start), Err: lastErr}
+}
+
+// notify reports a failed attempt to OnRetry.
+func (c *RetryConfig) notify(attempt int, err error, backoff time.Duration) {
+	if c.OnRetry != nil {
+		c.OnRetry(RetryAttempt{Attempt: attempt + 1, Err: err, Conflict: IsTransientConflict(err), Backoff: backoff})
+	}
+}
@@ -0,0 +1,150 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransientConflict(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&Error{StatusCode: 409, Message: `{"code":"DEADLOCK_DETECTED","error":"cycle"}`}, true},
+		{&Error{StatusCode: 400, Message: `{"code":"Neo.TransientError.Transaction.LockClientStopped"}`}, true},
+		{&Error{StatusCode: 500, Message: `{"error":"Lock timeout: row 7"}`}, true},
+		{&Error{StatusCode: 500, Message: "Deadlock detected: tx 3 waits for tx 4"}, true},
+		{errors.New("rpc: Write conflict on node 12"), true},
+		{&Error{StatusCode: 409, Message: `{"error":"user already exists"}`}, false},
+		{&Error{StatusCode: 500, Message: `{"error":"internal error"}`}, false},
+		{context.DeadlineExceeded, false},
+		{nil, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, IsTransientConflict(tc.err), "%v", tc.err)
+	}
+}
+
+func fastRetry() *RetryConfig {
+	cfg := DefaultRetryConfig()
+	cfg.InitialBackoff, cfg.Jitter = time.Millisecond, false
+	return cfg
+}
+
+func TestRetryableClientRetriesConflicts(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"code":"WRITE_CONFLICT","error":"node 1 modified concurrently"}`))
+			return
+		}
+		w.Write([]byte(`{"columns":["x"],"rows":[[1]]}`))
+	}))
+	defer server.Close()
+
+	var attempts []RetryAttempt
+	cfg := fastRetry()
+	cfg.OnRetry = func(a RetryAttempt) { attempts = append(attempts, a) }
+	client := NewClient(Config{BaseURL: server.URL}).WithRetry(cfg)
+
+	result, err := client.ExecuteCypher(context.Background(), "RETURN 1 AS x", nil)
+	require.NoError(t, err)
+	assert.Len(t, result.Rows, 1)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, 1, attempts[0].Attempt)
+	assert.True(t, attempts[0].Conflict)
+}
+
+func TestRetryableClientRetryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := fastRetry()
+	cfg.MaxRetries = 2
+	client := NewClient(Config{BaseURL: server.URL}).WithRetry(cfg)
+	err := client.Ping(context.Background())
+
+	var retryErr *RetryError
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 3, retryErr.Attempts)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+}
+
+func TestExecuteWrite(t *testing.T) {
+	var commits, rollbacks, executes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/transaction/begin":
+			json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx"})
+		case "/transaction/execute":
+			executes++
+			if executes < 3 {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":"Deadlock detected: waiting on tx 9"}`))
+				return
+			}
+			w.Write([]byte(`{"columns":[],"rows":[]}`))
+		case "/transaction/commit":
+			commits++
+			w.Write([]byte(`{}`))
+		case "/transaction/rollback":
+			rollbacks++
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL}).WithRetry(fastRetry())
+	var seen []int
+	err := client.ExecuteWrite(context.Background(), func(ctx context.Context, tx *Transaction) error {
+		seen = append(seen, AttemptFromContext(ctx))
+		_, err := tx.ExecuteCypher(ctx, "MATCH (a:Account {id: 1}) SET a.balance = a.balance - 10", nil)
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, seen)
+	assert.Equal(t, 2, rollbacks)
+	assert.Equal(t, 1, commits)
+}
+
+func TestExecuteWriteDoesNotRetryOtherErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/transaction/begin":
+			json.NewEncoder(w).Encode(map[string]string{"transaction_id": "tx"})
+		case "/transaction/execute":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"storage failure"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL}).WithRetry(fastRetry())
+	attempts := 0
+	err := client.ExecuteWrite(context.Background(), func(ctx context.Context, tx *Transaction) error {
+		attempts++
+		_, err := tx.ExecuteCypher(ctx, "CREATE (n)", nil)
+		return err
+	})
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 1, attempts)
+}
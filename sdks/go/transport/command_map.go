@@ -1,5 +1,7 @@
 package transport
 
+import "encoding/json"
+
 // CommandMapping is the result of mapping a dotted SDK name onto a
 // wire-level verb + argument vector.
 type CommandMapping struct {
@@ -133,6 +135,15 @@ func JsonToNexus(v any) NexusValue {
 		}
 		return NxMap(pairs)
 	}
+	// Typed slices, maps and structs ([]string, map[string]float64, …)
+	// take a JSON round-trip so they land on the cases above instead
+	// of silently collapsing to Null.
+	if data, err := json.Marshal(v); err == nil {
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err == nil {
+			return JsonToNexus(decoded)
+		}
+	}
 	return NxNull()
 }
 
@@ -2,6 +2,7 @@ package transport
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -23,6 +24,12 @@ type BuildOptions struct {
 	// EnvTransport — injected test shim for NEXUS_SDK_TRANSPORT. Leave
 	// empty to read from os.Environ.
 	EnvTransport string
+	// RoundTripper replaces the HTTP transport's http.RoundTripper
+	// (request signing, proxies). Ignored by RPC.
+	RoundTripper http.RoundTripper
+	// CredentialsSource, when set, resolves credentials per HTTP request
+	// or RPC connect instead of using the static Credentials.
+	CredentialsSource CredentialsSource
 }
 
 // Built is the resolved-transport tuple.
@@ -75,14 +82,25 @@ func Build(opts BuildOptions, creds Credentials) (Built, error) {
 
 	switch mode {
 	case ModeNexusRpc:
+		t := NewRpcTransport(endpoint, creds)
+		if opts.CredentialsSource != nil {
+			t.SetCredentialsSource(opts.CredentialsSource)
+		}
 		return Built{
-			Transport: NewRpcTransport(endpoint, creds),
+			Transport: t,
 			Endpoint:  endpoint,
 			Mode:      mode,
 		}, nil
 	case ModeHttp, ModeHttps:
+		t := NewHttpTransport(endpoint, creds, opts.Timeout)
+		if opts.RoundTripper != nil {
+			t.client.Transport = opts.RoundTripper
+		}
+		if opts.CredentialsSource != nil {
+			t.SetCredentialsSource(opts.CredentialsSource)
+		}
 		return Built{
-			Transport: NewHttpTransport(endpoint, creds, opts.Timeout),
+			Transport: t,
 			Endpoint:  endpoint,
 			Mode:      mode,
 		}, nil
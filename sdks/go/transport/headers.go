@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+)
+
+type headersKey struct{}
+
+// WithHeader returns a context that adds an HTTP header to every request
+// made with it. It is how request-scoped options (cache directives,
+// tenancy, priority, …) reach the wire; the context's existing headers
+// are copied, never mutated.
+//
+// Only the HTTP transport can carry headers. Callers holding an RPC
+// transport check HasHeaders and fall back to HTTP for such requests.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	h := http.Header{}
+	if prev, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		h = prev.Clone()
+	}
+	h.Set(key, value)
+	return context.WithValue(ctx, headersKey{}, h)
+}
+
+// HeadersFromContext returns the headers attached with WithHeader, or
+// nil. The result must not be modified.
+func HeadersFromContext(ctx context.Context) http.Header {
+	h, _ := ctx.Value(headersKey{}).(http.Header)
+	return h
+}
+
+// HasHeaders reports whether ctx carries request-scoped headers.
+func HasHeaders(ctx context.Context) bool {
+	return len(HeadersFromContext(ctx)) > 0
+}
+
+// ApplyHeaders copies the request-scoped headers of req's context onto
+// req.
+func ApplyHeaders(req *http.Request) {
+	for k, values := range HeadersFromContext(req.Context()) {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
@@ -17,10 +18,11 @@ import (
 // wire-level verbs onto the REST endpoints the legacy Go client
 // relied on. Unknown verbs surface [ErrUnmappedCommand].
 type HttpTransport struct {
-	endpoint Endpoint
-	creds    Credentials
-	baseURL  string
-	client   *http.Client
+	endpoint    Endpoint
+	creds       Credentials
+	credsSource CredentialsSource
+	baseURL     string
+	client      *http.Client
 }
 
 // NewHttpTransport builds a fresh HTTP transport.
@@ -63,13 +65,25 @@ func (t *HttpTransport) Close() error {
 	return nil
 }
 
-func (t *HttpTransport) applyAuth(req *http.Request) {
-	if t.creds.APIKey != "" {
-		req.Header.Set("X-API-Key", t.creds.APIKey)
-	} else if t.creds.Username != "" && t.creds.Password != "" {
-		token := base64.StdEncoding.EncodeToString([]byte(t.creds.Username + ":" + t.creds.Password))
+// SetCredentialsSource resolves credentials per request from source
+// instead of the static ones passed to [NewHttpTransport].
+func (t *HttpTransport) SetCredentialsSource(source CredentialsSource) { t.credsSource = source }
+
+func (t *HttpTransport) applyAuth(req *http.Request) error {
+	creds := t.creds
+	if t.credsSource != nil {
+		var err error
+		if creds, err = t.credsSource(req.Context()); err != nil {
+			return fmt.Errorf("failed to resolve credentials: %w", err)
+		}
+	}
+	if creds.APIKey != "" {
+		req.Header.Set("X-API-Key", creds.APIKey)
+	} else if creds.Username != "" && creds.Password != "" {
+		token := base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password))
 		req.Header.Set("Authorization", "Basic "+token)
 	}
+	return nil
 }
 
 func (t *HttpTransport) dispatch(ctx context.Context, cmd string, args []NexusValue) (NexusValue, error) {
@@ -140,44 +154,149 @@ func (t *HttpTransport) dispatch(ctx context.Context, cmd string, args []NexusVa
 	return NexusValue{}, &ErrUnmappedCommand{Command: cmd}
 }
 
+// bufferPool recycles the request and response buffers of doJSON, the
+// per-query hot path.
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
 func (t *HttpTransport) doJSON(ctx context.Context, method, path string, body any) (NexusValue, error) {
+	decoded, err := t.doJSONValue(ctx, method, path, body)
+	if err != nil {
+		return NexusValue{}, err
+	}
+	if raw, ok := decoded.(rawText); ok {
+		return NxStr(string(raw)), nil
+	}
+	return JsonToNexus(decoded), nil
+}
+
+// rawText marks a response body that was not JSON.
+type rawText string
+
+// doJSONValue sends body as JSON and decodes the response into plain
+// Go values (nil for an empty body, rawText when it is not JSON).
+func (t *HttpTransport) doJSONValue(ctx context.Context, method, path string, body any) (any, error) {
+	var decoded any
+	err := t.doJSONWith(ctx, method, path, body, func(data []byte) error {
+		if len(data) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			// Fall back to string if JSON decode fails.
+			decoded = rawText(data)
+		}
+		return nil
+	})
+	return decoded, err
+}
+
+// doJSONWith sends body as JSON and hands the response body to decode.
+// The bytes are pooled, so decode must not keep them.
+func (t *HttpTransport) doJSONWith(ctx context.Context, method, path string, body any, decode func([]byte) error) error {
 	var reqBody io.Reader
 	if body != nil {
-		data, err := json.Marshal(body)
-		if err != nil {
-			return NexusValue{}, err
+		out := bufferPool.Get().(*bytes.Buffer)
+		out.Reset()
+		defer bufferPool.Put(out)
+		if err := json.NewEncoder(out).Encode(body); err != nil {
+			return err
 		}
-		reqBody = bytes.NewReader(data)
+		reqBody = bytes.NewReader(out.Bytes())
 	}
 	req, err := http.NewRequestWithContext(ctx, method, t.baseURL+path, reqBody)
 	if err != nil {
-		return NexusValue{}, err
+		return err
 	}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	t.applyAuth(req)
+	if err := t.applyAuth(req); err != nil {
+		return err
+	}
+	ApplyHeaders(req)
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return NexusValue{}, err
+		return err
 	}
 	defer resp.Body.Close()
-	raw, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return NexusValue{}, err
+	in := bufferPool.Get().(*bytes.Buffer)
+	in.Reset()
+	defer bufferPool.Put(in)
+	if _, err := in.ReadFrom(resp.Body); err != nil {
+		return err
 	}
 	if resp.StatusCode >= 400 {
-		return NexusValue{}, &HttpError{StatusCode: resp.StatusCode, Body: string(raw)}
+		return &HttpError{StatusCode: resp.StatusCode, Body: in.String()}
 	}
-	if len(raw) == 0 {
-		return NxNull(), nil
+	return decode(in.Bytes())
+}
+
+// CypherJSON runs a query over POST /cypher and returns the decoded
+// response, shaped as NexusToJson(JsonToNexus(response)) would be
+// (whole numbers as int64) but without building the intermediate
+// NexusValue tree. params are sent as given.
+func (t *HttpTransport) CypherJSON(ctx context.Context, query string, params map[string]any) (any, error) {
+	decoded, err := t.doJSONValue(ctx, http.MethodPost, "/cypher", cypherBody{Query: query, Parameters: params})
+	if err != nil {
+		return nil, err
 	}
-	var decoded any
-	if err := json.Unmarshal(raw, &decoded); err != nil {
-		// Fall back to string if JSON decode fails.
-		return NxStr(string(raw)), nil
+	if raw, ok := decoded.(rawText); ok {
+		return string(raw), nil
 	}
-	return JsonToNexus(decoded), nil
+	return normalizeNumbers(decoded), nil
+}
+
+// CypherResponse is the body of a POST /cypher response. Decoding into
+// a CypherResponse used before reuses its row slices, so callers can
+// pool them.
+type CypherResponse struct {
+	Columns         []any          `json:"columns"`
+	Rows            [][]any        `json:"rows"`
+	Stats           map[string]any `json:"stats"`
+	ExecutionTimeMs *float64       `json:"execution_time_ms"`
+	Notifications   []any          `json:"notifications"`
+	ColumnTypes     []any          `json:"column_types"`
+}
+
+// CypherInto runs a query over POST /cypher and decodes the response
+// into dst, with whole numbers as int64 as in CypherJSON. dst's rows
+// are overwritten in place.
+func (t *HttpTransport) CypherInto(ctx context.Context, query string, params map[string]any, dst *CypherResponse) error {
+	return t.doJSONWith(ctx, http.MethodPost, "/cypher", cypherBody{Query: query, Parameters: params}, func(data []byte) error {
+		*dst = CypherResponse{Rows: dst.Rows[:0]}
+		if err := json.Unmarshal(data, dst); err != nil {
+			return fmt.Errorf("HTTP fallback: 'CYPHER' response is not an object: %w", err)
+		}
+		for _, row := range dst.Rows {
+			normalizeNumbers(row)
+		}
+		normalizeNumbers(dst.Stats)
+		return nil
+	})
+}
+
+type cypherBody struct {
+	Query      string         `json:"query"`
+	Parameters map[string]any `json:"parameters"`
+}
+
+// normalizeNumbers turns whole float64 values into int64 in place,
+// matching JsonToNexus.
+func normalizeNumbers(v any) any {
+	switch x := v.(type) {
+	case float64:
+		if x == float64(int64(x)) {
+			return int64(x)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = normalizeNumbers(e)
+		}
+	case map[string]any:
+		for k, e := range x {
+			x[k] = normalizeNumbers(e)
+		}
+	}
+	return v
 }
 
 func (t *HttpTransport) doText(ctx context.Context, method, path string, body io.Reader, contentType string) (string, error) {
@@ -188,7 +307,10 @@ func (t *HttpTransport) doText(ctx context.Context, method, path string, body io
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
-	t.applyAuth(req)
+	if err := t.applyAuth(req); err != nil {
+		return "", err
+	}
+	ApplyHeaders(req)
 	resp, err := t.client.Do(req)
 	if err != nil {
 		return "", err
@@ -210,7 +332,10 @@ func (t *HttpTransport) doRaw(ctx context.Context, method, path, body, contentTy
 		return NexusValue{}, err
 	}
 	req.Header.Set("Content-Type", contentType)
-	t.applyAuth(req)
+	if err := t.applyAuth(req); err != nil {
+		return NexusValue{}, err
+	}
+	ApplyHeaders(req)
 	resp, err := t.client.Do(req)
 	if err != nil {
 		return NexusValue{}, err
@@ -23,6 +23,7 @@ const pushID uint32 = 0xFFFFFFFF
 type RpcTransport struct {
 	endpoint       Endpoint
 	creds          Credentials
+	credsSource    CredentialsSource
 	connectTimeout time.Duration
 
 	connMu  sync.Mutex
@@ -51,6 +52,11 @@ func NewRpcTransport(endpoint Endpoint, creds Credentials) *RpcTransport {
 	return t
 }
 
+// SetCredentialsSource resolves credentials from source at each
+// (re)connect instead of using the static ones passed to
+// [NewRpcTransport]. An open connection keeps its authentication.
+func (t *RpcTransport) SetCredentialsSource(source CredentialsSource) { t.credsSource = source }
+
 // SetConnectTimeout tunes the TCP-level connect timeout.
 func (t *RpcTransport) SetConnectTimeout(d time.Duration) { t.connectTimeout = d }
 
@@ -161,14 +167,20 @@ func (t *RpcTransport) handshakeLocked(ctx context.Context) error {
 	if !hello.OK {
 		return fmt.Errorf("HELLO rejected by server: %s", hello.Err)
 	}
-	if !t.creds.HasAny() {
+	creds := t.creds
+	if t.credsSource != nil {
+		if creds, err = t.credsSource(ctx); err != nil {
+			return fmt.Errorf("failed to resolve credentials: %w", err)
+		}
+	}
+	if !creds.HasAny() {
 		return nil
 	}
 	var authArgs []NexusValue
-	if t.creds.APIKey != "" {
-		authArgs = []NexusValue{NxStr(t.creds.APIKey)}
+	if creds.APIKey != "" {
+		authArgs = []NexusValue{NxStr(creds.APIKey)}
 	} else {
-		authArgs = []NexusValue{NxStr(t.creds.Username), NxStr(t.creds.Password)}
+		authArgs = []NexusValue{NxStr(creds.Username), NxStr(creds.Password)}
 	}
 	auth, err := t.sendUnlocked(ctx, RpcRequest{ID: 0, Command: "AUTH", Args: authArgs})
 	if err != nil {
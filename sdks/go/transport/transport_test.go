@@ -3,7 +3,12 @@ package transport
 import (
 	"context"
 	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -292,6 +297,107 @@ func TestCommandMap_Unknown(t *testing.T) {
 	}
 }
 
+func TestJsonToNexus_TypedSlicesAndMaps(t *testing.T) {
+	v := JsonToNexus([]string{"a", "b"})
+	if v.Kind != KindArray || len(v.Value.([]NexusValue)) != 2 {
+		t.Fatalf("expected 2-element Array, got %+v", v)
+	}
+	m := JsonToNexus(map[string]float64{"w": 0.5})
+	if m.Kind != KindMap {
+		t.Fatalf("expected Map, got %v", m.Kind)
+	}
+	if n := JsonToNexus(uint16(7)); n.Kind != KindInt || n.Value.(int64) != 7 {
+		t.Fatalf("expected Int 7, got %+v", n)
+	}
+}
+
+// ── HTTP CypherJSON ───────────────────────────────────────────────────
+
+func TestHttpCypherJSON_MatchesNexusRoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query      string         `json:"query"`
+			Parameters map[string]any `json:"parameters"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"columns": []string{"q", "n"},
+			"rows":    [][]any{{req.Query, req.Parameters["n"]}, {1.5, map[string]any{"k": 3}}},
+		})
+	}))
+	defer srv.Close()
+	ep, err := ParseEndpoint(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := NewHttpTransport(ep, Credentials{}, time.Second)
+	ctx := context.Background()
+
+	direct, err := tr.CypherJSON(ctx, "RETURN $n", map[string]any{"n": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tr.Execute(ctx, Request{Command: "CYPHER", Args: []NexusValue{NxStr("RETURN $n"), JsonToNexus(map[string]any{"n": 7})}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if via := NexusToJson(resp.Value); !reflect.DeepEqual(direct, via) {
+		t.Fatalf("CypherJSON = %#v, Execute = %#v", direct, via)
+	}
+	rows := direct.(map[string]any)["rows"].([]any)
+	if n, ok := rows[0].([]any)[1].(int64); !ok || n != 7 {
+		t.Fatalf("expected int64 7, got %#v", rows[0].([]any)[1])
+	}
+}
+
+func TestHttpCypherJSON_ConcurrentCallsKeepTheirBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(map[string]any{"query": req.Query})
+	}))
+	defer srv.Close()
+	ep, _ := ParseEndpoint(srv.URL)
+	tr := NewHttpTransport(ep, Credentials{}, time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			query := "RETURN " + strings.Repeat("x", i*100)
+			got, err := tr.CypherJSON(context.Background(), query, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got.(map[string]any)["query"] != query {
+				t.Errorf("call %d got another call's response", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestHttpCypherJSON_ErrorKeepsBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "syntax error", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	ep, _ := ParseEndpoint(srv.URL)
+	tr := NewHttpTransport(ep, Credentials{}, time.Second)
+
+	_, err := tr.CypherJSON(context.Background(), "RETURN", nil)
+	httpErr, ok := err.(*HttpError)
+	if !ok || httpErr.StatusCode != http.StatusBadRequest || !strings.Contains(httpErr.Body, "syntax error") {
+		t.Fatalf("expected HttpError 400, got %v", err)
+	}
+}
+
 // ── TransportMode parse ───────────────────────────────────────────────
 
 func TestParseMode_CanonicalTokens(t *testing.T) {
@@ -125,6 +125,10 @@ func (c Credentials) HasAny() bool {
 	return c.APIKey != "" || (c.Username != "" && c.Password != "")
 }
 
+// CredentialsSource resolves credentials on demand, for secrets that
+// live outside the process and may rotate.
+type CredentialsSource func(ctx context.Context) (Credentials, error)
+
 // Request is a single request against the active transport.
 type Request struct {
 	// Command is the wire-level verb ("CYPHER", "PING", "STATS", …).
@@ -147,6 +151,20 @@ type Transport interface {
 	Close() error
 }
 
+// CypherJSONer is implemented by transports that can return a Cypher
+// response as plain Go values directly, which saves converting it to
+// a NexusValue and back on the query hot path.
+type CypherJSONer interface {
+	CypherJSON(ctx context.Context, query string, params map[string]any) (any, error)
+}
+
+// CypherDecoder is implemented by transports that can decode a Cypher
+// response into a reusable CypherResponse, so its row slices can be
+// pooled.
+type CypherDecoder interface {
+	CypherInto(ctx context.Context, query string, params map[string]any, dst *CypherResponse) error
+}
+
 // ErrUnmappedCommand is returned by the HTTP transport when a caller
 // routes a wire verb the HTTP route table does not understand.
 type ErrUnmappedCommand struct{ Command string }
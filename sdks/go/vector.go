@@ -0,0 +1,134 @@
+package nexus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// VectorSimilarity names the distance function of a vector index.
+type VectorSimilarity string
+
+const (
+	SimilarityCosine    VectorSimilarity = "cosine"
+	SimilarityEuclidean VectorSimilarity = "euclidean"
+	SimilarityDot       VectorSimilarity = "dot"
+)
+
+// VectorIndexOptions describes a vector (HNSW) index over a node
+// property holding embeddings.
+type VectorIndexOptions struct {
+	Name       string
+	Label      string
+	Property   string
+	Dimensions int
+	// Similarity defaults to SimilarityCosine when empty.
+	Similarity VectorSimilarity
+	// Quantization asks the server to store the indexed vectors in a
+	// compact encoding; empty keeps float32.
+	Quantization VectorQuantization
+}
+
+// CreateVectorIndex creates a vector index through POST /schema/indexes.
+func (c *Client) CreateVectorIndex(ctx context.Context, opts VectorIndexOptions) error {
+	if opts.Name == "" || opts.Label == "" || opts.Property == "" {
+		return errors.New("nexus: vector index requires Name, Label and Property")
+	}
+	if opts.Dimensions <= 0 {
+		return fmt.Errorf("nexus: vector index dimensions must be positive, got %d", opts.Dimensions)
+	}
+	if opts.Similarity == "" {
+		opts.Similarity = SimilarityCosine
+	}
+	options := map[string]interface{}{
+		"dimensions": opts.Dimensions,
+		"similarity": string(opts.Similarity),
+	}
+	switch opts.Quantization {
+	case QuantizationNone:
+	case QuantizationFloat16, QuantizationInt8:
+		options["quantization"] = string(opts.Quantization)
+	default:
+		return fmt.Errorf("nexus: unknown vector quantization %q", opts.Quantization)
+	}
+	reqBody := map[string]interface{}{
+		"name":       opts.Name,
+		"label":      opts.Label,
+		"properties": []string{opts.Property},
+		"type":       "vector",
+		"options":    options,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/schema/indexes", reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// KnnRequest is the body of POST /knn_traverse.
+type KnnRequest struct {
+	// Label is the node label whose vector index is searched.
+	Label string `json:"label"`
+	// Vector is the query embedding; its length must match the index.
+	Vector []float32 `json:"vector"`
+	// K is the number of nearest neighbours to seed the traversal with.
+	K int `json:"k"`
+	// Where is an optional Cypher predicate over `n`.
+	Where string `json:"where,omitempty"`
+	// Limit caps the returned matches (server default 100).
+	Limit int `json:"limit,omitempty"`
+}
+
+// KnnMatch is one nearest-neighbour hit.
+type KnnMatch struct {
+	ID         string
+	Properties map[string]interface{}
+	Score      float64
+}
+
+// KnnSearch runs a k-nearest-neighbour search against a label's vector
+// index and returns matches ordered by similarity.
+func (c *Client) KnnSearch(ctx context.Context, req KnnRequest) ([]KnnMatch, error) {
+	if req.Label == "" || len(req.Vector) == 0 {
+		return nil, errors.New("nexus: KNN search requires Label and Vector")
+	}
+	if req.K <= 0 {
+		return nil, fmt.Errorf("nexus: KNN search k must be positive, got %d", req.K)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/knn_traverse", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Nodes []struct {
+			ID         uint64                 `json:"id"`
+			Properties map[string]interface{} `json:"properties"`
+			Score      float64                `json:"score"`
+		} `json:"nodes"`
+		Error *string `json:"error,omitempty"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, &Error{StatusCode: resp.StatusCode, Message: *result.Error}
+	}
+
+	matches := make([]KnnMatch, len(result.Nodes))
+	for i, n := range result.Nodes {
+		matches[i] = KnnMatch{
+			ID:         strconv.FormatUint(n.ID, 10),
+			Properties: n.Properties,
+			Score:      n.Score,
+		}
+	}
+	return matches, nil
+}
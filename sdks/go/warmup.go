@@ -0,0 +1,135 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hivellm/nexus-go/transport"
+)
+
+// WarmupOptions configures Warmup.
+type WarmupOptions struct {
+	// Connections is the number of HTTP connections opened concurrently
+	// and left idle for reuse (default 2, the idle limit per host of
+	// Go's default transport).
+	Connections int
+	// Queries are planned with EXPLAIN, without running them, so the
+	// server's plan cache holds them before the first real request.
+	Queries []string
+	// Procedures have their signatures fetched and cached for Call.
+	Procedures []string
+}
+
+// WarmupReport describes what Warmup did.
+type WarmupReport struct {
+	// Addresses are the resolved addresses of the server host.
+	Addresses []string
+	// ServerVersion is the version reported by GET /health, if any.
+	ServerVersion string
+	// Connections is the number of HTTP connections established.
+	Connections   int
+	PrimedQueries int
+	Duration      time.Duration
+}
+
+// Warmup front-loads the cost of the first requests after a deploy: it
+// resolves the server host, runs the transport handshake (HELLO and
+// AUTH on the RPC transport), opens TLS connections, plans
+// opts.Queries and caches opts.Procedures. Call it before marking an
+// instance ready; any failure means requests would fail too.
+func (c *Client) Warmup(ctx context.Context, opts WarmupOptions) (*WarmupReport, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	start := time.Now()
+	report := &WarmupReport{}
+
+	if host := c.endpoint.Host; host != "" && net.ParseIP(host) == nil {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: warmup: resolve %s: %w", host, err)
+		}
+		report.Addresses = addrs
+	} else if host != "" {
+		report.Addresses = []string{host}
+	}
+
+	if c.transport.IsRpc() {
+		if _, err := c.transport.Execute(ctx, transport.Request{Command: "PING"}); err != nil {
+			return nil, fmt.Errorf("nexus: warmup: handshake: %w", translateTransportError(err))
+		}
+	}
+
+	n := opts.Connections
+	if n <= 0 {
+		n = 2
+	}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			version, err := c.warmConnection(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			report.Connections++
+			if version != "" {
+				report.ServerVersion = version
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, fmt.Errorf("nexus: warmup: connect: %w", firstErr)
+	}
+
+	for _, name := range opts.Procedures {
+		if _, err := c.signature(ctx, name); err != nil {
+			return nil, fmt.Errorf("nexus: warmup: procedure %s: %w", name, err)
+		}
+	}
+	for i, q := range opts.Queries {
+		if _, err := c.ExecuteCypher(ctx, "EXPLAIN "+q, nil); err != nil {
+			return nil, fmt.Errorf("nexus: warmup: query %d: %w", i, err)
+		}
+		report.PrimedQueries++
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// warmConnection sends one health check and drains the body, so the
+// connection goes back to the idle pool.
+func (c *Client) warmConnection(ctx context.Context) (string, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/health", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var health struct {
+		Version string `json:"version"`
+	}
+	_ = json.Unmarshal(data, &health)
+	return health.Version, nil
+}
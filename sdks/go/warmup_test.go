@@ -0,0 +1,73 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmup(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		health  int
+		queries []string
+		procs   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/health":
+			health++
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "Healthy", "version": "1.2.3"})
+		case "/cypher":
+			var req struct {
+				Query string `json:"query"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			queries = append(queries, req.Query)
+			json.NewEncoder(w).Encode(map[string]interface{}{"columns": []string{}, "rows": [][]interface{}{}})
+		default:
+			procs = append(procs, r.URL.Path)
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "my.proc"})
+		}
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	report, err := client.Warmup(context.Background(), WarmupOptions{
+		Connections: 3,
+		Queries:     []string{"MATCH (p:Person {email: $email}) RETURN p"},
+		Procedures:  []string{"my.proc"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, health)
+	assert.Equal(t, 3, report.Connections)
+	assert.Equal(t, "1.2.3", report.ServerVersion)
+	assert.Equal(t, []string{"127.0.0.1"}, report.Addresses)
+	assert.Equal(t, []string{"EXPLAIN MATCH (p:Person {email: $email}) RETURN p"}, queries)
+	assert.Equal(t, 1, report.PrimedQueries)
+	assert.Len(t, procs, 1)
+
+	// The signature is cached: a second warmup does not fetch it again.
+	_, err = client.Warmup(context.Background(), WarmupOptions{Procedures: []string{"my.proc"}})
+	require.NoError(t, err)
+	assert.Len(t, procs, 1)
+}
+
+func TestWarmupFailsWhenServerIsDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL})
+
+	_, err := client.Warmup(context.Background(), WarmupOptions{})
+	assert.ErrorContains(t, err, "warmup: connect")
+}
@@ -0,0 +1,184 @@
+package nexus
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookEvent is a server event a webhook can subscribe to.
+type WebhookEvent string
+
+const (
+	EventNodeCreated         WebhookEvent = "node.created"
+	EventNodeUpdated         WebhookEvent = "node.updated"
+	EventNodeDeleted         WebhookEvent = "node.deleted"
+	EventRelationshipCreated WebhookEvent = "relationship.created"
+	EventRelationshipDeleted WebhookEvent = "relationship.deleted"
+	EventSchemaChanged       WebhookEvent = "schema.changed"
+)
+
+// WebhookSignatureHeader carries the delivery signature, formatted as
+// `t=<unix seconds>,v1=<hex HMAC-SHA256>`.
+const WebhookSignatureHeader = "X-Nexus-Signature"
+
+// WebhookOptions registers a webhook.
+type WebhookOptions struct {
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+	// Labels / RelationshipTypes narrow data events to matching entities;
+	// empty means all.
+	Labels            []string `json:"labels,omitempty"`
+	RelationshipTypes []string `json:"relationship_types,omitempty"`
+	// Secret signs every delivery; see VerifyWebhookSignature. The server
+	// never returns it.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Webhook is a registered subscription.
+type Webhook struct {
+	ID                string         `json:"id"`
+	URL               string         `json:"url"`
+	Events            []WebhookEvent `json:"events"`
+	Labels            []string       `json:"labels,omitempty"`
+	RelationshipTypes []string       `json:"relationship_types,omitempty"`
+	Active            bool           `json:"active"`
+	CreatedAt         time.Time      `json:"created_at"`
+}
+
+// CreateWebhook registers a webhook fired on data or schema changes.
+func (c *Client) CreateWebhook(ctx context.Context, opts WebhookOptions) (*Webhook, error) {
+	if opts.URL == "" {
+		return nil, errors.New("nexus: webhook URL must not be empty")
+	}
+	if len(opts.Events) == 0 {
+		return nil, errors.New("nexus: webhook must subscribe to at least one event")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/webhooks", opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var webhook Webhook
+	if err := decodeResponse(resp, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks retrieves all registered webhooks.
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/webhooks", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Webhooks []Webhook `json:"webhooks"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	return result.Webhooks, nil
+}
+
+// DeleteWebhook removes a webhook by ID.
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/webhooks/%s", url.PathEscape(id))
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// Webhook signature verification errors.
+var (
+	ErrWebhookSignature = errors.New("nexus: invalid webhook signature")
+	ErrWebhookExpired   = errors.New("nexus: webhook signature timestamp outside tolerance")
+)
+
+// SignWebhookPayload computes the WebhookSignatureHeader value for body
+// sent at ts. The HMAC-SHA256 covers "<unix seconds>.<body>" so a
+// captured delivery cannot be replayed with a fresh timestamp.
+func SignWebhookPayload(secret string, body []byte, ts time.Time) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + t + ",v1=" + webhookMAC(secret, t, body)
+}
+
+func webhookMAC(secret, t string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a WebhookSignatureHeader value against
+// body. Deliveries signed more than tolerance away from now are rejected
+// with ErrWebhookExpired (tolerance 0 disables the check).
+func VerifyWebhookSignature(secret, header string, body []byte, tolerance time.Duration) error {
+	var t string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			sigs = append(sigs, value)
+		}
+	}
+	if t == "" || len(sigs) == 0 {
+		return ErrWebhookSignature
+	}
+
+	if tolerance > 0 {
+		unix, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return ErrWebhookSignature
+		}
+		age := time.Since(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrWebhookExpired
+		}
+	}
+
+	expected := []byte(webhookMAC(secret, t, body))
+	for _, sig := range sigs {
+		if hmac.Equal(expected, []byte(sig)) {
+			return nil
+		}
+	}
+	return ErrWebhookSignature
+}
+
+// VerifyWebhookRequest reads and verifies an incoming delivery in an
+// http.Handler, returning the body on success.
+func VerifyWebhookRequest(r *http.Request, secret string, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: read webhook body: %w", err)
+	}
+	if err := VerifyWebhookSignature(secret, r.Header.Get(WebhookSignatureHeader), body, tolerance); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
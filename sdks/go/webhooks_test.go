@@ -0,0 +1,35 @@
+package nexus
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSignatureRoundTrip(t *testing.T) {
+	body := []byte(`{"event":"node.created","id":"42"}`)
+	header := SignWebhookPayload("s3cret", body, time.Now())
+
+	assert.NoError(t, VerifyWebhookSignature("s3cret", header, body, 5*time.Minute))
+	assert.ErrorIs(t, VerifyWebhookSignature("wrong", header, body, 5*time.Minute), ErrWebhookSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("s3cret", header, []byte(`{}`), 5*time.Minute), ErrWebhookSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("s3cret", "garbage", body, 0), ErrWebhookSignature)
+
+	old := SignWebhookPayload("s3cret", body, time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, VerifyWebhookSignature("s3cret", old, body, 5*time.Minute), ErrWebhookExpired)
+	assert.NoError(t, VerifyWebhookSignature("s3cret", old, body, 0))
+}
+
+func TestVerifyWebhookRequest(t *testing.T) {
+	body := []byte(`{"event":"schema.changed"}`)
+	req := httptest.NewRequest("POST", "/hook", bytes.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("k", body, time.Now()))
+
+	got, err := VerifyWebhookRequest(req, "k", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, body, got)
+}